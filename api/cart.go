@@ -1,6 +1,8 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
@@ -11,6 +13,10 @@ type getUserCartPathVar struct {
 	UserID int64 `path:"user_id" validate:"required,min=1"`
 }
 
+type getUserCartQueryStr struct {
+	Currency string `querystr:"currency" validate:"omitempty,iso4217"`
+}
+
 // getUserCart maps to endpoint "GET /carts/{user_id}"
 func (s *StoreHub) getUserCart(w http.ResponseWriter, r *http.Request) {
 	// parse path variables
@@ -19,6 +25,12 @@ func (s *StoreHub) getUserCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// parse query string
+	var reqQueryStr getUserCartQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
 	// db query
 	cart, err := s.dbStore.GetUserCartTx(r.Context(), pathVar.UserID)
 	if err != nil {
@@ -27,22 +39,48 @@ func (s *StoreHub) getUserCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	result := envelop{
+		"cart":    cart.Cart,
+		"cart_id": cart.CartID,
+	}
+
+	// show the cart's totals in the buyer's currency
+	if reqQueryStr.Currency != "" {
+		quote, err := s.dbStore.QuoteCartTx(r.Context(), db.QuoteCartTxParams{UserID: pathVar.UserID})
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to price cart")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		display, err := s.displayBreakdown(r.Context(), quote.Breakdown, reqQueryStr.Currency)
+		if err != nil {
+			switch {
+			case errors.Is(err, errNoFxRate):
+				s.errorResponse(w, r, http.StatusUnprocessableEntity, "currency is not supported")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to convert cart totals")
+			}
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+		result["display_breakdown"] = display
+	}
+
 	// return response
 	s.writeJSON(w, http.StatusOK, envelop{
 		"status": "success",
 		"data": envelop{
 			"message": "retrieved user cart",
-			"result": envelop{
-				"cart":    cart.Cart,
-				"cart_id": cart.CartID,
-			},
+			"result":  result,
 		},
 	}, nil)
 }
 
 type addItemToCartRequestBody struct {
-	ItemID  int64 `json:"item_id" validate:"required,min=1"`
-	StoreID int64 `json:"store_id" validate:"required,min=1"`
+	ItemID    int64 `json:"item_id" validate:"required,min=1"`
+	StoreID   int64 `json:"store_id" validate:"required,min=1"`
+	VariantID int64 `json:"variant_id" validate:"omitempty,min=1"` // required for an item with variants
 }
 
 type addItemToCartPathVar struct {
@@ -64,14 +102,25 @@ func (s *StoreHub) addItemToCart(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// db query
-	arg := db.UpsertCartItemParams{
+	arg := db.AddCartItemTxParams{
 		CartID:  pathVar.CartID,
 		ItemID:  reqBody.ItemID,
 		StoreID: reqBody.StoreID,
+		VariantID: sql.NullInt64{
+			Int64: reqBody.VariantID,
+			Valid: reqBody.VariantID != 0,
+		},
 	}
-	cartItem, err := s.dbStore.UpsertCartItem(r.Context(), arg)
+	cartItem, err := s.dbStore.AddCartItemTx(r.Context(), arg)
 	if err != nil {
-		s.errorResponse(w, r, http.StatusInternalServerError, "failed to add item to cart")
+		switch {
+		case errors.Is(err, db.ErrVariantNotFound):
+			s.errorResponse(w, r, http.StatusNotFound, "item variant not found")
+		case errors.Is(err, db.ErrVariantRequired):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to add item to cart")
+		}
 		log.Error().Err(err).Msg("error occurred")
 		return
 	}
@@ -93,6 +142,10 @@ type removeItemFromCartPathVar struct {
 	ItemID int64 `path:"item_id" validate:"required,min=1"`
 }
 
+type removeItemFromCartQueryStr struct {
+	VariantID int64 `querystr:"variant_id" validate:"omitempty,min=1"` // removes every variant of the item when left out
+}
+
 // removeItemFromCart maps to endpoint "DELETE /carts/{cart_id}/items/{item_id}"
 func (s *StoreHub) removeItemFromCart(w http.ResponseWriter, r *http.Request) {
 	// parse path variables
@@ -101,10 +154,20 @@ func (s *StoreHub) removeItemFromCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// parse query string
+	var reqQueryStr removeItemFromCartQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
 	// db query
 	err := s.dbStore.RemoveItemFromCart(r.Context(), db.RemoveItemFromCartParams{
 		CartID: pathVar.CartID,
 		ItemID: pathVar.ItemID,
+		VariantID: sql.NullInt64{
+			Int64: reqQueryStr.VariantID,
+			Valid: reqQueryStr.VariantID != 0,
+		},
 	})
 	if err != nil {
 		s.errorResponse(w, r, http.StatusInternalServerError, "failed to remove item from cart")
@@ -123,6 +186,7 @@ func (s *StoreHub) removeItemFromCart(w http.ResponseWriter, r *http.Request) {
 
 type increaseCartItemRequestBody struct {
 	IncreaseAmount int32 `json:"increase_amount" validate:"required,min=1"`
+	VariantID      int64 `json:"variant_id" validate:"omitempty,min=1"`
 }
 
 type increaseCartItemPathVar struct {
@@ -149,10 +213,19 @@ func (s *StoreHub) increaseCartItem(w http.ResponseWriter, r *http.Request) {
 		CartID:         pathVar.CartID,
 		ItemID:         pathVar.ItemID,
 		IncreaseAmount: reqBody.IncreaseAmount,
+		VariantID: sql.NullInt64{
+			Int64: reqBody.VariantID,
+			Valid: reqBody.VariantID != 0,
+		},
 	}
 	cartItem, err := s.dbStore.IncreaseCartItemQuantity(r.Context(), arg)
 	if err != nil {
-		s.errorResponse(w, r, http.StatusInternalServerError, "failed to increase cart item quantity")
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "cart item not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to increase cart item quantity")
+		}
 		log.Error().Err(err).Msg("error occurred")
 		return
 	}
@@ -171,6 +244,7 @@ func (s *StoreHub) increaseCartItem(w http.ResponseWriter, r *http.Request) {
 
 type decreaseCartItemRequestBody struct {
 	DecreaseAmount int32 `json:"decrease_amount" validate:"required,min=1"`
+	VariantID      int64 `json:"variant_id" validate:"omitempty,min=1"`
 }
 
 type decreaseCartItemPathVar struct {
@@ -197,10 +271,19 @@ func (s *StoreHub) decreaseCartItem(w http.ResponseWriter, r *http.Request) {
 		CartID:         pathVar.CartID,
 		ItemID:         pathVar.ItemID,
 		DecreaseAmount: reqBody.DecreaseAmount,
+		VariantID: sql.NullInt64{
+			Int64: reqBody.VariantID,
+			Valid: reqBody.VariantID != 0,
+		},
 	}
 	cartItem, err := s.dbStore.DecreaseCartItemQuantity(r.Context(), arg)
 	if err != nil {
-		s.errorResponse(w, r, http.StatusInternalServerError, "failed to decrease cart item quantity")
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "cart item not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to decrease cart item quantity")
+		}
 		log.Error().Err(err).Msg("error occurred")
 		return
 	}
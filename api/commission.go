@@ -0,0 +1,228 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/OCD-Labs/store-hub/commission"
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type createCommissionRuleRequestBody struct {
+	Scope       string `json:"scope" validate:"required,oneof=GLOBAL STORE CATEGORY"`
+	StoreID     int64  `json:"store_id" validate:"required_if=Scope STORE,omitempty,min=1"`
+	Category    string `json:"category" validate:"required_if=Scope CATEGORY,max=100"`
+	Percentage  string `json:"percentage" validate:"required,numeric"`
+	FixedAmount string `json:"fixed_amount" validate:"omitempty,numeric"`
+}
+
+// createCommissionRule maps to endpoint "POST /admin/commission-rules"
+func (s *StoreHub) createCommissionRule(w http.ResponseWriter, r *http.Request) {
+	var reqBody createCommissionRuleRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	fixedAmount, ok := s.bindCommissionAmounts(w, r, reqBody.Percentage, reqBody.FixedAmount)
+	if !ok {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	arg := db.CreateCommissionRuleParams{
+		Scope:       reqBody.Scope,
+		Percentage:  reqBody.Percentage,
+		FixedAmount: fixedAmount.String(),
+		CreatedBy:   authPayload.UserID,
+	}
+	switch reqBody.Scope {
+	case commission.ScopeStore:
+		arg.StoreID = sql.NullInt64{Int64: reqBody.StoreID, Valid: true}
+	case commission.ScopeCategory:
+		arg.Category = sql.NullString{String: reqBody.Category, Valid: true}
+	}
+
+	rule, err := s.dbStore.CreateCommissionRule(r.Context(), arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				s.errorResponse(w, r, http.StatusConflict, "a commission rule already exists for this scope")
+			case "foreign_key_violation":
+				s.errorResponse(w, r, http.StatusNotFound, "store not found")
+			case "check_violation", "numeric_value_out_of_range":
+				s.errorResponse(w, r, http.StatusBadRequest, "invalid commission rule")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to create commission rule")
+			}
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to create commission rule")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "created commission rule",
+			"result": envelop{
+				"commission_rule": rule,
+			},
+		},
+	}, nil)
+}
+
+type listCommissionRulesQueryStr struct {
+	Scope   string `querystr:"scope" validate:"omitempty,oneof=GLOBAL STORE CATEGORY"`
+	StoreID int64  `querystr:"store_id" validate:"omitempty,min=1"`
+}
+
+// listCommissionRules maps to endpoint "GET /admin/commission-rules"
+func (s *StoreHub) listCommissionRules(w http.ResponseWriter, r *http.Request) {
+	var reqQueryStr listCommissionRulesQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	rules, err := s.dbStore.ListCommissionRules(r.Context(), db.ListCommissionRulesParams{
+		Scope: sql.NullString{
+			String: reqQueryStr.Scope,
+			Valid:  reqQueryStr.Scope != "",
+		},
+		StoreID: sql.NullInt64{
+			Int64: reqQueryStr.StoreID,
+			Valid: reqQueryStr.StoreID != 0,
+		},
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list commission rules")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some commission rules",
+			"result": envelop{
+				"commission_rules": rules,
+			},
+		},
+	}, nil)
+}
+
+type updateCommissionRuleRequestBody struct {
+	Percentage  string `json:"percentage" validate:"omitempty,numeric"`
+	FixedAmount string `json:"fixed_amount" validate:"omitempty,numeric"`
+}
+
+type updateCommissionRulePathVars struct {
+	RuleID int64 `path:"rule_id" validate:"required,min=1"`
+}
+
+// updateCommissionRule maps to endpoint "PATCH /admin/commission-rules/{rule_id}"
+func (s *StoreHub) updateCommissionRule(w http.ResponseWriter, r *http.Request) {
+	var reqBody updateCommissionRuleRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars updateCommissionRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	fixedAmount, ok := s.bindCommissionAmounts(w, r, reqBody.Percentage, reqBody.FixedAmount)
+	if !ok {
+		return
+	}
+
+	rule, err := s.dbStore.UpdateCommissionRule(r.Context(), db.UpdateCommissionRuleParams{
+		RuleID: pathVars.RuleID,
+		Percentage: sql.NullString{
+			String: reqBody.Percentage,
+			Valid:  reqBody.Percentage != "",
+		},
+		FixedAmount: sql.NullString{
+			String: fixedAmount.String(),
+			Valid:  reqBody.FixedAmount != "",
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "commission rule not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update commission rule")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated commission rule",
+			"result": envelop{
+				"commission_rule": rule,
+			},
+		},
+	}, nil)
+}
+
+type deleteCommissionRulePathVars struct {
+	RuleID int64 `path:"rule_id" validate:"required,min=1"`
+}
+
+// deleteCommissionRule maps to endpoint "DELETE /admin/commission-rules/{rule_id}"
+func (s *StoreHub) deleteCommissionRule(w http.ResponseWriter, r *http.Request) {
+	var pathVars deleteCommissionRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	deleted, err := s.dbStore.DeleteCommissionRule(r.Context(), pathVars.RuleID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to delete commission rule")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if deleted == 0 {
+		s.errorResponse(w, r, http.StatusNotFound, "commission rule not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusNoContent, nil, nil)
+}
+
+// bindCommissionAmounts checks a rule's percentage is between 0 and 100 and its
+// fixed amount is not negative, writing a 400 response if not. An empty value
+// is not checked, and an empty fixedAmount is returned as zero.
+func (s *StoreHub) bindCommissionAmounts(w http.ResponseWriter, r *http.Request, percentage, fixedAmount string) (money.Amount, bool) {
+	if percentage != "" {
+		pct, err := strconv.ParseFloat(percentage, 64)
+		if err != nil || pct < 0 || pct > 100 {
+			s.errorResponse(w, r, http.StatusBadRequest, "percentage must be between 0 and 100")
+			return money.Zero, false
+		}
+	}
+
+	if fixedAmount == "" {
+		return money.Zero, true
+	}
+
+	amount, err := money.Parse(fixedAmount)
+	if err != nil || amount < money.Zero {
+		s.errorResponse(w, r, http.StatusBadRequest, "fixed_amount must be a non-negative amount")
+		return money.Zero, false
+	}
+	return amount, true
+}
@@ -0,0 +1,472 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type createCouponRequestBody struct {
+	Code          string     `json:"code" validate:"required,alphanum,max=50"`
+	Description   string     `json:"description" validate:"max=500"`
+	DiscountType  string     `json:"discount_type" validate:"required,oneof=PERCENT FIXED"`
+	DiscountValue string     `json:"discount_value" validate:"required,numeric"`
+	Scope         string     `json:"scope" validate:"required,oneof=STORE ITEM CATEGORY"`
+	ItemIDs       []int64    `json:"item_ids" validate:"required_if=Scope ITEM,dive,min=1"`
+	Category      string     `json:"category" validate:"required_if=Scope CATEGORY,max=100"`
+	MinSpend      string     `json:"min_spend" validate:"omitempty,numeric"`
+	UsageLimit    int32      `json:"usage_limit" validate:"min=0"`
+	PerUserLimit  int32      `json:"per_user_limit" validate:"min=0"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	IsActive      *bool      `json:"is_active"`
+}
+
+type createCouponPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// createCoupon maps to endpoint "POST /inventory/stores/{store_id}/coupons"
+func (s *StoreHub) createCoupon(w http.ResponseWriter, r *http.Request) {
+	var reqBody createCouponRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars createCouponPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	minSpend, ok := s.bindCouponMinSpend(w, r, reqBody.MinSpend)
+	if !ok {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	arg := db.CreateCouponParams{
+		StoreID:       pathVars.StoreID,
+		Code:          reqBody.Code,
+		Description:   reqBody.Description,
+		DiscountType:  reqBody.DiscountType,
+		DiscountValue: reqBody.DiscountValue,
+		Scope:         reqBody.Scope,
+		ItemIds:       []int64{},
+		MinSpend:      minSpend.String(),
+		UsageLimit:    reqBody.UsageLimit,
+		PerUserLimit:  reqBody.PerUserLimit,
+		StartsAt:      time.Now(),
+		IsActive:      true,
+		CreatedBy:     authPayload.UserID,
+	}
+	switch reqBody.Scope {
+	case "ITEM":
+		arg.ItemIds = reqBody.ItemIDs
+	case "CATEGORY":
+		arg.Category = reqBody.Category
+	}
+	if reqBody.StartsAt != nil {
+		arg.StartsAt = *reqBody.StartsAt
+	}
+	if reqBody.EndsAt != nil {
+		arg.EndsAt = sql.NullTime{Time: *reqBody.EndsAt, Valid: true}
+	}
+	if reqBody.IsActive != nil {
+		arg.IsActive = *reqBody.IsActive
+	}
+
+	coupon, err := s.dbStore.CreateCoupon(r.Context(), arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				s.errorResponse(w, r, http.StatusConflict, "coupon code is already taken")
+			case "check_violation", "numeric_value_out_of_range":
+				s.errorResponse(w, r, http.StatusBadRequest, "invalid coupon")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to create coupon")
+			}
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to create coupon")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "created coupon",
+			"result": envelop{
+				"coupon": coupon,
+			},
+		},
+	}, nil)
+}
+
+type listStoreCouponsPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listStoreCouponsQueryStr struct {
+	IsActive string `querystr:"is_active" validate:"omitempty,oneof=true false"`
+	Page     int    `querystr:"page" validate:"max=10000000"`
+	PageSize int    `querystr:"page_size" validate:"max=20"`
+}
+
+// storeCoupon is a coupon with the number of times it has been redeemed.
+type storeCoupon struct {
+	db.Coupon
+	TimesRedeemed int64 `json:"times_redeemed"`
+}
+
+// listStoreCoupons maps to endpoint "GET /inventory/stores/{store_id}/coupons"
+func (s *StoreHub) listStoreCoupons(w http.ResponseWriter, r *http.Request) {
+	var pathVars listStoreCouponsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listStoreCouponsQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 15
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListStoreCoupons(r.Context(), db.ListStoreCouponsParams{
+		StoreID: pathVars.StoreID,
+		IsActive: sql.NullBool{
+			Bool:  reqQueryStr.IsActive == "true",
+			Valid: reqQueryStr.IsActive != "",
+		},
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list coupons")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	coupons := make([]storeCoupon, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		coupons[i] = storeCoupon{
+			Coupon: db.Coupon{
+				ID:            row.ID,
+				StoreID:       row.StoreID,
+				Code:          row.Code,
+				Description:   row.Description,
+				DiscountType:  row.DiscountType,
+				DiscountValue: row.DiscountValue,
+				Scope:         row.Scope,
+				ItemIds:       row.ItemIds,
+				Category:      row.Category,
+				MinSpend:      row.MinSpend,
+				UsageLimit:    row.UsageLimit,
+				PerUserLimit:  row.PerUserLimit,
+				StartsAt:      row.StartsAt,
+				EndsAt:        row.EndsAt,
+				IsActive:      row.IsActive,
+				CreatedBy:     row.CreatedBy,
+				CreatedAt:     row.CreatedAt,
+				UpdatedAt:     row.UpdatedAt,
+			},
+			TimesRedeemed: row.TimesRedeemed,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some coupons",
+			"result": envelop{
+				"coupons":  coupons,
+				"metadata": pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type getCouponPathVars struct {
+	StoreID  int64 `path:"store_id" validate:"required,min=1"`
+	CouponID int64 `path:"coupon_id" validate:"required,min=1"`
+}
+
+// getCoupon maps to endpoint "GET /inventory/stores/{store_id}/coupons/{coupon_id}"
+func (s *StoreHub) getCoupon(w http.ResponseWriter, r *http.Request) {
+	var pathVars getCouponPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	coupon, err := s.dbStore.GetCoupon(r.Context(), db.GetCouponParams{
+		CouponID: pathVars.CouponID,
+		StoreID:  pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "coupon not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch coupon")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found coupon",
+			"result": envelop{
+				"coupon": coupon,
+			},
+		},
+	}, nil)
+}
+
+type updateCouponRequestBody struct {
+	Description  *string    `json:"description" validate:"omitempty,max=500"`
+	MinSpend     string     `json:"min_spend" validate:"omitempty,numeric"`
+	UsageLimit   *int32     `json:"usage_limit" validate:"omitempty,min=0"`
+	PerUserLimit *int32     `json:"per_user_limit" validate:"omitempty,min=0"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	IsActive     *bool      `json:"is_active"`
+}
+
+type updateCouponPathVars struct {
+	StoreID  int64 `path:"store_id" validate:"required,min=1"`
+	CouponID int64 `path:"coupon_id" validate:"required,min=1"`
+}
+
+// updateCoupon maps to endpoint "PATCH /inventory/stores/{store_id}/coupons/{coupon_id}"
+func (s *StoreHub) updateCoupon(w http.ResponseWriter, r *http.Request) {
+	var reqBody updateCouponRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars updateCouponPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	minSpend, ok := s.bindCouponMinSpend(w, r, reqBody.MinSpend)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateCouponParams{
+		CouponID: pathVars.CouponID,
+		StoreID:  pathVars.StoreID,
+		MinSpend: sql.NullString{
+			String: minSpend.String(),
+			Valid:  reqBody.MinSpend != "",
+		},
+	}
+	if reqBody.Description != nil {
+		arg.Description = sql.NullString{String: *reqBody.Description, Valid: true}
+	}
+	if reqBody.UsageLimit != nil {
+		arg.UsageLimit = sql.NullInt32{Int32: *reqBody.UsageLimit, Valid: true}
+	}
+	if reqBody.PerUserLimit != nil {
+		arg.PerUserLimit = sql.NullInt32{Int32: *reqBody.PerUserLimit, Valid: true}
+	}
+	if reqBody.StartsAt != nil {
+		arg.StartsAt = sql.NullTime{Time: *reqBody.StartsAt, Valid: true}
+	}
+	if reqBody.EndsAt != nil {
+		arg.EndsAt = sql.NullTime{Time: *reqBody.EndsAt, Valid: true}
+	}
+	if reqBody.IsActive != nil {
+		arg.IsActive = sql.NullBool{Bool: *reqBody.IsActive, Valid: true}
+	}
+
+	coupon, err := s.dbStore.UpdateCoupon(r.Context(), arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "check_violation" {
+			s.errorResponse(w, r, http.StatusBadRequest, "invalid coupon")
+		} else if errors.Is(err, sql.ErrNoRows) {
+			s.errorResponse(w, r, http.StatusNotFound, "coupon not found")
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update coupon")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated coupon",
+			"result": envelop{
+				"coupon": coupon,
+			},
+		},
+	}, nil)
+}
+
+type deleteCouponPathVars struct {
+	StoreID  int64 `path:"store_id" validate:"required,min=1"`
+	CouponID int64 `path:"coupon_id" validate:"required,min=1"`
+}
+
+// deleteCoupon maps to endpoint "DELETE /inventory/stores/{store_id}/coupons/{coupon_id}"
+func (s *StoreHub) deleteCoupon(w http.ResponseWriter, r *http.Request) {
+	var pathVars deleteCouponPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	deleted, err := s.dbStore.DeleteCoupon(r.Context(), db.DeleteCouponParams{
+		CouponID: pathVars.CouponID,
+		StoreID:  pathVars.StoreID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to delete coupon")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if deleted == 0 {
+		// a redeemed coupon is kept for its orders, and can only be deactivated
+		s.errorResponse(w, r, http.StatusNotFound, "coupon not found or already redeemed")
+		return
+	}
+
+	s.writeJSON(w, http.StatusNoContent, nil, nil)
+}
+
+type applyCartCouponRequestBody struct {
+	Code string `json:"code" validate:"required,max=50"`
+}
+
+type applyCartCouponPathVars struct {
+	CartID int64 `path:"cart_id" validate:"required,min=1"`
+}
+
+// applyCartCoupon maps to endpoint "POST /carts/{cart_id}/coupons"
+func (s *StoreHub) applyCartCoupon(w http.ResponseWriter, r *http.Request) {
+	var reqBody applyCartCouponRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars applyCartCouponPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	result, err := s.dbStore.ApplyCartCouponTx(r.Context(), db.ApplyCartCouponTxParams{
+		UserID: authPayload.UserID,
+		CartID: pathVars.CartID,
+		Code:   reqBody.Code,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrCartNotFound), errors.Is(err, db.ErrCouponNotFound):
+			s.errorResponse(w, r, http.StatusNotFound, err.Error())
+		case db.IsCouponRejected(err):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to apply coupon")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "applied coupon to cart",
+			"result": envelop{
+				"coupon":    result.Coupon,
+				"breakdown": result.Breakdown,
+			},
+		},
+	}, nil)
+}
+
+type removeCartCouponPathVars struct {
+	CartID   int64 `path:"cart_id" validate:"required,min=1"`
+	CouponID int64 `path:"coupon_id" validate:"required,min=1"`
+}
+
+// removeCartCoupon maps to endpoint "DELETE /carts/{cart_id}/coupons/{coupon_id}"
+func (s *StoreHub) removeCartCoupon(w http.ResponseWriter, r *http.Request) {
+	var pathVars removeCartCouponPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	cartID, err := s.dbStore.GetCartID(r.Context(), authPayload.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to remove coupon")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if cartID != pathVars.CartID {
+		s.errorResponse(w, r, http.StatusNotFound, "cart not found")
+		return
+	}
+
+	removed, err := s.dbStore.RemoveCartCoupon(r.Context(), db.RemoveCartCouponParams{
+		CartID:   pathVars.CartID,
+		CouponID: pathVars.CouponID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to remove coupon")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if removed == 0 {
+		s.errorResponse(w, r, http.StatusNotFound, "coupon not applied to cart")
+		return
+	}
+
+	s.writeJSON(w, http.StatusNoContent, nil, nil)
+}
+
+// bindCouponMinSpend checks a coupon's minimum spend is not negative, writing a
+// 400 response if not. An empty minSpend is returned as zero.
+func (s *StoreHub) bindCouponMinSpend(w http.ResponseWriter, r *http.Request, minSpend string) (money.Amount, bool) {
+	if minSpend == "" {
+		return money.Zero, true
+	}
+
+	amount, err := money.Parse(minSpend)
+	if err != nil || amount < money.Zero {
+		s.errorResponse(w, r, http.StatusBadRequest, "min_spend must be a non-negative amount")
+		return money.Zero, false
+	}
+	return amount, true
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type getStoreDeliveryRulePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// getStoreDeliveryRule maps to endpoint "GET /inventory/stores/{store_id}/delivery-rules"
+func (s *StoreHub) getStoreDeliveryRule(w http.ResponseWriter, r *http.Request) {
+	var pathVar getStoreDeliveryRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVar); err != nil {
+		return
+	}
+
+	rule, err := s.dbStore.GetStoreDeliveryRule(r.Context(), pathVar.StoreID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch delivery rules")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		// a store without rules delivers for free
+		rule = db.StoreDeliveryRule{
+			StoreID:               pathVar.StoreID,
+			BaseFee:               money.Zero.String(),
+			PerItemFee:            money.Zero.String(),
+			FreeDeliveryThreshold: money.Zero.String(),
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "fetched delivery rules",
+			"result": envelop{
+				"delivery_rule": rule,
+			},
+		},
+	}, nil)
+}
+
+type updateStoreDeliveryRuleRequestBody struct {
+	BaseFee               string `json:"base_fee" validate:"required,numeric"`
+	PerItemFee            string `json:"per_item_fee" validate:"required,numeric"`
+	FreeDeliveryThreshold string `json:"free_delivery_threshold" validate:"required,numeric"`
+}
+
+type updateStoreDeliveryRulePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// updateStoreDeliveryRule maps to endpoint "PUT /inventory/stores/{store_id}/delivery-rules"
+func (s *StoreHub) updateStoreDeliveryRule(w http.ResponseWriter, r *http.Request) {
+	var reqBody updateStoreDeliveryRuleRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVar updateStoreDeliveryRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVar); err != nil {
+		return
+	}
+
+	var amounts [3]money.Amount
+	for i, v := range []string{reqBody.BaseFee, reqBody.PerItemFee, reqBody.FreeDeliveryThreshold} {
+		amount, err := money.Parse(v)
+		if err != nil || amount < money.Zero {
+			s.errorResponse(w, r, http.StatusBadRequest, "fees must be non-negative amounts")
+			return
+		}
+		amounts[i] = amount
+	}
+
+	rule, err := s.dbStore.UpsertStoreDeliveryRule(r.Context(), db.UpsertStoreDeliveryRuleParams{
+		StoreID:               pathVar.StoreID,
+		BaseFee:               amounts[0].String(),
+		PerItemFee:            amounts[1].String(),
+		FreeDeliveryThreshold: amounts[2].String(),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				s.errorResponse(w, r, http.StatusNotFound, "store not found")
+			case "check_violation", "numeric_value_out_of_range":
+				s.errorResponse(w, r, http.StatusBadRequest, "invalid delivery fees")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to update delivery rules")
+			}
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update delivery rules")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated delivery rules",
+			"result": envelop{
+				"delivery_rule": rule,
+			},
+		},
+	}, nil)
+}
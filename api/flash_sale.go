@@ -0,0 +1,458 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/cache"
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// flashSaleCountdown is a flash sale as buyers see it: whether it's live, how
+// long until it starts or ends, and the units left.
+type flashSaleCountdown struct {
+	ID              int64     `json:"id"`
+	ItemID          int64     `json:"item_id"`
+	SalePrice       string    `json:"sale_price"`
+	Quantity        int32     `json:"quantity"`
+	PerUserLimit    int32     `json:"per_user_limit"`
+	Remaining       int64     `json:"remaining"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	IsLive          bool      `json:"is_live"`
+	StartsInSeconds int64     `json:"starts_in_seconds"`
+	EndsInSeconds   int64     `json:"ends_in_seconds"`
+}
+
+// countdown shows a flash sale, of which claimed units are taken, as of now.
+func countdown(flashSale db.FlashSale, claimed int64, now time.Time) flashSaleCountdown {
+	c := flashSaleCountdown{
+		ID:           flashSale.ID,
+		ItemID:       flashSale.ItemID,
+		SalePrice:    flashSale.SalePrice,
+		Quantity:     flashSale.Quantity,
+		PerUserLimit: flashSale.PerUserLimit,
+		Remaining:    int64(flashSale.Quantity) - claimed,
+		StartsAt:     flashSale.StartsAt,
+		EndsAt:       flashSale.EndsAt,
+		IsLive:       !now.Before(flashSale.StartsAt) && now.Before(flashSale.EndsAt),
+	}
+	if c.Remaining < 0 {
+		c.Remaining = 0
+	}
+	if now.Before(flashSale.StartsAt) {
+		c.StartsInSeconds = int64(flashSale.StartsAt.Sub(now).Seconds())
+	}
+	if now.Before(flashSale.EndsAt) {
+		c.EndsInSeconds = int64(flashSale.EndsAt.Sub(now).Seconds())
+	}
+	return c
+}
+
+// flashSalesClaimed returns the units claimed of each of flashSales. Redis
+// holds the live counts; if it can't be read, the counts last reconciled to
+// Postgres are used instead.
+func (s *StoreHub) flashSalesClaimed(ctx context.Context, flashSales []db.FlashSale) map[int64]int64 {
+	ids := make([]int64, len(flashSales))
+	for i, flashSale := range flashSales {
+		ids[i] = flashSale.ID
+	}
+
+	claimed, err := s.cache.FlashSalesClaimed(ctx, ids)
+	if err == nil {
+		return claimed
+	}
+	log.Error().Err(err).Msg("failed to read flash sale counters")
+
+	claimed = make(map[int64]int64, len(flashSales))
+	for _, flashSale := range flashSales {
+		claimed[flashSale.ID] = int64(flashSale.ClaimedQuantity)
+	}
+	return claimed
+}
+
+// claimFlashSales claims, for the checkout under reference, the cart's quantity
+// of each of a user's items on a live flash sale. If any can't be claimed,
+// those already claimed are given back.
+func (s *StoreHub) claimFlashSales(ctx context.Context, userID int64, reference string) ([]cache.FlashSaleClaim, error) {
+	flashSales, err := s.dbStore.ListCartFlashSales(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make([]cache.FlashSaleClaim, 0, len(flashSales))
+	for _, flashSale := range flashSales {
+		claim := cache.FlashSaleClaim{
+			FlashSaleID:  flashSale.ID,
+			UserID:       userID,
+			Reference:    reference,
+			Units:        int64(flashSale.CartQuantity),
+			Quantity:     int64(flashSale.Quantity),
+			PerUserLimit: int64(flashSale.PerUserLimit),
+			EndsAt:       flashSale.EndsAt,
+		}
+
+		if _, err := s.cache.ClaimFlashSale(ctx, claim); err != nil {
+			s.releaseFlashSaleClaims(claims)
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+
+	return claims, nil
+}
+
+// releaseFlashSaleClaims gives back claims no checkout recorded. It runs after
+// the response is decided, so it outlives the request.
+func (s *StoreHub) releaseFlashSaleClaims(claims []cache.FlashSaleClaim) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, claim := range claims {
+		if err := s.cache.ReleaseFlashSaleClaim(ctx, claim); err != nil {
+			log.Error().Err(err).
+				Int64("flash_sale_id", claim.FlashSaleID).
+				Str("reference", claim.Reference).
+				Msg("failed to release flash sale claim")
+		}
+	}
+}
+
+type createFlashSaleRequestBody struct {
+	ItemID       int64     `json:"item_id" validate:"required,min=1"`
+	SalePrice    string    `json:"sale_price" validate:"required,numeric"`
+	Quantity     int32     `json:"quantity" validate:"required,min=1"`
+	PerUserLimit int32     `json:"per_user_limit" validate:"required,min=1,ltefield=Quantity"`
+	StartsAt     time.Time `json:"starts_at" validate:"required"`
+	EndsAt       time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+type createFlashSalePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// createFlashSale maps to endpoint "POST /inventory/stores/{store_id}/flash-sales"
+func (s *StoreHub) createFlashSale(w http.ResponseWriter, r *http.Request) {
+	var reqBody createFlashSaleRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars createFlashSalePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	salePrice, err := money.Parse(reqBody.SalePrice)
+	if err != nil || salePrice <= money.Zero {
+		s.errorResponse(w, r, http.StatusBadRequest, "sale_price must be a positive amount")
+		return
+	}
+
+	if !reqBody.EndsAt.After(time.Now()) {
+		s.errorResponse(w, r, http.StatusBadRequest, "ends_at must be in the future")
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	flashSale, err := s.dbStore.CreateFlashSaleTx(r.Context(), db.CreateFlashSaleParams{
+		StoreID:      pathVars.StoreID,
+		ItemID:       reqBody.ItemID,
+		SalePrice:    salePrice.String(),
+		Quantity:     reqBody.Quantity,
+		PerUserLimit: reqBody.PerUserLimit,
+		StartsAt:     reqBody.StartsAt,
+		EndsAt:       reqBody.EndsAt,
+		CreatedBy:    authPayload.UserID,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "check_violation", "numeric_value_out_of_range":
+				s.errorResponse(w, r, http.StatusBadRequest, "invalid flash sale")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to create flash sale")
+			}
+		} else {
+			switch {
+			case errors.Is(err, db.ErrItemNotFound):
+				s.errorResponse(w, r, http.StatusNotFound, "item not found")
+			case errors.Is(err, db.ErrFlashSaleExceedsStock), errors.Is(err, db.ErrFlashSaleOverlaps):
+				s.errorResponse(w, r, http.StatusConflict, err.Error())
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to create flash sale")
+			}
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "created flash sale",
+			"result": envelop{
+				"flash_sale": flashSale,
+			},
+		},
+	}, nil)
+}
+
+type listStoreFlashSalesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listStoreFlashSalesQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=20"`
+}
+
+// storeFlashSale is a flash sale with the name of its item.
+type storeFlashSale struct {
+	db.FlashSale
+	ItemName string `json:"item_name"`
+}
+
+// listStoreFlashSales maps to endpoint "GET /inventory/stores/{store_id}/flash-sales"
+func (s *StoreHub) listStoreFlashSales(w http.ResponseWriter, r *http.Request) {
+	var pathVars listStoreFlashSalesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listStoreFlashSalesQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 10
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListStoreFlashSales(r.Context(), db.ListStoreFlashSalesParams{
+		StoreID:  pathVars.StoreID,
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list flash sales")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	flashSales := make([]storeFlashSale, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		flashSales[i] = storeFlashSale{
+			FlashSale: db.FlashSale{
+				ID:              row.ID,
+				StoreID:         row.StoreID,
+				ItemID:          row.ItemID,
+				SalePrice:       row.SalePrice,
+				Quantity:        row.Quantity,
+				PerUserLimit:    row.PerUserLimit,
+				ClaimedQuantity: row.ClaimedQuantity,
+				SoldQuantity:    row.SoldQuantity,
+				StartsAt:        row.StartsAt,
+				EndsAt:          row.EndsAt,
+				CancelledAt:     row.CancelledAt,
+				ReconciledAt:    row.ReconciledAt,
+				CreatedBy:       row.CreatedBy,
+				CreatedAt:       row.CreatedAt,
+				UpdatedAt:       row.UpdatedAt,
+			},
+			ItemName: row.ItemName,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some flash sales",
+			"result": envelop{
+				"flash_sales": flashSales,
+				"metadata":    pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type cancelFlashSalePathVars struct {
+	StoreID     int64 `path:"store_id" validate:"required,min=1"`
+	FlashSaleID int64 `path:"flash_sale_id" validate:"required,min=1"`
+}
+
+// cancelFlashSale maps to endpoint "DELETE /inventory/stores/{store_id}/flash-sales/{flash_sale_id}"
+func (s *StoreHub) cancelFlashSale(w http.ResponseWriter, r *http.Request) {
+	var pathVars cancelFlashSalePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	arg := db.CancelFlashSaleParams{
+		FlashSaleID: pathVars.FlashSaleID,
+		StoreID:     pathVars.StoreID,
+	}
+
+	flashSale, err := s.dbStore.CancelFlashSale(r.Context(), arg)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to cancel flash sale")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		// Tell a sale that can't be cancelled apart from one that doesn't exist.
+		_, err = s.dbStore.GetFlashSale(r.Context(), db.GetFlashSaleParams(arg))
+		switch {
+		case err == nil:
+			s.errorResponse(w, r, http.StatusConflict, "flash sale has ended or was already cancelled")
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "flash sale not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to cancel flash sale")
+			log.Error().Err(err).Msg("error occurred")
+		}
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "cancelled flash sale",
+			"result": envelop{
+				"flash_sale": flashSale,
+			},
+		},
+	}, nil)
+}
+
+type listUpcomingFlashSalesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listUpcomingFlashSalesQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=20"`
+}
+
+// upcomingFlashSale is a flash sale's countdown with the item it's for.
+type upcomingFlashSale struct {
+	flashSaleCountdown
+	ItemName     string `json:"item_name"`
+	ItemPrice    string `json:"item_price"`
+	ItemCurrency string `json:"item_currency"`
+	ItemImage    string `json:"item_image"`
+}
+
+// listUpcomingFlashSales maps to endpoint "GET /stores/{store_id}/flash-sales"
+func (s *StoreHub) listUpcomingFlashSales(w http.ResponseWriter, r *http.Request) {
+	var pathVars listUpcomingFlashSalesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listUpcomingFlashSalesQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 10
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListUpcomingFlashSales(r.Context(), db.ListUpcomingFlashSalesParams{
+		StoreID:  pathVars.StoreID,
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list flash sales")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	flashSales := make([]db.FlashSale, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		flashSales[i] = db.FlashSale{
+			ID:              row.ID,
+			StoreID:         row.StoreID,
+			ItemID:          row.ItemID,
+			SalePrice:       row.SalePrice,
+			Quantity:        row.Quantity,
+			PerUserLimit:    row.PerUserLimit,
+			ClaimedQuantity: row.ClaimedQuantity,
+			StartsAt:        row.StartsAt,
+			EndsAt:          row.EndsAt,
+		}
+	}
+
+	claimed := s.flashSalesClaimed(r.Context(), flashSales)
+	now := time.Now()
+
+	upcoming := make([]upcomingFlashSale, len(rows))
+	for i, row := range rows {
+		upcoming[i] = upcomingFlashSale{
+			flashSaleCountdown: countdown(flashSales[i], claimed[row.ID], now),
+			ItemName:           row.ItemName,
+			ItemPrice:          row.ItemPrice,
+			ItemCurrency:       row.ItemCurrency,
+			ItemImage:          row.ItemImage,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some flash sales",
+			"result": envelop{
+				"flash_sales": upcoming,
+				"metadata":    pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+// itemFlashSale returns the countdown of the flash sale an item is on, or its
+// next one, and nil if it has neither.
+func (s *StoreHub) itemFlashSale(ctx context.Context, itemID int64) (*flashSaleCountdown, error) {
+	flashSale, err := s.dbStore.GetItemFlashSale(ctx, itemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch item's flash sale: %w", err)
+	}
+
+	claimed := s.flashSalesClaimed(ctx, []db.FlashSale{flashSale})
+	c := countdown(flashSale, claimed[flashSale.ID], time.Now())
+	return &c, nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/pricing"
+	"github.com/rs/zerolog/log"
+)
+
+// listFxRates maps to endpoint "GET /fx-rates"
+func (s *StoreHub) listFxRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := s.dbStore.ListFxRates(r.Context())
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list exchange rates")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some exchange rates",
+			"result": envelop{
+				"base":     fx.Base,
+				"fx_rates": rates,
+			},
+		},
+	}, nil)
+}
+
+// refreshFxRates maps to endpoint "POST /admin/fx-rates/refresh"
+func (s *StoreHub) refreshFxRates(w http.ResponseWriter, r *http.Request) {
+	quote, err := s.fxProvider.FetchRates(r.Context())
+	if err != nil {
+		s.errorResponse(w, r, http.StatusBadGateway, "failed to fetch exchange rates")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	rates, err := s.dbStore.UpdateFxRatesTx(r.Context(), db.UpdateFxRatesTxParams{
+		Source: s.fxProvider.Name(),
+		Quote:  quote,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to update exchange rates")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated exchange rates",
+			"result": envelop{
+				"base":     fx.Base,
+				"fx_rates": rates,
+			},
+		},
+	}, nil)
+}
+
+// errNoFxRate is returned by displayBreakdown for a currency without a rate.
+var errNoFxRate = errors.New("no exchange rate for currency")
+
+// displayBreakdown converts breakdown to currency, to show a buyer their totals
+// in it.
+func (s *StoreHub) displayBreakdown(ctx context.Context, breakdown pricing.Breakdown, currency string) (pricing.Breakdown, error) {
+	if currency == breakdown.Currency {
+		return breakdown, nil
+	}
+
+	fxRate, err := s.dbStore.GetFxRate(ctx, currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pricing.Breakdown{}, errNoFxRate
+		}
+		return pricing.Breakdown{}, err
+	}
+
+	rate, err := fx.Rates{currency: fxRate.Rate}.Rate(breakdown.Currency, currency)
+	if err != nil {
+		return pricing.Breakdown{}, err
+	}
+
+	return breakdown.Convert(currency, rate)
+}
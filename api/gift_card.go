@@ -0,0 +1,376 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/OCD-Labs/store-hub/credit"
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/near"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/OCD-Labs/store-hub/payment"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Bounds on what a gift card can be bought for.
+var (
+	minGiftCardAmount = money.FromMinor(100_00)
+	maxGiftCardAmount = money.FromMinor(1_000_000_00)
+)
+
+// purchasedGiftCard is a gift card with the name of the store it's for.
+type purchasedGiftCard struct {
+	db.GiftCard
+	StoreName string `json:"store_name"`
+}
+
+// hideUnpaidCode blanks the code of a gift card that hasn't been paid for, so it
+// can't be handed out before it's usable.
+func hideUnpaidCode(giftCard *db.GiftCard) {
+	if giftCard.Status != db.GiftCardActive {
+		giftCard.Code = ""
+	}
+}
+
+type buyGiftCardRequestBody struct {
+	Amount          string `json:"amount" validate:"required,numeric"`
+	PaymentProvider string `json:"payment_provider" validate:"required,oneof=PAYSTACK NEAR_WALLET"`
+}
+
+type buyGiftCardPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// buyGiftCard maps to endpoint "POST /stores/{store_id}/gift-cards"
+func (s *StoreHub) buyGiftCard(w http.ResponseWriter, r *http.Request) {
+	var reqBody buyGiftCardRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars buyGiftCardPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	amount, err := money.Parse(reqBody.Amount)
+	if err != nil || amount < minGiftCardAmount || amount > maxGiftCardAmount {
+		s.errorResponse(w, r, http.StatusBadRequest, "amount must be between "+minGiftCardAmount.String()+" and "+maxGiftCardAmount.String())
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	user, err := s.dbStore.GetUserByID(r.Context(), authPayload.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "user not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch user's profile")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	store, err := s.dbStore.GetStoreByID(r.Context(), pathVars.StoreID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "store not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch store")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if store.IsFrozen {
+		s.errorResponse(w, r, http.StatusConflict, "store is frozen")
+		return
+	}
+
+	reference := uuid.NewString()
+
+	result := envelop{
+		"reference": reference,
+	}
+
+	arg := db.CreateGiftCardPurchaseTxParams{
+		StoreID:         store.ID,
+		PurchaserID:     user.ID,
+		Amount:          amount,
+		PaymentProvider: reqBody.PaymentProvider,
+		Reference:       reference,
+	}
+
+	switch reqBody.PaymentProvider {
+	case payment.ProviderPaystack:
+		initResult, err := s.paymentProvider.InitializeTransaction(r.Context(), payment.InitializeTransactionParams{
+			Email:       user.Email,
+			Amount:      amount.Minor(),
+			Reference:   reference,
+			CallbackURL: s.configs.PaystackCallbackURL,
+			Metadata: map[string]interface{}{
+				"user_id":  user.ID,
+				"store_id": store.ID,
+			},
+		})
+		if err != nil {
+			s.errorResponse(w, r, http.StatusBadGateway, "failed to initialize payment")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		arg.ProviderTxAccessCode = initResult.AccessCode
+		result["authorization_url"] = initResult.AuthorizationURL
+		result["access_code"] = initResult.AccessCode
+	case payment.ProviderNEARWallet:
+		yocto, err := s.nearAmount(amount)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to initialize payment")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		arg.NearAmount = sql.NullString{String: yocto.String(), Valid: true}
+
+		result["near_payment"] = envelop{
+			"receiver_id":  s.configs.NEARAccountID,
+			"amount_yocto": yocto.String(),
+			"amount":       near.FormatNEAR(yocto),
+		}
+	}
+
+	purchase, err := s.dbStore.CreateGiftCardPurchaseTx(r.Context(), arg)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to create gift card")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	hideUnpaidCode(&purchase.GiftCard)
+	result["gift_card"] = purchase.GiftCard
+	result["transaction"] = purchase.Transaction
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "initialized gift card purchase",
+			"result":  result,
+		},
+	}, nil)
+}
+
+type listPurchasedGiftCardsPathVars struct {
+	UserID int64 `path:"user_id" validate:"required,min=1"`
+}
+
+type listPurchasedGiftCardsQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=20"`
+}
+
+// listPurchasedGiftCards maps to endpoint "GET /users/{user_id}/gift-cards"
+func (s *StoreHub) listPurchasedGiftCards(w http.ResponseWriter, r *http.Request) {
+	var pathVars listPurchasedGiftCardsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listPurchasedGiftCardsQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+	if pathVars.UserID != authPayload.UserID {
+		s.errorResponse(w, r, http.StatusForbidden, "can't view another user's gift cards")
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 10
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListPurchasedGiftCards(r.Context(), db.ListPurchasedGiftCardsParams{
+		PurchaserID: authPayload.UserID,
+		RwLimit:     int32(filters.Limit()),
+		RwOffset:    int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list gift cards")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	giftCards := make([]purchasedGiftCard, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		giftCards[i] = purchasedGiftCard{
+			GiftCard: db.GiftCard{
+				ID:            row.ID,
+				StoreID:       row.StoreID,
+				Code:          row.Code,
+				Currency:      row.Currency,
+				InitialAmount: row.InitialAmount,
+				Balance:       row.Balance,
+				Status:        row.Status,
+				PurchaserID:   row.PurchaserID,
+				Reference:     row.Reference,
+				CreatedAt:     row.CreatedAt,
+				UpdatedAt:     row.UpdatedAt,
+			},
+			StoreName: row.StoreName,
+		}
+		hideUnpaidCode(&giftCards[i].GiftCard)
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some gift cards",
+			"result": envelop{
+				"gift_cards": giftCards,
+				"metadata":   pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type getGiftCardPathVars struct {
+	Code string `path:"code" validate:"required,max=30"`
+}
+
+// getGiftCard maps to endpoint "GET /gift-cards/{code}"
+func (s *StoreHub) getGiftCard(w http.ResponseWriter, r *http.Request) {
+	var pathVars getGiftCardPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	giftCard, err := s.dbStore.GetActiveGiftCard(r.Context(), credit.NormalizeCode(pathVars.Code))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "gift card not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch gift card")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found gift card",
+			"result": envelop{
+				"gift_card": envelop{
+					"code":     giftCard.Code,
+					"store_id": giftCard.StoreID,
+					"currency": giftCard.Currency,
+					"balance":  giftCard.Balance,
+				},
+			},
+		},
+	}, nil)
+}
+
+type getStoreCreditPathVars struct {
+	UserID int64 `path:"user_id" validate:"required,min=1"`
+}
+
+type getStoreCreditQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=20"`
+}
+
+// getStoreCredit maps to endpoint "GET /users/{user_id}/store-credit"
+func (s *StoreHub) getStoreCredit(w http.ResponseWriter, r *http.Request) {
+	var pathVars getStoreCreditPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr getStoreCreditQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+	if pathVars.UserID != authPayload.UserID {
+		s.errorResponse(w, r, http.StatusForbidden, "can't view another user's store credit")
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 10
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	balance, err := s.dbStore.GetStoreCredit(r.Context(), authPayload.UserID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch store credit")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	rows, err := s.dbStore.ListStoreCreditEntries(r.Context(), db.ListStoreCreditEntriesParams{
+		UserID:   authPayload.UserID,
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list store credit history")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	entries := make([]db.StoreCreditEntry, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		entries[i] = db.StoreCreditEntry{
+			ID:          row.ID,
+			UserID:      row.UserID,
+			Kind:        row.Kind,
+			Amount:      row.Amount,
+			Balance:     row.Balance,
+			Reference:   row.Reference,
+			Description: row.Description,
+			CreatedAt:   row.CreatedAt,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found store credit",
+			"result": envelop{
+				"balance":  balance,
+				"entries":  entries,
+				"metadata": pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/OCD-Labs/store-hub/blob"
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/imaging"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// maxImageUploadSize caps the size of an image upload, leaving room for
+// the multipart form around the image.
+const maxImageUploadSize = imaging.MaxSize + 1<<20
+
+// An uploadedImage is where an uploaded image and its thumbnails are stored.
+type uploadedImage struct {
+	URL         string                    `json:"url"`
+	ContentType string                    `json:"content_type"`
+	Width       int                       `json:"width"`
+	Height      int                       `json:"height"`
+	Thumbnails  map[string]imageThumbnail `json:"thumbnails"`
+}
+
+type imageThumbnail struct {
+	URL     string `json:"url"`
+	WebPURL string `json:"webp_url"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+}
+
+// readImageUpload reads and processes the image in the "file" field of a
+// multipart form, responding with an error if it isn't a valid image.
+func (s *StoreHub) readImageUpload(w http.ResponseWriter, r *http.Request) (imaging.Image, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUploadSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.errorResponse(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("the image must not be larger than %d bytes", imaging.MaxSize))
+		} else {
+			s.errorResponse(w, r, http.StatusBadRequest, "an image is required in the file field")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return imaging.Image{}, false
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusBadRequest, "failed to read image")
+		log.Error().Err(err).Msg("error occurred")
+		return imaging.Image{}, false
+	}
+
+	img, err := imaging.Process(content)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedType):
+			s.errorResponse(w, r, http.StatusUnsupportedMediaType, "the image must be a JPEG, PNG or GIF")
+		case errors.Is(err, imaging.ErrTooLarge):
+			s.errorResponse(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("the image must not be larger than %d bytes or %d pixels", imaging.MaxSize, imaging.MaxPixels))
+		default:
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, "the image is invalid")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return imaging.Image{}, false
+	}
+
+	return img, true
+}
+
+// putImage stores img and its thumbnails under prefix, in a directory of its
+// own so an upload never replaces another, and returns the keys of its files.
+// If any file fails to store, the ones already stored are deleted.
+func (s *StoreHub) putImage(ctx context.Context, prefix string, img imaging.Image) (uploadedImage, []string, error) {
+	dir := prefix + "/" + uuid.NewString()
+	var keys []string
+
+	put := func(name string, file imaging.File) (string, error) {
+		key := dir + "/" + name + "." + file.Ext
+		url, err := s.blobStore.Put(ctx, key, file.Data, file.ContentType)
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, key)
+		return url, nil
+	}
+
+	uploaded, err := func() (uploadedImage, error) {
+		url, err := put("original", img.Original)
+		if err != nil {
+			return uploadedImage{}, err
+		}
+
+		uploaded := uploadedImage{
+			URL:         url,
+			ContentType: img.Original.ContentType,
+			Width:       img.Original.Width,
+			Height:      img.Original.Height,
+			Thumbnails:  make(map[string]imageThumbnail, len(img.Thumbnails)),
+		}
+		for _, thumb := range img.Thumbnails {
+			url, err := put(thumb.Size, thumb.File)
+			if err != nil {
+				return uploadedImage{}, err
+			}
+			webpURL, err := put(thumb.Size, thumb.WebP)
+			if err != nil {
+				return uploadedImage{}, err
+			}
+			uploaded.Thumbnails[thumb.Size] = imageThumbnail{
+				URL:     url,
+				WebPURL: webpURL,
+				Width:   thumb.File.Width,
+				Height:  thumb.File.Height,
+			}
+		}
+		return uploaded, nil
+	}()
+	if err != nil {
+		s.deleteImage(keys)
+		return uploadedImage{}, nil, err
+	}
+
+	return uploaded, keys, nil
+}
+
+// deleteImage deletes the files of an image that couldn't be used. Failures
+// are only logged; the files are unreferenced either way.
+func (s *StoreHub) deleteImage(keys []string) {
+	for _, key := range keys {
+		if err := s.blobStore.Delete(context.Background(), key); err != nil {
+			log.Error().Err(err).Str("key", key).Msg("failed to delete image")
+		}
+	}
+}
+
+type uploadItemImagePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// uploadItemImage maps to endpoint "POST /inventory/stores/{store_id}/item-images"
+func (s *StoreHub) uploadItemImage(w http.ResponseWriter, r *http.Request) {
+	var pathVars uploadItemImagePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	img, ok := s.readImageUpload(w, r)
+	if !ok {
+		return
+	}
+
+	uploaded, _, err := s.putImage(r.Context(), fmt.Sprintf("stores/%d/items", pathVars.StoreID), img)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to upload image")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "uploaded image",
+			"result": envelop{
+				"image": uploaded,
+			},
+		},
+	}, nil)
+}
+
+type uploadStoreProfileImagePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// uploadStoreProfileImage maps to endpoint "POST /inventory/stores/{store_id}/profile-image"
+func (s *StoreHub) uploadStoreProfileImage(w http.ResponseWriter, r *http.Request) {
+	var pathVars uploadStoreProfileImagePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	img, ok := s.readImageUpload(w, r)
+	if !ok {
+		return
+	}
+
+	uploaded, keys, err := s.putImage(r.Context(), fmt.Sprintf("stores/%d/profile", pathVars.StoreID), img)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to upload image")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	store, err := s.dbStore.UpdateStore(r.Context(), db.UpdateStoreParams{
+		StoreID: pathVars.StoreID,
+		ProfileImageUrl: sql.NullString{
+			String: uploaded.URL,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		s.deleteImage(keys)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "Store not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update store profile")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated store profile image",
+			"result": envelop{
+				"store": store,
+				"image": uploaded,
+			},
+		},
+	}, nil)
+}
+
+// serveMedia serves the files of local storage, but not listings of its
+// directories. It's only routed when images are stored locally.
+func (s *StoreHub) serveMedia(dir string) http.Handler {
+	files := http.StripPrefix("/api/v1/media", http.FileServer(mediaDir{http.Dir(dir)}))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Files are never replaced, so can be cached for good.
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
+
+// mediaDir is a directory whose subdirectories can't be opened, so they
+// aren't listed.
+type mediaDir struct {
+	http.Dir
+}
+
+func (d mediaDir) Open(name string) (http.File, error) {
+	f, err := d.Dir.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		f.Close()
+		return nil, os.ErrNotExist
+	}
+	return f, nil
+}
+
+// localMediaDir is the directory of local storage, if images are stored locally.
+func (s *StoreHub) localMediaDir() (string, bool) {
+	local, ok := s.blobStore.(*blob.LocalStorage)
+	if !ok {
+		return "", false
+	}
+	return local.Dir(), true
+}
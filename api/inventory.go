@@ -9,8 +9,11 @@ import (
 	"time"
 
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/near"
 	"github.com/OCD-Labs/store-hub/pagination"
 	"github.com/OCD-Labs/store-hub/util"
+	"github.com/OCD-Labs/store-hub/variant"
 	"github.com/OCD-Labs/store-hub/worker"
 	"github.com/hibiken/asynq"
 	"github.com/lib/pq"
@@ -27,6 +30,7 @@ type createStoreRequestBody struct {
 	ProfileImageUrl string `json:"profile_image_url" validate:"required"`
 	Category        string `json:"category" validate:"required"`
 	StoreAccountID  string `json:"store_account_id" validate:"required,min=2,max=64"`
+	NEARPublicKey   string `json:"near_public_key" validate:"required"`
 }
 
 // createStore maps to endpoint "POST /inventory/stores".
@@ -38,6 +42,13 @@ func (s *StoreHub) createStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the store's NEAR sub-account is created with its owner's key, not the platform's
+	if _, err := near.ParsePublicKey(reqBody.NEARPublicKey); err != nil {
+		s.errorResponse(w, r, http.StatusBadRequest, "invalid near_public_key")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
 	authPayload := s.contextGetMustToken(r)
 
 	// db query
@@ -53,7 +64,10 @@ func (s *StoreHub) createStore(w http.ResponseWriter, r *http.Request) {
 		AfterCreate: func(ctx context.Context, store db.Store) (err error) {
 			subaccount := fmt.Sprintf("%s-%d.%s", util.SanitizeAccountID(reqBody.StoreAccountID, s.configs.NEARNetwork), store.ID, s.configs.NEARAccountID)
 			taskNEARTxPayload := &worker.PayloadNEARTx{
-				Args: []string{"create-account", subaccount, "--masterAccount", s.configs.NEARAccountID, "--initialBalance", "1"},
+				Op:             worker.NEAROpCreateSubAccount,
+				AccountID:      subaccount,
+				InitialBalance: "1",
+				PublicKey:      reqBody.NEARPublicKey,
 			}
 
 			nearTxOpts := []asynq.Option{
@@ -124,9 +138,16 @@ type addStoreItemRequestBody struct {
 	ImageURLs          []string `json:"image_urls" validate:"required"`
 	Category           string   `json:"category" validate:"required"` // TODO: change DB schema to tags
 	DiscountPercentage string   `json:"discount_percentage" validate:"required"`
-	SupplyQuantity     int64    `json:"supply_quantity" validate:"required"`
+	SupplyQuantity     int64    `json:"supply_quantity" validate:"required_without=Variants"` // the sum of the variants' for an item with variants
 	CoverImgURL        string   `json:"cover_img_url" validate:"required"`
 	Status             string   `json:"status" validate:"required,oneof=VISIBLE HIDDEN"`
+	Currency           string   `json:"currency" validate:"omitempty,iso4217"` // defaults to fx.Base
+	WeightGrams        int64    `json:"weight_grams" validate:"min=0"`
+	SKU                string   `json:"sku" validate:"omitempty,max=64"` // unique among the store's items
+
+	// the axes and versions the item is sold in, if more than one
+	Options  []variant.Option         `json:"options"`
+	Variants []itemVariantRequestBody `json:"variants" validate:"omitempty,dive"`
 }
 
 type addStoreItemPathVar struct {
@@ -147,26 +168,54 @@ func (s *StoreHub) addStoreItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if reqBody.Currency == "" {
+		reqBody.Currency = fx.Base
+	}
+
+	// an item can only be priced in a currency with an exchange rate
+	if _, err := s.dbStore.GetFxRate(r.Context(), reqBody.Currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, "currency is not supported")
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to add item")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
 	// db query
-	arg := db.CreateStoreItemParams{
-		Name:               reqBody.Name,
-		Description:        reqBody.Description,
-		Price:              reqBody.Price,
-		StoreID:            pathVar.StoreID,
-		ImageUrls:          reqBody.ImageURLs,
-		Category:           reqBody.Category,
-		SupplyQuantity:     reqBody.SupplyQuantity,
-		DiscountPercentage: reqBody.DiscountPercentage,
-		CoverImgUrl:        reqBody.CoverImgURL,
-		Extra:              []byte("{}"),
-		Status:             reqBody.Status,
-	}
-	item, err := s.dbStore.CreateStoreItem(r.Context(), arg)
+	arg := db.CreateStoreItemTxParams{
+		CreateStoreItemParams: db.CreateStoreItemParams{
+			Name:               reqBody.Name,
+			Description:        reqBody.Description,
+			Price:              reqBody.Price,
+			StoreID:            pathVar.StoreID,
+			ImageUrls:          reqBody.ImageURLs,
+			Category:           reqBody.Category,
+			SupplyQuantity:     reqBody.SupplyQuantity,
+			DiscountPercentage: reqBody.DiscountPercentage,
+			CoverImgUrl:        reqBody.CoverImgURL,
+			Extra:              []byte("{}"),
+			Status:             reqBody.Status,
+			Currency:           reqBody.Currency,
+			WeightGrams:        reqBody.WeightGrams,
+			Sku:                reqBody.SKU,
+		},
+		Variants: itemVariantsParams(reqBody.Options, reqBody.Variants),
+		UserID:   s.contextGetMustToken(r).UserID,
+	}
+	result, err := s.dbStore.CreateStoreItemTx(r.Context(), arg)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
+		if errors.Is(err, db.ErrSKUTaken) {
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		} else if db.IsVariantRejected(err) {
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		} else if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "foreign_key_violation":
 				s.errorResponse(w, r, http.StatusConflict, "Referenced store doesn't exist.")
+			case "unique_violation":
+				s.errorResponse(w, r, http.StatusConflict, "SKU is already used by another item of the store")
 			default:
 				s.errorResponse(w, r, http.StatusInternalServerError, "failed to add item")
 			}
@@ -183,7 +232,9 @@ func (s *StoreHub) addStoreItem(w http.ResponseWriter, r *http.Request) {
 		"data": envelop{
 			"message": "add a new item",
 			"result": envelop{
-				"item": item,
+				"item":     result.Item,
+				"options":  result.Options,
+				"variants": result.Variants,
 			},
 		},
 	}, nil)
@@ -265,6 +316,12 @@ type updateStoreItemsRequestBody struct { // TODO: write custom validation tags
 	DiscountPercentage *string  `json:"discount_percentage"`
 	SupplyQuantity     *int64   `json:"supply_quantity"`
 	Status             *string  `json:"status"`
+	WeightGrams        *int64   `json:"weight_grams" validate:"omitempty,min=0"`
+	SKU                *string  `json:"sku" validate:"omitempty,max=64"`
+
+	// Options and Variants replace the item's when either is given; variants left out are deleted.
+	Options  []variant.Option         `json:"options"`
+	Variants []itemVariantRequestBody `json:"variants" validate:"omitempty,dive"`
 }
 
 type updateStoreItemsPathVar struct {
@@ -344,11 +401,41 @@ func (s *StoreHub) updateStoreItems(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	item, err := s.dbStore.UpdateItem(r.Context(), arg)
+	if reqBody.WeightGrams != nil {
+		arg.WeightGrams = sql.NullInt64{
+			Int64: *reqBody.WeightGrams,
+			Valid: true,
+		}
+	}
+	if reqBody.SKU != nil {
+		arg.Sku = sql.NullString{
+			String: *reqBody.SKU,
+			Valid:  true,
+		}
+	}
+
+	txArg := db.UpdateStoreItemTxParams{
+		UpdateItemParams: arg,
+		StoreID:          pathVar.StoreID,
+		UserID:           s.contextGetMustToken(r).UserID,
+	}
+	if reqBody.Options != nil || reqBody.Variants != nil {
+		variants := itemVariantsParams(reqBody.Options, reqBody.Variants)
+		txArg.Variants = &variants
+	}
+
+	result, err := s.dbStore.UpdateStoreItemTx(r.Context(), txArg)
 	if err != nil {
+		pqErr, isPqErr := err.(*pq.Error)
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			s.errorResponse(w, r, http.StatusNotFound, "item not found")
+		case errors.Is(err, db.ErrSKUTaken):
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		case isPqErr && pqErr.Code.Name() == "unique_violation":
+			s.errorResponse(w, r, http.StatusConflict, "SKU is already used by another item of the store")
+		case db.IsVariantRejected(err):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
 		default:
 			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update item details")
 		}
@@ -362,7 +449,9 @@ func (s *StoreHub) updateStoreItems(w http.ResponseWriter, r *http.Request) {
 		"data": envelop{
 			"message": "updated item's details",
 			"result": envelop{
-				"item": item,
+				"item":     result.Item,
+				"options":  result.Options,
+				"variants": result.Variants,
 			},
 		},
 	}, nil)
@@ -410,6 +499,8 @@ type updateStoreProfileRquestBody struct {
 	ProfileImageUrl *string  `json:"profile_image_url"`
 	Category        *string  `json:"category"`
 	Tags            []string `json:"tags"` // TODO: Ask if updating account_id of a store is necessary
+
+	LowStockThreshold *int32 `json:"low_stock_threshold" validate:"omitempty,min=0"`
 }
 
 // updateStoreProfile maps to "PATCH /api/v1/users/:user_id/stores/:store_id"
@@ -454,6 +545,12 @@ func (s *StoreHub) updateStoreProfile(w http.ResponseWriter, r *http.Request) {
 			Valid:  true,
 		}
 	}
+	if reqBody.LowStockThreshold != nil {
+		arg.LowStockThreshold = sql.NullInt32{
+			Int32: *reqBody.LowStockThreshold,
+			Valid: true,
+		}
+	}
 	// TODO: Add if section for Tags
 	// if reqBody.Tags != nil {
 	// 	arg.Tags = sql.NullString{
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/invoice"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/rs/zerolog/log"
+)
+
+// buyerInvoice is an invoice with the name of the store that issued it.
+type buyerInvoice struct {
+	db.Invoice
+	StoreName string `json:"store_name"`
+}
+
+// writeInvoicePDF renders an invoice and sends it as a PDF download.
+func (s *StoreHub) writeInvoicePDF(w http.ResponseWriter, r *http.Request, inv db.Invoice) {
+	doc, err := s.dbStore.InvoiceDocument(r.Context(), inv)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to render invoice")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	pdf, err := invoice.Render(doc)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to render invoice")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Filename()))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(pdf); err != nil {
+		log.Error().Err(err).Msg("error occurred")
+	}
+}
+
+type listBuyerInvoicesPathVars struct {
+	UserID int64 `path:"user_id" validate:"required,min=1"`
+}
+
+type listBuyerInvoicesQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=20"`
+}
+
+// listBuyerInvoices maps to endpoint "GET /users/{user_id}/invoices"
+func (s *StoreHub) listBuyerInvoices(w http.ResponseWriter, r *http.Request) {
+	var pathVars listBuyerInvoicesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listBuyerInvoicesQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+	if pathVars.UserID != authPayload.UserID {
+		s.errorResponse(w, r, http.StatusForbidden, "can't view another user's invoices")
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 10
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListBuyerInvoices(r.Context(), db.ListBuyerInvoicesParams{
+		BuyerID:  authPayload.UserID,
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list invoices")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	invoices := make([]buyerInvoice, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		invoices[i] = buyerInvoice{
+			Invoice: db.Invoice{
+				ID:             row.ID,
+				StoreID:        row.StoreID,
+				SequenceNumber: row.SequenceNumber,
+				InvoiceNumber:  row.InvoiceNumber,
+				Reference:      row.Reference,
+				BuyerID:        row.BuyerID,
+				Currency:       row.Currency,
+				Subtotal:       row.Subtotal,
+				Discount:       row.Discount,
+				DeliveryFee:    row.DeliveryFee,
+				Tax:            row.Tax,
+				Total:          row.Total,
+				EmailedAt:      row.EmailedAt,
+				CreatedAt:      row.CreatedAt,
+			},
+			StoreName: row.StoreName,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some invoices",
+			"result": envelop{
+				"invoices": invoices,
+				"metadata": pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type getBuyerInvoicePDFPathVars struct {
+	UserID    int64 `path:"user_id" validate:"required,min=1"`
+	InvoiceID int64 `path:"invoice_id" validate:"required,min=1"`
+}
+
+// getBuyerInvoicePDF maps to endpoint "GET /users/{user_id}/invoices/{invoice_id}/pdf"
+func (s *StoreHub) getBuyerInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	var pathVars getBuyerInvoicePDFPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+	if pathVars.UserID != authPayload.UserID {
+		s.errorResponse(w, r, http.StatusForbidden, "can't view another user's invoices")
+		return
+	}
+
+	inv, err := s.dbStore.GetBuyerInvoice(r.Context(), db.GetBuyerInvoiceParams{
+		InvoiceID: pathVars.InvoiceID,
+		BuyerID:   authPayload.UserID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "invoice not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve invoice")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeInvoicePDF(w, r, inv)
+}
+
+type listStoreInvoicesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listStoreInvoicesQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=50"`
+}
+
+// listStoreInvoices maps to endpoint "GET /inventory/stores/{store_id}/invoices"
+func (s *StoreHub) listStoreInvoices(w http.ResponseWriter, r *http.Request) {
+	var pathVars listStoreInvoicesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listStoreInvoicesQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 15
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListStoreInvoices(r.Context(), db.ListStoreInvoicesParams{
+		StoreID:  pathVars.StoreID,
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list invoices")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	invoices := make([]db.Invoice, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		invoices[i] = db.Invoice{
+			ID:             row.ID,
+			StoreID:        row.StoreID,
+			SequenceNumber: row.SequenceNumber,
+			InvoiceNumber:  row.InvoiceNumber,
+			Reference:      row.Reference,
+			BuyerID:        row.BuyerID,
+			Currency:       row.Currency,
+			Subtotal:       row.Subtotal,
+			Discount:       row.Discount,
+			DeliveryFee:    row.DeliveryFee,
+			Tax:            row.Tax,
+			Total:          row.Total,
+			EmailedAt:      row.EmailedAt,
+			CreatedAt:      row.CreatedAt,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some invoices",
+			"result": envelop{
+				"invoices": invoices,
+				"metadata": pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type getStoreInvoicePDFPathVars struct {
+	StoreID   int64 `path:"store_id" validate:"required,min=1"`
+	InvoiceID int64 `path:"invoice_id" validate:"required,min=1"`
+}
+
+// getStoreInvoicePDF maps to endpoint "GET /inventory/stores/{store_id}/invoices/{invoice_id}/pdf"
+func (s *StoreHub) getStoreInvoicePDF(w http.ResponseWriter, r *http.Request) {
+	var pathVars getStoreInvoicePDFPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	inv, err := s.dbStore.GetStoreInvoice(r.Context(), db.GetStoreInvoiceParams{
+		InvoiceID: pathVars.InvoiceID,
+		StoreID:   pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "invoice not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve invoice")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeInvoicePDF(w, r, inv)
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/itemcsv"
+	"github.com/OCD-Labs/store-hub/worker"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxImportFileSize caps the size of an uploaded CSV of items.
+	maxImportFileSize = 10 << 20
+
+	// maxInlineImportRows is the most rows an import has to be imported
+	// during its upload; larger imports are left to a worker.
+	maxInlineImportRows = 100
+
+	// exportPageSize is how many items are read at a time for an export.
+	exportPageSize = 500
+)
+
+type exportStoreItemsPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// exportStoreItems maps to endpoint "GET /inventory/stores/{store_id}/items/export"
+func (s *StoreHub) exportStoreItems(w http.ResponseWriter, r *http.Request) {
+	var pathVars exportStoreItemsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	arg := db.ListStoreItemsAfterParams{
+		StoreID: pathVars.StoreID,
+		RwLimit: exportPageSize,
+	}
+	items, err := s.dbStore.ListStoreItemsAfter(r.Context(), arg)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to export items")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	// The items are streamed a page at a time, so a failure after the first
+	// can only cut the file short.
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("store-%d-items.csv", pathVars.StoreID)))
+	w.WriteHeader(http.StatusOK)
+
+	csvWriter := itemcsv.NewWriter(w)
+	for {
+		for _, item := range items {
+			err := csvWriter.Write(itemcsv.Row{
+				SKU:                item.Sku,
+				Name:               item.Name,
+				Description:        item.Description,
+				Price:              item.Price,
+				Currency:           item.Currency,
+				DiscountPercentage: item.DiscountPercentage,
+				Category:           item.Category,
+				SupplyQuantity:     item.SupplyQuantity,
+				Status:             item.Status,
+				WeightGrams:        item.WeightGrams,
+				CoverImgURL:        item.CoverImgUrl,
+				ImageURLs:          item.ImageUrls,
+			})
+			if err != nil {
+				log.Error().Err(err).Msg("error occurred")
+				return
+			}
+		}
+
+		if err := csvWriter.Flush(); err != nil {
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if len(items) < exportPageSize {
+			return
+		}
+
+		arg.AfterID = items[len(items)-1].ID
+		items, err = s.dbStore.ListStoreItemsAfter(r.Context(), arg)
+		if err != nil {
+			log.Error().Err(err).Int64("store_id", pathVars.StoreID).Msg("failed to export items")
+			return
+		}
+	}
+}
+
+type importStoreItemsPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// importStoreItems maps to endpoint "POST /inventory/stores/{store_id}/item-imports"
+func (s *StoreHub) importStoreItems(w http.ResponseWriter, r *http.Request) {
+	var pathVars importStoreItemsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.errorResponse(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("the file must not be larger than %d bytes", maxImportFileSize))
+		} else {
+			s.errorResponse(w, r, http.StatusBadRequest, "a CSV file is required in the file field")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusBadRequest, "failed to read file")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	// A file that isn't a CSV of items is rejected outright; rows that
+	// aren't valid items are reported once it's imported.
+	rows, rowErrs, err := itemcsv.Read(bytes.NewReader(content))
+	if err != nil {
+		s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRows := len(rows) + len(rowErrs)
+	if totalRows == 0 {
+		s.errorResponse(w, r, http.StatusUnprocessableEntity, "the file has no rows")
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	itemImport, err := s.dbStore.CreateItemImportTx(r.Context(), db.CreateItemImportTxParams{
+		StoreID:    pathVars.StoreID,
+		UploadedBy: authPayload.UserID,
+		Filename:   filepath.Base(header.Filename),
+		TotalRows:  int32(totalRows),
+		Content:    content,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to import items")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	// small files are imported straight away
+	if totalRows <= maxInlineImportRows {
+		itemImport, err = s.dbStore.ImportItemsTx(r.Context(), itemImport.ID)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to import items")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.writeJSON(w, http.StatusCreated, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "imported items",
+				"result": envelop{
+					"import": itemImport,
+				},
+			},
+		}, nil)
+		return
+	}
+
+	err = s.taskDistributor.DistributeTaskImportItems(r.Context(), &worker.PayloadImportItems{
+		ImportID: itemImport.ID,
+	}, asynq.MaxRetry(3), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to import items")
+		log.Error().Err(err).Int64("import_id", itemImport.ID).Msg("failed to enqueue item import")
+		return
+	}
+
+	s.writeJSON(w, http.StatusAccepted, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "importing items",
+			"result": envelop{
+				"import": itemImport,
+			},
+		},
+	}, nil)
+}
+
+type getItemImportPathVars struct {
+	StoreID  int64 `path:"store_id" validate:"required,min=1"`
+	ImportID int64 `path:"import_id" validate:"required,min=1"`
+}
+
+// getItemImport maps to endpoint "GET /inventory/stores/{store_id}/item-imports/{import_id}"
+func (s *StoreHub) getItemImport(w http.ResponseWriter, r *http.Request) {
+	var pathVars getItemImportPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	itemImport, err := s.dbStore.GetItemImport(r.Context(), db.GetItemImportParams{
+		ImportID: pathVars.ImportID,
+		StoreID:  pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "import not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve import")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found import",
+			"result": envelop{
+				"import": itemImport,
+			},
+		},
+	}, nil)
+}
+
+type getItemImportErrorsPathVars struct {
+	StoreID  int64 `path:"store_id" validate:"required,min=1"`
+	ImportID int64 `path:"import_id" validate:"required,min=1"`
+}
+
+// getItemImportErrors maps to endpoint "GET /inventory/stores/{store_id}/item-imports/{import_id}/errors"
+func (s *StoreHub) getItemImportErrors(w http.ResponseWriter, r *http.Request) {
+	var pathVars getItemImportErrorsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	itemImport, err := s.dbStore.GetItemImport(r.Context(), db.GetItemImportParams{
+		ImportID: pathVars.ImportID,
+		StoreID:  pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "import not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve import errors")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	importErrors, err := s.dbStore.ListItemImportErrors(r.Context(), itemImport.ID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve import errors")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	rowErrs := make([]itemcsv.RowError, len(importErrors))
+	for i, importError := range importErrors {
+		rowErrs[i] = itemcsv.RowError{
+			Line:    int(importError.Line),
+			SKU:     importError.Sku,
+			Message: importError.Message,
+		}
+	}
+
+	var report bytes.Buffer
+	if err := itemcsv.WriteErrors(&report, rowErrs); err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve import errors")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("item-import-%d-errors.csv", itemImport.ID)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(report.Bytes()); err != nil {
+		log.Error().Err(err).Msg("error occurred")
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/variant"
+)
+
+type itemVariantRequestBody struct {
+	SKU            string         `json:"sku" validate:"required,max=64"`
+	Options        variant.Values `json:"options" validate:"required"`
+	Price          *string        `json:"price"` // defaults to the item's
+	SupplyQuantity int64          `json:"supply_quantity" validate:"min=0"`
+	ImageURLs      []string       `json:"image_urls"`
+}
+
+// itemVariantsParams converts an item's options and variants from a request body.
+func itemVariantsParams(options []variant.Option, variants []itemVariantRequestBody) db.ItemVariantsParams {
+	arg := db.ItemVariantsParams{
+		Options:  options,
+		Variants: make([]db.ItemVariantParams, len(variants)),
+	}
+
+	for i, v := range variants {
+		arg.Variants[i] = db.ItemVariantParams{
+			SKU:            v.SKU,
+			Options:        v.Options,
+			SupplyQuantity: v.SupplyQuantity,
+			ImageUrls:      v.ImageURLs,
+		}
+		if v.Price != nil {
+			arg.Variants[i].Price = sql.NullString{
+				String: *v.Price,
+				Valid:  true,
+			}
+		}
+	}
+
+	return arg
+}
+
+// storefrontVariant is a variant of an item as buyers see it, at the price
+// it sells at and with the stock not held for checkouts.
+type storefrontVariant struct {
+	ID             int64           `json:"id"`
+	SKU            string          `json:"sku"`
+	Options        json.RawMessage `json:"options"`
+	Price          string          `json:"price"`
+	SupplyQuantity int64           `json:"supply_quantity"`
+	ImageURLs      []string        `json:"image_urls"`
+}
+
+// storefrontVariants fetches the variant matrix of item for buyers.
+func (s *StoreHub) storefrontVariants(ctx context.Context, item db.Item) ([]db.ItemOption, []storefrontVariant, error) {
+	options, err := s.dbStore.ListItemOptions(ctx, item.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variants, err := s.dbStore.ListItemVariants(ctx, item.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reservations, err := s.dbStore.ListReservedVariantStock(ctx, item.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserved := make(map[int64]int64, len(reservations))
+	for _, reservation := range reservations {
+		reserved[reservation.VariantID] = reservation.Reserved
+	}
+
+	matrix := make([]storefrontVariant, len(variants))
+	for i, v := range variants {
+		price := item.Price
+		if v.Price.Valid {
+			price = v.Price.String
+		}
+
+		matrix[i] = storefrontVariant{
+			ID:             v.ID,
+			SKU:            v.Sku,
+			Options:        v.Options,
+			Price:          price,
+			SupplyQuantity: v.SupplyQuantity - reserved[v.ID],
+			ImageURLs:      v.ImageUrls,
+		}
+	}
+
+	return options, matrix, nil
+}
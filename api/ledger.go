@@ -0,0 +1,127 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/rs/zerolog/log"
+)
+
+type listStoreLedgerEntriesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listStoreLedgerEntriesQueryStr struct {
+	Kind     string `querystr:"kind"`
+	Page     int    `querystr:"page" validate:"max=10000000"`
+	PageSize int    `querystr:"page_size" validate:"max=50"`
+}
+
+// listStoreLedgerEntries maps to endpoint "GET /inventory/stores/{store_id}/ledger"
+func (s *StoreHub) listStoreLedgerEntries(w http.ResponseWriter, r *http.Request) {
+	var pathVars listStoreLedgerEntriesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listStoreLedgerEntriesQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 20
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	entries, err := s.dbStore.ListStoreLedgerEntries(r.Context(), db.ListStoreLedgerEntriesParams{
+		StoreID: pathVars.StoreID,
+		Kind: sql.NullString{
+			String: reqQueryStr.Kind,
+			Valid:  reqQueryStr.Kind != "",
+		},
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list ledger entries")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	if len(entries) > 0 {
+		totalRecords = int(entries[0].TotalCount)
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some ledger entries",
+			"result": envelop{
+				"entries":  entries,
+				"metadata": pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+// getTrialBalance maps to endpoint "GET /admin/ledger/trial-balance"
+func (s *StoreHub) getTrialBalance(w http.ResponseWriter, r *http.Request) {
+	accounts, err := s.dbStore.GetTrialBalance(r.Context())
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to get trial balance")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	unbalanced, err := s.dbStore.ListUnbalancedJournalEntries(r.Context())
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to get trial balance")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	// debits and credits cancel out across all accounts when the books balance
+	total := money.Zero
+	for _, account := range accounts {
+		amount, err := money.Parse(account.Total)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to get trial balance")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+		total += amount
+	}
+
+	balanced := total == money.Zero && len(unbalanced) == 0
+	if !balanced {
+		log.Error().
+			Str("total", total.String()).
+			Int("unbalanced_entries", len(unbalanced)).
+			Msg("ledger does not balance")
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "got the trial balance",
+			"result": envelop{
+				"balanced":           balanced,
+				"total":              total.String(),
+				"accounts":           accounts,
+				"unbalanced_entries": unbalanced,
+			},
+		},
+	}, nil)
+}
@@ -1,16 +1,21 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/OCD-Labs/store-hub/cache"
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
 	"github.com/OCD-Labs/store-hub/token"
 	"github.com/julienschmidt/httprouter"
@@ -22,6 +27,17 @@ import (
 const (
 	authorizationHeaderKey  = "Authorization"
 	authorizationTypeBearer = "Bearer"
+	idempotencyKeyHeaderKey = "Idempotency-Key"
+)
+
+const (
+	// idempotencyKeyMaxLength is the longest Idempotency-Key accepted.
+	idempotencyKeyMaxLength = 255
+	// idempotencyClaimTTL is how long a key stays claimed by a request
+	// still being processed, in case the server dies before it responds.
+	idempotencyClaimTTL = time.Minute
+	// idempotencyResponseTTL is how long a response is replayed for.
+	idempotencyResponseTTL = 24 * time.Hour
 )
 
 var (
@@ -70,7 +86,7 @@ func (rec *ResponseRecorder) WriteHeader(statusCode int) {
 // Write capture the response body as it's being written
 // by the next handler
 func (rec *ResponseRecorder) Write(body []byte) (int, error) {
-	rec.Body = body
+	rec.Body = append(rec.Body, body...)
 	return rec.ResponseWriter.Write(body)
 }
 
@@ -157,6 +173,100 @@ func (s *StoreHub) authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// idempotent replays the stored response to a request retried with the same
+// 'Idempotency-Key' header, instead of running the handler again. Keys are
+// scoped to the user and endpoint; reusing one with a different body is
+// rejected. Requests without the header run as usual. Server errors are not
+// stored, so the request can be retried with the same key.
+func (s *StoreHub) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(idempotencyKeyHeaderKey)
+		if idempotencyKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(idempotencyKey) > idempotencyKeyMaxLength {
+			s.errorResponse(w, r, http.StatusBadRequest, fmt.Sprintf("%s must not be more than %d characters", idempotencyKeyHeaderKey, idempotencyKeyMaxLength))
+			return
+		}
+
+		// Restrict r.Body to 1MB, as shouldBindBody does
+		maxBytes := 1_048_578
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(maxBytes)))
+		if err != nil {
+			s.errorResponse(w, r, http.StatusBadRequest, fmt.Sprintf("body must not be larger than %d bytes", maxBytes))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var userID int64
+		if authPayload := s.contextGetToken(r); authPayload != nil {
+			userID = authPayload.UserID
+		}
+
+		key := fmt.Sprintf("%d:%s:%s:%s", userID, r.Method, r.URL.Path, idempotencyKey)
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		claimed, stored, err := s.cache.StartIdempotentRequest(r.Context(), key, fingerprint, idempotencyClaimTTL)
+		if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to check idempotency key")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		if !claimed {
+			switch {
+			case err != nil:
+				// the key expired between the claim and the read
+				s.errorResponse(w, r, http.StatusConflict, "retry the request")
+			case stored.Fingerprint != fingerprint:
+				s.errorResponse(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("%s was already used for a different request", idempotencyKeyHeaderKey))
+			case !stored.Completed:
+				s.errorResponse(w, r, http.StatusConflict, fmt.Sprintf("a request with this %s is still being processed", idempotencyKeyHeaderKey))
+			default:
+				for k, v := range stored.Header {
+					w.Header()[k] = v
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(stored.StatusCode)
+				w.Write(stored.Body)
+			}
+			return
+		}
+
+		rec := &ResponseRecorder{
+			ResponseWriter: w,
+			StatusCode:     http.StatusOK,
+		}
+
+		next.ServeHTTP(rec, r)
+
+		// The client may have gone away; record the outcome regardless.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if rec.StatusCode >= http.StatusInternalServerError {
+			if err := s.cache.ReleaseIdempotencyKey(ctx, key); err != nil {
+				log.Error().Err(err).Str("key", key).Msg("failed to release idempotency key")
+			}
+			return
+		}
+
+		err = s.cache.SaveIdempotentResponse(ctx, key, cache.IdempotentResponse{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			StatusCode:  rec.StatusCode,
+			Header:      w.Header().Clone(),
+			Body:        rec.Body,
+		}, idempotencyResponseTTL)
+		if err != nil {
+			log.Error().Err(err).Str("key", key).Msg("failed to store idempotent response")
+		}
+	})
+}
+
 // enableCORS enables cross-site requests for web user-agents.
 func (s *StoreHub) enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -173,7 +283,7 @@ func (s *StoreHub) enableCORS(next http.Handler) http.Handler {
 
 					if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
 						w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, PUT, PATCH, DELETE")
-						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+						w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Idempotency-Key")
 
 						w.WriteHeader(http.StatusOK)
 						return
@@ -260,6 +370,23 @@ func (s *StoreHub) CheckAccessLevel(requiredAccessLevels ...int32) func(http.Han
 	}
 }
 
+// requirePlatformAdmin lets through only the users configured as platform
+// admins, who manage marketplace-wide settings such as commission rules.
+func (s *StoreHub) requirePlatformAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authPayload := s.contextGetMustToken(r)
+
+		for _, accountID := range s.configs.PlatformAdmins {
+			if accountID == authPayload.AccountID {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		s.errorResponse(w, r, http.StatusForbidden, "only platform admins can do this")
+	})
+}
+
 func (s *StoreHub) rateLimit(next http.Handler) http.Handler {
 	type client struct {
 		lastSeen time.Time
@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,18 +10,21 @@ import (
 
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
 	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/OCD-Labs/store-hub/pricing"
 	"github.com/OCD-Labs/store-hub/util"
 	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
 type createOrderRequestBody struct {
-	ItemID         int64  `json:"item_id" validate:"required,min=1"`
-	OrderQuantity  int32  `json:"order_quantity" validate:"required,min=1"`
-	SellerID       int64  `json:"seller_id" validate:"required,min=1"`
-	DeliveryFee    string `json:"delivery_fee" validate:"required"`
-	PaymentChannel string `json:"payment_channel" validate:"required,oneof=NEAR 'Debit Card' PayPal 'Credit Card'"`
-	PaymentMethod  string `json:"payment_method" validate:"required,oneof='Instant Pay' 'Pay on Delivery'"`
+	ItemID          int64            `json:"item_id" validate:"required,min=1"`
+	VariantID       int64            `json:"variant_id" validate:"omitempty,min=1"` // required for an item with variants
+	OrderQuantity   int32            `json:"order_quantity" validate:"required,min=1"`
+	SellerID        int64            `json:"seller_id" validate:"required,min=1"`
+	PaymentChannel  string           `json:"payment_channel" validate:"required,oneof=NEAR 'Debit Card' PayPal 'Credit Card'"`
+	PaymentMethod   string           `json:"payment_method" validate:"required,oneof='Instant Pay' 'Pay on Delivery'"`
+	CouponCode      string           `json:"coupon_code" validate:"omitempty,max=50"`
+	ShippingAddress *shippingAddress `json:"shipping_address"`
 }
 
 type createOrderPathVars struct {
@@ -55,6 +59,26 @@ func (s *StoreHub) createOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a variant's own stock is what's ordered from
+	if reqBody.VariantID != 0 {
+		itemVariant, err := s.dbStore.GetItemVariant(r.Context(), db.GetItemVariantParams{
+			VariantID: reqBody.VariantID,
+			ItemID:    reqBody.ItemID,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				s.errorResponse(w, r, http.StatusNotFound, "item variant not found")
+				log.Error().Err(err).Msg("error occurred")
+				return
+			}
+
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to create order")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+		supply_quantity = itemVariant.SupplyQuantity
+	}
+
 	if supply_quantity < int64(reqBody.OrderQuantity) {
 		s.errorResponse(w, r, http.StatusForbidden, "item is out of stock")
 		err = fmt.Errorf("order quantity %d is greater than supply %d", reqBody.OrderQuantity, supply_quantity)
@@ -82,15 +106,47 @@ func (s *StoreHub) createOrder(w http.ResponseWriter, r *http.Request) {
 
 	authPayload := s.contextGetMustToken(r)
 
-	order, err := s.dbStore.CreateOrderFn(r.Context(), db.CreateOrderFnParams{
-		ItemID:         reqBody.ItemID,
-		OrderQuantity:  reqBody.OrderQuantity,
-		BuyerID:        authPayload.UserID,
-		SellerID:       reqBody.SellerID,
-		StoreID:        pathVar.StoreID,
-		DeliveryFee:    reqBody.DeliveryFee,
-		PaymentChannel: reqBody.PaymentChannel,
-		PaymentMethod:  reqBody.PaymentMethod,
+	var address json.RawMessage
+	if reqBody.ShippingAddress != nil {
+		address, err = json.Marshal(reqBody.ShippingAddress)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to create order")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+	}
+
+	item, err := s.dbStore.GetItem(r.Context(), reqBody.ItemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			s.errorResponse(w, r, http.StatusForbidden, "item not found")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to create order")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	result, err := s.dbStore.CreateOrderTx(r.Context(), db.CreateOrderTxParams{
+		Line: pricing.Line{
+			ItemID:             item.ID,
+			VariantID:          reqBody.VariantID,
+			StoreID:            item.StoreID,
+			Price:              item.Price,
+			Currency:           item.Currency,
+			DiscountPercentage: item.DiscountPercentage,
+			Category:           item.Category,
+			Quantity:           reqBody.OrderQuantity,
+			WeightGrams:        item.WeightGrams,
+		},
+		CouponCode:      reqBody.CouponCode,
+		BuyerID:         authPayload.UserID,
+		SellerID:        reqBody.SellerID,
+		PaymentChannel:  reqBody.PaymentChannel,
+		PaymentMethod:   reqBody.PaymentMethod,
+		ShippingAddress: address,
 	})
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -100,6 +156,10 @@ func (s *StoreHub) createOrder(w http.ResponseWriter, r *http.Request) {
 			default:
 				s.errorResponse(w, r, http.StatusInternalServerError, "failed to create order")
 			}
+		} else if db.IsCouponRejected(err) || errors.Is(err, db.ErrVariantRequired) {
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		} else if errors.Is(err, db.ErrVariantNotFound) {
+			s.errorResponse(w, r, http.StatusNotFound, "item variant not found")
 		} else {
 			s.errorResponse(w, r, http.StatusInternalServerError, "failed to create order")
 		}
@@ -112,7 +172,9 @@ func (s *StoreHub) createOrder(w http.ResponseWriter, r *http.Request) {
 		"data": envelop{
 			"message": "created a new order",
 			"result": envelop{
-				"order": order,
+				"order":       result.Order,
+				"order_group": result.OrderGroup,
+				"breakdown":   result.Breakdown,
 			},
 		},
 	}, nil)
@@ -170,7 +232,7 @@ func (s *StoreHub) listSellerOrders(w http.ResponseWriter, r *http.Request) {
 			Page:         reqQueryStr.Page,
 			PageSize:     reqQueryStr.PageSize,
 			Sort:         reqQueryStr.Sort,
-			SortSafelist: []string{"-id", "-item_name", "-created_at", "order_id", "item_name", "created_at", "id"},
+			SortSafelist: []string{"-id", "-created_at", "-total", "id", "created_at", "total"},
 		},
 	}
 	orders, pagination, err := s.dbStore.ListSellerOrders(r.Context(), arg)
@@ -223,13 +285,26 @@ func (s *StoreHub) getSellerOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the whole shipment the order is a line of
+	shipment, err := s.sellerShipment(r.Context(), db.GetSellerFulfilmentGroupParams{
+		FulfilmentGroupID: order.FulfilmentGroupID.Int64,
+		StoreID:           pathVar.StoreID,
+		SellerID:          authPayload.UserID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve order details")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
 	// return response
 	s.writeJSON(w, http.StatusOK, envelop{
 		"status": "success",
 		"data": envelop{
 			"message": "found an order",
 			"result": envelop{
-				"order": order,
+				"order":    order,
+				"shipment": shipment,
 			},
 		},
 	}, nil)
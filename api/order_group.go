@@ -0,0 +1,209 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/rs/zerolog/log"
+)
+
+// shippingAddress is where an order group is delivered to.
+type shippingAddress struct {
+	RecipientName string `json:"recipient_name" validate:"required,max=100"`
+	PhoneNumber   string `json:"phone_number" validate:"required,max=20"`
+	AddressLine1  string `json:"address_line1" validate:"required,max=200"`
+	AddressLine2  string `json:"address_line2" validate:"max=200"`
+	City          string `json:"city" validate:"required,max=100"`
+	State         string `json:"state" validate:"required,max=100"`
+	PostalCode    string `json:"postal_code" validate:"max=20"`
+	Country       string `json:"country" validate:"required,iso3166_1_alpha2"`
+}
+
+// buyerOrderGroup is an order group with what each store ships of it.
+type buyerOrderGroup struct {
+	db.OrderGroup
+	Shipments []buyerShipment `json:"shipments"`
+}
+
+type buyerShipment struct {
+	db.FulfilmentGroup
+	Lines []db.ListFulfilmentGroupLinesRow `json:"lines"`
+}
+
+// withShipments attaches the shipments of each order group, with their lines.
+func (s *StoreHub) withShipments(r *http.Request, orderGroups []db.OrderGroup) ([]buyerOrderGroup, error) {
+	results := make([]buyerOrderGroup, len(orderGroups))
+	shipments := make(map[int64]*buyerShipment)
+	var shipmentIDs []int64
+	for i, og := range orderGroups {
+		fgs, err := s.dbStore.ListOrderGroupFulfilmentGroups(r.Context(), og.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = buyerOrderGroup{OrderGroup: og, Shipments: make([]buyerShipment, len(fgs))}
+		for j, fg := range fgs {
+			results[i].Shipments[j] = buyerShipment{FulfilmentGroup: fg, Lines: []db.ListFulfilmentGroupLinesRow{}}
+			shipments[fg.ID] = &results[i].Shipments[j]
+			shipmentIDs = append(shipmentIDs, fg.ID)
+		}
+	}
+
+	lines, err := s.dbStore.ListFulfilmentGroupLines(r.Context(), shipmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		if shipment, ok := shipments[line.FulfilmentGroupID.Int64]; ok {
+			shipment.Lines = append(shipment.Lines, line)
+		}
+	}
+
+	return results, nil
+}
+
+type listBuyerOrderGroupsPathVars struct {
+	UserID int64 `path:"user_id" validate:"required,min=1"`
+}
+
+type listBuyerOrderGroupsQueryStr struct {
+	Page     int `querystr:"page" validate:"omitempty,min=1,max=10000000"`
+	PageSize int `querystr:"page_size" validate:"omitempty,min=1,max=20"`
+}
+
+// listBuyerOrderGroups maps to endpoint "GET /users/{user_id}/orders"
+func (s *StoreHub) listBuyerOrderGroups(w http.ResponseWriter, r *http.Request) {
+	var pathVars listBuyerOrderGroupsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listBuyerOrderGroupsQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+	if pathVars.UserID != authPayload.UserID {
+		s.errorResponse(w, r, http.StatusForbidden, "can't view another user's orders")
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 10
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListBuyerOrderGroups(r.Context(), db.ListBuyerOrderGroupsParams{
+		BuyerID:  authPayload.UserID,
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list orders")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	orderGroups := make([]db.OrderGroup, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		orderGroups[i] = db.OrderGroup{
+			ID:              row.ID,
+			OrderNumber:     row.OrderNumber,
+			BuyerID:         row.BuyerID,
+			Reference:       row.Reference,
+			Status:          row.Status,
+			ShippingAddress: row.ShippingAddress,
+			Currency:        row.Currency,
+			Subtotal:        row.Subtotal,
+			DiscountTotal:   row.DiscountTotal,
+			DeliveryTotal:   row.DeliveryTotal,
+			TaxTotal:        row.TaxTotal,
+			GrandTotal:      row.GrandTotal,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		}
+	}
+
+	results, err := s.withShipments(r, orderGroups)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list orders")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some orders",
+			"result": envelop{
+				"orders":   results,
+				"metadata": pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type getBuyerOrderGroupPathVars struct {
+	UserID      int64  `path:"user_id" validate:"required,min=1"`
+	OrderNumber string `path:"order_number" validate:"required,max=50"`
+}
+
+// getBuyerOrderGroup maps to endpoint "GET /users/{user_id}/orders/{order_number}"
+func (s *StoreHub) getBuyerOrderGroup(w http.ResponseWriter, r *http.Request) {
+	var pathVars getBuyerOrderGroupPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+	if pathVars.UserID != authPayload.UserID {
+		s.errorResponse(w, r, http.StatusForbidden, "can't view another user's orders")
+		return
+	}
+
+	orderGroup, err := s.dbStore.GetBuyerOrderGroup(r.Context(), db.GetBuyerOrderGroupParams{
+		OrderNumber: pathVars.OrderNumber,
+		BuyerID:     authPayload.UserID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "order not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve order")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	results, err := s.withShipments(r, []db.OrderGroup{orderGroup})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve order")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found an order",
+			"result": envelop{
+				"order": results[0],
+			},
+		},
+	}, nil)
+}
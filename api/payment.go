@@ -0,0 +1,849 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/cache"
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/near"
+	"github.com/OCD-Labs/store-hub/payment"
+	"github.com/OCD-Labs/store-hub/pricing"
+	"github.com/OCD-Labs/store-hub/worker"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxWebhookBytes restricts the size of a payment provider's webhook body.
+	maxWebhookBytes = 1_048_576
+
+	// defaultStockReservationTTL is how long checkout holds a cart's stock when
+	// STOCK_RESERVATION_TTL isn't set.
+	defaultStockReservationTTL = 15 * time.Minute
+)
+
+type checkoutRequestBody struct {
+	PaymentProvider string          `json:"payment_provider" validate:"required,oneof=PAYSTACK NEAR_WALLET"`
+	ShippingAddress shippingAddress `json:"shipping_address"`
+	GiftCardCodes   []string        `json:"gift_card_codes" validate:"omitempty,max=5,dive,required,max=30"`
+	UseStoreCredit  bool            `json:"use_store_credit"`
+}
+
+// checkout maps to endpoint "POST /checkout"
+func (s *StoreHub) checkout(w http.ResponseWriter, r *http.Request) {
+	var reqBody checkoutRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	user, err := s.dbStore.GetUserByID(r.Context(), authPayload.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "user not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch user's profile")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	address, err := json.Marshal(reqBody.ShippingAddress)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to initialize checkout")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	reference := uuid.NewString()
+
+	ttl := s.configs.StockReservationTTL
+	if ttl <= 0 {
+		ttl = defaultStockReservationTTL
+	}
+	reservedUntil := time.Now().Add(ttl)
+
+	// Flash sale units are claimed in Redis first; the claims recorded with the
+	// checkout hold the stock of their lines.
+	flashSaleClaims, err := s.claimFlashSales(r.Context(), authPayload.UserID, reference)
+	if err != nil {
+		switch {
+		case errors.Is(err, cache.ErrFlashSaleSoldOut):
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		case errors.Is(err, cache.ErrFlashSaleLimitReached):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to claim flash sale items")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	claimed := make(map[int64]int32, len(flashSaleClaims))
+	for _, claim := range flashSaleClaims {
+		claimed[claim.FlashSaleID] = int32(claim.Units)
+	}
+
+	quote, err := s.dbStore.PrepareCheckoutTx(r.Context(), db.PrepareCheckoutTxParams{
+		UserID:          authPayload.UserID,
+		Reference:       reference,
+		ShippingAddress: address,
+		ReservedUntil:   reservedUntil,
+		GiftCardCodes:   reqBody.GiftCardCodes,
+		UseStoreCredit:  reqBody.UseStoreCredit,
+		FlashSaleClaims: claimed,
+	})
+	if err != nil {
+		// Nothing recorded the claims, so they go straight back to the sales.
+		s.releaseFlashSaleClaims(flashSaleClaims)
+
+		switch {
+		case db.IsCouponRejected(err), db.IsGiftCardRejected(err):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, db.ErrInsufficientStock), errors.Is(err, db.ErrItemNotFound),
+			errors.Is(err, db.ErrFlashSaleNotClaimed):
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to price cart")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if len(quote.Cart) == 0 {
+		s.errorResponse(w, r, http.StatusBadRequest, "cart is empty")
+		return
+	}
+
+	// The reservations stop holding stock at reservedUntil regardless; the task
+	// only marks them EXPIRED, so failing to enqueue it isn't fatal.
+	err = s.taskDistributor.DistributeTaskExpireStockReservations(r.Context(), &worker.PayloadExpireStockReservations{
+		Reference: reference,
+	}, asynq.ProcessAt(reservedUntil), asynq.MaxRetry(5), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Error().Err(err).Str("reference", reference).Msg("failed to schedule stock reservations expiry")
+	}
+
+	// gift cards and store credit pay what they can; the provider is charged the rest
+	total := quote.Credit.Due
+
+	result := envelop{
+		"breakdown":      quote.Breakdown,
+		"credit":         quote.Credit,
+		"order_group":    quote.OrderGroup,
+		"reference":      reference,
+		"reserved_until": reservedUntil,
+	}
+
+	arg := db.CreateTransactionParams{
+		CustomerID:      user.ID,
+		Amount:          total.String(),
+		PaymentProvider: reqBody.PaymentProvider,
+		ProviderTxRefID: reference,
+	}
+
+	if total == money.Zero {
+		s.checkoutWithCredit(w, r, arg, result)
+		return
+	}
+
+	switch reqBody.PaymentProvider {
+	case payment.ProviderPaystack:
+		initResult, err := s.paymentProvider.InitializeTransaction(r.Context(), payment.InitializeTransactionParams{
+			Email:       user.Email,
+			Amount:      total.Minor(),
+			Reference:   reference,
+			CallbackURL: s.configs.PaystackCallbackURL,
+			Metadata: map[string]interface{}{
+				"user_id": user.ID,
+				"cart_id": quote.CartID,
+			},
+		})
+		if err != nil {
+			s.errorResponse(w, r, http.StatusBadGateway, "failed to initialize payment")
+			log.Error().Err(err).Msg("error occurred")
+			s.abandonCheckout(reference)
+			return
+		}
+
+		arg.ProviderTxAccessCode = initResult.AccessCode
+		result["authorization_url"] = initResult.AuthorizationURL
+		result["access_code"] = initResult.AccessCode
+	case payment.ProviderNEARWallet:
+		yocto, err := s.nearAmount(total)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to initialize payment")
+			log.Error().Err(err).Msg("error occurred")
+			s.abandonCheckout(reference)
+			return
+		}
+
+		arg.NearAmount = sql.NullString{String: yocto.String(), Valid: true}
+
+		result["near_payment"] = envelop{
+			"receiver_id":  s.configs.NEARAccountID,
+			"amount_yocto": yocto.String(),
+			"amount":       near.FormatNEAR(yocto),
+		}
+	}
+
+	transaction, err := s.dbStore.CreateTransaction(r.Context(), arg)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to create transaction")
+		log.Error().Err(err).Msg("error occurred")
+		s.abandonCheckout(reference)
+		return
+	}
+	result["transaction"] = transaction
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "initialized checkout",
+			"result":  result,
+		},
+	}, nil)
+}
+
+type getCheckoutQuoteQueryStr struct {
+	Currency string `querystr:"currency" validate:"omitempty,iso4217"`
+	Country  string `querystr:"country" validate:"omitempty,iso3166_1_alpha2"`
+	State    string `querystr:"state" validate:"omitempty,max=100"`
+	City     string `querystr:"city" validate:"omitempty,max=100"`
+}
+
+// checkoutWithCredit completes a checkout that gift cards and store credit paid
+// for in full, with nothing for the provider to charge.
+func (s *StoreHub) checkoutWithCredit(w http.ResponseWriter, r *http.Request, arg db.CreateTransactionParams, result envelop) {
+	_, err := s.dbStore.CreateTransaction(r.Context(), arg)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to create transaction")
+		log.Error().Err(err).Msg("error occurred")
+		s.abandonCheckout(arg.ProviderTxRefID)
+		return
+	}
+
+	fee := money.Zero.String()
+
+	checkout, err := s.dbStore.CheckoutCartTx(r.Context(), db.CheckoutCartTxParams{
+		UserID:          arg.CustomerID,
+		ProviderTxRefID: arg.ProviderTxRefID,
+		ProviderTxFee:   fee,
+	})
+	if err != nil {
+		if !db.IsUnfulfillableCart(err) {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		log.Error().Err(err).Str("reference", arg.ProviderTxRefID).Msg("failed to checkout cart")
+
+		if err := s.failTransaction(r.Context(), arg.ProviderTxRefID, fee); err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.errorResponse(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	s.sendInvoices(r.Context(), arg.ProviderTxRefID)
+
+	result["transaction"] = checkout.Transaction
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "completed checkout",
+			"result":  result,
+		},
+	}, nil)
+}
+
+// getCheckoutQuote maps to endpoint "GET /checkout/quote"
+func (s *StoreHub) getCheckoutQuote(w http.ResponseWriter, r *http.Request) {
+	var reqQueryStr getCheckoutQuoteQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	// delivery is charged by shipping zone, and the cart taxed, once it's known where it ships to
+	quote, err := s.dbStore.QuoteCartTx(r.Context(), db.QuoteCartTxParams{
+		UserID: authPayload.UserID,
+		Region: pricing.Region{
+			Country: reqQueryStr.Country,
+			State:   reqQueryStr.State,
+			City:    reqQueryStr.City,
+		},
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to price cart")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	result := envelop{
+		"cart":      quote.Cart,
+		"breakdown": quote.Breakdown,
+	}
+
+	if reqQueryStr.Currency != "" {
+		display, err := s.displayBreakdown(r.Context(), quote.Breakdown, reqQueryStr.Currency)
+		if err != nil {
+			switch {
+			case errors.Is(err, errNoFxRate):
+				s.errorResponse(w, r, http.StatusUnprocessableEntity, "currency is not supported")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to convert cart totals")
+			}
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+		result["display_breakdown"] = display
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "priced cart",
+			"result":  result,
+		},
+	}, nil)
+}
+
+// paystackWebhook maps to endpoint "POST /payments/paystack/webhook"
+func (s *StoreHub) paystackWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		s.errorResponse(w, r, http.StatusBadRequest, "failed to read request body")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if !s.paymentProvider.ValidateWebhookSignature(body, r.Header.Get(payment.PaystackSignatureHeader)) {
+		s.errorResponse(w, r, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	event, err := payment.ParsePaystackWebhookEvent(body)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusBadRequest, "badly-formatted event")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if event.Event != payment.PaystackEventChargeSuccess {
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "event ignored",
+			},
+		}, nil)
+		return
+	}
+
+	transaction, err := s.dbStore.GetTransactionByRefID(r.Context(), event.Data.Reference)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// Acknowledge it, so Paystack stops retrying a reference we never issued.
+			s.writeJSON(w, http.StatusOK, envelop{
+				"status": "success",
+				"data": envelop{
+					"message": "unknown transaction",
+				},
+			}, nil)
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch transaction")
+		}
+		log.Error().Err(err).Str("reference", event.Data.Reference).Msg("error occurred")
+		return
+	}
+
+	if transaction.Status == "COMPLETED" {
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "transaction already completed",
+			},
+		}, nil)
+		return
+	}
+
+	amount, err := money.Parse(transaction.Amount)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	fee := money.FromMinor(event.Data.Fees).String()
+
+	if event.Data.Amount != amount.Minor() {
+		log.Error().
+			Str("reference", transaction.ProviderTxRefID).
+			Int64("paid", event.Data.Amount).
+			Int64("expected", amount.Minor()).
+			Msg("paid amount does not match transaction amount")
+
+		// The checkout can't complete for a different amount, so whatever was paid is refunded.
+		paid := money.FromMinor(event.Data.Amount)
+		err = worker.RefundFailedCheckout(r.Context(), s.dbStore, s.taskDistributor, db.FailPaidCheckoutTxParams{
+			ProviderTxRefID: transaction.ProviderTxRefID,
+			ProviderTxFee:   fee,
+			Reason:          fmt.Sprintf("paid %s, expected %s", paid, amount),
+			Amount:          paid.String(),
+		})
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "transaction failed",
+			},
+		}, nil)
+		return
+	}
+
+	isGiftCard, err := s.dbStore.IsGiftCardPurchase(r.Context(), transaction.ProviderTxRefID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if isGiftCard {
+		_, err = s.dbStore.CompleteGiftCardPurchaseTx(r.Context(), db.CompleteGiftCardPurchaseTxParams{
+			ProviderTxRefID: transaction.ProviderTxRefID,
+			ProviderTxFee:   fee,
+		})
+		if err != nil {
+			if !errors.Is(err, db.ErrTransactionFailed) {
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+				log.Error().Err(err).Msg("error occurred")
+				return
+			}
+
+			// Paid after the purchase was failed, so the buyer is refunded.
+			log.Error().Err(err).Str("reference", transaction.ProviderTxRefID).Msg("failed to complete gift card purchase")
+
+			err = s.refundFailedCheckout(r.Context(), transaction, fee, err)
+			if err != nil {
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+				log.Error().Err(err).Msg("error occurred")
+				return
+			}
+
+			s.writeJSON(w, http.StatusOK, envelop{
+				"status": "success",
+				"data": envelop{
+					"message": "transaction failed",
+				},
+			}, nil)
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "transaction completed",
+			},
+		}, nil)
+		return
+	}
+
+	_, err = s.dbStore.CheckoutCartTx(r.Context(), db.CheckoutCartTxParams{
+		UserID:          transaction.CustomerID,
+		ProviderTxRefID: transaction.ProviderTxRefID,
+		ProviderTxFee:   fee,
+	})
+	if err != nil {
+		if !db.IsUnfulfillableCart(err) {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		// The cart can no longer be fulfilled, retrying won't change that.
+		log.Error().Err(err).Str("reference", transaction.ProviderTxRefID).Msg("failed to checkout cart")
+
+		err = s.refundFailedCheckout(r.Context(), transaction, fee, err)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "transaction failed",
+			},
+		}, nil)
+		return
+	}
+
+	s.sendInvoices(r.Context(), transaction.ProviderTxRefID)
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "transaction completed",
+		},
+	}, nil)
+}
+
+// sendInvoices has the invoices of a completed transaction emailed. The invoices
+// exist whether or not they're emailed, so failing to enqueue the task isn't fatal.
+func (s *StoreHub) sendInvoices(ctx context.Context, reference string) {
+	err := s.taskDistributor.DistributeTaskSendInvoices(ctx, &worker.PayloadSendInvoices{
+		Reference: reference,
+	}, asynq.MaxRetry(5), asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Error().Err(err).Str("reference", reference).Msg("failed to enqueue invoices")
+	}
+}
+
+type verifyNEARPaymentRequestBody struct {
+	Reference string `json:"reference" validate:"required"`
+	TxHash    string `json:"tx_hash" validate:"required"`
+}
+
+// verifyNEARPayment maps to endpoint "POST /payments/near/verify"
+func (s *StoreHub) verifyNEARPayment(w http.ResponseWriter, r *http.Request) {
+	var reqBody verifyNEARPaymentRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	user, err := s.dbStore.GetUserByID(r.Context(), authPayload.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "user not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch user's profile")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	transaction, err := s.dbStore.GetTransactionByRefID(r.Context(), reqBody.Reference)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "transaction not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch transaction")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if transaction.CustomerID != user.ID {
+		s.errorResponse(w, r, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	if transaction.PaymentProvider != payment.ProviderNEARWallet {
+		s.errorResponse(w, r, http.StatusBadRequest, "transaction is not a NEAR wallet payment")
+		return
+	}
+
+	if transaction.ProviderTxHash.Valid && transaction.ProviderTxHash.String != reqBody.TxHash {
+		s.errorResponse(w, r, http.StatusConflict, "transaction was paid with another tx_hash")
+		return
+	}
+
+	switch transaction.Status {
+	case "COMPLETED":
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "transaction already completed",
+				"result": envelop{
+					"transaction": transaction,
+				},
+			},
+		}, nil)
+		return
+	}
+
+	// A FAILED transaction is still verified: if the buyer paid it after it
+	// failed, they're refunded below instead of completing it.
+	txStatus, err := s.nearRPC.TxStatus(r.Context(), reqBody.TxHash, user.AccountID)
+	if err != nil {
+		switch {
+		case errors.Is(err, near.ErrUnknownTransaction):
+			s.errorResponse(w, r, http.StatusNotFound, "tx_hash not found on chain")
+		default:
+			s.errorResponse(w, r, http.StatusBadGateway, "failed to look up tx_hash")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if !txStatus.IsSuccess() {
+		s.errorResponse(w, r, http.StatusUnprocessableEntity, "on-chain transaction did not succeed")
+		return
+	}
+
+	if txStatus.Transaction.SignerID != user.AccountID {
+		s.errorResponse(w, r, http.StatusForbidden, "transaction was not signed by the buyer")
+		return
+	}
+
+	if txStatus.Transaction.ReceiverID != s.configs.NEARAccountID {
+		s.errorResponse(w, r, http.StatusUnprocessableEntity, "transaction was not sent to the store hub account")
+		return
+	}
+
+	amount, err := money.Parse(transaction.Amount)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	expected, err := s.lockedNEARAmount(transaction, amount)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	deposit, err := txStatus.Deposit()
+	if err != nil {
+		s.errorResponse(w, r, http.StatusUnprocessableEntity, "failed to read transferred amount")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if deposit.Cmp(expected) < 0 {
+		s.errorResponse(w, r, http.StatusUnprocessableEntity, fmt.Sprintf("transferred %s NEAR, expected %s NEAR", near.FormatNEAR(deposit), near.FormatNEAR(expected)))
+		return
+	}
+
+	if !transaction.ProviderTxHash.Valid {
+		_, err = s.dbStore.SetTransactionProviderTxHash(r.Context(), db.SetTransactionProviderTxHashParams{
+			ProviderTxHash:  sql.NullString{String: reqBody.TxHash, Valid: true},
+			ProviderTxRefID: transaction.ProviderTxRefID,
+		})
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+				s.errorResponse(w, r, http.StatusConflict, "tx_hash has already been used")
+			} else if errors.Is(err, sql.ErrNoRows) {
+				s.errorResponse(w, r, http.StatusConflict, "transaction was paid with another tx_hash")
+			} else {
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			}
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+	}
+
+	fee := money.Zero.String()
+
+	isGiftCard, err := s.dbStore.IsGiftCardPurchase(r.Context(), transaction.ProviderTxRefID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if isGiftCard {
+		_, err = s.dbStore.CompleteGiftCardPurchaseTx(r.Context(), db.CompleteGiftCardPurchaseTxParams{
+			ProviderTxRefID: transaction.ProviderTxRefID,
+			ProviderTxFee:   fee,
+		})
+		if err != nil {
+			if !errors.Is(err, db.ErrTransactionFailed) {
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+				log.Error().Err(err).Msg("error occurred")
+				return
+			}
+
+			// Paid after the purchase was failed, so the buyer is refunded.
+			log.Error().Err(err).Str("reference", transaction.ProviderTxRefID).Msg("failed to complete gift card purchase")
+
+			if err := s.refundFailedCheckout(r.Context(), transaction, fee, err); err != nil {
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+				log.Error().Err(err).Msg("error occurred")
+				return
+			}
+
+			s.errorResponse(w, r, http.StatusConflict, "transaction has failed")
+			return
+		}
+
+		transaction, err = s.dbStore.GetTransactionByRefID(r.Context(), transaction.ProviderTxRefID)
+		if err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, envelop{
+			"status": "success",
+			"data": envelop{
+				"message": "transaction completed",
+				"result": envelop{
+					"transaction": transaction,
+				},
+			},
+		}, nil)
+		return
+	}
+
+	result, err := s.dbStore.CheckoutCartTx(r.Context(), db.CheckoutCartTxParams{
+		UserID:          user.ID,
+		ProviderTxRefID: transaction.ProviderTxRefID,
+		ProviderTxFee:   fee,
+	})
+	if err != nil {
+		if !db.IsUnfulfillableCart(err) {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		log.Error().Err(err).Str("reference", transaction.ProviderTxRefID).Msg("failed to checkout cart")
+
+		if err := s.refundFailedCheckout(r.Context(), transaction, fee, err); err != nil {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to process transaction")
+			log.Error().Err(err).Msg("error occurred")
+			return
+		}
+
+		s.errorResponse(w, r, http.StatusConflict, err.Error())
+		return
+	}
+
+	s.sendInvoices(r.Context(), transaction.ProviderTxRefID)
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "transaction completed",
+			"result": envelop{
+				"transaction": result.Transaction,
+			},
+		},
+	}, nil)
+}
+
+// nearAmount converts amount into yoctoNEAR at the configured NEAR price.
+func (s *StoreHub) nearAmount(amount money.Amount) (*big.Int, error) {
+	price, err := money.Parse(s.configs.NEARPrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NEAR_PRICE: %w", err)
+	}
+	return near.ToYocto(amount, price)
+}
+
+// lockedNEARAmount converts amount, all or part of what a NEAR wallet payment
+// paid, into yoctoNEAR at the rate the payment was quoted at. Transactions from
+// before quotes were locked are converted at the configured NEAR price.
+func (s *StoreHub) lockedNEARAmount(transaction db.Transaction, amount money.Amount) (*big.Int, error) {
+	if !transaction.NearAmount.Valid {
+		return s.nearAmount(amount)
+	}
+
+	quoted, err := near.ParseYocto(transaction.NearAmount.String)
+	if err != nil {
+		return nil, err
+	}
+
+	paid, err := money.Parse(transaction.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if paid <= money.Zero {
+		return nil, fmt.Errorf("transaction %s paid nothing in NEAR", transaction.ProviderTxRefID)
+	}
+
+	// rounded down, so a part never converts to more than the whole
+	yocto := new(big.Int).Mul(quoted, big.NewInt(amount.Minor()))
+	return yocto.Quo(yocto, big.NewInt(paid.Minor())), nil
+}
+
+// failTransaction marks a transaction FAILED without creating any order,
+// releases the coupons, stock, gift cards and store credit reserved for it and
+// fails its order group.
+func (s *StoreHub) failTransaction(ctx context.Context, reference, fee string) error {
+	_, err := s.dbStore.FailTransactionTx(ctx, db.FailTransactionTxParams{
+		ProviderTxRefID: reference,
+		ProviderTxFee:   fee,
+	})
+	return err
+}
+
+// refundFailedCheckout fails a checkout that was paid but can't be completed
+// because of cause, and has what its provider charged refunded, as
+// worker.RefundFailedCheckout does. A NEAR wallet payment is refunded at the
+// rate the buyer paid at.
+func (s *StoreHub) refundFailedCheckout(ctx context.Context, transaction db.Transaction, fee string, cause error) error {
+	arg := db.FailPaidCheckoutTxParams{
+		ProviderTxRefID: transaction.ProviderTxRefID,
+		ProviderTxFee:   fee,
+		Reason:          cause.Error(),
+	}
+
+	if transaction.PaymentProvider == payment.ProviderNEARWallet {
+		amount, err := money.Parse(transaction.Amount)
+		if err != nil {
+			return err
+		}
+
+		yocto, err := s.lockedNEARAmount(transaction, amount)
+		if err != nil {
+			return err
+		}
+
+		buyer, err := s.dbStore.GetUserByID(ctx, transaction.CustomerID)
+		if err != nil {
+			return err
+		}
+
+		arg.NEARReceiverID = buyer.AccountID
+		arg.NEARAmount = yocto.String()
+	}
+
+	return worker.RefundFailedCheckout(ctx, s.dbStore, s.taskDistributor, arg)
+}
+
+// abandonCheckout releases the coupons, stock, gift cards and store credit
+// reserved for a checkout that never got a transaction, and fails its order
+// group. It runs after the response is decided, so it outlives the request.
+func (s *StoreHub) abandonCheckout(reference string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.dbStore.AbandonCheckoutTx(ctx, reference); err != nil {
+		log.Error().Err(err).Str("reference", reference).Msg("failed to abandon checkout")
+	}
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/rs/zerolog/log"
+)
+
+type getStoreBalancesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// getStoreBalances maps to endpoint "GET /inventory/stores/{store_id}/balances"
+func (s *StoreHub) getStoreBalances(w http.ResponseWriter, r *http.Request) {
+	var pathVars getStoreBalancesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	balances, err := s.dbStore.GetStoreBalancesTx(r.Context(), pathVars.StoreID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch balances")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "fetched balances",
+			"result": envelop{
+				"balances": balances,
+			},
+		},
+	}, nil)
+}
+
+type createWithdrawalRequestBody struct {
+	AccountType string `json:"account_type" validate:"required,oneof=FIAT CRYPTO"`
+	Amount      string `json:"amount" validate:"required,numeric"`
+	Destination string `json:"destination" validate:"required,max=256"`
+}
+
+type createWithdrawalPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// createWithdrawal maps to endpoint "POST /inventory/stores/{store_id}/withdrawals"
+func (s *StoreHub) createWithdrawal(w http.ResponseWriter, r *http.Request) {
+	var reqBody createWithdrawalRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars createWithdrawalPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	amount, err := money.Parse(reqBody.Amount)
+	if err != nil || amount <= money.Zero {
+		s.errorResponse(w, r, http.StatusBadRequest, "amount must be a positive amount")
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	withdrawal, err := s.dbStore.CreateWithdrawalTx(r.Context(), db.CreateWithdrawalTxParams{
+		StoreID:     pathVars.StoreID,
+		AccountType: reqBody.AccountType,
+		Amount:      amount,
+		Destination: reqBody.Destination,
+		RequestedBy: authPayload.UserID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrInsufficientBalance):
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to request withdrawal")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "requested a withdrawal",
+			"result": envelop{
+				"withdrawal": withdrawal,
+			},
+		},
+	}, nil)
+}
+
+type listWithdrawalsPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listWithdrawalsQueryStr struct {
+	Status   string `querystr:"status" validate:"omitempty,oneof=PENDING PAID REJECTED CANCELLED"`
+	Page     int    `querystr:"page" validate:"max=10000000"`
+	PageSize int    `querystr:"page_size" validate:"max=20"`
+}
+
+// listWithdrawals maps to endpoint "GET /inventory/stores/{store_id}/withdrawals"
+func (s *StoreHub) listWithdrawals(w http.ResponseWriter, r *http.Request) {
+	var pathVars listWithdrawalsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listWithdrawalsQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 15
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListWithdrawalRequests(r.Context(), db.ListWithdrawalRequestsParams{
+		StoreID: pathVars.StoreID,
+		Status: sql.NullString{
+			String: reqQueryStr.Status,
+			Valid:  reqQueryStr.Status != "",
+		},
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list withdrawals")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	withdrawals := make([]db.WithdrawalRequest, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		withdrawals[i] = db.WithdrawalRequest{
+			ID:          row.ID,
+			StoreID:     row.StoreID,
+			AccountType: row.AccountType,
+			Amount:      row.Amount,
+			Destination: row.Destination,
+			Status:      row.Status,
+			RequestedBy: row.RequestedBy,
+			Note:        row.Note,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some withdrawals",
+			"result": envelop{
+				"withdrawals": withdrawals,
+				"metadata":    pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type withdrawalPathVars struct {
+	StoreID      int64 `path:"store_id" validate:"required,min=1"`
+	WithdrawalID int64 `path:"withdrawal_id" validate:"required,min=1"`
+}
+
+// getWithdrawal maps to endpoint "GET /inventory/stores/{store_id}/withdrawals/{withdrawal_id}"
+func (s *StoreHub) getWithdrawal(w http.ResponseWriter, r *http.Request) {
+	var pathVars withdrawalPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	withdrawal, err := s.dbStore.GetWithdrawalRequest(r.Context(), db.GetWithdrawalRequestParams{
+		WithdrawalID: pathVars.WithdrawalID,
+		StoreID:      pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "withdrawal not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch withdrawal")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found a withdrawal",
+			"result": envelop{
+				"withdrawal": withdrawal,
+			},
+		},
+	}, nil)
+}
+
+// cancelWithdrawal maps to endpoint "PATCH /inventory/stores/{store_id}/withdrawals/{withdrawal_id}/cancel"
+func (s *StoreHub) cancelWithdrawal(w http.ResponseWriter, r *http.Request) {
+	var pathVars withdrawalPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	withdrawal, err := s.dbStore.CancelWithdrawalTx(r.Context(), db.CancelWithdrawalTxParams{
+		StoreID:      pathVars.StoreID,
+		WithdrawalID: pathVars.WithdrawalID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "withdrawal not found")
+		case errors.Is(err, db.ErrWithdrawalNotPending):
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to cancel withdrawal")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "cancelled the withdrawal",
+			"result": envelop{
+				"withdrawal": withdrawal,
+			},
+		},
+	}, nil)
+}
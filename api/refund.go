@@ -0,0 +1,279 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/payment"
+	"github.com/OCD-Labs/store-hub/worker"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+type refundOrderRequestBody struct {
+	Amount          string `json:"amount" validate:"omitempty,numeric"`
+	RestockQuantity *int32 `json:"restock_quantity" validate:"omitempty,min=0"`
+	Reason          string `json:"reason" validate:"max=500"`
+	Destination     string `json:"destination" validate:"omitempty,oneof=ORIGINAL STORE_CREDIT"`
+}
+
+type refundOrderPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+	OrderID int64 `path:"order_id" validate:"required,min=1"`
+}
+
+// refundOrder maps to endpoint "POST /inventory/stores/{store_id}/orders/{order_id}/refunds"
+func (s *StoreHub) refundOrder(w http.ResponseWriter, r *http.Request) {
+	var reqBody refundOrderRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars refundOrderPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	amount := money.Zero
+	if reqBody.Amount != "" {
+		var err error
+		amount, err = money.Parse(reqBody.Amount)
+		if err != nil || amount <= money.Zero {
+			s.errorResponse(w, r, http.StatusBadRequest, "amount must be a positive amount")
+			return
+		}
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	result, err := s.dbStore.CreateRefundTx(r.Context(), db.CreateRefundTxParams{
+		StoreID:         pathVars.StoreID,
+		OrderID:         pathVars.OrderID,
+		Amount:          amount,
+		RestockQuantity: reqBody.RestockQuantity,
+		Reason:          reqBody.Reason,
+		RequestedBy:     authPayload.UserID,
+		Destination:     reqBody.Destination,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "order not found")
+		case errors.Is(err, db.ErrOrderNotRefundable), errors.Is(err, db.ErrOrderNotPaid):
+			s.errorResponse(w, r, http.StatusConflict, err.Error())
+		case errors.Is(err, db.ErrRefundExceedsOrder), errors.Is(err, db.ErrRestockExceedsOrder),
+			errors.Is(err, db.ErrRefundExceedsPayment):
+			s.errorResponse(w, r, http.StatusBadRequest, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to refund order")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	providerRefundID := ""
+	switch {
+	case result.Refund.Destination == db.RefundToStoreCredit:
+		// store credit is added to the buyer's wallet as the refund completes
+	case result.Transaction.PaymentProvider == payment.ProviderNEARWallet:
+		s.sendNEARRefund(w, r, result)
+		return
+	default:
+		providerRefundID, err = s.sendRefund(r.Context(), result)
+	}
+	if err != nil {
+		if !errors.Is(err, payment.ErrProvider) {
+			// The provider may have got the refund, so it's confirmed with them,
+			// and sent again only if they didn't.
+			s.resendRefund(w, r, result, err)
+			return
+		}
+
+		if _, fErr := s.dbStore.FailRefund(r.Context(), db.FailRefundParams{
+			RefundID:      result.Refund.ID,
+			FailureReason: err.Error(),
+		}); fErr != nil {
+			log.Error().Err(fErr).Int64("refund_id", result.Refund.ID).Msg("failed to mark refund FAILED")
+		}
+
+		s.errorResponse(w, r, http.StatusBadGateway, "payment provider could not refund the order")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	refund, err := s.dbStore.CompleteRefundTx(r.Context(), db.CompleteRefundTxParams{
+		RefundID:         result.Refund.ID,
+		ProviderRefundID: providerRefundID,
+	})
+	if err != nil {
+		// the buyer is already being refunded, so the refund stays PENDING for finance to settle
+		s.errorResponse(w, r, http.StatusInternalServerError, "refund was sent, but failed to record it")
+		log.Error().Err(err).Int64("refund_id", result.Refund.ID).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "refunded the order",
+			"result": envelop{
+				"refund": refund,
+			},
+		},
+	}, nil)
+}
+
+// sendRefund returns a refund's amount to the buyer through the provider
+// that took the payment, and returns the provider's reference for it. Only a
+// payment.ErrProvider error means the provider didn't get the refund.
+func (s *StoreHub) sendRefund(ctx context.Context, result db.CreateRefundTxResult) (string, error) {
+	switch result.Transaction.PaymentProvider {
+	case payment.ProviderPaystack:
+		refund, err := worker.SendRefund(ctx, s.paymentProvider, result.Refund.ID,
+			result.Transaction.ProviderTxRefID, result.Refund.Amount, result.Refund.Reason)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(refund.ID, 10), nil
+	default:
+		return "", fmt.Errorf("%w: can't refund %s payments", payment.ErrProvider, result.Transaction.PaymentProvider)
+	}
+}
+
+// resendRefund queues a refund whose first attempt failed with err, though
+// the provider may have got it, to be confirmed with the provider and sent
+// again if it wasn't, and responds with the refund. It stays PENDING until
+// the provider accepts it.
+func (s *StoreHub) resendRefund(w http.ResponseWriter, r *http.Request, result db.CreateRefundTxResult, err error) {
+	log.Error().Err(err).Int64("refund_id", result.Refund.ID).Msg("failed to send refund")
+
+	err = s.taskDistributor.DistributeTaskSendRefund(r.Context(), &worker.PayloadSendRefund{
+		RefundID: result.Refund.ID,
+	}, asynq.MaxRetry(10), asynq.Timeout(time.Minute), asynq.Queue(worker.QueueCritical))
+	if err != nil {
+		// the refund may still have been sent, so it stays PENDING for finance to settle
+		log.Error().Err(err).Int64("refund_id", result.Refund.ID).Msg("refund left PENDING with an unconfirmed provider refund")
+	}
+
+	s.writeJSON(w, http.StatusAccepted, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "refund is being sent",
+			"result": envelop{
+				"refund": result.Refund,
+			},
+		},
+	}, nil)
+}
+
+// sendNEARRefund queues a refund of a NEAR wallet payment as a transfer from
+// the master account, at the rate the buyer paid at, and responds with the
+// refund. It stays PENDING until the transfer's transaction executes.
+func (s *StoreHub) sendNEARRefund(w http.ResponseWriter, r *http.Request, result db.CreateRefundTxResult) {
+	transfer, err := s.createNEARRefundTransfer(r.Context(), result)
+	if err != nil {
+		if _, fErr := s.dbStore.FailRefund(r.Context(), db.FailRefundParams{
+			RefundID:      result.Refund.ID,
+			FailureReason: err.Error(),
+		}); fErr != nil {
+			log.Error().Err(fErr).Int64("refund_id", result.Refund.ID).Msg("failed to mark refund FAILED")
+		}
+
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to refund order")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	err = s.taskDistributor.DistributeTaskSendNEARTransfer(r.Context(), &worker.PayloadSendNEARTransfer{
+		TransferID: transfer.ID,
+	}, asynq.MaxRetry(10), asynq.Timeout(time.Minute), asynq.Queue(worker.QueueCritical))
+	if err != nil {
+		// nothing was signed, so the transfer and its refund can't have been sent
+		if _, fErr := s.dbStore.FailNEARTransferTx(r.Context(), db.FailNEARTransferTxParams{
+			TransferID:    transfer.ID,
+			FailureReason: err.Error(),
+		}); fErr != nil {
+			log.Error().Err(fErr).Int64("refund_id", result.Refund.ID).Msg("failed to mark refund FAILED")
+		}
+
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to refund order")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusAccepted, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "refund is being sent",
+			"result": envelop{
+				"refund": result.Refund,
+			},
+		},
+	}, nil)
+}
+
+// createNEARRefundTransfer records the transfer of a refund's amount, in
+// yoctoNEAR at the rate the buyer paid at, to the buyer's NEAR account.
+func (s *StoreHub) createNEARRefundTransfer(ctx context.Context, result db.CreateRefundTxResult) (db.NearTransfer, error) {
+	amount, err := money.Parse(result.Refund.Amount)
+	if err != nil {
+		return db.NearTransfer{}, err
+	}
+
+	buyer, err := s.dbStore.GetUserByID(ctx, result.Order.BuyerID)
+	if err != nil {
+		return db.NearTransfer{}, err
+	}
+
+	yocto, err := s.lockedNEARAmount(result.Transaction, amount)
+	if err != nil {
+		return db.NearTransfer{}, err
+	}
+
+	return s.dbStore.CreateNEARTransfer(ctx, db.CreateNEARTransferParams{
+		Reference:  result.Transaction.ProviderTxRefID,
+		RefundID:   sql.NullInt64{Int64: result.Refund.ID, Valid: true},
+		ReceiverID: buyer.AccountID,
+		Amount:     yocto.String(),
+	})
+}
+
+type listOrderRefundsPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+	OrderID int64 `path:"order_id" validate:"required,min=1"`
+}
+
+// listOrderRefunds maps to endpoint "GET /inventory/stores/{store_id}/orders/{order_id}/refunds"
+func (s *StoreHub) listOrderRefunds(w http.ResponseWriter, r *http.Request) {
+	var pathVars listOrderRefundsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	refunds, err := s.dbStore.ListOrderRefunds(r.Context(), db.ListOrderRefundsParams{
+		OrderID: pathVars.OrderID,
+		StoreID: pathVars.StoreID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list refunds")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some refunds",
+			"result": envelop{
+				"refunds": refunds,
+			},
+		},
+	}, nil)
+}
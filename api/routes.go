@@ -13,6 +13,10 @@ func (s *StoreHub) setupRoutes() http.Handler {
 	fsysHandler := http.FileServer(http.FS(s.swaggerFiles))
 	mux.Handler(http.MethodGet, "/api/v1/swagger/*any", http.StripPrefix("/api/v1/swagger/", fsysHandler))
 
+	if dir, ok := s.localMediaDir(); ok {
+		mux.Handler(http.MethodGet, "/api/v1/media/*filepath", s.serveMedia(dir))
+	}
+
 	mux.HandlerFunc(http.MethodGet, "/", s.healthcheck)
 	mux.HandlerFunc(http.MethodPost, "/api/v1/ping", s.healthcheck)
 
@@ -22,6 +26,7 @@ func (s *StoreHub) setupRoutes() http.Handler {
 	mux.Handler(http.MethodGet, "/api/v1/stores/:store_id/items", s.supportUnauthenticated(s.authenticate(http.HandlerFunc(s.listStoreItems))))
 	mux.HandlerFunc(http.MethodGet, "/api/v1/stores/:store_id/items/:item_id", http.HandlerFunc(s.getStoreItems))
 	mux.Handler(http.MethodPatch, "/api/v1/stores/:store_id/items/:item_id/buy", s.authenticate(http.HandlerFunc(s.buyStoreItems)))
+	mux.HandlerFunc(http.MethodGet, "/api/v1/stores/:store_id/flash-sales", s.listUpcomingFlashSales)
 
 	// inventory
 	mux.Handler(http.MethodPost, "/api/v1/inventory/stores", s.authenticate(http.HandlerFunc(s.createStore)))
@@ -74,6 +79,125 @@ func (s *StoreHub) setupRoutes() http.Handler {
 			),
 		),
 	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/items/export",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.exportStoreItems),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/item-imports",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.importStoreItems),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/item-imports/:import_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.getItemImport),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/item-imports/:import_id/errors",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.getItemImportErrors),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/stock-movements",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.listStockMovements),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/stock-takes",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.createStockTake),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPut,
+		"/api/v1/inventory/stores/:store_id/items/:item_id/reorder-threshold",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.setItemReorderThreshold),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/items-at-risk",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.listItemsAtRisk),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/item-images",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.PRODUCTINVENTORYACCESS,
+			)(
+				http.HandlerFunc(s.uploadItemImage),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/profile-image",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.uploadStoreProfileImage),
+			),
+		),
+	)
 	mux.Handler(
 		http.MethodPatch,
 		"/api/v1/inventory/stores/:store_id",
@@ -97,6 +221,30 @@ func (s *StoreHub) setupRoutes() http.Handler {
 		),
 	)
 
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/delivery-rules",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.ORDERSACCESS,
+			)(
+				http.HandlerFunc(s.getStoreDeliveryRule),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPut,
+		"/api/v1/inventory/stores/:store_id/delivery-rules",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.updateStoreDeliveryRule),
+			),
+		),
+	)
+
 	mux.Handler(
 		http.MethodPost,
 		"/api/v1/inventory/stores/:store_id/send-access-invitation",
@@ -139,7 +287,7 @@ func (s *StoreHub) setupRoutes() http.Handler {
 	)
 
 	// orders
-	mux.Handler(http.MethodPost, "/api/v1/inventory/stores/:store_id/orders", s.authenticate(http.HandlerFunc(s.createOrder)))
+	mux.Handler(http.MethodPost, "/api/v1/inventory/stores/:store_id/orders", s.authenticate(s.idempotent(http.HandlerFunc(s.createOrder))))
 	mux.Handler(
 		http.MethodGet,
 		"/api/v1/inventory/stores/:store_id/orders",
@@ -176,6 +324,55 @@ func (s *StoreHub) setupRoutes() http.Handler {
 			),
 		),
 	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/shipments/:shipment_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.ORDERSACCESS,
+			)(
+				http.HandlerFunc(s.getSellerShipment),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPatch,
+		"/api/v1/inventory/stores/:store_id/shipments/:shipment_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.ORDERSACCESS,
+			)(
+				http.HandlerFunc(s.updateSellerShipment),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/orders/:order_id/refunds",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				s.idempotent(http.HandlerFunc(s.refundOrder)),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/orders/:order_id/refunds",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.ORDERSACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.listOrderRefunds),
+			),
+		),
+	)
 
 	// sales
 	mux.Handler(
@@ -215,6 +412,342 @@ func (s *StoreHub) setupRoutes() http.Handler {
 		),
 	)
 
+	// payouts
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/balances",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.getStoreBalances),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/withdrawals",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				s.idempotent(http.HandlerFunc(s.createWithdrawal)),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/withdrawals",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.listWithdrawals),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/withdrawals/:withdrawal_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.getWithdrawal),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPatch,
+		"/api/v1/inventory/stores/:store_id/withdrawals/:withdrawal_id/cancel",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.cancelWithdrawal),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/ledger",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.listStoreLedgerEntries),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/invoices",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.listStoreInvoices),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/invoices/:invoice_id/pdf",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+				util.FINANCIALACCESS,
+			)(
+				http.HandlerFunc(s.getStoreInvoicePDF),
+			),
+		),
+	)
+
+	// coupons
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/coupons",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.createCoupon),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/coupons",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.listStoreCoupons),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/coupons/:coupon_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.getCoupon),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPatch,
+		"/api/v1/inventory/stores/:store_id/coupons/:coupon_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.updateCoupon),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodDelete,
+		"/api/v1/inventory/stores/:store_id/coupons/:coupon_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.deleteCoupon),
+			),
+		),
+	)
+
+	// flash sales
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/flash-sales",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.createFlashSale),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/flash-sales",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.listStoreFlashSales),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodDelete,
+		"/api/v1/inventory/stores/:store_id/flash-sales/:flash_sale_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.cancelFlashSale),
+			),
+		),
+	)
+
+	// tax rules
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/tax-rules",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.createTaxRule),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/tax-rules",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.listTaxRules),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/tax-rules/:tax_rule_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.getTaxRule),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPatch,
+		"/api/v1/inventory/stores/:store_id/tax-rules/:tax_rule_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.updateTaxRule),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodDelete,
+		"/api/v1/inventory/stores/:store_id/tax-rules/:tax_rule_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.deleteTaxRule),
+			),
+		),
+	)
+
+	// shipping zones
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/inventory/stores/:store_id/shipping-zones",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.createShippingZone),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/shipping-zones",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.listShippingZones),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/inventory/stores/:store_id/shipping-zones/:shipping_zone_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.getShippingZone),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodPut,
+		"/api/v1/inventory/stores/:store_id/shipping-zones/:shipping_zone_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.updateShippingZone),
+			),
+		),
+	)
+	mux.Handler(
+		http.MethodDelete,
+		"/api/v1/inventory/stores/:store_id/shipping-zones/:shipping_zone_id",
+		s.authenticate(
+			s.CheckAccessLevel(
+				util.FULLACCESS,
+			)(
+				http.HandlerFunc(s.deleteShippingZone),
+			),
+		),
+	)
+
+	// platform admin
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/admin/commission-rules",
+		s.authenticate(s.requirePlatformAdmin(http.HandlerFunc(s.createCommissionRule))),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/admin/commission-rules",
+		s.authenticate(s.requirePlatformAdmin(http.HandlerFunc(s.listCommissionRules))),
+	)
+	mux.Handler(
+		http.MethodPatch,
+		"/api/v1/admin/commission-rules/:rule_id",
+		s.authenticate(s.requirePlatformAdmin(http.HandlerFunc(s.updateCommissionRule))),
+	)
+	mux.Handler(
+		http.MethodDelete,
+		"/api/v1/admin/commission-rules/:rule_id",
+		s.authenticate(s.requirePlatformAdmin(http.HandlerFunc(s.deleteCommissionRule))),
+	)
+	mux.Handler(
+		http.MethodPost,
+		"/api/v1/admin/fx-rates/refresh",
+		s.authenticate(s.requirePlatformAdmin(http.HandlerFunc(s.refreshFxRates))),
+	)
+	mux.Handler(
+		http.MethodGet,
+		"/api/v1/admin/ledger/trial-balance",
+		s.authenticate(s.requirePlatformAdmin(http.HandlerFunc(s.getTrialBalance))),
+	)
+
 	// user
 	mux.HandlerFunc(http.MethodPost, "/api/v1/users", s.createUser)
 	mux.HandlerFunc(http.MethodPost, "/api/v1/auth/login", s.login)
@@ -222,6 +755,12 @@ func (s *StoreHub) setupRoutes() http.Handler {
 	mux.Handler(http.MethodGet, "/api/v1/users/:user_id", s.authenticate(http.HandlerFunc(s.getUser)))
 	mux.HandlerFunc(http.MethodPost, "/api/v1/users/verify-email", s.verifyEmail)
 	mux.HandlerFunc(http.MethodPost, "/api/v1/users/send-email-verification", s.sendEmailVerification)
+	mux.Handler(http.MethodGet, "/api/v1/users/:user_id/orders", s.authenticate(http.HandlerFunc(s.listBuyerOrderGroups)))
+	mux.Handler(http.MethodGet, "/api/v1/users/:user_id/orders/:order_number", s.authenticate(http.HandlerFunc(s.getBuyerOrderGroup)))
+	mux.Handler(http.MethodGet, "/api/v1/users/:user_id/invoices", s.authenticate(http.HandlerFunc(s.listBuyerInvoices)))
+	mux.Handler(http.MethodGet, "/api/v1/users/:user_id/invoices/:invoice_id/pdf", s.authenticate(http.HandlerFunc(s.getBuyerInvoicePDF)))
+	mux.Handler(http.MethodGet, "/api/v1/users/:user_id/gift-cards", s.authenticate(http.HandlerFunc(s.listPurchasedGiftCards)))
+	mux.Handler(http.MethodGet, "/api/v1/users/:user_id/store-credit", s.authenticate(http.HandlerFunc(s.getStoreCredit)))
 
 	// cart
 	mux.Handler(http.MethodGet, "/api/v1/carts/:user_id", s.authenticate(http.HandlerFunc(s.getUserCart)))
@@ -229,6 +768,19 @@ func (s *StoreHub) setupRoutes() http.Handler {
 	mux.Handler(http.MethodDelete, "/api/v1/carts/:cart_id/items/:item_id", s.authenticate(http.HandlerFunc(s.removeItemFromCart)))
 	mux.Handler(http.MethodPut, "/api/v1/carts/:cart_id/items/:item_id/increase", s.authenticate(http.HandlerFunc(s.increaseCartItem)))
 	mux.Handler(http.MethodPut, "/api/v1/carts/:cart_id/items/:item_id/decrease", s.authenticate(http.HandlerFunc(s.decreaseCartItem)))
+	mux.Handler(http.MethodPost, "/api/v1/carts/:cart_id/coupons", s.authenticate(http.HandlerFunc(s.applyCartCoupon)))
+	mux.Handler(http.MethodDelete, "/api/v1/carts/:cart_id/coupons/:coupon_id", s.authenticate(http.HandlerFunc(s.removeCartCoupon)))
+
+	// payments
+	mux.Handler(http.MethodGet, "/api/v1/checkout/quote", s.authenticate(http.HandlerFunc(s.getCheckoutQuote)))
+	mux.HandlerFunc(http.MethodGet, "/api/v1/fx-rates", s.listFxRates)
+	mux.Handler(http.MethodPost, "/api/v1/checkout", s.authenticate(s.idempotent(http.HandlerFunc(s.checkout))))
+	mux.HandlerFunc(http.MethodPost, "/api/v1/payments/paystack/webhook", s.paystackWebhook)
+	mux.Handler(http.MethodPost, "/api/v1/payments/near/verify", s.authenticate(s.idempotent(http.HandlerFunc(s.verifyNEARPayment))))
+
+	// gift cards
+	mux.Handler(http.MethodPost, "/api/v1/stores/:store_id/gift-cards", s.authenticate(s.idempotent(http.HandlerFunc(s.buyGiftCard))))
+	mux.Handler(http.MethodGet, "/api/v1/gift-cards/:code", s.authenticate(http.HandlerFunc(s.getGiftCard)))
 
 	// review
 	mux.Handler(http.MethodPut, "/api/v1/users/:user_id/reviews/:order_id", s.authenticate(http.HandlerFunc(s.addReview)))
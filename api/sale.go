@@ -7,6 +7,7 @@ import (
 	"time"
 
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
 	"github.com/OCD-Labs/store-hub/pagination"
 	"github.com/rs/zerolog/log"
 )
@@ -131,6 +132,13 @@ func (s *StoreHub) getSale(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fees, err := newSaleFees(sale)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve sale details")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
 	// return response
 	s.writeJSON(w, http.StatusOK, envelop{
 		"status": "success",
@@ -138,11 +146,70 @@ func (s *StoreHub) getSale(w http.ResponseWriter, r *http.Request) {
 			"message": "found a sale",
 			"result": envelop{
 				"sale": sale,
+				"fees": fees,
 			},
 		},
 	}, nil)
 }
 
+// saleFees breaks down what the buyer paid for a sale into the
+// platform's commission and what the store is owed. Gross includes
+// exclusive tax, which the store collects.
+type saleFees struct {
+	Subtotal             money.Amount `json:"subtotal"`
+	Discount             money.Amount `json:"discount"`
+	DeliveryFee          money.Amount `json:"delivery_fee"`
+	TaxRate              string       `json:"tax_rate"`
+	TaxInclusive         bool         `json:"tax_inclusive"`
+	Tax                  money.Amount `json:"tax"`
+	Gross                money.Amount `json:"gross"`
+	CommissionPercentage string       `json:"commission_percentage"`
+	CommissionFixed      money.Amount `json:"commission_fixed"`
+	Commission           money.Amount `json:"commission"`
+	Net                  money.Amount `json:"net"`
+}
+
+func newSaleFees(sale db.GetSaleRow) (saleFees, error) {
+	var fees saleFees
+
+	unitPrice, err := money.Parse(sale.OrderUnitPrice)
+	if err != nil {
+		return fees, err
+	}
+
+	if fees.DeliveryFee, err = money.Parse(sale.DeliveryFee); err != nil {
+		return fees, err
+	}
+
+	if fees.Discount, err = money.Parse(sale.DiscountAmount); err != nil {
+		return fees, err
+	}
+
+	if fees.Tax, err = money.Parse(sale.TaxAmount); err != nil {
+		return fees, err
+	}
+
+	if fees.CommissionFixed, err = money.Parse(sale.CommissionFixed); err != nil {
+		return fees, err
+	}
+
+	if fees.Commission, err = money.Parse(sale.CommissionAmount); err != nil {
+		return fees, err
+	}
+
+	fees.Subtotal = unitPrice.Mul(int64(sale.OrderQuantity))
+	fees.Gross = fees.Subtotal - fees.Discount + fees.DeliveryFee
+	if !sale.TaxInclusive {
+		fees.Gross += fees.Tax
+	}
+	fees.TaxRate = sale.TaxRate
+	fees.TaxInclusive = sale.TaxInclusive
+	fees.CommissionPercentage = sale.CommissionPercentage
+	fees.Net = fees.Gross - fees.Commission
+
+	return fees, nil
+}
+
 type listSalesOverviewPathVars struct {
 	StoreID int64 `path:"store_id" validate:"required,min=1"`
 }
@@ -11,8 +11,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/OCD-Labs/store-hub/blob"
 	"github.com/OCD-Labs/store-hub/cache"
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/near"
+	"github.com/OCD-Labs/store-hub/payment"
 	"github.com/OCD-Labs/store-hub/token"
 	"github.com/OCD-Labs/store-hub/util"
 	"github.com/OCD-Labs/store-hub/worker"
@@ -27,6 +31,10 @@ type StoreHub struct {
 	tokenMaker             token.Maker
 	dbStore                db.StoreTx
 	taskDistributor        worker.TaskDistributor
+	paymentProvider        payment.Provider
+	nearRPC                near.RPCClient
+	fxProvider             fx.Provider
+	blobStore              blob.Storage
 	SupportUnauthenticated bool
 }
 
@@ -37,6 +45,10 @@ func NewStoreHub(
 	store db.StoreTx,
 	taskDistributor worker.TaskDistributor,
 	tokenMaker token.Maker,
+	paymentProvider payment.Provider,
+	nearRPC near.RPCClient,
+	fxProvider fx.Provider,
+	blobStore blob.Storage,
 	swaggerFiles fs.FS,
 ) (*StoreHub, error) {
 	return &StoreHub{
@@ -46,6 +58,10 @@ func NewStoreHub(
 		tokenMaker:      tokenMaker,
 		dbStore:         store,
 		taskDistributor: taskDistributor,
+		paymentProvider: paymentProvider,
+		nearRPC:         nearRPC,
+		fxProvider:      fxProvider,
+		blobStore:       blobStore,
 		swaggerFiles:    swaggerFiles,
 	}, nil
 }
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/rs/zerolog/log"
+)
+
+// sellerShipment is what a store ships of an order group, with its lines.
+type sellerShipment struct {
+	db.GetSellerFulfilmentGroupRow
+	Lines []db.ListFulfilmentGroupLinesRow `json:"lines"`
+}
+
+// sellerShipment retrieves a store's shipment with its lines.
+func (s *StoreHub) sellerShipment(ctx context.Context, arg db.GetSellerFulfilmentGroupParams) (sellerShipment, error) {
+	fg, err := s.dbStore.GetSellerFulfilmentGroup(ctx, arg)
+	if err != nil {
+		return sellerShipment{}, err
+	}
+
+	lines, err := s.dbStore.ListFulfilmentGroupLines(ctx, []int64{fg.ID})
+	if err != nil {
+		return sellerShipment{}, err
+	}
+
+	return sellerShipment{GetSellerFulfilmentGroupRow: fg, Lines: lines}, nil
+}
+
+type getSellerShipmentPathVars struct {
+	StoreID    int64 `path:"store_id" validate:"required,min=1"`
+	ShipmentID int64 `path:"shipment_id" validate:"required,min=1"`
+}
+
+// getSellerShipment maps to endpoint "GET /inventory/stores/{store_id}/shipments/{shipment_id}"
+func (s *StoreHub) getSellerShipment(w http.ResponseWriter, r *http.Request) {
+	var pathVars getSellerShipmentPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	shipment, err := s.sellerShipment(r.Context(), db.GetSellerFulfilmentGroupParams{
+		FulfilmentGroupID: pathVars.ShipmentID,
+		StoreID:           pathVars.StoreID,
+		SellerID:          authPayload.UserID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "shipment not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to retrieve shipment")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found a shipment",
+			"result": envelop{
+				"shipment": shipment,
+			},
+		},
+	}, nil)
+}
+
+type updateSellerShipmentRequestBody struct {
+	DeliveryStatus       *string   `json:"delivery_status"`
+	DeliveredOn          time.Time `json:"delivered_on"`
+	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
+	Carrier              *string   `json:"carrier" validate:"omitempty,max=100"`
+	TrackingNumber       *string   `json:"tracking_number" validate:"omitempty,max=100"`
+}
+
+type updateSellerShipmentPathVars struct {
+	StoreID    int64 `path:"store_id" validate:"required,min=1"`
+	ShipmentID int64 `path:"shipment_id" validate:"required,min=1"`
+}
+
+// updateSellerShipment maps to endpoint "PATCH /inventory/stores/{store_id}/shipments/{shipment_id}"
+func (s *StoreHub) updateSellerShipment(w http.ResponseWriter, r *http.Request) {
+	var pathVars updateSellerShipmentPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqBody updateSellerShipmentRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	authPayload := s.contextGetMustToken(r)
+
+	arg := db.UpdateShipmentTxParams{
+		FulfilmentGroupID: pathVars.ShipmentID,
+		StoreID:           pathVars.StoreID,
+		SellerID:          authPayload.UserID,
+	}
+
+	if reqBody.DeliveryStatus != nil && *reqBody.DeliveryStatus != "" {
+		if !util.IsValidStatus(*reqBody.DeliveryStatus) {
+			s.errorResponse(w, r, http.StatusForbidden, "unsupported status")
+			return
+		}
+		arg.DeliveryStatus = sql.NullString{
+			String: *reqBody.DeliveryStatus,
+			Valid:  true,
+		}
+	}
+
+	if reqBody.DeliveryStatus != nil && *reqBody.DeliveryStatus == "DELIVERED" {
+		if reqBody.DeliveredOn.IsZero() {
+			s.errorResponse(w, r, http.StatusBadRequest, "can't change shipment status to DELIVERED without its 'delivered_on' date")
+			return
+		}
+		arg.DeliveredOn = sql.NullTime{
+			Time:  reqBody.DeliveredOn,
+			Valid: true,
+		}
+	} else if !reqBody.DeliveredOn.IsZero() {
+		s.errorResponse(w, r, http.StatusBadRequest, "can't set 'delivered_on' date if shipment status is not DELIVERED")
+		return
+	}
+
+	if !reqBody.ExpectedDeliveryDate.IsZero() {
+		arg.ExpectedDeliveryDate = sql.NullTime{
+			Time:  reqBody.ExpectedDeliveryDate,
+			Valid: true,
+		}
+	}
+
+	if reqBody.Carrier != nil {
+		arg.Carrier = sql.NullString{String: *reqBody.Carrier, Valid: true}
+	}
+	if reqBody.TrackingNumber != nil {
+		arg.TrackingNumber = sql.NullString{String: *reqBody.TrackingNumber, Valid: true}
+	}
+
+	result, err := s.dbStore.UpdateShipmentTx(r.Context(), arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "shipment not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update shipment")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated shipment's details",
+			"result":  result,
+		},
+	}, nil)
+}
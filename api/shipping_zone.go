@@ -0,0 +1,326 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// shippingRate is a row of a shipping zone's rate table: the fee for shipping
+// from_quantity grams or units up.
+type shippingRate struct {
+	FromQuantity int64  `json:"from_quantity" validate:"min=0"`
+	Fee          string `json:"fee" validate:"required,numeric"`
+}
+
+// shippingZoneRequestBody is a whole shipping zone, as created or replaced.
+type shippingZoneRequestBody struct {
+	Name                  string         `json:"name" validate:"required,max=100"`
+	Country               string         `json:"country" validate:"required,iso3166_1_alpha2"`
+	State                 string         `json:"state" validate:"max=100"`
+	City                  string         `json:"city" validate:"max=100"`
+	RateBasis             string         `json:"rate_basis" validate:"required,oneof=FLAT WEIGHT ITEM_COUNT"`
+	Rates                 []shippingRate `json:"rates" validate:"required,min=1,max=50,dive"`
+	FreeShippingThreshold string         `json:"free_shipping_threshold" validate:"omitempty,numeric"`
+	MinDeliveryDays       int32          `json:"min_delivery_days" validate:"min=0,max=365"`
+	MaxDeliveryDays       int32          `json:"max_delivery_days" validate:"required,min=1,max=365,gtefield=MinDeliveryDays"`
+}
+
+// bindShippingZoneRates checks a shipping zone's free shipping threshold and
+// rate table, writing an error response if they're invalid.
+func (s *StoreHub) bindShippingZoneRates(w http.ResponseWriter, r *http.Request, reqBody shippingZoneRequestBody) (money.Amount, []db.ShippingRateParams, bool) {
+	threshold := money.Zero
+	if reqBody.FreeShippingThreshold != "" {
+		var err error
+		threshold, err = money.Parse(reqBody.FreeShippingThreshold)
+		if err != nil || threshold < money.Zero {
+			s.errorResponse(w, r, http.StatusBadRequest, "free_shipping_threshold must be a non-negative amount")
+			return money.Zero, nil, false
+		}
+	}
+
+	if reqBody.RateBasis == pricing.RateFlat && len(reqBody.Rates) != 1 {
+		s.errorResponse(w, r, http.StatusBadRequest, "a FLAT zone takes a single rate")
+		return money.Zero, nil, false
+	}
+
+	rates := make([]db.ShippingRateParams, 0, len(reqBody.Rates))
+	seen := make(map[int64]bool, len(reqBody.Rates))
+	for _, rate := range reqBody.Rates {
+		fee, err := money.Parse(rate.Fee)
+		if err != nil || fee < money.Zero {
+			s.errorResponse(w, r, http.StatusBadRequest, "rate fees must be non-negative amounts")
+			return money.Zero, nil, false
+		}
+
+		if seen[rate.FromQuantity] {
+			s.errorResponse(w, r, http.StatusBadRequest, "rates must start from different quantities")
+			return money.Zero, nil, false
+		}
+		seen[rate.FromQuantity] = true
+
+		from := rate.FromQuantity
+		if reqBody.RateBasis == pricing.RateFlat {
+			from = 0
+		}
+		rates = append(rates, db.ShippingRateParams{FromQuantity: from, Fee: fee.String()})
+	}
+
+	return threshold, rates, true
+}
+
+// shippingZoneError writes the error response for a failed shipping zone write.
+func (s *StoreHub) shippingZoneError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			s.errorResponse(w, r, http.StatusConflict, "store already has a shipping zone for this region")
+		case "foreign_key_violation":
+			s.errorResponse(w, r, http.StatusNotFound, "store not found")
+		case "check_violation", "numeric_value_out_of_range":
+			s.errorResponse(w, r, http.StatusBadRequest, "invalid shipping zone")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to "+action+" shipping zone")
+		}
+	} else if errors.Is(err, sql.ErrNoRows) {
+		s.errorResponse(w, r, http.StatusNotFound, "shipping zone not found")
+	} else {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to "+action+" shipping zone")
+	}
+	log.Error().Err(err).Msg("error occurred")
+}
+
+type createShippingZonePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// createShippingZone maps to endpoint "POST /inventory/stores/{store_id}/shipping-zones"
+func (s *StoreHub) createShippingZone(w http.ResponseWriter, r *http.Request) {
+	var reqBody shippingZoneRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars createShippingZonePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	threshold, rates, ok := s.bindShippingZoneRates(w, r, reqBody)
+	if !ok {
+		return
+	}
+
+	zone, err := s.dbStore.CreateShippingZoneTx(r.Context(), db.CreateShippingZoneTxParams{
+		CreateShippingZoneParams: db.CreateShippingZoneParams{
+			StoreID:               pathVars.StoreID,
+			Name:                  reqBody.Name,
+			Country:               reqBody.Country,
+			State:                 reqBody.State,
+			City:                  reqBody.City,
+			RateBasis:             reqBody.RateBasis,
+			FreeShippingThreshold: threshold.String(),
+			MinDeliveryDays:       reqBody.MinDeliveryDays,
+			MaxDeliveryDays:       reqBody.MaxDeliveryDays,
+		},
+		Rates: rates,
+	})
+	if err != nil {
+		s.shippingZoneError(w, r, err, "create")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "created shipping zone",
+			"result": envelop{
+				"shipping_zone": zone,
+			},
+		},
+	}, nil)
+}
+
+type listShippingZonesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// listShippingZones maps to endpoint "GET /inventory/stores/{store_id}/shipping-zones"
+func (s *StoreHub) listShippingZones(w http.ResponseWriter, r *http.Request) {
+	var pathVars listShippingZonesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	zones, err := s.dbStore.ListShippingZones(r.Context(), pathVars.StoreID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list shipping zones")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	zoneIDs := make([]int64, len(zones))
+	results := make([]db.ShippingZoneWithRates, len(zones))
+	zoneIdx := make(map[int64]int, len(zones))
+	for i, zone := range zones {
+		zoneIDs[i] = zone.ID
+		results[i] = db.ShippingZoneWithRates{ShippingZone: zone, Rates: []db.ShippingRate{}}
+		zoneIdx[zone.ID] = i
+	}
+
+	rates, err := s.dbStore.ListShippingRates(r.Context(), zoneIDs)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list shipping zones")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	for _, rate := range rates {
+		i := zoneIdx[rate.ShippingZoneID]
+		results[i].Rates = append(results[i].Rates, rate)
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some shipping zones",
+			"result": envelop{
+				"shipping_zones": results,
+			},
+		},
+	}, nil)
+}
+
+type getShippingZonePathVars struct {
+	StoreID        int64 `path:"store_id" validate:"required,min=1"`
+	ShippingZoneID int64 `path:"shipping_zone_id" validate:"required,min=1"`
+}
+
+// getShippingZone maps to endpoint "GET /inventory/stores/{store_id}/shipping-zones/{shipping_zone_id}"
+func (s *StoreHub) getShippingZone(w http.ResponseWriter, r *http.Request) {
+	var pathVars getShippingZonePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	zone, err := s.dbStore.GetShippingZone(r.Context(), db.GetShippingZoneParams{
+		ShippingZoneID: pathVars.ShippingZoneID,
+		StoreID:        pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "shipping zone not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch shipping zone")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	rates, err := s.dbStore.ListShippingRates(r.Context(), []int64{zone.ID})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch shipping zone")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found shipping zone",
+			"result": envelop{
+				"shipping_zone": db.ShippingZoneWithRates{ShippingZone: zone, Rates: rates},
+			},
+		},
+	}, nil)
+}
+
+type updateShippingZonePathVars struct {
+	StoreID        int64 `path:"store_id" validate:"required,min=1"`
+	ShippingZoneID int64 `path:"shipping_zone_id" validate:"required,min=1"`
+}
+
+// updateShippingZone maps to endpoint "PUT /inventory/stores/{store_id}/shipping-zones/{shipping_zone_id}"
+func (s *StoreHub) updateShippingZone(w http.ResponseWriter, r *http.Request) {
+	var reqBody shippingZoneRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars updateShippingZonePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	threshold, rates, ok := s.bindShippingZoneRates(w, r, reqBody)
+	if !ok {
+		return
+	}
+
+	zone, err := s.dbStore.UpdateShippingZoneTx(r.Context(), db.UpdateShippingZoneTxParams{
+		UpdateShippingZoneParams: db.UpdateShippingZoneParams{
+			ShippingZoneID:        pathVars.ShippingZoneID,
+			StoreID:               pathVars.StoreID,
+			Name:                  reqBody.Name,
+			Country:               reqBody.Country,
+			State:                 reqBody.State,
+			City:                  reqBody.City,
+			RateBasis:             reqBody.RateBasis,
+			FreeShippingThreshold: threshold.String(),
+			MinDeliveryDays:       reqBody.MinDeliveryDays,
+			MaxDeliveryDays:       reqBody.MaxDeliveryDays,
+		},
+		Rates: rates,
+	})
+	if err != nil {
+		s.shippingZoneError(w, r, err, "update")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated shipping zone",
+			"result": envelop{
+				"shipping_zone": zone,
+			},
+		},
+	}, nil)
+}
+
+type deleteShippingZonePathVars struct {
+	StoreID        int64 `path:"store_id" validate:"required,min=1"`
+	ShippingZoneID int64 `path:"shipping_zone_id" validate:"required,min=1"`
+}
+
+// deleteShippingZone maps to endpoint "DELETE /inventory/stores/{store_id}/shipping-zones/{shipping_zone_id}"
+func (s *StoreHub) deleteShippingZone(w http.ResponseWriter, r *http.Request) {
+	var pathVars deleteShippingZonePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	deleted, err := s.dbStore.DeleteShippingZone(r.Context(), db.DeleteShippingZoneParams{
+		ShippingZoneID: pathVars.ShippingZoneID,
+		StoreID:        pathVars.StoreID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to delete shipping zone")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if deleted == 0 {
+		s.errorResponse(w, r, http.StatusNotFound, "shipping zone not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusNoContent, nil, nil)
+}
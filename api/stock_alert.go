@@ -0,0 +1,97 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+type setItemReorderThresholdRequestBody struct {
+	ReorderThreshold *int32 `json:"reorder_threshold" validate:"omitempty,min=0"` // null leaves it to the store's low_stock_threshold
+}
+
+type setItemReorderThresholdPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+	ItemID  int64 `path:"item_id" validate:"required,min=1"`
+}
+
+// setItemReorderThreshold maps to endpoint "PUT /inventory/stores/{store_id}/items/{item_id}/reorder-threshold"
+func (s *StoreHub) setItemReorderThreshold(w http.ResponseWriter, r *http.Request) {
+	var pathVars setItemReorderThresholdPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqBody setItemReorderThresholdRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	arg := db.SetItemReorderThresholdParams{
+		ItemID:  pathVars.ItemID,
+		StoreID: pathVars.StoreID,
+	}
+	if reqBody.ReorderThreshold != nil {
+		arg.ReorderThreshold = sql.NullInt32{
+			Int32: *reqBody.ReorderThreshold,
+			Valid: true,
+		}
+	}
+
+	item, err := s.dbStore.SetItemReorderThreshold(r.Context(), arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "item not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to set reorder threshold")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "set reorder threshold",
+			"result": envelop{
+				"item": item,
+			},
+		},
+	}, nil)
+}
+
+type listItemsAtRiskPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// listItemsAtRisk maps to endpoint "GET /inventory/stores/{store_id}/items-at-risk"
+func (s *StoreHub) listItemsAtRisk(w http.ResponseWriter, r *http.Request) {
+	var pathVars listItemsAtRiskPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	items, err := s.dbStore.ListItemsAtRisk(r.Context(), sql.NullInt64{
+		Int64: pathVars.StoreID,
+		Valid: true,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list items at risk")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some items at risk",
+			"result": envelop{
+				"items": items,
+			},
+		},
+	}, nil)
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type listStockMovementsPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+type listStockMovementsQueryStr struct {
+	ItemID    int64     `querystr:"item_id" validate:"omitempty,min=1"`
+	VariantID int64     `querystr:"variant_id" validate:"omitempty,min=1"`
+	Reason    string    `querystr:"reason" validate:"omitempty,oneof=INITIAL SALE ADJUSTMENT STOCK_TAKE RETURN IMPORT RESERVATION RELEASE"`
+	From      time.Time `querystr:"from"` // YYYY-MM-DD
+	To        time.Time `querystr:"to"`   // YYYY-MM-DD, inclusive
+	Page      int       `querystr:"page" validate:"max=10000000"`
+	PageSize  int       `querystr:"page_size" validate:"max=50"`
+}
+
+// listStockMovements maps to endpoint "GET /inventory/stores/{store_id}/stock-movements"
+func (s *StoreHub) listStockMovements(w http.ResponseWriter, r *http.Request) {
+	var pathVars listStockMovementsPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqQueryStr listStockMovementsQueryStr
+	if err := s.shouldBindQuery(w, r, &reqQueryStr); err != nil {
+		return
+	}
+
+	if reqQueryStr.Page < 1 {
+		reqQueryStr.Page = 1
+	}
+	if reqQueryStr.PageSize < 1 {
+		reqQueryStr.PageSize = 20
+	}
+
+	filters := pagination.Filters{
+		Page:     reqQueryStr.Page,
+		PageSize: reqQueryStr.PageSize,
+	}
+
+	rows, err := s.dbStore.ListStockMovements(r.Context(), db.ListStockMovementsParams{
+		StoreID: pathVars.StoreID,
+		ItemID: sql.NullInt64{
+			Int64: reqQueryStr.ItemID,
+			Valid: reqQueryStr.ItemID != 0,
+		},
+		VariantID: sql.NullInt64{
+			Int64: reqQueryStr.VariantID,
+			Valid: reqQueryStr.VariantID != 0,
+		},
+		Reason: sql.NullString{
+			String: reqQueryStr.Reason,
+			Valid:  reqQueryStr.Reason != "",
+		},
+		CreatedFrom: sql.NullTime{
+			Time:  reqQueryStr.From,
+			Valid: !reqQueryStr.From.IsZero(),
+		},
+		CreatedBefore: sql.NullTime{
+			Time:  reqQueryStr.To.AddDate(0, 0, 1),
+			Valid: !reqQueryStr.To.IsZero(),
+		},
+		RwLimit:  int32(filters.Limit()),
+		RwOffset: int32(filters.Offset()),
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list stock movements")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	totalRecords := 0
+	movements := make([]db.StockMovement, len(rows))
+	for i, row := range rows {
+		totalRecords = int(row.TotalCount)
+		movements[i] = db.StockMovement{
+			ID:                row.ID,
+			StoreID:           row.StoreID,
+			ItemID:            row.ItemID,
+			VariantID:         row.VariantID,
+			Reason:            row.Reason,
+			QuantityChange:    row.QuantityChange,
+			ResultingQuantity: row.ResultingQuantity,
+			UserID:            row.UserID,
+			Reference:         row.Reference,
+			Note:              row.Note,
+			CreatedAt:         row.CreatedAt,
+		}
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some stock movements",
+			"result": envelop{
+				"movements": movements,
+				"metadata":  pagination.CalcMetadata(totalRecords, filters.Page, filters.PageSize),
+			},
+		},
+	}, nil)
+}
+
+type stockCount struct {
+	ItemID          int64 `json:"item_id" validate:"required,min=1"`
+	VariantID       int64 `json:"variant_id" validate:"omitempty,min=1"` // required for an item with variants
+	CountedQuantity int64 `json:"counted_quantity" validate:"min=0"`
+}
+
+type createStockTakeRequestBody struct {
+	Note   string       `json:"note" validate:"max=500"`
+	Counts []stockCount `json:"counts" validate:"required,min=1,max=500,dive"`
+}
+
+type createStockTakePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// createStockTake maps to endpoint "POST /inventory/stores/{store_id}/stock-takes"
+func (s *StoreHub) createStockTake(w http.ResponseWriter, r *http.Request) {
+	var pathVars createStockTakePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	var reqBody createStockTakeRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	counts := make([]db.StockCount, len(reqBody.Counts))
+	for i, count := range reqBody.Counts {
+		counts[i] = db.StockCount{
+			ItemID:          count.ItemID,
+			VariantID:       count.VariantID,
+			CountedQuantity: count.CountedQuantity,
+		}
+	}
+
+	reference := "stock-take:" + uuid.NewString()
+
+	movements, err := s.dbStore.StockTakeTx(r.Context(), db.StockTakeTxParams{
+		StoreID:   pathVars.StoreID,
+		UserID:    s.contextGetMustToken(r).UserID,
+		Reference: reference,
+		Note:      reqBody.Note,
+		Counts:    counts,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrItemNotFound), errors.Is(err, db.ErrVariantNotFound):
+			s.errorResponse(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, db.ErrVariantRequired):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to record stock-take")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "recorded stock-take",
+			"result": envelop{
+				"reference": reference,
+				"movements": movements,
+			},
+		},
+	}, nil)
+}
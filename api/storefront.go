@@ -149,44 +149,51 @@ func (s *StoreHub) buyStoreItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := s.dbStore.GetItem(r.Context(), pathVar.ItemID)
+	// Units on a flash sale are claimed through checkout, against the sale's
+	// own counter, so the item can't be bought outside it while it runs.
+	flashSale, err := s.itemFlashSale(r.Context(), pathVar.ItemID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to update item")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+	if flashSale != nil && flashSale.IsLive {
+		s.errorResponse(w, r, http.StatusConflict, "item is on a flash sale; buy it through checkout")
+		return
+	}
+
+	// The supply is checked and deducted under a lock on the item, net of the
+	// stock reserved for checkouts, so concurrent buyers can't oversell it.
+	updatedItem, err := s.dbStore.BuyItemTx(r.Context(), db.BuyItemTxParams{
+		ItemID:   pathVar.ItemID,
+		StoreID:  pathVar.StoreID,
+		Quantity: 1,
+		UserID:   s.contextGetMustToken(r).UserID,
+	})
 	if err != nil {
 		switch {
-		case errors.Is(err, sql.ErrNoRows):
+		case errors.Is(err, db.ErrItemNotFound):
 			s.errorResponse(w, r, http.StatusNotFound, "item not found")
+		case errors.Is(err, db.ErrInsufficientStock):
+			s.errorResponse(w, r, http.StatusNotFound, "item no longer in stock")
+		case errors.Is(err, db.ErrVariantRequired):
+			s.errorResponse(w, r, http.StatusUnprocessableEntity, "item has variants; buy it through checkout")
 		default:
-			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch item's details")
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update item")
 		}
 		log.Error().Err(err).Msg("error occurred")
 		return
 	}
 
-	if item.SupplyQuantity >= 1 {
-		arg := db.UpdateItemParams{
-			ItemID: pathVar.ItemID,
-			SupplyQuantity: sql.NullInt64{
-				Int64: item.SupplyQuantity - 1,
-				Valid: true,
-			},
-		}
-		updatedItem, err := s.dbStore.UpdateItem(r.Context(), arg)
-		if err != nil {
-			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update item")
-			log.Error().Err(err).Msg("error occurred")
-			return
-		}
-		s.writeJSON(w, http.StatusOK, envelop{
-			"status": "success",
-			"data": envelop{
-				"message": "item sold",
-				"result": envelop{
-					"new_item": updatedItem,
-				},
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "item sold",
+			"result": envelop{
+				"new_item": updatedItem,
 			},
-		}, nil)
-	} else {
-		s.errorResponse(w, r, http.StatusNotFound, "item no longer in stock")
-	}
+		},
+	}, nil)
 
 	// TODO: Add swagger documentation for this endpoint
 }
@@ -216,12 +223,42 @@ func (s *StoreHub) getStoreItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Buyers see the stock not held for checkouts
+	reserved, err := s.dbStore.GetReservedStock(r.Context(), db.GetReservedStockParams{
+		ItemID: item.ID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch item's details")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+	item.SupplyQuantity -= reserved
+
+	// the variant matrix, each at the price and stock it sells at
+	options, variants, err := s.storefrontVariants(r.Context(), item)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch item's details")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	// the flash sale the item is on, or its next one
+	flashSale, err := s.itemFlashSale(r.Context(), item.ID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch item's details")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
 	s.writeJSON(w, http.StatusOK, envelop{
 		"status": "success",
 		"data": envelop{
 			"message": "found item",
 			"result": envelop{
-				"item": item,
+				"item":       item,
+				"options":    options,
+				"variants":   variants,
+				"flash_sale": flashSale,
 			},
 		},
 	}, nil)
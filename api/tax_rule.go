@@ -0,0 +1,234 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type createTaxRuleRequestBody struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	Country   string `json:"country" validate:"required,iso3166_1_alpha2"`
+	State     string `json:"state" validate:"max=100"`
+	Category  string `json:"category" validate:"max=100"`
+	Rate      string `json:"rate" validate:"required,numeric"`
+	Inclusive bool   `json:"inclusive"`
+}
+
+type createTaxRulePathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// createTaxRule maps to endpoint "POST /inventory/stores/{store_id}/tax-rules"
+func (s *StoreHub) createTaxRule(w http.ResponseWriter, r *http.Request) {
+	var reqBody createTaxRuleRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars createTaxRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	rule, err := s.dbStore.CreateTaxRule(r.Context(), db.CreateTaxRuleParams{
+		StoreID:   pathVars.StoreID,
+		Name:      reqBody.Name,
+		Country:   reqBody.Country,
+		State:     reqBody.State,
+		Category:  reqBody.Category,
+		Rate:      reqBody.Rate,
+		Inclusive: reqBody.Inclusive,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				s.errorResponse(w, r, http.StatusConflict, "store already has a tax rule for this region and category")
+			case "foreign_key_violation":
+				s.errorResponse(w, r, http.StatusNotFound, "store not found")
+			case "check_violation", "numeric_value_out_of_range":
+				s.errorResponse(w, r, http.StatusBadRequest, "tax rate must be between 0 and 100")
+			default:
+				s.errorResponse(w, r, http.StatusInternalServerError, "failed to create tax rule")
+			}
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to create tax rule")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusCreated, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "created tax rule",
+			"result": envelop{
+				"tax_rule": rule,
+			},
+		},
+	}, nil)
+}
+
+type listTaxRulesPathVars struct {
+	StoreID int64 `path:"store_id" validate:"required,min=1"`
+}
+
+// listTaxRules maps to endpoint "GET /inventory/stores/{store_id}/tax-rules"
+func (s *StoreHub) listTaxRules(w http.ResponseWriter, r *http.Request) {
+	var pathVars listTaxRulesPathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	rules, err := s.dbStore.ListTaxRules(r.Context(), pathVars.StoreID)
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to list tax rules")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found some tax rules",
+			"result": envelop{
+				"tax_rules": rules,
+			},
+		},
+	}, nil)
+}
+
+type getTaxRulePathVars struct {
+	StoreID   int64 `path:"store_id" validate:"required,min=1"`
+	TaxRuleID int64 `path:"tax_rule_id" validate:"required,min=1"`
+}
+
+// getTaxRule maps to endpoint "GET /inventory/stores/{store_id}/tax-rules/{tax_rule_id}"
+func (s *StoreHub) getTaxRule(w http.ResponseWriter, r *http.Request) {
+	var pathVars getTaxRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	rule, err := s.dbStore.GetTaxRule(r.Context(), db.GetTaxRuleParams{
+		TaxRuleID: pathVars.TaxRuleID,
+		StoreID:   pathVars.StoreID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			s.errorResponse(w, r, http.StatusNotFound, "tax rule not found")
+		default:
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to fetch tax rule")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "found tax rule",
+			"result": envelop{
+				"tax_rule": rule,
+			},
+		},
+	}, nil)
+}
+
+type updateTaxRuleRequestBody struct {
+	Name      *string `json:"name" validate:"omitempty,max=100"`
+	Rate      *string `json:"rate" validate:"omitempty,numeric"`
+	Inclusive *bool   `json:"inclusive"`
+}
+
+type updateTaxRulePathVars struct {
+	StoreID   int64 `path:"store_id" validate:"required,min=1"`
+	TaxRuleID int64 `path:"tax_rule_id" validate:"required,min=1"`
+}
+
+// updateTaxRule maps to endpoint "PATCH /inventory/stores/{store_id}/tax-rules/{tax_rule_id}"
+func (s *StoreHub) updateTaxRule(w http.ResponseWriter, r *http.Request) {
+	var reqBody updateTaxRuleRequestBody
+	if err := s.shouldBindBody(w, r, &reqBody); err != nil {
+		return
+	}
+
+	var pathVars updateTaxRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	arg := db.UpdateTaxRuleParams{
+		TaxRuleID: pathVars.TaxRuleID,
+		StoreID:   pathVars.StoreID,
+	}
+	if reqBody.Name != nil && *reqBody.Name != "" {
+		arg.Name = sql.NullString{String: *reqBody.Name, Valid: true}
+	}
+	if reqBody.Rate != nil && *reqBody.Rate != "" {
+		arg.Rate = sql.NullString{String: *reqBody.Rate, Valid: true}
+	}
+	if reqBody.Inclusive != nil {
+		arg.Inclusive = sql.NullBool{Bool: *reqBody.Inclusive, Valid: true}
+	}
+
+	rule, err := s.dbStore.UpdateTaxRule(r.Context(), arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && (pqErr.Code.Name() == "check_violation" || pqErr.Code.Name() == "numeric_value_out_of_range") {
+			s.errorResponse(w, r, http.StatusBadRequest, "tax rate must be between 0 and 100")
+		} else if errors.Is(err, sql.ErrNoRows) {
+			s.errorResponse(w, r, http.StatusNotFound, "tax rule not found")
+		} else {
+			s.errorResponse(w, r, http.StatusInternalServerError, "failed to update tax rule")
+		}
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, envelop{
+		"status": "success",
+		"data": envelop{
+			"message": "updated tax rule",
+			"result": envelop{
+				"tax_rule": rule,
+			},
+		},
+	}, nil)
+}
+
+type deleteTaxRulePathVars struct {
+	StoreID   int64 `path:"store_id" validate:"required,min=1"`
+	TaxRuleID int64 `path:"tax_rule_id" validate:"required,min=1"`
+}
+
+// deleteTaxRule maps to endpoint "DELETE /inventory/stores/{store_id}/tax-rules/{tax_rule_id}"
+func (s *StoreHub) deleteTaxRule(w http.ResponseWriter, r *http.Request) {
+	var pathVars deleteTaxRulePathVars
+	if err := s.ShouldBindPathVars(w, r, &pathVars); err != nil {
+		return
+	}
+
+	// orders keep the rate they were taxed at, so a rule can always be deleted
+	deleted, err := s.dbStore.DeleteTaxRule(r.Context(), db.DeleteTaxRuleParams{
+		TaxRuleID: pathVars.TaxRuleID,
+		StoreID:   pathVars.StoreID,
+	})
+	if err != nil {
+		s.errorResponse(w, r, http.StatusInternalServerError, "failed to delete tax rule")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
+	if deleted == 0 {
+		s.errorResponse(w, r, http.StatusNotFound, "tax rule not found")
+		return
+	}
+
+	s.writeJSON(w, http.StatusNoContent, nil, nil)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/near"
 	"github.com/OCD-Labs/store-hub/token"
 	"github.com/OCD-Labs/store-hub/util"
 	"github.com/OCD-Labs/store-hub/worker"
@@ -24,6 +25,7 @@ type createUserRequest struct {
 	Password        string  `json:"password" validate:"required,min=8"`
 	Email           string  `json:"email" validate:"required,email"`
 	AccountID       string  `json:"account_id" validate:"required,min=2,max=64"`
+	NEARPublicKey   string  `json:"near_public_key" validate:"required"`
 	ProfileImageUrl *string `json:"profile_image_url"`
 }
 
@@ -67,6 +69,13 @@ func (s *StoreHub) createUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the user's NEAR sub-account is created with their key, not the platform's
+	if _, err := near.ParsePublicKey(reqBody.NEARPublicKey); err != nil {
+		s.errorResponse(w, r, http.StatusBadRequest, "invalid near_public_key")
+		log.Error().Err(err).Msg("error occurred")
+		return
+	}
+
 	// hash password
 	hashedPassword, err := util.HashedPassword(reqBody.Password)
 	if err != nil {
@@ -113,7 +122,10 @@ func (s *StoreHub) createUser(w http.ResponseWriter, r *http.Request) {
 			subaccount := fmt.Sprintf("%s-%d.%s", util.SanitizeAccountID(reqBody.AccountID, s.configs.NEARNetwork), user.ID, s.configs.NEARAccountID)
 
 			taskNEARTxPayload := &worker.PayloadNEARTx{
-				Args: []string{"create-account", subaccount, "--masterAccount", s.configs.NEARAccountID, "--initialBalance", "1"},
+				Op:             worker.NEAROpCreateSubAccount,
+				AccountID:      subaccount,
+				InitialBalance: "1",
+				PublicKey:      reqBody.NEARPublicKey,
 			}
 
 			nearTxopts := []asynq.Option{
@@ -0,0 +1,39 @@
+// Package blob stores files, such as uploaded images, under keys like
+// "stores/1/items/9b2f.../small.webp", and serves them at public URLs.
+package blob
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// A Storage keeps files under keys, each at a public URL.
+type Storage interface {
+	// Put stores data under key, replacing any file there, and returns its public URL.
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+
+	// Delete removes the file under key, if there is one.
+	Delete(ctx context.Context, key string) error
+}
+
+var ErrInvalidKey = errors.New("blob: invalid key")
+
+// checkKey checks key is a relative, clean, slash-separated path, so it can't
+// reach outside where files are kept.
+func checkKey(key string) error {
+	if key == "" ||
+		strings.HasPrefix(key, "/") ||
+		strings.Contains(key, "\\") ||
+		path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// joinURL joins a base URL and a key, whether or not base ends in "/".
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
@@ -2,9 +2,44 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"time"
 )
 
+var (
+	// ErrCacheMiss is returned when a key is not in the cache.
+	ErrCacheMiss = errors.New("cache: key not found")
+
+	// ErrFlashSaleSoldOut is returned when a flash sale has too few units left for a claim.
+	ErrFlashSaleSoldOut = errors.New("flash sale has sold out")
+
+	// ErrFlashSaleLimitReached is returned when a claim would take a buyer past a flash sale's per-user limit.
+	ErrFlashSaleLimitReached = errors.New("flash sale limit per buyer reached")
+)
+
+// IdempotentResponse is the response stored against an Idempotency-Key,
+// together with a fingerprint of the request that produced it.
+type IdempotentResponse struct {
+	Fingerprint string      `json:"fingerprint"`
+	Completed   bool        `json:"completed"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// FlashSaleClaim is units of a flash sale claimed by a buyer for the checkout
+// under Reference. Quantity, PerUserLimit and EndsAt are the sale's.
+type FlashSaleClaim struct {
+	FlashSaleID  int64
+	UserID       int64
+	Reference    string
+	Units        int64
+	Quantity     int64
+	PerUserLimit int64
+	EndsAt       time.Time
+}
+
 // Cache defines interfaces required for caching.
 type Cache interface {
 	// BlacklistSession adds a session token to the blacklist cache with an expiration duration.
@@ -12,4 +47,24 @@ type Cache interface {
 
 	// IsSessionBlacklisted checks if a session token is blacklisted by querying the Redis cache.
 	IsSessionBlacklisted(ctx context.Context, sessionToken string) (bool, error)
+
+	// StartIdempotentRequest claims an Idempotency-Key for a request with the given fingerprint.
+	// If the key was already claimed, it returns false with what is stored against the key.
+	StartIdempotentRequest(ctx context.Context, key, fingerprint string, expirationTime time.Duration) (bool, IdempotentResponse, error)
+
+	// SaveIdempotentResponse stores the response to the request that claimed an Idempotency-Key.
+	SaveIdempotentResponse(ctx context.Context, key string, response IdempotentResponse, expirationTime time.Duration) error
+
+	// ReleaseIdempotencyKey forgets an Idempotency-Key, so the request can be retried.
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+
+	// ClaimFlashSale atomically claims units of a flash sale for a buyer, and returns the units left.
+	// It fails with ErrFlashSaleSoldOut or ErrFlashSaleLimitReached, claiming nothing, if the units aren't available.
+	ClaimFlashSale(ctx context.Context, claim FlashSaleClaim) (int64, error)
+
+	// ReleaseFlashSaleClaim gives back the units of a claim. Releasing the same claim again does nothing.
+	ReleaseFlashSaleClaim(ctx context.Context, claim FlashSaleClaim) error
+
+	// FlashSalesClaimed returns the units claimed of each flash sale.
+	FlashSalesClaimed(ctx context.Context, flashSaleIDs []int64) (map[int64]int64, error)
 }
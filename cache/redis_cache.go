@@ -2,6 +2,9 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -44,3 +47,150 @@ func (rc *RedisCache) IsSessionBlacklisted(ctx context.Context, sessionToken str
 	}
 	return exists == 1, nil
 }
+
+// StartIdempotentRequest claims an Idempotency-Key for a request with the given fingerprint.
+// If the key was already claimed, it returns false with what is stored against the key.
+func (rc *RedisCache) StartIdempotentRequest(ctx context.Context, key, fingerprint string, expirationTime time.Duration) (bool, IdempotentResponse, error) {
+	value, err := json.Marshal(IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return false, IdempotentResponse{}, err
+	}
+
+	claimed, err := rc.client.SetNX(ctx, "idempotency:"+key, value, expirationTime).Result()
+	if err != nil || claimed {
+		return claimed, IdempotentResponse{}, err
+	}
+
+	stored, err := rc.client.Get(ctx, "idempotency:"+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			err = ErrCacheMiss
+		}
+		return false, IdempotentResponse{}, err
+	}
+
+	var response IdempotentResponse
+	err = json.Unmarshal(stored, &response)
+	return false, response, err
+}
+
+// SaveIdempotentResponse stores the response to the request that claimed an Idempotency-Key.
+func (rc *RedisCache) SaveIdempotentResponse(ctx context.Context, key string, response IdempotentResponse, expirationTime time.Duration) error {
+	value, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return rc.client.Set(ctx, "idempotency:"+key, value, expirationTime).Err()
+}
+
+// ReleaseIdempotencyKey forgets an Idempotency-Key, so the request can be retried.
+func (rc *RedisCache) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	return rc.client.Del(ctx, "idempotency:"+key).Err()
+}
+
+// flashSaleKeyTTL is how long a flash sale's counters outlive it, so late
+// releases and its reconciliation still find them.
+const flashSaleKeyTTL = 7 * 24 * time.Hour
+
+// A flash sale's keys share a hash tag, so its scripts run on one cluster node.
+func flashSaleKeys(flashSaleID int64) (claimed, users, released string) {
+	prefix := fmt.Sprintf("flash_sale:{%d}:", flashSaleID)
+	return prefix + "claimed", prefix + "users", prefix + "released"
+}
+
+// claimFlashSale takes ARGV[2] units for buyer ARGV[1] unless the sale's
+// claimed count would pass ARGV[3] (-1) or the buyer's ARGV[4] (-2), and
+// returns the units left.
+var claimFlashSale = redis.NewScript(`
+local claimed = tonumber(redis.call('GET', KEYS[1]) or '0')
+local units = tonumber(ARGV[2])
+if claimed + units > tonumber(ARGV[3]) then
+  return -1
+end
+local mine = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '0')
+if mine + units > tonumber(ARGV[4]) then
+  return -2
+end
+redis.call('INCRBY', KEYS[1], units)
+redis.call('HINCRBY', KEYS[2], ARGV[1], units)
+redis.call('EXPIREAT', KEYS[1], ARGV[5])
+redis.call('EXPIREAT', KEYS[2], ARGV[5])
+return tonumber(ARGV[3]) - claimed - units
+`)
+
+// releaseFlashSaleClaim gives back ARGV[2] units of buyer ARGV[1]'s claim for
+// checkout ARGV[3], once.
+var releaseFlashSaleClaim = redis.NewScript(`
+if redis.call('SADD', KEYS[3], ARGV[3]) == 0 then
+  return 0
+end
+redis.call('EXPIREAT', KEYS[3], ARGV[4])
+local units = tonumber(ARGV[2])
+local claimed = tonumber(redis.call('GET', KEYS[1]) or '0')
+if claimed > 0 then
+  redis.call('DECRBY', KEYS[1], math.min(units, claimed))
+end
+local mine = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '0')
+if mine > 0 then
+  redis.call('HINCRBY', KEYS[2], ARGV[1], -math.min(units, mine))
+end
+return 1
+`)
+
+// ClaimFlashSale atomically claims units of a flash sale for a buyer, and returns the units left.
+// It fails with ErrFlashSaleSoldOut or ErrFlashSaleLimitReached, claiming nothing, if the units aren't available.
+func (rc *RedisCache) ClaimFlashSale(ctx context.Context, claim FlashSaleClaim) (int64, error) {
+	claimed, users, _ := flashSaleKeys(claim.FlashSaleID)
+
+	left, err := claimFlashSale.Run(ctx, rc.client, []string{claimed, users},
+		claim.UserID, claim.Units, claim.Quantity, claim.PerUserLimit, claim.EndsAt.Add(flashSaleKeyTTL).Unix(),
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	switch left {
+	case -1:
+		return 0, ErrFlashSaleSoldOut
+	case -2:
+		return 0, ErrFlashSaleLimitReached
+	}
+	return left, nil
+}
+
+// ReleaseFlashSaleClaim gives back the units of a claim. Releasing the same claim again does nothing.
+func (rc *RedisCache) ReleaseFlashSaleClaim(ctx context.Context, claim FlashSaleClaim) error {
+	claimed, users, released := flashSaleKeys(claim.FlashSaleID)
+
+	return releaseFlashSaleClaim.Run(ctx, rc.client, []string{claimed, users, released},
+		claim.UserID, claim.Units, claim.Reference, claim.EndsAt.Add(flashSaleKeyTTL).Unix(),
+	).Err()
+}
+
+// FlashSalesClaimed returns the units claimed of each flash sale.
+func (rc *RedisCache) FlashSalesClaimed(ctx context.Context, flashSaleIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(flashSaleIDs))
+	if len(flashSaleIDs) == 0 {
+		return counts, nil
+	}
+
+	// The counters are read in one round trip.
+	pipe := rc.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(flashSaleIDs))
+	for i, id := range flashSaleIDs {
+		claimed, _, _ := flashSaleKeys(id)
+		cmds[i] = pipe.Get(ctx, claimed)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		n, err := cmd.Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		counts[flashSaleIDs[i]] = n
+	}
+	return counts, nil
+}
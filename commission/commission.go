@@ -0,0 +1,109 @@
+// Package commission works out the platform's cut of each order line
+// from the commission rules in force. A rule for the line's store
+// overrides one for its category, which overrides the global default.
+package commission
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// Scopes a Rule can apply to.
+const (
+	ScopeGlobal   = "GLOBAL"
+	ScopeStore    = "STORE"
+	ScopeCategory = "CATEGORY"
+)
+
+// A Rule charges Percentage percent of a line's total plus Fixed per line.
+type Rule struct {
+	ID         int64
+	Percentage string // e.g. "7.5" for 7.5%
+	Fixed      money.Amount
+}
+
+// Fee returns the commission on a line worth lineTotal. It never takes
+// more than limit, what the store is owed for the line.
+func (r Rule) Fee(lineTotal, limit money.Amount) (money.Amount, error) {
+	fee := r.Fixed
+	if r.Percentage != "" {
+		cut, err := lineTotal.Percent(r.Percentage)
+		if err != nil {
+			return money.Zero, err
+		}
+		fee += cut
+	}
+
+	if fee < money.Zero {
+		return money.Zero, fmt.Errorf("commission: negative fee %s for rule %d", fee, r.ID)
+	}
+
+	if fee > limit {
+		return limit, nil
+	}
+	return fee, nil
+}
+
+// A Schedule holds the commission rules in force.
+type Schedule struct {
+	Global     *Rule
+	Stores     map[int64]Rule
+	Categories map[string]Rule
+}
+
+// Add adds a rule with the given scope to the schedule.
+func (s *Schedule) Add(scope string, storeID int64, category string, rule Rule) error {
+	switch scope {
+	case ScopeGlobal:
+		s.Global = &rule
+	case ScopeStore:
+		if s.Stores == nil {
+			s.Stores = make(map[int64]Rule)
+		}
+		s.Stores[storeID] = rule
+	case ScopeCategory:
+		if s.Categories == nil {
+			s.Categories = make(map[string]Rule)
+		}
+		s.Categories[category] = rule
+	default:
+		return fmt.Errorf("commission: unknown scope %q", scope)
+	}
+	return nil
+}
+
+// RuleFor returns the rule for a line from storeID in category, and
+// false if no rule applies.
+func (s Schedule) RuleFor(storeID int64, category string) (Rule, bool) {
+	if rule, ok := s.Stores[storeID]; ok {
+		return rule, true
+	}
+	if rule, ok := s.Categories[category]; ok {
+		return rule, true
+	}
+	if s.Global != nil {
+		return *s.Global, true
+	}
+	return Rule{}, false
+}
+
+// Share returns the part of commission that goes with amount out of
+// total, e.g. the commission given back when amount is refunded.
+func Share(commission, amount, total money.Amount) money.Amount {
+	if total <= money.Zero {
+		return money.Zero
+	}
+	if amount >= total {
+		return commission
+	}
+
+	// commission * amount / total, rounded half up
+	share := new(big.Int).Mul(big.NewInt(commission.Minor()), big.NewInt(amount.Minor()))
+	share.Mul(share, big.NewInt(2))
+	share.Add(share, big.NewInt(total.Minor()))
+	share.Quo(share, big.NewInt(2*total.Minor()))
+
+	return money.FromMinor(share.Int64())
+}
@@ -0,0 +1,65 @@
+package commission
+
+import (
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleFee(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rule      Rule
+		lineTotal string
+		limit     string
+		fee       string
+	}{
+		{name: "percentage", rule: Rule{Percentage: "7.5"}, lineTotal: "200", limit: "250", fee: "15.00"},
+		{name: "fixed", rule: Rule{Fixed: money.MustParse("1.50")}, lineTotal: "200", limit: "250", fee: "1.50"},
+		{name: "percentage plus fixed", rule: Rule{Percentage: "5", Fixed: money.MustParse("0.25")}, lineTotal: "33.33", limit: "33.33", fee: "1.92"},
+		{name: "capped", rule: Rule{Percentage: "10", Fixed: money.MustParse("5")}, lineTotal: "4", limit: "4", fee: "4.00"},
+		{name: "no charge", rule: Rule{}, lineTotal: "100", limit: "100", fee: "0.00"},
+	}
+
+	for _, tc := range testCases {
+		fee, err := tc.rule.Fee(money.MustParse(tc.lineTotal), money.MustParse(tc.limit))
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.fee, fee.String(), tc.name)
+	}
+
+	_, err := Rule{Percentage: "ten"}.Fee(money.MustParse("100"), money.MustParse("100"))
+	require.Error(t, err)
+}
+
+func TestScheduleRuleFor(t *testing.T) {
+	var schedule Schedule
+
+	_, ok := schedule.RuleFor(1, "shoes")
+	require.False(t, ok)
+
+	require.NoError(t, schedule.Add(ScopeGlobal, 0, "", Rule{ID: 1, Percentage: "5"}))
+	require.NoError(t, schedule.Add(ScopeCategory, 0, "shoes", Rule{ID: 2, Percentage: "8"}))
+	require.NoError(t, schedule.Add(ScopeStore, 7, "", Rule{ID: 3, Percentage: "2"}))
+	require.Error(t, schedule.Add("STOREFRONT", 7, "", Rule{ID: 4}))
+
+	rule, ok := schedule.RuleFor(7, "shoes")
+	require.True(t, ok)
+	require.Equal(t, int64(3), rule.ID)
+
+	rule, _ = schedule.RuleFor(8, "shoes")
+	require.Equal(t, int64(2), rule.ID)
+
+	rule, _ = schedule.RuleFor(8, "hats")
+	require.Equal(t, int64(1), rule.ID)
+}
+
+func TestShare(t *testing.T) {
+	commission := money.MustParse("10")
+	total := money.MustParse("300")
+
+	require.Equal(t, "3.33", Share(commission, money.MustParse("100"), total).String())
+	require.Equal(t, "6.67", Share(commission, money.MustParse("200"), total).String())
+	require.Equal(t, commission, Share(commission, total, total))
+	require.Equal(t, money.Zero, Share(commission, money.MustParse("1"), money.Zero))
+}
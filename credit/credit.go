@@ -0,0 +1,134 @@
+// Package credit applies gift cards and store credit to what a checkout
+// costs, before the rest is charged through a payment provider, and
+// generates gift card codes.
+//
+// A gift card is bought for a store, and only pays for that store's part
+// of a checkout. Store credit is a buyer's balance with the platform, and
+// pays for any of it.
+package credit
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// ErrCardNotApplicable is returned for a gift card whose store has nothing in the checkout.
+var ErrCardNotApplicable = errors.New("credit: gift card's store has nothing in the checkout")
+
+// A Card is a gift card with Balance left to spend at its store.
+type Card struct {
+	ID      int64
+	Code    string
+	StoreID int64
+	Balance money.Amount
+}
+
+// A CardApplication is what a gift card pays of a checkout.
+type CardApplication struct {
+	CardID  int64        `json:"gift_card_id"`
+	Code    string       `json:"code"`
+	StoreID int64        `json:"store_id"`
+	Amount  money.Amount `json:"amount"`
+}
+
+// An Application is how a checkout is paid for: Cards and StoreCredit
+// first, and Due through the payment provider.
+type Application struct {
+	Cards       []CardApplication `json:"gift_cards"`
+	StoreCredit money.Amount      `json:"store_credit"`
+	Due         money.Amount      `json:"due"`
+}
+
+// Credited is what gift cards and store credit pay of the checkout.
+func (a Application) Credited() money.Amount {
+	total := a.StoreCredit
+	for _, c := range a.Cards {
+		total += c.Amount
+	}
+	return total
+}
+
+// Apply pays for a checkout costing grandTotal, storeTotals of it at each
+// store, with cards in the order given, then with up to storeCredit. A card
+// pays no more than what's left to pay at its store; one left with nothing
+// to pay is skipped.
+func Apply(grandTotal money.Amount, storeTotals map[int64]money.Amount, cards []Card, storeCredit money.Amount) (Application, error) {
+	left := make(map[int64]money.Amount, len(storeTotals))
+	for storeID, total := range storeTotals {
+		left[storeID] = total
+	}
+
+	var a Application
+	due := grandTotal
+	for _, card := range cards {
+		storeLeft, ok := left[card.StoreID]
+		if !ok {
+			return Application{}, fmt.Errorf("%w: %s", ErrCardNotApplicable, card.Code)
+		}
+
+		amount := min(card.Balance, storeLeft, due)
+		if amount <= money.Zero {
+			continue
+		}
+
+		a.Cards = append(a.Cards, CardApplication{
+			CardID:  card.ID,
+			Code:    card.Code,
+			StoreID: card.StoreID,
+			Amount:  amount,
+		})
+		left[card.StoreID] -= amount
+		due -= amount
+	}
+
+	if storeCredit > money.Zero {
+		a.StoreCredit = min(storeCredit, due)
+		due -= a.StoreCredit
+	}
+
+	a.Due = due
+	return a, nil
+}
+
+func min(amounts ...money.Amount) money.Amount {
+	m := amounts[0]
+	for _, a := range amounts[1:] {
+		if a < m {
+			m = a
+		}
+	}
+	return m
+}
+
+// codeAlphabet leaves out characters easily mistaken for one another.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewCode generates a gift card code, such as "GC-7K2M-QX9P-4HTA".
+func NewCode() (string, error) {
+	var b strings.Builder
+	b.WriteString("GC")
+
+	n := big.NewInt(int64(len(codeAlphabet)))
+	for i := 0; i < 12; i++ {
+		if i%4 == 0 {
+			b.WriteByte('-')
+		}
+		c, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(codeAlphabet[c.Int64()])
+	}
+
+	return b.String(), nil
+}
+
+// NormalizeCode puts a code as a buyer typed it in the form it's stored in.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,109 @@
+package credit
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	storeTotals := map[int64]money.Amount{
+		1: money.MustParse("3000"),
+		2: money.MustParse("2000"),
+	}
+	grandTotal := money.MustParse("5000")
+
+	testCases := []struct {
+		name        string
+		cards       []Card
+		storeCredit money.Amount
+		check       func(t *testing.T, a Application)
+	}{
+		{
+			name: "NoCredit",
+			check: func(t *testing.T, a Application) {
+				require.Empty(t, a.Cards)
+				require.Equal(t, money.Zero, a.StoreCredit)
+				require.Equal(t, grandTotal, a.Due)
+			},
+		},
+		{
+			name:  "PartialCard",
+			cards: []Card{{ID: 1, Code: "A", StoreID: 1, Balance: money.MustParse("1000")}},
+			check: func(t *testing.T, a Application) {
+				require.Len(t, a.Cards, 1)
+				require.Equal(t, money.MustParse("1000"), a.Cards[0].Amount)
+				require.Equal(t, money.MustParse("4000"), a.Due)
+			},
+		},
+		{
+			name: "CardCappedAtItsStore",
+			cards: []Card{
+				{ID: 1, Code: "A", StoreID: 2, Balance: money.MustParse("2500")},
+				{ID: 2, Code: "B", StoreID: 2, Balance: money.MustParse("500")},
+			},
+			check: func(t *testing.T, a Application) {
+				// the second card has nothing left to pay at store 2
+				require.Len(t, a.Cards, 1)
+				require.Equal(t, money.MustParse("2000"), a.Cards[0].Amount)
+				require.Equal(t, money.MustParse("3000"), a.Due)
+			},
+		},
+		{
+			name:        "StoreCreditAfterCards",
+			cards:       []Card{{ID: 1, Code: "A", StoreID: 1, Balance: money.MustParse("3000")}},
+			storeCredit: money.MustParse("500"),
+			check: func(t *testing.T, a Application) {
+				require.Equal(t, money.MustParse("500"), a.StoreCredit)
+				require.Equal(t, money.MustParse("3500"), a.Credited())
+				require.Equal(t, money.MustParse("1500"), a.Due)
+			},
+		},
+		{
+			name: "PaidInFull",
+			cards: []Card{
+				{ID: 1, Code: "A", StoreID: 1, Balance: money.MustParse("10000")},
+			},
+			storeCredit: money.MustParse("10000"),
+			check: func(t *testing.T, a Application) {
+				require.Equal(t, money.MustParse("3000"), a.Cards[0].Amount)
+				require.Equal(t, money.MustParse("2000"), a.StoreCredit)
+				require.Equal(t, money.Zero, a.Due)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := Apply(grandTotal, storeTotals, tc.cards, tc.storeCredit)
+			require.NoError(t, err)
+			require.Equal(t, grandTotal, a.Credited()+a.Due)
+			tc.check(t, a)
+		})
+	}
+}
+
+func TestApplyCardNotApplicable(t *testing.T) {
+	_, err := Apply(
+		money.MustParse("100"),
+		map[int64]money.Amount{1: money.MustParse("100")},
+		[]Card{{ID: 1, Code: "A", StoreID: 9, Balance: money.MustParse("50")}},
+		money.Zero,
+	)
+	require.ErrorIs(t, err, ErrCardNotApplicable)
+}
+
+func TestNewCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := NewCode()
+		require.NoError(t, err)
+		require.Regexp(t, regexp.MustCompile(`^GC(-[A-HJ-NP-Z2-9]{4}){3}$`), code)
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+
+	require.Equal(t, "GC-AAAA-BBBB-CCCC", NormalizeCode(" gc-aaaa-bbbb-cccc "))
+}
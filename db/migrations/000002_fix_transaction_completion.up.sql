@@ -0,0 +1,147 @@
+-- UP Migration
+
+-- Function: process_transaction_completion
+-- Description: Process transaction completion for a user's cart items.
+-- Completing an already COMPLETED transaction is a no-op, so a provider
+-- retrying its webhook does not create the orders twice.
+CREATE OR REPLACE FUNCTION process_transaction_completion(
+    p_transaction_ref_id varchar,
+    p_status varchar,
+    p_provider_tx_fee NUMERIC(10, 2),
+    p_cart_items jsonb
+) RETURNS transactions AS $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order_id bigint;
+    v_order_ids bigint[] := '{}';
+    v_cart_item jsonb;
+    v_item items%ROWTYPE;
+    v_store_owner store_owners%ROWTYPE;
+    v_item_total NUMERIC(18, 2);
+    v_store_total record;
+    v_account_type varchar;
+BEGIN
+    -- Get transaction record
+    SELECT * INTO v_transaction
+    FROM transactions
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Transaction not found';
+    END IF;
+
+    IF v_transaction.status IN ('COMPLETED', 'FAILED') THEN
+        RETURN v_transaction;
+    END IF;
+
+    -- Update transaction status and fee
+    UPDATE transactions
+    SET
+        status = p_status,
+        provider_tx_fee = p_provider_tx_fee
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    RETURNING * INTO v_transaction;
+
+    -- If transaction completed successfully, create orders
+    IF p_status = 'COMPLETED' THEN
+        -- Determine account type based on payment provider
+        v_account_type := CASE
+            WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'FIAT'
+            ELSE 'CRYPTO'
+        END;
+
+        -- Initialize store totals map
+        DROP TABLE IF EXISTS store_totals;
+        CREATE TEMPORARY TABLE store_totals (
+            store_id bigint PRIMARY KEY,
+            total_amount NUMERIC(18, 2)
+        ) ON COMMIT DROP;
+
+        -- Process each cart item
+        FOR v_cart_item IN SELECT * FROM jsonb_array_elements(p_cart_items)
+        LOOP
+            -- Get item details
+            SELECT * INTO v_item
+            FROM items
+            WHERE id = (v_cart_item->>'item_id')::bigint;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Item not found: %', (v_cart_item->>'item_id')::bigint;
+            END IF;
+
+            -- Get store owner details
+            SELECT * INTO v_store_owner
+            FROM store_owners
+            WHERE store_id = v_item.store_id AND is_primary = true;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Store owner not found for store: %', v_item.store_id;
+            END IF;
+
+            -- Calculate item total
+            v_item_total := v_item.price * (v_cart_item->>'quantity')::int;
+
+            -- Accumulate store totals
+            INSERT INTO store_totals (store_id, total_amount)
+            VALUES (v_item.store_id, v_item_total)
+            ON CONFLICT (store_id) DO UPDATE
+            SET total_amount = store_totals.total_amount + EXCLUDED.total_amount;
+
+            -- Create order
+            INSERT INTO orders (
+                item_id,
+                item_price,
+                item_currency,
+                order_quantity,
+                buyer_id,
+                seller_id,
+                store_id,
+                delivery_fee,
+                payment_channel,
+                payment_method
+            ) VALUES (
+                v_item.id,
+                v_item.price,
+                v_item.currency,
+                (v_cart_item->>'quantity')::int,
+                v_transaction.customer_id,
+                v_store_owner.user_id,
+                v_item.store_id,
+                COALESCE((v_cart_item->>'delivery_fee')::numeric(10,2), 0),
+                v_account_type,
+                CASE
+                    WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'Instant Pay'
+                    ELSE 'Web Wallet'
+                END
+            ) RETURNING id INTO v_order_id;
+
+            -- Add order ID to array
+            v_order_ids := array_append(v_order_ids, v_order_id);
+
+            -- Update item supply quantity
+            UPDATE items
+            SET supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int
+            WHERE id = v_item.id;
+        END LOOP;
+
+        -- Create/update pending funds for each store
+        FOR v_store_total IN SELECT * FROM store_totals
+        LOOP
+            PERFORM upsert_pending_funds(
+                v_store_total.store_id,
+                v_account_type,
+                v_store_total.total_amount
+            );
+        END LOOP;
+
+        -- Update transaction with order IDs
+        UPDATE transactions
+        SET order_ids = v_order_ids
+        WHERE provider_tx_ref_id = p_transaction_ref_id
+        RETURNING * INTO v_transaction;
+    END IF;
+
+    RETURN v_transaction;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,310 @@
+-- UP Migration
+
+-- Store Delivery Rules Table
+-- A store charges base_fee plus per_item_fee for every unit ordered,
+-- and delivers for free once an order reaches free_delivery_threshold
+-- (0 disables it).
+CREATE TABLE "store_delivery_rules" (
+  "store_id" bigint PRIMARY KEY,
+  "base_fee" NUMERIC(10, 2) NOT NULL DEFAULT 0,
+  "per_item_fee" NUMERIC(10, 2) NOT NULL DEFAULT 0,
+  "free_delivery_threshold" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "store_delivery_rules" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "store_delivery_rules" ADD CONSTRAINT non_negative_delivery_fees CHECK (
+  "base_fee" >= 0 AND "per_item_fee" >= 0 AND "free_delivery_threshold" >= 0
+);
+
+-- Function to create an order, with checks to ensure the supposed referenced rows 
+-- exist in their respective tables. The unit price is computed by the caller,
+-- so discounts apply.
+DROP FUNCTION IF EXISTS create_order(bigint, int, bigint, bigint, bigint, NUMERIC(10, 2), varchar, varchar);
+CREATE OR REPLACE FUNCTION create_order(
+    p_item_id bigint,
+    p_order_quantity int,
+    p_buyer_id bigint,
+    p_seller_id bigint,
+    p_store_id bigint,
+    p_item_price NUMERIC(10, 2),
+    p_delivery_fee NUMERIC(10, 2),
+    p_payment_channel varchar,
+    p_payment_method varchar
+)
+RETURNS orders AS $$
+DECLARE
+    v_item_exists bool;
+    v_buyer_exists bool;
+    v_seller_exists bool;
+    v_store_exists bool;
+    v_item items%ROWTYPE;
+    v_result orders%ROWTYPE;
+BEGIN
+    -- Get item details
+    SELECT * INTO v_item
+    FROM items
+    WHERE id = p_item_id;
+    
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if buyer exists
+    SELECT EXISTS(SELECT 1 FROM users WHERE id = p_buyer_id) INTO v_buyer_exists;
+    IF NOT v_buyer_exists THEN
+        RAISE EXCEPTION 'Buyer with ID % does not exist', p_buyer_id;
+    END IF;
+
+    -- Check if seller exists
+    SELECT EXISTS(SELECT 1 FROM users WHERE id = p_seller_id) INTO v_seller_exists;
+    IF NOT v_seller_exists THEN
+        RAISE EXCEPTION 'Seller with ID % does not exist', p_seller_id;
+    END IF;
+
+    -- Check if store exists
+    SELECT EXISTS(SELECT 1 FROM stores WHERE id = p_store_id) INTO v_store_exists;
+    IF NOT v_store_exists THEN
+        RAISE EXCEPTION 'Store with ID % does not exist', p_store_id;
+    END IF;
+
+    -- If all checks pass, insert the order
+    INSERT INTO orders (
+        item_id,
+        item_price,
+        item_currency,
+        order_quantity,
+        buyer_id,
+        seller_id,
+        store_id,
+        delivery_fee,
+        payment_channel,
+        payment_method
+    ) VALUES (
+        p_item_id, p_item_price, v_item.currency, p_order_quantity, p_buyer_id, p_seller_id, p_store_id, p_delivery_fee, p_payment_channel, p_payment_method
+    ) RETURNING * INTO v_result;
+
+    RETURN v_result;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: process_transaction_completion
+-- Description: Process transaction completion for a user's cart items.
+-- Each cart item carries the unit_price and delivery_fee computed by the
+-- pricing component.
+CREATE OR REPLACE FUNCTION process_transaction_completion(
+    p_transaction_ref_id varchar,
+    p_status varchar,
+    p_provider_tx_fee NUMERIC(10, 2),
+    p_cart_items jsonb
+) RETURNS transactions AS $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order_id bigint;
+    v_order_ids bigint[] := '{}';
+    v_cart_item jsonb;
+    v_item items%ROWTYPE;
+    v_store_owner store_owners%ROWTYPE;
+    v_unit_price NUMERIC(10, 2);
+    v_delivery_fee NUMERIC(10, 2);
+    v_item_total NUMERIC(18, 2);
+    v_store_total record;
+    v_account_type varchar;
+BEGIN
+    -- Get transaction record
+    SELECT * INTO v_transaction
+    FROM transactions
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Transaction not found';
+    END IF;
+
+    IF v_transaction.status IN ('COMPLETED', 'FAILED') THEN
+        RETURN v_transaction;
+    END IF;
+
+    -- Update transaction status and fee
+    UPDATE transactions
+    SET
+        status = p_status,
+        provider_tx_fee = p_provider_tx_fee
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    RETURNING * INTO v_transaction;
+
+    -- If transaction completed successfully, create orders
+    IF p_status = 'COMPLETED' THEN
+        -- Determine account type based on payment provider
+        v_account_type := CASE
+            WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'FIAT'
+            ELSE 'CRYPTO'
+        END;
+
+        -- Initialize store totals map
+        DROP TABLE IF EXISTS store_totals;
+        CREATE TEMPORARY TABLE store_totals (
+            store_id bigint PRIMARY KEY,
+            total_amount NUMERIC(18, 2)
+        ) ON COMMIT DROP;
+
+        -- Process each cart item
+        FOR v_cart_item IN SELECT * FROM jsonb_array_elements(p_cart_items)
+        LOOP
+            -- Get item details
+            SELECT * INTO v_item
+            FROM items
+            WHERE id = (v_cart_item->>'item_id')::bigint;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Item not found: %', (v_cart_item->>'item_id')::bigint;
+            END IF;
+
+            -- Get store owner details
+            SELECT * INTO v_store_owner
+            FROM store_owners
+            WHERE store_id = v_item.store_id AND is_primary = true;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Store owner not found for store: %', v_item.store_id;
+            END IF;
+
+            -- Use the price computed by the pricing component, falling back to the listed price
+            v_unit_price := COALESCE((v_cart_item->>'unit_price')::numeric(10,2), v_item.price);
+            v_delivery_fee := COALESCE((v_cart_item->>'delivery_fee')::numeric(10,2), 0);
+
+            -- Calculate item total, the store is also owed the delivery fee
+            v_item_total := v_unit_price * (v_cart_item->>'quantity')::int + v_delivery_fee;
+
+            -- Accumulate store totals
+            INSERT INTO store_totals (store_id, total_amount)
+            VALUES (v_item.store_id, v_item_total)
+            ON CONFLICT (store_id) DO UPDATE
+            SET total_amount = store_totals.total_amount + EXCLUDED.total_amount;
+
+            -- Create order
+            INSERT INTO orders (
+                item_id,
+                item_price,
+                item_currency,
+                order_quantity,
+                buyer_id,
+                seller_id,
+                store_id,
+                delivery_fee,
+                payment_channel,
+                payment_method
+            ) VALUES (
+                v_item.id,
+                v_unit_price,
+                v_item.currency,
+                (v_cart_item->>'quantity')::int,
+                v_transaction.customer_id,
+                v_store_owner.user_id,
+                v_item.store_id,
+                v_delivery_fee,
+                v_account_type,
+                CASE
+                    WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'Instant Pay'
+                    ELSE 'Web Wallet'
+                END
+            ) RETURNING id INTO v_order_id;
+
+            -- Add order ID to array
+            v_order_ids := array_append(v_order_ids, v_order_id);
+
+            -- Update item supply quantity
+            UPDATE items
+            SET supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int
+            WHERE id = v_item.id;
+        END LOOP;
+
+        -- Create/update pending funds for each store
+        FOR v_store_total IN SELECT * FROM store_totals
+        LOOP
+            PERFORM upsert_pending_funds(
+                v_store_total.store_id,
+                v_account_type,
+                v_store_total.total_amount
+            );
+        END LOOP;
+
+        -- Update transaction with order IDs
+        UPDATE transactions
+        SET order_ids = v_order_ids
+        WHERE provider_tx_ref_id = p_transaction_ref_id
+        RETURNING * INTO v_transaction;
+    END IF;
+
+    RETURN v_transaction;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: release_pending_funds
+-- Description: Releases funds for a fulfilled order to appropriate store's account
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS void AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate order total, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity + v_order.delivery_fee;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Update store account
+    IF v_account_type = 'FIAT' THEN
+        UPDATE fiat_accounts
+        SET balance = balance + v_total
+        WHERE store_id = v_order.store_id;
+    ELSE
+        UPDATE crypto_accounts
+        SET balance = balance + v_total
+        WHERE store_id = v_order.store_id;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+-- DOWN Migration
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "provider_tx_hash";
@@ -0,0 +1,5 @@
+-- UP Migration
+
+-- The hash of the on-chain transaction that paid a NEAR_WALLET transaction.
+-- It is unique, so one payment can't settle two checkouts.
+ALTER TABLE "transactions" ADD COLUMN "provider_tx_hash" varchar UNIQUE;
@@ -0,0 +1,75 @@
+-- DOWN Migration
+
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS void AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate order total, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity + v_order.delivery_fee;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Update store account
+    IF v_account_type = 'FIAT' THEN
+        UPDATE fiat_accounts
+        SET balance = balance + v_total
+        WHERE store_id = v_order.store_id;
+    ELSE
+        UPDATE crypto_accounts
+        SET balance = balance + v_total
+        WHERE store_id = v_order.store_id;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS "withdrawal_requests";
+DROP INDEX IF EXISTS "transactions_order_ids_idx";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "funds_released_at";
+ALTER TABLE "crypto_accounts" DROP CONSTRAINT IF EXISTS "unique_store_crypto_account";
+ALTER TABLE "fiat_accounts" DROP CONSTRAINT IF EXISTS "unique_store_fiat_account";
@@ -0,0 +1,130 @@
+-- UP Migration
+
+-- A store holds one fiat and one crypto account, created on its first payout.
+ALTER TABLE "fiat_accounts" ADD CONSTRAINT unique_store_fiat_account UNIQUE ("store_id");
+ALTER TABLE "crypto_accounts" ADD CONSTRAINT unique_store_crypto_account UNIQUE ("store_id");
+
+-- When an order's funds moved from pending into the store's account.
+ALTER TABLE "orders" ADD COLUMN "funds_released_at" timestamptz;
+
+-- Lets release_pending_funds find the transaction that paid for an order.
+CREATE INDEX ON "transactions" USING GIN ("order_ids");
+
+-- Withdrawal Requests Table
+-- The amount is held from the store's account when requested, and given
+-- back if the request is cancelled or rejected.
+CREATE TABLE "withdrawal_requests" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "account_type" varchar NOT NULL,
+  "amount" NUMERIC(18, 2) NOT NULL,
+  "destination" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "requested_by" bigint NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "withdrawal_requests" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "withdrawal_requests" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("id");
+ALTER TABLE "withdrawal_requests" ADD CONSTRAINT valid_withdrawal_account_type CHECK ("account_type" IN (
+  'FIAT', 'CRYPTO'
+));
+ALTER TABLE "withdrawal_requests" ADD CONSTRAINT valid_withdrawal_status CHECK ("status" IN (
+  'PENDING', 'PAID', 'REJECTED', 'CANCELLED'
+));
+ALTER TABLE "withdrawal_requests" ADD CONSTRAINT positive_withdrawal_amount CHECK ("amount" > 0);
+CREATE INDEX ON "withdrawal_requests" ("store_id", "status");
+
+-- Function: release_pending_funds
+-- Description: Releases funds for a delivered order to the appropriate store's account.
+-- Orders not paid through a transaction hold no funds, and an order's funds are
+-- released only once.
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS void AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    IF v_order.funds_released_at IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM transactions
+        WHERE order_ids @> ARRAY[v_order.id]
+        AND status = 'COMPLETED'
+    ) THEN
+        RETURN;
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate order total, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity + v_order.delivery_fee;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Credit store account, creating it on the first payout
+    IF v_account_type = 'FIAT' THEN
+        INSERT INTO fiat_accounts (store_id, balance, currency)
+        VALUES (v_order.store_id, v_total, v_order.item_currency)
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = fiat_accounts.balance + EXCLUDED.balance;
+    ELSE
+        INSERT INTO crypto_accounts (store_id, balance, wallet_address, crypto_type)
+        SELECT v_order.store_id, v_total, s.store_account_id, 'NEAR'
+        FROM stores s
+        WHERE s.id = v_order.store_id
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = crypto_accounts.balance + EXCLUDED.balance;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+
+    UPDATE orders
+    SET funds_released_at = now()
+    WHERE id = v_order.id;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,102 @@
+-- DOWN Migration
+
+DROP FUNCTION IF EXISTS release_pending_funds(bigint);
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS void AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    IF v_order.funds_released_at IS NOT NULL THEN
+        RETURN;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM transactions
+        WHERE order_ids @> ARRAY[v_order.id]
+        AND status = 'COMPLETED'
+    ) THEN
+        RETURN;
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate order total, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity + v_order.delivery_fee;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Credit store account, creating it on the first payout
+    IF v_account_type = 'FIAT' THEN
+        INSERT INTO fiat_accounts (store_id, balance, currency)
+        VALUES (v_order.store_id, v_total, v_order.item_currency)
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = fiat_accounts.balance + EXCLUDED.balance;
+    ELSE
+        INSERT INTO crypto_accounts (store_id, balance, wallet_address, crypto_type)
+        SELECT v_order.store_id, v_total, s.store_account_id, 'NEAR'
+        FROM stores s
+        WHERE s.id = v_order.store_id
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = crypto_accounts.balance + EXCLUDED.balance;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+
+    UPDATE orders
+    SET funds_released_at = now()
+    WHERE id = v_order.id;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_ledger_postings_append_only ON ledger_postings;
+DROP TRIGGER IF EXISTS trigger_journal_entries_append_only ON journal_entries;
+DROP TRIGGER IF EXISTS trigger_journal_entry_balanced ON ledger_postings;
+DROP FUNCTION IF EXISTS prevent_ledger_changes();
+DROP FUNCTION IF EXISTS check_journal_entry_balanced();
+DROP FUNCTION IF EXISTS ensure_ledger_account(varchar, varchar, bigint);
+
+DROP TABLE IF EXISTS "ledger_postings";
+DROP TABLE IF EXISTS "journal_entries";
+DROP TABLE IF EXISTS "ledger_accounts";
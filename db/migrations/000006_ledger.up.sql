@@ -0,0 +1,251 @@
+-- UP Migration
+
+-- Ledger Accounts Table
+-- Accounts are identified by a code, such as "platform:commission" or
+-- "store:12:pending:FIAT". Platform accounts have no store.
+CREATE TABLE "ledger_accounts" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar UNIQUE NOT NULL,
+  "type" varchar NOT NULL,
+  "store_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "ledger_accounts" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id");
+ALTER TABLE "ledger_accounts" ADD CONSTRAINT valid_ledger_account_type CHECK ("type" IN (
+  'ASSET', 'LIABILITY', 'EQUITY', 'REVENUE', 'EXPENSE'
+));
+CREATE INDEX ON "ledger_accounts" ("store_id");
+
+-- Journal Entries Table
+-- The reference ties an entry to what caused it, such as a transaction's
+-- provider_tx_ref_id or "order:42".
+CREATE TABLE "journal_entries" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "reference" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+CREATE INDEX ON "journal_entries" ("reference");
+
+-- Ledger Postings Table
+-- Debits are positive amounts and credits negative; an entry's postings sum to zero.
+CREATE TABLE "ledger_postings" (
+  "id" bigserial PRIMARY KEY,
+  "journal_entry_id" bigint NOT NULL,
+  "ledger_account_id" bigint NOT NULL,
+  "amount" NUMERIC(18, 2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "ledger_postings" ADD FOREIGN KEY ("journal_entry_id") REFERENCES "journal_entries" ("id");
+ALTER TABLE "ledger_postings" ADD FOREIGN KEY ("ledger_account_id") REFERENCES "ledger_accounts" ("id");
+ALTER TABLE "ledger_postings" ADD CONSTRAINT non_zero_posting CHECK ("amount" <> 0);
+CREATE INDEX ON "ledger_postings" ("journal_entry_id");
+CREATE INDEX ON "ledger_postings" ("ledger_account_id");
+
+-- Function: ensure_ledger_account
+-- Description: Returns the id of the ledger account with the given code, creating it if needed.
+CREATE OR REPLACE FUNCTION ensure_ledger_account(
+    p_code varchar,
+    p_type varchar,
+    p_store_id bigint
+) RETURNS bigint AS $$
+DECLARE
+    v_id bigint;
+BEGIN
+    INSERT INTO ledger_accounts (code, type, store_id)
+    VALUES (p_code, p_type, p_store_id)
+    ON CONFLICT (code) DO NOTHING;
+
+    SELECT id INTO v_id
+    FROM ledger_accounts
+    WHERE code = p_code;
+
+    RETURN v_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: check_journal_entry_balanced
+-- Description: Checked at commit, so all of an entry's postings are in.
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (
+        SELECT SUM(amount)
+        FROM ledger_postings
+        WHERE journal_entry_id = NEW.journal_entry_id
+    ) <> 0 THEN
+        RAISE EXCEPTION 'Journal entry % is unbalanced', NEW.journal_entry_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trigger_journal_entry_balanced
+AFTER INSERT ON ledger_postings
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW
+EXECUTE FUNCTION check_journal_entry_balanced();
+
+-- Function: prevent_ledger_changes
+-- Description: The ledger is append-only, mistakes are corrected with a reversing entry.
+CREATE OR REPLACE FUNCTION prevent_ledger_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'The ledger is append-only, post a reversing entry instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_journal_entries_append_only
+BEFORE UPDATE OR DELETE ON journal_entries
+FOR EACH ROW
+EXECUTE FUNCTION prevent_ledger_changes();
+
+CREATE TRIGGER trigger_ledger_postings_append_only
+BEFORE UPDATE OR DELETE ON ledger_postings
+FOR EACH ROW
+EXECUTE FUNCTION prevent_ledger_changes();
+
+-- Open the ledger with the balances stores already hold.
+DO $$
+DECLARE
+    v_balance record;
+    v_entry_id bigint;
+BEGIN
+    FOR v_balance IN
+        SELECT store_id, 'pending' AS bucket, account_type, amount
+        FROM pending_transaction_funds
+        WHERE amount <> 0
+        UNION ALL
+        SELECT store_id, 'available', 'FIAT', balance
+        FROM fiat_accounts
+        WHERE balance <> 0
+        UNION ALL
+        SELECT store_id, 'available', 'CRYPTO', balance::NUMERIC(18, 2)
+        FROM crypto_accounts
+        WHERE balance <> 0
+        UNION ALL
+        SELECT store_id, 'withdrawing', account_type, SUM(amount)
+        FROM withdrawal_requests
+        WHERE status = 'PENDING'
+        GROUP BY store_id, account_type
+    LOOP
+        INSERT INTO journal_entries (kind, reference, description)
+        VALUES ('OPENING_BALANCE', 'store:' || v_balance.store_id, 'Balance held before the ledger')
+        RETURNING id INTO v_entry_id;
+
+        INSERT INTO ledger_postings (journal_entry_id, ledger_account_id, amount)
+        VALUES
+            (
+                v_entry_id,
+                ensure_ledger_account(
+                    format('store:%s:%s:%s', v_balance.store_id, v_balance.bucket, v_balance.account_type),
+                    'LIABILITY',
+                    v_balance.store_id
+                ),
+                -v_balance.amount
+            ),
+            (
+                v_entry_id,
+                ensure_ledger_account('platform:opening_balances', 'EQUITY', NULL),
+                v_balance.amount
+            );
+    END LOOP;
+END;
+$$;
+
+-- Function: release_pending_funds
+-- Description: Releases funds for a delivered order to the appropriate store's account,
+-- returning the amount released (0 if the order holds none) for the ledger.
+DROP FUNCTION IF EXISTS release_pending_funds(bigint);
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS NUMERIC(18, 2) AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    IF v_order.funds_released_at IS NOT NULL THEN
+        RETURN 0;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM transactions
+        WHERE order_ids @> ARRAY[v_order.id]
+        AND status = 'COMPLETED'
+    ) THEN
+        RETURN 0;
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate order total, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity + v_order.delivery_fee;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Credit store account, creating it on the first payout
+    IF v_account_type = 'FIAT' THEN
+        INSERT INTO fiat_accounts (store_id, balance, currency)
+        VALUES (v_order.store_id, v_total, v_order.item_currency)
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = fiat_accounts.balance + EXCLUDED.balance;
+    ELSE
+        INSERT INTO crypto_accounts (store_id, balance, wallet_address, crypto_type)
+        SELECT v_order.store_id, v_total, s.store_account_id, 'NEAR'
+        FROM stores s
+        WHERE s.id = v_order.store_id
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = crypto_accounts.balance + EXCLUDED.balance;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+
+    UPDATE orders
+    SET funds_released_at = now()
+    WHERE id = v_order.id;
+
+    RETURN v_total;
+END;
+$$ LANGUAGE plpgsql;
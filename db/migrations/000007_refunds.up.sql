@@ -0,0 +1,33 @@
+-- UP Migration
+
+-- Refunds Table
+-- A refund returns part or all of an order's total to the buyer, through
+-- the provider of the transaction that paid for it. It is PENDING while the
+-- provider is asked, and COMPLETED once the store's funds are reversed and
+-- restock_quantity units are back in supply.
+CREATE TABLE "refunds" (
+  "id" bigserial PRIMARY KEY,
+  "order_id" bigint NOT NULL,
+  "transaction_id" bigint NOT NULL,
+  "store_id" bigint NOT NULL,
+  "amount" NUMERIC(18, 2) NOT NULL,
+  "restock_quantity" int NOT NULL DEFAULT 0,
+  "reason" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "provider_refund_id" varchar NOT NULL DEFAULT '',
+  "failure_reason" varchar NOT NULL DEFAULT '',
+  "requested_by" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "refunds" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id");
+ALTER TABLE "refunds" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "refunds" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id");
+ALTER TABLE "refunds" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("id");
+ALTER TABLE "refunds" ADD CONSTRAINT valid_refund_status CHECK ("status" IN (
+  'PENDING', 'COMPLETED', 'FAILED'
+));
+ALTER TABLE "refunds" ADD CONSTRAINT positive_refund_amount CHECK ("amount" > 0);
+ALTER TABLE "refunds" ADD CONSTRAINT non_negative_restock_quantity CHECK ("restock_quantity" >= 0);
+CREATE INDEX ON "refunds" ("order_id");
+CREATE INDEX ON "refunds" ("transaction_id");
@@ -0,0 +1,256 @@
+-- DOWN Migration
+
+-- Function: process_transaction_completion
+-- Description: Process transaction completion for a user's cart items.
+-- Each cart item carries the unit_price and delivery_fee computed by the
+-- pricing component.
+CREATE OR REPLACE FUNCTION process_transaction_completion(
+    p_transaction_ref_id varchar,
+    p_status varchar,
+    p_provider_tx_fee NUMERIC(10, 2),
+    p_cart_items jsonb
+) RETURNS transactions AS $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order_id bigint;
+    v_order_ids bigint[] := '{}';
+    v_cart_item jsonb;
+    v_item items%ROWTYPE;
+    v_store_owner store_owners%ROWTYPE;
+    v_unit_price NUMERIC(10, 2);
+    v_delivery_fee NUMERIC(10, 2);
+    v_item_total NUMERIC(18, 2);
+    v_store_total record;
+    v_account_type varchar;
+BEGIN
+    -- Get transaction record
+    SELECT * INTO v_transaction
+    FROM transactions
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Transaction not found';
+    END IF;
+
+    IF v_transaction.status IN ('COMPLETED', 'FAILED') THEN
+        RETURN v_transaction;
+    END IF;
+
+    -- Update transaction status and fee
+    UPDATE transactions
+    SET
+        status = p_status,
+        provider_tx_fee = p_provider_tx_fee
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    RETURNING * INTO v_transaction;
+
+    -- If transaction completed successfully, create orders
+    IF p_status = 'COMPLETED' THEN
+        -- Determine account type based on payment provider
+        v_account_type := CASE
+            WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'FIAT'
+            ELSE 'CRYPTO'
+        END;
+
+        -- Initialize store totals map
+        DROP TABLE IF EXISTS store_totals;
+        CREATE TEMPORARY TABLE store_totals (
+            store_id bigint PRIMARY KEY,
+            total_amount NUMERIC(18, 2)
+        ) ON COMMIT DROP;
+
+        -- Process each cart item
+        FOR v_cart_item IN SELECT * FROM jsonb_array_elements(p_cart_items)
+        LOOP
+            -- Get item details
+            SELECT * INTO v_item
+            FROM items
+            WHERE id = (v_cart_item->>'item_id')::bigint;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Item not found: %', (v_cart_item->>'item_id')::bigint;
+            END IF;
+
+            -- Get store owner details
+            SELECT * INTO v_store_owner
+            FROM store_owners
+            WHERE store_id = v_item.store_id AND is_primary = true;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Store owner not found for store: %', v_item.store_id;
+            END IF;
+
+            -- Use the price computed by the pricing component, falling back to the listed price
+            v_unit_price := COALESCE((v_cart_item->>'unit_price')::numeric(10,2), v_item.price);
+            v_delivery_fee := COALESCE((v_cart_item->>'delivery_fee')::numeric(10,2), 0);
+
+            -- Calculate item total, the store is also owed the delivery fee
+            v_item_total := v_unit_price * (v_cart_item->>'quantity')::int + v_delivery_fee;
+
+            -- Accumulate store totals
+            INSERT INTO store_totals (store_id, total_amount)
+            VALUES (v_item.store_id, v_item_total)
+            ON CONFLICT (store_id) DO UPDATE
+            SET total_amount = store_totals.total_amount + EXCLUDED.total_amount;
+
+            -- Create order
+            INSERT INTO orders (
+                item_id,
+                item_price,
+                item_currency,
+                order_quantity,
+                buyer_id,
+                seller_id,
+                store_id,
+                delivery_fee,
+                payment_channel,
+                payment_method
+            ) VALUES (
+                v_item.id,
+                v_unit_price,
+                v_item.currency,
+                (v_cart_item->>'quantity')::int,
+                v_transaction.customer_id,
+                v_store_owner.user_id,
+                v_item.store_id,
+                v_delivery_fee,
+                v_account_type,
+                CASE
+                    WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'Instant Pay'
+                    ELSE 'Web Wallet'
+                END
+            ) RETURNING id INTO v_order_id;
+
+            -- Add order ID to array
+            v_order_ids := array_append(v_order_ids, v_order_id);
+
+            -- Update item supply quantity
+            UPDATE items
+            SET supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int
+            WHERE id = v_item.id;
+        END LOOP;
+
+        -- Create/update pending funds for each store
+        FOR v_store_total IN SELECT * FROM store_totals
+        LOOP
+            PERFORM upsert_pending_funds(
+                v_store_total.store_id,
+                v_account_type,
+                v_store_total.total_amount
+            );
+        END LOOP;
+
+        -- Update transaction with order IDs
+        UPDATE transactions
+        SET order_ids = v_order_ids
+        WHERE provider_tx_ref_id = p_transaction_ref_id
+        RETURNING * INTO v_transaction;
+    END IF;
+
+    RETURN v_transaction;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: release_pending_funds
+-- Description: Releases funds for a delivered order to the appropriate store's account,
+-- returning the amount released (0 if the order holds none) for the ledger.
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS NUMERIC(18, 2) AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    IF v_order.funds_released_at IS NOT NULL THEN
+        RETURN 0;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM transactions
+        WHERE order_ids @> ARRAY[v_order.id]
+        AND status = 'COMPLETED'
+    ) THEN
+        RETURN 0;
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate order total, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity + v_order.delivery_fee;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Credit store account, creating it on the first payout
+    IF v_account_type = 'FIAT' THEN
+        INSERT INTO fiat_accounts (store_id, balance, currency)
+        VALUES (v_order.store_id, v_total, v_order.item_currency)
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = fiat_accounts.balance + EXCLUDED.balance;
+    ELSE
+        INSERT INTO crypto_accounts (store_id, balance, wallet_address, crypto_type)
+        SELECT v_order.store_id, v_total, s.store_account_id, 'NEAR'
+        FROM stores s
+        WHERE s.id = v_order.store_id
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = crypto_accounts.balance + EXCLUDED.balance;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+
+    UPDATE orders
+    SET funds_released_at = now()
+    WHERE id = v_order.id;
+
+    RETURN v_total;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "refunds" DROP COLUMN IF EXISTS "commission_amount";
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "commission_amount";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "commission_fixed";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "commission_percentage";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "commission_rule_id";
+
+DROP TABLE IF EXISTS "commission_rules";
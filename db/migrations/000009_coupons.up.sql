@@ -0,0 +1,359 @@
+-- UP Migration
+
+-- Coupons Table
+-- A coupon takes discount_value percent (PERCENT) or discount_value off (FIXED)
+-- the items it covers in its store: every item (STORE), the items in item_ids
+-- (ITEM), or the items in category (CATEGORY). It applies between starts_at and
+-- ends_at once min_spend is spent on covered items, and can be redeemed
+-- usage_limit times overall and per_user_limit times by each buyer (0 is no limit).
+CREATE TABLE "coupons" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "code" varchar NOT NULL UNIQUE,
+  "description" varchar NOT NULL DEFAULT '',
+  "discount_type" varchar NOT NULL,
+  "discount_value" NUMERIC(10, 2) NOT NULL,
+  "scope" varchar NOT NULL DEFAULT 'STORE',
+  "item_ids" bigint[] NOT NULL DEFAULT '{}',
+  "category" varchar NOT NULL DEFAULT '',
+  "min_spend" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "usage_limit" int NOT NULL DEFAULT 0,
+  "per_user_limit" int NOT NULL DEFAULT 0,
+  "starts_at" timestamptz NOT NULL DEFAULT (now()),
+  "ends_at" timestamptz,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_by" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "coupons" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "coupons" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "coupons" ADD CONSTRAINT uppercase_coupon_code CHECK ("code" = upper("code") AND "code" <> '');
+ALTER TABLE "coupons" ADD CONSTRAINT valid_coupon_discount CHECK (
+  ("discount_type" = 'PERCENT' AND "discount_value" > 0 AND "discount_value" <= 100) OR
+  ("discount_type" = 'FIXED' AND "discount_value" > 0)
+);
+ALTER TABLE "coupons" ADD CONSTRAINT valid_coupon_scope CHECK (
+  ("scope" = 'STORE') OR
+  ("scope" = 'ITEM' AND cardinality("item_ids") > 0) OR
+  ("scope" = 'CATEGORY' AND "category" <> '')
+);
+ALTER TABLE "coupons" ADD CONSTRAINT valid_coupon_limits CHECK (
+  "min_spend" >= 0 AND "usage_limit" >= 0 AND "per_user_limit" >= 0
+);
+ALTER TABLE "coupons" ADD CONSTRAINT valid_coupon_window CHECK ("ends_at" IS NULL OR "ends_at" > "starts_at");
+CREATE INDEX ON "coupons" ("store_id");
+
+-- Cart Coupons Table
+-- The coupons a buyer applied to their cart, at most one per store.
+CREATE TABLE "cart_coupons" (
+  "cart_id" bigint NOT NULL,
+  "coupon_id" bigint NOT NULL,
+  "store_id" bigint NOT NULL,
+  "applied_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("cart_id", "store_id")
+);
+ALTER TABLE "cart_coupons" ADD FOREIGN KEY ("cart_id") REFERENCES "carts" ("id") ON DELETE CASCADE;
+ALTER TABLE "cart_coupons" ADD FOREIGN KEY ("coupon_id") REFERENCES "coupons" ("id") ON DELETE CASCADE;
+
+-- Coupon Redemptions Table
+-- A coupon is RESERVED for a transaction at checkout, counting towards its
+-- limits, then REDEEMED when the transaction completes or RELEASED if it fails.
+CREATE TABLE "coupon_redemptions" (
+  "id" bigserial PRIMARY KEY,
+  "coupon_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "reference" varchar NOT NULL,
+  "discount_amount" NUMERIC(18, 2) NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'RESERVED',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "coupon_redemptions" ADD FOREIGN KEY ("coupon_id") REFERENCES "coupons" ("id");
+ALTER TABLE "coupon_redemptions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "coupon_redemptions" ADD CONSTRAINT valid_coupon_redemption_status CHECK ("status" IN (
+  'RESERVED', 'REDEEMED', 'RELEASED'
+));
+ALTER TABLE "coupon_redemptions" ADD CONSTRAINT unique_coupon_redemption UNIQUE ("coupon_id", "reference");
+CREATE INDEX ON "coupon_redemptions" ("reference");
+CREATE INDEX ON "coupon_redemptions" ("coupon_id", "user_id");
+
+-- The coupon applied to an order, and what it took off.
+ALTER TABLE "orders" ADD COLUMN "coupon_id" bigint;
+ALTER TABLE "orders" ADD COLUMN "discount_amount" NUMERIC(18, 2) NOT NULL DEFAULT 0;
+ALTER TABLE "orders" ADD FOREIGN KEY ("coupon_id") REFERENCES "coupons" ("id") ON DELETE SET NULL;
+
+-- Function: process_transaction_completion
+-- Description: Process transaction completion for a user's cart items.
+-- Each cart item carries the unit_price and delivery_fee computed by the
+-- pricing component, the discount taken off by a coupon, and the commission
+-- the platform takes from the line; the store's pending funds are credited
+-- with the rest.
+CREATE OR REPLACE FUNCTION process_transaction_completion(
+    p_transaction_ref_id varchar,
+    p_status varchar,
+    p_provider_tx_fee NUMERIC(10, 2),
+    p_cart_items jsonb
+) RETURNS transactions AS $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order_id bigint;
+    v_order_ids bigint[] := '{}';
+    v_cart_item jsonb;
+    v_item items%ROWTYPE;
+    v_store_owner store_owners%ROWTYPE;
+    v_unit_price NUMERIC(10, 2);
+    v_delivery_fee NUMERIC(10, 2);
+    v_discount NUMERIC(18, 2);
+    v_item_total NUMERIC(18, 2);
+    v_commission NUMERIC(18, 2);
+    v_store_total record;
+    v_account_type varchar;
+BEGIN
+    -- Get transaction record
+    SELECT * INTO v_transaction
+    FROM transactions
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Transaction not found';
+    END IF;
+
+    IF v_transaction.status IN ('COMPLETED', 'FAILED') THEN
+        RETURN v_transaction;
+    END IF;
+
+    -- Update transaction status and fee
+    UPDATE transactions
+    SET
+        status = p_status,
+        provider_tx_fee = p_provider_tx_fee
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    RETURNING * INTO v_transaction;
+
+    -- If transaction completed successfully, create orders
+    IF p_status = 'COMPLETED' THEN
+        -- Determine account type based on payment provider
+        v_account_type := CASE
+            WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'FIAT'
+            ELSE 'CRYPTO'
+        END;
+
+        -- Initialize store totals map
+        DROP TABLE IF EXISTS store_totals;
+        CREATE TEMPORARY TABLE store_totals (
+            store_id bigint PRIMARY KEY,
+            total_amount NUMERIC(18, 2)
+        ) ON COMMIT DROP;
+
+        -- Process each cart item
+        FOR v_cart_item IN SELECT * FROM jsonb_array_elements(p_cart_items)
+        LOOP
+            -- Get item details
+            SELECT * INTO v_item
+            FROM items
+            WHERE id = (v_cart_item->>'item_id')::bigint;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Item not found: %', (v_cart_item->>'item_id')::bigint;
+            END IF;
+
+            -- Get store owner details
+            SELECT * INTO v_store_owner
+            FROM store_owners
+            WHERE store_id = v_item.store_id AND is_primary = true;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Store owner not found for store: %', v_item.store_id;
+            END IF;
+
+            -- Use the price computed by the pricing component, falling back to the listed price
+            v_unit_price := COALESCE((v_cart_item->>'unit_price')::numeric(10,2), v_item.price);
+            v_delivery_fee := COALESCE((v_cart_item->>'delivery_fee')::numeric(10,2), 0);
+
+            -- Calculate item total less the coupon discount, the store is also owed the delivery fee
+            v_discount := COALESCE((v_cart_item->>'discount')::numeric(18,2), 0);
+            v_item_total := v_unit_price * (v_cart_item->>'quantity')::int - v_discount + v_delivery_fee;
+
+            IF v_discount < 0 OR v_item_total < v_delivery_fee THEN
+                RAISE EXCEPTION 'Invalid discount % for item %', v_discount, v_item.id;
+            END IF;
+            v_commission := COALESCE((v_cart_item->>'commission')::numeric(18,2), 0);
+
+            IF v_commission < 0 OR v_commission > v_item_total THEN
+                RAISE EXCEPTION 'Invalid commission % for item %', v_commission, v_item.id;
+            END IF;
+
+            -- Accumulate store totals, less the platform's commission
+            INSERT INTO store_totals (store_id, total_amount)
+            VALUES (v_item.store_id, v_item_total - v_commission)
+            ON CONFLICT (store_id) DO UPDATE
+            SET total_amount = store_totals.total_amount + EXCLUDED.total_amount;
+
+            -- Create order
+            INSERT INTO orders (
+                item_id,
+                item_price,
+                item_currency,
+                order_quantity,
+                buyer_id,
+                seller_id,
+                store_id,
+                delivery_fee,
+                payment_channel,
+                payment_method,
+                commission_rule_id,
+                commission_percentage,
+                commission_fixed,
+                commission_amount,
+                coupon_id,
+                discount_amount
+            ) VALUES (
+                v_item.id,
+                v_unit_price,
+                v_item.currency,
+                (v_cart_item->>'quantity')::int,
+                v_transaction.customer_id,
+                v_store_owner.user_id,
+                v_item.store_id,
+                v_delivery_fee,
+                v_account_type,
+                CASE
+                    WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'Instant Pay'
+                    ELSE 'Web Wallet'
+                END,
+                (v_cart_item->>'commission_rule_id')::bigint,
+                COALESCE((v_cart_item->>'commission_percentage')::numeric(5,2), 0),
+                COALESCE((v_cart_item->>'commission_fixed')::numeric(10,2), 0),
+                v_commission,
+                (v_cart_item->>'coupon_id')::bigint,
+                v_discount
+            ) RETURNING id INTO v_order_id;
+
+            -- Add order ID to array
+            v_order_ids := array_append(v_order_ids, v_order_id);
+
+            -- Update item supply quantity
+            UPDATE items
+            SET supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int
+            WHERE id = v_item.id;
+        END LOOP;
+
+        -- Create/update pending funds for each store
+        FOR v_store_total IN SELECT * FROM store_totals
+        LOOP
+            PERFORM upsert_pending_funds(
+                v_store_total.store_id,
+                v_account_type,
+                v_store_total.total_amount
+            );
+        END LOOP;
+
+        -- Update transaction with order IDs
+        UPDATE transactions
+        SET order_ids = v_order_ids
+        WHERE provider_tx_ref_id = p_transaction_ref_id
+        RETURNING * INTO v_transaction;
+    END IF;
+
+    RETURN v_transaction;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: release_pending_funds
+-- Description: Releases funds for a delivered order to the appropriate store's account,
+-- returning the amount released (0 if the order holds none) for the ledger. Coupon
+-- discounts were never paid, and the platform's commission was never held for the
+-- store, so neither is released.
+CREATE OR REPLACE FUNCTION release_pending_funds(
+    p_order_id bigint
+) RETURNS NUMERIC(18, 2) AS $$
+DECLARE
+    v_order orders%ROWTYPE;
+    v_pending pending_transaction_funds%ROWTYPE;
+    v_account_type varchar;
+    v_total NUMERIC(18, 2);
+BEGIN
+    -- Get order details
+    SELECT * INTO v_order
+    FROM orders
+    WHERE id = p_order_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Order not found';
+    END IF;
+
+    -- Check if order is delivered
+    IF v_order.delivery_status != 'DELIVERED' THEN
+        RAISE EXCEPTION 'Cannot release funds for undelivered order';
+    END IF;
+
+    IF v_order.funds_released_at IS NOT NULL THEN
+        RETURN 0;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM transactions
+        WHERE order_ids @> ARRAY[v_order.id]
+        AND status = 'COMPLETED'
+    ) THEN
+        RETURN 0;
+    END IF;
+
+    -- Determine account type
+    v_account_type := CASE 
+        WHEN v_order.payment_channel = 'FIAT' THEN 'FIAT'
+        ELSE 'CRYPTO'
+    END;
+
+    -- Get pending funds record
+    SELECT * INTO v_pending
+    FROM pending_transaction_funds
+    WHERE store_id = v_order.store_id
+    AND account_type = v_account_type
+    FOR UPDATE;  -- Lock row for update
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'No pending funds found for store';
+    END IF;
+
+    -- Calculate the store's share of the order, including the delivery fee held with it
+    v_total := v_order.item_price * v_order.order_quantity - v_order.discount_amount
+        + v_order.delivery_fee - v_order.commission_amount;
+
+    -- Ensure sufficient pending funds
+    IF v_pending.amount < v_total THEN
+        RAISE EXCEPTION 'Insufficient pending funds';
+    END IF;
+
+    -- Credit store account, creating it on the first payout
+    IF v_account_type = 'FIAT' THEN
+        INSERT INTO fiat_accounts (store_id, balance, currency)
+        VALUES (v_order.store_id, v_total, v_order.item_currency)
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = fiat_accounts.balance + EXCLUDED.balance;
+    ELSE
+        INSERT INTO crypto_accounts (store_id, balance, wallet_address, crypto_type)
+        SELECT v_order.store_id, v_total, s.store_account_id, 'NEAR'
+        FROM stores s
+        WHERE s.id = v_order.store_id
+        ON CONFLICT (store_id) DO UPDATE
+        SET balance = crypto_accounts.balance + EXCLUDED.balance;
+    END IF;
+
+    -- Reduce pending funds
+    UPDATE pending_transaction_funds
+    SET 
+        amount = amount - v_total,
+        updated_at = now()
+    WHERE id = v_pending.id;
+
+    UPDATE orders
+    SET funds_released_at = now()
+    WHERE id = v_order.id;
+
+    RETURN v_total;
+END;
+$$ LANGUAGE plpgsql;
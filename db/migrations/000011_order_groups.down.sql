@@ -0,0 +1,7 @@
+-- DOWN Migration
+
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "fulfilment_group_id";
+
+DROP TABLE IF EXISTS "fulfilment_groups";
+DROP TABLE IF EXISTS "order_groups";
+DROP SEQUENCE IF EXISTS "order_number_seq";
@@ -0,0 +1,157 @@
+-- UP Migration
+
+-- Order Groups Table
+-- What a buyer bought in one checkout: the buyer-visible order number, where
+-- it ships to and what it cost. A checkout's order group is PENDING until its
+-- transaction completes (PLACED) or fails (FAILED).
+CREATE SEQUENCE "order_number_seq";
+
+CREATE TABLE "order_groups" (
+  "id" bigserial PRIMARY KEY,
+  "order_number" varchar UNIQUE NOT NULL DEFAULT ('SH-' || lpad(nextval('order_number_seq')::text, 8, '0')),
+  "buyer_id" bigint NOT NULL,
+  "reference" varchar UNIQUE NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "shipping_address" jsonb NOT NULL DEFAULT '{}',
+  "currency" varchar(3) NOT NULL DEFAULT 'NGN',
+  "subtotal" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "discount_total" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "delivery_total" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "grand_total" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "order_groups" ADD FOREIGN KEY ("buyer_id") REFERENCES "users" ("id");
+ALTER TABLE "order_groups" ADD CONSTRAINT valid_order_group_status CHECK ("status" IN ('PENDING', 'PLACED', 'FAILED'));
+CREATE INDEX ON "order_groups" ("buyer_id");
+
+-- Fulfilment Groups Table
+-- The part of an order group one store ships. Its delivery_status is that of
+-- its least advanced line, so it's CANCELLED only once every line is.
+CREATE TABLE "fulfilment_groups" (
+  "id" bigserial PRIMARY KEY,
+  "order_group_id" bigint NOT NULL,
+  "store_id" bigint NOT NULL,
+  "seller_id" bigint NOT NULL,
+  "delivery_status" varchar NOT NULL DEFAULT 'PENDING',
+  "subtotal" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "discount" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "delivery_fee" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "total" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "carrier" varchar NOT NULL DEFAULT '',
+  "tracking_number" varchar NOT NULL DEFAULT '',
+  "shipped_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "expected_delivery_date" timestamptz NOT NULL DEFAULT (now() + interval '3 days'),
+  "delivered_on" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("order_group_id", "store_id")
+);
+ALTER TABLE "fulfilment_groups" ADD FOREIGN KEY ("order_group_id") REFERENCES "order_groups" ("id") ON DELETE CASCADE;
+ALTER TABLE "fulfilment_groups" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id");
+ALTER TABLE "fulfilment_groups" ADD FOREIGN KEY ("seller_id") REFERENCES "users" ("id");
+CREATE INDEX ON "fulfilment_groups" ("store_id", "seller_id");
+
+-- Each order is a line of the fulfilment group its store ships it in.
+ALTER TABLE "orders" ADD COLUMN "fulfilment_group_id" bigint;
+ALTER TABLE "orders" ADD FOREIGN KEY ("fulfilment_group_id") REFERENCES "fulfilment_groups" ("id");
+CREATE INDEX ON "orders" ("fulfilment_group_id");
+
+-- Group the orders placed before order groups existed: a completed
+-- transaction's orders share an order group, and any other order is an
+-- order group of its own.
+DO $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order orders%ROWTYPE;
+    v_order_group_id bigint;
+BEGIN
+    FOR v_transaction IN
+        SELECT * FROM transactions
+        WHERE status = 'COMPLETED' AND cardinality(order_ids) > 0
+        ORDER BY id
+    LOOP
+        v_order_group_id := NULL;
+
+        INSERT INTO order_groups (buyer_id, reference, status, currency, created_at)
+        SELECT v_transaction.customer_id, v_transaction.provider_tx_ref_id, 'PLACED', min(o.currency), v_transaction.created_at
+        FROM orders o
+        WHERE o.id = ANY(v_transaction.order_ids)
+        HAVING count(*) > 0
+        RETURNING id INTO v_order_group_id;
+
+        CONTINUE WHEN v_order_group_id IS NULL;
+
+        INSERT INTO fulfilment_groups (order_group_id, store_id, seller_id, created_at)
+        SELECT v_order_group_id, o.store_id, min(o.seller_id), min(o.created_at)
+        FROM orders o
+        WHERE o.id = ANY(v_transaction.order_ids)
+        GROUP BY o.store_id;
+
+        UPDATE orders o
+        SET fulfilment_group_id = fg.id
+        FROM fulfilment_groups fg
+        WHERE fg.order_group_id = v_order_group_id
+        AND fg.store_id = o.store_id
+        AND o.id = ANY(v_transaction.order_ids);
+    END LOOP;
+
+    FOR v_order IN SELECT * FROM orders WHERE fulfilment_group_id IS NULL ORDER BY id
+    LOOP
+        INSERT INTO order_groups (buyer_id, reference, status, currency, created_at)
+        VALUES (v_order.buyer_id, 'order:' || v_order.id, 'PLACED', v_order.currency, v_order.created_at)
+        RETURNING id INTO v_order_group_id;
+
+        INSERT INTO fulfilment_groups (order_group_id, store_id, seller_id, created_at)
+        VALUES (v_order_group_id, v_order.store_id, v_order.seller_id, v_order.created_at);
+
+        UPDATE orders
+        SET fulfilment_group_id = currval(pg_get_serial_sequence('fulfilment_groups', 'id'))
+        WHERE id = v_order.id;
+    END LOOP;
+
+    UPDATE fulfilment_groups fg
+    SET
+        subtotal = l.subtotal,
+        discount = l.discount,
+        delivery_fee = l.delivery_fee,
+        total = l.subtotal - l.discount + l.delivery_fee,
+        delivery_status = l.delivery_status,
+        expected_delivery_date = l.expected_delivery_date,
+        delivered_on = l.delivered_on
+    FROM (
+        SELECT
+            o.fulfilment_group_id,
+            sum(o.item_price * o.order_quantity) AS subtotal,
+            sum(o.discount_amount) AS discount,
+            sum(o.delivery_fee) AS delivery_fee,
+            (array_agg(o.delivery_status ORDER BY array_position(
+                ARRAY['PENDING', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'RETURNED', 'CANCELLED']::varchar[],
+                o.delivery_status
+            )))[1] AS delivery_status,
+            max(o.expected_delivery_date) AS expected_delivery_date,
+            max(o.delivered_on) AS delivered_on
+        FROM orders o
+        GROUP BY o.fulfilment_group_id
+    ) l
+    WHERE l.fulfilment_group_id = fg.id;
+
+    UPDATE order_groups og
+    SET
+        subtotal = f.subtotal,
+        discount_total = f.discount,
+        delivery_total = f.delivery_fee,
+        grand_total = f.total
+    FROM (
+        SELECT
+            order_group_id,
+            sum(subtotal) AS subtotal,
+            sum(discount) AS discount,
+            sum(delivery_fee) AS delivery_fee,
+            sum(total) AS total
+        FROM fulfilment_groups
+        GROUP BY order_group_id
+    ) f
+    WHERE f.order_group_id = og.id;
+END;
+$$;
@@ -0,0 +1,255 @@
+-- UP Migration
+
+-- Shipping Zones Table
+-- Where a store ships to: a country, or a state or city of it. The most
+-- specific zone covering a shipping address applies. A zone charges the fee
+-- of its rate table for what's shipped, looked up by rate_basis, is free from
+-- free_shipping_threshold (when not 0), and delivers within min_delivery_days
+-- to max_delivery_days. A store without a zone for an address charges its
+-- delivery rules.
+CREATE TABLE "shipping_zones" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "country" varchar(2) NOT NULL,
+  "state" varchar NOT NULL DEFAULT '',
+  "city" varchar NOT NULL DEFAULT '',
+  "rate_basis" varchar NOT NULL DEFAULT 'FLAT',
+  "free_shipping_threshold" NUMERIC(10, 2) NOT NULL DEFAULT 0,
+  "min_delivery_days" int NOT NULL DEFAULT 1,
+  "max_delivery_days" int NOT NULL DEFAULT 3,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("store_id", "country", "state", "city")
+);
+ALTER TABLE "shipping_zones" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "shipping_zones" ADD CONSTRAINT valid_shipping_zone CHECK (
+  "country" ~ '^[A-Z]{2}$'
+  AND "rate_basis" IN ('FLAT', 'WEIGHT', 'ITEM_COUNT')
+  AND "free_shipping_threshold" >= 0
+  AND "min_delivery_days" >= 0
+  AND "max_delivery_days" >= "min_delivery_days"
+  AND "max_delivery_days" > 0
+);
+
+-- Shipping Rates Table
+-- A zone's rate table: shipping from_quantity grams or units up costs fee.
+CREATE TABLE "shipping_rates" (
+  "id" bigserial PRIMARY KEY,
+  "shipping_zone_id" bigint NOT NULL,
+  "from_quantity" bigint NOT NULL DEFAULT 0,
+  "fee" NUMERIC(10, 2) NOT NULL,
+  UNIQUE ("shipping_zone_id", "from_quantity")
+);
+ALTER TABLE "shipping_rates" ADD FOREIGN KEY ("shipping_zone_id") REFERENCES "shipping_zones" ("id") ON DELETE CASCADE;
+ALTER TABLE "shipping_rates" ADD CONSTRAINT valid_shipping_rate CHECK ("from_quantity" >= 0 AND "fee" >= 0);
+
+-- What a unit of an item weighs, for zones charging by weight.
+ALTER TABLE "items" ADD COLUMN "weight_grams" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "items" ADD CONSTRAINT valid_item_weight CHECK ("weight_grams" >= 0);
+
+-- Function: process_transaction_completion
+-- Description: Process transaction completion for a user's cart items.
+-- Each cart item carries the unit_price and delivery_fee computed by the
+-- pricing component in the settlement currency, with the exchange rate its
+-- listed price was converted at, the discount taken off by a coupon, the tax
+-- charged on it, the days its store's shipping zone takes to deliver it, and the
+-- commission the platform takes from the line; the store's pending funds are
+-- credited with the rest, exclusive tax included.
+CREATE OR REPLACE FUNCTION process_transaction_completion(
+    p_transaction_ref_id varchar,
+    p_status varchar,
+    p_provider_tx_fee NUMERIC(10, 2),
+    p_cart_items jsonb
+) RETURNS transactions AS $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order_id bigint;
+    v_order_ids bigint[] := '{}';
+    v_cart_item jsonb;
+    v_item items%ROWTYPE;
+    v_store_owner store_owners%ROWTYPE;
+    v_unit_price NUMERIC(10, 2);
+    v_delivery_fee NUMERIC(10, 2);
+    v_discount NUMERIC(18, 2);
+    v_tax NUMERIC(18, 2);
+    v_tax_inclusive boolean;
+    v_item_total NUMERIC(18, 2);
+    v_commission NUMERIC(18, 2);
+    v_store_total record;
+    v_account_type varchar;
+BEGIN
+    -- Get transaction record
+    SELECT * INTO v_transaction
+    FROM transactions
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Transaction not found';
+    END IF;
+
+    IF v_transaction.status IN ('COMPLETED', 'FAILED') THEN
+        RETURN v_transaction;
+    END IF;
+
+    -- Update transaction status and fee
+    UPDATE transactions
+    SET
+        status = p_status,
+        provider_tx_fee = p_provider_tx_fee
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    RETURNING * INTO v_transaction;
+
+    -- If transaction completed successfully, create orders
+    IF p_status = 'COMPLETED' THEN
+        -- Determine account type based on payment provider
+        v_account_type := CASE
+            WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'FIAT'
+            ELSE 'CRYPTO'
+        END;
+
+        -- Initialize store totals map
+        DROP TABLE IF EXISTS store_totals;
+        CREATE TEMPORARY TABLE store_totals (
+            store_id bigint PRIMARY KEY,
+            total_amount NUMERIC(18, 2)
+        ) ON COMMIT DROP;
+
+        -- Process each cart item
+        FOR v_cart_item IN SELECT * FROM jsonb_array_elements(p_cart_items)
+        LOOP
+            -- Get item details
+            SELECT * INTO v_item
+            FROM items
+            WHERE id = (v_cart_item->>'item_id')::bigint;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Item not found: %', (v_cart_item->>'item_id')::bigint;
+            END IF;
+
+            -- Get store owner details
+            SELECT * INTO v_store_owner
+            FROM store_owners
+            WHERE store_id = v_item.store_id AND is_primary = true;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Store owner not found for store: %', v_item.store_id;
+            END IF;
+
+            -- Use the price computed by the pricing component, falling back to the listed price
+            v_unit_price := COALESCE((v_cart_item->>'unit_price')::numeric(10,2), v_item.price);
+            v_delivery_fee := COALESCE((v_cart_item->>'delivery_fee')::numeric(10,2), 0);
+
+            -- Calculate item total less the coupon discount, the store is also owed the delivery fee
+            v_discount := COALESCE((v_cart_item->>'discount')::numeric(18,2), 0);
+            v_item_total := v_unit_price * (v_cart_item->>'quantity')::int - v_discount + v_delivery_fee;
+
+            IF v_discount < 0 OR v_item_total < v_delivery_fee THEN
+                RAISE EXCEPTION 'Invalid discount % for item %', v_discount, v_item.id;
+            END IF;
+
+            -- Inclusive tax is part of the price, exclusive tax is charged on top of it
+            v_tax := COALESCE((v_cart_item->>'tax')::numeric(18,2), 0);
+            v_tax_inclusive := COALESCE((v_cart_item->>'tax_inclusive')::boolean, false);
+
+            IF v_tax < 0 THEN
+                RAISE EXCEPTION 'Invalid tax % for item %', v_tax, v_item.id;
+            END IF;
+
+            IF NOT v_tax_inclusive THEN
+                v_item_total := v_item_total + v_tax;
+            END IF;
+            v_commission := COALESCE((v_cart_item->>'commission')::numeric(18,2), 0);
+
+            IF v_commission < 0 OR v_commission > v_item_total THEN
+                RAISE EXCEPTION 'Invalid commission % for item %', v_commission, v_item.id;
+            END IF;
+
+            -- Accumulate store totals, less the platform's commission
+            INSERT INTO store_totals (store_id, total_amount)
+            VALUES (v_item.store_id, v_item_total - v_commission)
+            ON CONFLICT (store_id) DO UPDATE
+            SET total_amount = store_totals.total_amount + EXCLUDED.total_amount;
+
+            -- Create order
+            INSERT INTO orders (
+                item_id,
+                item_price,
+                item_currency,
+                order_quantity,
+                buyer_id,
+                seller_id,
+                store_id,
+                delivery_fee,
+                payment_channel,
+                payment_method,
+                commission_rule_id,
+                commission_percentage,
+                commission_fixed,
+                commission_amount,
+                coupon_id,
+                discount_amount,
+                currency,
+                fx_rate,
+                tax_rate,
+                tax_inclusive,
+                tax_amount,
+                expected_delivery_date
+            ) VALUES (
+                v_item.id,
+                v_unit_price,
+                v_item.currency,
+                (v_cart_item->>'quantity')::int,
+                v_transaction.customer_id,
+                v_store_owner.user_id,
+                v_item.store_id,
+                v_delivery_fee,
+                v_account_type,
+                CASE
+                    WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'Instant Pay'
+                    ELSE 'Web Wallet'
+                END,
+                (v_cart_item->>'commission_rule_id')::bigint,
+                COALESCE((v_cart_item->>'commission_percentage')::numeric(5,2), 0),
+                COALESCE((v_cart_item->>'commission_fixed')::numeric(10,2), 0),
+                v_commission,
+                (v_cart_item->>'coupon_id')::bigint,
+                v_discount,
+                COALESCE(v_cart_item->>'currency', 'NGN'),
+                COALESCE((v_cart_item->>'fx_rate')::numeric(24,8), 1),
+                COALESCE((v_cart_item->>'tax_rate')::numeric(5,2), 0),
+                v_tax_inclusive,
+                v_tax,
+                now() + make_interval(days => COALESCE(NULLIF((v_cart_item->>'delivery_days')::int, 0), 3))
+            ) RETURNING id INTO v_order_id;
+
+            -- Add order ID to array
+            v_order_ids := array_append(v_order_ids, v_order_id);
+
+            -- Update item supply quantity
+            UPDATE items
+            SET supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int
+            WHERE id = v_item.id;
+        END LOOP;
+
+        -- Create/update pending funds for each store
+        FOR v_store_total IN SELECT * FROM store_totals
+        LOOP
+            PERFORM upsert_pending_funds(
+                v_store_total.store_id,
+                v_account_type,
+                v_store_total.total_amount
+            );
+        END LOOP;
+
+        -- Update transaction with order IDs
+        UPDATE transactions
+        SET order_ids = v_order_ids
+        WHERE provider_tx_ref_id = p_transaction_ref_id
+        RETURNING * INTO v_transaction;
+    END IF;
+
+    RETURN v_transaction;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,27 @@
+-- UP Migration
+
+-- Stock Reservations Table
+-- Stock held for a checkout, under its reference, while the buyer pays. A
+-- RESERVED reservation holds its quantity until expires_at; it is CONVERTED
+-- when the checkout completes and its items' supply is deducted, RELEASED when
+-- the payment fails or is abandoned, and EXPIRED once expires_at passes. An
+-- item's available stock is its supply_quantity less its live reservations.
+CREATE TABLE "stock_reservations" (
+  "id" bigserial PRIMARY KEY,
+  "item_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "reference" varchar NOT NULL,
+  "quantity" int NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'RESERVED',
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "stock_reservations" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON DELETE CASCADE;
+ALTER TABLE "stock_reservations" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "stock_reservations" ADD CONSTRAINT valid_stock_reservation CHECK (
+  "quantity" > 0
+  AND "status" IN ('RESERVED', 'CONVERTED', 'RELEASED', 'EXPIRED')
+);
+ALTER TABLE "stock_reservations" ADD CONSTRAINT unique_stock_reservation UNIQUE ("reference", "item_id");
+CREATE INDEX ON "stock_reservations" ("item_id", "expires_at") WHERE "status" = 'RESERVED';
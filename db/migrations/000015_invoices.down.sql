@@ -0,0 +1,4 @@
+-- DOWN Migration
+
+DROP TABLE IF EXISTS "invoices";
+DROP TABLE IF EXISTS "invoice_sequences";
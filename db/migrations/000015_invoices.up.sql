@@ -0,0 +1,37 @@
+-- UP Migration
+
+-- Invoice Sequences Table
+-- The last invoice number each store issued. A store's next number is taken by
+-- incrementing its row in the transaction that issues the invoice, so a rolled
+-- back invoice gives its number back and a store's numbering has no gaps.
+CREATE TABLE "invoice_sequences" (
+  "store_id" bigint PRIMARY KEY,
+  "last_number" bigint NOT NULL DEFAULT 0
+);
+ALTER TABLE "invoice_sequences" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+
+-- Invoices Table
+-- The invoice a store issues for its orders in a completed transaction, under
+-- the transaction's reference. Its totals are those of the orders; emailed_at
+-- is the zero time until the invoice is emailed to the buyer.
+CREATE TABLE "invoices" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "sequence_number" bigint NOT NULL,
+  "invoice_number" varchar UNIQUE NOT NULL,
+  "reference" varchar NOT NULL,
+  "buyer_id" bigint NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT 'NGN',
+  "subtotal" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "discount" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "delivery_fee" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "tax" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "total" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "emailed_at" timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("store_id", "sequence_number"),
+  UNIQUE ("reference", "store_id")
+);
+ALTER TABLE "invoices" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "invoices" ADD FOREIGN KEY ("buyer_id") REFERENCES "users" ("id");
+CREATE INDEX ON "invoices" ("buyer_id");
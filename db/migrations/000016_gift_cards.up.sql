@@ -0,0 +1,143 @@
+-- UP Migration
+
+-- Gift Cards Table
+-- A gift card a buyer bought for a store, paid through the transaction under
+-- reference. It's PENDING until the transaction completes (ACTIVE) or fails
+-- (FAILED). An ACTIVE card's balance pays for the store's part of checkouts
+-- until it runs out.
+CREATE TABLE "gift_cards" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "code" varchar UNIQUE NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT 'NGN',
+  "initial_amount" NUMERIC(18, 2) NOT NULL,
+  "balance" NUMERIC(18, 2) NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "purchaser_id" bigint NOT NULL,
+  "reference" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "gift_cards" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id");
+ALTER TABLE "gift_cards" ADD FOREIGN KEY ("purchaser_id") REFERENCES "users" ("id");
+ALTER TABLE "gift_cards" ADD CONSTRAINT valid_gift_card CHECK (
+  "initial_amount" > 0
+  AND "balance" >= 0
+  AND "balance" <= "initial_amount"
+  AND "status" IN ('PENDING', 'ACTIVE', 'FAILED')
+);
+CREATE INDEX ON "gift_cards" ("purchaser_id");
+
+-- Store Credits Table
+-- A buyer's store credit: a balance with the platform, topped up by refunds,
+-- that pays for checkouts at any store.
+CREATE TABLE "store_credits" (
+  "user_id" bigint PRIMARY KEY,
+  "balance" NUMERIC(18, 2) NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "store_credits" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "store_credits" ADD CONSTRAINT non_negative_store_credit CHECK ("balance" >= 0);
+
+-- Store Credit Entries Table
+-- The history of a buyer's store credit. amount is what the entry added (or,
+-- if negative, took off), and balance the store credit left after it.
+CREATE TABLE "store_credit_entries" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "amount" NUMERIC(18, 2) NOT NULL,
+  "balance" NUMERIC(18, 2) NOT NULL,
+  "reference" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "store_credit_entries" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "store_credit_entries" ADD CONSTRAINT valid_store_credit_entry CHECK (
+  "amount" <> 0
+  AND "kind" IN ('REFUND', 'CHECKOUT', 'CHECKOUT_RELEASE')
+);
+CREATE INDEX ON "store_credit_entries" ("user_id");
+
+-- Checkout Credits Table
+-- What a gift card, or the buyer's store credit if gift_card_id is NULL, pays
+-- of the checkout under reference. The amount is taken off the card or store
+-- credit when the checkout starts, and held RESERVED until the checkout
+-- completes (REDEEMED), or its payment fails or is abandoned (RELEASED) and
+-- the amount is given back.
+CREATE TABLE "checkout_credits" (
+  "id" bigserial PRIMARY KEY,
+  "reference" varchar NOT NULL,
+  "user_id" bigint NOT NULL,
+  "gift_card_id" bigint,
+  "amount" NUMERIC(18, 2) NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'RESERVED',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "checkout_credits" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "checkout_credits" ADD FOREIGN KEY ("gift_card_id") REFERENCES "gift_cards" ("id");
+ALTER TABLE "checkout_credits" ADD CONSTRAINT valid_checkout_credit CHECK (
+  "amount" > 0
+  AND "status" IN ('RESERVED', 'REDEEMED', 'RELEASED')
+);
+CREATE INDEX ON "checkout_credits" ("reference");
+
+-- Where a refund goes: back through the provider that took the payment
+-- (ORIGINAL), or to the buyer's store credit (STORE_CREDIT).
+ALTER TABLE "refunds" ADD COLUMN "destination" varchar NOT NULL DEFAULT 'ORIGINAL';
+ALTER TABLE "refunds" ADD CONSTRAINT valid_refund_destination CHECK ("destination" IN ('ORIGINAL', 'STORE_CREDIT'));
+
+-- Function: initialize_transaction
+-- Description: Initialize transaction record. A checkout paid in full with
+-- gift cards and store credit charges nothing through its provider, so the
+-- amount may be zero.
+CREATE OR REPLACE FUNCTION initialize_transaction(
+    p_customer_id bigint,
+    p_amount NUMERIC(18, 2),
+    p_payment_provider varchar,
+    p_provider_tx_ref_id varchar,
+    p_provider_tx_access_code varchar
+) RETURNS transactions AS $$
+DECLARE
+    v_customer_exists bool;
+    v_result transactions%ROWTYPE;
+BEGIN
+    -- Validate customer exists
+    SELECT EXISTS(
+        SELECT 1 FROM users WHERE id = p_customer_id
+    ) INTO v_customer_exists;
+    
+    IF NOT v_customer_exists THEN
+        RAISE EXCEPTION 'Customer with ID % does not exist', p_customer_id;
+    END IF;
+
+    -- Validate amount is not negative
+    IF p_amount < 0 THEN
+        RAISE EXCEPTION 'Amount must not be negative';
+    END IF;
+
+    -- Create initial transaction record
+    INSERT INTO transactions (
+        customer_id,
+        amount,
+        payment_provider,
+        provider_tx_ref_id,
+        provider_tx_access_code,
+        provider_tx_fee,
+        status,
+        order_ids
+    ) VALUES (
+        p_customer_id,
+        p_amount,
+        p_payment_provider,
+        p_provider_tx_ref_id,
+        NULLIF(p_provider_tx_access_code, ''),
+        0,
+        'PROCESSING',
+        '{}'::bigint[]
+    ) RETURNING * INTO v_result;
+
+    RETURN v_result;
+END;
+$$ LANGUAGE plpgsql;
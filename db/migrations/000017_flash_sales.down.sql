@@ -0,0 +1,4 @@
+-- DOWN Migration
+
+DROP TABLE IF EXISTS "flash_sale_claims";
+DROP TABLE IF EXISTS "flash_sales";
@@ -0,0 +1,63 @@
+-- UP Migration
+
+-- Flash Sales Table
+-- An item sold at sale_price between starts_at and ends_at, for at most
+-- quantity units and per_user_limit units a buyer. Claims are counted in
+-- Redis while the sale runs; claimed_quantity is the count as last reconciled
+-- from there, and sold_quantity the units bought by completed checkouts.
+CREATE TABLE "flash_sales" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "item_id" bigint NOT NULL,
+  "sale_price" NUMERIC(18, 2) NOT NULL,
+  "quantity" int NOT NULL,
+  "per_user_limit" int NOT NULL,
+  "claimed_quantity" int NOT NULL DEFAULT 0,
+  "sold_quantity" int NOT NULL DEFAULT 0,
+  "starts_at" timestamptz NOT NULL,
+  "ends_at" timestamptz NOT NULL,
+  "cancelled_at" timestamptz,
+  "reconciled_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "flash_sales" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "flash_sales" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON DELETE CASCADE;
+ALTER TABLE "flash_sales" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+ALTER TABLE "flash_sales" ADD CONSTRAINT valid_flash_sale CHECK (
+  "sale_price" > 0
+  AND "quantity" > 0
+  AND "per_user_limit" > 0
+  AND "per_user_limit" <= "quantity"
+  AND "claimed_quantity" >= 0
+  AND "sold_quantity" >= 0
+  AND "ends_at" > "starts_at"
+);
+CREATE INDEX ON "flash_sales" ("item_id", "ends_at");
+CREATE INDEX ON "flash_sales" ("store_id", "ends_at");
+
+-- Flash Sale Claims Table
+-- The units of a flash sale a checkout claimed, once Redis granted them. A
+-- claim is CLAIMED until the checkout's transaction completes (CONVERTED) or
+-- fails (RELEASED); returned_at is when a RELEASED claim was given back in Redis.
+CREATE TABLE "flash_sale_claims" (
+  "id" bigserial PRIMARY KEY,
+  "flash_sale_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "reference" varchar NOT NULL,
+  "quantity" int NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'CLAIMED',
+  "returned_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "flash_sale_claims" ADD FOREIGN KEY ("flash_sale_id") REFERENCES "flash_sales" ("id") ON DELETE CASCADE;
+ALTER TABLE "flash_sale_claims" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "flash_sale_claims" ADD CONSTRAINT valid_flash_sale_claim CHECK (
+  "quantity" > 0
+  AND "status" IN ('CLAIMED', 'CONVERTED', 'RELEASED')
+);
+ALTER TABLE "flash_sale_claims" ADD CONSTRAINT unique_flash_sale_claim UNIQUE ("flash_sale_id", "reference");
+CREATE INDEX ON "flash_sale_claims" ("reference");
+CREATE INDEX ON "flash_sale_claims" ("id") WHERE "status" = 'RELEASED' AND "returned_at" IS NULL;
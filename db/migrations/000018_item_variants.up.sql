@@ -0,0 +1,384 @@
+-- UP Migration
+
+-- Item Options Table
+-- The axes an item's variants differ along, e.g. Size with S, M and L, in the
+-- order they're shown.
+CREATE TABLE "item_options" (
+  "id" bigserial PRIMARY KEY,
+  "item_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "values" text[] NOT NULL,
+  "position" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("item_id", "name")
+);
+ALTER TABLE "item_options" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON DELETE CASCADE;
+
+-- Item Variants Table
+-- A version of an item picking one value of each of its options, with a SKU
+-- of its own in its store, and its own stock and images. A variant without a
+-- price sells at its item's. An item with variants is bought in one of them,
+-- and its supply_quantity is the sum of theirs.
+CREATE TABLE "item_variants" (
+  "id" bigserial PRIMARY KEY,
+  "item_id" bigint NOT NULL,
+  "store_id" bigint NOT NULL,
+  "sku" varchar NOT NULL,
+  "options" jsonb NOT NULL,
+  "price" NUMERIC(10, 2),
+  "supply_quantity" bigint NOT NULL DEFAULT 0,
+  "image_urls" text[] NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("store_id", "sku")
+);
+ALTER TABLE "item_variants" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON DELETE CASCADE;
+ALTER TABLE "item_variants" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "item_variants" ADD CONSTRAINT valid_item_variant CHECK (
+  "sku" <> ''
+  AND ("price" IS NULL OR "price" > 0)
+  AND "supply_quantity" >= 0
+);
+CREATE INDEX ON "item_variants" ("item_id");
+
+-- Cart items, stock reservations, orders and reviews name the variant of
+-- their item, when it has variants. An item is in a cart once per variant.
+ALTER TABLE "cart_items" ADD COLUMN "variant_id" bigint;
+ALTER TABLE "cart_items" ADD FOREIGN KEY ("variant_id") REFERENCES "item_variants" ("id") ON DELETE CASCADE;
+ALTER TABLE "cart_items" DROP CONSTRAINT unique_item_in_cart;
+CREATE UNIQUE INDEX unique_item_in_cart ON "cart_items" ("cart_id", "item_id", COALESCE("variant_id", 0));
+
+ALTER TABLE "stock_reservations" ADD COLUMN "variant_id" bigint;
+ALTER TABLE "stock_reservations" ADD FOREIGN KEY ("variant_id") REFERENCES "item_variants" ("id") ON DELETE CASCADE;
+ALTER TABLE "stock_reservations" DROP CONSTRAINT unique_stock_reservation;
+CREATE UNIQUE INDEX unique_stock_reservation ON "stock_reservations" ("reference", "item_id", COALESCE("variant_id", 0));
+CREATE INDEX ON "stock_reservations" ("variant_id", "expires_at") WHERE "status" = 'RESERVED';
+
+-- variant_sku keeps what was ordered should the variant be deleted.
+ALTER TABLE "orders" ADD COLUMN "variant_id" bigint;
+ALTER TABLE "orders" ADD COLUMN "variant_sku" varchar NOT NULL DEFAULT '';
+ALTER TABLE "orders" ADD FOREIGN KEY ("variant_id") REFERENCES "item_variants" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "reviews" ADD COLUMN "variant_id" bigint;
+ALTER TABLE "reviews" ADD FOREIGN KEY ("variant_id") REFERENCES "item_variants" ("id") ON DELETE SET NULL;
+
+-- Function: upsert_cart_item
+-- Description: Adds quantity of an item, in a variant when given, to a cart.
+DROP FUNCTION IF EXISTS upsert_cart_item(bigint, bigint, bigint, int);
+CREATE OR REPLACE FUNCTION upsert_cart_item(p_cart_id bigint, p_item_id bigint, p_store_id bigint, p_variant_id bigint, p_quantity int DEFAULT 1)
+RETURNS cart_items AS $$
+DECLARE
+    v_result cart_items%ROWTYPE;
+BEGIN
+    INSERT INTO cart_items (cart_id, item_id, store_id, variant_id, quantity)
+    VALUES (p_cart_id, p_item_id, p_store_id, p_variant_id, p_quantity)
+    ON CONFLICT (cart_id, item_id, COALESCE(variant_id, 0)) DO UPDATE
+    SET
+        quantity = cart_items.quantity + EXCLUDED.quantity,
+        updated_at = now()
+    RETURNING * INTO v_result;
+
+    RETURN v_result;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: create_review
+-- Description: Creates a review of an item, in the variant it was bought in.
+DROP FUNCTION IF EXISTS create_review(bigint, bigint, bigint, NUMERIC(2, 1), varchar, TEXT, BOOLEAN);
+CREATE OR REPLACE FUNCTION create_review(
+    p_store_id bigint,
+    p_user_id bigint,
+    p_item_id bigint,
+    p_variant_id bigint,
+    p_rating NUMERIC(2, 1),
+    p_review_type varchar,
+    p_comment TEXT,
+    p_is_verified_purchase BOOLEAN
+) RETURNS void AS $$
+DECLARE
+    v_store_exists bool;
+    v_user_exists bool;
+    v_item_exists bool;
+    v_order_exists bool;
+BEGIN
+    -- Check if the store exists
+    SELECT EXISTS(SELECT 1 FROM stores WHERE id = p_store_id) INTO v_store_exists;
+    IF NOT v_store_exists THEN
+        RAISE EXCEPTION 'Store with ID % does not exist', p_store_id;
+    END IF;
+
+    -- Check if the user exists
+    SELECT EXISTS(SELECT 1 FROM users WHERE id = p_user_id) INTO v_user_exists;
+    IF NOT v_user_exists THEN
+        RAISE EXCEPTION 'User with ID % does not exist', p_user_id;
+    END IF;
+
+    -- Check if the item exists
+    SELECT EXISTS(SELECT 1 FROM items WHERE id = p_item_id) INTO v_item_exists;
+    IF NOT v_item_exists THEN
+        RAISE EXCEPTION 'Item with ID % does not exist', p_item_id;
+    END IF;
+
+    -- If is_verified_purchase is true, check for a corresponding order
+    IF p_is_verified_purchase THEN
+        SELECT EXISTS(SELECT 1 FROM orders WHERE buyer_id = p_user_id AND item_id = p_item_id) INTO v_order_exists;
+        IF NOT v_order_exists THEN
+            RAISE EXCEPTION 'No verified purchase found for User ID % and Item ID %', p_user_id, p_item_id;
+        END IF;
+    END IF;
+
+    -- Insert the review
+    INSERT INTO reviews (
+        store_id,
+        user_id,
+        item_id,
+        variant_id,
+        rating,
+        review_type,
+        comment,
+        is_verified_purchase
+    ) VALUES (
+        p_store_id,
+        p_user_id,
+        p_item_id,
+        p_variant_id,
+        p_rating,
+        p_review_type,
+        p_comment,
+        p_is_verified_purchase
+    );
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: process_transaction_completion
+-- Description: Process transaction completion for a user's cart items.
+-- Each cart item carries the unit_price and delivery_fee computed by the
+-- pricing component in the settlement currency, with the exchange rate its
+-- listed price was converted at, the discount taken off by a coupon, the tax
+-- charged on it, the days its store's shipping zone takes to deliver it, and the
+-- commission the platform takes from the line; the store's pending funds are
+-- credited with the rest, exclusive tax included. A cart item with a
+-- variant_id orders that variant of the item, and is deducted from its supply
+-- as well as the item's.
+CREATE OR REPLACE FUNCTION process_transaction_completion(
+    p_transaction_ref_id varchar,
+    p_status varchar,
+    p_provider_tx_fee NUMERIC(10, 2),
+    p_cart_items jsonb
+) RETURNS transactions AS $$
+DECLARE
+    v_transaction transactions%ROWTYPE;
+    v_order_id bigint;
+    v_order_ids bigint[] := '{}';
+    v_cart_item jsonb;
+    v_item items%ROWTYPE;
+    v_variant item_variants%ROWTYPE;
+    v_store_owner store_owners%ROWTYPE;
+    v_unit_price NUMERIC(10, 2);
+    v_delivery_fee NUMERIC(10, 2);
+    v_discount NUMERIC(18, 2);
+    v_tax NUMERIC(18, 2);
+    v_tax_inclusive boolean;
+    v_item_total NUMERIC(18, 2);
+    v_commission NUMERIC(18, 2);
+    v_store_total record;
+    v_account_type varchar;
+BEGIN
+    -- Get transaction record
+    SELECT * INTO v_transaction
+    FROM transactions
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Transaction not found';
+    END IF;
+
+    IF v_transaction.status IN ('COMPLETED', 'FAILED') THEN
+        RETURN v_transaction;
+    END IF;
+
+    -- Update transaction status and fee
+    UPDATE transactions
+    SET
+        status = p_status,
+        provider_tx_fee = p_provider_tx_fee
+    WHERE provider_tx_ref_id = p_transaction_ref_id
+    RETURNING * INTO v_transaction;
+
+    -- If transaction completed successfully, create orders
+    IF p_status = 'COMPLETED' THEN
+        -- Determine account type based on payment provider
+        v_account_type := CASE
+            WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'FIAT'
+            ELSE 'CRYPTO'
+        END;
+
+        -- Initialize store totals map
+        DROP TABLE IF EXISTS store_totals;
+        CREATE TEMPORARY TABLE store_totals (
+            store_id bigint PRIMARY KEY,
+            total_amount NUMERIC(18, 2)
+        ) ON COMMIT DROP;
+
+        -- Process each cart item
+        FOR v_cart_item IN SELECT * FROM jsonb_array_elements(p_cart_items)
+        LOOP
+            -- Get item details
+            SELECT * INTO v_item
+            FROM items
+            WHERE id = (v_cart_item->>'item_id')::bigint;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Item not found: %', (v_cart_item->>'item_id')::bigint;
+            END IF;
+
+            -- Get variant details, when the item is bought in one
+            v_variant := NULL;
+            IF v_cart_item->>'variant_id' IS NOT NULL THEN
+                SELECT * INTO v_variant
+                FROM item_variants
+                WHERE id = (v_cart_item->>'variant_id')::bigint AND item_id = v_item.id;
+
+                IF NOT FOUND THEN
+                    RAISE EXCEPTION 'Variant not found: %', (v_cart_item->>'variant_id')::bigint;
+                END IF;
+            END IF;
+
+            -- Get store owner details
+            SELECT * INTO v_store_owner
+            FROM store_owners
+            WHERE store_id = v_item.store_id AND is_primary = true;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Store owner not found for store: %', v_item.store_id;
+            END IF;
+
+            -- Use the price computed by the pricing component, falling back to the listed price
+            v_unit_price := COALESCE((v_cart_item->>'unit_price')::numeric(10,2), v_variant.price, v_item.price);
+            v_delivery_fee := COALESCE((v_cart_item->>'delivery_fee')::numeric(10,2), 0);
+
+            -- Calculate item total less the coupon discount, the store is also owed the delivery fee
+            v_discount := COALESCE((v_cart_item->>'discount')::numeric(18,2), 0);
+            v_item_total := v_unit_price * (v_cart_item->>'quantity')::int - v_discount + v_delivery_fee;
+
+            IF v_discount < 0 OR v_item_total < v_delivery_fee THEN
+                RAISE EXCEPTION 'Invalid discount % for item %', v_discount, v_item.id;
+            END IF;
+
+            -- Inclusive tax is part of the price, exclusive tax is charged on top of it
+            v_tax := COALESCE((v_cart_item->>'tax')::numeric(18,2), 0);
+            v_tax_inclusive := COALESCE((v_cart_item->>'tax_inclusive')::boolean, false);
+
+            IF v_tax < 0 THEN
+                RAISE EXCEPTION 'Invalid tax % for item %', v_tax, v_item.id;
+            END IF;
+
+            IF NOT v_tax_inclusive THEN
+                v_item_total := v_item_total + v_tax;
+            END IF;
+            v_commission := COALESCE((v_cart_item->>'commission')::numeric(18,2), 0);
+
+            IF v_commission < 0 OR v_commission > v_item_total THEN
+                RAISE EXCEPTION 'Invalid commission % for item %', v_commission, v_item.id;
+            END IF;
+
+            -- Accumulate store totals, less the platform's commission
+            INSERT INTO store_totals (store_id, total_amount)
+            VALUES (v_item.store_id, v_item_total - v_commission)
+            ON CONFLICT (store_id) DO UPDATE
+            SET total_amount = store_totals.total_amount + EXCLUDED.total_amount;
+
+            -- Create order
+            INSERT INTO orders (
+                item_id,
+                variant_id,
+                variant_sku,
+                item_price,
+                item_currency,
+                order_quantity,
+                buyer_id,
+                seller_id,
+                store_id,
+                delivery_fee,
+                payment_channel,
+                payment_method,
+                commission_rule_id,
+                commission_percentage,
+                commission_fixed,
+                commission_amount,
+                coupon_id,
+                discount_amount,
+                currency,
+                fx_rate,
+                tax_rate,
+                tax_inclusive,
+                tax_amount,
+                expected_delivery_date
+            ) VALUES (
+                v_item.id,
+                v_variant.id,
+                COALESCE(v_variant.sku, ''),
+                v_unit_price,
+                v_item.currency,
+                (v_cart_item->>'quantity')::int,
+                v_transaction.customer_id,
+                v_store_owner.user_id,
+                v_item.store_id,
+                v_delivery_fee,
+                v_account_type,
+                CASE
+                    WHEN v_transaction.payment_provider = 'PAYSTACK' THEN 'Instant Pay'
+                    ELSE 'Web Wallet'
+                END,
+                (v_cart_item->>'commission_rule_id')::bigint,
+                COALESCE((v_cart_item->>'commission_percentage')::numeric(5,2), 0),
+                COALESCE((v_cart_item->>'commission_fixed')::numeric(10,2), 0),
+                v_commission,
+                (v_cart_item->>'coupon_id')::bigint,
+                v_discount,
+                COALESCE(v_cart_item->>'currency', 'NGN'),
+                COALESCE((v_cart_item->>'fx_rate')::numeric(24,8), 1),
+                COALESCE((v_cart_item->>'tax_rate')::numeric(5,2), 0),
+                v_tax_inclusive,
+                v_tax,
+                now() + make_interval(days => COALESCE(NULLIF((v_cart_item->>'delivery_days')::int, 0), 3))
+            ) RETURNING id INTO v_order_id;
+
+            -- Add order ID to array
+            v_order_ids := array_append(v_order_ids, v_order_id);
+
+            -- Update item supply quantity
+            UPDATE items
+            SET supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int
+            WHERE id = v_item.id;
+
+            IF v_variant.id IS NOT NULL THEN
+                UPDATE item_variants
+                SET
+                    supply_quantity = supply_quantity - (v_cart_item->>'quantity')::int,
+                    updated_at = now()
+                WHERE id = v_variant.id;
+            END IF;
+        END LOOP;
+
+        -- Create/update pending funds for each store
+        FOR v_store_total IN SELECT * FROM store_totals
+        LOOP
+            PERFORM upsert_pending_funds(
+                v_store_total.store_id,
+                v_account_type,
+                v_store_total.total_amount
+            );
+        END LOOP;
+
+        -- Update transaction with order IDs
+        UPDATE transactions
+        SET order_ids = v_order_ids
+        WHERE provider_tx_ref_id = p_transaction_ref_id
+        RETURNING * INTO v_transaction;
+    END IF;
+
+    RETURN v_transaction;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,8 @@
+-- DOWN Migration
+
+DROP TABLE IF EXISTS "item_import_errors";
+DROP TABLE IF EXISTS "item_import_files";
+DROP TABLE IF EXISTS "item_imports";
+
+DROP INDEX IF EXISTS unique_item_sku;
+ALTER TABLE "items" DROP COLUMN IF EXISTS "sku";
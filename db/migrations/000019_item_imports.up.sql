@@ -0,0 +1,57 @@
+-- UP Migration
+
+-- Items get a SKU, unique among the items of their store, that CSV imports
+-- match them by. An item without one has an empty SKU.
+ALTER TABLE "items" ADD COLUMN "sku" varchar NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX unique_item_sku ON "items" ("store_id", "sku") WHERE "sku" <> '';
+
+-- Item Imports Table
+-- A CSV of items uploaded to a store, creating or updating an item per row
+-- by its SKU. An import is PENDING until a worker picks it up, PROCESSING
+-- while its rows are imported, then COMPLETED, or FAILED with an error when
+-- the file can't be read at all.
+CREATE TABLE "item_imports" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "uploaded_by" bigint NOT NULL,
+  "filename" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "error" varchar NOT NULL DEFAULT '',
+  "total_rows" int NOT NULL DEFAULT 0,
+  "created_rows" int NOT NULL DEFAULT 0,
+  "updated_rows" int NOT NULL DEFAULT 0,
+  "failed_rows" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "started_at" timestamptz,
+  "completed_at" timestamptz
+);
+ALTER TABLE "item_imports" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "item_imports" ADD FOREIGN KEY ("uploaded_by") REFERENCES "users" ("id");
+ALTER TABLE "item_imports" ADD CONSTRAINT valid_item_import CHECK (
+  "status" IN ('PENDING', 'PROCESSING', 'COMPLETED', 'FAILED')
+  AND "total_rows" >= 0
+  AND "created_rows" >= 0
+  AND "updated_rows" >= 0
+  AND "failed_rows" >= 0
+);
+CREATE INDEX ON "item_imports" ("store_id", "created_at");
+
+-- Item Import Files Table
+-- The uploaded CSV of an import, kept until its rows are imported.
+CREATE TABLE "item_import_files" (
+  "import_id" bigint PRIMARY KEY,
+  "content" bytea NOT NULL
+);
+ALTER TABLE "item_import_files" ADD FOREIGN KEY ("import_id") REFERENCES "item_imports" ("id") ON DELETE CASCADE;
+
+-- Item Import Errors Table
+-- Why a row of an import's CSV wasn't imported, by the line it starts on.
+CREATE TABLE "item_import_errors" (
+  "id" bigserial PRIMARY KEY,
+  "import_id" bigint NOT NULL,
+  "line" int NOT NULL,
+  "sku" varchar NOT NULL DEFAULT '',
+  "message" varchar NOT NULL
+);
+ALTER TABLE "item_import_errors" ADD FOREIGN KEY ("import_id") REFERENCES "item_imports" ("id") ON DELETE CASCADE;
+CREATE INDEX ON "item_import_errors" ("import_id", "line");
@@ -0,0 +1,10 @@
+-- DOWN Migration
+
+DROP TRIGGER IF EXISTS trigger_variant_stock_movement ON item_variants;
+DROP TRIGGER IF EXISTS trigger_item_stock_movement ON items;
+
+DROP FUNCTION IF EXISTS record_variant_stock_movement();
+DROP FUNCTION IF EXISTS record_item_stock_movement();
+DROP FUNCTION IF EXISTS record_stock_movement(bigint, bigint, bigint, bigint, bigint, boolean);
+
+DROP TABLE IF EXISTS "stock_movements";
@@ -0,0 +1,145 @@
+-- UP Migration
+
+-- Stock Movements Table
+-- Every change to the supply_quantity of an item, or of a variant for an item
+-- with variants, with why it changed, who changed it and the quantity it left.
+-- Movements are recorded by triggers, so no write to supply_quantity goes
+-- unrecorded; the code making the change tells them its reason, user,
+-- reference and note through settings local to its transaction (see
+-- record_stock_movement). Reserving stock for a checkout doesn't change
+-- supply_quantity, the checkout's completion does, as a SALE.
+CREATE TABLE "stock_movements" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "item_id" bigint NOT NULL,
+  "variant_id" bigint,
+  "reason" varchar NOT NULL,
+  "quantity_change" bigint NOT NULL,
+  "resulting_quantity" bigint NOT NULL,
+  "user_id" bigint,
+  "reference" varchar NOT NULL DEFAULT '',
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON DELETE CASCADE;
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("variant_id") REFERENCES "item_variants" ("id") ON DELETE SET NULL;
+ALTER TABLE "stock_movements" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+ALTER TABLE "stock_movements" ADD CONSTRAINT valid_stock_movement CHECK (
+  "reason" IN ('INITIAL', 'SALE', 'ADJUSTMENT', 'STOCK_TAKE', 'RETURN', 'IMPORT')
+  AND "quantity_change" <> 0
+);
+CREATE INDEX ON "stock_movements" ("store_id", "created_at");
+CREATE INDEX ON "stock_movements" ("item_id", "created_at");
+CREATE INDEX ON "stock_movements" ("store_id", "reference");
+
+-- Function: record_stock_movement
+-- Description: Records a change to the supply of an item, or of one of its
+-- variants, with the reason, user, reference and note set for the
+-- transaction under store_hub.stock_reason, store_hub.stock_user_id,
+-- store_hub.stock_reference and store_hub.stock_note. A change made without
+-- a reason is INITIAL when the item or variant is created, an ADJUSTMENT
+-- otherwise.
+CREATE OR REPLACE FUNCTION record_stock_movement(
+    p_store_id bigint,
+    p_item_id bigint,
+    p_variant_id bigint,
+    p_quantity_change bigint,
+    p_resulting_quantity bigint,
+    p_is_new boolean
+)
+RETURNS void AS $$
+BEGIN
+    INSERT INTO stock_movements (
+        store_id,
+        item_id,
+        variant_id,
+        reason,
+        quantity_change,
+        resulting_quantity,
+        user_id,
+        reference,
+        note
+    ) VALUES (
+        p_store_id,
+        p_item_id,
+        p_variant_id,
+        COALESCE(
+            NULLIF(current_setting('store_hub.stock_reason', true), ''),
+            CASE WHEN p_is_new THEN 'INITIAL' ELSE 'ADJUSTMENT' END
+        ),
+        p_quantity_change,
+        p_resulting_quantity,
+        NULLIF(current_setting('store_hub.stock_user_id', true), '')::bigint,
+        COALESCE(current_setting('store_hub.stock_reference', true), ''),
+        COALESCE(current_setting('store_hub.stock_note', true), '')
+    );
+END;
+$$ LANGUAGE plpgsql;
+
+-- Function: record_item_stock_movement
+-- Description: Records a change to an item's supply. The supply of an item
+-- with variants is the sum of theirs, so it's their movements that are
+-- recorded instead.
+CREATE OR REPLACE FUNCTION record_item_stock_movement()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_old_quantity bigint := 0;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        v_old_quantity := OLD.supply_quantity;
+    END IF;
+
+    IF NEW.supply_quantity = v_old_quantity
+        OR EXISTS (SELECT 1 FROM item_variants WHERE item_id = NEW.id) THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM record_stock_movement(
+        NEW.store_id,
+        NEW.id,
+        NULL,
+        NEW.supply_quantity - v_old_quantity,
+        NEW.supply_quantity,
+        TG_OP = 'INSERT'
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_item_stock_movement
+AFTER INSERT OR UPDATE OF supply_quantity ON items
+FOR EACH ROW
+EXECUTE FUNCTION record_item_stock_movement();
+
+-- Function: record_variant_stock_movement
+-- Description: Records a change to a variant's supply.
+CREATE OR REPLACE FUNCTION record_variant_stock_movement()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_old_quantity bigint := 0;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        v_old_quantity := OLD.supply_quantity;
+    END IF;
+
+    IF NEW.supply_quantity = v_old_quantity THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM record_stock_movement(
+        NEW.store_id,
+        NEW.item_id,
+        NEW.id,
+        NEW.supply_quantity - v_old_quantity,
+        NEW.supply_quantity,
+        TG_OP = 'INSERT'
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_variant_stock_movement
+AFTER INSERT OR UPDATE OF supply_quantity ON item_variants
+FOR EACH ROW
+EXECUTE FUNCTION record_variant_stock_movement();
@@ -0,0 +1,11 @@
+-- DOWN Migration
+
+DROP TRIGGER IF EXISTS trigger_stock_alert ON items;
+DROP FUNCTION IF EXISTS record_stock_alert();
+
+DROP TABLE IF EXISTS "stock_alerts";
+
+ALTER TABLE "items" DROP CONSTRAINT IF EXISTS valid_reorder_threshold;
+ALTER TABLE "items" DROP COLUMN IF EXISTS "reorder_threshold";
+ALTER TABLE "stores" DROP CONSTRAINT IF EXISTS valid_low_stock_threshold;
+ALTER TABLE "stores" DROP COLUMN IF EXISTS "low_stock_threshold";
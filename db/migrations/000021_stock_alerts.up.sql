@@ -0,0 +1,72 @@
+-- UP Migration
+
+-- A store's items run low at or below its low_stock_threshold, unless an item
+-- has a reorder_threshold of its own. A threshold of 0 alerts only when an
+-- item runs out.
+ALTER TABLE "stores" ADD COLUMN "low_stock_threshold" int NOT NULL DEFAULT 0;
+ALTER TABLE "stores" ADD CONSTRAINT valid_low_stock_threshold CHECK ("low_stock_threshold" >= 0);
+ALTER TABLE "items" ADD COLUMN "reorder_threshold" int;
+ALTER TABLE "items" ADD CONSTRAINT valid_reorder_threshold CHECK ("reorder_threshold" >= 0);
+
+-- Stock Alerts Table
+-- An item's supply falling to its threshold (LOW_STOCK) or to nothing
+-- (OUT_OF_STOCK), waiting to be emailed to the store's staff until sent_at.
+CREATE TABLE "stock_alerts" (
+  "id" bigserial PRIMARY KEY,
+  "store_id" bigint NOT NULL,
+  "item_id" bigint NOT NULL,
+  "kind" varchar NOT NULL,
+  "supply_quantity" bigint NOT NULL,
+  "threshold" int NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz
+);
+ALTER TABLE "stock_alerts" ADD FOREIGN KEY ("store_id") REFERENCES "stores" ("id") ON DELETE CASCADE;
+ALTER TABLE "stock_alerts" ADD FOREIGN KEY ("item_id") REFERENCES "items" ("id") ON DELETE CASCADE;
+ALTER TABLE "stock_alerts" ADD CONSTRAINT valid_stock_alert CHECK (
+  "kind" IN ('LOW_STOCK', 'OUT_OF_STOCK')
+);
+CREATE INDEX ON "stock_alerts" ("store_id", "id") WHERE "sent_at" IS NULL;
+CREATE INDEX ON "stock_alerts" ("item_id", "kind") WHERE "sent_at" IS NULL;
+
+-- Function: record_stock_alert
+-- Description: Records an alert when an item's supply crosses its threshold
+-- or runs out, unless the same alert is still waiting to be sent. The
+-- supply of an item with variants is the sum of theirs.
+CREATE OR REPLACE FUNCTION record_stock_alert()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_threshold int;
+    v_kind varchar;
+BEGIN
+    SELECT COALESCE(NEW.reorder_threshold, s.low_stock_threshold)
+    INTO v_threshold
+    FROM stores s
+    WHERE s.id = NEW.store_id;
+
+    IF NEW.supply_quantity <= 0 AND OLD.supply_quantity > 0 THEN
+        v_kind := 'OUT_OF_STOCK';
+    ELSIF NEW.supply_quantity <= v_threshold AND OLD.supply_quantity > v_threshold THEN
+        v_kind := 'LOW_STOCK';
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM stock_alerts
+        WHERE item_id = NEW.id AND kind = v_kind AND sent_at IS NULL
+    ) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO stock_alerts (store_id, item_id, kind, supply_quantity, threshold)
+    VALUES (NEW.store_id, NEW.id, v_kind, NEW.supply_quantity, v_threshold);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_stock_alert
+AFTER UPDATE OF supply_quantity ON items
+FOR EACH ROW
+WHEN (NEW.supply_quantity < OLD.supply_quantity)
+EXECUTE FUNCTION record_stock_alert();
@@ -0,0 +1,3 @@
+-- DOWN Migration
+
+DROP TABLE IF EXISTS "checkout_lines";
@@ -0,0 +1,24 @@
+-- UP Migration
+
+-- Checkout Lines Table
+-- The cart lines a checkout was priced from, saved when it starts so the
+-- transaction completes with what the buyer paid for, whatever happens to
+-- their cart in the meantime.
+CREATE TABLE "checkout_lines" (
+  "id" bigserial PRIMARY KEY,
+  "reference" varchar NOT NULL,
+  "item_id" bigint NOT NULL,
+  "variant_id" bigint,
+  "variant_sku" varchar NOT NULL DEFAULT '',
+  "store_id" bigint NOT NULL,
+  "item_name" varchar NOT NULL,
+  "price" NUMERIC(10, 2) NOT NULL,
+  "discount_percentage" NUMERIC(6, 4) NOT NULL,
+  "item_category" varchar NOT NULL,
+  "item_currency" varchar(3) NOT NULL,
+  "item_weight_grams" bigint NOT NULL,
+  "quantity" int NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "checkout_lines" ADD CONSTRAINT valid_checkout_line CHECK ("quantity" > 0);
+CREATE INDEX ON "checkout_lines" ("reference");
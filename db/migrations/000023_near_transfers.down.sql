@@ -0,0 +1,61 @@
+-- DOWN Migration
+
+DROP TABLE IF EXISTS "near_transfers";
+
+DROP FUNCTION IF EXISTS initialize_transaction(bigint, NUMERIC, varchar, varchar, varchar, NUMERIC);
+
+-- Function: initialize_transaction
+-- Description: Initialize transaction record. A checkout paid in full with
+-- gift cards and store credit charges nothing through its provider, so the
+-- amount may be zero.
+CREATE OR REPLACE FUNCTION initialize_transaction(
+    p_customer_id bigint,
+    p_amount NUMERIC(18, 2),
+    p_payment_provider varchar,
+    p_provider_tx_ref_id varchar,
+    p_provider_tx_access_code varchar
+) RETURNS transactions AS $$
+DECLARE
+    v_customer_exists bool;
+    v_result transactions%ROWTYPE;
+BEGIN
+    -- Validate customer exists
+    SELECT EXISTS(
+        SELECT 1 FROM users WHERE id = p_customer_id
+    ) INTO v_customer_exists;
+    
+    IF NOT v_customer_exists THEN
+        RAISE EXCEPTION 'Customer with ID % does not exist', p_customer_id;
+    END IF;
+
+    -- Validate amount is not negative
+    IF p_amount < 0 THEN
+        RAISE EXCEPTION 'Amount must not be negative';
+    END IF;
+
+    -- Create initial transaction record
+    INSERT INTO transactions (
+        customer_id,
+        amount,
+        payment_provider,
+        provider_tx_ref_id,
+        provider_tx_access_code,
+        provider_tx_fee,
+        status,
+        order_ids
+    ) VALUES (
+        p_customer_id,
+        p_amount,
+        p_payment_provider,
+        p_provider_tx_ref_id,
+        NULLIF(p_provider_tx_access_code, ''),
+        0,
+        'PROCESSING',
+        '{}'::bigint[]
+    ) RETURNING * INTO v_result;
+
+    RETURN v_result;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "transactions" DROP COLUMN IF EXISTS "near_amount";
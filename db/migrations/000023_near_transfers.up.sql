@@ -0,0 +1,89 @@
+-- UP Migration
+
+-- The yoctoNEAR a NEAR wallet payment was quoted at, locked at the NEAR price
+-- when the buyer paid. Refunds are converted at the same rate.
+ALTER TABLE "transactions" ADD COLUMN "near_amount" NUMERIC(40, 0);
+
+DROP FUNCTION IF EXISTS initialize_transaction(bigint, NUMERIC, varchar, varchar, varchar);
+
+-- Function: initialize_transaction
+-- Description: Initialize transaction record. A checkout paid in full with
+-- gift cards and store credit charges nothing through its provider, so the
+-- amount may be zero. A NEAR wallet payment records the yoctoNEAR the buyer
+-- is asked to pay as near_amount.
+CREATE OR REPLACE FUNCTION initialize_transaction(
+    p_customer_id bigint,
+    p_amount NUMERIC(18, 2),
+    p_payment_provider varchar,
+    p_provider_tx_ref_id varchar,
+    p_provider_tx_access_code varchar,
+    p_near_amount NUMERIC(40, 0)
+) RETURNS transactions AS $$
+DECLARE
+    v_customer_exists bool;
+    v_result transactions%ROWTYPE;
+BEGIN
+    -- Validate customer exists
+    SELECT EXISTS(
+        SELECT 1 FROM users WHERE id = p_customer_id
+    ) INTO v_customer_exists;
+    
+    IF NOT v_customer_exists THEN
+        RAISE EXCEPTION 'Customer with ID % does not exist', p_customer_id;
+    END IF;
+
+    -- Validate amount is not negative
+    IF p_amount < 0 THEN
+        RAISE EXCEPTION 'Amount must not be negative';
+    END IF;
+
+    -- Create initial transaction record
+    INSERT INTO transactions (
+        customer_id,
+        amount,
+        payment_provider,
+        provider_tx_ref_id,
+        provider_tx_access_code,
+        provider_tx_fee,
+        status,
+        order_ids,
+        near_amount
+    ) VALUES (
+        p_customer_id,
+        p_amount,
+        p_payment_provider,
+        p_provider_tx_ref_id,
+        NULLIF(p_provider_tx_access_code, ''),
+        0,
+        'PROCESSING',
+        '{}'::bigint[],
+        p_near_amount
+    ) RETURNING * INTO v_result;
+
+    RETURN v_result;
+END;
+$$ LANGUAGE plpgsql;
+
+-- NEAR Transfers Table
+-- NEAR sent from the master account to refund a buyer. The transfer's signed
+-- transaction is saved before it's sent, so a retry looks it up on chain and
+-- sends the same one again, rather than signing another that pays twice.
+CREATE TABLE "near_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "reference" varchar NOT NULL,
+  "refund_id" bigint,
+  "receiver_id" varchar NOT NULL,
+  "amount" NUMERIC(40, 0) NOT NULL,
+  "signed_tx" bytea,
+  "tx_hash" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "failure_reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "near_transfers" ADD FOREIGN KEY ("refund_id") REFERENCES "refunds" ("id");
+ALTER TABLE "near_transfers" ADD CONSTRAINT valid_near_transfer CHECK (
+  "status" IN ('PENDING', 'COMPLETED', 'FAILED') AND "amount" > 0
+);
+CREATE UNIQUE INDEX ON "near_transfers" ("refund_id");
+CREATE INDEX ON "near_transfers" ("reference");
@@ -0,0 +1,5 @@
+-- DOWN Migration
+
+ALTER TABLE "near_transfers" DROP COLUMN IF EXISTS "checkout_refund_id";
+
+DROP TABLE IF EXISTS "checkout_refunds";
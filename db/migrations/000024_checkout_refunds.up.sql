@@ -0,0 +1,27 @@
+-- UP Migration
+
+-- Checkout Refunds Table
+-- A refund of everything a checkout's provider charged, for a checkout that
+-- was paid but couldn't be completed, so it has no orders to refund. A
+-- transaction is refunded this way at most once.
+CREATE TABLE "checkout_refunds" (
+  "id" bigserial PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "amount" NUMERIC(18, 2) NOT NULL,
+  "reason" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'PENDING',
+  "provider_refund_id" varchar NOT NULL DEFAULT '',
+  "failure_reason" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+ALTER TABLE "checkout_refunds" ADD FOREIGN KEY ("transaction_id") REFERENCES "transactions" ("id");
+ALTER TABLE "checkout_refunds" ADD CONSTRAINT valid_checkout_refund CHECK (
+  "status" IN ('PENDING', 'COMPLETED', 'FAILED') AND "amount" > 0
+);
+CREATE UNIQUE INDEX ON "checkout_refunds" ("transaction_id");
+
+-- A NEAR transfer sends either an order's refund or a checkout's.
+ALTER TABLE "near_transfers" ADD COLUMN "checkout_refund_id" bigint;
+ALTER TABLE "near_transfers" ADD FOREIGN KEY ("checkout_refund_id") REFERENCES "checkout_refunds" ("id");
+CREATE UNIQUE INDEX ON "near_transfers" ("checkout_refund_id");
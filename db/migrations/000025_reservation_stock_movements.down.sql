@@ -0,0 +1,13 @@
+-- DOWN Migration
+
+DROP TRIGGER IF EXISTS trigger_reservation_stock_movement ON stock_reservations;
+
+DROP FUNCTION IF EXISTS record_reservation_stock_movement();
+
+DELETE FROM stock_movements WHERE reason IN ('RESERVATION', 'RELEASE');
+
+ALTER TABLE "stock_movements" DROP CONSTRAINT valid_stock_movement;
+ALTER TABLE "stock_movements" ADD CONSTRAINT valid_stock_movement CHECK (
+  "reason" IN ('INITIAL', 'SALE', 'ADJUSTMENT', 'STOCK_TAKE', 'RETURN', 'IMPORT')
+  AND "quantity_change" <> 0
+);
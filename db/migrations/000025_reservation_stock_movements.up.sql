@@ -0,0 +1,80 @@
+-- UP Migration
+
+-- Stock held for a checkout is recorded as a RESERVATION movement, taking
+-- its quantity out of what's available, and a RELEASE putting it back
+-- however the reservation ends: released, expired or converted into the
+-- checkout's SALE. Neither changes supply_quantity, so their
+-- resulting_quantity is what's left available, net of live reservations,
+-- rather than the supply.
+ALTER TABLE "stock_movements" DROP CONSTRAINT valid_stock_movement;
+ALTER TABLE "stock_movements" ADD CONSTRAINT valid_stock_movement CHECK (
+  "reason" IN ('INITIAL', 'SALE', 'ADJUSTMENT', 'STOCK_TAKE', 'RETURN', 'IMPORT', 'RESERVATION', 'RELEASE')
+  AND "quantity_change" <> 0
+);
+
+-- Function: record_reservation_stock_movement
+-- Description: Records a reservation being made as a RESERVATION, and it
+-- ending as a RELEASE, for its variant if it has one, to the buyer and under
+-- the checkout's reference.
+CREATE OR REPLACE FUNCTION record_reservation_stock_movement()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_reason varchar;
+    v_quantity_change bigint;
+    v_store_id bigint;
+    v_supply bigint;
+    v_reserved bigint;
+BEGIN
+    IF TG_OP = 'INSERT' AND NEW.status = 'RESERVED' THEN
+        v_reason := 'RESERVATION';
+        v_quantity_change := -NEW.quantity;
+    ELSIF TG_OP = 'UPDATE' AND OLD.status = 'RESERVED' AND NEW.status <> 'RESERVED' THEN
+        v_reason := 'RELEASE';
+        v_quantity_change := NEW.quantity;
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    IF NEW.variant_id IS NULL THEN
+        SELECT store_id, supply_quantity INTO v_store_id, v_supply
+        FROM items WHERE id = NEW.item_id;
+
+        SELECT COALESCE(sum(quantity), 0) INTO v_reserved
+        FROM stock_reservations
+        WHERE item_id = NEW.item_id AND status = 'RESERVED' AND expires_at > now();
+    ELSE
+        SELECT store_id, supply_quantity INTO v_store_id, v_supply
+        FROM item_variants WHERE id = NEW.variant_id;
+
+        SELECT COALESCE(sum(quantity), 0) INTO v_reserved
+        FROM stock_reservations
+        WHERE variant_id = NEW.variant_id AND status = 'RESERVED' AND expires_at > now();
+    END IF;
+
+    INSERT INTO stock_movements (
+        store_id,
+        item_id,
+        variant_id,
+        reason,
+        quantity_change,
+        resulting_quantity,
+        user_id,
+        reference
+    ) VALUES (
+        v_store_id,
+        NEW.item_id,
+        NEW.variant_id,
+        v_reason,
+        v_quantity_change,
+        v_supply - v_reserved,
+        NEW.user_id,
+        NEW.reference
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_reservation_stock_movement
+AFTER INSERT OR UPDATE OF status ON stock_reservations
+FOR EACH ROW
+EXECUTE FUNCTION record_reservation_stock_movement();
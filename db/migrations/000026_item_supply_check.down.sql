@@ -0,0 +1,3 @@
+-- DOWN Migration
+
+ALTER TABLE "items" DROP CONSTRAINT IF EXISTS valid_item_supply;
@@ -0,0 +1,5 @@
+-- UP Migration
+
+-- Flash sale checkouts don't lock their item's row to check its stock, so a
+-- sale that would take its supply below zero is refused here instead.
+ALTER TABLE "items" ADD CONSTRAINT valid_item_supply CHECK ("supply_quantity" >= 0);
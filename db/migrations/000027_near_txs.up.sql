@@ -0,0 +1,14 @@
+-- UP Migration
+
+-- NEAR Transactions Table
+-- The transaction a NEAR task sends from the master account, such as one
+-- creating a sub-account. It's saved under the task's id before it's sent, so
+-- a retry looks it up on chain and sends the same one again, rather than
+-- signing another.
+CREATE TABLE "near_txs" (
+  "task_id" varchar PRIMARY KEY,
+  "signed_tx" bytea NOT NULL,
+  "tx_hash" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
@@ -17,9 +17,17 @@ WHERE
 SELECT 
   c.id AS cart_id,
   ci.item_id,
+  ci.variant_id,
+  COALESCE(v.sku, '')::varchar AS variant_sku,
+  COALESCE(v.options, '{}')::jsonb AS variant_options,
+  ci.store_id,
   i.name AS item_name,
   i.description AS item_description,
-  i.price,
+  COALESCE(v.price, i.price)::NUMERIC(10, 2) AS price,
+  i.discount_percentage,
+  i.category AS item_category,
+  i.currency AS item_currency,
+  i.weight_grams AS item_weight_grams,
   ci.quantity,
   i.cover_img_url AS item_image
 FROM 
@@ -28,32 +36,42 @@ JOIN
   cart_items ci ON c.id = ci.cart_id
 JOIN 
   items i ON ci.item_id = i.id
+LEFT JOIN
+  item_variants v ON ci.variant_id = v.id
 WHERE 
-  c.user_id = sqlc.arg(user_id);
+  c.user_id = sqlc.arg(user_id)
+ORDER BY
+  ci.id;
 
 -- name: UpsertCartItem :one
 SELECT * FROM upsert_cart_item(
   sqlc.arg(cart_id)::bigint,
   sqlc.arg(item_id)::bigint,
-  sqlc.arg(store_id)::bigint
+  sqlc.arg(store_id)::bigint,
+  sqlc.narg(variant_id)::bigint
 );
 
 -- name: RemoveItemFromCart :exec
+-- Removes an item from a cart, only in a variant when one is given.
 DELETE FROM cart_items
-WHERE cart_id = sqlc.arg(cart_id) AND item_id = sqlc.arg(item_id);
+WHERE cart_id = sqlc.arg(cart_id)
+  AND item_id = sqlc.arg(item_id)
+  AND (sqlc.narg(variant_id)::bigint IS NULL OR variant_id = sqlc.narg(variant_id)::bigint);
 
 -- name: IncreaseCartItemQuantity :one
 WITH item_supply AS (
-  SELECT supply_quantity 
-  FROM items 
-  WHERE id = sqlc.arg(item_id)
+  SELECT COALESCE(
+    (SELECT v.supply_quantity FROM item_variants v WHERE v.id = sqlc.narg(variant_id)::bigint),
+    (SELECT i.supply_quantity FROM items i WHERE i.id = sqlc.arg(item_id)::bigint)
+  ) AS supply_quantity
 )
 UPDATE cart_items 
 SET 
   quantity = LEAST((SELECT supply_quantity FROM item_supply), quantity + sqlc.arg(increase_amount))
 WHERE 
-  cart_id = sqlc.arg(cart_id) 
-  AND item_id = sqlc.arg(item_id)
+  cart_items.cart_id = sqlc.arg(cart_id) 
+  AND cart_items.item_id = sqlc.arg(item_id)::bigint
+  AND cart_items.variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)::bigint
 RETURNING *;
 
 -- name: DecreaseCartItemQuantity :one
@@ -62,4 +80,58 @@ SET
   quantity = GREATEST(1, cart_items.quantity - sqlc.arg(decrease_amount))
 WHERE 
   cart_id = sqlc.arg(cart_id) AND item_id = sqlc.arg(item_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)::bigint
 RETURNING *;
+
+
+-- name: ClearCart :exec
+DELETE FROM cart_items
+WHERE cart_id = sqlc.arg(cart_id);
+-- name: CreateCheckoutLine :exec
+INSERT INTO checkout_lines (
+  reference,
+  item_id,
+  variant_id,
+  variant_sku,
+  store_id,
+  item_name,
+  price,
+  discount_percentage,
+  item_category,
+  item_currency,
+  item_weight_grams,
+  quantity
+) VALUES (
+  sqlc.arg(reference), sqlc.arg(item_id), sqlc.narg(variant_id), sqlc.arg(variant_sku),
+  sqlc.arg(store_id), sqlc.arg(item_name), sqlc.arg(price), sqlc.arg(discount_percentage),
+  sqlc.arg(item_category), sqlc.arg(item_currency), sqlc.arg(item_weight_grams), sqlc.arg(quantity)
+);
+
+-- name: ListCheckoutLines :many
+SELECT * FROM checkout_lines
+WHERE reference = sqlc.arg(reference)
+ORDER BY id;
+
+-- name: RemoveCheckoutLinesFromCart :exec
+-- Takes the lines bought under reference out of a cart, leaving what was
+-- added to it since.
+WITH bought AS (
+  SELECT item_id, variant_id, quantity FROM checkout_lines
+  WHERE reference = sqlc.arg(reference)
+), removed AS (
+  DELETE FROM cart_items ci
+  USING bought b
+  WHERE ci.cart_id = sqlc.arg(cart_id)
+    AND ci.item_id = b.item_id
+    AND ci.variant_id IS NOT DISTINCT FROM b.variant_id
+    AND ci.quantity <= b.quantity
+)
+UPDATE cart_items ci
+SET
+  quantity = ci.quantity - b.quantity,
+  updated_at = now()
+FROM bought b
+WHERE ci.cart_id = sqlc.arg(cart_id)
+  AND ci.item_id = b.item_id
+  AND ci.variant_id IS NOT DISTINCT FROM b.variant_id
+  AND ci.quantity > b.quantity;
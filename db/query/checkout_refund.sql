@@ -0,0 +1,41 @@
+-- name: CreateCheckoutRefund :one
+INSERT INTO checkout_refunds (
+  transaction_id,
+  amount,
+  reason
+) VALUES (
+  sqlc.arg(transaction_id), sqlc.arg(amount), sqlc.arg(reason)
+) RETURNING *;
+
+-- name: GetCheckoutRefund :one
+SELECT
+  cr.*,
+  t.provider_tx_ref_id,
+  t.payment_provider
+FROM checkout_refunds cr
+JOIN transactions t ON t.id = cr.transaction_id
+WHERE cr.id = sqlc.arg(checkout_refund_id);
+
+-- name: GetCheckoutRefundByTransaction :one
+SELECT * FROM checkout_refunds
+WHERE transaction_id = sqlc.arg(transaction_id);
+
+-- name: CompleteCheckoutRefund :one
+UPDATE checkout_refunds
+SET
+  status = 'COMPLETED',
+  provider_refund_id = sqlc.arg(provider_refund_id),
+  updated_at = now()
+WHERE id = sqlc.arg(checkout_refund_id)
+  AND status = 'PENDING'
+RETURNING *;
+
+-- name: FailCheckoutRefund :one
+UPDATE checkout_refunds
+SET
+  status = 'FAILED',
+  failure_reason = sqlc.arg(failure_reason),
+  updated_at = now()
+WHERE id = sqlc.arg(checkout_refund_id)
+  AND status = 'PENDING'
+RETURNING *;
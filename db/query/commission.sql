@@ -0,0 +1,43 @@
+-- name: CreateCommissionRule :one
+INSERT INTO commission_rules (
+  scope,
+  store_id,
+  category,
+  percentage,
+  fixed_amount,
+  created_by
+) VALUES (
+  sqlc.arg(scope), sqlc.narg(store_id), sqlc.narg(category), sqlc.arg(percentage), sqlc.arg(fixed_amount), sqlc.arg(created_by)
+)
+RETURNING *;
+
+-- name: GetCommissionRule :one
+SELECT * FROM commission_rules
+WHERE id = sqlc.arg(rule_id);
+
+-- name: UpdateCommissionRule :one
+UPDATE commission_rules
+SET
+  percentage = COALESCE(sqlc.narg(percentage), percentage),
+  fixed_amount = COALESCE(sqlc.narg(fixed_amount), fixed_amount),
+  updated_at = now()
+WHERE id = sqlc.arg(rule_id)
+RETURNING *;
+
+-- name: DeleteCommissionRule :execrows
+DELETE FROM commission_rules
+WHERE id = sqlc.arg(rule_id);
+
+-- name: ListCommissionRules :many
+SELECT * FROM commission_rules
+WHERE
+  (sqlc.narg(scope)::varchar IS NULL OR scope = sqlc.narg(scope))
+  AND (sqlc.narg(store_id)::bigint IS NULL OR store_id = sqlc.narg(store_id))
+ORDER BY scope, id;
+
+-- name: ListApplicableCommissionRules :many
+SELECT * FROM commission_rules
+WHERE
+  scope = 'GLOBAL'
+  OR (scope = 'STORE' AND store_id = ANY(sqlc.arg(store_ids)::bigint[]))
+  OR (scope = 'CATEGORY' AND category = ANY(sqlc.arg(categories)::varchar[]));
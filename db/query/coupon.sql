@@ -0,0 +1,146 @@
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+  store_id,
+  code,
+  description,
+  discount_type,
+  discount_value,
+  scope,
+  item_ids,
+  category,
+  min_spend,
+  usage_limit,
+  per_user_limit,
+  starts_at,
+  ends_at,
+  is_active,
+  created_by
+) VALUES (
+  sqlc.arg(store_id), upper(sqlc.arg(code)::varchar), sqlc.arg(description), sqlc.arg(discount_type),
+  sqlc.arg(discount_value), sqlc.arg(scope), sqlc.arg(item_ids)::bigint[], sqlc.arg(category),
+  sqlc.arg(min_spend), sqlc.arg(usage_limit), sqlc.arg(per_user_limit), sqlc.arg(starts_at),
+  sqlc.narg(ends_at), sqlc.arg(is_active), sqlc.arg(created_by)
+)
+RETURNING *;
+
+-- name: GetCoupon :one
+SELECT * FROM coupons
+WHERE id = sqlc.arg(coupon_id)
+  AND store_id = sqlc.arg(store_id);
+
+-- name: GetCouponByCode :one
+SELECT * FROM coupons
+WHERE code = upper(sqlc.arg(code)::varchar);
+
+-- name: GetCouponForUpdate :one
+SELECT * FROM coupons
+WHERE id = sqlc.arg(coupon_id)
+FOR UPDATE;
+
+-- name: UpdateCoupon :one
+UPDATE coupons
+SET
+  description = COALESCE(sqlc.narg(description), description),
+  min_spend = COALESCE(sqlc.narg(min_spend), min_spend),
+  usage_limit = COALESCE(sqlc.narg(usage_limit), usage_limit),
+  per_user_limit = COALESCE(sqlc.narg(per_user_limit), per_user_limit),
+  starts_at = COALESCE(sqlc.narg(starts_at), starts_at),
+  ends_at = COALESCE(sqlc.narg(ends_at), ends_at),
+  is_active = COALESCE(sqlc.narg(is_active), is_active),
+  updated_at = now()
+WHERE id = sqlc.arg(coupon_id)
+  AND store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: DeleteCoupon :execrows
+DELETE FROM coupons c
+WHERE c.id = sqlc.arg(coupon_id)
+  AND c.store_id = sqlc.arg(store_id)
+  AND NOT EXISTS (SELECT 1 FROM coupon_redemptions cr WHERE cr.coupon_id = c.id);
+
+-- name: ListStoreCoupons :many
+SELECT
+  count(*) OVER() AS total_count,
+  c.*,
+  (
+    SELECT count(*) FROM coupon_redemptions cr
+    WHERE cr.coupon_id = c.id AND cr.status <> 'RELEASED'
+  ) AS times_redeemed
+FROM coupons c
+WHERE c.store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(is_active)::boolean IS NULL OR c.is_active = sqlc.narg(is_active))
+ORDER BY c.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: AddCartCoupon :one
+INSERT INTO cart_coupons (
+  cart_id,
+  coupon_id,
+  store_id
+) VALUES (
+  sqlc.arg(cart_id), sqlc.arg(coupon_id), sqlc.arg(store_id)
+)
+ON CONFLICT (cart_id, store_id) DO UPDATE
+SET
+  coupon_id = EXCLUDED.coupon_id,
+  applied_at = now()
+RETURNING *;
+
+-- name: RemoveCartCoupon :execrows
+DELETE FROM cart_coupons
+WHERE cart_id = sqlc.arg(cart_id)
+  AND coupon_id = sqlc.arg(coupon_id);
+
+-- name: ListCartCoupons :many
+SELECT c.* FROM coupons c
+JOIN cart_coupons cc ON cc.coupon_id = c.id
+WHERE cc.cart_id = sqlc.arg(cart_id)
+ORDER BY cc.applied_at;
+
+-- name: ClearCartCoupons :exec
+DELETE FROM cart_coupons
+WHERE cart_id = sqlc.arg(cart_id);
+
+-- name: CountCouponRedemptions :one
+SELECT
+  count(*) AS total,
+  count(*) FILTER (WHERE user_id = sqlc.arg(user_id)) AS by_user
+FROM coupon_redemptions
+WHERE coupon_id = sqlc.arg(coupon_id)
+  AND status <> 'RELEASED';
+
+-- name: CreateCouponRedemption :one
+INSERT INTO coupon_redemptions (
+  coupon_id,
+  user_id,
+  reference,
+  discount_amount,
+  status
+) VALUES (
+  sqlc.arg(coupon_id), sqlc.arg(user_id), sqlc.arg(reference), sqlc.arg(discount_amount), sqlc.arg(status)
+)
+RETURNING *;
+
+-- name: ListRedeemedCoupons :many
+SELECT c.* FROM coupons c
+JOIN coupon_redemptions cr ON cr.coupon_id = c.id
+WHERE cr.reference = sqlc.arg(reference)
+  AND cr.status = sqlc.arg(status)
+ORDER BY cr.id;
+
+-- name: UpdateCouponRedemptionsStatus :execrows
+UPDATE coupon_redemptions
+SET
+  status = sqlc.arg(to_status),
+  updated_at = now()
+WHERE reference = sqlc.arg(reference)
+  AND status = sqlc.arg(from_status);
+
+-- name: SetOrderCoupon :one
+UPDATE orders
+SET
+  coupon_id = sqlc.arg(coupon_id),
+  discount_amount = sqlc.arg(discount_amount)
+WHERE id = sqlc.arg(order_id)
+RETURNING *;
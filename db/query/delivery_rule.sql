@@ -0,0 +1,24 @@
+-- name: UpsertStoreDeliveryRule :one
+INSERT INTO store_delivery_rules (
+  store_id,
+  base_fee,
+  per_item_fee,
+  free_delivery_threshold
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(base_fee), sqlc.arg(per_item_fee), sqlc.arg(free_delivery_threshold)
+)
+ON CONFLICT (store_id) DO UPDATE
+SET
+  base_fee = EXCLUDED.base_fee,
+  per_item_fee = EXCLUDED.per_item_fee,
+  free_delivery_threshold = EXCLUDED.free_delivery_threshold,
+  updated_at = now()
+RETURNING *;
+
+-- name: GetStoreDeliveryRule :one
+SELECT * FROM store_delivery_rules
+WHERE store_id = sqlc.arg(store_id);
+
+-- name: ListStoreDeliveryRules :many
+SELECT * FROM store_delivery_rules
+WHERE store_id = ANY(sqlc.arg(store_ids)::bigint[]);
@@ -0,0 +1,189 @@
+-- name: CreateFlashSale :one
+INSERT INTO flash_sales (
+  store_id,
+  item_id,
+  sale_price,
+  quantity,
+  per_user_limit,
+  starts_at,
+  ends_at,
+  created_by
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(item_id), sqlc.arg(sale_price), sqlc.arg(quantity),
+  sqlc.arg(per_user_limit), sqlc.arg(starts_at), sqlc.arg(ends_at), sqlc.arg(created_by)
+) RETURNING *;
+
+-- name: CountOverlappingFlashSales :one
+SELECT count(*) FROM flash_sales
+WHERE item_id = sqlc.arg(item_id)
+  AND cancelled_at IS NULL
+  AND starts_at < sqlc.arg(ends_at)
+  AND ends_at > sqlc.arg(starts_at);
+
+-- name: GetFlashSale :one
+SELECT * FROM flash_sales
+WHERE id = sqlc.arg(flash_sale_id) AND store_id = sqlc.arg(store_id);
+
+-- name: CancelFlashSale :one
+-- Cancels a flash sale that hasn't ended.
+UPDATE flash_sales
+SET
+  cancelled_at = now(),
+  updated_at = now()
+WHERE id = sqlc.arg(flash_sale_id)
+  AND store_id = sqlc.arg(store_id)
+  AND cancelled_at IS NULL
+  AND ends_at > now()
+RETURNING *;
+
+-- name: ListStoreFlashSales :many
+SELECT
+  count(*) OVER() AS total_count,
+  fs.*,
+  i.name AS item_name
+FROM flash_sales fs
+JOIN items i ON i.id = fs.item_id
+WHERE fs.store_id = sqlc.arg(store_id)
+ORDER BY fs.starts_at DESC, fs.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: ListUpcomingFlashSales :many
+-- A store's flash sales that are running or yet to start.
+SELECT
+  count(*) OVER() AS total_count,
+  fs.*,
+  i.name AS item_name,
+  i.price AS item_price,
+  i.currency AS item_currency,
+  i.cover_img_url AS item_image
+FROM flash_sales fs
+JOIN items i ON i.id = fs.item_id
+WHERE fs.store_id = sqlc.arg(store_id)
+  AND fs.cancelled_at IS NULL
+  AND fs.ends_at > now()
+ORDER BY fs.starts_at, fs.id
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: GetItemFlashSale :one
+-- The flash sale an item is on, or its next one.
+SELECT * FROM flash_sales
+WHERE item_id = sqlc.arg(item_id)
+  AND cancelled_at IS NULL
+  AND ends_at > now()
+ORDER BY starts_at
+LIMIT 1;
+
+-- name: ListLiveFlashSales :many
+SELECT * FROM flash_sales
+WHERE item_id = ANY(sqlc.arg(item_ids)::bigint[])
+  AND cancelled_at IS NULL
+  AND starts_at <= now()
+  AND ends_at > now();
+
+-- name: GetFlashSaleHeldStock :one
+-- The units of an item its flash sales hold out of its available stock: those
+-- not sold yet of sales that haven't ended, and those claimed by checkouts
+-- not completed yet of sales that have.
+SELECT (
+  COALESCE((
+    SELECT sum(GREATEST(fs.quantity - fs.sold_quantity, 0))
+    FROM flash_sales fs
+    WHERE fs.item_id = sqlc.arg(item_id)
+      AND fs.cancelled_at IS NULL
+      AND fs.ends_at > now()
+  ), 0)
+  + COALESCE((
+    SELECT sum(fsc.quantity)
+    FROM flash_sale_claims fsc
+    JOIN flash_sales fs ON fs.id = fsc.flash_sale_id
+    WHERE fs.item_id = sqlc.arg(item_id)
+      AND fsc.status = 'CLAIMED'
+      AND (fs.cancelled_at IS NOT NULL OR fs.ends_at <= now())
+  ), 0)
+)::bigint AS held;
+
+-- name: ListCartFlashSales :many
+-- The flash sales running on the items in a user's cart, with how many of
+-- each item the cart holds, in all its variants.
+SELECT
+  fs.*,
+  sum(ci.quantity)::int AS cart_quantity
+FROM carts c
+JOIN cart_items ci ON ci.cart_id = c.id
+JOIN flash_sales fs ON fs.item_id = ci.item_id
+WHERE c.user_id = sqlc.arg(user_id)
+  AND fs.cancelled_at IS NULL
+  AND fs.starts_at <= now()
+  AND fs.ends_at > now()
+GROUP BY fs.id;
+
+-- name: CreateFlashSaleClaim :one
+INSERT INTO flash_sale_claims (
+  flash_sale_id,
+  user_id,
+  reference,
+  quantity
+) VALUES (
+  sqlc.arg(flash_sale_id), sqlc.arg(user_id), sqlc.arg(reference), sqlc.arg(quantity)
+) RETURNING *;
+
+-- name: ListCheckoutFlashSales :many
+-- The flash sales claimed for the checkout under reference, whether or not
+-- they've ended since.
+SELECT fs.* FROM flash_sales fs
+JOIN flash_sale_claims fsc ON fsc.flash_sale_id = fs.id
+WHERE fsc.reference = sqlc.arg(reference)
+  AND fsc.status = sqlc.arg(status);
+
+-- name: UpdateFlashSaleClaimsStatus :many
+UPDATE flash_sale_claims
+SET
+  status = sqlc.arg(to_status),
+  updated_at = now()
+WHERE reference = sqlc.arg(reference)
+  AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: AddFlashSaleSold :exec
+UPDATE flash_sales
+SET
+  sold_quantity = sold_quantity + sqlc.arg(quantity)::int,
+  updated_at = now()
+WHERE id = sqlc.arg(flash_sale_id);
+
+-- name: ListUnreturnedFlashSaleClaims :many
+-- RELEASED claims whose units haven't been given back in Redis yet.
+SELECT
+  fsc.*,
+  fs.ends_at
+FROM flash_sale_claims fsc
+JOIN flash_sales fs ON fs.id = fsc.flash_sale_id
+WHERE fsc.status = 'RELEASED'
+  AND fsc.returned_at IS NULL
+ORDER BY fsc.id
+LIMIT sqlc.arg(rw_limit);
+
+-- name: SetFlashSaleClaimReturned :exec
+UPDATE flash_sale_claims
+SET
+  returned_at = now(),
+  updated_at = now()
+WHERE id = sqlc.arg(claim_id);
+
+-- name: ListFlashSalesToReconcile :many
+-- Flash sales that have started and not been reconciled since they ended.
+SELECT * FROM flash_sales
+WHERE starts_at <= now()
+  AND (reconciled_at IS NULL OR reconciled_at < ends_at)
+ORDER BY id
+LIMIT sqlc.arg(rw_limit);
+
+-- name: SetFlashSaleClaimedQuantity :exec
+UPDATE flash_sales
+SET
+  claimed_quantity = sqlc.arg(claimed_quantity),
+  reconciled_at = now(),
+  updated_at = now()
+WHERE id = sqlc.arg(flash_sale_id);
@@ -0,0 +1,49 @@
+-- name: ListFxRates :many
+SELECT * FROM fx_rates
+ORDER BY currency;
+
+-- name: GetFxRate :one
+SELECT * FROM fx_rates
+WHERE currency = sqlc.arg(currency);
+
+-- name: ListFxRatesFor :many
+SELECT * FROM fx_rates
+WHERE currency = ANY(sqlc.arg(currencies)::varchar[]);
+
+-- name: UpsertFxRate :one
+INSERT INTO fx_rates (
+  currency,
+  rate,
+  source,
+  as_of
+) VALUES (
+  sqlc.arg(currency), sqlc.arg(rate), sqlc.arg(source), sqlc.arg(as_of)
+)
+ON CONFLICT (currency) DO UPDATE
+SET
+  rate = EXCLUDED.rate,
+  source = EXCLUDED.source,
+  as_of = EXCLUDED.as_of,
+  updated_at = now()
+RETURNING *;
+
+-- name: SetOrderFxRate :one
+UPDATE orders
+SET
+  currency = sqlc.arg(currency),
+  fx_rate = sqlc.arg(fx_rate)
+WHERE id = sqlc.arg(order_id)
+RETURNING *;
+
+-- name: CreateCheckoutFxRate :exec
+INSERT INTO checkout_fx_rates (
+  reference,
+  currency,
+  rate
+) VALUES (
+  sqlc.arg(reference), sqlc.arg(currency), sqlc.arg(rate)
+);
+
+-- name: ListCheckoutFxRates :many
+SELECT * FROM checkout_fx_rates
+WHERE reference = sqlc.arg(reference);
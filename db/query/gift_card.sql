@@ -0,0 +1,60 @@
+-- name: CreateGiftCard :one
+INSERT INTO gift_cards (
+  store_id,
+  code,
+  currency,
+  initial_amount,
+  balance,
+  purchaser_id,
+  reference
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(code), sqlc.arg(currency), sqlc.arg(amount), sqlc.arg(amount),
+  sqlc.arg(purchaser_id), sqlc.arg(reference)
+) RETURNING *;
+
+-- name: IsGiftCardPurchase :one
+SELECT EXISTS (
+  SELECT 1 FROM gift_cards WHERE reference = sqlc.arg(reference)
+) AS is_gift_card_purchase;
+
+-- name: UpdateGiftCardPurchaseStatus :one
+-- Settles the gift card bought under reference, once its transaction
+-- completes or fails.
+UPDATE gift_cards
+SET
+  status = sqlc.arg(status),
+  updated_at = now()
+WHERE reference = sqlc.arg(reference) AND status = 'PENDING'
+RETURNING *;
+
+-- name: GetActiveGiftCard :one
+SELECT * FROM gift_cards
+WHERE code = sqlc.arg(code) AND status = 'ACTIVE';
+
+-- name: GetActiveGiftCardForUpdate :one
+SELECT * FROM gift_cards
+WHERE code = sqlc.arg(code) AND status = 'ACTIVE'
+FOR UPDATE;
+
+-- name: ListPurchasedGiftCards :many
+SELECT
+  count(*) OVER() AS total_count,
+  gc.*,
+  s.name AS store_name
+FROM gift_cards gc
+JOIN stores s ON s.id = gc.store_id
+WHERE gc.purchaser_id = sqlc.arg(purchaser_id)
+ORDER BY gc.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: AdjustGiftCardBalance :one
+-- Adds amount, or takes it off if negative, to an ACTIVE gift card's balance.
+UPDATE gift_cards
+SET
+  balance = balance + sqlc.arg(amount)::NUMERIC(18, 2),
+  updated_at = now()
+WHERE id = sqlc.arg(gift_card_id)
+  AND status = 'ACTIVE'
+  AND balance + sqlc.arg(amount)::NUMERIC(18, 2) BETWEEN 0 AND initial_amount
+RETURNING *;
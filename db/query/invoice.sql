@@ -0,0 +1,75 @@
+-- name: NextInvoiceNumber :one
+-- Takes a store's next invoice number, holding its sequence row until the
+-- transaction ends.
+INSERT INTO invoice_sequences (store_id, last_number)
+VALUES (sqlc.arg(store_id), 1)
+ON CONFLICT (store_id) DO UPDATE
+SET last_number = invoice_sequences.last_number + 1
+RETURNING last_number;
+
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+  store_id,
+  sequence_number,
+  invoice_number,
+  reference,
+  buyer_id,
+  currency,
+  subtotal,
+  discount,
+  delivery_fee,
+  tax,
+  total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetBuyerInvoice :one
+SELECT * FROM invoices
+WHERE id = sqlc.arg(invoice_id) AND buyer_id = sqlc.arg(buyer_id);
+
+-- name: GetStoreInvoice :one
+SELECT * FROM invoices
+WHERE id = sqlc.arg(invoice_id) AND store_id = sqlc.arg(store_id);
+
+-- name: ListBuyerInvoices :many
+SELECT
+  count(*) OVER() AS total_count,
+  inv.*,
+  s.name AS store_name
+FROM invoices inv
+JOIN stores s ON s.id = inv.store_id
+WHERE inv.buyer_id = sqlc.arg(buyer_id)
+ORDER BY inv.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: ListStoreInvoices :many
+SELECT
+  count(*) OVER() AS total_count,
+  inv.*
+FROM invoices inv
+WHERE inv.store_id = sqlc.arg(store_id)
+ORDER BY inv.sequence_number DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: ListUnsentInvoices :many
+SELECT * FROM invoices
+WHERE reference = sqlc.arg(reference)
+  AND emailed_at = '0001-01-01 00:00:00Z'
+ORDER BY store_id;
+
+-- name: SetInvoiceEmailed :exec
+UPDATE invoices
+SET emailed_at = now()
+WHERE id = sqlc.arg(invoice_id);
+
+-- name: ListStoreStaffEmails :many
+-- Emails of a store's staff holding any of access_levels.
+SELECT u.email
+FROM store_owners so
+JOIN users u ON u.id = so.user_id
+WHERE so.store_id = sqlc.arg(store_id)
+  AND so.access_levels && sqlc.arg(access_levels)::int[]
+ORDER BY so.user_id;
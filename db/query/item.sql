@@ -10,9 +10,12 @@ INSERT INTO items (
   discount_percentage,
   supply_quantity,
   extra,
-  status
+  status,
+  currency,
+  weight_grams,
+  sku
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 ) RETURNING *;
 
 -- name: GetItem :one
@@ -33,17 +36,38 @@ SET
   extra = COALESCE(sqlc.narg(extra), extra),
   is_frozen = COALESCE(sqlc.narg(is_frozen), is_frozen),
   status = COALESCE(sqlc.narg(status), status),
+  weight_grams = COALESCE(sqlc.narg(weight_grams), weight_grams),
+  sku = COALESCE(sqlc.narg(sku), sku),
+  currency = COALESCE(sqlc.narg(currency), currency),
   updated_at = COALESCE(sqlc.narg(updated_at), updated_at)
 WHERE
   id = sqlc.arg(item_id)
 RETURNING *;
 
--- name: DeductItemSupply :exec
+-- name: DeductItemSupply :one
 UPDATE items 
 SET 
   supply_quantity = supply_quantity - sqlc.arg(order_quantity)
 WHERE
-  id = sqlc.arg(item_id) AND supply_quantity >= sqlc.arg(order_quantity);
+  id = sqlc.arg(item_id) AND supply_quantity >= sqlc.arg(order_quantity)
+RETURNING *;
+
+-- name: SetItemReorderThreshold :one
+-- Sets the threshold an item runs low at; NULL leaves it to its store's.
+UPDATE items
+SET
+  reorder_threshold = sqlc.narg(reorder_threshold),
+  updated_at = now()
+WHERE id = sqlc.arg(item_id) AND store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: SetItemSupply :one
+UPDATE items
+SET
+  supply_quantity = sqlc.arg(supply_quantity),
+  updated_at = now()
+WHERE id = sqlc.arg(item_id) AND store_id = sqlc.arg(store_id)
+RETURNING *;
 
 -- name: DeleteItem :exec
 DELETE FROM items
@@ -52,4 +76,17 @@ WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(item_id);
 -- name: CheckItemStoreMatch :one
 SELECT supply_quantity from items
 WHERE id = sqlc.arg(item_id)
-  AND store_id = sqlc.arg(store_id);
\ No newline at end of file
+  AND store_id = sqlc.arg(store_id)
+FOR UPDATE;
+
+-- name: GetStoreItemBySKUForUpdate :one
+SELECT * FROM items
+WHERE store_id = sqlc.arg(store_id) AND sku = sqlc.arg(sku) AND sku <> ''
+FOR UPDATE;
+
+-- name: ListStoreItemsAfter :many
+-- Lists a page of a store's items in id order, after the item after_id.
+SELECT * FROM items
+WHERE store_id = sqlc.arg(store_id) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(rw_limit);
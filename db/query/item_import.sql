@@ -0,0 +1,83 @@
+-- name: CreateItemImport :one
+INSERT INTO item_imports (
+  store_id,
+  uploaded_by,
+  filename,
+  total_rows
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(uploaded_by), sqlc.arg(filename), sqlc.arg(total_rows)
+) RETURNING *;
+
+-- name: CreateItemImportFile :exec
+INSERT INTO item_import_files (
+  import_id,
+  content
+) VALUES (
+  sqlc.arg(import_id), sqlc.arg(content)
+);
+
+-- name: GetItemImportFile :one
+SELECT content FROM item_import_files
+WHERE import_id = sqlc.arg(import_id);
+
+-- name: DeleteItemImportFile :exec
+DELETE FROM item_import_files
+WHERE import_id = sqlc.arg(import_id);
+
+-- name: GetItemImport :one
+SELECT * FROM item_imports
+WHERE id = sqlc.arg(import_id) AND store_id = sqlc.arg(store_id);
+
+-- name: GetItemImportByID :one
+SELECT * FROM item_imports
+WHERE id = sqlc.arg(import_id);
+
+-- name: StartItemImport :one
+-- Marks an import PROCESSING, unless it's already COMPLETED or FAILED. A
+-- PROCESSING import is started again when its task is retried.
+UPDATE item_imports
+SET
+  status = 'PROCESSING',
+  started_at = now()
+WHERE id = sqlc.arg(import_id)
+  AND status IN ('PENDING', 'PROCESSING')
+RETURNING *;
+
+-- name: CompleteItemImport :one
+UPDATE item_imports
+SET
+  status = 'COMPLETED',
+  created_rows = sqlc.arg(created_rows),
+  updated_rows = sqlc.arg(updated_rows),
+  failed_rows = sqlc.arg(failed_rows),
+  completed_at = now()
+WHERE id = sqlc.arg(import_id)
+RETURNING *;
+
+-- name: FailItemImport :one
+UPDATE item_imports
+SET
+  status = 'FAILED',
+  error = sqlc.arg(error),
+  completed_at = now()
+WHERE id = sqlc.arg(import_id)
+RETURNING *;
+
+-- name: CreateItemImportError :exec
+INSERT INTO item_import_errors (
+  import_id,
+  line,
+  sku,
+  message
+) VALUES (
+  sqlc.arg(import_id), sqlc.arg(line), sqlc.arg(sku), sqlc.arg(message)
+);
+
+-- name: DeleteItemImportErrors :exec
+DELETE FROM item_import_errors
+WHERE import_id = sqlc.arg(import_id);
+
+-- name: ListItemImportErrors :many
+SELECT * FROM item_import_errors
+WHERE import_id = sqlc.arg(import_id)
+ORDER BY line, id;
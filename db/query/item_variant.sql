@@ -0,0 +1,129 @@
+-- name: CreateItemOption :one
+INSERT INTO item_options (
+  item_id,
+  name,
+  "values",
+  position
+) VALUES (
+  sqlc.arg(item_id), sqlc.arg(name), sqlc.arg(option_values)::text[], sqlc.arg(position)
+) RETURNING *;
+
+-- name: ListItemOptions :many
+SELECT * FROM item_options
+WHERE item_id = sqlc.arg(item_id)
+ORDER BY position, id;
+
+-- name: DeleteItemOptions :exec
+DELETE FROM item_options
+WHERE item_id = sqlc.arg(item_id);
+
+-- name: UpsertItemVariant :one
+-- Creates a variant of an item, or updates the item's variant with its SKU.
+-- Returns no row if another item of the store has the SKU.
+INSERT INTO item_variants (
+  item_id,
+  store_id,
+  sku,
+  options,
+  price,
+  supply_quantity,
+  image_urls
+) VALUES (
+  sqlc.arg(item_id),
+  sqlc.arg(store_id),
+  sqlc.arg(sku),
+  sqlc.arg(options),
+  sqlc.narg(price),
+  sqlc.arg(supply_quantity),
+  sqlc.arg(image_urls)::text[]
+)
+ON CONFLICT (store_id, sku) DO UPDATE
+SET
+  options = EXCLUDED.options,
+  price = EXCLUDED.price,
+  supply_quantity = EXCLUDED.supply_quantity,
+  image_urls = EXCLUDED.image_urls,
+  updated_at = now()
+WHERE item_variants.item_id = EXCLUDED.item_id
+RETURNING *;
+
+-- name: DeleteItemVariantsExcept :exec
+DELETE FROM item_variants
+WHERE item_id = sqlc.arg(item_id)
+  AND NOT (sku = ANY(sqlc.arg(skus)::text[]));
+
+-- name: ListItemVariants :many
+SELECT * FROM item_variants
+WHERE item_id = sqlc.arg(item_id)
+ORDER BY id;
+
+-- name: GetItemVariant :one
+SELECT * FROM item_variants
+WHERE id = sqlc.arg(variant_id) AND item_id = sqlc.arg(item_id);
+
+-- name: GetItemVariantForUpdate :one
+SELECT * FROM item_variants
+WHERE id = sqlc.arg(variant_id) AND item_id = sqlc.arg(item_id)
+FOR UPDATE;
+
+-- name: CountItemVariants :one
+SELECT count(*) FROM item_variants
+WHERE item_id = sqlc.arg(item_id);
+
+-- name: SumItemVariantsSupply :one
+-- Sets an item's supply to the sum of its variants'.
+UPDATE items
+SET
+  supply_quantity = (
+    SELECT COALESCE(sum(v.supply_quantity), 0)
+    FROM item_variants v
+    WHERE v.item_id = items.id
+  ),
+  updated_at = now()
+WHERE items.id = sqlc.arg(item_id)
+RETURNING *;
+
+-- name: GetReservedVariantStock :one
+SELECT COALESCE(sum(quantity), 0)::bigint AS reserved
+FROM stock_reservations
+WHERE variant_id = sqlc.arg(variant_id)
+  AND reference <> sqlc.arg(reference)
+  AND status = 'RESERVED'
+  AND expires_at > now();
+
+-- name: ListReservedVariantStock :many
+-- The stock of each variant of an item held for checkouts.
+SELECT
+  variant_id::bigint AS variant_id,
+  COALESCE(sum(quantity), 0)::bigint AS reserved
+FROM stock_reservations
+WHERE item_id = sqlc.arg(item_id)
+  AND variant_id IS NOT NULL
+  AND status = 'RESERVED'
+  AND expires_at > now()
+GROUP BY variant_id;
+
+-- name: RestockItemVariant :exec
+UPDATE item_variants
+SET
+  supply_quantity = supply_quantity + sqlc.arg(quantity)::bigint,
+  updated_at = now()
+WHERE id = sqlc.arg(variant_id);
+
+-- name: SetItemVariantSupply :one
+UPDATE item_variants
+SET
+  supply_quantity = sqlc.arg(supply_quantity),
+  updated_at = now()
+WHERE id = sqlc.arg(variant_id)
+  AND item_id = sqlc.arg(item_id)
+  AND store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: SetOrderVariant :one
+UPDATE orders
+SET
+  variant_id = sqlc.arg(variant_id),
+  variant_sku = sqlc.arg(variant_sku)
+WHERE id = sqlc.arg(order_id)
+RETURNING *;
@@ -0,0 +1,84 @@
+-- name: EnsureLedgerAccount :one
+SELECT ensure_ledger_account(
+  sqlc.arg(code)::varchar,
+  sqlc.arg(type)::varchar,
+  sqlc.narg(store_id)::bigint
+)::bigint AS id;
+
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+  kind,
+  reference,
+  description
+) VALUES (
+  sqlc.arg(kind), sqlc.arg(reference), sqlc.arg(description)
+)
+RETURNING *;
+
+-- name: CreateLedgerPosting :exec
+INSERT INTO ledger_postings (
+  journal_entry_id,
+  ledger_account_id,
+  amount
+) VALUES (
+  sqlc.arg(journal_entry_id), sqlc.arg(ledger_account_id), sqlc.arg(amount)
+);
+
+-- name: ListStoreLedgerBalances :many
+SELECT
+  la.code,
+  la.type,
+  COALESCE(SUM(lp.amount), 0)::NUMERIC(18, 2) AS total
+FROM ledger_accounts la
+LEFT JOIN ledger_postings lp ON lp.ledger_account_id = la.id
+WHERE la.store_id = sqlc.arg(store_id)::bigint
+GROUP BY la.id
+ORDER BY la.code;
+
+-- name: ListStoreLedgerEntries :many
+SELECT
+  count(*) OVER() AS total_count,
+  je.*,
+  (
+    SELECT jsonb_agg(jsonb_build_object(
+      'account', la.code,
+      'amount', lp.amount
+    ) ORDER BY lp.id)
+    FROM ledger_postings lp
+    JOIN ledger_accounts la ON la.id = lp.ledger_account_id
+    WHERE lp.journal_entry_id = je.id
+      AND la.store_id = sqlc.arg(store_id)::bigint
+  )::jsonb AS postings
+FROM journal_entries je
+WHERE EXISTS (
+  SELECT 1
+  FROM ledger_postings lp
+  JOIN ledger_accounts la ON la.id = lp.ledger_account_id
+  WHERE lp.journal_entry_id = je.id
+    AND la.store_id = sqlc.arg(store_id)::bigint
+)
+AND (sqlc.narg(kind)::varchar IS NULL OR je.kind = sqlc.narg(kind))
+ORDER BY je.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: GetTrialBalance :many
+SELECT
+  la.code,
+  la.type,
+  COALESCE(SUM(lp.amount), 0)::NUMERIC(18, 2) AS total
+FROM ledger_accounts la
+LEFT JOIN ledger_postings lp ON lp.ledger_account_id = la.id
+GROUP BY la.id
+ORDER BY la.code;
+
+-- name: ListUnbalancedJournalEntries :many
+SELECT
+  je.id,
+  je.kind,
+  je.reference,
+  SUM(lp.amount)::NUMERIC(18, 2) AS total
+FROM journal_entries je
+JOIN ledger_postings lp ON lp.journal_entry_id = je.id
+GROUP BY je.id
+HAVING SUM(lp.amount) <> 0;
@@ -0,0 +1,50 @@
+-- name: CreateNEARTransfer :one
+INSERT INTO near_transfers (
+  reference,
+  refund_id,
+  checkout_refund_id,
+  receiver_id,
+  amount
+) VALUES (
+  sqlc.arg(reference), sqlc.narg(refund_id), sqlc.narg(checkout_refund_id), sqlc.arg(receiver_id), sqlc.arg(amount)
+) RETURNING *;
+
+-- name: GetNEARTransfer :one
+SELECT * FROM near_transfers
+WHERE id = sqlc.arg(transfer_id);
+
+-- name: GetNEARTransferForUpdate :one
+SELECT * FROM near_transfers
+WHERE id = sqlc.arg(transfer_id)
+FOR UPDATE;
+
+-- name: SetNEARTransferSignedTx :one
+-- Saves the transaction a PENDING transfer is sent in, or clears it with a
+-- NULL signed_tx once it can no longer execute.
+UPDATE near_transfers
+SET
+  signed_tx = sqlc.narg(signed_tx),
+  tx_hash = sqlc.arg(tx_hash),
+  updated_at = now()
+WHERE id = sqlc.arg(transfer_id)
+  AND status = 'PENDING'
+RETURNING *;
+
+-- name: CompleteNEARTransfer :one
+UPDATE near_transfers
+SET
+  status = 'COMPLETED',
+  updated_at = now()
+WHERE id = sqlc.arg(transfer_id)
+  AND status = 'PENDING'
+RETURNING *;
+
+-- name: FailNEARTransfer :one
+UPDATE near_transfers
+SET
+  status = 'FAILED',
+  failure_reason = sqlc.arg(failure_reason),
+  updated_at = now()
+WHERE id = sqlc.arg(transfer_id)
+  AND status = 'PENDING'
+RETURNING *;
@@ -0,0 +1,23 @@
+-- name: GetNEARTx :one
+SELECT * FROM near_txs
+WHERE task_id = sqlc.arg(task_id);
+
+-- name: SaveNEARTx :one
+-- Saves the transaction a task sends, replacing one that can no longer execute.
+INSERT INTO near_txs (
+  task_id,
+  signed_tx,
+  tx_hash
+) VALUES (
+  sqlc.arg(task_id), sqlc.arg(signed_tx), sqlc.arg(tx_hash)
+)
+ON CONFLICT (task_id) DO UPDATE
+SET
+  signed_tx = EXCLUDED.signed_tx,
+  tx_hash = EXCLUDED.tx_hash,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteNEARTx :exec
+DELETE FROM near_txs
+WHERE task_id = sqlc.arg(task_id);
@@ -21,6 +21,7 @@ SELECT * FROM create_order(
   sqlc.arg(buyer_id),
   sqlc.arg(seller_id),
   sqlc.arg(store_id),
+  sqlc.arg(item_price),
   sqlc.arg(delivery_fee),
   sqlc.arg(payment_channel),
   sqlc.arg(payment_method)
@@ -40,6 +41,7 @@ SELECT
   o.payment_channel,
   o.payment_method,
   o.created_at,
+  o.fulfilment_group_id,
   i.name AS item_name,
   i.description AS item_description,
   i.price,
@@ -84,6 +86,8 @@ SELECT
   o.delivered_on,
   o.expected_delivery_date,
   o.item_id,
+  o.variant_id,
+  o.variant_sku,
   o.order_quantity,
   o.seller_id,
   o.store_id,
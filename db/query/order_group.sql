@@ -0,0 +1,192 @@
+-- name: CreateOrderGroup :one
+INSERT INTO order_groups (
+  buyer_id,
+  reference,
+  status,
+  shipping_address,
+  currency,
+  subtotal,
+  discount_total,
+  delivery_total,
+  tax_total,
+  grand_total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING *;
+
+-- name: GetOrderGroupByReferenceForUpdate :one
+SELECT * FROM order_groups
+WHERE reference = sqlc.arg(reference)
+FOR UPDATE;
+
+-- name: PlaceOrderGroup :one
+-- Marks an order group PLACED, totalling what its fulfilment groups cost.
+UPDATE order_groups og
+SET
+  status = 'PLACED',
+  subtotal = f.subtotal,
+  discount_total = f.discount,
+  delivery_total = f.delivery_fee,
+  tax_total = f.tax,
+  grand_total = f.total,
+  updated_at = now()
+FROM (
+  SELECT
+    COALESCE(sum(subtotal), 0)::NUMERIC(18, 2) AS subtotal,
+    COALESCE(sum(discount), 0)::NUMERIC(18, 2) AS discount,
+    COALESCE(sum(delivery_fee), 0)::NUMERIC(18, 2) AS delivery_fee,
+    COALESCE(sum(tax), 0)::NUMERIC(18, 2) AS tax,
+    COALESCE(sum(total), 0)::NUMERIC(18, 2) AS total
+  FROM fulfilment_groups
+  WHERE order_group_id = sqlc.arg(order_group_id)
+) f
+WHERE og.id = sqlc.arg(order_group_id)
+RETURNING og.*;
+
+-- name: FailOrderGroup :exec
+UPDATE order_groups
+SET
+  status = 'FAILED',
+  updated_at = now()
+WHERE reference = sqlc.arg(reference) AND status = 'PENDING';
+
+-- name: GetOrderGroupByReference :one
+SELECT * FROM order_groups
+WHERE reference = sqlc.arg(reference);
+
+-- name: GetBuyerOrderGroup :one
+SELECT * FROM order_groups
+WHERE order_number = sqlc.arg(order_number) AND buyer_id = sqlc.arg(buyer_id);
+
+-- name: ListBuyerOrderGroups :many
+SELECT
+  count(*) OVER() AS total_count,
+  og.*
+FROM order_groups og
+WHERE og.buyer_id = sqlc.arg(buyer_id) AND og.status <> 'FAILED'
+ORDER BY og.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: CreateFulfilmentGroups :many
+-- Groups orders by store under an order group, totalling what each store ships.
+INSERT INTO fulfilment_groups (
+  order_group_id,
+  store_id,
+  seller_id,
+  subtotal,
+  discount,
+  delivery_fee,
+  tax,
+  total,
+  expected_delivery_date
+)
+SELECT
+  sqlc.arg(order_group_id)::bigint,
+  o.store_id,
+  min(o.seller_id),
+  sum(o.item_price * o.order_quantity),
+  sum(o.discount_amount),
+  sum(o.delivery_fee),
+  sum(o.tax_amount),
+  sum(o.item_price * o.order_quantity - o.discount_amount + o.delivery_fee
+    + CASE WHEN o.tax_inclusive THEN 0 ELSE o.tax_amount END),
+  max(o.expected_delivery_date)
+FROM orders o
+WHERE o.id = ANY(sqlc.arg(order_ids)::bigint[])
+GROUP BY o.store_id
+RETURNING *;
+
+-- name: AssignFulfilmentGroups :execrows
+UPDATE orders o
+SET fulfilment_group_id = fg.id
+FROM fulfilment_groups fg
+WHERE fg.order_group_id = sqlc.arg(order_group_id)
+  AND fg.store_id = o.store_id
+  AND o.id = ANY(sqlc.arg(order_ids)::bigint[]);
+
+-- name: GetSellerFulfilmentGroup :one
+SELECT
+  fg.*,
+  og.order_number,
+  og.shipping_address,
+  og.currency,
+  u.first_name AS buyer_first_name,
+  u.last_name AS buyer_last_name,
+  u.email AS buyer_email
+FROM fulfilment_groups fg
+JOIN order_groups og ON fg.order_group_id = og.id
+JOIN users u ON og.buyer_id = u.id
+WHERE fg.id = sqlc.arg(fulfilment_group_id)
+  AND fg.store_id = sqlc.arg(store_id)
+  AND fg.seller_id = sqlc.arg(seller_id);
+
+-- name: ListOrderGroupFulfilmentGroups :many
+SELECT * FROM fulfilment_groups
+WHERE order_group_id = sqlc.arg(order_group_id)
+ORDER BY id;
+
+-- name: ListFulfilmentGroupLines :many
+SELECT
+  o.id AS order_id,
+  o.fulfilment_group_id,
+  o.item_id,
+  i.name AS item_name,
+  i.cover_img_url AS item_cover_img_url,
+  o.order_quantity,
+  o.item_price,
+  o.discount_amount,
+  o.delivery_fee,
+  o.tax_rate,
+  o.tax_inclusive,
+  o.tax_amount,
+  o.delivery_status,
+  o.is_reviewed
+FROM orders o
+JOIN items i ON o.item_id = i.id
+WHERE o.fulfilment_group_id = ANY(sqlc.arg(fulfilment_group_ids)::bigint[])
+ORDER BY o.id;
+
+-- name: ListFulfilmentGroupOrders :many
+SELECT * FROM orders
+WHERE fulfilment_group_id = sqlc.arg(fulfilment_group_id)::bigint
+ORDER BY id
+FOR UPDATE;
+
+-- name: UpdateFulfilmentGroupShipping :one
+UPDATE fulfilment_groups
+SET
+  carrier = COALESCE(sqlc.narg(carrier), carrier),
+  tracking_number = COALESCE(sqlc.narg(tracking_number), tracking_number),
+  expected_delivery_date = COALESCE(sqlc.narg(expected_delivery_date), expected_delivery_date),
+  updated_at = now()
+WHERE id = sqlc.arg(fulfilment_group_id)
+RETURNING *;
+
+-- name: SyncFulfilmentGroup :one
+-- Sets a fulfilment group's delivery_status to that of its least advanced
+-- line, stamping when it first shipped and when its last line was delivered.
+UPDATE fulfilment_groups fg
+SET
+  delivery_status = l.delivery_status,
+  shipped_at = CASE
+    WHEN fg.shipped_at = '0001-01-01 00:00:00Z' AND l.delivery_status IN ('SHIPPED', 'DELIVERED') THEN now()
+    ELSE fg.shipped_at
+  END,
+  delivered_on = CASE
+    WHEN l.delivery_status = 'DELIVERED' THEN l.delivered_on
+    ELSE fg.delivered_on
+  END,
+  updated_at = now()
+FROM (
+  SELECT
+    (array_agg(o.delivery_status ORDER BY array_position(
+      ARRAY['PENDING', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'RETURNED', 'CANCELLED']::varchar[],
+      o.delivery_status
+    )))[1]::varchar AS delivery_status,
+    max(o.delivered_on)::timestamptz AS delivered_on
+  FROM orders o
+  WHERE o.fulfilment_group_id = sqlc.arg(fulfilment_group_id)
+) l
+WHERE fg.id = sqlc.arg(fulfilment_group_id)
+RETURNING fg.*;
@@ -0,0 +1,95 @@
+-- name: GetStoreFiatAccount :one
+SELECT * FROM fiat_accounts
+WHERE store_id = sqlc.arg(store_id);
+
+-- name: GetStoreCryptoAccount :one
+SELECT * FROM crypto_accounts
+WHERE store_id = sqlc.arg(store_id);
+
+-- name: DebitFiatAccount :one
+UPDATE fiat_accounts
+SET balance = balance - sqlc.arg(amount)::NUMERIC(18, 2)
+WHERE store_id = sqlc.arg(store_id)
+  AND balance >= sqlc.arg(amount)::NUMERIC(18, 2)
+RETURNING *;
+
+-- name: DebitCryptoAccount :one
+UPDATE crypto_accounts
+SET balance = balance - sqlc.arg(amount)::NUMERIC(18, 2)
+WHERE store_id = sqlc.arg(store_id)
+  AND balance >= sqlc.arg(amount)::NUMERIC(18, 2)
+RETURNING *;
+
+-- name: CreditFiatAccount :one
+UPDATE fiat_accounts
+SET balance = balance + sqlc.arg(amount)::NUMERIC(18, 2)
+WHERE store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: CreditCryptoAccount :one
+UPDATE crypto_accounts
+SET balance = balance + sqlc.arg(amount)::NUMERIC(18, 2)
+WHERE store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: SumPendingWithdrawals :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(18, 2) AS total
+FROM withdrawal_requests
+WHERE store_id = sqlc.arg(store_id)
+  AND account_type = sqlc.arg(account_type)
+  AND status = 'PENDING';
+
+-- name: CreateWithdrawalRequest :one
+INSERT INTO withdrawal_requests (
+  store_id,
+  account_type,
+  amount,
+  destination,
+  requested_by
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(account_type), sqlc.arg(amount), sqlc.arg(destination), sqlc.arg(requested_by)
+)
+RETURNING *;
+
+-- name: GetWithdrawalRequest :one
+SELECT * FROM withdrawal_requests
+WHERE id = sqlc.arg(withdrawal_id)
+  AND store_id = sqlc.arg(store_id);
+
+-- name: GetWithdrawalRequestForUpdate :one
+SELECT * FROM withdrawal_requests
+WHERE id = sqlc.arg(withdrawal_id)
+  AND store_id = sqlc.arg(store_id)
+FOR UPDATE;
+
+-- name: ListWithdrawalRequests :many
+SELECT
+  count(*) OVER() AS total_count,
+  wr.*
+FROM withdrawal_requests wr
+WHERE wr.store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(status)::varchar IS NULL OR wr.status = sqlc.narg(status))
+ORDER BY wr.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: UpdateWithdrawalRequestStatus :one
+UPDATE withdrawal_requests
+SET
+  status = sqlc.arg(status),
+  note = sqlc.arg(note),
+  updated_at = now()
+WHERE id = sqlc.arg(withdrawal_id)
+RETURNING *;
+
+-- name: ChargeBackFiatAccount :one
+UPDATE fiat_accounts
+SET balance = balance - sqlc.arg(amount)::NUMERIC(18, 2)
+WHERE store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: ChargeBackCryptoAccount :one
+UPDATE crypto_accounts
+SET balance = balance - sqlc.arg(amount)::NUMERIC(18, 2)
+WHERE store_id = sqlc.arg(store_id)
+RETURNING *;
@@ -0,0 +1,100 @@
+-- name: CreateRefund :one
+INSERT INTO refunds (
+  order_id,
+  transaction_id,
+  store_id,
+  amount,
+  commission_amount,
+  restock_quantity,
+  reason,
+  requested_by,
+  destination
+) VALUES (
+  sqlc.arg(order_id), sqlc.arg(transaction_id), sqlc.arg(store_id), sqlc.arg(amount),
+  sqlc.arg(commission_amount), sqlc.arg(restock_quantity), sqlc.arg(reason), sqlc.arg(requested_by),
+  sqlc.arg(destination)
+)
+RETURNING *;
+
+-- name: GetRefund :one
+SELECT
+  r.*,
+  t.provider_tx_ref_id,
+  t.payment_provider
+FROM refunds r
+JOIN transactions t ON t.id = r.transaction_id
+WHERE r.id = sqlc.arg(refund_id);
+
+-- name: GetRefundForUpdate :one
+SELECT * FROM refunds
+WHERE id = sqlc.arg(refund_id)
+FOR UPDATE;
+
+-- name: ListOrderRefunds :many
+SELECT * FROM refunds
+WHERE order_id = sqlc.arg(order_id)
+  AND store_id = sqlc.arg(store_id)
+ORDER BY id DESC;
+
+-- name: GetOrderRefundTotals :one
+SELECT
+  COALESCE(SUM(amount), 0)::NUMERIC(18, 2) AS amount,
+  COALESCE(SUM(restock_quantity), 0)::int AS restock_quantity
+FROM refunds
+WHERE order_id = sqlc.arg(order_id)
+  AND status != 'FAILED';
+
+-- name: GetTransactionRefundedAmount :one
+-- What's been refunded of a transaction to destination, not counting failed refunds.
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(18, 2) AS amount
+FROM refunds
+WHERE transaction_id = sqlc.arg(transaction_id)
+  AND destination = sqlc.arg(destination)
+  AND status != 'FAILED';
+
+-- name: CompleteRefund :one
+UPDATE refunds
+SET
+  status = 'COMPLETED',
+  provider_refund_id = sqlc.arg(provider_refund_id),
+  updated_at = now()
+WHERE id = sqlc.arg(refund_id)
+RETURNING *;
+
+-- name: FailRefund :one
+UPDATE refunds
+SET
+  status = 'FAILED',
+  failure_reason = sqlc.arg(failure_reason),
+  updated_at = now()
+WHERE id = sqlc.arg(refund_id)
+  AND status = 'PENDING'
+RETURNING *;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = sqlc.arg(order_id)
+  AND store_id = sqlc.arg(store_id)
+FOR UPDATE;
+
+-- name: GetOrderTransaction :one
+SELECT * FROM transactions
+WHERE order_ids @> ARRAY[sqlc.arg(order_id)::bigint]
+  AND status = 'COMPLETED';
+
+-- name: ReducePendingFunds :one
+UPDATE pending_transaction_funds
+SET
+  amount = amount - sqlc.arg(amount)::NUMERIC(18, 2),
+  updated_at = now()
+WHERE store_id = sqlc.arg(store_id)
+  AND account_type = sqlc.arg(account_type)
+  AND amount >= sqlc.arg(amount)::NUMERIC(18, 2)
+RETURNING *;
+
+-- name: RestockItem :exec
+UPDATE items
+SET
+  supply_quantity = supply_quantity + sqlc.arg(quantity)::bigint,
+  updated_at = now()
+WHERE id = sqlc.arg(item_id);
@@ -16,6 +16,7 @@ SELECT * FROM create_review(
   sqlc.arg(store_id),
   sqlc.arg(user_id),
   sqlc.arg(item_id),
+  sqlc.narg(variant_id)::bigint,
   sqlc.arg(rating),
   sqlc.arg(review_type),
   sqlc.arg(comment),
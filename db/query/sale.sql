@@ -31,7 +31,17 @@ SELECT
   u.account_id AS customer_account_id,
   s.order_id,
   o.created_at AS order_date,
-  o.delivered_on AS delivery_date
+  o.delivered_on AS delivery_date,
+  o.item_price AS order_unit_price,
+  o.order_quantity,
+  o.delivery_fee,
+  o.discount_amount,
+  o.tax_rate,
+  o.tax_inclusive,
+  o.tax_amount,
+  o.commission_percentage,
+  o.commission_fixed,
+  o.commission_amount
 FROM 
   sales s
 JOIN
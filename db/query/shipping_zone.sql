@@ -0,0 +1,78 @@
+-- name: CreateShippingZone :one
+INSERT INTO shipping_zones (
+  store_id,
+  name,
+  country,
+  state,
+  city,
+  rate_basis,
+  free_shipping_threshold,
+  min_delivery_days,
+  max_delivery_days
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(name), upper(sqlc.arg(country)::varchar), sqlc.arg(state), sqlc.arg(city),
+  sqlc.arg(rate_basis), sqlc.arg(free_shipping_threshold), sqlc.arg(min_delivery_days), sqlc.arg(max_delivery_days)
+)
+RETURNING *;
+
+-- name: GetShippingZone :one
+SELECT * FROM shipping_zones
+WHERE id = sqlc.arg(shipping_zone_id)
+  AND store_id = sqlc.arg(store_id);
+
+-- name: UpdateShippingZone :one
+UPDATE shipping_zones
+SET
+  name = sqlc.arg(name),
+  country = upper(sqlc.arg(country)::varchar),
+  state = sqlc.arg(state),
+  city = sqlc.arg(city),
+  rate_basis = sqlc.arg(rate_basis),
+  free_shipping_threshold = sqlc.arg(free_shipping_threshold),
+  min_delivery_days = sqlc.arg(min_delivery_days),
+  max_delivery_days = sqlc.arg(max_delivery_days),
+  updated_at = now()
+WHERE id = sqlc.arg(shipping_zone_id)
+  AND store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: DeleteShippingZone :execrows
+DELETE FROM shipping_zones
+WHERE id = sqlc.arg(shipping_zone_id)
+  AND store_id = sqlc.arg(store_id);
+
+-- name: ListShippingZones :many
+SELECT * FROM shipping_zones
+WHERE store_id = sqlc.arg(store_id)
+ORDER BY country, state, city, id;
+
+-- name: ListStoreShippingZones :many
+-- The zones of every store in store_ids in country.
+SELECT * FROM shipping_zones
+WHERE store_id = ANY(sqlc.arg(store_ids)::bigint[])
+  AND country = upper(sqlc.arg(country)::varchar);
+
+-- name: CreateShippingRate :one
+INSERT INTO shipping_rates (
+  shipping_zone_id,
+  from_quantity,
+  fee
+) VALUES (
+  sqlc.arg(shipping_zone_id), sqlc.arg(from_quantity), sqlc.arg(fee)
+)
+RETURNING *;
+
+-- name: DeleteShippingRates :exec
+DELETE FROM shipping_rates
+WHERE shipping_zone_id = sqlc.arg(shipping_zone_id);
+
+-- name: ListShippingRates :many
+SELECT * FROM shipping_rates
+WHERE shipping_zone_id = ANY(sqlc.arg(shipping_zone_ids)::bigint[])
+ORDER BY shipping_zone_id, from_quantity;
+
+-- name: SetOrderExpectedDeliveryDate :one
+UPDATE orders
+SET expected_delivery_date = sqlc.arg(expected_delivery_date)
+WHERE id = sqlc.arg(order_id)
+RETURNING *;
@@ -0,0 +1,45 @@
+-- name: ListUnsentStockAlerts :many
+SELECT
+  sa.*,
+  s.name AS store_name,
+  i.name AS item_name,
+  i.sku AS item_sku
+FROM stock_alerts sa
+JOIN stores s ON s.id = sa.store_id
+JOIN items i ON i.id = sa.item_id
+WHERE sa.sent_at IS NULL
+ORDER BY sa.store_id, sa.id
+LIMIT sqlc.arg(rw_limit);
+
+-- name: SetStockAlertsSent :exec
+UPDATE stock_alerts
+SET sent_at = now()
+WHERE id = ANY(sqlc.arg(alert_ids)::bigint[]);
+
+-- name: ListItemsAtRisk :many
+-- Items at or below their threshold, or that sold more in the last week than
+-- they have left, of a store or of every store.
+SELECT
+  i.store_id,
+  s.name AS store_name,
+  i.id AS item_id,
+  i.name AS item_name,
+  i.sku AS item_sku,
+  i.supply_quantity,
+  COALESCE(i.reorder_threshold, s.low_stock_threshold)::int AS threshold,
+  COALESCE(sold.quantity, 0)::bigint AS sold_last_week
+FROM items i
+JOIN stores s ON s.id = i.store_id
+LEFT JOIN LATERAL (
+  SELECT -sum(sm.quantity_change) AS quantity
+  FROM stock_movements sm
+  WHERE sm.item_id = i.id
+    AND sm.reason = 'SALE'
+    AND sm.created_at > now() - interval '7 days'
+) sold ON true
+WHERE (sqlc.narg(store_id)::bigint IS NULL OR i.store_id = sqlc.narg(store_id))
+  AND (
+    i.supply_quantity <= COALESCE(i.reorder_threshold, s.low_stock_threshold)
+    OR i.supply_quantity < COALESCE(sold.quantity, 0)
+  )
+ORDER BY i.store_id, i.supply_quantity, i.id;
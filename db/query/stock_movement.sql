@@ -0,0 +1,29 @@
+-- name: SetStockMovementContext :exec
+-- Sets the reason, user, reference and note of the stock movements recorded
+-- for the rest of the transaction. An empty reason leaves the trigger to
+-- tell INITIAL from ADJUSTMENT, an empty user_id records no user.
+SELECT
+  set_config('store_hub.stock_reason', sqlc.arg(reason)::text, true),
+  set_config('store_hub.stock_user_id', sqlc.arg(user_id)::text, true),
+  set_config('store_hub.stock_reference', sqlc.arg(reference)::text, true),
+  set_config('store_hub.stock_note', sqlc.arg(note)::text, true);
+
+-- name: ListStockMovements :many
+SELECT
+  count(*) OVER() AS total_count,
+  sm.*
+FROM stock_movements sm
+WHERE sm.store_id = sqlc.arg(store_id)
+  AND (sqlc.narg(item_id)::bigint IS NULL OR sm.item_id = sqlc.narg(item_id))
+  AND (sqlc.narg(variant_id)::bigint IS NULL OR sm.variant_id = sqlc.narg(variant_id))
+  AND (sqlc.narg(reason)::varchar IS NULL OR sm.reason = sqlc.narg(reason))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR sm.created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR sm.created_at < sqlc.narg(created_before))
+ORDER BY sm.created_at DESC, sm.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: ListStockMovementsByReference :many
+SELECT * FROM stock_movements
+WHERE store_id = sqlc.arg(store_id) AND reference = sqlc.arg(reference)
+ORDER BY id;
@@ -0,0 +1,35 @@
+-- name: CreateStockReservation :one
+INSERT INTO stock_reservations (
+  item_id,
+  variant_id,
+  user_id,
+  reference,
+  quantity,
+  expires_at
+) VALUES (
+  sqlc.arg(item_id), sqlc.narg(variant_id), sqlc.arg(user_id), sqlc.arg(reference), sqlc.arg(quantity), sqlc.arg(expires_at)
+) RETURNING *;
+
+-- name: GetReservedStock :one
+SELECT COALESCE(sum(quantity), 0)::bigint AS reserved
+FROM stock_reservations
+WHERE item_id = sqlc.arg(item_id)
+  AND reference <> sqlc.arg(reference)
+  AND status = 'RESERVED'
+  AND expires_at > now();
+
+-- name: UpdateStockReservationsStatus :execrows
+UPDATE stock_reservations
+SET
+  status = sqlc.arg(to_status),
+  updated_at = now()
+WHERE reference = sqlc.arg(reference)
+  AND status = sqlc.arg(from_status);
+
+-- name: ExpireStockReservations :execrows
+UPDATE stock_reservations
+SET
+  status = 'EXPIRED',
+  updated_at = now()
+WHERE reference = sqlc.arg(reference)
+  AND status = 'RESERVED';
@@ -38,7 +38,8 @@ SET
   profile_image_url = COALESCE(sqlc.narg(profile_image_url), profile_image_url),
   is_verified = COALESCE(sqlc.narg(is_verified), is_verified),
   category = COALESCE(sqlc.narg(category), category),
-  is_frozen = COALESCE(sqlc.narg(is_frozen), is_frozen)
+  is_frozen = COALESCE(sqlc.narg(is_frozen), is_frozen),
+  low_stock_threshold = COALESCE(sqlc.narg(low_stock_threshold), low_stock_threshold)
 WHERE 
   id = sqlc.arg(store_id)
 RETURNING *;
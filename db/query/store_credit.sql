@@ -0,0 +1,66 @@
+-- name: GetStoreCredit :one
+SELECT COALESCE((
+  SELECT balance FROM store_credits WHERE user_id = sqlc.arg(user_id)
+), 0)::NUMERIC(18, 2) AS balance;
+
+-- name: GetStoreCreditForUpdate :one
+SELECT balance FROM store_credits
+WHERE user_id = sqlc.arg(user_id)
+FOR UPDATE;
+
+-- name: AdjustStoreCredit :one
+-- Adds amount, or takes it off if negative, to a user's store credit.
+INSERT INTO store_credits (user_id, balance)
+VALUES (sqlc.arg(user_id), sqlc.arg(amount)::NUMERIC(18, 2))
+ON CONFLICT (user_id) DO UPDATE
+SET
+  balance = store_credits.balance + EXCLUDED.balance,
+  updated_at = now()
+RETURNING balance;
+
+-- name: CreateStoreCreditEntry :one
+INSERT INTO store_credit_entries (
+  user_id,
+  kind,
+  amount,
+  balance,
+  reference,
+  description
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListStoreCreditEntries :many
+SELECT
+  count(*) OVER() AS total_count,
+  sce.*
+FROM store_credit_entries sce
+WHERE sce.user_id = sqlc.arg(user_id)
+ORDER BY sce.id DESC
+LIMIT sqlc.arg(rw_limit)
+OFFSET sqlc.arg(rw_offset);
+
+-- name: CreateCheckoutCredit :one
+INSERT INTO checkout_credits (
+  reference,
+  user_id,
+  gift_card_id,
+  amount
+) VALUES (
+  sqlc.arg(reference), sqlc.arg(user_id), sqlc.narg(gift_card_id), sqlc.arg(amount)
+) RETURNING *;
+
+-- name: ListCheckoutCredits :many
+SELECT * FROM checkout_credits
+WHERE reference = sqlc.arg(reference)
+  AND status = sqlc.arg(status)
+ORDER BY id;
+
+-- name: UpdateCheckoutCreditsStatus :many
+UPDATE checkout_credits
+SET
+  status = sqlc.arg(to_status),
+  updated_at = now()
+WHERE reference = sqlc.arg(reference)
+  AND status = sqlc.arg(from_status)
+RETURNING *;
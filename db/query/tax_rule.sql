@@ -0,0 +1,55 @@
+-- name: CreateTaxRule :one
+INSERT INTO tax_rules (
+  store_id,
+  name,
+  country,
+  state,
+  category,
+  rate,
+  inclusive
+) VALUES (
+  sqlc.arg(store_id), sqlc.arg(name), upper(sqlc.arg(country)::varchar), sqlc.arg(state),
+  sqlc.arg(category), sqlc.arg(rate), sqlc.arg(inclusive)
+)
+RETURNING *;
+
+-- name: GetTaxRule :one
+SELECT * FROM tax_rules
+WHERE id = sqlc.arg(tax_rule_id)
+  AND store_id = sqlc.arg(store_id);
+
+-- name: UpdateTaxRule :one
+UPDATE tax_rules
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  rate = COALESCE(sqlc.narg(rate), rate),
+  inclusive = COALESCE(sqlc.narg(inclusive), inclusive),
+  updated_at = now()
+WHERE id = sqlc.arg(tax_rule_id)
+  AND store_id = sqlc.arg(store_id)
+RETURNING *;
+
+-- name: DeleteTaxRule :execrows
+DELETE FROM tax_rules
+WHERE id = sqlc.arg(tax_rule_id)
+  AND store_id = sqlc.arg(store_id);
+
+-- name: ListTaxRules :many
+SELECT * FROM tax_rules
+WHERE store_id = sqlc.arg(store_id)
+ORDER BY country, state, category, id;
+
+-- name: ListStoreTaxRules :many
+-- The rules of every store in store_ids taxing sales shipped to country.
+SELECT * FROM tax_rules
+WHERE store_id = ANY(sqlc.arg(store_ids)::bigint[])
+  AND country = upper(sqlc.arg(country)::varchar);
+
+-- name: SetOrderTax :one
+UPDATE orders
+SET
+  tax_rate = sqlc.arg(tax_rate),
+  tax_inclusive = sqlc.arg(tax_inclusive),
+  tax_amount = sqlc.arg(tax_amount)
+WHERE id = sqlc.arg(order_id)
+RETURNING *;
@@ -4,7 +4,8 @@ SELECT * FROM initialize_transaction(
   sqlc.arg(amount)::NUMERIC(18, 2),
   sqlc.arg(payment_provider)::varchar,
   sqlc.arg(provider_tx_ref_id)::varchar,
-  sqlc.arg(provider_tx_access_code)::varchar
+  sqlc.arg(provider_tx_access_code)::varchar,
+  sqlc.narg(near_amount)::NUMERIC(40, 0)
 );
 
 -- name: ProcessTransaction :one
@@ -15,8 +16,8 @@ SELECT * FROM process_transaction_completion(
   sqlc.arg(cart_items)::jsonb
 );
 
--- name: ReleaseFunds :exec
-SELECT release_pending_funds(sqlc.arg(order_id)::bigint);
+-- name: ReleaseFunds :one
+SELECT release_pending_funds(sqlc.arg(order_id)::bigint)::NUMERIC(18, 2) AS released;
 
 -- name: GetTransactionByRefID :one
 SELECT * FROM transactions 
@@ -56,3 +57,22 @@ JOIN items i ON i.id = o.item_id
 JOIN stores s ON s.id = o.store_id
 JOIN users u ON u.id = o.buyer_id
 WHERE t.provider_tx_ref_id = sqlc.arg(provider_tx_ref_id);
+
+-- name: GetTransactionByRefIDForUpdate :one
+SELECT * FROM transactions 
+WHERE provider_tx_ref_id = sqlc.arg(provider_tx_ref_id)
+FOR UPDATE;
+
+-- name: SetTransactionProviderTxHash :one
+UPDATE transactions
+SET provider_tx_hash = sqlc.arg(provider_tx_hash)
+WHERE provider_tx_ref_id = sqlc.arg(provider_tx_ref_id)
+  AND provider_tx_hash IS NULL
+RETURNING *;
+
+-- name: ListStuckTransactions :many
+SELECT * FROM transactions
+WHERE status = 'PROCESSING'
+  AND created_at < sqlc.arg(created_before)
+ORDER BY id
+LIMIT sqlc.arg(rw_limit);
@@ -7,8 +7,20 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 )
 
+const clearCart = `-- name: ClearCart :exec
+DELETE FROM cart_items
+WHERE cart_id = $1
+`
+
+func (q *Queries) ClearCart(ctx context.Context, cartID int64) error {
+	_, err := q.db.ExecContext(ctx, clearCart, cartID)
+	return err
+}
+
 const createCartForUser = `-- name: CreateCartForUser :exec
 INSERT INTO carts (
   user_id
@@ -22,23 +34,84 @@ func (q *Queries) CreateCartForUser(ctx context.Context, userID int64) error {
 	return err
 }
 
+const createCheckoutLine = `-- name: CreateCheckoutLine :exec
+INSERT INTO checkout_lines (
+  reference,
+  item_id,
+  variant_id,
+  variant_sku,
+  store_id,
+  item_name,
+  price,
+  discount_percentage,
+  item_category,
+  item_currency,
+  item_weight_grams,
+  quantity
+) VALUES (
+  $1, $2, $3, $4,
+  $5, $6, $7, $8,
+  $9, $10, $11, $12
+)
+`
+
+type CreateCheckoutLineParams struct {
+	Reference          string        `json:"reference"`
+	ItemID             int64         `json:"item_id"`
+	VariantID          sql.NullInt64 `json:"variant_id"`
+	VariantSku         string        `json:"variant_sku"`
+	StoreID            int64         `json:"store_id"`
+	ItemName           string        `json:"item_name"`
+	Price              string        `json:"price"`
+	DiscountPercentage string        `json:"discount_percentage"`
+	ItemCategory       string        `json:"item_category"`
+	ItemCurrency       string        `json:"item_currency"`
+	ItemWeightGrams    int64         `json:"item_weight_grams"`
+	Quantity           int32         `json:"quantity"`
+}
+
+func (q *Queries) CreateCheckoutLine(ctx context.Context, arg CreateCheckoutLineParams) error {
+	_, err := q.db.ExecContext(ctx, createCheckoutLine,
+		arg.Reference,
+		arg.ItemID,
+		arg.VariantID,
+		arg.VariantSku,
+		arg.StoreID,
+		arg.ItemName,
+		arg.Price,
+		arg.DiscountPercentage,
+		arg.ItemCategory,
+		arg.ItemCurrency,
+		arg.ItemWeightGrams,
+		arg.Quantity,
+	)
+	return err
+}
+
 const decreaseCartItemQuantity = `-- name: DecreaseCartItemQuantity :one
 UPDATE cart_items 
 SET 
   quantity = GREATEST(1, cart_items.quantity - $1)
 WHERE 
   cart_id = $2 AND item_id = $3
-RETURNING id, cart_id, item_id, store_id, quantity, added_at, updated_at
+  AND variant_id IS NOT DISTINCT FROM $4::bigint
+RETURNING id, cart_id, item_id, store_id, quantity, added_at, updated_at, variant_id
 `
 
 type DecreaseCartItemQuantityParams struct {
-	DecreaseAmount int32 `json:"decrease_amount"`
-	CartID         int64 `json:"cart_id"`
-	ItemID         int64 `json:"item_id"`
+	DecreaseAmount int32         `json:"decrease_amount"`
+	CartID         int64         `json:"cart_id"`
+	ItemID         int64         `json:"item_id"`
+	VariantID      sql.NullInt64 `json:"variant_id"`
 }
 
 func (q *Queries) DecreaseCartItemQuantity(ctx context.Context, arg DecreaseCartItemQuantityParams) (CartItem, error) {
-	row := q.db.QueryRowContext(ctx, decreaseCartItemQuantity, arg.DecreaseAmount, arg.CartID, arg.ItemID)
+	row := q.db.QueryRowContext(ctx, decreaseCartItemQuantity,
+		arg.DecreaseAmount,
+		arg.CartID,
+		arg.ItemID,
+		arg.VariantID,
+	)
 	var i CartItem
 	err := row.Scan(
 		&i.ID,
@@ -48,6 +121,7 @@ func (q *Queries) DecreaseCartItemQuantity(ctx context.Context, arg DecreaseCart
 		&i.Quantity,
 		&i.AddedAt,
 		&i.UpdatedAt,
+		&i.VariantID,
 	)
 	return i, err
 }
@@ -56,9 +130,17 @@ const getCartByUserID = `-- name: GetCartByUserID :many
 SELECT 
   c.id AS cart_id,
   ci.item_id,
+  ci.variant_id,
+  COALESCE(v.sku, '')::varchar AS variant_sku,
+  COALESCE(v.options, '{}')::jsonb AS variant_options,
+  ci.store_id,
   i.name AS item_name,
   i.description AS item_description,
-  i.price,
+  COALESCE(v.price, i.price)::NUMERIC(10, 2) AS price,
+  i.discount_percentage,
+  i.category AS item_category,
+  i.currency AS item_currency,
+  i.weight_grams AS item_weight_grams,
   ci.quantity,
   i.cover_img_url AS item_image
 FROM 
@@ -67,18 +149,30 @@ JOIN
   cart_items ci ON c.id = ci.cart_id
 JOIN 
   items i ON ci.item_id = i.id
+LEFT JOIN
+  item_variants v ON ci.variant_id = v.id
 WHERE 
   c.user_id = $1
+ORDER BY
+  ci.id
 `
 
 type GetCartByUserIDRow struct {
-	CartID          int64  `json:"cart_id"`
-	ItemID          int64  `json:"item_id"`
-	ItemName        string `json:"item_name"`
-	ItemDescription string `json:"item_description"`
-	Price           string `json:"price"`
-	Quantity        int32  `json:"quantity"`
-	ItemImage       string `json:"item_image"`
+	CartID             int64           `json:"cart_id"`
+	ItemID             int64           `json:"item_id"`
+	VariantID          sql.NullInt64   `json:"variant_id"`
+	VariantSku         string          `json:"variant_sku"`
+	VariantOptions     json.RawMessage `json:"variant_options"`
+	StoreID            int64           `json:"store_id"`
+	ItemName           string          `json:"item_name"`
+	ItemDescription    string          `json:"item_description"`
+	Price              string          `json:"price"`
+	DiscountPercentage string          `json:"discount_percentage"`
+	ItemCategory       string          `json:"item_category"`
+	ItemCurrency       string          `json:"item_currency"`
+	ItemWeightGrams    int64           `json:"item_weight_grams"`
+	Quantity           int32           `json:"quantity"`
+	ItemImage          string          `json:"item_image"`
 }
 
 func (q *Queries) GetCartByUserID(ctx context.Context, userID int64) ([]GetCartByUserIDRow, error) {
@@ -93,9 +187,17 @@ func (q *Queries) GetCartByUserID(ctx context.Context, userID int64) ([]GetCartB
 		if err := rows.Scan(
 			&i.CartID,
 			&i.ItemID,
+			&i.VariantID,
+			&i.VariantSku,
+			&i.VariantOptions,
+			&i.StoreID,
 			&i.ItemName,
 			&i.ItemDescription,
 			&i.Price,
+			&i.DiscountPercentage,
+			&i.ItemCategory,
+			&i.ItemCurrency,
+			&i.ItemWeightGrams,
 			&i.Quantity,
 			&i.ItemImage,
 		); err != nil {
@@ -130,27 +232,35 @@ func (q *Queries) GetCartID(ctx context.Context, userID int64) (int64, error) {
 
 const increaseCartItemQuantity = `-- name: IncreaseCartItemQuantity :one
 WITH item_supply AS (
-  SELECT supply_quantity 
-  FROM items 
-  WHERE id = $3
+  SELECT COALESCE(
+    (SELECT v.supply_quantity FROM item_variants v WHERE v.id = $4::bigint),
+    (SELECT i.supply_quantity FROM items i WHERE i.id = $3::bigint)
+  ) AS supply_quantity
 )
 UPDATE cart_items 
 SET 
   quantity = LEAST((SELECT supply_quantity FROM item_supply), quantity + $1)
 WHERE 
-  cart_id = $2 
-  AND item_id = $3
-RETURNING id, cart_id, item_id, store_id, quantity, added_at, updated_at
+  cart_items.cart_id = $2 
+  AND cart_items.item_id = $3::bigint
+  AND cart_items.variant_id IS NOT DISTINCT FROM $4::bigint
+RETURNING id, cart_id, item_id, store_id, quantity, added_at, updated_at, variant_id
 `
 
 type IncreaseCartItemQuantityParams struct {
-	IncreaseAmount int32 `json:"increase_amount"`
-	CartID         int64 `json:"cart_id"`
-	ItemID         int64 `json:"item_id"`
+	IncreaseAmount int32         `json:"increase_amount"`
+	CartID         int64         `json:"cart_id"`
+	ItemID         int64         `json:"item_id"`
+	VariantID      sql.NullInt64 `json:"variant_id"`
 }
 
 func (q *Queries) IncreaseCartItemQuantity(ctx context.Context, arg IncreaseCartItemQuantityParams) (CartItem, error) {
-	row := q.db.QueryRowContext(ctx, increaseCartItemQuantity, arg.IncreaseAmount, arg.CartID, arg.ItemID)
+	row := q.db.QueryRowContext(ctx, increaseCartItemQuantity,
+		arg.IncreaseAmount,
+		arg.CartID,
+		arg.ItemID,
+		arg.VariantID,
+	)
 	var i CartItem
 	err := row.Scan(
 		&i.ID,
@@ -160,41 +270,132 @@ func (q *Queries) IncreaseCartItemQuantity(ctx context.Context, arg IncreaseCart
 		&i.Quantity,
 		&i.AddedAt,
 		&i.UpdatedAt,
+		&i.VariantID,
 	)
 	return i, err
 }
 
+const listCheckoutLines = `-- name: ListCheckoutLines :many
+SELECT id, reference, item_id, variant_id, variant_sku, store_id, item_name, price, discount_percentage, item_category, item_currency, item_weight_grams, quantity, created_at FROM checkout_lines
+WHERE reference = $1
+ORDER BY id
+`
+
+func (q *Queries) ListCheckoutLines(ctx context.Context, reference string) ([]CheckoutLine, error) {
+	rows, err := q.db.QueryContext(ctx, listCheckoutLines, reference)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CheckoutLine{}
+	for rows.Next() {
+		var i CheckoutLine
+		if err := rows.Scan(
+			&i.ID,
+			&i.Reference,
+			&i.ItemID,
+			&i.VariantID,
+			&i.VariantSku,
+			&i.StoreID,
+			&i.ItemName,
+			&i.Price,
+			&i.DiscountPercentage,
+			&i.ItemCategory,
+			&i.ItemCurrency,
+			&i.ItemWeightGrams,
+			&i.Quantity,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeCheckoutLinesFromCart = `-- name: RemoveCheckoutLinesFromCart :exec
+WITH bought AS (
+  SELECT item_id, variant_id, quantity FROM checkout_lines
+  WHERE reference = $2
+), removed AS (
+  DELETE FROM cart_items ci
+  USING bought b
+  WHERE ci.cart_id = $1
+    AND ci.item_id = b.item_id
+    AND ci.variant_id IS NOT DISTINCT FROM b.variant_id
+    AND ci.quantity <= b.quantity
+)
+UPDATE cart_items ci
+SET
+  quantity = ci.quantity - b.quantity,
+  updated_at = now()
+FROM bought b
+WHERE ci.cart_id = $1
+  AND ci.item_id = b.item_id
+  AND ci.variant_id IS NOT DISTINCT FROM b.variant_id
+  AND ci.quantity > b.quantity
+`
+
+type RemoveCheckoutLinesFromCartParams struct {
+	CartID    int64  `json:"cart_id"`
+	Reference string `json:"reference"`
+}
+
+// Takes the lines bought under reference out of a cart, leaving what was
+// added to it since.
+func (q *Queries) RemoveCheckoutLinesFromCart(ctx context.Context, arg RemoveCheckoutLinesFromCartParams) error {
+	_, err := q.db.ExecContext(ctx, removeCheckoutLinesFromCart, arg.CartID, arg.Reference)
+	return err
+}
+
 const removeItemFromCart = `-- name: RemoveItemFromCart :exec
 DELETE FROM cart_items
-WHERE cart_id = $1 AND item_id = $2
+WHERE cart_id = $1
+  AND item_id = $2
+  AND ($3::bigint IS NULL OR variant_id = $3::bigint)
 `
 
 type RemoveItemFromCartParams struct {
-	CartID int64 `json:"cart_id"`
-	ItemID int64 `json:"item_id"`
+	CartID    int64         `json:"cart_id"`
+	ItemID    int64         `json:"item_id"`
+	VariantID sql.NullInt64 `json:"variant_id"`
 }
 
+// Removes an item from a cart, only in a variant when one is given.
 func (q *Queries) RemoveItemFromCart(ctx context.Context, arg RemoveItemFromCartParams) error {
-	_, err := q.db.ExecContext(ctx, removeItemFromCart, arg.CartID, arg.ItemID)
+	_, err := q.db.ExecContext(ctx, removeItemFromCart, arg.CartID, arg.ItemID, arg.VariantID)
 	return err
 }
 
 const upsertCartItem = `-- name: UpsertCartItem :one
-SELECT id, cart_id, item_id, store_id, quantity, added_at, updated_at FROM upsert_cart_item(
+SELECT id, cart_id, item_id, store_id, quantity, added_at, updated_at, variant_id FROM upsert_cart_item(
   $1::bigint,
   $2::bigint,
-  $3::bigint
+  $3::bigint,
+  $4::bigint
 )
 `
 
 type UpsertCartItemParams struct {
-	CartID  int64 `json:"cart_id"`
-	ItemID  int64 `json:"item_id"`
-	StoreID int64 `json:"store_id"`
+	CartID    int64         `json:"cart_id"`
+	ItemID    int64         `json:"item_id"`
+	StoreID   int64         `json:"store_id"`
+	VariantID sql.NullInt64 `json:"variant_id"`
 }
 
 func (q *Queries) UpsertCartItem(ctx context.Context, arg UpsertCartItemParams) (CartItem, error) {
-	row := q.db.QueryRowContext(ctx, upsertCartItem, arg.CartID, arg.ItemID, arg.StoreID)
+	row := q.db.QueryRowContext(ctx, upsertCartItem,
+		arg.CartID,
+		arg.ItemID,
+		arg.StoreID,
+		arg.VariantID,
+	)
 	var i CartItem
 	err := row.Scan(
 		&i.ID,
@@ -204,6 +405,7 @@ func (q *Queries) UpsertCartItem(ctx context.Context, arg UpsertCartItemParams)
 		&i.Quantity,
 		&i.AddedAt,
 		&i.UpdatedAt,
+		&i.VariantID,
 	)
 	return i, err
 }
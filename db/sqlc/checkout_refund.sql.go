@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: checkout_refund.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const completeCheckoutRefund = `-- name: CompleteCheckoutRefund :one
+UPDATE checkout_refunds
+SET
+  status = 'COMPLETED',
+  provider_refund_id = $1,
+  updated_at = now()
+WHERE id = $2
+  AND status = 'PENDING'
+RETURNING id, transaction_id, amount, reason, status, provider_refund_id, failure_reason, created_at, updated_at
+`
+
+type CompleteCheckoutRefundParams struct {
+	ProviderRefundID string `json:"provider_refund_id"`
+	CheckoutRefundID int64  `json:"checkout_refund_id"`
+}
+
+func (q *Queries) CompleteCheckoutRefund(ctx context.Context, arg CompleteCheckoutRefundParams) (CheckoutRefund, error) {
+	row := q.db.QueryRowContext(ctx, completeCheckoutRefund, arg.ProviderRefundID, arg.CheckoutRefundID)
+	var i CheckoutRefund
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createCheckoutRefund = `-- name: CreateCheckoutRefund :one
+INSERT INTO checkout_refunds (
+  transaction_id,
+  amount,
+  reason
+) VALUES (
+  $1, $2, $3
+) RETURNING id, transaction_id, amount, reason, status, provider_refund_id, failure_reason, created_at, updated_at
+`
+
+type CreateCheckoutRefundParams struct {
+	TransactionID int64  `json:"transaction_id"`
+	Amount        string `json:"amount"`
+	Reason        string `json:"reason"`
+}
+
+func (q *Queries) CreateCheckoutRefund(ctx context.Context, arg CreateCheckoutRefundParams) (CheckoutRefund, error) {
+	row := q.db.QueryRowContext(ctx, createCheckoutRefund, arg.TransactionID, arg.Amount, arg.Reason)
+	var i CheckoutRefund
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const failCheckoutRefund = `-- name: FailCheckoutRefund :one
+UPDATE checkout_refunds
+SET
+  status = 'FAILED',
+  failure_reason = $1,
+  updated_at = now()
+WHERE id = $2
+  AND status = 'PENDING'
+RETURNING id, transaction_id, amount, reason, status, provider_refund_id, failure_reason, created_at, updated_at
+`
+
+type FailCheckoutRefundParams struct {
+	FailureReason    string `json:"failure_reason"`
+	CheckoutRefundID int64  `json:"checkout_refund_id"`
+}
+
+func (q *Queries) FailCheckoutRefund(ctx context.Context, arg FailCheckoutRefundParams) (CheckoutRefund, error) {
+	row := q.db.QueryRowContext(ctx, failCheckoutRefund, arg.FailureReason, arg.CheckoutRefundID)
+	var i CheckoutRefund
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCheckoutRefund = `-- name: GetCheckoutRefund :one
+SELECT
+  cr.id, cr.transaction_id, cr.amount, cr.reason, cr.status, cr.provider_refund_id, cr.failure_reason, cr.created_at, cr.updated_at,
+  t.provider_tx_ref_id,
+  t.payment_provider
+FROM checkout_refunds cr
+JOIN transactions t ON t.id = cr.transaction_id
+WHERE cr.id = $1
+`
+
+type GetCheckoutRefundRow struct {
+	ID               int64     `json:"id"`
+	TransactionID    int64     `json:"transaction_id"`
+	Amount           string    `json:"amount"`
+	Reason           string    `json:"reason"`
+	Status           string    `json:"status"`
+	ProviderRefundID string    `json:"provider_refund_id"`
+	FailureReason    string    `json:"failure_reason"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	ProviderTxRefID  string    `json:"provider_tx_ref_id"`
+	PaymentProvider  string    `json:"payment_provider"`
+}
+
+func (q *Queries) GetCheckoutRefund(ctx context.Context, checkoutRefundID int64) (GetCheckoutRefundRow, error) {
+	row := q.db.QueryRowContext(ctx, getCheckoutRefund, checkoutRefundID)
+	var i GetCheckoutRefundRow
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProviderTxRefID,
+		&i.PaymentProvider,
+	)
+	return i, err
+}
+
+const getCheckoutRefundByTransaction = `-- name: GetCheckoutRefundByTransaction :one
+SELECT id, transaction_id, amount, reason, status, provider_refund_id, failure_reason, created_at, updated_at FROM checkout_refunds
+WHERE transaction_id = $1
+`
+
+func (q *Queries) GetCheckoutRefundByTransaction(ctx context.Context, transactionID int64) (CheckoutRefund, error) {
+	row := q.db.QueryRowContext(ctx, getCheckoutRefundByTransaction, transactionID)
+	var i CheckoutRefund
+	err := row.Scan(
+		&i.ID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
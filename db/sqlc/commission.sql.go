@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: commission.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const createCommissionRule = `-- name: CreateCommissionRule :one
+INSERT INTO commission_rules (
+  scope,
+  store_id,
+  category,
+  percentage,
+  fixed_amount,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING id, scope, store_id, category, percentage, fixed_amount, created_by, created_at, updated_at
+`
+
+type CreateCommissionRuleParams struct {
+	Scope       string         `json:"scope"`
+	StoreID     sql.NullInt64  `json:"store_id"`
+	Category    sql.NullString `json:"category"`
+	Percentage  string         `json:"percentage"`
+	FixedAmount string         `json:"fixed_amount"`
+	CreatedBy   int64          `json:"created_by"`
+}
+
+func (q *Queries) CreateCommissionRule(ctx context.Context, arg CreateCommissionRuleParams) (CommissionRule, error) {
+	row := q.db.QueryRowContext(ctx, createCommissionRule,
+		arg.Scope,
+		arg.StoreID,
+		arg.Category,
+		arg.Percentage,
+		arg.FixedAmount,
+		arg.CreatedBy,
+	)
+	var i CommissionRule
+	err := row.Scan(
+		&i.ID,
+		&i.Scope,
+		&i.StoreID,
+		&i.Category,
+		&i.Percentage,
+		&i.FixedAmount,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCommissionRule = `-- name: DeleteCommissionRule :execrows
+DELETE FROM commission_rules
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCommissionRule(ctx context.Context, ruleID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCommissionRule, ruleID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCommissionRule = `-- name: GetCommissionRule :one
+SELECT id, scope, store_id, category, percentage, fixed_amount, created_by, created_at, updated_at FROM commission_rules
+WHERE id = $1
+`
+
+func (q *Queries) GetCommissionRule(ctx context.Context, ruleID int64) (CommissionRule, error) {
+	row := q.db.QueryRowContext(ctx, getCommissionRule, ruleID)
+	var i CommissionRule
+	err := row.Scan(
+		&i.ID,
+		&i.Scope,
+		&i.StoreID,
+		&i.Category,
+		&i.Percentage,
+		&i.FixedAmount,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listApplicableCommissionRules = `-- name: ListApplicableCommissionRules :many
+SELECT id, scope, store_id, category, percentage, fixed_amount, created_by, created_at, updated_at FROM commission_rules
+WHERE
+  scope = 'GLOBAL'
+  OR (scope = 'STORE' AND store_id = ANY($1::bigint[]))
+  OR (scope = 'CATEGORY' AND category = ANY($2::varchar[]))
+`
+
+type ListApplicableCommissionRulesParams struct {
+	StoreIds   []int64  `json:"store_ids"`
+	Categories []string `json:"categories"`
+}
+
+func (q *Queries) ListApplicableCommissionRules(ctx context.Context, arg ListApplicableCommissionRulesParams) ([]CommissionRule, error) {
+	rows, err := q.db.QueryContext(ctx, listApplicableCommissionRules, pq.Array(arg.StoreIds), pq.Array(arg.Categories))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CommissionRule{}
+	for rows.Next() {
+		var i CommissionRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Scope,
+			&i.StoreID,
+			&i.Category,
+			&i.Percentage,
+			&i.FixedAmount,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommissionRules = `-- name: ListCommissionRules :many
+SELECT id, scope, store_id, category, percentage, fixed_amount, created_by, created_at, updated_at FROM commission_rules
+WHERE
+  ($1::varchar IS NULL OR scope = $1)
+  AND ($2::bigint IS NULL OR store_id = $2)
+ORDER BY scope, id
+`
+
+type ListCommissionRulesParams struct {
+	Scope   sql.NullString `json:"scope"`
+	StoreID sql.NullInt64  `json:"store_id"`
+}
+
+func (q *Queries) ListCommissionRules(ctx context.Context, arg ListCommissionRulesParams) ([]CommissionRule, error) {
+	rows, err := q.db.QueryContext(ctx, listCommissionRules, arg.Scope, arg.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CommissionRule{}
+	for rows.Next() {
+		var i CommissionRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Scope,
+			&i.StoreID,
+			&i.Category,
+			&i.Percentage,
+			&i.FixedAmount,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCommissionRule = `-- name: UpdateCommissionRule :one
+UPDATE commission_rules
+SET
+  percentage = COALESCE($1, percentage),
+  fixed_amount = COALESCE($2, fixed_amount),
+  updated_at = now()
+WHERE id = $3
+RETURNING id, scope, store_id, category, percentage, fixed_amount, created_by, created_at, updated_at
+`
+
+type UpdateCommissionRuleParams struct {
+	Percentage  sql.NullString `json:"percentage"`
+	FixedAmount sql.NullString `json:"fixed_amount"`
+	RuleID      int64          `json:"rule_id"`
+}
+
+func (q *Queries) UpdateCommissionRule(ctx context.Context, arg UpdateCommissionRuleParams) (CommissionRule, error) {
+	row := q.db.QueryRowContext(ctx, updateCommissionRule, arg.Percentage, arg.FixedAmount, arg.RuleID)
+	var i CommissionRule
+	err := row.Scan(
+		&i.ID,
+		&i.Scope,
+		&i.StoreID,
+		&i.Category,
+		&i.Percentage,
+		&i.FixedAmount,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
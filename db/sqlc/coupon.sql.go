@@ -0,0 +1,691 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: coupon.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const addCartCoupon = `-- name: AddCartCoupon :one
+INSERT INTO cart_coupons (
+  cart_id,
+  coupon_id,
+  store_id
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (cart_id, store_id) DO UPDATE
+SET
+  coupon_id = EXCLUDED.coupon_id,
+  applied_at = now()
+RETURNING cart_id, coupon_id, store_id, applied_at
+`
+
+type AddCartCouponParams struct {
+	CartID   int64 `json:"cart_id"`
+	CouponID int64 `json:"coupon_id"`
+	StoreID  int64 `json:"store_id"`
+}
+
+func (q *Queries) AddCartCoupon(ctx context.Context, arg AddCartCouponParams) (CartCoupon, error) {
+	row := q.db.QueryRowContext(ctx, addCartCoupon, arg.CartID, arg.CouponID, arg.StoreID)
+	var i CartCoupon
+	err := row.Scan(
+		&i.CartID,
+		&i.CouponID,
+		&i.StoreID,
+		&i.AppliedAt,
+	)
+	return i, err
+}
+
+const clearCartCoupons = `-- name: ClearCartCoupons :exec
+DELETE FROM cart_coupons
+WHERE cart_id = $1
+`
+
+func (q *Queries) ClearCartCoupons(ctx context.Context, cartID int64) error {
+	_, err := q.db.ExecContext(ctx, clearCartCoupons, cartID)
+	return err
+}
+
+const countCouponRedemptions = `-- name: CountCouponRedemptions :one
+SELECT
+  count(*) AS total,
+  count(*) FILTER (WHERE user_id = $1) AS by_user
+FROM coupon_redemptions
+WHERE coupon_id = $2
+  AND status <> 'RELEASED'
+`
+
+type CountCouponRedemptionsParams struct {
+	UserID   int64 `json:"user_id"`
+	CouponID int64 `json:"coupon_id"`
+}
+
+type CountCouponRedemptionsRow struct {
+	Total  int64 `json:"total"`
+	ByUser int64 `json:"by_user"`
+}
+
+func (q *Queries) CountCouponRedemptions(ctx context.Context, arg CountCouponRedemptionsParams) (CountCouponRedemptionsRow, error) {
+	row := q.db.QueryRowContext(ctx, countCouponRedemptions, arg.UserID, arg.CouponID)
+	var i CountCouponRedemptionsRow
+	err := row.Scan(&i.Total, &i.ByUser)
+	return i, err
+}
+
+const createCoupon = `-- name: CreateCoupon :one
+INSERT INTO coupons (
+  store_id,
+  code,
+  description,
+  discount_type,
+  discount_value,
+  scope,
+  item_ids,
+  category,
+  min_spend,
+  usage_limit,
+  per_user_limit,
+  starts_at,
+  ends_at,
+  is_active,
+  created_by
+) VALUES (
+  $1, upper($2::varchar), $3, $4,
+  $5, $6, $7::bigint[], $8,
+  $9, $10, $11, $12,
+  $13, $14, $15
+)
+RETURNING id, store_id, code, description, discount_type, discount_value, scope, item_ids, category, min_spend, usage_limit, per_user_limit, starts_at, ends_at, is_active, created_by, created_at, updated_at
+`
+
+type CreateCouponParams struct {
+	StoreID       int64        `json:"store_id"`
+	Code          string       `json:"code"`
+	Description   string       `json:"description"`
+	DiscountType  string       `json:"discount_type"`
+	DiscountValue string       `json:"discount_value"`
+	Scope         string       `json:"scope"`
+	ItemIds       []int64      `json:"item_ids"`
+	Category      string       `json:"category"`
+	MinSpend      string       `json:"min_spend"`
+	UsageLimit    int32        `json:"usage_limit"`
+	PerUserLimit  int32        `json:"per_user_limit"`
+	StartsAt      time.Time    `json:"starts_at"`
+	EndsAt        sql.NullTime `json:"ends_at"`
+	IsActive      bool         `json:"is_active"`
+	CreatedBy     int64        `json:"created_by"`
+}
+
+func (q *Queries) CreateCoupon(ctx context.Context, arg CreateCouponParams) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, createCoupon,
+		arg.StoreID,
+		arg.Code,
+		arg.Description,
+		arg.DiscountType,
+		arg.DiscountValue,
+		arg.Scope,
+		pq.Array(arg.ItemIds),
+		arg.Category,
+		arg.MinSpend,
+		arg.UsageLimit,
+		arg.PerUserLimit,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.IsActive,
+		arg.CreatedBy,
+	)
+	var i Coupon
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Scope,
+		pq.Array(&i.ItemIds),
+		&i.Category,
+		&i.MinSpend,
+		&i.UsageLimit,
+		&i.PerUserLimit,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createCouponRedemption = `-- name: CreateCouponRedemption :one
+INSERT INTO coupon_redemptions (
+  coupon_id,
+  user_id,
+  reference,
+  discount_amount,
+  status
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, coupon_id, user_id, reference, discount_amount, status, created_at, updated_at
+`
+
+type CreateCouponRedemptionParams struct {
+	CouponID       int64  `json:"coupon_id"`
+	UserID         int64  `json:"user_id"`
+	Reference      string `json:"reference"`
+	DiscountAmount string `json:"discount_amount"`
+	Status         string `json:"status"`
+}
+
+func (q *Queries) CreateCouponRedemption(ctx context.Context, arg CreateCouponRedemptionParams) (CouponRedemption, error) {
+	row := q.db.QueryRowContext(ctx, createCouponRedemption,
+		arg.CouponID,
+		arg.UserID,
+		arg.Reference,
+		arg.DiscountAmount,
+		arg.Status,
+	)
+	var i CouponRedemption
+	err := row.Scan(
+		&i.ID,
+		&i.CouponID,
+		&i.UserID,
+		&i.Reference,
+		&i.DiscountAmount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCoupon = `-- name: DeleteCoupon :execrows
+DELETE FROM coupons c
+WHERE c.id = $1
+  AND c.store_id = $2
+  AND NOT EXISTS (SELECT 1 FROM coupon_redemptions cr WHERE cr.coupon_id = c.id)
+`
+
+type DeleteCouponParams struct {
+	CouponID int64 `json:"coupon_id"`
+	StoreID  int64 `json:"store_id"`
+}
+
+func (q *Queries) DeleteCoupon(ctx context.Context, arg DeleteCouponParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCoupon, arg.CouponID, arg.StoreID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCoupon = `-- name: GetCoupon :one
+SELECT id, store_id, code, description, discount_type, discount_value, scope, item_ids, category, min_spend, usage_limit, per_user_limit, starts_at, ends_at, is_active, created_by, created_at, updated_at FROM coupons
+WHERE id = $1
+  AND store_id = $2
+`
+
+type GetCouponParams struct {
+	CouponID int64 `json:"coupon_id"`
+	StoreID  int64 `json:"store_id"`
+}
+
+func (q *Queries) GetCoupon(ctx context.Context, arg GetCouponParams) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, getCoupon, arg.CouponID, arg.StoreID)
+	var i Coupon
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Scope,
+		pq.Array(&i.ItemIds),
+		&i.Category,
+		&i.MinSpend,
+		&i.UsageLimit,
+		&i.PerUserLimit,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCouponByCode = `-- name: GetCouponByCode :one
+SELECT id, store_id, code, description, discount_type, discount_value, scope, item_ids, category, min_spend, usage_limit, per_user_limit, starts_at, ends_at, is_active, created_by, created_at, updated_at FROM coupons
+WHERE code = upper($1::varchar)
+`
+
+func (q *Queries) GetCouponByCode(ctx context.Context, code string) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, getCouponByCode, code)
+	var i Coupon
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Scope,
+		pq.Array(&i.ItemIds),
+		&i.Category,
+		&i.MinSpend,
+		&i.UsageLimit,
+		&i.PerUserLimit,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCouponForUpdate = `-- name: GetCouponForUpdate :one
+SELECT id, store_id, code, description, discount_type, discount_value, scope, item_ids, category, min_spend, usage_limit, per_user_limit, starts_at, ends_at, is_active, created_by, created_at, updated_at FROM coupons
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetCouponForUpdate(ctx context.Context, couponID int64) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, getCouponForUpdate, couponID)
+	var i Coupon
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Scope,
+		pq.Array(&i.ItemIds),
+		&i.Category,
+		&i.MinSpend,
+		&i.UsageLimit,
+		&i.PerUserLimit,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCartCoupons = `-- name: ListCartCoupons :many
+SELECT c.id, c.store_id, c.code, c.description, c.discount_type, c.discount_value, c.scope, c.item_ids, c.category, c.min_spend, c.usage_limit, c.per_user_limit, c.starts_at, c.ends_at, c.is_active, c.created_by, c.created_at, c.updated_at FROM coupons c
+JOIN cart_coupons cc ON cc.coupon_id = c.id
+WHERE cc.cart_id = $1
+ORDER BY cc.applied_at
+`
+
+func (q *Queries) ListCartCoupons(ctx context.Context, cartID int64) ([]Coupon, error) {
+	rows, err := q.db.QueryContext(ctx, listCartCoupons, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Coupon{}
+	for rows.Next() {
+		var i Coupon
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Code,
+			&i.Description,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Scope,
+			pq.Array(&i.ItemIds),
+			&i.Category,
+			&i.MinSpend,
+			&i.UsageLimit,
+			&i.PerUserLimit,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.IsActive,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRedeemedCoupons = `-- name: ListRedeemedCoupons :many
+SELECT c.id, c.store_id, c.code, c.description, c.discount_type, c.discount_value, c.scope, c.item_ids, c.category, c.min_spend, c.usage_limit, c.per_user_limit, c.starts_at, c.ends_at, c.is_active, c.created_by, c.created_at, c.updated_at FROM coupons c
+JOIN coupon_redemptions cr ON cr.coupon_id = c.id
+WHERE cr.reference = $1
+  AND cr.status = $2
+ORDER BY cr.id
+`
+
+type ListRedeemedCouponsParams struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+}
+
+func (q *Queries) ListRedeemedCoupons(ctx context.Context, arg ListRedeemedCouponsParams) ([]Coupon, error) {
+	rows, err := q.db.QueryContext(ctx, listRedeemedCoupons, arg.Reference, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Coupon{}
+	for rows.Next() {
+		var i Coupon
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Code,
+			&i.Description,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Scope,
+			pq.Array(&i.ItemIds),
+			&i.Category,
+			&i.MinSpend,
+			&i.UsageLimit,
+			&i.PerUserLimit,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.IsActive,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreCoupons = `-- name: ListStoreCoupons :many
+SELECT
+  count(*) OVER() AS total_count,
+  c.id, c.store_id, c.code, c.description, c.discount_type, c.discount_value, c.scope, c.item_ids, c.category, c.min_spend, c.usage_limit, c.per_user_limit, c.starts_at, c.ends_at, c.is_active, c.created_by, c.created_at, c.updated_at,
+  (
+    SELECT count(*) FROM coupon_redemptions cr
+    WHERE cr.coupon_id = c.id AND cr.status <> 'RELEASED'
+  ) AS times_redeemed
+FROM coupons c
+WHERE c.store_id = $1
+  AND ($2::boolean IS NULL OR c.is_active = $2)
+ORDER BY c.id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListStoreCouponsParams struct {
+	StoreID  int64        `json:"store_id"`
+	IsActive sql.NullBool `json:"is_active"`
+	RwOffset int32        `json:"rw_offset"`
+	RwLimit  int32        `json:"rw_limit"`
+}
+
+type ListStoreCouponsRow struct {
+	TotalCount    int64        `json:"total_count"`
+	ID            int64        `json:"id"`
+	StoreID       int64        `json:"store_id"`
+	Code          string       `json:"code"`
+	Description   string       `json:"description"`
+	DiscountType  string       `json:"discount_type"`
+	DiscountValue string       `json:"discount_value"`
+	Scope         string       `json:"scope"`
+	ItemIds       []int64      `json:"item_ids"`
+	Category      string       `json:"category"`
+	MinSpend      string       `json:"min_spend"`
+	UsageLimit    int32        `json:"usage_limit"`
+	PerUserLimit  int32        `json:"per_user_limit"`
+	StartsAt      time.Time    `json:"starts_at"`
+	EndsAt        sql.NullTime `json:"ends_at"`
+	IsActive      bool         `json:"is_active"`
+	CreatedBy     int64        `json:"created_by"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	TimesRedeemed int64        `json:"times_redeemed"`
+}
+
+func (q *Queries) ListStoreCoupons(ctx context.Context, arg ListStoreCouponsParams) ([]ListStoreCouponsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreCoupons,
+		arg.StoreID,
+		arg.IsActive,
+		arg.RwOffset,
+		arg.RwLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStoreCouponsRow{}
+	for rows.Next() {
+		var i ListStoreCouponsRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.Code,
+			&i.Description,
+			&i.DiscountType,
+			&i.DiscountValue,
+			&i.Scope,
+			pq.Array(&i.ItemIds),
+			&i.Category,
+			&i.MinSpend,
+			&i.UsageLimit,
+			&i.PerUserLimit,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.IsActive,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TimesRedeemed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeCartCoupon = `-- name: RemoveCartCoupon :execrows
+DELETE FROM cart_coupons
+WHERE cart_id = $1
+  AND coupon_id = $2
+`
+
+type RemoveCartCouponParams struct {
+	CartID   int64 `json:"cart_id"`
+	CouponID int64 `json:"coupon_id"`
+}
+
+func (q *Queries) RemoveCartCoupon(ctx context.Context, arg RemoveCartCouponParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeCartCoupon, arg.CartID, arg.CouponID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setOrderCoupon = `-- name: SetOrderCoupon :one
+UPDATE orders
+SET
+  coupon_id = $1,
+  discount_amount = $2
+WHERE id = $3
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
+`
+
+type SetOrderCouponParams struct {
+	CouponID       sql.NullInt64 `json:"coupon_id"`
+	DiscountAmount string        `json:"discount_amount"`
+	OrderID        int64         `json:"order_id"`
+}
+
+func (q *Queries) SetOrderCoupon(ctx context.Context, arg SetOrderCouponParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderCoupon, arg.CouponID, arg.DiscountAmount, arg.OrderID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryStatus,
+		&i.DeliveredOn,
+		&i.ExpectedDeliveryDate,
+		&i.ItemID,
+		&i.ItemPrice,
+		&i.ItemCurrency,
+		&i.OrderQuantity,
+		&i.BuyerID,
+		&i.SellerID,
+		&i.StoreID,
+		&i.DeliveryFee,
+		&i.PaymentChannel,
+		&i.PaymentMethod,
+		&i.IsReviewed,
+		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
+	)
+	return i, err
+}
+
+const updateCoupon = `-- name: UpdateCoupon :one
+UPDATE coupons
+SET
+  description = COALESCE($1, description),
+  min_spend = COALESCE($2, min_spend),
+  usage_limit = COALESCE($3, usage_limit),
+  per_user_limit = COALESCE($4, per_user_limit),
+  starts_at = COALESCE($5, starts_at),
+  ends_at = COALESCE($6, ends_at),
+  is_active = COALESCE($7, is_active),
+  updated_at = now()
+WHERE id = $8
+  AND store_id = $9
+RETURNING id, store_id, code, description, discount_type, discount_value, scope, item_ids, category, min_spend, usage_limit, per_user_limit, starts_at, ends_at, is_active, created_by, created_at, updated_at
+`
+
+type UpdateCouponParams struct {
+	Description  sql.NullString `json:"description"`
+	MinSpend     sql.NullString `json:"min_spend"`
+	UsageLimit   sql.NullInt32  `json:"usage_limit"`
+	PerUserLimit sql.NullInt32  `json:"per_user_limit"`
+	StartsAt     sql.NullTime   `json:"starts_at"`
+	EndsAt       sql.NullTime   `json:"ends_at"`
+	IsActive     sql.NullBool   `json:"is_active"`
+	CouponID     int64          `json:"coupon_id"`
+	StoreID      int64          `json:"store_id"`
+}
+
+func (q *Queries) UpdateCoupon(ctx context.Context, arg UpdateCouponParams) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, updateCoupon,
+		arg.Description,
+		arg.MinSpend,
+		arg.UsageLimit,
+		arg.PerUserLimit,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.IsActive,
+		arg.CouponID,
+		arg.StoreID,
+	)
+	var i Coupon
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Description,
+		&i.DiscountType,
+		&i.DiscountValue,
+		&i.Scope,
+		pq.Array(&i.ItemIds),
+		&i.Category,
+		&i.MinSpend,
+		&i.UsageLimit,
+		&i.PerUserLimit,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.IsActive,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateCouponRedemptionsStatus = `-- name: UpdateCouponRedemptionsStatus :execrows
+UPDATE coupon_redemptions
+SET
+  status = $1,
+  updated_at = now()
+WHERE reference = $2
+  AND status = $3
+`
+
+type UpdateCouponRedemptionsStatusParams struct {
+	ToStatus   string `json:"to_status"`
+	Reference  string `json:"reference"`
+	FromStatus string `json:"from_status"`
+}
+
+func (q *Queries) UpdateCouponRedemptionsStatus(ctx context.Context, arg UpdateCouponRedemptionsStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateCouponRedemptionsStatus, arg.ToStatus, arg.Reference, arg.FromStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/OCD-Labs/store-hub/invoice"
 	"github.com/OCD-Labs/store-hub/pagination"
+	"github.com/OCD-Labs/store-hub/pricing"
 )
 
 var (
@@ -34,15 +36,109 @@ type StoreTx interface {
 	// AddCoOwner adds a co-owner to a store.
 	AddCoOwnerAccess(ctx context.Context, arg AddCoOwnerAccessParams) (StoreOwner, error)
 
-	// UpdateSellerOrderTx updates a order row, create a sale row if order is DELIVERED.
+	// UpdateSellerOrderTx updates a order row, create a sale row and releases the
+	// order's pending funds to the store if order is DELIVERED.
 	UpdateSellerOrderTx(ctx context.Context, arg UpdateSellerOrderParams) (GetOrderForSellerRow, error)
 
+	// UpdateShipmentTx updates a store's fulfilment group and moves its lines to a new delivery status.
+	UpdateShipmentTx(ctx context.Context, arg UpdateShipmentTxParams) (UpdateShipmentTxResult, error)
+
 	// CreateReviewTx create a review for an item under a store, updates an order.
 	CreateReviewTx(ctx context.Context, arg CreateReviewTxParams) error
 
+	// CreateStoreItemTx creates a store's item with its options and variants.
+	CreateStoreItemTx(ctx context.Context, arg CreateStoreItemTxParams) (ItemWithVariants, error)
+
+	// UpdateStoreItemTx updates a store's item, replacing its options and variants when given.
+	UpdateStoreItemTx(ctx context.Context, arg UpdateStoreItemTxParams) (ItemWithVariants, error)
+
+	// CreateItemImportTx records an upload of a CSV of items to a store, to be imported.
+	CreateItemImportTx(ctx context.Context, arg CreateItemImportTxParams) (ItemImport, error)
+
+	// ImportItemsTx creates or updates a store's items by SKU from the rows of an import's CSV.
+	ImportItemsTx(ctx context.Context, importID int64) (ItemImport, error)
+
+	// AddCartItemTx adds an item to a cart, in one of its variants if it has any.
+	AddCartItemTx(ctx context.Context, arg AddCartItemTxParams) (CartItem, error)
+
 	// GetUserCart retrieves a user's cart items.
 	GetUserCartTx(ctx context.Context, userID int64) (GetUserCartResult, error)
 
+	// QuoteCartTx prices a user's cart, with the coupons applied to it, and with delivery and tax charged for where it ships.
+	QuoteCartTx(ctx context.Context, arg QuoteCartTxParams) (QuoteCartTxResult, error)
+
+	// QuoteLines prices lines with the delivery rules of their stores.
+	QuoteLines(ctx context.Context, lines []pricing.Line) (pricing.Breakdown, error)
+
+	// ApplyCartCouponTx applies the coupon with a code to a user's cart.
+	ApplyCartCouponTx(ctx context.Context, arg ApplyCartCouponTxParams) (ApplyCartCouponTxResult, error)
+
+	// PrepareCheckoutTx prices a user's cart for checkout, reserves its stock, the coupons applied to it, the gift cards and store credit paying for it and the flash sale units claimed for it, and opens its order group.
+	PrepareCheckoutTx(ctx context.Context, arg PrepareCheckoutTxParams) (PrepareCheckoutTxResult, error)
+
+	// AbandonCheckoutTx releases the coupons, stock, gift cards, store credit and flash sale units reserved for a checkout, and fails its order group.
+	AbandonCheckoutTx(ctx context.Context, reference string) error
+
+	// FailTransactionTx marks a transaction FAILED, and releases the coupons, stock, gift cards, store credit and flash sale units reserved for it.
+	FailTransactionTx(ctx context.Context, arg FailTransactionTxParams) (Transaction, error)
+
+	// FailPaidCheckoutTx fails a paid checkout that can't be completed, and records a refund of what its provider charged.
+	FailPaidCheckoutTx(ctx context.Context, arg FailPaidCheckoutTxParams) (FailPaidCheckoutTxResult, error)
+
+	// CreateOrderTx prices and creates a single-item order, in the item's variant, in an order group of its own, redeeming a coupon for it.
+	CreateOrderTx(ctx context.Context, arg CreateOrderTxParams) (CreateOrderTxResult, error)
+
+	// CreateFlashSaleTx creates a flash sale for an item that has its quantity in stock and no other flash sale at the time.
+	CreateFlashSaleTx(ctx context.Context, arg CreateFlashSaleParams) (FlashSale, error)
+
+	// UpdateFxRatesTx replaces the exchange rates of the currencies in a quote.
+	UpdateFxRatesTx(ctx context.Context, arg UpdateFxRatesTxParams) ([]FxRate, error)
+
+	// CreateShippingZoneTx creates a store's shipping zone with its rate table.
+	CreateShippingZoneTx(ctx context.Context, arg CreateShippingZoneTxParams) (ShippingZoneWithRates, error)
+
+	// UpdateShippingZoneTx updates a store's shipping zone, replacing its rate table.
+	UpdateShippingZoneTx(ctx context.Context, arg UpdateShippingZoneTxParams) (ShippingZoneWithRates, error)
+
+	// CheckoutCartTx converts a user's cart into orders under a transaction and order group, and empties the cart.
+	CheckoutCartTx(ctx context.Context, arg CheckoutCartTxParams) (CheckoutCartTxResult, error)
+
+	// CreateGiftCardPurchaseTx creates a PENDING gift card for a store and the transaction paying for it.
+	CreateGiftCardPurchaseTx(ctx context.Context, arg CreateGiftCardPurchaseTxParams) (CreateGiftCardPurchaseTxResult, error)
+
+	// CompleteGiftCardPurchaseTx completes a gift card's transaction and activates the card.
+	CompleteGiftCardPurchaseTx(ctx context.Context, arg CompleteGiftCardPurchaseTxParams) (GiftCard, error)
+
+	// InvoiceDocument lays out an invoice with its store's orders, its buyer and where the orders ship to.
+	InvoiceDocument(ctx context.Context, inv Invoice) (invoice.Invoice, error)
+
+	// BuyItemTx deducts a quantity from the supply of an item without variants, if that much isn't reserved for checkouts.
+	BuyItemTx(ctx context.Context, arg BuyItemTxParams) (Item, error)
+
+	// StockTakeTx sets the supply of a store's counted items and variants to their counts, recording STOCK_TAKE movements.
+	StockTakeTx(ctx context.Context, arg StockTakeTxParams) ([]StockMovement, error)
+
+	// GetStoreBalancesTx retrieves a store's fiat and crypto balances.
+	GetStoreBalancesTx(ctx context.Context, storeID int64) ([]StoreBalance, error)
+
+	// CreateWithdrawalTx records a withdrawal request, holding its amount from the store's account.
+	CreateWithdrawalTx(ctx context.Context, arg CreateWithdrawalTxParams) (WithdrawalRequest, error)
+
+	// CancelWithdrawalTx cancels a pending withdrawal request, giving its amount back to the store's account.
+	CancelWithdrawalTx(ctx context.Context, arg CancelWithdrawalTxParams) (WithdrawalRequest, error)
+
+	// CreateRefundTx records a PENDING refund for a CANCELLED or RETURNED order.
+	CreateRefundTx(ctx context.Context, arg CreateRefundTxParams) (CreateRefundTxResult, error)
+
+	// CompleteRefundTx reverses a store's funds and restocks items for a refund the provider accepted.
+	CompleteRefundTx(ctx context.Context, arg CompleteRefundTxParams) (Refund, error)
+
+	// CompleteNEARTransferTx completes a NEAR transfer that executed on chain, and the refund it sends.
+	CompleteNEARTransferTx(ctx context.Context, transferID int64) (NearTransfer, error)
+
+	// FailNEARTransferTx fails a NEAR transfer that can't be sent, and the refund it sends.
+	FailNEARTransferTx(ctx context.Context, arg FailNEARTransferTxParams) (NearTransfer, error)
+
 	// ListAllStores do a fulltext search to list stores, and paginates accordingly.
 	ListAllStores(ctx context.Context, arg ListAllStoresParams) ([]StoreAndOwnersResult, pagination.Metadata, error)
 
@@ -52,8 +148,8 @@ type StoreTx interface {
 	// CreateUserTx creates a user row and schedules a verify email task on redis.
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (User, error)
 
-	// ListSellerOrders do a fulltext search to list orders, and paginates accordingly.
-	ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams) ([]SellerOrder, pagination.Metadata, error)
+	// ListSellerOrders do a fulltext search to list a store's shipments with their lines, and paginates accordingly.
+	ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams) ([]SellerShipment, pagination.Metadata, error)
 
 	// ListAllSellerSales do a fulltext search to list a seller sales, and paginates accordingly.
 	ListAllSellerSales(ctx context.Context, arg ListAllSellerSalesParams) ([]GetSaleRow, pagination.Metadata, error)
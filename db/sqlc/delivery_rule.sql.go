@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: delivery_rule.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const getStoreDeliveryRule = `-- name: GetStoreDeliveryRule :one
+SELECT store_id, base_fee, per_item_fee, free_delivery_threshold, updated_at FROM store_delivery_rules
+WHERE store_id = $1
+`
+
+func (q *Queries) GetStoreDeliveryRule(ctx context.Context, storeID int64) (StoreDeliveryRule, error) {
+	row := q.db.QueryRowContext(ctx, getStoreDeliveryRule, storeID)
+	var i StoreDeliveryRule
+	err := row.Scan(
+		&i.StoreID,
+		&i.BaseFee,
+		&i.PerItemFee,
+		&i.FreeDeliveryThreshold,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listStoreDeliveryRules = `-- name: ListStoreDeliveryRules :many
+SELECT store_id, base_fee, per_item_fee, free_delivery_threshold, updated_at FROM store_delivery_rules
+WHERE store_id = ANY($1::bigint[])
+`
+
+func (q *Queries) ListStoreDeliveryRules(ctx context.Context, storeIds []int64) ([]StoreDeliveryRule, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreDeliveryRules, pq.Array(storeIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StoreDeliveryRule{}
+	for rows.Next() {
+		var i StoreDeliveryRule
+		if err := rows.Scan(
+			&i.StoreID,
+			&i.BaseFee,
+			&i.PerItemFee,
+			&i.FreeDeliveryThreshold,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertStoreDeliveryRule = `-- name: UpsertStoreDeliveryRule :one
+INSERT INTO store_delivery_rules (
+  store_id,
+  base_fee,
+  per_item_fee,
+  free_delivery_threshold
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (store_id) DO UPDATE
+SET
+  base_fee = EXCLUDED.base_fee,
+  per_item_fee = EXCLUDED.per_item_fee,
+  free_delivery_threshold = EXCLUDED.free_delivery_threshold,
+  updated_at = now()
+RETURNING store_id, base_fee, per_item_fee, free_delivery_threshold, updated_at
+`
+
+type UpsertStoreDeliveryRuleParams struct {
+	StoreID               int64  `json:"store_id"`
+	BaseFee               string `json:"base_fee"`
+	PerItemFee            string `json:"per_item_fee"`
+	FreeDeliveryThreshold string `json:"free_delivery_threshold"`
+}
+
+func (q *Queries) UpsertStoreDeliveryRule(ctx context.Context, arg UpsertStoreDeliveryRuleParams) (StoreDeliveryRule, error) {
+	row := q.db.QueryRowContext(ctx, upsertStoreDeliveryRule,
+		arg.StoreID,
+		arg.BaseFee,
+		arg.PerItemFee,
+		arg.FreeDeliveryThreshold,
+	)
+	var i StoreDeliveryRule
+	err := row.Scan(
+		&i.StoreID,
+		&i.BaseFee,
+		&i.PerItemFee,
+		&i.FreeDeliveryThreshold,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
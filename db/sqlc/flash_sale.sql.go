@@ -0,0 +1,825 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: flash_sale.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const addFlashSaleSold = `-- name: AddFlashSaleSold :exec
+UPDATE flash_sales
+SET
+  sold_quantity = sold_quantity + $1::int,
+  updated_at = now()
+WHERE id = $2
+`
+
+type AddFlashSaleSoldParams struct {
+	Quantity    int32 `json:"quantity"`
+	FlashSaleID int64 `json:"flash_sale_id"`
+}
+
+func (q *Queries) AddFlashSaleSold(ctx context.Context, arg AddFlashSaleSoldParams) error {
+	_, err := q.db.ExecContext(ctx, addFlashSaleSold, arg.Quantity, arg.FlashSaleID)
+	return err
+}
+
+const cancelFlashSale = `-- name: CancelFlashSale :one
+UPDATE flash_sales
+SET
+  cancelled_at = now(),
+  updated_at = now()
+WHERE id = $1
+  AND store_id = $2
+  AND cancelled_at IS NULL
+  AND ends_at > now()
+RETURNING id, store_id, item_id, sale_price, quantity, per_user_limit, claimed_quantity, sold_quantity, starts_at, ends_at, cancelled_at, reconciled_at, created_by, created_at, updated_at
+`
+
+type CancelFlashSaleParams struct {
+	FlashSaleID int64 `json:"flash_sale_id"`
+	StoreID     int64 `json:"store_id"`
+}
+
+// Cancels a flash sale that hasn't ended.
+func (q *Queries) CancelFlashSale(ctx context.Context, arg CancelFlashSaleParams) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, cancelFlashSale, arg.FlashSaleID, arg.StoreID)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.ItemID,
+		&i.SalePrice,
+		&i.Quantity,
+		&i.PerUserLimit,
+		&i.ClaimedQuantity,
+		&i.SoldQuantity,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CancelledAt,
+		&i.ReconciledAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countOverlappingFlashSales = `-- name: CountOverlappingFlashSales :one
+SELECT count(*) FROM flash_sales
+WHERE item_id = $1
+  AND cancelled_at IS NULL
+  AND starts_at < $2
+  AND ends_at > $3
+`
+
+type CountOverlappingFlashSalesParams struct {
+	ItemID   int64     `json:"item_id"`
+	EndsAt   time.Time `json:"ends_at"`
+	StartsAt time.Time `json:"starts_at"`
+}
+
+func (q *Queries) CountOverlappingFlashSales(ctx context.Context, arg CountOverlappingFlashSalesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOverlappingFlashSales, arg.ItemID, arg.EndsAt, arg.StartsAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFlashSale = `-- name: CreateFlashSale :one
+INSERT INTO flash_sales (
+  store_id,
+  item_id,
+  sale_price,
+  quantity,
+  per_user_limit,
+  starts_at,
+  ends_at,
+  created_by
+) VALUES (
+  $1, $2, $3, $4,
+  $5, $6, $7, $8
+) RETURNING id, store_id, item_id, sale_price, quantity, per_user_limit, claimed_quantity, sold_quantity, starts_at, ends_at, cancelled_at, reconciled_at, created_by, created_at, updated_at
+`
+
+type CreateFlashSaleParams struct {
+	StoreID      int64     `json:"store_id"`
+	ItemID       int64     `json:"item_id"`
+	SalePrice    string    `json:"sale_price"`
+	Quantity     int32     `json:"quantity"`
+	PerUserLimit int32     `json:"per_user_limit"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	CreatedBy    int64     `json:"created_by"`
+}
+
+func (q *Queries) CreateFlashSale(ctx context.Context, arg CreateFlashSaleParams) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, createFlashSale,
+		arg.StoreID,
+		arg.ItemID,
+		arg.SalePrice,
+		arg.Quantity,
+		arg.PerUserLimit,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.CreatedBy,
+	)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.ItemID,
+		&i.SalePrice,
+		&i.Quantity,
+		&i.PerUserLimit,
+		&i.ClaimedQuantity,
+		&i.SoldQuantity,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CancelledAt,
+		&i.ReconciledAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createFlashSaleClaim = `-- name: CreateFlashSaleClaim :one
+INSERT INTO flash_sale_claims (
+  flash_sale_id,
+  user_id,
+  reference,
+  quantity
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, flash_sale_id, user_id, reference, quantity, status, returned_at, created_at, updated_at
+`
+
+type CreateFlashSaleClaimParams struct {
+	FlashSaleID int64  `json:"flash_sale_id"`
+	UserID      int64  `json:"user_id"`
+	Reference   string `json:"reference"`
+	Quantity    int32  `json:"quantity"`
+}
+
+func (q *Queries) CreateFlashSaleClaim(ctx context.Context, arg CreateFlashSaleClaimParams) (FlashSaleClaim, error) {
+	row := q.db.QueryRowContext(ctx, createFlashSaleClaim,
+		arg.FlashSaleID,
+		arg.UserID,
+		arg.Reference,
+		arg.Quantity,
+	)
+	var i FlashSaleClaim
+	err := row.Scan(
+		&i.ID,
+		&i.FlashSaleID,
+		&i.UserID,
+		&i.Reference,
+		&i.Quantity,
+		&i.Status,
+		&i.ReturnedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFlashSale = `-- name: GetFlashSale :one
+SELECT id, store_id, item_id, sale_price, quantity, per_user_limit, claimed_quantity, sold_quantity, starts_at, ends_at, cancelled_at, reconciled_at, created_by, created_at, updated_at FROM flash_sales
+WHERE id = $1 AND store_id = $2
+`
+
+type GetFlashSaleParams struct {
+	FlashSaleID int64 `json:"flash_sale_id"`
+	StoreID     int64 `json:"store_id"`
+}
+
+func (q *Queries) GetFlashSale(ctx context.Context, arg GetFlashSaleParams) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, getFlashSale, arg.FlashSaleID, arg.StoreID)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.ItemID,
+		&i.SalePrice,
+		&i.Quantity,
+		&i.PerUserLimit,
+		&i.ClaimedQuantity,
+		&i.SoldQuantity,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CancelledAt,
+		&i.ReconciledAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFlashSaleHeldStock = `-- name: GetFlashSaleHeldStock :one
+SELECT (
+  COALESCE((
+    SELECT sum(GREATEST(fs.quantity - fs.sold_quantity, 0))
+    FROM flash_sales fs
+    WHERE fs.item_id = $1
+      AND fs.cancelled_at IS NULL
+      AND fs.ends_at > now()
+  ), 0)
+  + COALESCE((
+    SELECT sum(fsc.quantity)
+    FROM flash_sale_claims fsc
+    JOIN flash_sales fs ON fs.id = fsc.flash_sale_id
+    WHERE fs.item_id = $1
+      AND fsc.status = 'CLAIMED'
+      AND (fs.cancelled_at IS NOT NULL OR fs.ends_at <= now())
+  ), 0)
+)::bigint AS held
+`
+
+// The units of an item its flash sales hold out of its available stock: those
+// not sold yet of sales that haven't ended, and those claimed by checkouts
+// not completed yet of sales that have.
+func (q *Queries) GetFlashSaleHeldStock(ctx context.Context, itemID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getFlashSaleHeldStock, itemID)
+	var held int64
+	err := row.Scan(&held)
+	return held, err
+}
+
+const getItemFlashSale = `-- name: GetItemFlashSale :one
+SELECT id, store_id, item_id, sale_price, quantity, per_user_limit, claimed_quantity, sold_quantity, starts_at, ends_at, cancelled_at, reconciled_at, created_by, created_at, updated_at FROM flash_sales
+WHERE item_id = $1
+  AND cancelled_at IS NULL
+  AND ends_at > now()
+ORDER BY starts_at
+LIMIT 1
+`
+
+// The flash sale an item is on, or its next one.
+func (q *Queries) GetItemFlashSale(ctx context.Context, itemID int64) (FlashSale, error) {
+	row := q.db.QueryRowContext(ctx, getItemFlashSale, itemID)
+	var i FlashSale
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.ItemID,
+		&i.SalePrice,
+		&i.Quantity,
+		&i.PerUserLimit,
+		&i.ClaimedQuantity,
+		&i.SoldQuantity,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CancelledAt,
+		&i.ReconciledAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCartFlashSales = `-- name: ListCartFlashSales :many
+SELECT
+  fs.id, fs.store_id, fs.item_id, fs.sale_price, fs.quantity, fs.per_user_limit, fs.claimed_quantity, fs.sold_quantity, fs.starts_at, fs.ends_at, fs.cancelled_at, fs.reconciled_at, fs.created_by, fs.created_at, fs.updated_at,
+  sum(ci.quantity)::int AS cart_quantity
+FROM carts c
+JOIN cart_items ci ON ci.cart_id = c.id
+JOIN flash_sales fs ON fs.item_id = ci.item_id
+WHERE c.user_id = $1
+  AND fs.cancelled_at IS NULL
+  AND fs.starts_at <= now()
+  AND fs.ends_at > now()
+GROUP BY fs.id
+`
+
+type ListCartFlashSalesRow struct {
+	ID              int64        `json:"id"`
+	StoreID         int64        `json:"store_id"`
+	ItemID          int64        `json:"item_id"`
+	SalePrice       string       `json:"sale_price"`
+	Quantity        int32        `json:"quantity"`
+	PerUserLimit    int32        `json:"per_user_limit"`
+	ClaimedQuantity int32        `json:"claimed_quantity"`
+	SoldQuantity    int32        `json:"sold_quantity"`
+	StartsAt        time.Time    `json:"starts_at"`
+	EndsAt          time.Time    `json:"ends_at"`
+	CancelledAt     sql.NullTime `json:"cancelled_at"`
+	ReconciledAt    sql.NullTime `json:"reconciled_at"`
+	CreatedBy       int64        `json:"created_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	CartQuantity    int32        `json:"cart_quantity"`
+}
+
+// The flash sales running on the items in a user's cart, with how many of
+// each item the cart holds, in all its variants.
+func (q *Queries) ListCartFlashSales(ctx context.Context, userID int64) ([]ListCartFlashSalesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCartFlashSales, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCartFlashSalesRow{}
+	for rows.Next() {
+		var i ListCartFlashSalesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.SalePrice,
+			&i.Quantity,
+			&i.PerUserLimit,
+			&i.ClaimedQuantity,
+			&i.SoldQuantity,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CancelledAt,
+			&i.ReconciledAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CartQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCheckoutFlashSales = `-- name: ListCheckoutFlashSales :many
+SELECT fs.id, fs.store_id, fs.item_id, fs.sale_price, fs.quantity, fs.per_user_limit, fs.claimed_quantity, fs.sold_quantity, fs.starts_at, fs.ends_at, fs.cancelled_at, fs.reconciled_at, fs.created_by, fs.created_at, fs.updated_at FROM flash_sales fs
+JOIN flash_sale_claims fsc ON fsc.flash_sale_id = fs.id
+WHERE fsc.reference = $1
+  AND fsc.status = $2
+`
+
+type ListCheckoutFlashSalesParams struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+}
+
+// The flash sales claimed for the checkout under reference, whether or not
+// they've ended since.
+func (q *Queries) ListCheckoutFlashSales(ctx context.Context, arg ListCheckoutFlashSalesParams) ([]FlashSale, error) {
+	rows, err := q.db.QueryContext(ctx, listCheckoutFlashSales, arg.Reference, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FlashSale{}
+	for rows.Next() {
+		var i FlashSale
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.SalePrice,
+			&i.Quantity,
+			&i.PerUserLimit,
+			&i.ClaimedQuantity,
+			&i.SoldQuantity,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CancelledAt,
+			&i.ReconciledAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFlashSalesToReconcile = `-- name: ListFlashSalesToReconcile :many
+SELECT id, store_id, item_id, sale_price, quantity, per_user_limit, claimed_quantity, sold_quantity, starts_at, ends_at, cancelled_at, reconciled_at, created_by, created_at, updated_at FROM flash_sales
+WHERE starts_at <= now()
+  AND (reconciled_at IS NULL OR reconciled_at < ends_at)
+ORDER BY id
+LIMIT $1
+`
+
+// Flash sales that have started and not been reconciled since they ended.
+func (q *Queries) ListFlashSalesToReconcile(ctx context.Context, rwLimit int32) ([]FlashSale, error) {
+	rows, err := q.db.QueryContext(ctx, listFlashSalesToReconcile, rwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FlashSale{}
+	for rows.Next() {
+		var i FlashSale
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.SalePrice,
+			&i.Quantity,
+			&i.PerUserLimit,
+			&i.ClaimedQuantity,
+			&i.SoldQuantity,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CancelledAt,
+			&i.ReconciledAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLiveFlashSales = `-- name: ListLiveFlashSales :many
+SELECT id, store_id, item_id, sale_price, quantity, per_user_limit, claimed_quantity, sold_quantity, starts_at, ends_at, cancelled_at, reconciled_at, created_by, created_at, updated_at FROM flash_sales
+WHERE item_id = ANY($1::bigint[])
+  AND cancelled_at IS NULL
+  AND starts_at <= now()
+  AND ends_at > now()
+`
+
+func (q *Queries) ListLiveFlashSales(ctx context.Context, itemIds []int64) ([]FlashSale, error) {
+	rows, err := q.db.QueryContext(ctx, listLiveFlashSales, pq.Array(itemIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FlashSale{}
+	for rows.Next() {
+		var i FlashSale
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.SalePrice,
+			&i.Quantity,
+			&i.PerUserLimit,
+			&i.ClaimedQuantity,
+			&i.SoldQuantity,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CancelledAt,
+			&i.ReconciledAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreFlashSales = `-- name: ListStoreFlashSales :many
+SELECT
+  count(*) OVER() AS total_count,
+  fs.id, fs.store_id, fs.item_id, fs.sale_price, fs.quantity, fs.per_user_limit, fs.claimed_quantity, fs.sold_quantity, fs.starts_at, fs.ends_at, fs.cancelled_at, fs.reconciled_at, fs.created_by, fs.created_at, fs.updated_at,
+  i.name AS item_name
+FROM flash_sales fs
+JOIN items i ON i.id = fs.item_id
+WHERE fs.store_id = $1
+ORDER BY fs.starts_at DESC, fs.id DESC
+LIMIT $3
+OFFSET $2
+`
+
+type ListStoreFlashSalesParams struct {
+	StoreID  int64 `json:"store_id"`
+	RwOffset int32 `json:"rw_offset"`
+	RwLimit  int32 `json:"rw_limit"`
+}
+
+type ListStoreFlashSalesRow struct {
+	TotalCount      int64        `json:"total_count"`
+	ID              int64        `json:"id"`
+	StoreID         int64        `json:"store_id"`
+	ItemID          int64        `json:"item_id"`
+	SalePrice       string       `json:"sale_price"`
+	Quantity        int32        `json:"quantity"`
+	PerUserLimit    int32        `json:"per_user_limit"`
+	ClaimedQuantity int32        `json:"claimed_quantity"`
+	SoldQuantity    int32        `json:"sold_quantity"`
+	StartsAt        time.Time    `json:"starts_at"`
+	EndsAt          time.Time    `json:"ends_at"`
+	CancelledAt     sql.NullTime `json:"cancelled_at"`
+	ReconciledAt    sql.NullTime `json:"reconciled_at"`
+	CreatedBy       int64        `json:"created_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	ItemName        string       `json:"item_name"`
+}
+
+func (q *Queries) ListStoreFlashSales(ctx context.Context, arg ListStoreFlashSalesParams) ([]ListStoreFlashSalesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreFlashSales, arg.StoreID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStoreFlashSalesRow{}
+	for rows.Next() {
+		var i ListStoreFlashSalesRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.SalePrice,
+			&i.Quantity,
+			&i.PerUserLimit,
+			&i.ClaimedQuantity,
+			&i.SoldQuantity,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CancelledAt,
+			&i.ReconciledAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ItemName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnreturnedFlashSaleClaims = `-- name: ListUnreturnedFlashSaleClaims :many
+SELECT
+  fsc.id, fsc.flash_sale_id, fsc.user_id, fsc.reference, fsc.quantity, fsc.status, fsc.returned_at, fsc.created_at, fsc.updated_at,
+  fs.ends_at
+FROM flash_sale_claims fsc
+JOIN flash_sales fs ON fs.id = fsc.flash_sale_id
+WHERE fsc.status = 'RELEASED'
+  AND fsc.returned_at IS NULL
+ORDER BY fsc.id
+LIMIT $1
+`
+
+type ListUnreturnedFlashSaleClaimsRow struct {
+	ID          int64        `json:"id"`
+	FlashSaleID int64        `json:"flash_sale_id"`
+	UserID      int64        `json:"user_id"`
+	Reference   string       `json:"reference"`
+	Quantity    int32        `json:"quantity"`
+	Status      string       `json:"status"`
+	ReturnedAt  sql.NullTime `json:"returned_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	EndsAt      time.Time    `json:"ends_at"`
+}
+
+// RELEASED claims whose units haven't been given back in Redis yet.
+func (q *Queries) ListUnreturnedFlashSaleClaims(ctx context.Context, rwLimit int32) ([]ListUnreturnedFlashSaleClaimsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnreturnedFlashSaleClaims, rwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnreturnedFlashSaleClaimsRow{}
+	for rows.Next() {
+		var i ListUnreturnedFlashSaleClaimsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FlashSaleID,
+			&i.UserID,
+			&i.Reference,
+			&i.Quantity,
+			&i.Status,
+			&i.ReturnedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingFlashSales = `-- name: ListUpcomingFlashSales :many
+SELECT
+  count(*) OVER() AS total_count,
+  fs.id, fs.store_id, fs.item_id, fs.sale_price, fs.quantity, fs.per_user_limit, fs.claimed_quantity, fs.sold_quantity, fs.starts_at, fs.ends_at, fs.cancelled_at, fs.reconciled_at, fs.created_by, fs.created_at, fs.updated_at,
+  i.name AS item_name,
+  i.price AS item_price,
+  i.currency AS item_currency,
+  i.cover_img_url AS item_image
+FROM flash_sales fs
+JOIN items i ON i.id = fs.item_id
+WHERE fs.store_id = $1
+  AND fs.cancelled_at IS NULL
+  AND fs.ends_at > now()
+ORDER BY fs.starts_at, fs.id
+LIMIT $3
+OFFSET $2
+`
+
+type ListUpcomingFlashSalesParams struct {
+	StoreID  int64 `json:"store_id"`
+	RwOffset int32 `json:"rw_offset"`
+	RwLimit  int32 `json:"rw_limit"`
+}
+
+type ListUpcomingFlashSalesRow struct {
+	TotalCount      int64        `json:"total_count"`
+	ID              int64        `json:"id"`
+	StoreID         int64        `json:"store_id"`
+	ItemID          int64        `json:"item_id"`
+	SalePrice       string       `json:"sale_price"`
+	Quantity        int32        `json:"quantity"`
+	PerUserLimit    int32        `json:"per_user_limit"`
+	ClaimedQuantity int32        `json:"claimed_quantity"`
+	SoldQuantity    int32        `json:"sold_quantity"`
+	StartsAt        time.Time    `json:"starts_at"`
+	EndsAt          time.Time    `json:"ends_at"`
+	CancelledAt     sql.NullTime `json:"cancelled_at"`
+	ReconciledAt    sql.NullTime `json:"reconciled_at"`
+	CreatedBy       int64        `json:"created_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	ItemName        string       `json:"item_name"`
+	ItemPrice       string       `json:"item_price"`
+	ItemCurrency    string       `json:"item_currency"`
+	ItemImage       string       `json:"item_image"`
+}
+
+// A store's flash sales that are running or yet to start.
+func (q *Queries) ListUpcomingFlashSales(ctx context.Context, arg ListUpcomingFlashSalesParams) ([]ListUpcomingFlashSalesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUpcomingFlashSales, arg.StoreID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUpcomingFlashSalesRow{}
+	for rows.Next() {
+		var i ListUpcomingFlashSalesRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.SalePrice,
+			&i.Quantity,
+			&i.PerUserLimit,
+			&i.ClaimedQuantity,
+			&i.SoldQuantity,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CancelledAt,
+			&i.ReconciledAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ItemName,
+			&i.ItemPrice,
+			&i.ItemCurrency,
+			&i.ItemImage,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFlashSaleClaimReturned = `-- name: SetFlashSaleClaimReturned :exec
+UPDATE flash_sale_claims
+SET
+  returned_at = now(),
+  updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) SetFlashSaleClaimReturned(ctx context.Context, claimID int64) error {
+	_, err := q.db.ExecContext(ctx, setFlashSaleClaimReturned, claimID)
+	return err
+}
+
+const setFlashSaleClaimedQuantity = `-- name: SetFlashSaleClaimedQuantity :exec
+UPDATE flash_sales
+SET
+  claimed_quantity = $1,
+  reconciled_at = now(),
+  updated_at = now()
+WHERE id = $2
+`
+
+type SetFlashSaleClaimedQuantityParams struct {
+	ClaimedQuantity int32 `json:"claimed_quantity"`
+	FlashSaleID     int64 `json:"flash_sale_id"`
+}
+
+func (q *Queries) SetFlashSaleClaimedQuantity(ctx context.Context, arg SetFlashSaleClaimedQuantityParams) error {
+	_, err := q.db.ExecContext(ctx, setFlashSaleClaimedQuantity, arg.ClaimedQuantity, arg.FlashSaleID)
+	return err
+}
+
+const updateFlashSaleClaimsStatus = `-- name: UpdateFlashSaleClaimsStatus :many
+UPDATE flash_sale_claims
+SET
+  status = $1,
+  updated_at = now()
+WHERE reference = $2
+  AND status = $3
+RETURNING id, flash_sale_id, user_id, reference, quantity, status, returned_at, created_at, updated_at
+`
+
+type UpdateFlashSaleClaimsStatusParams struct {
+	ToStatus   string `json:"to_status"`
+	Reference  string `json:"reference"`
+	FromStatus string `json:"from_status"`
+}
+
+func (q *Queries) UpdateFlashSaleClaimsStatus(ctx context.Context, arg UpdateFlashSaleClaimsStatusParams) ([]FlashSaleClaim, error) {
+	rows, err := q.db.QueryContext(ctx, updateFlashSaleClaimsStatus, arg.ToStatus, arg.Reference, arg.FromStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FlashSaleClaim{}
+	for rows.Next() {
+		var i FlashSaleClaim
+		if err := rows.Scan(
+			&i.ID,
+			&i.FlashSaleID,
+			&i.UserID,
+			&i.Reference,
+			&i.Quantity,
+			&i.Status,
+			&i.ReturnedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,250 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: fx_rate.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createCheckoutFxRate = `-- name: CreateCheckoutFxRate :exec
+INSERT INTO checkout_fx_rates (
+  reference,
+  currency,
+  rate
+) VALUES (
+  $1, $2, $3
+)
+`
+
+type CreateCheckoutFxRateParams struct {
+	Reference string `json:"reference"`
+	Currency  string `json:"currency"`
+	Rate      string `json:"rate"`
+}
+
+func (q *Queries) CreateCheckoutFxRate(ctx context.Context, arg CreateCheckoutFxRateParams) error {
+	_, err := q.db.ExecContext(ctx, createCheckoutFxRate, arg.Reference, arg.Currency, arg.Rate)
+	return err
+}
+
+const getFxRate = `-- name: GetFxRate :one
+SELECT currency, rate, source, as_of, updated_at FROM fx_rates
+WHERE currency = $1
+`
+
+func (q *Queries) GetFxRate(ctx context.Context, currency string) (FxRate, error) {
+	row := q.db.QueryRowContext(ctx, getFxRate, currency)
+	var i FxRate
+	err := row.Scan(
+		&i.Currency,
+		&i.Rate,
+		&i.Source,
+		&i.AsOf,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCheckoutFxRates = `-- name: ListCheckoutFxRates :many
+SELECT reference, currency, rate, created_at FROM checkout_fx_rates
+WHERE reference = $1
+`
+
+func (q *Queries) ListCheckoutFxRates(ctx context.Context, reference string) ([]CheckoutFxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listCheckoutFxRates, reference)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CheckoutFxRate{}
+	for rows.Next() {
+		var i CheckoutFxRate
+		if err := rows.Scan(
+			&i.Reference,
+			&i.Currency,
+			&i.Rate,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFxRates = `-- name: ListFxRates :many
+SELECT currency, rate, source, as_of, updated_at FROM fx_rates
+ORDER BY currency
+`
+
+func (q *Queries) ListFxRates(ctx context.Context) ([]FxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listFxRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FxRate{}
+	for rows.Next() {
+		var i FxRate
+		if err := rows.Scan(
+			&i.Currency,
+			&i.Rate,
+			&i.Source,
+			&i.AsOf,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFxRatesFor = `-- name: ListFxRatesFor :many
+SELECT currency, rate, source, as_of, updated_at FROM fx_rates
+WHERE currency = ANY($1::varchar[])
+`
+
+func (q *Queries) ListFxRatesFor(ctx context.Context, currencies []string) ([]FxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listFxRatesFor, pq.Array(currencies))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FxRate{}
+	for rows.Next() {
+		var i FxRate
+		if err := rows.Scan(
+			&i.Currency,
+			&i.Rate,
+			&i.Source,
+			&i.AsOf,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setOrderFxRate = `-- name: SetOrderFxRate :one
+UPDATE orders
+SET
+  currency = $1,
+  fx_rate = $2
+WHERE id = $3
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
+`
+
+type SetOrderFxRateParams struct {
+	Currency string `json:"currency"`
+	FxRate   string `json:"fx_rate"`
+	OrderID  int64  `json:"order_id"`
+}
+
+func (q *Queries) SetOrderFxRate(ctx context.Context, arg SetOrderFxRateParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderFxRate, arg.Currency, arg.FxRate, arg.OrderID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryStatus,
+		&i.DeliveredOn,
+		&i.ExpectedDeliveryDate,
+		&i.ItemID,
+		&i.ItemPrice,
+		&i.ItemCurrency,
+		&i.OrderQuantity,
+		&i.BuyerID,
+		&i.SellerID,
+		&i.StoreID,
+		&i.DeliveryFee,
+		&i.PaymentChannel,
+		&i.PaymentMethod,
+		&i.IsReviewed,
+		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
+	)
+	return i, err
+}
+
+const upsertFxRate = `-- name: UpsertFxRate :one
+INSERT INTO fx_rates (
+  currency,
+  rate,
+  source,
+  as_of
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (currency) DO UPDATE
+SET
+  rate = EXCLUDED.rate,
+  source = EXCLUDED.source,
+  as_of = EXCLUDED.as_of,
+  updated_at = now()
+RETURNING currency, rate, source, as_of, updated_at
+`
+
+type UpsertFxRateParams struct {
+	Currency string    `json:"currency"`
+	Rate     string    `json:"rate"`
+	Source   string    `json:"source"`
+	AsOf     time.Time `json:"as_of"`
+}
+
+func (q *Queries) UpsertFxRate(ctx context.Context, arg UpsertFxRateParams) (FxRate, error) {
+	row := q.db.QueryRowContext(ctx, upsertFxRate,
+		arg.Currency,
+		arg.Rate,
+		arg.Source,
+		arg.AsOf,
+	)
+	var i FxRate
+	err := row.Scan(
+		&i.Currency,
+		&i.Rate,
+		&i.Source,
+		&i.AsOf,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
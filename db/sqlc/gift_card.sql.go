@@ -0,0 +1,266 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: gift_card.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const adjustGiftCardBalance = `-- name: AdjustGiftCardBalance :one
+UPDATE gift_cards
+SET
+  balance = balance + $1::NUMERIC(18, 2),
+  updated_at = now()
+WHERE id = $2
+  AND status = 'ACTIVE'
+  AND balance + $1::NUMERIC(18, 2) BETWEEN 0 AND initial_amount
+RETURNING id, store_id, code, currency, initial_amount, balance, status, purchaser_id, reference, created_at, updated_at
+`
+
+type AdjustGiftCardBalanceParams struct {
+	Amount     string `json:"amount"`
+	GiftCardID int64  `json:"gift_card_id"`
+}
+
+// Adds amount, or takes it off if negative, to an ACTIVE gift card's balance.
+func (q *Queries) AdjustGiftCardBalance(ctx context.Context, arg AdjustGiftCardBalanceParams) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, adjustGiftCardBalance, arg.Amount, arg.GiftCardID)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Currency,
+		&i.InitialAmount,
+		&i.Balance,
+		&i.Status,
+		&i.PurchaserID,
+		&i.Reference,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createGiftCard = `-- name: CreateGiftCard :one
+INSERT INTO gift_cards (
+  store_id,
+  code,
+  currency,
+  initial_amount,
+  balance,
+  purchaser_id,
+  reference
+) VALUES (
+  $1, $2, $3, $4, $4,
+  $5, $6
+) RETURNING id, store_id, code, currency, initial_amount, balance, status, purchaser_id, reference, created_at, updated_at
+`
+
+type CreateGiftCardParams struct {
+	StoreID     int64  `json:"store_id"`
+	Code        string `json:"code"`
+	Currency    string `json:"currency"`
+	Amount      string `json:"amount"`
+	PurchaserID int64  `json:"purchaser_id"`
+	Reference   string `json:"reference"`
+}
+
+func (q *Queries) CreateGiftCard(ctx context.Context, arg CreateGiftCardParams) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, createGiftCard,
+		arg.StoreID,
+		arg.Code,
+		arg.Currency,
+		arg.Amount,
+		arg.PurchaserID,
+		arg.Reference,
+	)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Currency,
+		&i.InitialAmount,
+		&i.Balance,
+		&i.Status,
+		&i.PurchaserID,
+		&i.Reference,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getActiveGiftCard = `-- name: GetActiveGiftCard :one
+SELECT id, store_id, code, currency, initial_amount, balance, status, purchaser_id, reference, created_at, updated_at FROM gift_cards
+WHERE code = $1 AND status = 'ACTIVE'
+`
+
+func (q *Queries) GetActiveGiftCard(ctx context.Context, code string) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, getActiveGiftCard, code)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Currency,
+		&i.InitialAmount,
+		&i.Balance,
+		&i.Status,
+		&i.PurchaserID,
+		&i.Reference,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getActiveGiftCardForUpdate = `-- name: GetActiveGiftCardForUpdate :one
+SELECT id, store_id, code, currency, initial_amount, balance, status, purchaser_id, reference, created_at, updated_at FROM gift_cards
+WHERE code = $1 AND status = 'ACTIVE'
+FOR UPDATE
+`
+
+func (q *Queries) GetActiveGiftCardForUpdate(ctx context.Context, code string) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, getActiveGiftCardForUpdate, code)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Currency,
+		&i.InitialAmount,
+		&i.Balance,
+		&i.Status,
+		&i.PurchaserID,
+		&i.Reference,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isGiftCardPurchase = `-- name: IsGiftCardPurchase :one
+SELECT EXISTS (
+  SELECT 1 FROM gift_cards WHERE reference = $1
+) AS is_gift_card_purchase
+`
+
+func (q *Queries) IsGiftCardPurchase(ctx context.Context, reference string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isGiftCardPurchase, reference)
+	var is_gift_card_purchase bool
+	err := row.Scan(&is_gift_card_purchase)
+	return is_gift_card_purchase, err
+}
+
+const listPurchasedGiftCards = `-- name: ListPurchasedGiftCards :many
+SELECT
+  count(*) OVER() AS total_count,
+  gc.id, gc.store_id, gc.code, gc.currency, gc.initial_amount, gc.balance, gc.status, gc.purchaser_id, gc.reference, gc.created_at, gc.updated_at,
+  s.name AS store_name
+FROM gift_cards gc
+JOIN stores s ON s.id = gc.store_id
+WHERE gc.purchaser_id = $1
+ORDER BY gc.id DESC
+LIMIT $3
+OFFSET $2
+`
+
+type ListPurchasedGiftCardsParams struct {
+	PurchaserID int64 `json:"purchaser_id"`
+	RwOffset    int32 `json:"rw_offset"`
+	RwLimit     int32 `json:"rw_limit"`
+}
+
+type ListPurchasedGiftCardsRow struct {
+	TotalCount    int64     `json:"total_count"`
+	ID            int64     `json:"id"`
+	StoreID       int64     `json:"store_id"`
+	Code          string    `json:"code"`
+	Currency      string    `json:"currency"`
+	InitialAmount string    `json:"initial_amount"`
+	Balance       string    `json:"balance"`
+	Status        string    `json:"status"`
+	PurchaserID   int64     `json:"purchaser_id"`
+	Reference     string    `json:"reference"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	StoreName     string    `json:"store_name"`
+}
+
+func (q *Queries) ListPurchasedGiftCards(ctx context.Context, arg ListPurchasedGiftCardsParams) ([]ListPurchasedGiftCardsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPurchasedGiftCards, arg.PurchaserID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPurchasedGiftCardsRow{}
+	for rows.Next() {
+		var i ListPurchasedGiftCardsRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.Code,
+			&i.Currency,
+			&i.InitialAmount,
+			&i.Balance,
+			&i.Status,
+			&i.PurchaserID,
+			&i.Reference,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StoreName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateGiftCardPurchaseStatus = `-- name: UpdateGiftCardPurchaseStatus :one
+UPDATE gift_cards
+SET
+  status = $1,
+  updated_at = now()
+WHERE reference = $2 AND status = 'PENDING'
+RETURNING id, store_id, code, currency, initial_amount, balance, status, purchaser_id, reference, created_at, updated_at
+`
+
+type UpdateGiftCardPurchaseStatusParams struct {
+	Status    string `json:"status"`
+	Reference string `json:"reference"`
+}
+
+// Settles the gift card bought under reference, once its transaction
+// completes or fails.
+func (q *Queries) UpdateGiftCardPurchaseStatus(ctx context.Context, arg UpdateGiftCardPurchaseStatusParams) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, updateGiftCardPurchaseStatus, arg.Status, arg.Reference)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Code,
+		&i.Currency,
+		&i.InitialAmount,
+		&i.Balance,
+		&i.Status,
+		&i.PurchaserID,
+		&i.Reference,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,406 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: invoice.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createInvoice = `-- name: CreateInvoice :one
+INSERT INTO invoices (
+  store_id,
+  sequence_number,
+  invoice_number,
+  reference,
+  buyer_id,
+  currency,
+  subtotal,
+  discount,
+  delivery_fee,
+  tax,
+  total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, store_id, sequence_number, invoice_number, reference, buyer_id, currency, subtotal, discount, delivery_fee, tax, total, emailed_at, created_at
+`
+
+type CreateInvoiceParams struct {
+	StoreID        int64  `json:"store_id"`
+	SequenceNumber int64  `json:"sequence_number"`
+	InvoiceNumber  string `json:"invoice_number"`
+	Reference      string `json:"reference"`
+	BuyerID        int64  `json:"buyer_id"`
+	Currency       string `json:"currency"`
+	Subtotal       string `json:"subtotal"`
+	Discount       string `json:"discount"`
+	DeliveryFee    string `json:"delivery_fee"`
+	Tax            string `json:"tax"`
+	Total          string `json:"total"`
+}
+
+func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, createInvoice,
+		arg.StoreID,
+		arg.SequenceNumber,
+		arg.InvoiceNumber,
+		arg.Reference,
+		arg.BuyerID,
+		arg.Currency,
+		arg.Subtotal,
+		arg.Discount,
+		arg.DeliveryFee,
+		arg.Tax,
+		arg.Total,
+	)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.SequenceNumber,
+		&i.InvoiceNumber,
+		&i.Reference,
+		&i.BuyerID,
+		&i.Currency,
+		&i.Subtotal,
+		&i.Discount,
+		&i.DeliveryFee,
+		&i.Tax,
+		&i.Total,
+		&i.EmailedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getBuyerInvoice = `-- name: GetBuyerInvoice :one
+SELECT id, store_id, sequence_number, invoice_number, reference, buyer_id, currency, subtotal, discount, delivery_fee, tax, total, emailed_at, created_at FROM invoices
+WHERE id = $1 AND buyer_id = $2
+`
+
+type GetBuyerInvoiceParams struct {
+	InvoiceID int64 `json:"invoice_id"`
+	BuyerID   int64 `json:"buyer_id"`
+}
+
+func (q *Queries) GetBuyerInvoice(ctx context.Context, arg GetBuyerInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, getBuyerInvoice, arg.InvoiceID, arg.BuyerID)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.SequenceNumber,
+		&i.InvoiceNumber,
+		&i.Reference,
+		&i.BuyerID,
+		&i.Currency,
+		&i.Subtotal,
+		&i.Discount,
+		&i.DeliveryFee,
+		&i.Tax,
+		&i.Total,
+		&i.EmailedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStoreInvoice = `-- name: GetStoreInvoice :one
+SELECT id, store_id, sequence_number, invoice_number, reference, buyer_id, currency, subtotal, discount, delivery_fee, tax, total, emailed_at, created_at FROM invoices
+WHERE id = $1 AND store_id = $2
+`
+
+type GetStoreInvoiceParams struct {
+	InvoiceID int64 `json:"invoice_id"`
+	StoreID   int64 `json:"store_id"`
+}
+
+func (q *Queries) GetStoreInvoice(ctx context.Context, arg GetStoreInvoiceParams) (Invoice, error) {
+	row := q.db.QueryRowContext(ctx, getStoreInvoice, arg.InvoiceID, arg.StoreID)
+	var i Invoice
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.SequenceNumber,
+		&i.InvoiceNumber,
+		&i.Reference,
+		&i.BuyerID,
+		&i.Currency,
+		&i.Subtotal,
+		&i.Discount,
+		&i.DeliveryFee,
+		&i.Tax,
+		&i.Total,
+		&i.EmailedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listBuyerInvoices = `-- name: ListBuyerInvoices :many
+SELECT
+  count(*) OVER() AS total_count,
+  inv.id, inv.store_id, inv.sequence_number, inv.invoice_number, inv.reference, inv.buyer_id, inv.currency, inv.subtotal, inv.discount, inv.delivery_fee, inv.tax, inv.total, inv.emailed_at, inv.created_at,
+  s.name AS store_name
+FROM invoices inv
+JOIN stores s ON s.id = inv.store_id
+WHERE inv.buyer_id = $1
+ORDER BY inv.id DESC
+LIMIT $3
+OFFSET $2
+`
+
+type ListBuyerInvoicesParams struct {
+	BuyerID  int64 `json:"buyer_id"`
+	RwOffset int32 `json:"rw_offset"`
+	RwLimit  int32 `json:"rw_limit"`
+}
+
+type ListBuyerInvoicesRow struct {
+	TotalCount     int64     `json:"total_count"`
+	ID             int64     `json:"id"`
+	StoreID        int64     `json:"store_id"`
+	SequenceNumber int64     `json:"sequence_number"`
+	InvoiceNumber  string    `json:"invoice_number"`
+	Reference      string    `json:"reference"`
+	BuyerID        int64     `json:"buyer_id"`
+	Currency       string    `json:"currency"`
+	Subtotal       string    `json:"subtotal"`
+	Discount       string    `json:"discount"`
+	DeliveryFee    string    `json:"delivery_fee"`
+	Tax            string    `json:"tax"`
+	Total          string    `json:"total"`
+	EmailedAt      time.Time `json:"emailed_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	StoreName      string    `json:"store_name"`
+}
+
+func (q *Queries) ListBuyerInvoices(ctx context.Context, arg ListBuyerInvoicesParams) ([]ListBuyerInvoicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBuyerInvoices, arg.BuyerID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBuyerInvoicesRow{}
+	for rows.Next() {
+		var i ListBuyerInvoicesRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.SequenceNumber,
+			&i.InvoiceNumber,
+			&i.Reference,
+			&i.BuyerID,
+			&i.Currency,
+			&i.Subtotal,
+			&i.Discount,
+			&i.DeliveryFee,
+			&i.Tax,
+			&i.Total,
+			&i.EmailedAt,
+			&i.CreatedAt,
+			&i.StoreName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreInvoices = `-- name: ListStoreInvoices :many
+SELECT
+  count(*) OVER() AS total_count,
+  inv.id, inv.store_id, inv.sequence_number, inv.invoice_number, inv.reference, inv.buyer_id, inv.currency, inv.subtotal, inv.discount, inv.delivery_fee, inv.tax, inv.total, inv.emailed_at, inv.created_at
+FROM invoices inv
+WHERE inv.store_id = $1
+ORDER BY inv.sequence_number DESC
+LIMIT $3
+OFFSET $2
+`
+
+type ListStoreInvoicesParams struct {
+	StoreID  int64 `json:"store_id"`
+	RwOffset int32 `json:"rw_offset"`
+	RwLimit  int32 `json:"rw_limit"`
+}
+
+type ListStoreInvoicesRow struct {
+	TotalCount     int64     `json:"total_count"`
+	ID             int64     `json:"id"`
+	StoreID        int64     `json:"store_id"`
+	SequenceNumber int64     `json:"sequence_number"`
+	InvoiceNumber  string    `json:"invoice_number"`
+	Reference      string    `json:"reference"`
+	BuyerID        int64     `json:"buyer_id"`
+	Currency       string    `json:"currency"`
+	Subtotal       string    `json:"subtotal"`
+	Discount       string    `json:"discount"`
+	DeliveryFee    string    `json:"delivery_fee"`
+	Tax            string    `json:"tax"`
+	Total          string    `json:"total"`
+	EmailedAt      time.Time `json:"emailed_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListStoreInvoices(ctx context.Context, arg ListStoreInvoicesParams) ([]ListStoreInvoicesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreInvoices, arg.StoreID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStoreInvoicesRow{}
+	for rows.Next() {
+		var i ListStoreInvoicesRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.SequenceNumber,
+			&i.InvoiceNumber,
+			&i.Reference,
+			&i.BuyerID,
+			&i.Currency,
+			&i.Subtotal,
+			&i.Discount,
+			&i.DeliveryFee,
+			&i.Tax,
+			&i.Total,
+			&i.EmailedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreStaffEmails = `-- name: ListStoreStaffEmails :many
+SELECT u.email
+FROM store_owners so
+JOIN users u ON u.id = so.user_id
+WHERE so.store_id = $1
+  AND so.access_levels && $2::int[]
+ORDER BY so.user_id
+`
+
+type ListStoreStaffEmailsParams struct {
+	StoreID      int64   `json:"store_id"`
+	AccessLevels []int32 `json:"access_levels"`
+}
+
+// Emails of a store's staff holding any of access_levels.
+func (q *Queries) ListStoreStaffEmails(ctx context.Context, arg ListStoreStaffEmailsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreStaffEmails, arg.StoreID, pq.Array(arg.AccessLevels))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnsentInvoices = `-- name: ListUnsentInvoices :many
+SELECT id, store_id, sequence_number, invoice_number, reference, buyer_id, currency, subtotal, discount, delivery_fee, tax, total, emailed_at, created_at FROM invoices
+WHERE reference = $1
+  AND emailed_at = '0001-01-01 00:00:00Z'
+ORDER BY store_id
+`
+
+func (q *Queries) ListUnsentInvoices(ctx context.Context, reference string) ([]Invoice, error) {
+	rows, err := q.db.QueryContext(ctx, listUnsentInvoices, reference)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Invoice{}
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.SequenceNumber,
+			&i.InvoiceNumber,
+			&i.Reference,
+			&i.BuyerID,
+			&i.Currency,
+			&i.Subtotal,
+			&i.Discount,
+			&i.DeliveryFee,
+			&i.Tax,
+			&i.Total,
+			&i.EmailedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextInvoiceNumber = `-- name: NextInvoiceNumber :one
+INSERT INTO invoice_sequences (store_id, last_number)
+VALUES ($1, 1)
+ON CONFLICT (store_id) DO UPDATE
+SET last_number = invoice_sequences.last_number + 1
+RETURNING last_number
+`
+
+// Takes a store's next invoice number, holding its sequence row until the
+// transaction ends.
+func (q *Queries) NextInvoiceNumber(ctx context.Context, storeID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextInvoiceNumber, storeID)
+	var last_number int64
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
+const setInvoiceEmailed = `-- name: SetInvoiceEmailed :exec
+UPDATE invoices
+SET emailed_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) SetInvoiceEmailed(ctx context.Context, invoiceID int64) error {
+	_, err := q.db.ExecContext(ctx, setInvoiceEmailed, invoiceID)
+	return err
+}
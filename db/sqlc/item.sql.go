@@ -18,6 +18,7 @@ const checkItemStoreMatch = `-- name: CheckItemStoreMatch :one
 SELECT supply_quantity from items
 WHERE id = $1
   AND store_id = $2
+FOR UPDATE
 `
 
 type CheckItemStoreMatchParams struct {
@@ -44,10 +45,13 @@ INSERT INTO items (
   discount_percentage,
   supply_quantity,
   extra,
-  status
+  status,
+  currency,
+  weight_grams,
+  sku
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold
 `
 
 type CreateStoreItemParams struct {
@@ -62,6 +66,9 @@ type CreateStoreItemParams struct {
 	SupplyQuantity     int64           `json:"supply_quantity"`
 	Extra              json.RawMessage `json:"extra"`
 	Status             string          `json:"status"`
+	Currency           string          `json:"currency"`
+	WeightGrams        int64           `json:"weight_grams"`
+	Sku                string          `json:"sku"`
 }
 
 func (q *Queries) CreateStoreItem(ctx context.Context, arg CreateStoreItemParams) (Item, error) {
@@ -77,6 +84,9 @@ func (q *Queries) CreateStoreItem(ctx context.Context, arg CreateStoreItemParams
 		arg.SupplyQuantity,
 		arg.Extra,
 		arg.Status,
+		arg.Currency,
+		arg.WeightGrams,
+		arg.Sku,
 	)
 	var i Item
 	err := row.Scan(
@@ -96,16 +106,20 @@ func (q *Queries) CreateStoreItem(ctx context.Context, arg CreateStoreItemParams
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
 	)
 	return i, err
 }
 
-const deductItemSupply = `-- name: DeductItemSupply :exec
+const deductItemSupply = `-- name: DeductItemSupply :one
 UPDATE items 
 SET 
   supply_quantity = supply_quantity - $1
 WHERE
   id = $2 AND supply_quantity >= $1
+RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold
 `
 
 type DeductItemSupplyParams struct {
@@ -113,9 +127,31 @@ type DeductItemSupplyParams struct {
 	ItemID        int64 `json:"item_id"`
 }
 
-func (q *Queries) DeductItemSupply(ctx context.Context, arg DeductItemSupplyParams) error {
-	_, err := q.db.ExecContext(ctx, deductItemSupply, arg.OrderQuantity, arg.ItemID)
-	return err
+func (q *Queries) DeductItemSupply(ctx context.Context, arg DeductItemSupplyParams) (Item, error) {
+	row := q.db.QueryRowContext(ctx, deductItemSupply, arg.OrderQuantity, arg.ItemID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StoreID,
+		pq.Array(&i.ImageUrls),
+		&i.Category,
+		&i.DiscountPercentage,
+		&i.SupplyQuantity,
+		&i.Extra,
+		&i.IsFrozen,
+		&i.Currency,
+		&i.CoverImgUrl,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
+	)
+	return i, err
 }
 
 const deleteItem = `-- name: DeleteItem :exec
@@ -134,7 +170,7 @@ func (q *Queries) DeleteItem(ctx context.Context, arg DeleteItemParams) error {
 }
 
 const getItem = `-- name: GetItem :one
-SELECT id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at FROM items
+SELECT id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold FROM items
 WHERE id = $1 AND supply_quantity > 0
 `
 
@@ -158,6 +194,189 @@ func (q *Queries) GetItem(ctx context.Context, itemID int64) (Item, error) {
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
+	)
+	return i, err
+}
+
+const getStoreItemBySKUForUpdate = `-- name: GetStoreItemBySKUForUpdate :one
+SELECT id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold FROM items
+WHERE store_id = $1 AND sku = $2 AND sku <> ''
+FOR UPDATE
+`
+
+type GetStoreItemBySKUForUpdateParams struct {
+	StoreID int64  `json:"store_id"`
+	Sku     string `json:"sku"`
+}
+
+func (q *Queries) GetStoreItemBySKUForUpdate(ctx context.Context, arg GetStoreItemBySKUForUpdateParams) (Item, error) {
+	row := q.db.QueryRowContext(ctx, getStoreItemBySKUForUpdate, arg.StoreID, arg.Sku)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StoreID,
+		pq.Array(&i.ImageUrls),
+		&i.Category,
+		&i.DiscountPercentage,
+		&i.SupplyQuantity,
+		&i.Extra,
+		&i.IsFrozen,
+		&i.Currency,
+		&i.CoverImgUrl,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
+	)
+	return i, err
+}
+
+const listStoreItemsAfter = `-- name: ListStoreItemsAfter :many
+SELECT id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold FROM items
+WHERE store_id = $1 AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListStoreItemsAfterParams struct {
+	StoreID int64 `json:"store_id"`
+	AfterID int64 `json:"after_id"`
+	RwLimit int32 `json:"rw_limit"`
+}
+
+// Lists a page of a store's items in id order, after the item after_id.
+func (q *Queries) ListStoreItemsAfter(ctx context.Context, arg ListStoreItemsAfterParams) ([]Item, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreItemsAfter, arg.StoreID, arg.AfterID, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Item{}
+	for rows.Next() {
+		var i Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.StoreID,
+			pq.Array(&i.ImageUrls),
+			&i.Category,
+			&i.DiscountPercentage,
+			&i.SupplyQuantity,
+			&i.Extra,
+			&i.IsFrozen,
+			&i.Currency,
+			&i.CoverImgUrl,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.Sku,
+			&i.ReorderThreshold,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setItemReorderThreshold = `-- name: SetItemReorderThreshold :one
+UPDATE items
+SET
+  reorder_threshold = $1,
+  updated_at = now()
+WHERE id = $2 AND store_id = $3
+RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold
+`
+
+type SetItemReorderThresholdParams struct {
+	ReorderThreshold sql.NullInt32 `json:"reorder_threshold"`
+	ItemID           int64         `json:"item_id"`
+	StoreID          int64         `json:"store_id"`
+}
+
+// Sets the threshold an item runs low at; NULL leaves it to its store's.
+func (q *Queries) SetItemReorderThreshold(ctx context.Context, arg SetItemReorderThresholdParams) (Item, error) {
+	row := q.db.QueryRowContext(ctx, setItemReorderThreshold, arg.ReorderThreshold, arg.ItemID, arg.StoreID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StoreID,
+		pq.Array(&i.ImageUrls),
+		&i.Category,
+		&i.DiscountPercentage,
+		&i.SupplyQuantity,
+		&i.Extra,
+		&i.IsFrozen,
+		&i.Currency,
+		&i.CoverImgUrl,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
+	)
+	return i, err
+}
+
+const setItemSupply = `-- name: SetItemSupply :one
+UPDATE items
+SET
+  supply_quantity = $1,
+  updated_at = now()
+WHERE id = $2 AND store_id = $3
+RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold
+`
+
+type SetItemSupplyParams struct {
+	SupplyQuantity int64 `json:"supply_quantity"`
+	ItemID         int64 `json:"item_id"`
+	StoreID        int64 `json:"store_id"`
+}
+
+func (q *Queries) SetItemSupply(ctx context.Context, arg SetItemSupplyParams) (Item, error) {
+	row := q.db.QueryRowContext(ctx, setItemSupply, arg.SupplyQuantity, arg.ItemID, arg.StoreID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StoreID,
+		pq.Array(&i.ImageUrls),
+		&i.Category,
+		&i.DiscountPercentage,
+		&i.SupplyQuantity,
+		&i.Extra,
+		&i.IsFrozen,
+		&i.Currency,
+		&i.CoverImgUrl,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
 	)
 	return i, err
 }
@@ -176,10 +395,13 @@ SET
   extra = COALESCE($9, extra),
   is_frozen = COALESCE($10, is_frozen),
   status = COALESCE($11, status),
-  updated_at = COALESCE($12, updated_at)
+  weight_grams = COALESCE($12, weight_grams),
+  sku = COALESCE($13, sku),
+  currency = COALESCE($14, currency),
+  updated_at = COALESCE($15, updated_at)
 WHERE
-  id = $13
-RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at
+  id = $16
+RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold
 `
 
 type UpdateItemParams struct {
@@ -194,6 +416,9 @@ type UpdateItemParams struct {
 	Extra              pqtype.NullRawMessage `json:"extra"`
 	IsFrozen           sql.NullBool          `json:"is_frozen"`
 	Status             sql.NullString        `json:"status"`
+	WeightGrams        sql.NullInt64         `json:"weight_grams"`
+	Sku                sql.NullString        `json:"sku"`
+	Currency           sql.NullString        `json:"currency"`
 	UpdatedAt          sql.NullTime          `json:"updated_at"`
 	ItemID             int64                 `json:"item_id"`
 }
@@ -211,6 +436,9 @@ func (q *Queries) UpdateItem(ctx context.Context, arg UpdateItemParams) (Item, e
 		arg.Extra,
 		arg.IsFrozen,
 		arg.Status,
+		arg.WeightGrams,
+		arg.Sku,
+		arg.Currency,
 		arg.UpdatedAt,
 		arg.ItemID,
 	)
@@ -232,6 +460,9 @@ func (q *Queries) UpdateItem(ctx context.Context, arg UpdateItemParams) (Item, e
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
 	)
 	return i, err
 }
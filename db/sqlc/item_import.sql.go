@@ -0,0 +1,339 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: item_import.sql
+
+package db
+
+import (
+	"context"
+)
+
+const completeItemImport = `-- name: CompleteItemImport :one
+UPDATE item_imports
+SET
+  status = 'COMPLETED',
+  created_rows = $1,
+  updated_rows = $2,
+  failed_rows = $3,
+  completed_at = now()
+WHERE id = $4
+RETURNING id, store_id, uploaded_by, filename, status, error, total_rows, created_rows, updated_rows, failed_rows, created_at, started_at, completed_at
+`
+
+type CompleteItemImportParams struct {
+	CreatedRows int32 `json:"created_rows"`
+	UpdatedRows int32 `json:"updated_rows"`
+	FailedRows  int32 `json:"failed_rows"`
+	ImportID    int64 `json:"import_id"`
+}
+
+func (q *Queries) CompleteItemImport(ctx context.Context, arg CompleteItemImportParams) (ItemImport, error) {
+	row := q.db.QueryRowContext(ctx, completeItemImport,
+		arg.CreatedRows,
+		arg.UpdatedRows,
+		arg.FailedRows,
+		arg.ImportID,
+	)
+	var i ItemImport
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.Status,
+		&i.Error,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createItemImport = `-- name: CreateItemImport :one
+INSERT INTO item_imports (
+  store_id,
+  uploaded_by,
+  filename,
+  total_rows
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, store_id, uploaded_by, filename, status, error, total_rows, created_rows, updated_rows, failed_rows, created_at, started_at, completed_at
+`
+
+type CreateItemImportParams struct {
+	StoreID    int64  `json:"store_id"`
+	UploadedBy int64  `json:"uploaded_by"`
+	Filename   string `json:"filename"`
+	TotalRows  int32  `json:"total_rows"`
+}
+
+func (q *Queries) CreateItemImport(ctx context.Context, arg CreateItemImportParams) (ItemImport, error) {
+	row := q.db.QueryRowContext(ctx, createItemImport,
+		arg.StoreID,
+		arg.UploadedBy,
+		arg.Filename,
+		arg.TotalRows,
+	)
+	var i ItemImport
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.Status,
+		&i.Error,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createItemImportError = `-- name: CreateItemImportError :exec
+INSERT INTO item_import_errors (
+  import_id,
+  line,
+  sku,
+  message
+) VALUES (
+  $1, $2, $3, $4
+)
+`
+
+type CreateItemImportErrorParams struct {
+	ImportID int64  `json:"import_id"`
+	Line     int32  `json:"line"`
+	Sku      string `json:"sku"`
+	Message  string `json:"message"`
+}
+
+func (q *Queries) CreateItemImportError(ctx context.Context, arg CreateItemImportErrorParams) error {
+	_, err := q.db.ExecContext(ctx, createItemImportError,
+		arg.ImportID,
+		arg.Line,
+		arg.Sku,
+		arg.Message,
+	)
+	return err
+}
+
+const createItemImportFile = `-- name: CreateItemImportFile :exec
+INSERT INTO item_import_files (
+  import_id,
+  content
+) VALUES (
+  $1, $2
+)
+`
+
+type CreateItemImportFileParams struct {
+	ImportID int64  `json:"import_id"`
+	Content  []byte `json:"content"`
+}
+
+func (q *Queries) CreateItemImportFile(ctx context.Context, arg CreateItemImportFileParams) error {
+	_, err := q.db.ExecContext(ctx, createItemImportFile, arg.ImportID, arg.Content)
+	return err
+}
+
+const deleteItemImportErrors = `-- name: DeleteItemImportErrors :exec
+DELETE FROM item_import_errors
+WHERE import_id = $1
+`
+
+func (q *Queries) DeleteItemImportErrors(ctx context.Context, importID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteItemImportErrors, importID)
+	return err
+}
+
+const deleteItemImportFile = `-- name: DeleteItemImportFile :exec
+DELETE FROM item_import_files
+WHERE import_id = $1
+`
+
+func (q *Queries) DeleteItemImportFile(ctx context.Context, importID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteItemImportFile, importID)
+	return err
+}
+
+const failItemImport = `-- name: FailItemImport :one
+UPDATE item_imports
+SET
+  status = 'FAILED',
+  error = $1,
+  completed_at = now()
+WHERE id = $2
+RETURNING id, store_id, uploaded_by, filename, status, error, total_rows, created_rows, updated_rows, failed_rows, created_at, started_at, completed_at
+`
+
+type FailItemImportParams struct {
+	Error    string `json:"error"`
+	ImportID int64  `json:"import_id"`
+}
+
+func (q *Queries) FailItemImport(ctx context.Context, arg FailItemImportParams) (ItemImport, error) {
+	row := q.db.QueryRowContext(ctx, failItemImport, arg.Error, arg.ImportID)
+	var i ItemImport
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.Status,
+		&i.Error,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getItemImport = `-- name: GetItemImport :one
+SELECT id, store_id, uploaded_by, filename, status, error, total_rows, created_rows, updated_rows, failed_rows, created_at, started_at, completed_at FROM item_imports
+WHERE id = $1 AND store_id = $2
+`
+
+type GetItemImportParams struct {
+	ImportID int64 `json:"import_id"`
+	StoreID  int64 `json:"store_id"`
+}
+
+func (q *Queries) GetItemImport(ctx context.Context, arg GetItemImportParams) (ItemImport, error) {
+	row := q.db.QueryRowContext(ctx, getItemImport, arg.ImportID, arg.StoreID)
+	var i ItemImport
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.Status,
+		&i.Error,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getItemImportByID = `-- name: GetItemImportByID :one
+SELECT id, store_id, uploaded_by, filename, status, error, total_rows, created_rows, updated_rows, failed_rows, created_at, started_at, completed_at FROM item_imports
+WHERE id = $1
+`
+
+func (q *Queries) GetItemImportByID(ctx context.Context, importID int64) (ItemImport, error) {
+	row := q.db.QueryRowContext(ctx, getItemImportByID, importID)
+	var i ItemImport
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.Status,
+		&i.Error,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getItemImportFile = `-- name: GetItemImportFile :one
+SELECT content FROM item_import_files
+WHERE import_id = $1
+`
+
+func (q *Queries) GetItemImportFile(ctx context.Context, importID int64) ([]byte, error) {
+	row := q.db.QueryRowContext(ctx, getItemImportFile, importID)
+	var content []byte
+	err := row.Scan(&content)
+	return content, err
+}
+
+const listItemImportErrors = `-- name: ListItemImportErrors :many
+SELECT id, import_id, line, sku, message FROM item_import_errors
+WHERE import_id = $1
+ORDER BY line, id
+`
+
+func (q *Queries) ListItemImportErrors(ctx context.Context, importID int64) ([]ItemImportError, error) {
+	rows, err := q.db.QueryContext(ctx, listItemImportErrors, importID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ItemImportError{}
+	for rows.Next() {
+		var i ItemImportError
+		if err := rows.Scan(
+			&i.ID,
+			&i.ImportID,
+			&i.Line,
+			&i.Sku,
+			&i.Message,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startItemImport = `-- name: StartItemImport :one
+UPDATE item_imports
+SET
+  status = 'PROCESSING',
+  started_at = now()
+WHERE id = $1
+  AND status IN ('PENDING', 'PROCESSING')
+RETURNING id, store_id, uploaded_by, filename, status, error, total_rows, created_rows, updated_rows, failed_rows, created_at, started_at, completed_at
+`
+
+// Marks an import PROCESSING, unless it's already COMPLETED or FAILED. A
+// PROCESSING import is started again when its task is retried.
+func (q *Queries) StartItemImport(ctx context.Context, importID int64) (ItemImport, error) {
+	row := q.db.QueryRowContext(ctx, startItemImport, importID)
+	var i ItemImport
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.UploadedBy,
+		&i.Filename,
+		&i.Status,
+		&i.Error,
+		&i.TotalRows,
+		&i.CreatedRows,
+		&i.UpdatedRows,
+		&i.FailedRows,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
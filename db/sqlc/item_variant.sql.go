@@ -0,0 +1,505 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: item_variant.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/lib/pq"
+)
+
+const countItemVariants = `-- name: CountItemVariants :one
+SELECT count(*) FROM item_variants
+WHERE item_id = $1
+`
+
+func (q *Queries) CountItemVariants(ctx context.Context, itemID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countItemVariants, itemID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createItemOption = `-- name: CreateItemOption :one
+INSERT INTO item_options (
+  item_id,
+  name,
+  "values",
+  position
+) VALUES (
+  $1, $2, $3::text[], $4
+) RETURNING id, item_id, name, values, position, created_at
+`
+
+type CreateItemOptionParams struct {
+	ItemID       int64    `json:"item_id"`
+	Name         string   `json:"name"`
+	OptionValues []string `json:"option_values"`
+	Position     int32    `json:"position"`
+}
+
+func (q *Queries) CreateItemOption(ctx context.Context, arg CreateItemOptionParams) (ItemOption, error) {
+	row := q.db.QueryRowContext(ctx, createItemOption,
+		arg.ItemID,
+		arg.Name,
+		pq.Array(arg.OptionValues),
+		arg.Position,
+	)
+	var i ItemOption
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.Name,
+		pq.Array(&i.Values),
+		&i.Position,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteItemOptions = `-- name: DeleteItemOptions :exec
+DELETE FROM item_options
+WHERE item_id = $1
+`
+
+func (q *Queries) DeleteItemOptions(ctx context.Context, itemID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteItemOptions, itemID)
+	return err
+}
+
+const deleteItemVariantsExcept = `-- name: DeleteItemVariantsExcept :exec
+DELETE FROM item_variants
+WHERE item_id = $1
+  AND NOT (sku = ANY($2::text[]))
+`
+
+type DeleteItemVariantsExceptParams struct {
+	ItemID int64    `json:"item_id"`
+	Skus   []string `json:"skus"`
+}
+
+func (q *Queries) DeleteItemVariantsExcept(ctx context.Context, arg DeleteItemVariantsExceptParams) error {
+	_, err := q.db.ExecContext(ctx, deleteItemVariantsExcept, arg.ItemID, pq.Array(arg.Skus))
+	return err
+}
+
+const getItemVariant = `-- name: GetItemVariant :one
+SELECT id, item_id, store_id, sku, options, price, supply_quantity, image_urls, created_at, updated_at FROM item_variants
+WHERE id = $1 AND item_id = $2
+`
+
+type GetItemVariantParams struct {
+	VariantID int64 `json:"variant_id"`
+	ItemID    int64 `json:"item_id"`
+}
+
+func (q *Queries) GetItemVariant(ctx context.Context, arg GetItemVariantParams) (ItemVariant, error) {
+	row := q.db.QueryRowContext(ctx, getItemVariant, arg.VariantID, arg.ItemID)
+	var i ItemVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.StoreID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.SupplyQuantity,
+		pq.Array(&i.ImageUrls),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getItemVariantForUpdate = `-- name: GetItemVariantForUpdate :one
+SELECT id, item_id, store_id, sku, options, price, supply_quantity, image_urls, created_at, updated_at FROM item_variants
+WHERE id = $1 AND item_id = $2
+FOR UPDATE
+`
+
+type GetItemVariantForUpdateParams struct {
+	VariantID int64 `json:"variant_id"`
+	ItemID    int64 `json:"item_id"`
+}
+
+func (q *Queries) GetItemVariantForUpdate(ctx context.Context, arg GetItemVariantForUpdateParams) (ItemVariant, error) {
+	row := q.db.QueryRowContext(ctx, getItemVariantForUpdate, arg.VariantID, arg.ItemID)
+	var i ItemVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.StoreID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.SupplyQuantity,
+		pq.Array(&i.ImageUrls),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getReservedVariantStock = `-- name: GetReservedVariantStock :one
+SELECT COALESCE(sum(quantity), 0)::bigint AS reserved
+FROM stock_reservations
+WHERE variant_id = $1
+  AND reference <> $2
+  AND status = 'RESERVED'
+  AND expires_at > now()
+`
+
+type GetReservedVariantStockParams struct {
+	VariantID sql.NullInt64 `json:"variant_id"`
+	Reference string        `json:"reference"`
+}
+
+func (q *Queries) GetReservedVariantStock(ctx context.Context, arg GetReservedVariantStockParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getReservedVariantStock, arg.VariantID, arg.Reference)
+	var reserved int64
+	err := row.Scan(&reserved)
+	return reserved, err
+}
+
+const listItemOptions = `-- name: ListItemOptions :many
+SELECT id, item_id, name, values, position, created_at FROM item_options
+WHERE item_id = $1
+ORDER BY position, id
+`
+
+func (q *Queries) ListItemOptions(ctx context.Context, itemID int64) ([]ItemOption, error) {
+	rows, err := q.db.QueryContext(ctx, listItemOptions, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ItemOption{}
+	for rows.Next() {
+		var i ItemOption
+		if err := rows.Scan(
+			&i.ID,
+			&i.ItemID,
+			&i.Name,
+			pq.Array(&i.Values),
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemVariants = `-- name: ListItemVariants :many
+SELECT id, item_id, store_id, sku, options, price, supply_quantity, image_urls, created_at, updated_at FROM item_variants
+WHERE item_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListItemVariants(ctx context.Context, itemID int64) ([]ItemVariant, error) {
+	rows, err := q.db.QueryContext(ctx, listItemVariants, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ItemVariant{}
+	for rows.Next() {
+		var i ItemVariant
+		if err := rows.Scan(
+			&i.ID,
+			&i.ItemID,
+			&i.StoreID,
+			&i.Sku,
+			&i.Options,
+			&i.Price,
+			&i.SupplyQuantity,
+			pq.Array(&i.ImageUrls),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReservedVariantStock = `-- name: ListReservedVariantStock :many
+SELECT
+  variant_id::bigint AS variant_id,
+  COALESCE(sum(quantity), 0)::bigint AS reserved
+FROM stock_reservations
+WHERE item_id = $1
+  AND variant_id IS NOT NULL
+  AND status = 'RESERVED'
+  AND expires_at > now()
+GROUP BY variant_id
+`
+
+type ListReservedVariantStockRow struct {
+	VariantID int64 `json:"variant_id"`
+	Reserved  int64 `json:"reserved"`
+}
+
+// The stock of each variant of an item held for checkouts.
+func (q *Queries) ListReservedVariantStock(ctx context.Context, itemID int64) ([]ListReservedVariantStockRow, error) {
+	rows, err := q.db.QueryContext(ctx, listReservedVariantStock, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListReservedVariantStockRow{}
+	for rows.Next() {
+		var i ListReservedVariantStockRow
+		if err := rows.Scan(&i.VariantID, &i.Reserved); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restockItemVariant = `-- name: RestockItemVariant :exec
+UPDATE item_variants
+SET
+  supply_quantity = supply_quantity + $1::bigint,
+  updated_at = now()
+WHERE id = $2
+`
+
+type RestockItemVariantParams struct {
+	Quantity  int64 `json:"quantity"`
+	VariantID int64 `json:"variant_id"`
+}
+
+func (q *Queries) RestockItemVariant(ctx context.Context, arg RestockItemVariantParams) error {
+	_, err := q.db.ExecContext(ctx, restockItemVariant, arg.Quantity, arg.VariantID)
+	return err
+}
+
+const setItemVariantSupply = `-- name: SetItemVariantSupply :one
+UPDATE item_variants
+SET
+  supply_quantity = $1,
+  updated_at = now()
+WHERE id = $2
+  AND item_id = $3
+  AND store_id = $4
+RETURNING id, item_id, store_id, sku, options, price, supply_quantity, image_urls, created_at, updated_at
+`
+
+type SetItemVariantSupplyParams struct {
+	SupplyQuantity int64 `json:"supply_quantity"`
+	VariantID      int64 `json:"variant_id"`
+	ItemID         int64 `json:"item_id"`
+	StoreID        int64 `json:"store_id"`
+}
+
+func (q *Queries) SetItemVariantSupply(ctx context.Context, arg SetItemVariantSupplyParams) (ItemVariant, error) {
+	row := q.db.QueryRowContext(ctx, setItemVariantSupply,
+		arg.SupplyQuantity,
+		arg.VariantID,
+		arg.ItemID,
+		arg.StoreID,
+	)
+	var i ItemVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.StoreID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.SupplyQuantity,
+		pq.Array(&i.ImageUrls),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setOrderVariant = `-- name: SetOrderVariant :one
+UPDATE orders
+SET
+  variant_id = $1,
+  variant_sku = $2
+WHERE id = $3
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
+`
+
+type SetOrderVariantParams struct {
+	VariantID  sql.NullInt64 `json:"variant_id"`
+	VariantSku string        `json:"variant_sku"`
+	OrderID    int64         `json:"order_id"`
+}
+
+func (q *Queries) SetOrderVariant(ctx context.Context, arg SetOrderVariantParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderVariant, arg.VariantID, arg.VariantSku, arg.OrderID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryStatus,
+		&i.DeliveredOn,
+		&i.ExpectedDeliveryDate,
+		&i.ItemID,
+		&i.ItemPrice,
+		&i.ItemCurrency,
+		&i.OrderQuantity,
+		&i.BuyerID,
+		&i.SellerID,
+		&i.StoreID,
+		&i.DeliveryFee,
+		&i.PaymentChannel,
+		&i.PaymentMethod,
+		&i.IsReviewed,
+		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
+	)
+	return i, err
+}
+
+const sumItemVariantsSupply = `-- name: SumItemVariantsSupply :one
+UPDATE items
+SET
+  supply_quantity = (
+    SELECT COALESCE(sum(v.supply_quantity), 0)
+    FROM item_variants v
+    WHERE v.item_id = items.id
+  ),
+  updated_at = now()
+WHERE items.id = $1
+RETURNING id, name, description, price, store_id, image_urls, category, discount_percentage, supply_quantity, extra, is_frozen, currency, cover_img_url, status, created_at, updated_at, weight_grams, sku, reorder_threshold
+`
+
+// Sets an item's supply to the sum of its variants'.
+func (q *Queries) SumItemVariantsSupply(ctx context.Context, itemID int64) (Item, error) {
+	row := q.db.QueryRowContext(ctx, sumItemVariantsSupply, itemID)
+	var i Item
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StoreID,
+		pq.Array(&i.ImageUrls),
+		&i.Category,
+		&i.DiscountPercentage,
+		&i.SupplyQuantity,
+		&i.Extra,
+		&i.IsFrozen,
+		&i.Currency,
+		&i.CoverImgUrl,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.Sku,
+		&i.ReorderThreshold,
+	)
+	return i, err
+}
+
+const upsertItemVariant = `-- name: UpsertItemVariant :one
+INSERT INTO item_variants (
+  item_id,
+  store_id,
+  sku,
+  options,
+  price,
+  supply_quantity,
+  image_urls
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7::text[]
+)
+ON CONFLICT (store_id, sku) DO UPDATE
+SET
+  options = EXCLUDED.options,
+  price = EXCLUDED.price,
+  supply_quantity = EXCLUDED.supply_quantity,
+  image_urls = EXCLUDED.image_urls,
+  updated_at = now()
+WHERE item_variants.item_id = EXCLUDED.item_id
+RETURNING id, item_id, store_id, sku, options, price, supply_quantity, image_urls, created_at, updated_at
+`
+
+type UpsertItemVariantParams struct {
+	ItemID         int64           `json:"item_id"`
+	StoreID        int64           `json:"store_id"`
+	Sku            string          `json:"sku"`
+	Options        json.RawMessage `json:"options"`
+	Price          sql.NullString  `json:"price"`
+	SupplyQuantity int64           `json:"supply_quantity"`
+	ImageUrls      []string        `json:"image_urls"`
+}
+
+// Creates a variant of an item, or updates the item's variant with its SKU.
+// Returns no row if another item of the store has the SKU.
+func (q *Queries) UpsertItemVariant(ctx context.Context, arg UpsertItemVariantParams) (ItemVariant, error) {
+	row := q.db.QueryRowContext(ctx, upsertItemVariant,
+		arg.ItemID,
+		arg.StoreID,
+		arg.Sku,
+		arg.Options,
+		arg.Price,
+		arg.SupplyQuantity,
+		pq.Array(arg.ImageUrls),
+	)
+	var i ItemVariant
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.StoreID,
+		&i.Sku,
+		&i.Options,
+		&i.Price,
+		&i.SupplyQuantity,
+		pq.Array(&i.ImageUrls),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,294 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: ledger.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const createJournalEntry = `-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (
+  kind,
+  reference,
+  description
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, kind, reference, description, created_at
+`
+
+type CreateJournalEntryParams struct {
+	Kind        string `json:"kind"`
+	Reference   string `json:"reference"`
+	Description string `json:"description"`
+}
+
+func (q *Queries) CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error) {
+	row := q.db.QueryRowContext(ctx, createJournalEntry, arg.Kind, arg.Reference, arg.Description)
+	var i JournalEntry
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Reference,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createLedgerPosting = `-- name: CreateLedgerPosting :exec
+INSERT INTO ledger_postings (
+  journal_entry_id,
+  ledger_account_id,
+  amount
+) VALUES (
+  $1, $2, $3
+)
+`
+
+type CreateLedgerPostingParams struct {
+	JournalEntryID  int64  `json:"journal_entry_id"`
+	LedgerAccountID int64  `json:"ledger_account_id"`
+	Amount          string `json:"amount"`
+}
+
+func (q *Queries) CreateLedgerPosting(ctx context.Context, arg CreateLedgerPostingParams) error {
+	_, err := q.db.ExecContext(ctx, createLedgerPosting, arg.JournalEntryID, arg.LedgerAccountID, arg.Amount)
+	return err
+}
+
+const ensureLedgerAccount = `-- name: EnsureLedgerAccount :one
+SELECT ensure_ledger_account(
+  $1::varchar,
+  $2::varchar,
+  $3::bigint
+)::bigint AS id
+`
+
+type EnsureLedgerAccountParams struct {
+	Code    string        `json:"code"`
+	Type    string        `json:"type"`
+	StoreID sql.NullInt64 `json:"store_id"`
+}
+
+func (q *Queries) EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, ensureLedgerAccount, arg.Code, arg.Type, arg.StoreID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getTrialBalance = `-- name: GetTrialBalance :many
+SELECT
+  la.code,
+  la.type,
+  COALESCE(SUM(lp.amount), 0)::NUMERIC(18, 2) AS total
+FROM ledger_accounts la
+LEFT JOIN ledger_postings lp ON lp.ledger_account_id = la.id
+GROUP BY la.id
+ORDER BY la.code
+`
+
+type GetTrialBalanceRow struct {
+	Code  string `json:"code"`
+	Type  string `json:"type"`
+	Total string `json:"total"`
+}
+
+func (q *Queries) GetTrialBalance(ctx context.Context) ([]GetTrialBalanceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrialBalance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTrialBalanceRow{}
+	for rows.Next() {
+		var i GetTrialBalanceRow
+		if err := rows.Scan(&i.Code, &i.Type, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreLedgerBalances = `-- name: ListStoreLedgerBalances :many
+SELECT
+  la.code,
+  la.type,
+  COALESCE(SUM(lp.amount), 0)::NUMERIC(18, 2) AS total
+FROM ledger_accounts la
+LEFT JOIN ledger_postings lp ON lp.ledger_account_id = la.id
+WHERE la.store_id = $1::bigint
+GROUP BY la.id
+ORDER BY la.code
+`
+
+type ListStoreLedgerBalancesRow struct {
+	Code  string `json:"code"`
+	Type  string `json:"type"`
+	Total string `json:"total"`
+}
+
+func (q *Queries) ListStoreLedgerBalances(ctx context.Context, storeID int64) ([]ListStoreLedgerBalancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreLedgerBalances, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStoreLedgerBalancesRow{}
+	for rows.Next() {
+		var i ListStoreLedgerBalancesRow
+		if err := rows.Scan(&i.Code, &i.Type, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreLedgerEntries = `-- name: ListStoreLedgerEntries :many
+SELECT
+  count(*) OVER() AS total_count,
+  je.id, je.kind, je.reference, je.description, je.created_at,
+  (
+    SELECT jsonb_agg(jsonb_build_object(
+      'account', la.code,
+      'amount', lp.amount
+    ) ORDER BY lp.id)
+    FROM ledger_postings lp
+    JOIN ledger_accounts la ON la.id = lp.ledger_account_id
+    WHERE lp.journal_entry_id = je.id
+      AND la.store_id = $1::bigint
+  )::jsonb AS postings
+FROM journal_entries je
+WHERE EXISTS (
+  SELECT 1
+  FROM ledger_postings lp
+  JOIN ledger_accounts la ON la.id = lp.ledger_account_id
+  WHERE lp.journal_entry_id = je.id
+    AND la.store_id = $1::bigint
+)
+AND ($2::varchar IS NULL OR je.kind = $2)
+ORDER BY je.id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListStoreLedgerEntriesParams struct {
+	StoreID  int64          `json:"store_id"`
+	Kind     sql.NullString `json:"kind"`
+	RwOffset int32          `json:"rw_offset"`
+	RwLimit  int32          `json:"rw_limit"`
+}
+
+type ListStoreLedgerEntriesRow struct {
+	TotalCount  int64           `json:"total_count"`
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Reference   string          `json:"reference"`
+	Description string          `json:"description"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Postings    json.RawMessage `json:"postings"`
+}
+
+func (q *Queries) ListStoreLedgerEntries(ctx context.Context, arg ListStoreLedgerEntriesParams) ([]ListStoreLedgerEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreLedgerEntries,
+		arg.StoreID,
+		arg.Kind,
+		arg.RwOffset,
+		arg.RwLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStoreLedgerEntriesRow{}
+	for rows.Next() {
+		var i ListStoreLedgerEntriesRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.Kind,
+			&i.Reference,
+			&i.Description,
+			&i.CreatedAt,
+			&i.Postings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnbalancedJournalEntries = `-- name: ListUnbalancedJournalEntries :many
+SELECT
+  je.id,
+  je.kind,
+  je.reference,
+  SUM(lp.amount)::NUMERIC(18, 2) AS total
+FROM journal_entries je
+JOIN ledger_postings lp ON lp.journal_entry_id = je.id
+GROUP BY je.id
+HAVING SUM(lp.amount) <> 0
+`
+
+type ListUnbalancedJournalEntriesRow struct {
+	ID        int64  `json:"id"`
+	Kind      string `json:"kind"`
+	Reference string `json:"reference"`
+	Total     string `json:"total"`
+}
+
+func (q *Queries) ListUnbalancedJournalEntries(ctx context.Context) ([]ListUnbalancedJournalEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnbalancedJournalEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnbalancedJournalEntriesRow{}
+	for rows.Next() {
+		var i ListUnbalancedJournalEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.Reference,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
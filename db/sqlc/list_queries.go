@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -116,8 +117,14 @@ func (q *SQLTx) ListStoreItems(ctx context.Context, arg ListStoreItemsParams) ([
 		statusWhereClause = "AND status = 'VISIBLE'"
 	}
 	stmt := fmt.Sprintf(`
-		SELECT count(*) OVER() AS total_count, items.*
+		SELECT count(*) OVER() AS total_count, items.*, COALESCE(r.reserved, 0)
 		FROM items
+		LEFT JOIN (
+			SELECT item_id, sum(quantity) AS reserved
+			FROM stock_reservations
+			WHERE status = 'RESERVED' AND expires_at > now()
+			GROUP BY item_id
+		) r ON r.item_id = items.id
 		WHERE (name ILIKE '%%' || $1 || '%%' OR $1 = '') AND store_id = $4 %s
 		ORDER BY %s %s, id ASC
 		LIMIT $2 OFFSET $3`, statusWhereClause, arg.Filters.SortColumn(), arg.Filters.SortDirection(),
@@ -135,6 +142,7 @@ func (q *SQLTx) ListStoreItems(ctx context.Context, arg ListStoreItemsParams) ([
 
 	for rows.Next() {
 		var i Item
+		var reserved int64
 		if err := rows.Scan(
 			&totalRecords,
 			&i.ID,
@@ -153,9 +161,17 @@ func (q *SQLTx) ListStoreItems(ctx context.Context, arg ListStoreItemsParams) ([
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.Sku,
+			&i.ReorderThreshold,
+			&reserved,
 		); err != nil {
 			return nil, pagination.Metadata{}, err
 		}
+		if arg.IsStorefront {
+			// Buyers see the stock not held for checkouts
+			i.SupplyQuantity -= reserved
+		}
 		items = append(items, i)
 	}
 
@@ -179,20 +195,33 @@ type ListSellerOrdersParams struct {
 	Filters        pagination.Filters
 }
 
-type SellerOrder struct {
-	OrderID         int64     `json:"order_id"`
-	DeliveryStatus  string    `json:"delivery_status"`
-	PaymentChannel  string    `json:"payment_channel"`
-	CreatedAt       time.Time `json:"created_at"`
-	ItemName        string    `json:"item_name"`
-	ItemPrice       string    `json:"item_price"`
-	ItemCoverImgUrl string    `json:"item_cover_img_url"`
-	BuyerFirstName  string    `json:"buyer_first_name"`
-	BuyerLastName   string    `json:"buyer_last_name"`
+// A SellerShipment is the part of an order group a store ships, with its lines.
+type SellerShipment struct {
+	ShipmentID     int64                `json:"shipment_id"`
+	OrderNumber    string               `json:"order_number"`
+	DeliveryStatus string               `json:"delivery_status"`
+	Currency       string               `json:"currency"`
+	Total          string               `json:"total"`
+	CreatedAt      time.Time            `json:"created_at"`
+	BuyerFirstName string               `json:"buyer_first_name"`
+	BuyerLastName  string               `json:"buyer_last_name"`
+	Lines          []SellerShipmentLine `json:"lines"`
+}
+
+type SellerShipmentLine struct {
+	OrderID         int64  `json:"order_id"`
+	DeliveryStatus  string `json:"delivery_status"`
+	PaymentChannel  string `json:"payment_channel"`
+	ItemID          int64  `json:"item_id"`
+	ItemName        string `json:"item_name"`
+	ItemPrice       string `json:"item_price"`
+	ItemCoverImgUrl string `json:"item_cover_img_url"`
+	OrderQuantity   int32  `json:"order_quantity"`
 }
 
-// ListSellerOrders do a fulltext search to list a seller orders, and paginates accordingly.
-func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams) ([]SellerOrder, pagination.Metadata, error) {
+// ListSellerOrders do a fulltext search to list a store's shipments, and paginates accordingly.
+// A shipment is listed whole when any of its lines matches.
+func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams) ([]SellerShipment, pagination.Metadata, error) {
 	var whereClauses []string
 	var args []interface{}
 
@@ -201,7 +230,7 @@ func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams
 		args = append(args, arg.ItemName)
 	}
 	if arg.DeliveryStatus != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("o.delivery_status ILIKE '%%' || $%d || '%%'", len(args)+1))
+		whereClauses = append(whereClauses, fmt.Sprintf("fg.delivery_status ILIKE '%%' || $%d || '%%'", len(args)+1))
 		args = append(args, arg.DeliveryStatus)
 	}
 	if arg.PaymentChannel != "" {
@@ -209,7 +238,7 @@ func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams
 		args = append(args, arg.PaymentChannel)
 	}
 
-	whereClauses, args = addDateRangeFilter(arg.CreatedAtStart, arg.CreatedAtEnd, "o.created_at", whereClauses, args)
+	whereClauses, args = addDateRangeFilter(arg.CreatedAtStart, arg.CreatedAtEnd, "fg.created_at", whereClauses, args)
 
 	whereClause := strings.Join(whereClauses, " OR ")
 
@@ -220,26 +249,47 @@ func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams
 	stmt := fmt.Sprintf(`
     SELECT
         count(*) OVER() AS total_count,
-        o.id AS order_id,
-        o.delivery_status,
-        o.payment_channel,
-        o.created_at,
-        i.name AS item_name,
-        i.price AS item_price,
-        i.cover_img_url AS item_cover_img_url,
+        fg.id AS shipment_id,
+        og.order_number,
+        fg.delivery_status,
+        og.currency,
+        fg.total,
+        fg.created_at,
         u.first_name AS buyer_first_name,
-        u.last_name AS buyer_last_name
+        u.last_name AS buyer_last_name,
+        json_agg(json_build_object(
+            'order_id', o.id,
+            'delivery_status', o.delivery_status,
+            'payment_channel', o.payment_channel,
+            'item_id', i.id,
+            'item_name', i.name,
+            'item_price', o.item_price::text,
+            'item_cover_img_url', i.cover_img_url,
+            'order_quantity', o.order_quantity
+        ) ORDER BY o.id) AS lines
     FROM
-        orders o
+        fulfilment_groups fg
     JOIN
-        items i ON o.item_id = i.id
+        order_groups og ON fg.order_group_id = og.id
+    JOIN
+        users u ON og.buyer_id = u.id
     JOIN
-        users u ON o.buyer_id = u.id
+        orders o ON o.fulfilment_group_id = fg.id
+    JOIN
+        items i ON o.item_id = i.id
     WHERE
-        (%s)
-        AND o.seller_id = $%d
-				AND o.store_id = $%d
-    ORDER by o.%s %s, o.id ASC
+        fg.id IN (
+            SELECT fg.id
+            FROM fulfilment_groups fg
+            JOIN orders o ON o.fulfilment_group_id = fg.id
+            JOIN items i ON o.item_id = i.id
+            WHERE (%s)
+        )
+        AND fg.seller_id = $%d
+        AND fg.store_id = $%d
+    GROUP BY
+        fg.id, og.id, u.id
+    ORDER by fg.%s %s, fg.id ASC
     LIMIT $%d OFFSET $%d`, whereClause, len(args)+1, len(args)+2, arg.Filters.SortColumn(), arg.Filters.SortDirection(), len(args)+3, len(args)+4)
 
 	args = append(args, arg.SellerID, arg.StoreID, arg.Filters.Limit(), arg.Filters.Offset())
@@ -251,25 +301,30 @@ func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams
 	defer rows.Close()
 
 	totalRecords := 0
-	sos := []SellerOrder{}
+	shipments := []SellerShipment{}
 
 	for rows.Next() {
-		var so SellerOrder
+		var ss SellerShipment
+		var linesJSON []byte
 		if err := rows.Scan(
 			&totalRecords,
-			&so.OrderID,
-			&so.DeliveryStatus,
-			&so.PaymentChannel,
-			&so.CreatedAt,
-			&so.ItemName,
-			&so.ItemPrice,
-			&so.ItemCoverImgUrl,
-			&so.BuyerFirstName,
-			&so.BuyerLastName,
+			&ss.ShipmentID,
+			&ss.OrderNumber,
+			&ss.DeliveryStatus,
+			&ss.Currency,
+			&ss.Total,
+			&ss.CreatedAt,
+			&ss.BuyerFirstName,
+			&ss.BuyerLastName,
+			&linesJSON,
 		); err != nil {
 			return nil, pagination.Metadata{}, err
 		}
-		sos = append(sos, so)
+
+		if err := json.Unmarshal(linesJSON, &ss.Lines); err != nil {
+			return nil, pagination.Metadata{}, err
+		}
+		shipments = append(shipments, ss)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -278,7 +333,7 @@ func (q *SQLTx) ListSellerOrders(ctx context.Context, arg ListSellerOrdersParams
 
 	metadata := pagination.CalcMetadata(totalRecords, arg.Filters.Page, arg.Filters.PageSize)
 
-	return sos, metadata, nil
+	return shipments, metadata, nil
 }
 
 type ListAllSellerSalesParams struct {
@@ -350,7 +405,10 @@ func (q SQLTx) ListAllSellerSales(ctx context.Context, arg ListAllSellerSalesPar
 			u.account_id AS customer_account_id,
 			s.order_id,
 			o.created_at AS order_date,
-			o.delivered_on AS delivery_date 
+			o.delivered_on AS delivery_date,
+			o.tax_rate,
+			o.tax_inclusive,
+			o.tax_amount
 		FROM
 			sales s
 		JOIN
@@ -394,6 +452,9 @@ func (q SQLTx) ListAllSellerSales(ctx context.Context, arg ListAllSellerSalesPar
 			&s.OrderID,
 			&s.OrderDate,
 			&s.DeliveryDate,
+			&s.TaxRate,
+			&s.TaxInclusive,
+			&s.TaxAmount,
 		); err != nil {
 			return nil, pagination.Metadata{}, err
 		}
@@ -513,20 +574,21 @@ type ListReviewsParams struct {
 }
 
 type ListReviewsResult struct {
-	ID                 int64     `json:"id"`
-	StoreID            int64     `json:"store_id"`
-	UserID             int64     `json:"user_id"`
-	ItemID             int64     `json:"item_id"`
-	Rating             string    `json:"rating"`
-	ReviewType         string    `json:"review_type"`
-	Comment            string    `json:"comment"`
-	IsVerifiedPurchase bool      `json:"is_verified_purchase"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
-	FirstName          string    `json:"first_name"`
-	LastName           string    `json:"last_name"`
-	AccountID          string    `json:"account_id"`
-	ProfileImageUrl    string    `json:"profile_image_url"`
+	ID                 int64         `json:"id"`
+	StoreID            int64         `json:"store_id"`
+	UserID             int64         `json:"user_id"`
+	ItemID             int64         `json:"item_id"`
+	Rating             string        `json:"rating"`
+	ReviewType         string        `json:"review_type"`
+	Comment            string        `json:"comment"`
+	IsVerifiedPurchase bool          `json:"is_verified_purchase"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+	VariantID          sql.NullInt64 `json:"variant_id"`
+	FirstName          string        `json:"first_name"`
+	LastName           string        `json:"last_name"`
+	AccountID          string        `json:"account_id"`
+	ProfileImageUrl    string        `json:"profile_image_url"`
 }
 
 // ListReviews retrieves all the reviews for an item under a store.
@@ -579,6 +641,7 @@ func (q *Queries) ListReviews(ctx context.Context, arg ListReviewsParams) ([]Lis
 			&r.IsVerifiedPurchase,
 			&r.CreatedAt,
 			&r.UpdatedAt,
+			&r.VariantID,
 			&r.FirstName,
 			&r.LastName,
 			&r.AccountID,
@@ -19,14 +19,113 @@ type Cart struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-type CartItem struct {
-	ID        int64     `json:"id"`
+type CartCoupon struct {
 	CartID    int64     `json:"cart_id"`
-	ItemID    int64     `json:"item_id"`
+	CouponID  int64     `json:"coupon_id"`
 	StoreID   int64     `json:"store_id"`
-	Quantity  int32     `json:"quantity"`
-	AddedAt   time.Time `json:"added_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+type CartItem struct {
+	ID        int64         `json:"id"`
+	CartID    int64         `json:"cart_id"`
+	ItemID    int64         `json:"item_id"`
+	StoreID   int64         `json:"store_id"`
+	Quantity  int32         `json:"quantity"`
+	AddedAt   time.Time     `json:"added_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	VariantID sql.NullInt64 `json:"variant_id"`
+}
+
+type CheckoutCredit struct {
+	ID         int64         `json:"id"`
+	Reference  string        `json:"reference"`
+	UserID     int64         `json:"user_id"`
+	GiftCardID sql.NullInt64 `json:"gift_card_id"`
+	Amount     string        `json:"amount"`
+	Status     string        `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+type CheckoutFxRate struct {
+	Reference string    `json:"reference"`
+	Currency  string    `json:"currency"`
+	Rate      string    `json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CheckoutLine struct {
+	ID                 int64         `json:"id"`
+	Reference          string        `json:"reference"`
+	ItemID             int64         `json:"item_id"`
+	VariantID          sql.NullInt64 `json:"variant_id"`
+	VariantSku         string        `json:"variant_sku"`
+	StoreID            int64         `json:"store_id"`
+	ItemName           string        `json:"item_name"`
+	Price              string        `json:"price"`
+	DiscountPercentage string        `json:"discount_percentage"`
+	ItemCategory       string        `json:"item_category"`
+	ItemCurrency       string        `json:"item_currency"`
+	ItemWeightGrams    int64         `json:"item_weight_grams"`
+	Quantity           int32         `json:"quantity"`
+	CreatedAt          time.Time     `json:"created_at"`
+}
+
+type CheckoutRefund struct {
+	ID               int64     `json:"id"`
+	TransactionID    int64     `json:"transaction_id"`
+	Amount           string    `json:"amount"`
+	Reason           string    `json:"reason"`
+	Status           string    `json:"status"`
+	ProviderRefundID string    `json:"provider_refund_id"`
+	FailureReason    string    `json:"failure_reason"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type CommissionRule struct {
+	ID          int64          `json:"id"`
+	Scope       string         `json:"scope"`
+	StoreID     sql.NullInt64  `json:"store_id"`
+	Category    sql.NullString `json:"category"`
+	Percentage  string         `json:"percentage"`
+	FixedAmount string         `json:"fixed_amount"`
+	CreatedBy   int64          `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type Coupon struct {
+	ID            int64        `json:"id"`
+	StoreID       int64        `json:"store_id"`
+	Code          string       `json:"code"`
+	Description   string       `json:"description"`
+	DiscountType  string       `json:"discount_type"`
+	DiscountValue string       `json:"discount_value"`
+	Scope         string       `json:"scope"`
+	ItemIds       []int64      `json:"item_ids"`
+	Category      string       `json:"category"`
+	MinSpend      string       `json:"min_spend"`
+	UsageLimit    int32        `json:"usage_limit"`
+	PerUserLimit  int32        `json:"per_user_limit"`
+	StartsAt      time.Time    `json:"starts_at"`
+	EndsAt        sql.NullTime `json:"ends_at"`
+	IsActive      bool         `json:"is_active"`
+	CreatedBy     int64        `json:"created_by"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+type CouponRedemption struct {
+	ID             int64     `json:"id"`
+	CouponID       int64     `json:"coupon_id"`
+	UserID         int64     `json:"user_id"`
+	Reference      string    `json:"reference"`
+	DiscountAmount string    `json:"discount_amount"`
+	Status         string    `json:"status"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type CryptoAccount struct {
@@ -46,6 +145,100 @@ type FiatAccount struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type FlashSale struct {
+	ID              int64        `json:"id"`
+	StoreID         int64        `json:"store_id"`
+	ItemID          int64        `json:"item_id"`
+	SalePrice       string       `json:"sale_price"`
+	Quantity        int32        `json:"quantity"`
+	PerUserLimit    int32        `json:"per_user_limit"`
+	ClaimedQuantity int32        `json:"claimed_quantity"`
+	SoldQuantity    int32        `json:"sold_quantity"`
+	StartsAt        time.Time    `json:"starts_at"`
+	EndsAt          time.Time    `json:"ends_at"`
+	CancelledAt     sql.NullTime `json:"cancelled_at"`
+	ReconciledAt    sql.NullTime `json:"reconciled_at"`
+	CreatedBy       int64        `json:"created_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+type FlashSaleClaim struct {
+	ID          int64        `json:"id"`
+	FlashSaleID int64        `json:"flash_sale_id"`
+	UserID      int64        `json:"user_id"`
+	Reference   string       `json:"reference"`
+	Quantity    int32        `json:"quantity"`
+	Status      string       `json:"status"`
+	ReturnedAt  sql.NullTime `json:"returned_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type FulfilmentGroup struct {
+	ID                   int64     `json:"id"`
+	OrderGroupID         int64     `json:"order_group_id"`
+	StoreID              int64     `json:"store_id"`
+	SellerID             int64     `json:"seller_id"`
+	DeliveryStatus       string    `json:"delivery_status"`
+	Subtotal             string    `json:"subtotal"`
+	Discount             string    `json:"discount"`
+	DeliveryFee          string    `json:"delivery_fee"`
+	Total                string    `json:"total"`
+	Carrier              string    `json:"carrier"`
+	TrackingNumber       string    `json:"tracking_number"`
+	ShippedAt            time.Time `json:"shipped_at"`
+	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
+	DeliveredOn          time.Time `json:"delivered_on"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	Tax                  string    `json:"tax"`
+}
+
+type FxRate struct {
+	Currency  string    `json:"currency"`
+	Rate      string    `json:"rate"`
+	Source    string    `json:"source"`
+	AsOf      time.Time `json:"as_of"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GiftCard struct {
+	ID            int64     `json:"id"`
+	StoreID       int64     `json:"store_id"`
+	Code          string    `json:"code"`
+	Currency      string    `json:"currency"`
+	InitialAmount string    `json:"initial_amount"`
+	Balance       string    `json:"balance"`
+	Status        string    `json:"status"`
+	PurchaserID   int64     `json:"purchaser_id"`
+	Reference     string    `json:"reference"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type Invoice struct {
+	ID             int64     `json:"id"`
+	StoreID        int64     `json:"store_id"`
+	SequenceNumber int64     `json:"sequence_number"`
+	InvoiceNumber  string    `json:"invoice_number"`
+	Reference      string    `json:"reference"`
+	BuyerID        int64     `json:"buyer_id"`
+	Currency       string    `json:"currency"`
+	Subtotal       string    `json:"subtotal"`
+	Discount       string    `json:"discount"`
+	DeliveryFee    string    `json:"delivery_fee"`
+	Tax            string    `json:"tax"`
+	Total          string    `json:"total"`
+	EmailedAt      time.Time `json:"emailed_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type InvoiceSequence struct {
+	StoreID    int64 `json:"store_id"`
+	LastNumber int64 `json:"last_number"`
+}
+
 type Item struct {
 	ID                 int64           `json:"id"`
 	Name               string          `json:"name"`
@@ -63,25 +256,158 @@ type Item struct {
 	Status             string          `json:"status"`
 	CreatedAt          time.Time       `json:"created_at"`
 	UpdatedAt          time.Time       `json:"updated_at"`
+	WeightGrams        int64           `json:"weight_grams"`
+	Sku                string          `json:"sku"`
+	ReorderThreshold   sql.NullInt32   `json:"reorder_threshold"`
+}
+
+type ItemImport struct {
+	ID          int64        `json:"id"`
+	StoreID     int64        `json:"store_id"`
+	UploadedBy  int64        `json:"uploaded_by"`
+	Filename    string       `json:"filename"`
+	Status      string       `json:"status"`
+	Error       string       `json:"error"`
+	TotalRows   int32        `json:"total_rows"`
+	CreatedRows int32        `json:"created_rows"`
+	UpdatedRows int32        `json:"updated_rows"`
+	FailedRows  int32        `json:"failed_rows"`
+	CreatedAt   time.Time    `json:"created_at"`
+	StartedAt   sql.NullTime `json:"started_at"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+}
+
+type ItemImportError struct {
+	ID       int64  `json:"id"`
+	ImportID int64  `json:"import_id"`
+	Line     int32  `json:"line"`
+	Sku      string `json:"sku"`
+	Message  string `json:"message"`
+}
+
+type ItemImportFile struct {
+	ImportID int64  `json:"import_id"`
+	Content  []byte `json:"content"`
+}
+
+type ItemOption struct {
+	ID        int64     `json:"id"`
+	ItemID    int64     `json:"item_id"`
+	Name      string    `json:"name"`
+	Values    []string  `json:"values"`
+	Position  int32     `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ItemVariant struct {
+	ID             int64           `json:"id"`
+	ItemID         int64           `json:"item_id"`
+	StoreID        int64           `json:"store_id"`
+	Sku            string          `json:"sku"`
+	Options        json.RawMessage `json:"options"`
+	Price          sql.NullString  `json:"price"`
+	SupplyQuantity int64           `json:"supply_quantity"`
+	ImageUrls      []string        `json:"image_urls"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type JournalEntry struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LedgerAccount struct {
+	ID        int64         `json:"id"`
+	Code      string        `json:"code"`
+	Type      string        `json:"type"`
+	StoreID   sql.NullInt64 `json:"store_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type LedgerPosting struct {
+	ID              int64     `json:"id"`
+	JournalEntryID  int64     `json:"journal_entry_id"`
+	LedgerAccountID int64     `json:"ledger_account_id"`
+	Amount          string    `json:"amount"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type NearTransfer struct {
+	ID               int64         `json:"id"`
+	Reference        string        `json:"reference"`
+	RefundID         sql.NullInt64 `json:"refund_id"`
+	ReceiverID       string        `json:"receiver_id"`
+	Amount           string        `json:"amount"`
+	SignedTx         []byte        `json:"signed_tx"`
+	TxHash           string        `json:"tx_hash"`
+	Status           string        `json:"status"`
+	FailureReason    string        `json:"failure_reason"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+	CheckoutRefundID sql.NullInt64 `json:"checkout_refund_id"`
+}
+
+type NearTx struct {
+	TaskID    string    `json:"task_id"`
+	SignedTx  []byte    `json:"signed_tx"`
+	TxHash    string    `json:"tx_hash"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Order struct {
-	ID                   int64     `json:"id"`
-	DeliveryStatus       string    `json:"delivery_status"`
-	DeliveredOn          time.Time `json:"delivered_on"`
-	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
-	ItemID               int64     `json:"item_id"`
-	ItemPrice            string    `json:"item_price"`
-	ItemCurrency         string    `json:"item_currency"`
-	OrderQuantity        int32     `json:"order_quantity"`
-	BuyerID              int64     `json:"buyer_id"`
-	SellerID             int64     `json:"seller_id"`
-	StoreID              int64     `json:"store_id"`
-	DeliveryFee          string    `json:"delivery_fee"`
-	PaymentChannel       string    `json:"payment_channel"`
-	PaymentMethod        string    `json:"payment_method"`
-	IsReviewed           bool      `json:"is_reviewed"`
-	CreatedAt            time.Time `json:"created_at"`
+	ID                   int64         `json:"id"`
+	DeliveryStatus       string        `json:"delivery_status"`
+	DeliveredOn          time.Time     `json:"delivered_on"`
+	ExpectedDeliveryDate time.Time     `json:"expected_delivery_date"`
+	ItemID               int64         `json:"item_id"`
+	ItemPrice            string        `json:"item_price"`
+	ItemCurrency         string        `json:"item_currency"`
+	OrderQuantity        int32         `json:"order_quantity"`
+	BuyerID              int64         `json:"buyer_id"`
+	SellerID             int64         `json:"seller_id"`
+	StoreID              int64         `json:"store_id"`
+	DeliveryFee          string        `json:"delivery_fee"`
+	PaymentChannel       string        `json:"payment_channel"`
+	PaymentMethod        string        `json:"payment_method"`
+	IsReviewed           bool          `json:"is_reviewed"`
+	CreatedAt            time.Time     `json:"created_at"`
+	FundsReleasedAt      sql.NullTime  `json:"funds_released_at"`
+	CommissionRuleID     sql.NullInt64 `json:"commission_rule_id"`
+	CommissionPercentage string        `json:"commission_percentage"`
+	CommissionFixed      string        `json:"commission_fixed"`
+	CommissionAmount     string        `json:"commission_amount"`
+	CouponID             sql.NullInt64 `json:"coupon_id"`
+	DiscountAmount       string        `json:"discount_amount"`
+	Currency             string        `json:"currency"`
+	FxRate               string        `json:"fx_rate"`
+	FulfilmentGroupID    sql.NullInt64 `json:"fulfilment_group_id"`
+	TaxRate              string        `json:"tax_rate"`
+	TaxInclusive         bool          `json:"tax_inclusive"`
+	TaxAmount            string        `json:"tax_amount"`
+	VariantID            sql.NullInt64 `json:"variant_id"`
+	VariantSku           string        `json:"variant_sku"`
+}
+
+type OrderGroup struct {
+	ID              int64           `json:"id"`
+	OrderNumber     string          `json:"order_number"`
+	BuyerID         int64           `json:"buyer_id"`
+	Reference       string          `json:"reference"`
+	Status          string          `json:"status"`
+	ShippingAddress json.RawMessage `json:"shipping_address"`
+	Currency        string          `json:"currency"`
+	Subtotal        string          `json:"subtotal"`
+	DiscountTotal   string          `json:"discount_total"`
+	DeliveryTotal   string          `json:"delivery_total"`
+	GrandTotal      string          `json:"grand_total"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	TaxTotal        string          `json:"tax_total"`
 }
 
 type PendingTransactionFund struct {
@@ -93,17 +419,36 @@ type PendingTransactionFund struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+type Refund struct {
+	ID               int64     `json:"id"`
+	OrderID          int64     `json:"order_id"`
+	TransactionID    int64     `json:"transaction_id"`
+	StoreID          int64     `json:"store_id"`
+	Amount           string    `json:"amount"`
+	RestockQuantity  int32     `json:"restock_quantity"`
+	Reason           string    `json:"reason"`
+	Status           string    `json:"status"`
+	ProviderRefundID string    `json:"provider_refund_id"`
+	FailureReason    string    `json:"failure_reason"`
+	RequestedBy      int64     `json:"requested_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	CommissionAmount string    `json:"commission_amount"`
+	Destination      string    `json:"destination"`
+}
+
 type Review struct {
-	ID                 int64     `json:"id"`
-	StoreID            int64     `json:"store_id"`
-	UserID             int64     `json:"user_id"`
-	ItemID             int64     `json:"item_id"`
-	Rating             string    `json:"rating"`
-	ReviewType         string    `json:"review_type"`
-	Comment            string    `json:"comment"`
-	IsVerifiedPurchase bool      `json:"is_verified_purchase"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                 int64         `json:"id"`
+	StoreID            int64         `json:"store_id"`
+	UserID             int64         `json:"user_id"`
+	ItemID             int64         `json:"item_id"`
+	Rating             string        `json:"rating"`
+	ReviewType         string        `json:"review_type"`
+	Comment            string        `json:"comment"`
+	IsVerifiedPurchase bool          `json:"is_verified_purchase"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+	VariantID          sql.NullInt64 `json:"variant_id"`
 }
 
 type ReviewLike struct {
@@ -143,16 +488,77 @@ type Session struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type ShippingRate struct {
+	ID             int64  `json:"id"`
+	ShippingZoneID int64  `json:"shipping_zone_id"`
+	FromQuantity   int64  `json:"from_quantity"`
+	Fee            string `json:"fee"`
+}
+
+type ShippingZone struct {
+	ID                    int64     `json:"id"`
+	StoreID               int64     `json:"store_id"`
+	Name                  string    `json:"name"`
+	Country               string    `json:"country"`
+	State                 string    `json:"state"`
+	City                  string    `json:"city"`
+	RateBasis             string    `json:"rate_basis"`
+	FreeShippingThreshold string    `json:"free_shipping_threshold"`
+	MinDeliveryDays       int32     `json:"min_delivery_days"`
+	MaxDeliveryDays       int32     `json:"max_delivery_days"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+type StockAlert struct {
+	ID             int64        `json:"id"`
+	StoreID        int64        `json:"store_id"`
+	ItemID         int64        `json:"item_id"`
+	Kind           string       `json:"kind"`
+	SupplyQuantity int64        `json:"supply_quantity"`
+	Threshold      int32        `json:"threshold"`
+	CreatedAt      time.Time    `json:"created_at"`
+	SentAt         sql.NullTime `json:"sent_at"`
+}
+
+type StockMovement struct {
+	ID                int64         `json:"id"`
+	StoreID           int64         `json:"store_id"`
+	ItemID            int64         `json:"item_id"`
+	VariantID         sql.NullInt64 `json:"variant_id"`
+	Reason            string        `json:"reason"`
+	QuantityChange    int64         `json:"quantity_change"`
+	ResultingQuantity int64         `json:"resulting_quantity"`
+	UserID            sql.NullInt64 `json:"user_id"`
+	Reference         string        `json:"reference"`
+	Note              string        `json:"note"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+type StockReservation struct {
+	ID        int64         `json:"id"`
+	ItemID    int64         `json:"item_id"`
+	UserID    int64         `json:"user_id"`
+	Reference string        `json:"reference"`
+	Quantity  int32         `json:"quantity"`
+	Status    string        `json:"status"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	VariantID sql.NullInt64 `json:"variant_id"`
+}
+
 type Store struct {
-	ID              int64     `json:"id"`
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	StoreAccountID  string    `json:"store_account_id"`
-	ProfileImageUrl string    `json:"profile_image_url"`
-	IsVerified      bool      `json:"is_verified"`
-	Category        string    `json:"category"`
-	IsFrozen        bool      `json:"is_frozen"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	StoreAccountID    string    `json:"store_account_id"`
+	ProfileImageUrl   string    `json:"profile_image_url"`
+	IsVerified        bool      `json:"is_verified"`
+	Category          string    `json:"category"`
+	IsFrozen          bool      `json:"is_frozen"`
+	CreatedAt         time.Time `json:"created_at"`
+	LowStockThreshold int32     `json:"low_stock_threshold"`
 }
 
 type StoreAuditTrail struct {
@@ -164,6 +570,31 @@ type StoreAuditTrail struct {
 	Timestamp time.Time             `json:"timestamp"`
 }
 
+type StoreCredit struct {
+	UserID    int64     `json:"user_id"`
+	Balance   string    `json:"balance"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type StoreCreditEntry struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Kind        string    `json:"kind"`
+	Amount      string    `json:"amount"`
+	Balance     string    `json:"balance"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type StoreDeliveryRule struct {
+	StoreID               int64     `json:"store_id"`
+	BaseFee               string    `json:"base_fee"`
+	PerItemFee            string    `json:"per_item_fee"`
+	FreeDeliveryThreshold string    `json:"free_delivery_threshold"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
 type StoreOwner struct {
 	UserID       int64     `json:"user_id"`
 	StoreID      int64     `json:"store_id"`
@@ -172,6 +603,19 @@ type StoreOwner struct {
 	AddedAt      time.Time `json:"added_at"`
 }
 
+type TaxRule struct {
+	ID        int64     `json:"id"`
+	StoreID   int64     `json:"store_id"`
+	Name      string    `json:"name"`
+	Country   string    `json:"country"`
+	State     string    `json:"state"`
+	Category  string    `json:"category"`
+	Rate      string    `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type Transaction struct {
 	ID                   int64          `json:"id"`
 	OrderIds             []int64        `json:"order_ids"`
@@ -183,6 +627,8 @@ type Transaction struct {
 	ProviderTxFee        string         `json:"provider_tx_fee"`
 	Status               string         `json:"status"`
 	CreatedAt            time.Time      `json:"created_at"`
+	ProviderTxHash       sql.NullString `json:"provider_tx_hash"`
+	NearAmount           sql.NullString `json:"near_amount"`
 }
 
 type User struct {
@@ -201,3 +647,16 @@ type User struct {
 	IsActive          bool            `json:"is_active"`
 	IsEmailVerified   bool            `json:"is_email_verified"`
 }
+
+type WithdrawalRequest struct {
+	ID          int64     `json:"id"`
+	StoreID     int64     `json:"store_id"`
+	AccountType string    `json:"account_type"`
+	Amount      string    `json:"amount"`
+	Destination string    `json:"destination"`
+	Status      string    `json:"status"`
+	RequestedBy int64     `json:"requested_by"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,213 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: near_transfer.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const completeNEARTransfer = `-- name: CompleteNEARTransfer :one
+UPDATE near_transfers
+SET
+  status = 'COMPLETED',
+  updated_at = now()
+WHERE id = $1
+  AND status = 'PENDING'
+RETURNING id, reference, refund_id, receiver_id, amount, signed_tx, tx_hash, status, failure_reason, created_at, updated_at, checkout_refund_id
+`
+
+func (q *Queries) CompleteNEARTransfer(ctx context.Context, transferID int64) (NearTransfer, error) {
+	row := q.db.QueryRowContext(ctx, completeNEARTransfer, transferID)
+	var i NearTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.RefundID,
+		&i.ReceiverID,
+		&i.Amount,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.Status,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutRefundID,
+	)
+	return i, err
+}
+
+const createNEARTransfer = `-- name: CreateNEARTransfer :one
+INSERT INTO near_transfers (
+  reference,
+  refund_id,
+  checkout_refund_id,
+  receiver_id,
+  amount
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id, reference, refund_id, receiver_id, amount, signed_tx, tx_hash, status, failure_reason, created_at, updated_at, checkout_refund_id
+`
+
+type CreateNEARTransferParams struct {
+	Reference        string        `json:"reference"`
+	RefundID         sql.NullInt64 `json:"refund_id"`
+	CheckoutRefundID sql.NullInt64 `json:"checkout_refund_id"`
+	ReceiverID       string        `json:"receiver_id"`
+	Amount           string        `json:"amount"`
+}
+
+func (q *Queries) CreateNEARTransfer(ctx context.Context, arg CreateNEARTransferParams) (NearTransfer, error) {
+	row := q.db.QueryRowContext(ctx, createNEARTransfer,
+		arg.Reference,
+		arg.RefundID,
+		arg.CheckoutRefundID,
+		arg.ReceiverID,
+		arg.Amount,
+	)
+	var i NearTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.RefundID,
+		&i.ReceiverID,
+		&i.Amount,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.Status,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutRefundID,
+	)
+	return i, err
+}
+
+const failNEARTransfer = `-- name: FailNEARTransfer :one
+UPDATE near_transfers
+SET
+  status = 'FAILED',
+  failure_reason = $1,
+  updated_at = now()
+WHERE id = $2
+  AND status = 'PENDING'
+RETURNING id, reference, refund_id, receiver_id, amount, signed_tx, tx_hash, status, failure_reason, created_at, updated_at, checkout_refund_id
+`
+
+type FailNEARTransferParams struct {
+	FailureReason string `json:"failure_reason"`
+	TransferID    int64  `json:"transfer_id"`
+}
+
+func (q *Queries) FailNEARTransfer(ctx context.Context, arg FailNEARTransferParams) (NearTransfer, error) {
+	row := q.db.QueryRowContext(ctx, failNEARTransfer, arg.FailureReason, arg.TransferID)
+	var i NearTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.RefundID,
+		&i.ReceiverID,
+		&i.Amount,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.Status,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutRefundID,
+	)
+	return i, err
+}
+
+const getNEARTransfer = `-- name: GetNEARTransfer :one
+SELECT id, reference, refund_id, receiver_id, amount, signed_tx, tx_hash, status, failure_reason, created_at, updated_at, checkout_refund_id FROM near_transfers
+WHERE id = $1
+`
+
+func (q *Queries) GetNEARTransfer(ctx context.Context, transferID int64) (NearTransfer, error) {
+	row := q.db.QueryRowContext(ctx, getNEARTransfer, transferID)
+	var i NearTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.RefundID,
+		&i.ReceiverID,
+		&i.Amount,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.Status,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutRefundID,
+	)
+	return i, err
+}
+
+const getNEARTransferForUpdate = `-- name: GetNEARTransferForUpdate :one
+SELECT id, reference, refund_id, receiver_id, amount, signed_tx, tx_hash, status, failure_reason, created_at, updated_at, checkout_refund_id FROM near_transfers
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetNEARTransferForUpdate(ctx context.Context, transferID int64) (NearTransfer, error) {
+	row := q.db.QueryRowContext(ctx, getNEARTransferForUpdate, transferID)
+	var i NearTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.RefundID,
+		&i.ReceiverID,
+		&i.Amount,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.Status,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutRefundID,
+	)
+	return i, err
+}
+
+const setNEARTransferSignedTx = `-- name: SetNEARTransferSignedTx :one
+UPDATE near_transfers
+SET
+  signed_tx = $1,
+  tx_hash = $2,
+  updated_at = now()
+WHERE id = $3
+  AND status = 'PENDING'
+RETURNING id, reference, refund_id, receiver_id, amount, signed_tx, tx_hash, status, failure_reason, created_at, updated_at, checkout_refund_id
+`
+
+type SetNEARTransferSignedTxParams struct {
+	SignedTx   []byte `json:"signed_tx"`
+	TxHash     string `json:"tx_hash"`
+	TransferID int64  `json:"transfer_id"`
+}
+
+// Saves the transaction a PENDING transfer is sent in, or clears it with a
+// NULL signed_tx once it can no longer execute.
+func (q *Queries) SetNEARTransferSignedTx(ctx context.Context, arg SetNEARTransferSignedTxParams) (NearTransfer, error) {
+	row := q.db.QueryRowContext(ctx, setNEARTransferSignedTx, arg.SignedTx, arg.TxHash, arg.TransferID)
+	var i NearTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.RefundID,
+		&i.ReceiverID,
+		&i.Amount,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.Status,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CheckoutRefundID,
+	)
+	return i, err
+}
@@ -0,0 +1,74 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: near_tx.sql
+
+package db
+
+import (
+	"context"
+)
+
+const deleteNEARTx = `-- name: DeleteNEARTx :exec
+DELETE FROM near_txs
+WHERE task_id = $1
+`
+
+func (q *Queries) DeleteNEARTx(ctx context.Context, taskID string) error {
+	_, err := q.db.ExecContext(ctx, deleteNEARTx, taskID)
+	return err
+}
+
+const getNEARTx = `-- name: GetNEARTx :one
+SELECT task_id, signed_tx, tx_hash, created_at, updated_at FROM near_txs
+WHERE task_id = $1
+`
+
+func (q *Queries) GetNEARTx(ctx context.Context, taskID string) (NearTx, error) {
+	row := q.db.QueryRowContext(ctx, getNEARTx, taskID)
+	var i NearTx
+	err := row.Scan(
+		&i.TaskID,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const saveNEARTx = `-- name: SaveNEARTx :one
+INSERT INTO near_txs (
+  task_id,
+  signed_tx,
+  tx_hash
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (task_id) DO UPDATE
+SET
+  signed_tx = EXCLUDED.signed_tx,
+  tx_hash = EXCLUDED.tx_hash,
+  updated_at = now()
+RETURNING task_id, signed_tx, tx_hash, created_at, updated_at
+`
+
+type SaveNEARTxParams struct {
+	TaskID   string `json:"task_id"`
+	SignedTx []byte `json:"signed_tx"`
+	TxHash   string `json:"tx_hash"`
+}
+
+// Saves the transaction a task sends, replacing one that can no longer execute.
+func (q *Queries) SaveNEARTx(ctx context.Context, arg SaveNEARTxParams) (NearTx, error) {
+	row := q.db.QueryRowContext(ctx, saveNEARTx, arg.TaskID, arg.SignedTx, arg.TxHash)
+	var i NearTx
+	err := row.Scan(
+		&i.TaskID,
+		&i.SignedTx,
+		&i.TxHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -25,7 +25,7 @@ INSERT INTO orders (
   payment_method
 ) VALUES (
   $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at
+) RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
 `
 
 type CreateOrderParams struct {
@@ -72,12 +72,27 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.PaymentMethod,
 		&i.IsReviewed,
 		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
 	)
 	return i, err
 }
 
 const createOrderFn = `-- name: CreateOrderFn :one
-SELECT id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at FROM create_order(
+SELECT id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku FROM create_order(
   $1,
   $2,
   $3,
@@ -85,7 +100,8 @@ SELECT id, delivery_status, delivered_on, expected_delivery_date, item_id, item_
   $5,
   $6,
   $7,
-  $8
+  $8,
+  $9
 )
 `
 
@@ -95,6 +111,7 @@ type CreateOrderFnParams struct {
 	BuyerID        int64  `json:"buyer_id"`
 	SellerID       int64  `json:"seller_id"`
 	StoreID        int64  `json:"store_id"`
+	ItemPrice      string `json:"item_price"`
 	DeliveryFee    string `json:"delivery_fee"`
 	PaymentChannel string `json:"payment_channel"`
 	PaymentMethod  string `json:"payment_method"`
@@ -107,6 +124,7 @@ func (q *Queries) CreateOrderFn(ctx context.Context, arg CreateOrderFnParams) (O
 		arg.BuyerID,
 		arg.SellerID,
 		arg.StoreID,
+		arg.ItemPrice,
 		arg.DeliveryFee,
 		arg.PaymentChannel,
 		arg.PaymentMethod,
@@ -129,6 +147,21 @@ func (q *Queries) CreateOrderFn(ctx context.Context, arg CreateOrderFnParams) (O
 		&i.PaymentMethod,
 		&i.IsReviewed,
 		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
 	)
 	return i, err
 }
@@ -140,6 +173,8 @@ SELECT
   o.delivered_on,
   o.expected_delivery_date,
   o.item_id,
+  o.variant_id,
+  o.variant_sku,
   o.order_quantity,
   o.seller_id,
   o.store_id,
@@ -168,24 +203,26 @@ type GetOrderForBuyerParams struct {
 }
 
 type GetOrderForBuyerRow struct {
-	OrderID              int64     `json:"order_id"`
-	DeliveryStatus       string    `json:"delivery_status"`
-	DeliveredOn          time.Time `json:"delivered_on"`
-	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
-	ItemID               int64     `json:"item_id"`
-	OrderQuantity        int32     `json:"order_quantity"`
-	SellerID             int64     `json:"seller_id"`
-	StoreID              int64     `json:"store_id"`
-	DeliveryFee          string    `json:"delivery_fee"`
-	PaymentChannel       string    `json:"payment_channel"`
-	PaymentMethod        string    `json:"payment_method"`
-	IsReviewed           bool      `json:"is_reviewed"`
-	CreatedAt            time.Time `json:"created_at"`
-	ItemName             string    `json:"item_name"`
-	ItemDescription      string    `json:"item_description"`
-	Price                string    `json:"price"`
-	CoverImgUrl          string    `json:"cover_img_url"`
-	DiscountPercentage   string    `json:"discount_percentage"`
+	OrderID              int64         `json:"order_id"`
+	DeliveryStatus       string        `json:"delivery_status"`
+	DeliveredOn          time.Time     `json:"delivered_on"`
+	ExpectedDeliveryDate time.Time     `json:"expected_delivery_date"`
+	ItemID               int64         `json:"item_id"`
+	VariantID            sql.NullInt64 `json:"variant_id"`
+	VariantSku           string        `json:"variant_sku"`
+	OrderQuantity        int32         `json:"order_quantity"`
+	SellerID             int64         `json:"seller_id"`
+	StoreID              int64         `json:"store_id"`
+	DeliveryFee          string        `json:"delivery_fee"`
+	PaymentChannel       string        `json:"payment_channel"`
+	PaymentMethod        string        `json:"payment_method"`
+	IsReviewed           bool          `json:"is_reviewed"`
+	CreatedAt            time.Time     `json:"created_at"`
+	ItemName             string        `json:"item_name"`
+	ItemDescription      string        `json:"item_description"`
+	Price                string        `json:"price"`
+	CoverImgUrl          string        `json:"cover_img_url"`
+	DiscountPercentage   string        `json:"discount_percentage"`
 }
 
 func (q *Queries) GetOrderForBuyer(ctx context.Context, arg GetOrderForBuyerParams) (GetOrderForBuyerRow, error) {
@@ -197,6 +234,8 @@ func (q *Queries) GetOrderForBuyer(ctx context.Context, arg GetOrderForBuyerPara
 		&i.DeliveredOn,
 		&i.ExpectedDeliveryDate,
 		&i.ItemID,
+		&i.VariantID,
+		&i.VariantSku,
 		&i.OrderQuantity,
 		&i.SellerID,
 		&i.StoreID,
@@ -228,6 +267,7 @@ SELECT
   o.payment_channel,
   o.payment_method,
   o.created_at,
+  o.fulfilment_group_id,
   i.name AS item_name,
   i.description AS item_description,
   i.price,
@@ -254,27 +294,28 @@ type GetOrderForSellerParams struct {
 }
 
 type GetOrderForSellerRow struct {
-	OrderID              int64     `json:"order_id"`
-	DeliveryStatus       string    `json:"delivery_status"`
-	DeliveredOn          time.Time `json:"delivered_on"`
-	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
-	ItemID               int64     `json:"item_id"`
-	OrderQuantity        int32     `json:"order_quantity"`
-	BuyerID              int64     `json:"buyer_id"`
-	StoreID              int64     `json:"store_id"`
-	DeliveryFee          string    `json:"delivery_fee"`
-	PaymentChannel       string    `json:"payment_channel"`
-	PaymentMethod        string    `json:"payment_method"`
-	CreatedAt            time.Time `json:"created_at"`
-	ItemName             string    `json:"item_name"`
-	ItemDescription      string    `json:"item_description"`
-	Price                string    `json:"price"`
-	CoverImgUrl          string    `json:"cover_img_url"`
-	DiscountPercentage   string    `json:"discount_percentage"`
-	FirstName            string    `json:"first_name"`
-	LastName             string    `json:"last_name"`
-	Email                string    `json:"email"`
-	AccountID            string    `json:"account_id"`
+	OrderID              int64         `json:"order_id"`
+	DeliveryStatus       string        `json:"delivery_status"`
+	DeliveredOn          time.Time     `json:"delivered_on"`
+	ExpectedDeliveryDate time.Time     `json:"expected_delivery_date"`
+	ItemID               int64         `json:"item_id"`
+	OrderQuantity        int32         `json:"order_quantity"`
+	BuyerID              int64         `json:"buyer_id"`
+	StoreID              int64         `json:"store_id"`
+	DeliveryFee          string        `json:"delivery_fee"`
+	PaymentChannel       string        `json:"payment_channel"`
+	PaymentMethod        string        `json:"payment_method"`
+	CreatedAt            time.Time     `json:"created_at"`
+	FulfilmentGroupID    sql.NullInt64 `json:"fulfilment_group_id"`
+	ItemName             string        `json:"item_name"`
+	ItemDescription      string        `json:"item_description"`
+	Price                string        `json:"price"`
+	CoverImgUrl          string        `json:"cover_img_url"`
+	DiscountPercentage   string        `json:"discount_percentage"`
+	FirstName            string        `json:"first_name"`
+	LastName             string        `json:"last_name"`
+	Email                string        `json:"email"`
+	AccountID            string        `json:"account_id"`
 }
 
 func (q *Queries) GetOrderForSeller(ctx context.Context, arg GetOrderForSellerParams) (GetOrderForSellerRow, error) {
@@ -293,6 +334,7 @@ func (q *Queries) GetOrderForSeller(ctx context.Context, arg GetOrderForSellerPa
 		&i.PaymentChannel,
 		&i.PaymentMethod,
 		&i.CreatedAt,
+		&i.FulfilmentGroupID,
 		&i.ItemName,
 		&i.ItemDescription,
 		&i.Price,
@@ -312,7 +354,7 @@ SET
   is_reviewed = COALESCE($1, is_reviewed)
 WHERE
   id = $2 AND buyer_id = $3 AND store_id = $4
-RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
 `
 
 type UpdateBuyerOrderParams struct {
@@ -347,6 +389,21 @@ func (q *Queries) UpdateBuyerOrder(ctx context.Context, arg UpdateBuyerOrderPara
 		&i.PaymentMethod,
 		&i.IsReviewed,
 		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
 	)
 	return i, err
 }
@@ -359,7 +416,7 @@ SET
   expected_delivery_date = COALESCE($3, expected_delivery_date)
 WHERE
   id = $4 AND seller_id = $5 AND store_id = $6
-RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
 `
 
 type UpdateSellerOrderParams struct {
@@ -398,6 +455,21 @@ func (q *Queries) UpdateSellerOrder(ctx context.Context, arg UpdateSellerOrderPa
 		&i.PaymentMethod,
 		&i.IsReviewed,
 		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
 	)
 	return i, err
 }
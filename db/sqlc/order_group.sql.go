@@ -0,0 +1,765 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: order_group.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const assignFulfilmentGroups = `-- name: AssignFulfilmentGroups :execrows
+UPDATE orders o
+SET fulfilment_group_id = fg.id
+FROM fulfilment_groups fg
+WHERE fg.order_group_id = $1
+  AND fg.store_id = o.store_id
+  AND o.id = ANY($2::bigint[])
+`
+
+type AssignFulfilmentGroupsParams struct {
+	OrderGroupID int64   `json:"order_group_id"`
+	OrderIds     []int64 `json:"order_ids"`
+}
+
+func (q *Queries) AssignFulfilmentGroups(ctx context.Context, arg AssignFulfilmentGroupsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, assignFulfilmentGroups, arg.OrderGroupID, pq.Array(arg.OrderIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createFulfilmentGroups = `-- name: CreateFulfilmentGroups :many
+INSERT INTO fulfilment_groups (
+  order_group_id,
+  store_id,
+  seller_id,
+  subtotal,
+  discount,
+  delivery_fee,
+  tax,
+  total,
+  expected_delivery_date
+)
+SELECT
+  $1::bigint,
+  o.store_id,
+  min(o.seller_id),
+  sum(o.item_price * o.order_quantity),
+  sum(o.discount_amount),
+  sum(o.delivery_fee),
+  sum(o.tax_amount),
+  sum(o.item_price * o.order_quantity - o.discount_amount + o.delivery_fee
+    + CASE WHEN o.tax_inclusive THEN 0 ELSE o.tax_amount END),
+  max(o.expected_delivery_date)
+FROM orders o
+WHERE o.id = ANY($2::bigint[])
+GROUP BY o.store_id
+RETURNING id, order_group_id, store_id, seller_id, delivery_status, subtotal, discount, delivery_fee, total, carrier, tracking_number, shipped_at, expected_delivery_date, delivered_on, created_at, updated_at, tax
+`
+
+type CreateFulfilmentGroupsParams struct {
+	OrderGroupID int64   `json:"order_group_id"`
+	OrderIds     []int64 `json:"order_ids"`
+}
+
+// Groups orders by store under an order group, totalling what each store ships.
+func (q *Queries) CreateFulfilmentGroups(ctx context.Context, arg CreateFulfilmentGroupsParams) ([]FulfilmentGroup, error) {
+	rows, err := q.db.QueryContext(ctx, createFulfilmentGroups, arg.OrderGroupID, pq.Array(arg.OrderIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FulfilmentGroup{}
+	for rows.Next() {
+		var i FulfilmentGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderGroupID,
+			&i.StoreID,
+			&i.SellerID,
+			&i.DeliveryStatus,
+			&i.Subtotal,
+			&i.Discount,
+			&i.DeliveryFee,
+			&i.Total,
+			&i.Carrier,
+			&i.TrackingNumber,
+			&i.ShippedAt,
+			&i.ExpectedDeliveryDate,
+			&i.DeliveredOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Tax,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOrderGroup = `-- name: CreateOrderGroup :one
+INSERT INTO order_groups (
+  buyer_id,
+  reference,
+  status,
+  shipping_address,
+  currency,
+  subtotal,
+  discount_total,
+  delivery_total,
+  tax_total,
+  grand_total
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, order_number, buyer_id, reference, status, shipping_address, currency, subtotal, discount_total, delivery_total, grand_total, created_at, updated_at, tax_total
+`
+
+type CreateOrderGroupParams struct {
+	BuyerID         int64           `json:"buyer_id"`
+	Reference       string          `json:"reference"`
+	Status          string          `json:"status"`
+	ShippingAddress json.RawMessage `json:"shipping_address"`
+	Currency        string          `json:"currency"`
+	Subtotal        string          `json:"subtotal"`
+	DiscountTotal   string          `json:"discount_total"`
+	DeliveryTotal   string          `json:"delivery_total"`
+	TaxTotal        string          `json:"tax_total"`
+	GrandTotal      string          `json:"grand_total"`
+}
+
+func (q *Queries) CreateOrderGroup(ctx context.Context, arg CreateOrderGroupParams) (OrderGroup, error) {
+	row := q.db.QueryRowContext(ctx, createOrderGroup,
+		arg.BuyerID,
+		arg.Reference,
+		arg.Status,
+		arg.ShippingAddress,
+		arg.Currency,
+		arg.Subtotal,
+		arg.DiscountTotal,
+		arg.DeliveryTotal,
+		arg.TaxTotal,
+		arg.GrandTotal,
+	)
+	var i OrderGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderNumber,
+		&i.BuyerID,
+		&i.Reference,
+		&i.Status,
+		&i.ShippingAddress,
+		&i.Currency,
+		&i.Subtotal,
+		&i.DiscountTotal,
+		&i.DeliveryTotal,
+		&i.GrandTotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxTotal,
+	)
+	return i, err
+}
+
+const failOrderGroup = `-- name: FailOrderGroup :exec
+UPDATE order_groups
+SET
+  status = 'FAILED',
+  updated_at = now()
+WHERE reference = $1 AND status = 'PENDING'
+`
+
+func (q *Queries) FailOrderGroup(ctx context.Context, reference string) error {
+	_, err := q.db.ExecContext(ctx, failOrderGroup, reference)
+	return err
+}
+
+const getBuyerOrderGroup = `-- name: GetBuyerOrderGroup :one
+SELECT id, order_number, buyer_id, reference, status, shipping_address, currency, subtotal, discount_total, delivery_total, grand_total, created_at, updated_at, tax_total FROM order_groups
+WHERE order_number = $1 AND buyer_id = $2
+`
+
+type GetBuyerOrderGroupParams struct {
+	OrderNumber string `json:"order_number"`
+	BuyerID     int64  `json:"buyer_id"`
+}
+
+func (q *Queries) GetBuyerOrderGroup(ctx context.Context, arg GetBuyerOrderGroupParams) (OrderGroup, error) {
+	row := q.db.QueryRowContext(ctx, getBuyerOrderGroup, arg.OrderNumber, arg.BuyerID)
+	var i OrderGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderNumber,
+		&i.BuyerID,
+		&i.Reference,
+		&i.Status,
+		&i.ShippingAddress,
+		&i.Currency,
+		&i.Subtotal,
+		&i.DiscountTotal,
+		&i.DeliveryTotal,
+		&i.GrandTotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxTotal,
+	)
+	return i, err
+}
+
+const getOrderGroupByReference = `-- name: GetOrderGroupByReference :one
+SELECT id, order_number, buyer_id, reference, status, shipping_address, currency, subtotal, discount_total, delivery_total, grand_total, created_at, updated_at, tax_total FROM order_groups
+WHERE reference = $1
+`
+
+func (q *Queries) GetOrderGroupByReference(ctx context.Context, reference string) (OrderGroup, error) {
+	row := q.db.QueryRowContext(ctx, getOrderGroupByReference, reference)
+	var i OrderGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderNumber,
+		&i.BuyerID,
+		&i.Reference,
+		&i.Status,
+		&i.ShippingAddress,
+		&i.Currency,
+		&i.Subtotal,
+		&i.DiscountTotal,
+		&i.DeliveryTotal,
+		&i.GrandTotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxTotal,
+	)
+	return i, err
+}
+
+const getOrderGroupByReferenceForUpdate = `-- name: GetOrderGroupByReferenceForUpdate :one
+SELECT id, order_number, buyer_id, reference, status, shipping_address, currency, subtotal, discount_total, delivery_total, grand_total, created_at, updated_at, tax_total FROM order_groups
+WHERE reference = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetOrderGroupByReferenceForUpdate(ctx context.Context, reference string) (OrderGroup, error) {
+	row := q.db.QueryRowContext(ctx, getOrderGroupByReferenceForUpdate, reference)
+	var i OrderGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderNumber,
+		&i.BuyerID,
+		&i.Reference,
+		&i.Status,
+		&i.ShippingAddress,
+		&i.Currency,
+		&i.Subtotal,
+		&i.DiscountTotal,
+		&i.DeliveryTotal,
+		&i.GrandTotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxTotal,
+	)
+	return i, err
+}
+
+const getSellerFulfilmentGroup = `-- name: GetSellerFulfilmentGroup :one
+SELECT
+  fg.id, fg.order_group_id, fg.store_id, fg.seller_id, fg.delivery_status, fg.subtotal, fg.discount, fg.delivery_fee, fg.total, fg.carrier, fg.tracking_number, fg.shipped_at, fg.expected_delivery_date, fg.delivered_on, fg.created_at, fg.updated_at, fg.tax,
+  og.order_number,
+  og.shipping_address,
+  og.currency,
+  u.first_name AS buyer_first_name,
+  u.last_name AS buyer_last_name,
+  u.email AS buyer_email
+FROM fulfilment_groups fg
+JOIN order_groups og ON fg.order_group_id = og.id
+JOIN users u ON og.buyer_id = u.id
+WHERE fg.id = $1
+  AND fg.store_id = $2
+  AND fg.seller_id = $3
+`
+
+type GetSellerFulfilmentGroupParams struct {
+	FulfilmentGroupID int64 `json:"fulfilment_group_id"`
+	StoreID           int64 `json:"store_id"`
+	SellerID          int64 `json:"seller_id"`
+}
+
+type GetSellerFulfilmentGroupRow struct {
+	ID                   int64           `json:"id"`
+	OrderGroupID         int64           `json:"order_group_id"`
+	StoreID              int64           `json:"store_id"`
+	SellerID             int64           `json:"seller_id"`
+	DeliveryStatus       string          `json:"delivery_status"`
+	Subtotal             string          `json:"subtotal"`
+	Discount             string          `json:"discount"`
+	DeliveryFee          string          `json:"delivery_fee"`
+	Total                string          `json:"total"`
+	Carrier              string          `json:"carrier"`
+	TrackingNumber       string          `json:"tracking_number"`
+	ShippedAt            time.Time       `json:"shipped_at"`
+	ExpectedDeliveryDate time.Time       `json:"expected_delivery_date"`
+	DeliveredOn          time.Time       `json:"delivered_on"`
+	CreatedAt            time.Time       `json:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+	Tax                  string          `json:"tax"`
+	OrderNumber          string          `json:"order_number"`
+	ShippingAddress      json.RawMessage `json:"shipping_address"`
+	Currency             string          `json:"currency"`
+	BuyerFirstName       string          `json:"buyer_first_name"`
+	BuyerLastName        string          `json:"buyer_last_name"`
+	BuyerEmail           string          `json:"buyer_email"`
+}
+
+func (q *Queries) GetSellerFulfilmentGroup(ctx context.Context, arg GetSellerFulfilmentGroupParams) (GetSellerFulfilmentGroupRow, error) {
+	row := q.db.QueryRowContext(ctx, getSellerFulfilmentGroup, arg.FulfilmentGroupID, arg.StoreID, arg.SellerID)
+	var i GetSellerFulfilmentGroupRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrderGroupID,
+		&i.StoreID,
+		&i.SellerID,
+		&i.DeliveryStatus,
+		&i.Subtotal,
+		&i.Discount,
+		&i.DeliveryFee,
+		&i.Total,
+		&i.Carrier,
+		&i.TrackingNumber,
+		&i.ShippedAt,
+		&i.ExpectedDeliveryDate,
+		&i.DeliveredOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Tax,
+		&i.OrderNumber,
+		&i.ShippingAddress,
+		&i.Currency,
+		&i.BuyerFirstName,
+		&i.BuyerLastName,
+		&i.BuyerEmail,
+	)
+	return i, err
+}
+
+const listBuyerOrderGroups = `-- name: ListBuyerOrderGroups :many
+SELECT
+  count(*) OVER() AS total_count,
+  og.id, og.order_number, og.buyer_id, og.reference, og.status, og.shipping_address, og.currency, og.subtotal, og.discount_total, og.delivery_total, og.grand_total, og.created_at, og.updated_at, og.tax_total
+FROM order_groups og
+WHERE og.buyer_id = $1 AND og.status <> 'FAILED'
+ORDER BY og.id DESC
+LIMIT $3
+OFFSET $2
+`
+
+type ListBuyerOrderGroupsParams struct {
+	BuyerID  int64 `json:"buyer_id"`
+	RwOffset int32 `json:"rw_offset"`
+	RwLimit  int32 `json:"rw_limit"`
+}
+
+type ListBuyerOrderGroupsRow struct {
+	TotalCount      int64           `json:"total_count"`
+	ID              int64           `json:"id"`
+	OrderNumber     string          `json:"order_number"`
+	BuyerID         int64           `json:"buyer_id"`
+	Reference       string          `json:"reference"`
+	Status          string          `json:"status"`
+	ShippingAddress json.RawMessage `json:"shipping_address"`
+	Currency        string          `json:"currency"`
+	Subtotal        string          `json:"subtotal"`
+	DiscountTotal   string          `json:"discount_total"`
+	DeliveryTotal   string          `json:"delivery_total"`
+	GrandTotal      string          `json:"grand_total"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	TaxTotal        string          `json:"tax_total"`
+}
+
+func (q *Queries) ListBuyerOrderGroups(ctx context.Context, arg ListBuyerOrderGroupsParams) ([]ListBuyerOrderGroupsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBuyerOrderGroups, arg.BuyerID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBuyerOrderGroupsRow{}
+	for rows.Next() {
+		var i ListBuyerOrderGroupsRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.OrderNumber,
+			&i.BuyerID,
+			&i.Reference,
+			&i.Status,
+			&i.ShippingAddress,
+			&i.Currency,
+			&i.Subtotal,
+			&i.DiscountTotal,
+			&i.DeliveryTotal,
+			&i.GrandTotal,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TaxTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFulfilmentGroupLines = `-- name: ListFulfilmentGroupLines :many
+SELECT
+  o.id AS order_id,
+  o.fulfilment_group_id,
+  o.item_id,
+  i.name AS item_name,
+  i.cover_img_url AS item_cover_img_url,
+  o.order_quantity,
+  o.item_price,
+  o.discount_amount,
+  o.delivery_fee,
+  o.tax_rate,
+  o.tax_inclusive,
+  o.tax_amount,
+  o.delivery_status,
+  o.is_reviewed
+FROM orders o
+JOIN items i ON o.item_id = i.id
+WHERE o.fulfilment_group_id = ANY($1::bigint[])
+ORDER BY o.id
+`
+
+type ListFulfilmentGroupLinesRow struct {
+	OrderID           int64         `json:"order_id"`
+	FulfilmentGroupID sql.NullInt64 `json:"fulfilment_group_id"`
+	ItemID            int64         `json:"item_id"`
+	ItemName          string        `json:"item_name"`
+	ItemCoverImgUrl   string        `json:"item_cover_img_url"`
+	OrderQuantity     int32         `json:"order_quantity"`
+	ItemPrice         string        `json:"item_price"`
+	DiscountAmount    string        `json:"discount_amount"`
+	DeliveryFee       string        `json:"delivery_fee"`
+	TaxRate           string        `json:"tax_rate"`
+	TaxInclusive      bool          `json:"tax_inclusive"`
+	TaxAmount         string        `json:"tax_amount"`
+	DeliveryStatus    string        `json:"delivery_status"`
+	IsReviewed        bool          `json:"is_reviewed"`
+}
+
+func (q *Queries) ListFulfilmentGroupLines(ctx context.Context, fulfilmentGroupIds []int64) ([]ListFulfilmentGroupLinesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFulfilmentGroupLines, pq.Array(fulfilmentGroupIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFulfilmentGroupLinesRow{}
+	for rows.Next() {
+		var i ListFulfilmentGroupLinesRow
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.FulfilmentGroupID,
+			&i.ItemID,
+			&i.ItemName,
+			&i.ItemCoverImgUrl,
+			&i.OrderQuantity,
+			&i.ItemPrice,
+			&i.DiscountAmount,
+			&i.DeliveryFee,
+			&i.TaxRate,
+			&i.TaxInclusive,
+			&i.TaxAmount,
+			&i.DeliveryStatus,
+			&i.IsReviewed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFulfilmentGroupOrders = `-- name: ListFulfilmentGroupOrders :many
+SELECT id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku FROM orders
+WHERE fulfilment_group_id = $1::bigint
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) ListFulfilmentGroupOrders(ctx context.Context, fulfilmentGroupID int64) ([]Order, error) {
+	rows, err := q.db.QueryContext(ctx, listFulfilmentGroupOrders, fulfilmentGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeliveryStatus,
+			&i.DeliveredOn,
+			&i.ExpectedDeliveryDate,
+			&i.ItemID,
+			&i.ItemPrice,
+			&i.ItemCurrency,
+			&i.OrderQuantity,
+			&i.BuyerID,
+			&i.SellerID,
+			&i.StoreID,
+			&i.DeliveryFee,
+			&i.PaymentChannel,
+			&i.PaymentMethod,
+			&i.IsReviewed,
+			&i.CreatedAt,
+			&i.FundsReleasedAt,
+			&i.CommissionRuleID,
+			&i.CommissionPercentage,
+			&i.CommissionFixed,
+			&i.CommissionAmount,
+			&i.CouponID,
+			&i.DiscountAmount,
+			&i.Currency,
+			&i.FxRate,
+			&i.FulfilmentGroupID,
+			&i.TaxRate,
+			&i.TaxInclusive,
+			&i.TaxAmount,
+			&i.VariantID,
+			&i.VariantSku,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderGroupFulfilmentGroups = `-- name: ListOrderGroupFulfilmentGroups :many
+SELECT id, order_group_id, store_id, seller_id, delivery_status, subtotal, discount, delivery_fee, total, carrier, tracking_number, shipped_at, expected_delivery_date, delivered_on, created_at, updated_at, tax FROM fulfilment_groups
+WHERE order_group_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListOrderGroupFulfilmentGroups(ctx context.Context, orderGroupID int64) ([]FulfilmentGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderGroupFulfilmentGroups, orderGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FulfilmentGroup{}
+	for rows.Next() {
+		var i FulfilmentGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderGroupID,
+			&i.StoreID,
+			&i.SellerID,
+			&i.DeliveryStatus,
+			&i.Subtotal,
+			&i.Discount,
+			&i.DeliveryFee,
+			&i.Total,
+			&i.Carrier,
+			&i.TrackingNumber,
+			&i.ShippedAt,
+			&i.ExpectedDeliveryDate,
+			&i.DeliveredOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Tax,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const placeOrderGroup = `-- name: PlaceOrderGroup :one
+UPDATE order_groups og
+SET
+  status = 'PLACED',
+  subtotal = f.subtotal,
+  discount_total = f.discount,
+  delivery_total = f.delivery_fee,
+  tax_total = f.tax,
+  grand_total = f.total,
+  updated_at = now()
+FROM (
+  SELECT
+    COALESCE(sum(subtotal), 0)::NUMERIC(18, 2) AS subtotal,
+    COALESCE(sum(discount), 0)::NUMERIC(18, 2) AS discount,
+    COALESCE(sum(delivery_fee), 0)::NUMERIC(18, 2) AS delivery_fee,
+    COALESCE(sum(tax), 0)::NUMERIC(18, 2) AS tax,
+    COALESCE(sum(total), 0)::NUMERIC(18, 2) AS total
+  FROM fulfilment_groups
+  WHERE order_group_id = $1
+) f
+WHERE og.id = $1
+RETURNING og.id, og.order_number, og.buyer_id, og.reference, og.status, og.shipping_address, og.currency, og.subtotal, og.discount_total, og.delivery_total, og.grand_total, og.created_at, og.updated_at, og.tax_total
+`
+
+// Marks an order group PLACED, totalling what its fulfilment groups cost.
+func (q *Queries) PlaceOrderGroup(ctx context.Context, orderGroupID int64) (OrderGroup, error) {
+	row := q.db.QueryRowContext(ctx, placeOrderGroup, orderGroupID)
+	var i OrderGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderNumber,
+		&i.BuyerID,
+		&i.Reference,
+		&i.Status,
+		&i.ShippingAddress,
+		&i.Currency,
+		&i.Subtotal,
+		&i.DiscountTotal,
+		&i.DeliveryTotal,
+		&i.GrandTotal,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxTotal,
+	)
+	return i, err
+}
+
+const syncFulfilmentGroup = `-- name: SyncFulfilmentGroup :one
+UPDATE fulfilment_groups fg
+SET
+  delivery_status = l.delivery_status,
+  shipped_at = CASE
+    WHEN fg.shipped_at = '0001-01-01 00:00:00Z' AND l.delivery_status IN ('SHIPPED', 'DELIVERED') THEN now()
+    ELSE fg.shipped_at
+  END,
+  delivered_on = CASE
+    WHEN l.delivery_status = 'DELIVERED' THEN l.delivered_on
+    ELSE fg.delivered_on
+  END,
+  updated_at = now()
+FROM (
+  SELECT
+    (array_agg(o.delivery_status ORDER BY array_position(
+      ARRAY['PENDING', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'RETURNED', 'CANCELLED']::varchar[],
+      o.delivery_status
+    )))[1]::varchar AS delivery_status,
+    max(o.delivered_on)::timestamptz AS delivered_on
+  FROM orders o
+  WHERE o.fulfilment_group_id = $1
+) l
+WHERE fg.id = $1
+RETURNING fg.id, fg.order_group_id, fg.store_id, fg.seller_id, fg.delivery_status, fg.subtotal, fg.discount, fg.delivery_fee, fg.total, fg.carrier, fg.tracking_number, fg.shipped_at, fg.expected_delivery_date, fg.delivered_on, fg.created_at, fg.updated_at, fg.tax
+`
+
+// Sets a fulfilment group's delivery_status to that of its least advanced
+// line, stamping when it first shipped and when its last line was delivered.
+func (q *Queries) SyncFulfilmentGroup(ctx context.Context, fulfilmentGroupID int64) (FulfilmentGroup, error) {
+	row := q.db.QueryRowContext(ctx, syncFulfilmentGroup, fulfilmentGroupID)
+	var i FulfilmentGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderGroupID,
+		&i.StoreID,
+		&i.SellerID,
+		&i.DeliveryStatus,
+		&i.Subtotal,
+		&i.Discount,
+		&i.DeliveryFee,
+		&i.Total,
+		&i.Carrier,
+		&i.TrackingNumber,
+		&i.ShippedAt,
+		&i.ExpectedDeliveryDate,
+		&i.DeliveredOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Tax,
+	)
+	return i, err
+}
+
+const updateFulfilmentGroupShipping = `-- name: UpdateFulfilmentGroupShipping :one
+UPDATE fulfilment_groups
+SET
+  carrier = COALESCE($1, carrier),
+  tracking_number = COALESCE($2, tracking_number),
+  expected_delivery_date = COALESCE($3, expected_delivery_date),
+  updated_at = now()
+WHERE id = $4
+RETURNING id, order_group_id, store_id, seller_id, delivery_status, subtotal, discount, delivery_fee, total, carrier, tracking_number, shipped_at, expected_delivery_date, delivered_on, created_at, updated_at, tax
+`
+
+type UpdateFulfilmentGroupShippingParams struct {
+	Carrier              sql.NullString `json:"carrier"`
+	TrackingNumber       sql.NullString `json:"tracking_number"`
+	ExpectedDeliveryDate sql.NullTime   `json:"expected_delivery_date"`
+	FulfilmentGroupID    int64          `json:"fulfilment_group_id"`
+}
+
+func (q *Queries) UpdateFulfilmentGroupShipping(ctx context.Context, arg UpdateFulfilmentGroupShippingParams) (FulfilmentGroup, error) {
+	row := q.db.QueryRowContext(ctx, updateFulfilmentGroupShipping,
+		arg.Carrier,
+		arg.TrackingNumber,
+		arg.ExpectedDeliveryDate,
+		arg.FulfilmentGroupID,
+	)
+	var i FulfilmentGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrderGroupID,
+		&i.StoreID,
+		&i.SellerID,
+		&i.DeliveryStatus,
+		&i.Subtotal,
+		&i.Discount,
+		&i.DeliveryFee,
+		&i.Total,
+		&i.Carrier,
+		&i.TrackingNumber,
+		&i.ShippedAt,
+		&i.ExpectedDeliveryDate,
+		&i.DeliveredOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Tax,
+	)
+	return i, err
+}
@@ -0,0 +1,435 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: payout.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const chargeBackCryptoAccount = `-- name: ChargeBackCryptoAccount :one
+UPDATE crypto_accounts
+SET balance = balance - $1::NUMERIC(18, 2)
+WHERE store_id = $2
+RETURNING id, store_id, balance, wallet_address, crypto_type, created_at
+`
+
+type ChargeBackCryptoAccountParams struct {
+	Amount  string `json:"amount"`
+	StoreID int64  `json:"store_id"`
+}
+
+func (q *Queries) ChargeBackCryptoAccount(ctx context.Context, arg ChargeBackCryptoAccountParams) (CryptoAccount, error) {
+	row := q.db.QueryRowContext(ctx, chargeBackCryptoAccount, arg.Amount, arg.StoreID)
+	var i CryptoAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.WalletAddress,
+		&i.CryptoType,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const chargeBackFiatAccount = `-- name: ChargeBackFiatAccount :one
+UPDATE fiat_accounts
+SET balance = balance - $1::NUMERIC(18, 2)
+WHERE store_id = $2
+RETURNING id, store_id, balance, currency, created_at
+`
+
+type ChargeBackFiatAccountParams struct {
+	Amount  string `json:"amount"`
+	StoreID int64  `json:"store_id"`
+}
+
+func (q *Queries) ChargeBackFiatAccount(ctx context.Context, arg ChargeBackFiatAccountParams) (FiatAccount, error) {
+	row := q.db.QueryRowContext(ctx, chargeBackFiatAccount, arg.Amount, arg.StoreID)
+	var i FiatAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWithdrawalRequest = `-- name: CreateWithdrawalRequest :one
+INSERT INTO withdrawal_requests (
+  store_id,
+  account_type,
+  amount,
+  destination,
+  requested_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, store_id, account_type, amount, destination, status, requested_by, note, created_at, updated_at
+`
+
+type CreateWithdrawalRequestParams struct {
+	StoreID     int64  `json:"store_id"`
+	AccountType string `json:"account_type"`
+	Amount      string `json:"amount"`
+	Destination string `json:"destination"`
+	RequestedBy int64  `json:"requested_by"`
+}
+
+func (q *Queries) CreateWithdrawalRequest(ctx context.Context, arg CreateWithdrawalRequestParams) (WithdrawalRequest, error) {
+	row := q.db.QueryRowContext(ctx, createWithdrawalRequest,
+		arg.StoreID,
+		arg.AccountType,
+		arg.Amount,
+		arg.Destination,
+		arg.RequestedBy,
+	)
+	var i WithdrawalRequest
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.AccountType,
+		&i.Amount,
+		&i.Destination,
+		&i.Status,
+		&i.RequestedBy,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const creditCryptoAccount = `-- name: CreditCryptoAccount :one
+UPDATE crypto_accounts
+SET balance = balance + $1::NUMERIC(18, 2)
+WHERE store_id = $2
+RETURNING id, store_id, balance, wallet_address, crypto_type, created_at
+`
+
+type CreditCryptoAccountParams struct {
+	Amount  string `json:"amount"`
+	StoreID int64  `json:"store_id"`
+}
+
+func (q *Queries) CreditCryptoAccount(ctx context.Context, arg CreditCryptoAccountParams) (CryptoAccount, error) {
+	row := q.db.QueryRowContext(ctx, creditCryptoAccount, arg.Amount, arg.StoreID)
+	var i CryptoAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.WalletAddress,
+		&i.CryptoType,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const creditFiatAccount = `-- name: CreditFiatAccount :one
+UPDATE fiat_accounts
+SET balance = balance + $1::NUMERIC(18, 2)
+WHERE store_id = $2
+RETURNING id, store_id, balance, currency, created_at
+`
+
+type CreditFiatAccountParams struct {
+	Amount  string `json:"amount"`
+	StoreID int64  `json:"store_id"`
+}
+
+func (q *Queries) CreditFiatAccount(ctx context.Context, arg CreditFiatAccountParams) (FiatAccount, error) {
+	row := q.db.QueryRowContext(ctx, creditFiatAccount, arg.Amount, arg.StoreID)
+	var i FiatAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const debitCryptoAccount = `-- name: DebitCryptoAccount :one
+UPDATE crypto_accounts
+SET balance = balance - $1::NUMERIC(18, 2)
+WHERE store_id = $2
+  AND balance >= $1::NUMERIC(18, 2)
+RETURNING id, store_id, balance, wallet_address, crypto_type, created_at
+`
+
+type DebitCryptoAccountParams struct {
+	Amount  string `json:"amount"`
+	StoreID int64  `json:"store_id"`
+}
+
+func (q *Queries) DebitCryptoAccount(ctx context.Context, arg DebitCryptoAccountParams) (CryptoAccount, error) {
+	row := q.db.QueryRowContext(ctx, debitCryptoAccount, arg.Amount, arg.StoreID)
+	var i CryptoAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.WalletAddress,
+		&i.CryptoType,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const debitFiatAccount = `-- name: DebitFiatAccount :one
+UPDATE fiat_accounts
+SET balance = balance - $1::NUMERIC(18, 2)
+WHERE store_id = $2
+  AND balance >= $1::NUMERIC(18, 2)
+RETURNING id, store_id, balance, currency, created_at
+`
+
+type DebitFiatAccountParams struct {
+	Amount  string `json:"amount"`
+	StoreID int64  `json:"store_id"`
+}
+
+func (q *Queries) DebitFiatAccount(ctx context.Context, arg DebitFiatAccountParams) (FiatAccount, error) {
+	row := q.db.QueryRowContext(ctx, debitFiatAccount, arg.Amount, arg.StoreID)
+	var i FiatAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStoreCryptoAccount = `-- name: GetStoreCryptoAccount :one
+SELECT id, store_id, balance, wallet_address, crypto_type, created_at FROM crypto_accounts
+WHERE store_id = $1
+`
+
+func (q *Queries) GetStoreCryptoAccount(ctx context.Context, storeID int64) (CryptoAccount, error) {
+	row := q.db.QueryRowContext(ctx, getStoreCryptoAccount, storeID)
+	var i CryptoAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.WalletAddress,
+		&i.CryptoType,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStoreFiatAccount = `-- name: GetStoreFiatAccount :one
+SELECT id, store_id, balance, currency, created_at FROM fiat_accounts
+WHERE store_id = $1
+`
+
+func (q *Queries) GetStoreFiatAccount(ctx context.Context, storeID int64) (FiatAccount, error) {
+	row := q.db.QueryRowContext(ctx, getStoreFiatAccount, storeID)
+	var i FiatAccount
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWithdrawalRequest = `-- name: GetWithdrawalRequest :one
+SELECT id, store_id, account_type, amount, destination, status, requested_by, note, created_at, updated_at FROM withdrawal_requests
+WHERE id = $1
+  AND store_id = $2
+`
+
+type GetWithdrawalRequestParams struct {
+	WithdrawalID int64 `json:"withdrawal_id"`
+	StoreID      int64 `json:"store_id"`
+}
+
+func (q *Queries) GetWithdrawalRequest(ctx context.Context, arg GetWithdrawalRequestParams) (WithdrawalRequest, error) {
+	row := q.db.QueryRowContext(ctx, getWithdrawalRequest, arg.WithdrawalID, arg.StoreID)
+	var i WithdrawalRequest
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.AccountType,
+		&i.Amount,
+		&i.Destination,
+		&i.Status,
+		&i.RequestedBy,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWithdrawalRequestForUpdate = `-- name: GetWithdrawalRequestForUpdate :one
+SELECT id, store_id, account_type, amount, destination, status, requested_by, note, created_at, updated_at FROM withdrawal_requests
+WHERE id = $1
+  AND store_id = $2
+FOR UPDATE
+`
+
+type GetWithdrawalRequestForUpdateParams struct {
+	WithdrawalID int64 `json:"withdrawal_id"`
+	StoreID      int64 `json:"store_id"`
+}
+
+func (q *Queries) GetWithdrawalRequestForUpdate(ctx context.Context, arg GetWithdrawalRequestForUpdateParams) (WithdrawalRequest, error) {
+	row := q.db.QueryRowContext(ctx, getWithdrawalRequestForUpdate, arg.WithdrawalID, arg.StoreID)
+	var i WithdrawalRequest
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.AccountType,
+		&i.Amount,
+		&i.Destination,
+		&i.Status,
+		&i.RequestedBy,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listWithdrawalRequests = `-- name: ListWithdrawalRequests :many
+SELECT
+  count(*) OVER() AS total_count,
+  wr.id, wr.store_id, wr.account_type, wr.amount, wr.destination, wr.status, wr.requested_by, wr.note, wr.created_at, wr.updated_at
+FROM withdrawal_requests wr
+WHERE wr.store_id = $1
+  AND ($2::varchar IS NULL OR wr.status = $2)
+ORDER BY wr.id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListWithdrawalRequestsParams struct {
+	StoreID  int64          `json:"store_id"`
+	Status   sql.NullString `json:"status"`
+	RwOffset int32          `json:"rw_offset"`
+	RwLimit  int32          `json:"rw_limit"`
+}
+
+type ListWithdrawalRequestsRow struct {
+	TotalCount  int64     `json:"total_count"`
+	ID          int64     `json:"id"`
+	StoreID     int64     `json:"store_id"`
+	AccountType string    `json:"account_type"`
+	Amount      string    `json:"amount"`
+	Destination string    `json:"destination"`
+	Status      string    `json:"status"`
+	RequestedBy int64     `json:"requested_by"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListWithdrawalRequests(ctx context.Context, arg ListWithdrawalRequestsParams) ([]ListWithdrawalRequestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWithdrawalRequests,
+		arg.StoreID,
+		arg.Status,
+		arg.RwOffset,
+		arg.RwLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWithdrawalRequestsRow{}
+	for rows.Next() {
+		var i ListWithdrawalRequestsRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.AccountType,
+			&i.Amount,
+			&i.Destination,
+			&i.Status,
+			&i.RequestedBy,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumPendingWithdrawals = `-- name: SumPendingWithdrawals :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(18, 2) AS total
+FROM withdrawal_requests
+WHERE store_id = $1
+  AND account_type = $2
+  AND status = 'PENDING'
+`
+
+type SumPendingWithdrawalsParams struct {
+	StoreID     int64  `json:"store_id"`
+	AccountType string `json:"account_type"`
+}
+
+func (q *Queries) SumPendingWithdrawals(ctx context.Context, arg SumPendingWithdrawalsParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, sumPendingWithdrawals, arg.StoreID, arg.AccountType)
+	var total string
+	err := row.Scan(&total)
+	return total, err
+}
+
+const updateWithdrawalRequestStatus = `-- name: UpdateWithdrawalRequestStatus :one
+UPDATE withdrawal_requests
+SET
+  status = $1,
+  note = $2,
+  updated_at = now()
+WHERE id = $3
+RETURNING id, store_id, account_type, amount, destination, status, requested_by, note, created_at, updated_at
+`
+
+type UpdateWithdrawalRequestStatusParams struct {
+	Status       string `json:"status"`
+	Note         string `json:"note"`
+	WithdrawalID int64  `json:"withdrawal_id"`
+}
+
+func (q *Queries) UpdateWithdrawalRequestStatus(ctx context.Context, arg UpdateWithdrawalRequestStatusParams) (WithdrawalRequest, error) {
+	row := q.db.QueryRowContext(ctx, updateWithdrawalRequestStatus, arg.Status, arg.Note, arg.WithdrawalID)
+	var i WithdrawalRequest
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.AccountType,
+		&i.Amount,
+		&i.Destination,
+		&i.Status,
+		&i.RequestedBy,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -6,69 +6,316 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddCartCoupon(ctx context.Context, arg AddCartCouponParams) (CartCoupon, error)
 	AddCoOwnerAccess(ctx context.Context, arg AddCoOwnerAccessParams) (StoreOwner, error)
+	AddFlashSaleSold(ctx context.Context, arg AddFlashSaleSoldParams) error
 	AddToCoOwnerAccess(ctx context.Context, arg AddToCoOwnerAccessParams) (StoreOwner, error)
+	// Adds amount, or takes it off if negative, to an ACTIVE gift card's balance.
+	AdjustGiftCardBalance(ctx context.Context, arg AdjustGiftCardBalanceParams) (GiftCard, error)
+	// Adds amount, or takes it off if negative, to a user's store credit.
+	AdjustStoreCredit(ctx context.Context, arg AdjustStoreCreditParams) (string, error)
+	AssignFulfilmentGroups(ctx context.Context, arg AssignFulfilmentGroupsParams) (int64, error)
+	// Cancels a flash sale that hasn't ended.
+	CancelFlashSale(ctx context.Context, arg CancelFlashSaleParams) (FlashSale, error)
+	ChargeBackCryptoAccount(ctx context.Context, arg ChargeBackCryptoAccountParams) (CryptoAccount, error)
+	ChargeBackFiatAccount(ctx context.Context, arg ChargeBackFiatAccountParams) (FiatAccount, error)
 	CheckItemStoreMatch(ctx context.Context, arg CheckItemStoreMatchParams) (int64, error)
 	CheckSessionExists(ctx context.Context, arg CheckSessionExistsParams) (bool, error)
+	ClearCart(ctx context.Context, cartID int64) error
+	ClearCartCoupons(ctx context.Context, cartID int64) error
+	CompleteCheckoutRefund(ctx context.Context, arg CompleteCheckoutRefundParams) (CheckoutRefund, error)
+	CompleteItemImport(ctx context.Context, arg CompleteItemImportParams) (ItemImport, error)
+	CompleteNEARTransfer(ctx context.Context, transferID int64) (NearTransfer, error)
+	CompleteRefund(ctx context.Context, arg CompleteRefundParams) (Refund, error)
+	CountCouponRedemptions(ctx context.Context, arg CountCouponRedemptionsParams) (CountCouponRedemptionsRow, error)
+	CountItemVariants(ctx context.Context, itemID int64) (int64, error)
+	CountOverlappingFlashSales(ctx context.Context, arg CountOverlappingFlashSalesParams) (int64, error)
 	CreateCartForUser(ctx context.Context, userID int64) error
+	CreateCheckoutCredit(ctx context.Context, arg CreateCheckoutCreditParams) (CheckoutCredit, error)
+	CreateCheckoutFxRate(ctx context.Context, arg CreateCheckoutFxRateParams) error
+	CreateCheckoutLine(ctx context.Context, arg CreateCheckoutLineParams) error
+	CreateCheckoutRefund(ctx context.Context, arg CreateCheckoutRefundParams) (CheckoutRefund, error)
+	CreateCommissionRule(ctx context.Context, arg CreateCommissionRuleParams) (CommissionRule, error)
+	CreateCoupon(ctx context.Context, arg CreateCouponParams) (Coupon, error)
+	CreateCouponRedemption(ctx context.Context, arg CreateCouponRedemptionParams) (CouponRedemption, error)
+	CreateFlashSale(ctx context.Context, arg CreateFlashSaleParams) (FlashSale, error)
+	CreateFlashSaleClaim(ctx context.Context, arg CreateFlashSaleClaimParams) (FlashSaleClaim, error)
+	// Groups orders by store under an order group, totalling what each store ships.
+	CreateFulfilmentGroups(ctx context.Context, arg CreateFulfilmentGroupsParams) ([]FulfilmentGroup, error)
+	CreateGiftCard(ctx context.Context, arg CreateGiftCardParams) (GiftCard, error)
+	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateItemImport(ctx context.Context, arg CreateItemImportParams) (ItemImport, error)
+	CreateItemImportError(ctx context.Context, arg CreateItemImportErrorParams) error
+	CreateItemImportFile(ctx context.Context, arg CreateItemImportFileParams) error
+	CreateItemOption(ctx context.Context, arg CreateItemOptionParams) (ItemOption, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateLedgerPosting(ctx context.Context, arg CreateLedgerPostingParams) error
+	CreateNEARTransfer(ctx context.Context, arg CreateNEARTransferParams) (NearTransfer, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
 	CreateOrderFn(ctx context.Context, arg CreateOrderFnParams) (Order, error)
+	CreateOrderGroup(ctx context.Context, arg CreateOrderGroupParams) (OrderGroup, error)
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
 	CreateReview(ctx context.Context, arg CreateReviewParams) error
 	CreateReviewFn(ctx context.Context, arg CreateReviewFnParams) error
 	CreateSale(ctx context.Context, arg CreateSaleParams) (Sale, error)
 	CreateSaleFn(ctx context.Context, arg CreateSaleFnParams) (Sale, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateShippingRate(ctx context.Context, arg CreateShippingRateParams) (ShippingRate, error)
+	CreateShippingZone(ctx context.Context, arg CreateShippingZoneParams) (ShippingZone, error)
+	CreateStockReservation(ctx context.Context, arg CreateStockReservationParams) (StockReservation, error)
 	CreateStore(ctx context.Context, arg CreateStoreParams) (Store, error)
+	CreateStoreCreditEntry(ctx context.Context, arg CreateStoreCreditEntryParams) (StoreCreditEntry, error)
 	CreateStoreItem(ctx context.Context, arg CreateStoreItemParams) (Item, error)
+	CreateTaxRule(ctx context.Context, arg CreateTaxRuleParams) (TaxRule, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWithdrawalRequest(ctx context.Context, arg CreateWithdrawalRequestParams) (WithdrawalRequest, error)
+	CreditCryptoAccount(ctx context.Context, arg CreditCryptoAccountParams) (CryptoAccount, error)
+	CreditFiatAccount(ctx context.Context, arg CreditFiatAccountParams) (FiatAccount, error)
+	DebitCryptoAccount(ctx context.Context, arg DebitCryptoAccountParams) (CryptoAccount, error)
+	DebitFiatAccount(ctx context.Context, arg DebitFiatAccountParams) (FiatAccount, error)
 	DecreaseCartItemQuantity(ctx context.Context, arg DecreaseCartItemQuantityParams) (CartItem, error)
-	DeductItemSupply(ctx context.Context, arg DeductItemSupplyParams) error
+	DeductItemSupply(ctx context.Context, arg DeductItemSupplyParams) (Item, error)
+	DeleteCommissionRule(ctx context.Context, ruleID int64) (int64, error)
+	DeleteCoupon(ctx context.Context, arg DeleteCouponParams) (int64, error)
 	DeleteExpiredSession(ctx context.Context) error
 	DeleteItem(ctx context.Context, arg DeleteItemParams) error
+	DeleteItemImportErrors(ctx context.Context, importID int64) error
+	DeleteItemImportFile(ctx context.Context, importID int64) error
+	DeleteItemOptions(ctx context.Context, itemID int64) error
+	DeleteItemVariantsExcept(ctx context.Context, arg DeleteItemVariantsExceptParams) error
+	DeleteNEARTx(ctx context.Context, taskID string) error
 	DeleteReview(ctx context.Context, arg DeleteReviewParams) error
+	DeleteShippingRates(ctx context.Context, shippingZoneID int64) error
+	DeleteShippingZone(ctx context.Context, arg DeleteShippingZoneParams) (int64, error)
 	DeleteStore(ctx context.Context, storeID int64) error
+	DeleteTaxRule(ctx context.Context, arg DeleteTaxRuleParams) (int64, error)
+	EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) (int64, error)
+	ExpireStockReservations(ctx context.Context, reference string) (int64, error)
+	FailCheckoutRefund(ctx context.Context, arg FailCheckoutRefundParams) (CheckoutRefund, error)
+	FailItemImport(ctx context.Context, arg FailItemImportParams) (ItemImport, error)
+	FailNEARTransfer(ctx context.Context, arg FailNEARTransferParams) (NearTransfer, error)
+	FailOrderGroup(ctx context.Context, reference string) error
+	FailRefund(ctx context.Context, arg FailRefundParams) (Refund, error)
+	GetActiveGiftCard(ctx context.Context, code string) (GiftCard, error)
+	GetActiveGiftCardForUpdate(ctx context.Context, code string) (GiftCard, error)
+	GetBuyerInvoice(ctx context.Context, arg GetBuyerInvoiceParams) (Invoice, error)
+	GetBuyerOrderGroup(ctx context.Context, arg GetBuyerOrderGroupParams) (OrderGroup, error)
 	GetCartByUserID(ctx context.Context, userID int64) ([]GetCartByUserIDRow, error)
 	GetCartID(ctx context.Context, userID int64) (int64, error)
+	GetCheckoutRefund(ctx context.Context, checkoutRefundID int64) (GetCheckoutRefundRow, error)
+	GetCheckoutRefundByTransaction(ctx context.Context, transactionID int64) (CheckoutRefund, error)
+	GetCommissionRule(ctx context.Context, ruleID int64) (CommissionRule, error)
+	GetCoupon(ctx context.Context, arg GetCouponParams) (Coupon, error)
+	GetCouponByCode(ctx context.Context, code string) (Coupon, error)
+	GetCouponForUpdate(ctx context.Context, couponID int64) (Coupon, error)
+	GetFlashSale(ctx context.Context, arg GetFlashSaleParams) (FlashSale, error)
+	// The units of an item its flash sales hold out of its available stock: those
+	// not sold yet of sales that haven't ended, and those claimed by checkouts
+	// not completed yet of sales that have.
+	GetFlashSaleHeldStock(ctx context.Context, itemID int64) (int64, error)
+	GetFxRate(ctx context.Context, currency string) (FxRate, error)
 	GetItem(ctx context.Context, itemID int64) (Item, error)
+	// The flash sale an item is on, or its next one.
+	GetItemFlashSale(ctx context.Context, itemID int64) (FlashSale, error)
+	GetItemImport(ctx context.Context, arg GetItemImportParams) (ItemImport, error)
+	GetItemImportByID(ctx context.Context, importID int64) (ItemImport, error)
+	GetItemImportFile(ctx context.Context, importID int64) ([]byte, error)
+	GetItemVariant(ctx context.Context, arg GetItemVariantParams) (ItemVariant, error)
+	GetItemVariantForUpdate(ctx context.Context, arg GetItemVariantForUpdateParams) (ItemVariant, error)
+	GetNEARTransfer(ctx context.Context, transferID int64) (NearTransfer, error)
+	GetNEARTransferForUpdate(ctx context.Context, transferID int64) (NearTransfer, error)
+	GetNEARTx(ctx context.Context, taskID string) (NearTx, error)
 	GetOrderForBuyer(ctx context.Context, arg GetOrderForBuyerParams) (GetOrderForBuyerRow, error)
 	GetOrderForSeller(ctx context.Context, arg GetOrderForSellerParams) (GetOrderForSellerRow, error)
+	GetOrderForUpdate(ctx context.Context, arg GetOrderForUpdateParams) (Order, error)
+	GetOrderGroupByReference(ctx context.Context, reference string) (OrderGroup, error)
+	GetOrderGroupByReferenceForUpdate(ctx context.Context, reference string) (OrderGroup, error)
+	GetOrderRefundTotals(ctx context.Context, orderID int64) (GetOrderRefundTotalsRow, error)
+	GetOrderTransaction(ctx context.Context, orderID int64) (Transaction, error)
 	GetPendingFunds(ctx context.Context, arg GetPendingFundsParams) (PendingTransactionFund, error)
+	GetRefund(ctx context.Context, refundID int64) (GetRefundRow, error)
+	GetRefundForUpdate(ctx context.Context, refundID int64) (Refund, error)
+	GetReservedStock(ctx context.Context, arg GetReservedStockParams) (int64, error)
+	GetReservedVariantStock(ctx context.Context, arg GetReservedVariantStockParams) (int64, error)
 	GetSale(ctx context.Context, arg GetSaleParams) (GetSaleRow, error)
+	GetSellerFulfilmentGroup(ctx context.Context, arg GetSellerFulfilmentGroupParams) (GetSellerFulfilmentGroupRow, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetShippingZone(ctx context.Context, arg GetShippingZoneParams) (ShippingZone, error)
 	GetStoreByID(ctx context.Context, storeID int64) (GetStoreByIDRow, error)
+	GetStoreCredit(ctx context.Context, userID int64) (string, error)
+	GetStoreCreditForUpdate(ctx context.Context, userID int64) (string, error)
+	GetStoreCryptoAccount(ctx context.Context, storeID int64) (CryptoAccount, error)
+	GetStoreDeliveryRule(ctx context.Context, storeID int64) (StoreDeliveryRule, error)
 	GetStoreDetails(ctx context.Context, storeID int64) (GetStoreDetailsRow, error)
+	GetStoreFiatAccount(ctx context.Context, storeID int64) (FiatAccount, error)
+	GetStoreInvoice(ctx context.Context, arg GetStoreInvoiceParams) (Invoice, error)
+	GetStoreItemBySKUForUpdate(ctx context.Context, arg GetStoreItemBySKUForUpdateParams) (Item, error)
 	GetStoreMetrics(ctx context.Context, storeID int64) (GetStoreMetricsRow, error)
 	GetStoreOwnersByStoreID(ctx context.Context, storeID int64) ([]StoreOwner, error)
+	GetTaxRule(ctx context.Context, arg GetTaxRuleParams) (TaxRule, error)
 	GetTransactionByRefID(ctx context.Context, providerTxRefID string) (Transaction, error)
+	GetTransactionByRefIDForUpdate(ctx context.Context, providerTxRefID string) (Transaction, error)
 	GetTransactionOrders(ctx context.Context, providerTxRefID string) ([]GetTransactionOrdersRow, error)
+	// What's been refunded of a transaction to destination, not counting failed refunds.
+	GetTransactionRefundedAmount(ctx context.Context, arg GetTransactionRefundedAmountParams) (string, error)
+	GetTrialBalance(ctx context.Context) ([]GetTrialBalanceRow, error)
 	GetUserAccessLevelsForStore(ctx context.Context, arg GetUserAccessLevelsForStoreParams) ([]int32, error)
 	GetUserByAccountID(ctx context.Context, accountID string) (User, error)
 	GetUserByEmail(ctx context.Context, userEmail string) (User, error)
 	GetUserByID(ctx context.Context, userID int64) (User, error)
+	GetWithdrawalRequest(ctx context.Context, arg GetWithdrawalRequestParams) (WithdrawalRequest, error)
+	GetWithdrawalRequestForUpdate(ctx context.Context, arg GetWithdrawalRequestForUpdateParams) (WithdrawalRequest, error)
 	HasMadePurchase(ctx context.Context, arg HasMadePurchaseParams) (bool, error)
 	IncreaseCartItemQuantity(ctx context.Context, arg IncreaseCartItemQuantityParams) (CartItem, error)
+	IsGiftCardPurchase(ctx context.Context, reference string) (bool, error)
+	ListApplicableCommissionRules(ctx context.Context, arg ListApplicableCommissionRulesParams) ([]CommissionRule, error)
+	ListBuyerInvoices(ctx context.Context, arg ListBuyerInvoicesParams) ([]ListBuyerInvoicesRow, error)
+	ListBuyerOrderGroups(ctx context.Context, arg ListBuyerOrderGroupsParams) ([]ListBuyerOrderGroupsRow, error)
+	ListCartCoupons(ctx context.Context, cartID int64) ([]Coupon, error)
+	// The flash sales running on the items in a user's cart, with how many of
+	// each item the cart holds, in all its variants.
+	ListCartFlashSales(ctx context.Context, userID int64) ([]ListCartFlashSalesRow, error)
+	ListCheckoutCredits(ctx context.Context, arg ListCheckoutCreditsParams) ([]CheckoutCredit, error)
+	// The flash sales claimed for the checkout under reference, whether or not
+	// they've ended since.
+	ListCheckoutFlashSales(ctx context.Context, arg ListCheckoutFlashSalesParams) ([]FlashSale, error)
+	ListCheckoutFxRates(ctx context.Context, reference string) ([]CheckoutFxRate, error)
+	ListCheckoutLines(ctx context.Context, reference string) ([]CheckoutLine, error)
+	ListCommissionRules(ctx context.Context, arg ListCommissionRulesParams) ([]CommissionRule, error)
+	// Flash sales that have started and not been reconciled since they ended.
+	ListFlashSalesToReconcile(ctx context.Context, rwLimit int32) ([]FlashSale, error)
+	ListFulfilmentGroupLines(ctx context.Context, fulfilmentGroupIds []int64) ([]ListFulfilmentGroupLinesRow, error)
+	ListFulfilmentGroupOrders(ctx context.Context, fulfilmentGroupID int64) ([]Order, error)
+	ListFxRates(ctx context.Context) ([]FxRate, error)
+	ListFxRatesFor(ctx context.Context, currencies []string) ([]FxRate, error)
+	ListItemImportErrors(ctx context.Context, importID int64) ([]ItemImportError, error)
+	ListItemOptions(ctx context.Context, itemID int64) ([]ItemOption, error)
+	ListItemVariants(ctx context.Context, itemID int64) ([]ItemVariant, error)
+	// Items at or below their threshold, or that sold more in the last week than
+	// they have left, of a store or of every store.
+	ListItemsAtRisk(ctx context.Context, storeID sql.NullInt64) ([]ListItemsAtRiskRow, error)
+	ListLiveFlashSales(ctx context.Context, itemIds []int64) ([]FlashSale, error)
+	ListOrderGroupFulfilmentGroups(ctx context.Context, orderGroupID int64) ([]FulfilmentGroup, error)
+	ListOrderRefunds(ctx context.Context, arg ListOrderRefundsParams) ([]Refund, error)
+	ListPurchasedGiftCards(ctx context.Context, arg ListPurchasedGiftCardsParams) ([]ListPurchasedGiftCardsRow, error)
+	ListRedeemedCoupons(ctx context.Context, arg ListRedeemedCouponsParams) ([]Coupon, error)
+	// The stock of each variant of an item held for checkouts.
+	ListReservedVariantStock(ctx context.Context, itemID int64) ([]ListReservedVariantStockRow, error)
+	ListShippingRates(ctx context.Context, shippingZoneIds []int64) ([]ShippingRate, error)
+	ListShippingZones(ctx context.Context, storeID int64) ([]ShippingZone, error)
+	ListStockMovements(ctx context.Context, arg ListStockMovementsParams) ([]ListStockMovementsRow, error)
+	ListStockMovementsByReference(ctx context.Context, arg ListStockMovementsByReferenceParams) ([]StockMovement, error)
+	ListStoreCoupons(ctx context.Context, arg ListStoreCouponsParams) ([]ListStoreCouponsRow, error)
+	ListStoreCreditEntries(ctx context.Context, arg ListStoreCreditEntriesParams) ([]ListStoreCreditEntriesRow, error)
+	ListStoreDeliveryRules(ctx context.Context, storeIds []int64) ([]StoreDeliveryRule, error)
+	ListStoreFlashSales(ctx context.Context, arg ListStoreFlashSalesParams) ([]ListStoreFlashSalesRow, error)
+	ListStoreInvoices(ctx context.Context, arg ListStoreInvoicesParams) ([]ListStoreInvoicesRow, error)
+	// Lists a page of a store's items in id order, after the item after_id.
+	ListStoreItemsAfter(ctx context.Context, arg ListStoreItemsAfterParams) ([]Item, error)
+	ListStoreLedgerBalances(ctx context.Context, storeID int64) ([]ListStoreLedgerBalancesRow, error)
+	ListStoreLedgerEntries(ctx context.Context, arg ListStoreLedgerEntriesParams) ([]ListStoreLedgerEntriesRow, error)
 	ListStorePendingFunds(ctx context.Context, arg ListStorePendingFundsParams) ([]ListStorePendingFundsRow, error)
+	// The zones of every store in store_ids in country.
+	ListStoreShippingZones(ctx context.Context, arg ListStoreShippingZonesParams) ([]ShippingZone, error)
+	// Emails of a store's staff holding any of access_levels.
+	ListStoreStaffEmails(ctx context.Context, arg ListStoreStaffEmailsParams) ([]string, error)
+	// The rules of every store in store_ids taxing sales shipped to country.
+	ListStoreTaxRules(ctx context.Context, arg ListStoreTaxRulesParams) ([]TaxRule, error)
+	ListStuckTransactions(ctx context.Context, arg ListStuckTransactionsParams) ([]Transaction, error)
+	ListTaxRules(ctx context.Context, storeID int64) ([]TaxRule, error)
+	ListUnbalancedJournalEntries(ctx context.Context) ([]ListUnbalancedJournalEntriesRow, error)
+	// RELEASED claims whose units haven't been given back in Redis yet.
+	ListUnreturnedFlashSaleClaims(ctx context.Context, rwLimit int32) ([]ListUnreturnedFlashSaleClaimsRow, error)
+	ListUnsentInvoices(ctx context.Context, reference string) ([]Invoice, error)
+	ListUnsentStockAlerts(ctx context.Context, rwLimit int32) ([]ListUnsentStockAlertsRow, error)
+	// A store's flash sales that are running or yet to start.
+	ListUpcomingFlashSales(ctx context.Context, arg ListUpcomingFlashSalesParams) ([]ListUpcomingFlashSalesRow, error)
+	ListWithdrawalRequests(ctx context.Context, arg ListWithdrawalRequestsParams) ([]ListWithdrawalRequestsRow, error)
 	LogAction(ctx context.Context, arg LogActionParams) error
+	// Takes a store's next invoice number, holding its sequence row until the
+	// transaction ends.
+	NextInvoiceNumber(ctx context.Context, storeID int64) (int64, error)
+	// Marks an order group PLACED, totalling what its fulfilment groups cost.
+	PlaceOrderGroup(ctx context.Context, orderGroupID int64) (OrderGroup, error)
 	ProcessTransaction(ctx context.Context, arg ProcessTransactionParams) (Transaction, error)
 	RatingOverview(ctx context.Context, storeID int64) (RatingOverviewRow, error)
+	ReducePendingFunds(ctx context.Context, arg ReducePendingFundsParams) (PendingTransactionFund, error)
 	ReduceSalesOverview(ctx context.Context, arg ReduceSalesOverviewParams) error
-	ReleaseFunds(ctx context.Context, orderID int64) error
+	ReleaseFunds(ctx context.Context, orderID int64) (string, error)
+	RemoveCartCoupon(ctx context.Context, arg RemoveCartCouponParams) (int64, error)
+	// Takes the lines bought under reference out of a cart, leaving what was
+	// added to it since.
+	RemoveCheckoutLinesFromCart(ctx context.Context, arg RemoveCheckoutLinesFromCartParams) error
+	// Removes an item from a cart, only in a variant when one is given.
 	RemoveItemFromCart(ctx context.Context, arg RemoveItemFromCartParams) error
+	RestockItem(ctx context.Context, arg RestockItemParams) error
+	RestockItemVariant(ctx context.Context, arg RestockItemVariantParams) error
 	RevokeAccess(ctx context.Context, arg RevokeAccessParams) error
 	RevokeAllAccess(ctx context.Context, arg RevokeAllAccessParams) error
+	// Saves the transaction a task sends, replacing one that can no longer execute.
+	SaveNEARTx(ctx context.Context, arg SaveNEARTxParams) (NearTx, error)
+	SetFlashSaleClaimReturned(ctx context.Context, claimID int64) error
+	SetFlashSaleClaimedQuantity(ctx context.Context, arg SetFlashSaleClaimedQuantityParams) error
+	SetInvoiceEmailed(ctx context.Context, invoiceID int64) error
+	// Sets the threshold an item runs low at; NULL leaves it to its store's.
+	SetItemReorderThreshold(ctx context.Context, arg SetItemReorderThresholdParams) (Item, error)
+	SetItemSupply(ctx context.Context, arg SetItemSupplyParams) (Item, error)
+	SetItemVariantSupply(ctx context.Context, arg SetItemVariantSupplyParams) (ItemVariant, error)
+	// Saves the transaction a PENDING transfer is sent in, or clears it with a
+	// NULL signed_tx once it can no longer execute.
+	SetNEARTransferSignedTx(ctx context.Context, arg SetNEARTransferSignedTxParams) (NearTransfer, error)
+	SetOrderCoupon(ctx context.Context, arg SetOrderCouponParams) (Order, error)
+	SetOrderExpectedDeliveryDate(ctx context.Context, arg SetOrderExpectedDeliveryDateParams) (Order, error)
+	SetOrderFxRate(ctx context.Context, arg SetOrderFxRateParams) (Order, error)
+	SetOrderTax(ctx context.Context, arg SetOrderTaxParams) (Order, error)
+	SetOrderVariant(ctx context.Context, arg SetOrderVariantParams) (Order, error)
+	SetStockAlertsSent(ctx context.Context, alertIds []int64) error
+	// Sets the reason, user, reference and note of the stock movements recorded
+	// for the rest of the transaction. An empty reason leaves the trigger to
+	// tell INITIAL from ADJUSTMENT, an empty user_id records no user.
+	SetStockMovementContext(ctx context.Context, arg SetStockMovementContextParams) error
+	SetTransactionProviderTxHash(ctx context.Context, arg SetTransactionProviderTxHashParams) (Transaction, error)
+	// Marks an import PROCESSING, unless it's already COMPLETED or FAILED. A
+	// PROCESSING import is started again when its task is retried.
+	StartItemImport(ctx context.Context, importID int64) (ItemImport, error)
+	// Sets an item's supply to the sum of its variants'.
+	SumItemVariantsSupply(ctx context.Context, itemID int64) (Item, error)
+	SumPendingWithdrawals(ctx context.Context, arg SumPendingWithdrawalsParams) (string, error)
+	// Sets a fulfilment group's delivery_status to that of its least advanced
+	// line, stamping when it first shipped and when its last line was delivered.
+	SyncFulfilmentGroup(ctx context.Context, fulfilmentGroupID int64) (FulfilmentGroup, error)
 	UpdateBuyerOrder(ctx context.Context, arg UpdateBuyerOrderParams) (Order, error)
+	UpdateCheckoutCreditsStatus(ctx context.Context, arg UpdateCheckoutCreditsStatusParams) ([]CheckoutCredit, error)
+	UpdateCommissionRule(ctx context.Context, arg UpdateCommissionRuleParams) (CommissionRule, error)
+	UpdateCoupon(ctx context.Context, arg UpdateCouponParams) (Coupon, error)
+	UpdateCouponRedemptionsStatus(ctx context.Context, arg UpdateCouponRedemptionsStatusParams) (int64, error)
+	UpdateFlashSaleClaimsStatus(ctx context.Context, arg UpdateFlashSaleClaimsStatusParams) ([]FlashSaleClaim, error)
+	UpdateFulfilmentGroupShipping(ctx context.Context, arg UpdateFulfilmentGroupShippingParams) (FulfilmentGroup, error)
+	// Settles the gift card bought under reference, once its transaction
+	// completes or fails.
+	UpdateGiftCardPurchaseStatus(ctx context.Context, arg UpdateGiftCardPurchaseStatusParams) (GiftCard, error)
 	UpdateItem(ctx context.Context, arg UpdateItemParams) (Item, error)
 	UpdateSellerOrder(ctx context.Context, arg UpdateSellerOrderParams) (Order, error)
+	UpdateShippingZone(ctx context.Context, arg UpdateShippingZoneParams) (ShippingZone, error)
+	UpdateStockReservationsStatus(ctx context.Context, arg UpdateStockReservationsStatusParams) (int64, error)
 	UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error)
+	UpdateTaxRule(ctx context.Context, arg UpdateTaxRuleParams) (TaxRule, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserReview(ctx context.Context, arg UpdateUserReviewParams) (Review, error)
+	UpdateWithdrawalRequestStatus(ctx context.Context, arg UpdateWithdrawalRequestStatusParams) (WithdrawalRequest, error)
 	UpsertCartItem(ctx context.Context, arg UpsertCartItemParams) (CartItem, error)
+	UpsertFxRate(ctx context.Context, arg UpsertFxRateParams) (FxRate, error)
+	// Creates a variant of an item, or updates the item's variant with its SKU.
+	// Returns no row if another item of the store has the SKU.
+	UpsertItemVariant(ctx context.Context, arg UpsertItemVariantParams) (ItemVariant, error)
+	UpsertStoreDeliveryRule(ctx context.Context, arg UpsertStoreDeliveryRuleParams) (StoreDeliveryRule, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,457 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: refund.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const completeRefund = `-- name: CompleteRefund :one
+UPDATE refunds
+SET
+  status = 'COMPLETED',
+  provider_refund_id = $1,
+  updated_at = now()
+WHERE id = $2
+RETURNING id, order_id, transaction_id, store_id, amount, restock_quantity, reason, status, provider_refund_id, failure_reason, requested_by, created_at, updated_at, commission_amount, destination
+`
+
+type CompleteRefundParams struct {
+	ProviderRefundID string `json:"provider_refund_id"`
+	RefundID         int64  `json:"refund_id"`
+}
+
+func (q *Queries) CompleteRefund(ctx context.Context, arg CompleteRefundParams) (Refund, error) {
+	row := q.db.QueryRowContext(ctx, completeRefund, arg.ProviderRefundID, arg.RefundID)
+	var i Refund
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.TransactionID,
+		&i.StoreID,
+		&i.Amount,
+		&i.RestockQuantity,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CommissionAmount,
+		&i.Destination,
+	)
+	return i, err
+}
+
+const createRefund = `-- name: CreateRefund :one
+INSERT INTO refunds (
+  order_id,
+  transaction_id,
+  store_id,
+  amount,
+  commission_amount,
+  restock_quantity,
+  reason,
+  requested_by,
+  destination
+) VALUES (
+  $1, $2, $3, $4,
+  $5, $6, $7, $8,
+  $9
+)
+RETURNING id, order_id, transaction_id, store_id, amount, restock_quantity, reason, status, provider_refund_id, failure_reason, requested_by, created_at, updated_at, commission_amount, destination
+`
+
+type CreateRefundParams struct {
+	OrderID          int64  `json:"order_id"`
+	TransactionID    int64  `json:"transaction_id"`
+	StoreID          int64  `json:"store_id"`
+	Amount           string `json:"amount"`
+	CommissionAmount string `json:"commission_amount"`
+	RestockQuantity  int32  `json:"restock_quantity"`
+	Reason           string `json:"reason"`
+	RequestedBy      int64  `json:"requested_by"`
+	Destination      string `json:"destination"`
+}
+
+func (q *Queries) CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error) {
+	row := q.db.QueryRowContext(ctx, createRefund,
+		arg.OrderID,
+		arg.TransactionID,
+		arg.StoreID,
+		arg.Amount,
+		arg.CommissionAmount,
+		arg.RestockQuantity,
+		arg.Reason,
+		arg.RequestedBy,
+		arg.Destination,
+	)
+	var i Refund
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.TransactionID,
+		&i.StoreID,
+		&i.Amount,
+		&i.RestockQuantity,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CommissionAmount,
+		&i.Destination,
+	)
+	return i, err
+}
+
+const failRefund = `-- name: FailRefund :one
+UPDATE refunds
+SET
+  status = 'FAILED',
+  failure_reason = $1,
+  updated_at = now()
+WHERE id = $2
+  AND status = 'PENDING'
+RETURNING id, order_id, transaction_id, store_id, amount, restock_quantity, reason, status, provider_refund_id, failure_reason, requested_by, created_at, updated_at, commission_amount, destination
+`
+
+type FailRefundParams struct {
+	FailureReason string `json:"failure_reason"`
+	RefundID      int64  `json:"refund_id"`
+}
+
+func (q *Queries) FailRefund(ctx context.Context, arg FailRefundParams) (Refund, error) {
+	row := q.db.QueryRowContext(ctx, failRefund, arg.FailureReason, arg.RefundID)
+	var i Refund
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.TransactionID,
+		&i.StoreID,
+		&i.Amount,
+		&i.RestockQuantity,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CommissionAmount,
+		&i.Destination,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku FROM orders
+WHERE id = $1
+  AND store_id = $2
+FOR UPDATE
+`
+
+type GetOrderForUpdateParams struct {
+	OrderID int64 `json:"order_id"`
+	StoreID int64 `json:"store_id"`
+}
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, arg GetOrderForUpdateParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, getOrderForUpdate, arg.OrderID, arg.StoreID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryStatus,
+		&i.DeliveredOn,
+		&i.ExpectedDeliveryDate,
+		&i.ItemID,
+		&i.ItemPrice,
+		&i.ItemCurrency,
+		&i.OrderQuantity,
+		&i.BuyerID,
+		&i.SellerID,
+		&i.StoreID,
+		&i.DeliveryFee,
+		&i.PaymentChannel,
+		&i.PaymentMethod,
+		&i.IsReviewed,
+		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
+	)
+	return i, err
+}
+
+const getOrderRefundTotals = `-- name: GetOrderRefundTotals :one
+SELECT
+  COALESCE(SUM(amount), 0)::NUMERIC(18, 2) AS amount,
+  COALESCE(SUM(restock_quantity), 0)::int AS restock_quantity
+FROM refunds
+WHERE order_id = $1
+  AND status != 'FAILED'
+`
+
+type GetOrderRefundTotalsRow struct {
+	Amount          string `json:"amount"`
+	RestockQuantity int32  `json:"restock_quantity"`
+}
+
+func (q *Queries) GetOrderRefundTotals(ctx context.Context, orderID int64) (GetOrderRefundTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getOrderRefundTotals, orderID)
+	var i GetOrderRefundTotalsRow
+	err := row.Scan(&i.Amount, &i.RestockQuantity)
+	return i, err
+}
+
+const getOrderTransaction = `-- name: GetOrderTransaction :one
+SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount FROM transactions
+WHERE order_ids @> ARRAY[$1::bigint]
+  AND status = 'COMPLETED'
+`
+
+func (q *Queries) GetOrderTransaction(ctx context.Context, orderID int64) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, getOrderTransaction, orderID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		pq.Array(&i.OrderIds),
+		&i.CustomerID,
+		&i.Amount,
+		&i.PaymentProvider,
+		&i.ProviderTxRefID,
+		&i.ProviderTxAccessCode,
+		&i.ProviderTxFee,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ProviderTxHash,
+		&i.NearAmount,
+	)
+	return i, err
+}
+
+const getRefund = `-- name: GetRefund :one
+SELECT
+  r.id, r.order_id, r.transaction_id, r.store_id, r.amount, r.restock_quantity, r.reason, r.status, r.provider_refund_id, r.failure_reason, r.requested_by, r.created_at, r.updated_at, r.commission_amount, r.destination,
+  t.provider_tx_ref_id,
+  t.payment_provider
+FROM refunds r
+JOIN transactions t ON t.id = r.transaction_id
+WHERE r.id = $1
+`
+
+type GetRefundRow struct {
+	ID               int64     `json:"id"`
+	OrderID          int64     `json:"order_id"`
+	TransactionID    int64     `json:"transaction_id"`
+	StoreID          int64     `json:"store_id"`
+	Amount           string    `json:"amount"`
+	RestockQuantity  int32     `json:"restock_quantity"`
+	Reason           string    `json:"reason"`
+	Status           string    `json:"status"`
+	ProviderRefundID string    `json:"provider_refund_id"`
+	FailureReason    string    `json:"failure_reason"`
+	RequestedBy      int64     `json:"requested_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	CommissionAmount string    `json:"commission_amount"`
+	Destination      string    `json:"destination"`
+	ProviderTxRefID  string    `json:"provider_tx_ref_id"`
+	PaymentProvider  string    `json:"payment_provider"`
+}
+
+func (q *Queries) GetRefund(ctx context.Context, refundID int64) (GetRefundRow, error) {
+	row := q.db.QueryRowContext(ctx, getRefund, refundID)
+	var i GetRefundRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.TransactionID,
+		&i.StoreID,
+		&i.Amount,
+		&i.RestockQuantity,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CommissionAmount,
+		&i.Destination,
+		&i.ProviderTxRefID,
+		&i.PaymentProvider,
+	)
+	return i, err
+}
+
+const getRefundForUpdate = `-- name: GetRefundForUpdate :one
+SELECT id, order_id, transaction_id, store_id, amount, restock_quantity, reason, status, provider_refund_id, failure_reason, requested_by, created_at, updated_at, commission_amount, destination FROM refunds
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetRefundForUpdate(ctx context.Context, refundID int64) (Refund, error) {
+	row := q.db.QueryRowContext(ctx, getRefundForUpdate, refundID)
+	var i Refund
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.TransactionID,
+		&i.StoreID,
+		&i.Amount,
+		&i.RestockQuantity,
+		&i.Reason,
+		&i.Status,
+		&i.ProviderRefundID,
+		&i.FailureReason,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CommissionAmount,
+		&i.Destination,
+	)
+	return i, err
+}
+
+const getTransactionRefundedAmount = `-- name: GetTransactionRefundedAmount :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(18, 2) AS amount
+FROM refunds
+WHERE transaction_id = $1
+  AND destination = $2
+  AND status != 'FAILED'
+`
+
+type GetTransactionRefundedAmountParams struct {
+	TransactionID int64  `json:"transaction_id"`
+	Destination   string `json:"destination"`
+}
+
+// What's been refunded of a transaction to destination, not counting failed refunds.
+func (q *Queries) GetTransactionRefundedAmount(ctx context.Context, arg GetTransactionRefundedAmountParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getTransactionRefundedAmount, arg.TransactionID, arg.Destination)
+	var amount string
+	err := row.Scan(&amount)
+	return amount, err
+}
+
+const listOrderRefunds = `-- name: ListOrderRefunds :many
+SELECT id, order_id, transaction_id, store_id, amount, restock_quantity, reason, status, provider_refund_id, failure_reason, requested_by, created_at, updated_at, commission_amount, destination FROM refunds
+WHERE order_id = $1
+  AND store_id = $2
+ORDER BY id DESC
+`
+
+type ListOrderRefundsParams struct {
+	OrderID int64 `json:"order_id"`
+	StoreID int64 `json:"store_id"`
+}
+
+func (q *Queries) ListOrderRefunds(ctx context.Context, arg ListOrderRefundsParams) ([]Refund, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderRefunds, arg.OrderID, arg.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Refund{}
+	for rows.Next() {
+		var i Refund
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.TransactionID,
+			&i.StoreID,
+			&i.Amount,
+			&i.RestockQuantity,
+			&i.Reason,
+			&i.Status,
+			&i.ProviderRefundID,
+			&i.FailureReason,
+			&i.RequestedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CommissionAmount,
+			&i.Destination,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reducePendingFunds = `-- name: ReducePendingFunds :one
+UPDATE pending_transaction_funds
+SET
+  amount = amount - $1::NUMERIC(18, 2),
+  updated_at = now()
+WHERE store_id = $2
+  AND account_type = $3
+  AND amount >= $1::NUMERIC(18, 2)
+RETURNING id, store_id, account_type, amount, updated_at, created_at
+`
+
+type ReducePendingFundsParams struct {
+	Amount      string `json:"amount"`
+	StoreID     int64  `json:"store_id"`
+	AccountType string `json:"account_type"`
+}
+
+func (q *Queries) ReducePendingFunds(ctx context.Context, arg ReducePendingFundsParams) (PendingTransactionFund, error) {
+	row := q.db.QueryRowContext(ctx, reducePendingFunds, arg.Amount, arg.StoreID, arg.AccountType)
+	var i PendingTransactionFund
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.AccountType,
+		&i.Amount,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const restockItem = `-- name: RestockItem :exec
+UPDATE items
+SET
+  supply_quantity = supply_quantity + $1::bigint,
+  updated_at = now()
+WHERE id = $2
+`
+
+type RestockItemParams struct {
+	Quantity int64 `json:"quantity"`
+	ItemID   int64 `json:"item_id"`
+}
+
+func (q *Queries) RestockItem(ctx context.Context, arg RestockItemParams) error {
+	_, err := q.db.ExecContext(ctx, restockItem, arg.Quantity, arg.ItemID)
+	return err
+}
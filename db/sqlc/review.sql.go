@@ -15,21 +15,23 @@ SELECT create_review FROM create_review(
   $1,
   $2,
   $3,
-  $4,
+  $4::bigint,
   $5,
   $6,
-  $7
+  $7,
+  $8
 )
 `
 
 type CreateReviewParams struct {
-	StoreID            int64  `json:"store_id"`
-	UserID             int64  `json:"user_id"`
-	ItemID             int64  `json:"item_id"`
-	Rating             string `json:"rating"`
-	ReviewType         string `json:"review_type"`
-	Comment            string `json:"comment"`
-	IsVerifiedPurchase bool   `json:"is_verified_purchase"`
+	StoreID            int64         `json:"store_id"`
+	UserID             int64         `json:"user_id"`
+	ItemID             int64         `json:"item_id"`
+	VariantID          sql.NullInt64 `json:"variant_id"`
+	Rating             string        `json:"rating"`
+	ReviewType         string        `json:"review_type"`
+	Comment            string        `json:"comment"`
+	IsVerifiedPurchase bool          `json:"is_verified_purchase"`
 }
 
 func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) error {
@@ -37,6 +39,7 @@ func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) erro
 		arg.StoreID,
 		arg.UserID,
 		arg.ItemID,
+		arg.VariantID,
 		arg.Rating,
 		arg.ReviewType,
 		arg.Comment,
@@ -155,7 +158,7 @@ SET
 WHERE 
   id = $3 
   AND user_id = $4
-RETURNING id, store_id, user_id, item_id, rating, review_type, comment, is_verified_purchase, created_at, updated_at
+RETURNING id, store_id, user_id, item_id, rating, review_type, comment, is_verified_purchase, created_at, updated_at, variant_id
 `
 
 type UpdateUserReviewParams struct {
@@ -184,6 +187,7 @@ func (q *Queries) UpdateUserReview(ctx context.Context, arg UpdateUserReviewPara
 		&i.IsVerifiedPurchase,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.VariantID,
 	)
 	return i, err
 }
@@ -103,7 +103,17 @@ SELECT
   u.account_id AS customer_account_id,
   s.order_id,
   o.created_at AS order_date,
-  o.delivered_on AS delivery_date
+  o.delivered_on AS delivery_date,
+  o.item_price AS order_unit_price,
+  o.order_quantity,
+  o.delivery_fee,
+  o.discount_amount,
+  o.tax_rate,
+  o.tax_inclusive,
+  o.tax_amount,
+  o.commission_percentage,
+  o.commission_fixed,
+  o.commission_amount
 FROM 
   sales s
 JOIN
@@ -125,18 +135,28 @@ type GetSaleParams struct {
 }
 
 type GetSaleRow struct {
-	SaleID            int64     `json:"sale_id"`
-	StoreID           int64     `json:"store_id"`
-	CreatedAt         time.Time `json:"created_at"`
-	ItemID            int64     `json:"item_id"`
-	ItemName          string    `json:"item_name"`
-	ItemPrice         string    `json:"item_price"`
-	ItemCoverImgUrl   string    `json:"item_cover_img_url"`
-	CustomerID        int64     `json:"customer_id"`
-	CustomerAccountID string    `json:"customer_account_id"`
-	OrderID           int64     `json:"order_id"`
-	OrderDate         time.Time `json:"order_date"`
-	DeliveryDate      time.Time `json:"delivery_date"`
+	SaleID               int64     `json:"sale_id"`
+	StoreID              int64     `json:"store_id"`
+	CreatedAt            time.Time `json:"created_at"`
+	ItemID               int64     `json:"item_id"`
+	ItemName             string    `json:"item_name"`
+	ItemPrice            string    `json:"item_price"`
+	ItemCoverImgUrl      string    `json:"item_cover_img_url"`
+	CustomerID           int64     `json:"customer_id"`
+	CustomerAccountID    string    `json:"customer_account_id"`
+	OrderID              int64     `json:"order_id"`
+	OrderDate            time.Time `json:"order_date"`
+	DeliveryDate         time.Time `json:"delivery_date"`
+	OrderUnitPrice       string    `json:"order_unit_price"`
+	OrderQuantity        int32     `json:"order_quantity"`
+	DeliveryFee          string    `json:"delivery_fee"`
+	DiscountAmount       string    `json:"discount_amount"`
+	TaxRate              string    `json:"tax_rate"`
+	TaxInclusive         bool      `json:"tax_inclusive"`
+	TaxAmount            string    `json:"tax_amount"`
+	CommissionPercentage string    `json:"commission_percentage"`
+	CommissionFixed      string    `json:"commission_fixed"`
+	CommissionAmount     string    `json:"commission_amount"`
 }
 
 func (q *Queries) GetSale(ctx context.Context, arg GetSaleParams) (GetSaleRow, error) {
@@ -155,6 +175,16 @@ func (q *Queries) GetSale(ctx context.Context, arg GetSaleParams) (GetSaleRow, e
 		&i.OrderID,
 		&i.OrderDate,
 		&i.DeliveryDate,
+		&i.OrderUnitPrice,
+		&i.OrderQuantity,
+		&i.DeliveryFee,
+		&i.DiscountAmount,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
 	)
 	return i, err
 }
@@ -0,0 +1,398 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: shipping_zone.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const createShippingRate = `-- name: CreateShippingRate :one
+INSERT INTO shipping_rates (
+  shipping_zone_id,
+  from_quantity,
+  fee
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, shipping_zone_id, from_quantity, fee
+`
+
+type CreateShippingRateParams struct {
+	ShippingZoneID int64  `json:"shipping_zone_id"`
+	FromQuantity   int64  `json:"from_quantity"`
+	Fee            string `json:"fee"`
+}
+
+func (q *Queries) CreateShippingRate(ctx context.Context, arg CreateShippingRateParams) (ShippingRate, error) {
+	row := q.db.QueryRowContext(ctx, createShippingRate, arg.ShippingZoneID, arg.FromQuantity, arg.Fee)
+	var i ShippingRate
+	err := row.Scan(
+		&i.ID,
+		&i.ShippingZoneID,
+		&i.FromQuantity,
+		&i.Fee,
+	)
+	return i, err
+}
+
+const createShippingZone = `-- name: CreateShippingZone :one
+INSERT INTO shipping_zones (
+  store_id,
+  name,
+  country,
+  state,
+  city,
+  rate_basis,
+  free_shipping_threshold,
+  min_delivery_days,
+  max_delivery_days
+) VALUES (
+  $1, $2, upper($3::varchar), $4, $5,
+  $6, $7, $8, $9
+)
+RETURNING id, store_id, name, country, state, city, rate_basis, free_shipping_threshold, min_delivery_days, max_delivery_days, created_at, updated_at
+`
+
+type CreateShippingZoneParams struct {
+	StoreID               int64  `json:"store_id"`
+	Name                  string `json:"name"`
+	Country               string `json:"country"`
+	State                 string `json:"state"`
+	City                  string `json:"city"`
+	RateBasis             string `json:"rate_basis"`
+	FreeShippingThreshold string `json:"free_shipping_threshold"`
+	MinDeliveryDays       int32  `json:"min_delivery_days"`
+	MaxDeliveryDays       int32  `json:"max_delivery_days"`
+}
+
+func (q *Queries) CreateShippingZone(ctx context.Context, arg CreateShippingZoneParams) (ShippingZone, error) {
+	row := q.db.QueryRowContext(ctx, createShippingZone,
+		arg.StoreID,
+		arg.Name,
+		arg.Country,
+		arg.State,
+		arg.City,
+		arg.RateBasis,
+		arg.FreeShippingThreshold,
+		arg.MinDeliveryDays,
+		arg.MaxDeliveryDays,
+	)
+	var i ShippingZone
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.Country,
+		&i.State,
+		&i.City,
+		&i.RateBasis,
+		&i.FreeShippingThreshold,
+		&i.MinDeliveryDays,
+		&i.MaxDeliveryDays,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteShippingRates = `-- name: DeleteShippingRates :exec
+DELETE FROM shipping_rates
+WHERE shipping_zone_id = $1
+`
+
+func (q *Queries) DeleteShippingRates(ctx context.Context, shippingZoneID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteShippingRates, shippingZoneID)
+	return err
+}
+
+const deleteShippingZone = `-- name: DeleteShippingZone :execrows
+DELETE FROM shipping_zones
+WHERE id = $1
+  AND store_id = $2
+`
+
+type DeleteShippingZoneParams struct {
+	ShippingZoneID int64 `json:"shipping_zone_id"`
+	StoreID        int64 `json:"store_id"`
+}
+
+func (q *Queries) DeleteShippingZone(ctx context.Context, arg DeleteShippingZoneParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteShippingZone, arg.ShippingZoneID, arg.StoreID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getShippingZone = `-- name: GetShippingZone :one
+SELECT id, store_id, name, country, state, city, rate_basis, free_shipping_threshold, min_delivery_days, max_delivery_days, created_at, updated_at FROM shipping_zones
+WHERE id = $1
+  AND store_id = $2
+`
+
+type GetShippingZoneParams struct {
+	ShippingZoneID int64 `json:"shipping_zone_id"`
+	StoreID        int64 `json:"store_id"`
+}
+
+func (q *Queries) GetShippingZone(ctx context.Context, arg GetShippingZoneParams) (ShippingZone, error) {
+	row := q.db.QueryRowContext(ctx, getShippingZone, arg.ShippingZoneID, arg.StoreID)
+	var i ShippingZone
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.Country,
+		&i.State,
+		&i.City,
+		&i.RateBasis,
+		&i.FreeShippingThreshold,
+		&i.MinDeliveryDays,
+		&i.MaxDeliveryDays,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listShippingRates = `-- name: ListShippingRates :many
+SELECT id, shipping_zone_id, from_quantity, fee FROM shipping_rates
+WHERE shipping_zone_id = ANY($1::bigint[])
+ORDER BY shipping_zone_id, from_quantity
+`
+
+func (q *Queries) ListShippingRates(ctx context.Context, shippingZoneIds []int64) ([]ShippingRate, error) {
+	rows, err := q.db.QueryContext(ctx, listShippingRates, pq.Array(shippingZoneIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ShippingRate{}
+	for rows.Next() {
+		var i ShippingRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShippingZoneID,
+			&i.FromQuantity,
+			&i.Fee,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShippingZones = `-- name: ListShippingZones :many
+SELECT id, store_id, name, country, state, city, rate_basis, free_shipping_threshold, min_delivery_days, max_delivery_days, created_at, updated_at FROM shipping_zones
+WHERE store_id = $1
+ORDER BY country, state, city, id
+`
+
+func (q *Queries) ListShippingZones(ctx context.Context, storeID int64) ([]ShippingZone, error) {
+	rows, err := q.db.QueryContext(ctx, listShippingZones, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ShippingZone{}
+	for rows.Next() {
+		var i ShippingZone
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Name,
+			&i.Country,
+			&i.State,
+			&i.City,
+			&i.RateBasis,
+			&i.FreeShippingThreshold,
+			&i.MinDeliveryDays,
+			&i.MaxDeliveryDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreShippingZones = `-- name: ListStoreShippingZones :many
+SELECT id, store_id, name, country, state, city, rate_basis, free_shipping_threshold, min_delivery_days, max_delivery_days, created_at, updated_at FROM shipping_zones
+WHERE store_id = ANY($1::bigint[])
+  AND country = upper($2::varchar)
+`
+
+type ListStoreShippingZonesParams struct {
+	StoreIds []int64 `json:"store_ids"`
+	Country  string  `json:"country"`
+}
+
+// The zones of every store in store_ids in country.
+func (q *Queries) ListStoreShippingZones(ctx context.Context, arg ListStoreShippingZonesParams) ([]ShippingZone, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreShippingZones, pq.Array(arg.StoreIds), arg.Country)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ShippingZone{}
+	for rows.Next() {
+		var i ShippingZone
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Name,
+			&i.Country,
+			&i.State,
+			&i.City,
+			&i.RateBasis,
+			&i.FreeShippingThreshold,
+			&i.MinDeliveryDays,
+			&i.MaxDeliveryDays,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setOrderExpectedDeliveryDate = `-- name: SetOrderExpectedDeliveryDate :one
+UPDATE orders
+SET expected_delivery_date = $1
+WHERE id = $2
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
+`
+
+type SetOrderExpectedDeliveryDateParams struct {
+	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
+	OrderID              int64     `json:"order_id"`
+}
+
+func (q *Queries) SetOrderExpectedDeliveryDate(ctx context.Context, arg SetOrderExpectedDeliveryDateParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderExpectedDeliveryDate, arg.ExpectedDeliveryDate, arg.OrderID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryStatus,
+		&i.DeliveredOn,
+		&i.ExpectedDeliveryDate,
+		&i.ItemID,
+		&i.ItemPrice,
+		&i.ItemCurrency,
+		&i.OrderQuantity,
+		&i.BuyerID,
+		&i.SellerID,
+		&i.StoreID,
+		&i.DeliveryFee,
+		&i.PaymentChannel,
+		&i.PaymentMethod,
+		&i.IsReviewed,
+		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
+	)
+	return i, err
+}
+
+const updateShippingZone = `-- name: UpdateShippingZone :one
+UPDATE shipping_zones
+SET
+  name = $1,
+  country = upper($2::varchar),
+  state = $3,
+  city = $4,
+  rate_basis = $5,
+  free_shipping_threshold = $6,
+  min_delivery_days = $7,
+  max_delivery_days = $8,
+  updated_at = now()
+WHERE id = $9
+  AND store_id = $10
+RETURNING id, store_id, name, country, state, city, rate_basis, free_shipping_threshold, min_delivery_days, max_delivery_days, created_at, updated_at
+`
+
+type UpdateShippingZoneParams struct {
+	Name                  string `json:"name"`
+	Country               string `json:"country"`
+	State                 string `json:"state"`
+	City                  string `json:"city"`
+	RateBasis             string `json:"rate_basis"`
+	FreeShippingThreshold string `json:"free_shipping_threshold"`
+	MinDeliveryDays       int32  `json:"min_delivery_days"`
+	MaxDeliveryDays       int32  `json:"max_delivery_days"`
+	ShippingZoneID        int64  `json:"shipping_zone_id"`
+	StoreID               int64  `json:"store_id"`
+}
+
+func (q *Queries) UpdateShippingZone(ctx context.Context, arg UpdateShippingZoneParams) (ShippingZone, error) {
+	row := q.db.QueryRowContext(ctx, updateShippingZone,
+		arg.Name,
+		arg.Country,
+		arg.State,
+		arg.City,
+		arg.RateBasis,
+		arg.FreeShippingThreshold,
+		arg.MinDeliveryDays,
+		arg.MaxDeliveryDays,
+		arg.ShippingZoneID,
+		arg.StoreID,
+	)
+	var i ShippingZone
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.Country,
+		&i.State,
+		&i.City,
+		&i.RateBasis,
+		&i.FreeShippingThreshold,
+		&i.MinDeliveryDays,
+		&i.MaxDeliveryDays,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
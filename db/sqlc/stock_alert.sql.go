@@ -0,0 +1,160 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: stock_alert.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const listItemsAtRisk = `-- name: ListItemsAtRisk :many
+SELECT
+  i.store_id,
+  s.name AS store_name,
+  i.id AS item_id,
+  i.name AS item_name,
+  i.sku AS item_sku,
+  i.supply_quantity,
+  COALESCE(i.reorder_threshold, s.low_stock_threshold)::int AS threshold,
+  COALESCE(sold.quantity, 0)::bigint AS sold_last_week
+FROM items i
+JOIN stores s ON s.id = i.store_id
+LEFT JOIN LATERAL (
+  SELECT -sum(sm.quantity_change) AS quantity
+  FROM stock_movements sm
+  WHERE sm.item_id = i.id
+    AND sm.reason = 'SALE'
+    AND sm.created_at > now() - interval '7 days'
+) sold ON true
+WHERE ($1::bigint IS NULL OR i.store_id = $1)
+  AND (
+    i.supply_quantity <= COALESCE(i.reorder_threshold, s.low_stock_threshold)
+    OR i.supply_quantity < COALESCE(sold.quantity, 0)
+  )
+ORDER BY i.store_id, i.supply_quantity, i.id
+`
+
+type ListItemsAtRiskRow struct {
+	StoreID        int64  `json:"store_id"`
+	StoreName      string `json:"store_name"`
+	ItemID         int64  `json:"item_id"`
+	ItemName       string `json:"item_name"`
+	ItemSku        string `json:"item_sku"`
+	SupplyQuantity int64  `json:"supply_quantity"`
+	Threshold      int32  `json:"threshold"`
+	SoldLastWeek   int64  `json:"sold_last_week"`
+}
+
+// Items at or below their threshold, or that sold more in the last week than
+// they have left, of a store or of every store.
+func (q *Queries) ListItemsAtRisk(ctx context.Context, storeID sql.NullInt64) ([]ListItemsAtRiskRow, error) {
+	rows, err := q.db.QueryContext(ctx, listItemsAtRisk, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListItemsAtRiskRow{}
+	for rows.Next() {
+		var i ListItemsAtRiskRow
+		if err := rows.Scan(
+			&i.StoreID,
+			&i.StoreName,
+			&i.ItemID,
+			&i.ItemName,
+			&i.ItemSku,
+			&i.SupplyQuantity,
+			&i.Threshold,
+			&i.SoldLastWeek,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnsentStockAlerts = `-- name: ListUnsentStockAlerts :many
+SELECT
+  sa.id, sa.store_id, sa.item_id, sa.kind, sa.supply_quantity, sa.threshold, sa.created_at, sa.sent_at,
+  s.name AS store_name,
+  i.name AS item_name,
+  i.sku AS item_sku
+FROM stock_alerts sa
+JOIN stores s ON s.id = sa.store_id
+JOIN items i ON i.id = sa.item_id
+WHERE sa.sent_at IS NULL
+ORDER BY sa.store_id, sa.id
+LIMIT $1
+`
+
+type ListUnsentStockAlertsRow struct {
+	ID             int64        `json:"id"`
+	StoreID        int64        `json:"store_id"`
+	ItemID         int64        `json:"item_id"`
+	Kind           string       `json:"kind"`
+	SupplyQuantity int64        `json:"supply_quantity"`
+	Threshold      int32        `json:"threshold"`
+	CreatedAt      time.Time    `json:"created_at"`
+	SentAt         sql.NullTime `json:"sent_at"`
+	StoreName      string       `json:"store_name"`
+	ItemName       string       `json:"item_name"`
+	ItemSku        string       `json:"item_sku"`
+}
+
+func (q *Queries) ListUnsentStockAlerts(ctx context.Context, rwLimit int32) ([]ListUnsentStockAlertsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnsentStockAlerts, rwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnsentStockAlertsRow{}
+	for rows.Next() {
+		var i ListUnsentStockAlertsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.Kind,
+			&i.SupplyQuantity,
+			&i.Threshold,
+			&i.CreatedAt,
+			&i.SentAt,
+			&i.StoreName,
+			&i.ItemName,
+			&i.ItemSku,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setStockAlertsSent = `-- name: SetStockAlertsSent :exec
+UPDATE stock_alerts
+SET sent_at = now()
+WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) SetStockAlertsSent(ctx context.Context, alertIds []int64) error {
+	_, err := q.db.ExecContext(ctx, setStockAlertsSent, pq.Array(alertIds))
+	return err
+}
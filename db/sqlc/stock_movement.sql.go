@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: stock_movement.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const listStockMovements = `-- name: ListStockMovements :many
+SELECT
+  count(*) OVER() AS total_count,
+  sm.id, sm.store_id, sm.item_id, sm.variant_id, sm.reason, sm.quantity_change, sm.resulting_quantity, sm.user_id, sm.reference, sm.note, sm.created_at
+FROM stock_movements sm
+WHERE sm.store_id = $1
+  AND ($2::bigint IS NULL OR sm.item_id = $2)
+  AND ($3::bigint IS NULL OR sm.variant_id = $3)
+  AND ($4::varchar IS NULL OR sm.reason = $4)
+  AND ($5::timestamptz IS NULL OR sm.created_at >= $5)
+  AND ($6::timestamptz IS NULL OR sm.created_at < $6)
+ORDER BY sm.created_at DESC, sm.id DESC
+LIMIT $8
+OFFSET $7
+`
+
+type ListStockMovementsParams struct {
+	StoreID       int64          `json:"store_id"`
+	ItemID        sql.NullInt64  `json:"item_id"`
+	VariantID     sql.NullInt64  `json:"variant_id"`
+	Reason        sql.NullString `json:"reason"`
+	CreatedFrom   sql.NullTime   `json:"created_from"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	RwOffset      int32          `json:"rw_offset"`
+	RwLimit       int32          `json:"rw_limit"`
+}
+
+type ListStockMovementsRow struct {
+	TotalCount        int64         `json:"total_count"`
+	ID                int64         `json:"id"`
+	StoreID           int64         `json:"store_id"`
+	ItemID            int64         `json:"item_id"`
+	VariantID         sql.NullInt64 `json:"variant_id"`
+	Reason            string        `json:"reason"`
+	QuantityChange    int64         `json:"quantity_change"`
+	ResultingQuantity int64         `json:"resulting_quantity"`
+	UserID            sql.NullInt64 `json:"user_id"`
+	Reference         string        `json:"reference"`
+	Note              string        `json:"note"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+func (q *Queries) ListStockMovements(ctx context.Context, arg ListStockMovementsParams) ([]ListStockMovementsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStockMovements,
+		arg.StoreID,
+		arg.ItemID,
+		arg.VariantID,
+		arg.Reason,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.RwOffset,
+		arg.RwLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStockMovementsRow{}
+	for rows.Next() {
+		var i ListStockMovementsRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.VariantID,
+			&i.Reason,
+			&i.QuantityChange,
+			&i.ResultingQuantity,
+			&i.UserID,
+			&i.Reference,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStockMovementsByReference = `-- name: ListStockMovementsByReference :many
+SELECT id, store_id, item_id, variant_id, reason, quantity_change, resulting_quantity, user_id, reference, note, created_at FROM stock_movements
+WHERE store_id = $1 AND reference = $2
+ORDER BY id
+`
+
+type ListStockMovementsByReferenceParams struct {
+	StoreID   int64  `json:"store_id"`
+	Reference string `json:"reference"`
+}
+
+func (q *Queries) ListStockMovementsByReference(ctx context.Context, arg ListStockMovementsByReferenceParams) ([]StockMovement, error) {
+	rows, err := q.db.QueryContext(ctx, listStockMovementsByReference, arg.StoreID, arg.Reference)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StockMovement{}
+	for rows.Next() {
+		var i StockMovement
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.ItemID,
+			&i.VariantID,
+			&i.Reason,
+			&i.QuantityChange,
+			&i.ResultingQuantity,
+			&i.UserID,
+			&i.Reference,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setStockMovementContext = `-- name: SetStockMovementContext :exec
+SELECT
+  set_config('store_hub.stock_reason', $1::text, true),
+  set_config('store_hub.stock_user_id', $2::text, true),
+  set_config('store_hub.stock_reference', $3::text, true),
+  set_config('store_hub.stock_note', $4::text, true)
+`
+
+type SetStockMovementContextParams struct {
+	Reason    string `json:"reason"`
+	UserID    string `json:"user_id"`
+	Reference string `json:"reference"`
+	Note      string `json:"note"`
+}
+
+// Sets the reason, user, reference and note of the stock movements recorded
+// for the rest of the transaction. An empty reason leaves the trigger to
+// tell INITIAL from ADJUSTMENT, an empty user_id records no user.
+func (q *Queries) SetStockMovementContext(ctx context.Context, arg SetStockMovementContextParams) error {
+	_, err := q.db.ExecContext(ctx, setStockMovementContext,
+		arg.Reason,
+		arg.UserID,
+		arg.Reference,
+		arg.Note,
+	)
+	return err
+}
@@ -0,0 +1,120 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: stock_reservation.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createStockReservation = `-- name: CreateStockReservation :one
+INSERT INTO stock_reservations (
+  item_id,
+  variant_id,
+  user_id,
+  reference,
+  quantity,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, item_id, user_id, reference, quantity, status, expires_at, created_at, updated_at, variant_id
+`
+
+type CreateStockReservationParams struct {
+	ItemID    int64         `json:"item_id"`
+	VariantID sql.NullInt64 `json:"variant_id"`
+	UserID    int64         `json:"user_id"`
+	Reference string        `json:"reference"`
+	Quantity  int32         `json:"quantity"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+func (q *Queries) CreateStockReservation(ctx context.Context, arg CreateStockReservationParams) (StockReservation, error) {
+	row := q.db.QueryRowContext(ctx, createStockReservation,
+		arg.ItemID,
+		arg.VariantID,
+		arg.UserID,
+		arg.Reference,
+		arg.Quantity,
+		arg.ExpiresAt,
+	)
+	var i StockReservation
+	err := row.Scan(
+		&i.ID,
+		&i.ItemID,
+		&i.UserID,
+		&i.Reference,
+		&i.Quantity,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VariantID,
+	)
+	return i, err
+}
+
+const expireStockReservations = `-- name: ExpireStockReservations :execrows
+UPDATE stock_reservations
+SET
+  status = 'EXPIRED',
+  updated_at = now()
+WHERE reference = $1
+  AND status = 'RESERVED'
+`
+
+func (q *Queries) ExpireStockReservations(ctx context.Context, reference string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireStockReservations, reference)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getReservedStock = `-- name: GetReservedStock :one
+SELECT COALESCE(sum(quantity), 0)::bigint AS reserved
+FROM stock_reservations
+WHERE item_id = $1
+  AND reference <> $2
+  AND status = 'RESERVED'
+  AND expires_at > now()
+`
+
+type GetReservedStockParams struct {
+	ItemID    int64  `json:"item_id"`
+	Reference string `json:"reference"`
+}
+
+func (q *Queries) GetReservedStock(ctx context.Context, arg GetReservedStockParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getReservedStock, arg.ItemID, arg.Reference)
+	var reserved int64
+	err := row.Scan(&reserved)
+	return reserved, err
+}
+
+const updateStockReservationsStatus = `-- name: UpdateStockReservationsStatus :execrows
+UPDATE stock_reservations
+SET
+  status = $1,
+  updated_at = now()
+WHERE reference = $2
+  AND status = $3
+`
+
+type UpdateStockReservationsStatusParams struct {
+	ToStatus   string `json:"to_status"`
+	Reference  string `json:"reference"`
+	FromStatus string `json:"from_status"`
+}
+
+func (q *Queries) UpdateStockReservationsStatus(ctx context.Context, arg UpdateStockReservationsStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateStockReservationsStatus, arg.ToStatus, arg.Reference, arg.FromStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -21,7 +21,7 @@ INSERT INTO stores (
   category
 ) VALUES (
   $1, $2, $3, $4, $5
-) RETURNING id, name, description, store_account_id, profile_image_url, is_verified, category, is_frozen, created_at
+) RETURNING id, name, description, store_account_id, profile_image_url, is_verified, category, is_frozen, created_at, low_stock_threshold
 `
 
 type CreateStoreParams struct {
@@ -51,6 +51,7 @@ func (q *Queries) CreateStore(ctx context.Context, arg CreateStoreParams) (Store
 		&i.Category,
 		&i.IsFrozen,
 		&i.CreatedAt,
+		&i.LowStockThreshold,
 	)
 	return i, err
 }
@@ -67,7 +68,7 @@ func (q *Queries) DeleteStore(ctx context.Context, storeID int64) error {
 
 const getStoreByID = `-- name: GetStoreByID :one
 SELECT 
-  s.id, s.name, s.description, s.store_account_id, s.profile_image_url, s.is_verified, s.category, s.is_frozen, s.created_at, 
+  s.id, s.name, s.description, s.store_account_id, s.profile_image_url, s.is_verified, s.category, s.is_frozen, s.created_at, s.low_stock_threshold, 
   json_agg(json_build_object(
       'account_id', u.account_id,
       'profile_img_url', u.profile_image_url,
@@ -88,16 +89,17 @@ GROUP BY
 `
 
 type GetStoreByIDRow struct {
-	ID              int64           `json:"id"`
-	Name            string          `json:"name"`
-	Description     string          `json:"description"`
-	StoreAccountID  string          `json:"store_account_id"`
-	ProfileImageUrl string          `json:"profile_image_url"`
-	IsVerified      bool            `json:"is_verified"`
-	Category        string          `json:"category"`
-	IsFrozen        bool            `json:"is_frozen"`
-	CreatedAt       time.Time       `json:"created_at"`
-	StoreOwners     json.RawMessage `json:"store_owners"`
+	ID                int64           `json:"id"`
+	Name              string          `json:"name"`
+	Description       string          `json:"description"`
+	StoreAccountID    string          `json:"store_account_id"`
+	ProfileImageUrl   string          `json:"profile_image_url"`
+	IsVerified        bool            `json:"is_verified"`
+	Category          string          `json:"category"`
+	IsFrozen          bool            `json:"is_frozen"`
+	CreatedAt         time.Time       `json:"created_at"`
+	LowStockThreshold int32           `json:"low_stock_threshold"`
+	StoreOwners       json.RawMessage `json:"store_owners"`
 }
 
 func (q *Queries) GetStoreByID(ctx context.Context, storeID int64) (GetStoreByIDRow, error) {
@@ -113,6 +115,7 @@ func (q *Queries) GetStoreByID(ctx context.Context, storeID int64) (GetStoreByID
 		&i.Category,
 		&i.IsFrozen,
 		&i.CreatedAt,
+		&i.LowStockThreshold,
 		&i.StoreOwners,
 	)
 	return i, err
@@ -128,7 +131,7 @@ WITH store_reviews AS (
     GROUP BY store_id
 )
 SELECT 
-    s.id, s.name, s.description, s.store_account_id, s.profile_image_url, s.is_verified, s.category, s.is_frozen, s.created_at,
+    s.id, s.name, s.description, s.store_account_id, s.profile_image_url, s.is_verified, s.category, s.is_frozen, s.created_at, s.low_stock_threshold,
     COALESCE(sr.average_rating, 0) as average_rating,
     json_agg(
         json_build_object(
@@ -147,17 +150,18 @@ GROUP BY
 `
 
 type GetStoreDetailsRow struct {
-	ID              int64           `json:"id"`
-	Name            string          `json:"name"`
-	Description     string          `json:"description"`
-	StoreAccountID  string          `json:"store_account_id"`
-	ProfileImageUrl string          `json:"profile_image_url"`
-	IsVerified      bool            `json:"is_verified"`
-	Category        string          `json:"category"`
-	IsFrozen        bool            `json:"is_frozen"`
-	CreatedAt       time.Time       `json:"created_at"`
-	AverageRating   string          `json:"average_rating"`
-	StoreOwners     json.RawMessage `json:"store_owners"`
+	ID                int64           `json:"id"`
+	Name              string          `json:"name"`
+	Description       string          `json:"description"`
+	StoreAccountID    string          `json:"store_account_id"`
+	ProfileImageUrl   string          `json:"profile_image_url"`
+	IsVerified        bool            `json:"is_verified"`
+	Category          string          `json:"category"`
+	IsFrozen          bool            `json:"is_frozen"`
+	CreatedAt         time.Time       `json:"created_at"`
+	LowStockThreshold int32           `json:"low_stock_threshold"`
+	AverageRating     string          `json:"average_rating"`
+	StoreOwners       json.RawMessage `json:"store_owners"`
 }
 
 func (q *Queries) GetStoreDetails(ctx context.Context, storeID int64) (GetStoreDetailsRow, error) {
@@ -173,6 +177,7 @@ func (q *Queries) GetStoreDetails(ctx context.Context, storeID int64) (GetStoreD
 		&i.Category,
 		&i.IsFrozen,
 		&i.CreatedAt,
+		&i.LowStockThreshold,
 		&i.AverageRating,
 		&i.StoreOwners,
 	)
@@ -187,20 +192,22 @@ SET
   profile_image_url = COALESCE($3, profile_image_url),
   is_verified = COALESCE($4, is_verified),
   category = COALESCE($5, category),
-  is_frozen = COALESCE($6, is_frozen)
+  is_frozen = COALESCE($6, is_frozen),
+  low_stock_threshold = COALESCE($7, low_stock_threshold)
 WHERE 
-  id = $7
-RETURNING id, name, description, store_account_id, profile_image_url, is_verified, category, is_frozen, created_at
+  id = $8
+RETURNING id, name, description, store_account_id, profile_image_url, is_verified, category, is_frozen, created_at, low_stock_threshold
 `
 
 type UpdateStoreParams struct {
-	Name            sql.NullString `json:"name"`
-	Description     sql.NullString `json:"description"`
-	ProfileImageUrl sql.NullString `json:"profile_image_url"`
-	IsVerified      sql.NullBool   `json:"is_verified"`
-	Category        sql.NullString `json:"category"`
-	IsFrozen        sql.NullBool   `json:"is_frozen"`
-	StoreID         int64          `json:"store_id"`
+	Name              sql.NullString `json:"name"`
+	Description       sql.NullString `json:"description"`
+	ProfileImageUrl   sql.NullString `json:"profile_image_url"`
+	IsVerified        sql.NullBool   `json:"is_verified"`
+	Category          sql.NullString `json:"category"`
+	IsFrozen          sql.NullBool   `json:"is_frozen"`
+	LowStockThreshold sql.NullInt32  `json:"low_stock_threshold"`
+	StoreID           int64          `json:"store_id"`
 }
 
 func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store, error) {
@@ -211,6 +218,7 @@ func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store
 		arg.IsVerified,
 		arg.Category,
 		arg.IsFrozen,
+		arg.LowStockThreshold,
 		arg.StoreID,
 	)
 	var i Store
@@ -224,6 +232,7 @@ func (q *Queries) UpdateStore(ctx context.Context, arg UpdateStoreParams) (Store
 		&i.Category,
 		&i.IsFrozen,
 		&i.CreatedAt,
+		&i.LowStockThreshold,
 	)
 	return i, err
 }
@@ -0,0 +1,299 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: store_credit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const adjustStoreCredit = `-- name: AdjustStoreCredit :one
+INSERT INTO store_credits (user_id, balance)
+VALUES ($1, $2::NUMERIC(18, 2))
+ON CONFLICT (user_id) DO UPDATE
+SET
+  balance = store_credits.balance + EXCLUDED.balance,
+  updated_at = now()
+RETURNING balance
+`
+
+type AdjustStoreCreditParams struct {
+	UserID int64  `json:"user_id"`
+	Amount string `json:"amount"`
+}
+
+// Adds amount, or takes it off if negative, to a user's store credit.
+func (q *Queries) AdjustStoreCredit(ctx context.Context, arg AdjustStoreCreditParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, adjustStoreCredit, arg.UserID, arg.Amount)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const createCheckoutCredit = `-- name: CreateCheckoutCredit :one
+INSERT INTO checkout_credits (
+  reference,
+  user_id,
+  gift_card_id,
+  amount
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, reference, user_id, gift_card_id, amount, status, created_at, updated_at
+`
+
+type CreateCheckoutCreditParams struct {
+	Reference  string        `json:"reference"`
+	UserID     int64         `json:"user_id"`
+	GiftCardID sql.NullInt64 `json:"gift_card_id"`
+	Amount     string        `json:"amount"`
+}
+
+func (q *Queries) CreateCheckoutCredit(ctx context.Context, arg CreateCheckoutCreditParams) (CheckoutCredit, error) {
+	row := q.db.QueryRowContext(ctx, createCheckoutCredit,
+		arg.Reference,
+		arg.UserID,
+		arg.GiftCardID,
+		arg.Amount,
+	)
+	var i CheckoutCredit
+	err := row.Scan(
+		&i.ID,
+		&i.Reference,
+		&i.UserID,
+		&i.GiftCardID,
+		&i.Amount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createStoreCreditEntry = `-- name: CreateStoreCreditEntry :one
+INSERT INTO store_credit_entries (
+  user_id,
+  kind,
+  amount,
+  balance,
+  reference,
+  description
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, user_id, kind, amount, balance, reference, description, created_at
+`
+
+type CreateStoreCreditEntryParams struct {
+	UserID      int64  `json:"user_id"`
+	Kind        string `json:"kind"`
+	Amount      string `json:"amount"`
+	Balance     string `json:"balance"`
+	Reference   string `json:"reference"`
+	Description string `json:"description"`
+}
+
+func (q *Queries) CreateStoreCreditEntry(ctx context.Context, arg CreateStoreCreditEntryParams) (StoreCreditEntry, error) {
+	row := q.db.QueryRowContext(ctx, createStoreCreditEntry,
+		arg.UserID,
+		arg.Kind,
+		arg.Amount,
+		arg.Balance,
+		arg.Reference,
+		arg.Description,
+	)
+	var i StoreCreditEntry
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.Amount,
+		&i.Balance,
+		&i.Reference,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStoreCredit = `-- name: GetStoreCredit :one
+SELECT COALESCE((
+  SELECT balance FROM store_credits WHERE user_id = $1
+), 0)::NUMERIC(18, 2) AS balance
+`
+
+func (q *Queries) GetStoreCredit(ctx context.Context, userID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getStoreCredit, userID)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const getStoreCreditForUpdate = `-- name: GetStoreCreditForUpdate :one
+SELECT balance FROM store_credits
+WHERE user_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetStoreCreditForUpdate(ctx context.Context, userID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getStoreCreditForUpdate, userID)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const listCheckoutCredits = `-- name: ListCheckoutCredits :many
+SELECT id, reference, user_id, gift_card_id, amount, status, created_at, updated_at FROM checkout_credits
+WHERE reference = $1
+  AND status = $2
+ORDER BY id
+`
+
+type ListCheckoutCreditsParams struct {
+	Reference string `json:"reference"`
+	Status    string `json:"status"`
+}
+
+func (q *Queries) ListCheckoutCredits(ctx context.Context, arg ListCheckoutCreditsParams) ([]CheckoutCredit, error) {
+	rows, err := q.db.QueryContext(ctx, listCheckoutCredits, arg.Reference, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CheckoutCredit{}
+	for rows.Next() {
+		var i CheckoutCredit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Reference,
+			&i.UserID,
+			&i.GiftCardID,
+			&i.Amount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoreCreditEntries = `-- name: ListStoreCreditEntries :many
+SELECT
+  count(*) OVER() AS total_count,
+  sce.id, sce.user_id, sce.kind, sce.amount, sce.balance, sce.reference, sce.description, sce.created_at
+FROM store_credit_entries sce
+WHERE sce.user_id = $1
+ORDER BY sce.id DESC
+LIMIT $3
+OFFSET $2
+`
+
+type ListStoreCreditEntriesParams struct {
+	UserID   int64 `json:"user_id"`
+	RwOffset int32 `json:"rw_offset"`
+	RwLimit  int32 `json:"rw_limit"`
+}
+
+type ListStoreCreditEntriesRow struct {
+	TotalCount  int64     `json:"total_count"`
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Kind        string    `json:"kind"`
+	Amount      string    `json:"amount"`
+	Balance     string    `json:"balance"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListStoreCreditEntries(ctx context.Context, arg ListStoreCreditEntriesParams) ([]ListStoreCreditEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreCreditEntries, arg.UserID, arg.RwOffset, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStoreCreditEntriesRow{}
+	for rows.Next() {
+		var i ListStoreCreditEntriesRow
+		if err := rows.Scan(
+			&i.TotalCount,
+			&i.ID,
+			&i.UserID,
+			&i.Kind,
+			&i.Amount,
+			&i.Balance,
+			&i.Reference,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCheckoutCreditsStatus = `-- name: UpdateCheckoutCreditsStatus :many
+UPDATE checkout_credits
+SET
+  status = $1,
+  updated_at = now()
+WHERE reference = $2
+  AND status = $3
+RETURNING id, reference, user_id, gift_card_id, amount, status, created_at, updated_at
+`
+
+type UpdateCheckoutCreditsStatusParams struct {
+	ToStatus   string `json:"to_status"`
+	Reference  string `json:"reference"`
+	FromStatus string `json:"from_status"`
+}
+
+func (q *Queries) UpdateCheckoutCreditsStatus(ctx context.Context, arg UpdateCheckoutCreditsStatusParams) ([]CheckoutCredit, error) {
+	rows, err := q.db.QueryContext(ctx, updateCheckoutCreditsStatus, arg.ToStatus, arg.Reference, arg.FromStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CheckoutCredit{}
+	for rows.Next() {
+		var i CheckoutCredit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Reference,
+			&i.UserID,
+			&i.GiftCardID,
+			&i.Amount,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
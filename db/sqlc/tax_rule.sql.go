@@ -0,0 +1,304 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: tax_rule.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const createTaxRule = `-- name: CreateTaxRule :one
+INSERT INTO tax_rules (
+  store_id,
+  name,
+  country,
+  state,
+  category,
+  rate,
+  inclusive
+) VALUES (
+  $1, $2, upper($3::varchar), $4,
+  $5, $6, $7
+)
+RETURNING id, store_id, name, country, state, category, rate, inclusive, created_at, updated_at
+`
+
+type CreateTaxRuleParams struct {
+	StoreID   int64  `json:"store_id"`
+	Name      string `json:"name"`
+	Country   string `json:"country"`
+	State     string `json:"state"`
+	Category  string `json:"category"`
+	Rate      string `json:"rate"`
+	Inclusive bool   `json:"inclusive"`
+}
+
+func (q *Queries) CreateTaxRule(ctx context.Context, arg CreateTaxRuleParams) (TaxRule, error) {
+	row := q.db.QueryRowContext(ctx, createTaxRule,
+		arg.StoreID,
+		arg.Name,
+		arg.Country,
+		arg.State,
+		arg.Category,
+		arg.Rate,
+		arg.Inclusive,
+	)
+	var i TaxRule
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.Country,
+		&i.State,
+		&i.Category,
+		&i.Rate,
+		&i.Inclusive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTaxRule = `-- name: DeleteTaxRule :execrows
+DELETE FROM tax_rules
+WHERE id = $1
+  AND store_id = $2
+`
+
+type DeleteTaxRuleParams struct {
+	TaxRuleID int64 `json:"tax_rule_id"`
+	StoreID   int64 `json:"store_id"`
+}
+
+func (q *Queries) DeleteTaxRule(ctx context.Context, arg DeleteTaxRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTaxRule, arg.TaxRuleID, arg.StoreID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getTaxRule = `-- name: GetTaxRule :one
+SELECT id, store_id, name, country, state, category, rate, inclusive, created_at, updated_at FROM tax_rules
+WHERE id = $1
+  AND store_id = $2
+`
+
+type GetTaxRuleParams struct {
+	TaxRuleID int64 `json:"tax_rule_id"`
+	StoreID   int64 `json:"store_id"`
+}
+
+func (q *Queries) GetTaxRule(ctx context.Context, arg GetTaxRuleParams) (TaxRule, error) {
+	row := q.db.QueryRowContext(ctx, getTaxRule, arg.TaxRuleID, arg.StoreID)
+	var i TaxRule
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.Country,
+		&i.State,
+		&i.Category,
+		&i.Rate,
+		&i.Inclusive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listStoreTaxRules = `-- name: ListStoreTaxRules :many
+SELECT id, store_id, name, country, state, category, rate, inclusive, created_at, updated_at FROM tax_rules
+WHERE store_id = ANY($1::bigint[])
+  AND country = upper($2::varchar)
+`
+
+type ListStoreTaxRulesParams struct {
+	StoreIds []int64 `json:"store_ids"`
+	Country  string  `json:"country"`
+}
+
+// The rules of every store in store_ids taxing sales shipped to country.
+func (q *Queries) ListStoreTaxRules(ctx context.Context, arg ListStoreTaxRulesParams) ([]TaxRule, error) {
+	rows, err := q.db.QueryContext(ctx, listStoreTaxRules, pq.Array(arg.StoreIds), arg.Country)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaxRule{}
+	for rows.Next() {
+		var i TaxRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Name,
+			&i.Country,
+			&i.State,
+			&i.Category,
+			&i.Rate,
+			&i.Inclusive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaxRules = `-- name: ListTaxRules :many
+SELECT id, store_id, name, country, state, category, rate, inclusive, created_at, updated_at FROM tax_rules
+WHERE store_id = $1
+ORDER BY country, state, category, id
+`
+
+func (q *Queries) ListTaxRules(ctx context.Context, storeID int64) ([]TaxRule, error) {
+	rows, err := q.db.QueryContext(ctx, listTaxRules, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TaxRule{}
+	for rows.Next() {
+		var i TaxRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Name,
+			&i.Country,
+			&i.State,
+			&i.Category,
+			&i.Rate,
+			&i.Inclusive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setOrderTax = `-- name: SetOrderTax :one
+UPDATE orders
+SET
+  tax_rate = $1,
+  tax_inclusive = $2,
+  tax_amount = $3
+WHERE id = $4
+RETURNING id, delivery_status, delivered_on, expected_delivery_date, item_id, item_price, item_currency, order_quantity, buyer_id, seller_id, store_id, delivery_fee, payment_channel, payment_method, is_reviewed, created_at, funds_released_at, commission_rule_id, commission_percentage, commission_fixed, commission_amount, coupon_id, discount_amount, currency, fx_rate, fulfilment_group_id, tax_rate, tax_inclusive, tax_amount, variant_id, variant_sku
+`
+
+type SetOrderTaxParams struct {
+	TaxRate      string `json:"tax_rate"`
+	TaxInclusive bool   `json:"tax_inclusive"`
+	TaxAmount    string `json:"tax_amount"`
+	OrderID      int64  `json:"order_id"`
+}
+
+func (q *Queries) SetOrderTax(ctx context.Context, arg SetOrderTaxParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, setOrderTax,
+		arg.TaxRate,
+		arg.TaxInclusive,
+		arg.TaxAmount,
+		arg.OrderID,
+	)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryStatus,
+		&i.DeliveredOn,
+		&i.ExpectedDeliveryDate,
+		&i.ItemID,
+		&i.ItemPrice,
+		&i.ItemCurrency,
+		&i.OrderQuantity,
+		&i.BuyerID,
+		&i.SellerID,
+		&i.StoreID,
+		&i.DeliveryFee,
+		&i.PaymentChannel,
+		&i.PaymentMethod,
+		&i.IsReviewed,
+		&i.CreatedAt,
+		&i.FundsReleasedAt,
+		&i.CommissionRuleID,
+		&i.CommissionPercentage,
+		&i.CommissionFixed,
+		&i.CommissionAmount,
+		&i.CouponID,
+		&i.DiscountAmount,
+		&i.Currency,
+		&i.FxRate,
+		&i.FulfilmentGroupID,
+		&i.TaxRate,
+		&i.TaxInclusive,
+		&i.TaxAmount,
+		&i.VariantID,
+		&i.VariantSku,
+	)
+	return i, err
+}
+
+const updateTaxRule = `-- name: UpdateTaxRule :one
+UPDATE tax_rules
+SET
+  name = COALESCE($1, name),
+  rate = COALESCE($2, rate),
+  inclusive = COALESCE($3, inclusive),
+  updated_at = now()
+WHERE id = $4
+  AND store_id = $5
+RETURNING id, store_id, name, country, state, category, rate, inclusive, created_at, updated_at
+`
+
+type UpdateTaxRuleParams struct {
+	Name      sql.NullString `json:"name"`
+	Rate      sql.NullString `json:"rate"`
+	Inclusive sql.NullBool   `json:"inclusive"`
+	TaxRuleID int64          `json:"tax_rule_id"`
+	StoreID   int64          `json:"store_id"`
+}
+
+func (q *Queries) UpdateTaxRule(ctx context.Context, arg UpdateTaxRuleParams) (TaxRule, error) {
+	row := q.db.QueryRowContext(ctx, updateTaxRule,
+		arg.Name,
+		arg.Rate,
+		arg.Inclusive,
+		arg.TaxRuleID,
+		arg.StoreID,
+	)
+	var i TaxRule
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Name,
+		&i.Country,
+		&i.State,
+		&i.Category,
+		&i.Rate,
+		&i.Inclusive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -15,21 +15,23 @@ import (
 )
 
 const createTransaction = `-- name: CreateTransaction :one
-SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at FROM initialize_transaction(
+SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount FROM initialize_transaction(
   $1::bigint,
   $2::NUMERIC(18, 2),
   $3::varchar,
   $4::varchar,
-  $5::varchar
+  $5::varchar,
+  $6::NUMERIC(40, 0)
 )
 `
 
 type CreateTransactionParams struct {
-	CustomerID           int64  `json:"customer_id"`
-	Amount               string `json:"amount"`
-	PaymentProvider      string `json:"payment_provider"`
-	ProviderTxRefID      string `json:"provider_tx_ref_id"`
-	ProviderTxAccessCode string `json:"provider_tx_access_code"`
+	CustomerID           int64          `json:"customer_id"`
+	Amount               string         `json:"amount"`
+	PaymentProvider      string         `json:"payment_provider"`
+	ProviderTxRefID      string         `json:"provider_tx_ref_id"`
+	ProviderTxAccessCode string         `json:"provider_tx_access_code"`
+	NearAmount           sql.NullString `json:"near_amount"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -39,6 +41,7 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.PaymentProvider,
 		arg.ProviderTxRefID,
 		arg.ProviderTxAccessCode,
+		arg.NearAmount,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -52,6 +55,8 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.ProviderTxFee,
 		&i.Status,
 		&i.CreatedAt,
+		&i.ProviderTxHash,
+		&i.NearAmount,
 	)
 	return i, err
 }
@@ -82,7 +87,7 @@ func (q *Queries) GetPendingFunds(ctx context.Context, arg GetPendingFundsParams
 }
 
 const getTransactionByRefID = `-- name: GetTransactionByRefID :one
-SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at FROM transactions 
+SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount FROM transactions 
 WHERE provider_tx_ref_id = $1
 `
 
@@ -100,13 +105,41 @@ func (q *Queries) GetTransactionByRefID(ctx context.Context, providerTxRefID str
 		&i.ProviderTxFee,
 		&i.Status,
 		&i.CreatedAt,
+		&i.ProviderTxHash,
+		&i.NearAmount,
+	)
+	return i, err
+}
+
+const getTransactionByRefIDForUpdate = `-- name: GetTransactionByRefIDForUpdate :one
+SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount FROM transactions 
+WHERE provider_tx_ref_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetTransactionByRefIDForUpdate(ctx context.Context, providerTxRefID string) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, getTransactionByRefIDForUpdate, providerTxRefID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		pq.Array(&i.OrderIds),
+		&i.CustomerID,
+		&i.Amount,
+		&i.PaymentProvider,
+		&i.ProviderTxRefID,
+		&i.ProviderTxAccessCode,
+		&i.ProviderTxFee,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ProviderTxHash,
+		&i.NearAmount,
 	)
 	return i, err
 }
 
 const getTransactionOrders = `-- name: GetTransactionOrders :many
 SELECT 
-  o.id, o.delivery_status, o.delivered_on, o.expected_delivery_date, o.item_id, o.item_price, o.item_currency, o.order_quantity, o.buyer_id, o.seller_id, o.store_id, o.delivery_fee, o.payment_channel, o.payment_method, o.is_reviewed, o.created_at,
+  o.id, o.delivery_status, o.delivered_on, o.expected_delivery_date, o.item_id, o.item_price, o.item_currency, o.order_quantity, o.buyer_id, o.seller_id, o.store_id, o.delivery_fee, o.payment_channel, o.payment_method, o.is_reviewed, o.created_at, o.funds_released_at, o.commission_rule_id, o.commission_percentage, o.commission_fixed, o.commission_amount, o.coupon_id, o.discount_amount, o.currency, o.fx_rate, o.fulfilment_group_id, o.tax_rate, o.tax_inclusive, o.tax_amount, o.variant_id, o.variant_sku,
   i.name as item_name,
   i.description as item_description,
   s.name as store_name,
@@ -121,26 +154,41 @@ WHERE t.provider_tx_ref_id = $1
 `
 
 type GetTransactionOrdersRow struct {
-	ID                   int64     `json:"id"`
-	DeliveryStatus       string    `json:"delivery_status"`
-	DeliveredOn          time.Time `json:"delivered_on"`
-	ExpectedDeliveryDate time.Time `json:"expected_delivery_date"`
-	ItemID               int64     `json:"item_id"`
-	ItemPrice            string    `json:"item_price"`
-	ItemCurrency         string    `json:"item_currency"`
-	OrderQuantity        int32     `json:"order_quantity"`
-	BuyerID              int64     `json:"buyer_id"`
-	SellerID             int64     `json:"seller_id"`
-	StoreID              int64     `json:"store_id"`
-	DeliveryFee          string    `json:"delivery_fee"`
-	PaymentChannel       string    `json:"payment_channel"`
-	PaymentMethod        string    `json:"payment_method"`
-	IsReviewed           bool      `json:"is_reviewed"`
-	CreatedAt            time.Time `json:"created_at"`
-	ItemName             string    `json:"item_name"`
-	ItemDescription      string    `json:"item_description"`
-	StoreName            string    `json:"store_name"`
-	BuyerAccountID       string    `json:"buyer_account_id"`
+	ID                   int64         `json:"id"`
+	DeliveryStatus       string        `json:"delivery_status"`
+	DeliveredOn          time.Time     `json:"delivered_on"`
+	ExpectedDeliveryDate time.Time     `json:"expected_delivery_date"`
+	ItemID               int64         `json:"item_id"`
+	ItemPrice            string        `json:"item_price"`
+	ItemCurrency         string        `json:"item_currency"`
+	OrderQuantity        int32         `json:"order_quantity"`
+	BuyerID              int64         `json:"buyer_id"`
+	SellerID             int64         `json:"seller_id"`
+	StoreID              int64         `json:"store_id"`
+	DeliveryFee          string        `json:"delivery_fee"`
+	PaymentChannel       string        `json:"payment_channel"`
+	PaymentMethod        string        `json:"payment_method"`
+	IsReviewed           bool          `json:"is_reviewed"`
+	CreatedAt            time.Time     `json:"created_at"`
+	FundsReleasedAt      sql.NullTime  `json:"funds_released_at"`
+	CommissionRuleID     sql.NullInt64 `json:"commission_rule_id"`
+	CommissionPercentage string        `json:"commission_percentage"`
+	CommissionFixed      string        `json:"commission_fixed"`
+	CommissionAmount     string        `json:"commission_amount"`
+	CouponID             sql.NullInt64 `json:"coupon_id"`
+	DiscountAmount       string        `json:"discount_amount"`
+	Currency             string        `json:"currency"`
+	FxRate               string        `json:"fx_rate"`
+	FulfilmentGroupID    sql.NullInt64 `json:"fulfilment_group_id"`
+	TaxRate              string        `json:"tax_rate"`
+	TaxInclusive         bool          `json:"tax_inclusive"`
+	TaxAmount            string        `json:"tax_amount"`
+	VariantID            sql.NullInt64 `json:"variant_id"`
+	VariantSku           string        `json:"variant_sku"`
+	ItemName             string        `json:"item_name"`
+	ItemDescription      string        `json:"item_description"`
+	StoreName            string        `json:"store_name"`
+	BuyerAccountID       string        `json:"buyer_account_id"`
 }
 
 func (q *Queries) GetTransactionOrders(ctx context.Context, providerTxRefID string) ([]GetTransactionOrdersRow, error) {
@@ -169,6 +217,21 @@ func (q *Queries) GetTransactionOrders(ctx context.Context, providerTxRefID stri
 			&i.PaymentMethod,
 			&i.IsReviewed,
 			&i.CreatedAt,
+			&i.FundsReleasedAt,
+			&i.CommissionRuleID,
+			&i.CommissionPercentage,
+			&i.CommissionFixed,
+			&i.CommissionAmount,
+			&i.CouponID,
+			&i.DiscountAmount,
+			&i.Currency,
+			&i.FxRate,
+			&i.FulfilmentGroupID,
+			&i.TaxRate,
+			&i.TaxInclusive,
+			&i.TaxAmount,
+			&i.VariantID,
+			&i.VariantSku,
 			&i.ItemName,
 			&i.ItemDescription,
 			&i.StoreName,
@@ -250,8 +313,57 @@ func (q *Queries) ListStorePendingFunds(ctx context.Context, arg ListStorePendin
 	return items, nil
 }
 
+const listStuckTransactions = `-- name: ListStuckTransactions :many
+SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount FROM transactions
+WHERE status = 'PROCESSING'
+  AND created_at < $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListStuckTransactionsParams struct {
+	CreatedBefore time.Time `json:"created_before"`
+	RwLimit       int32     `json:"rw_limit"`
+}
+
+func (q *Queries) ListStuckTransactions(ctx context.Context, arg ListStuckTransactionsParams) ([]Transaction, error) {
+	rows, err := q.db.QueryContext(ctx, listStuckTransactions, arg.CreatedBefore, arg.RwLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transaction{}
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			pq.Array(&i.OrderIds),
+			&i.CustomerID,
+			&i.Amount,
+			&i.PaymentProvider,
+			&i.ProviderTxRefID,
+			&i.ProviderTxAccessCode,
+			&i.ProviderTxFee,
+			&i.Status,
+			&i.CreatedAt,
+			&i.ProviderTxHash,
+			&i.NearAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const processTransaction = `-- name: ProcessTransaction :one
-SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at FROM process_transaction_completion(
+SELECT id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount FROM process_transaction_completion(
   $1::varchar,
   $2::varchar,
   $3::NUMERIC(10, 2),
@@ -285,15 +397,52 @@ func (q *Queries) ProcessTransaction(ctx context.Context, arg ProcessTransaction
 		&i.ProviderTxFee,
 		&i.Status,
 		&i.CreatedAt,
+		&i.ProviderTxHash,
+		&i.NearAmount,
 	)
 	return i, err
 }
 
-const releaseFunds = `-- name: ReleaseFunds :exec
-SELECT release_pending_funds($1::bigint)
+const releaseFunds = `-- name: ReleaseFunds :one
+SELECT release_pending_funds($1::bigint)::NUMERIC(18, 2) AS released
+`
+
+func (q *Queries) ReleaseFunds(ctx context.Context, orderID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, releaseFunds, orderID)
+	var released string
+	err := row.Scan(&released)
+	return released, err
+}
+
+const setTransactionProviderTxHash = `-- name: SetTransactionProviderTxHash :one
+UPDATE transactions
+SET provider_tx_hash = $1
+WHERE provider_tx_ref_id = $2
+  AND provider_tx_hash IS NULL
+RETURNING id, order_ids, customer_id, amount, payment_provider, provider_tx_ref_id, provider_tx_access_code, provider_tx_fee, status, created_at, provider_tx_hash, near_amount
 `
 
-func (q *Queries) ReleaseFunds(ctx context.Context, orderID int64) error {
-	_, err := q.db.ExecContext(ctx, releaseFunds, orderID)
-	return err
+type SetTransactionProviderTxHashParams struct {
+	ProviderTxHash  sql.NullString `json:"provider_tx_hash"`
+	ProviderTxRefID string         `json:"provider_tx_ref_id"`
+}
+
+func (q *Queries) SetTransactionProviderTxHash(ctx context.Context, arg SetTransactionProviderTxHashParams) (Transaction, error) {
+	row := q.db.QueryRowContext(ctx, setTransactionProviderTxHash, arg.ProviderTxHash, arg.ProviderTxRefID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		pq.Array(&i.OrderIds),
+		&i.CustomerID,
+		&i.Amount,
+		&i.PaymentProvider,
+		&i.ProviderTxRefID,
+		&i.ProviderTxAccessCode,
+		&i.ProviderTxFee,
+		&i.Status,
+		&i.CreatedAt,
+		&i.ProviderTxHash,
+		&i.NearAmount,
+	)
+	return i, err
 }
@@ -0,0 +1,693 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/credit"
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrEmptyCart         = errors.New("cart is empty")
+	ErrInsufficientStock = errors.New("item is out of stock")
+	ErrItemNotFound      = errors.New("item not found")
+	ErrPriceChanged      = errors.New("cart price changed since checkout")
+)
+
+// IsUnfulfillableCart reports whether err means a paid cart can't be turned into
+// orders, so retrying won't help.
+func IsUnfulfillableCart(err error) bool {
+	return errors.Is(err, ErrEmptyCart) ||
+		errors.Is(err, ErrInsufficientStock) ||
+		errors.Is(err, ErrItemNotFound) ||
+		errors.Is(err, ErrVariantNotFound) ||
+		errors.Is(err, ErrVariantRequired) ||
+		errors.Is(err, ErrPriceChanged) ||
+		errors.Is(err, ErrTransactionFailed)
+}
+
+type PrepareCheckoutTxParams struct {
+	UserID          int64
+	Reference       string
+	ShippingAddress json.RawMessage
+	ReservedUntil   time.Time
+	GiftCardCodes   []string
+	UseStoreCredit  bool
+	// FlashSaleClaims holds the units claimed of each flash sale running on
+	// the cart's items, by flash sale id.
+	FlashSaleClaims map[int64]int32
+}
+
+type PrepareCheckoutTxResult struct {
+	QuoteCartTxResult
+	OrderGroup OrderGroup         `json:"order_group"`
+	Credit     credit.Application `json:"credit"`
+}
+
+// PrepareCheckoutTx prices a user's cart for checkout under reference, locking
+// the exchange rates it was priced at, and reserves every coupon that applies to
+// it. Reservations count towards the coupons' limits until the checkout
+// completes, or its coupons are released. Delivery and tax are charged for
+// where the cart ships to. The checkout's order group is PENDING until then.
+// The cart's stock is held for the checkout until ReservedUntil, failing with
+// ErrInsufficientStock if any of it isn't available; the stock of items on a
+// flash sale is held by the units claimed for them instead. The gift cards with
+// GiftCardCodes, then the user's store credit if UseStoreCredit, pay what they
+// can of it, and are held for the checkout too; Credit says what's left Due.
+// The cart's quantity of each item on a flash sale must have been claimed in
+// FlashSaleClaims, or it fails with ErrFlashSaleNotClaimed. The cart's lines
+// are saved for the checkout, which completes with them.
+func (dbTx *SQLTx) PrepareCheckoutTx(ctx context.Context, arg PrepareCheckoutTxParams) (PrepareCheckoutTxResult, error) {
+	var result PrepareCheckoutTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.QuoteCartTxResult, err = q.quoteCart(ctx, arg.UserID, shippingRegion(arg.ShippingAddress))
+		if err != nil {
+			return err
+		}
+
+		if len(result.Cart) == 0 {
+			return nil
+		}
+
+		err = q.claimFlashSales(ctx, arg.UserID, arg.Reference, result.Cart, result.FlashSales, arg.FlashSaleClaims)
+		if err != nil {
+			return err
+		}
+
+		err = q.reserveStock(ctx, arg.UserID, arg.Reference, result.Cart, arg.ReservedUntil, flashSaleItems(result.FlashSales))
+		if err != nil {
+			return err
+		}
+
+		if err := q.saveCheckoutLines(ctx, arg.Reference, result.Cart); err != nil {
+			return err
+		}
+
+		result.OrderGroup, err = q.createOrderGroup(ctx, arg.UserID, arg.Reference, arg.ShippingAddress, result.Breakdown)
+		if err != nil {
+			return err
+		}
+
+		locked := make(map[string]bool)
+		for _, line := range result.Breakdown.Lines {
+			if line.FxRate == "" || locked[line.Currency] {
+				continue
+			}
+
+			err = q.CreateCheckoutFxRate(ctx, CreateCheckoutFxRateParams{
+				Reference: arg.Reference,
+				Currency:  line.Currency,
+				Rate:      line.FxRate,
+			})
+			if err != nil {
+				return err
+			}
+			locked[line.Currency] = true
+		}
+
+		for _, cb := range result.Breakdown.Coupons {
+			if !cb.Applied {
+				continue
+			}
+
+			// Lock the coupon, so concurrent checkouts count each other's reservations.
+			coupon, err := q.GetCouponForUpdate(ctx, cb.CouponID)
+			if err != nil {
+				return err
+			}
+
+			if err := q.checkCoupon(ctx, coupon, arg.UserID); err != nil {
+				return fmt.Errorf("coupon %s: %w", coupon.Code, err)
+			}
+
+			_, err = q.CreateCouponRedemption(ctx, CreateCouponRedemptionParams{
+				CouponID:       coupon.ID,
+				UserID:         arg.UserID,
+				Reference:      arg.Reference,
+				DiscountAmount: cb.Discount.String(),
+				Status:         RedemptionReserved,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Credit, err = q.reserveCredits(ctx, arg.UserID, arg.Reference, result.Breakdown, arg.GiftCardCodes, arg.UseStoreCredit)
+		return err
+	})
+
+	return result, err
+}
+
+// TransactionCartItem is a cart line as expected by process_transaction_completion.
+type TransactionCartItem struct {
+	ItemID               int64  `json:"item_id"`
+	VariantID            *int64 `json:"variant_id"`
+	Quantity             int32  `json:"quantity"`
+	UnitPrice            string `json:"unit_price"`
+	Currency             string `json:"currency"`
+	FxRate               string `json:"fx_rate"`
+	DeliveryFee          string `json:"delivery_fee"`
+	Discount             string `json:"discount"`
+	CouponID             *int64 `json:"coupon_id"`
+	TaxRate              string `json:"tax_rate"`
+	TaxInclusive         bool   `json:"tax_inclusive"`
+	Tax                  string `json:"tax"`
+	DeliveryDays         int32  `json:"delivery_days"`
+	CommissionRuleID     *int64 `json:"commission_rule_id"`
+	CommissionPercentage string `json:"commission_percentage"`
+	CommissionFixed      string `json:"commission_fixed"`
+	Commission           string `json:"commission"`
+}
+
+type CheckoutCartTxParams struct {
+	UserID          int64
+	ProviderTxRefID string
+	ProviderTxFee   string
+}
+
+type CheckoutCartTxResult struct {
+	Transaction Transaction `json:"transaction"`
+	OrderGroup  OrderGroup  `json:"order_group"`
+	CartID      int64       `json:"cart_id"`
+}
+
+// CheckoutCartTx completes a paid checkout, turning the cart lines saved when
+// it started into orders and taking them out of the user's cart.
+//
+// The lines are re-checked against stock and repriced as the checkout was
+// quoted; nothing is created unless the price matches what was paid. What was
+// reserved for the checkout is then redeemed, and each store issues an invoice
+// for its orders, under the checkout's PLACED order group.
+func (dbTx *SQLTx) CheckoutCartTx(ctx context.Context, arg CheckoutCartTxParams) (CheckoutCartTxResult, error) {
+	var result CheckoutCartTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock the transaction, so a repeated completion waits and then sees it COMPLETED.
+		result.Transaction, err = q.GetTransactionByRefIDForUpdate(ctx, arg.ProviderTxRefID)
+		if err != nil {
+			return err
+		}
+
+		if result.Transaction.Status == "COMPLETED" {
+			return nil
+		}
+
+		// A payment arriving after the checkout was failed, by reconciliation
+		// or abandonment, creates no orders; it's refunded instead.
+		if result.Transaction.Status != "PROCESSING" {
+			return ErrTransactionFailed
+		}
+
+		result.CartID, err = q.GetCartID(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		// Lines added to the cart after checkout weren't paid for.
+		cart, err := q.checkoutCart(ctx, arg.ProviderTxRefID)
+		if err != nil {
+			return err
+		}
+
+		if len(cart) == 0 {
+			return ErrEmptyCart
+		}
+
+		// Items are priced at the flash sales claimed for them, even if those have since ended.
+		flashSales, err := q.ListCheckoutFlashSales(ctx, ListCheckoutFlashSalesParams{
+			Reference: arg.ProviderTxRefID,
+			Status:    FlashClaimed,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The stock reserved for this checkout counts as available, even if the
+		// reservation has since expired, as long as nobody else has taken it.
+		if err := q.checkStock(ctx, cart, arg.ProviderTxRefID, flashSaleItems(flashSales)); err != nil {
+			return err
+		}
+
+		reserved, err := q.ListRedeemedCoupons(ctx, ListRedeemedCouponsParams{
+			Reference: arg.ProviderTxRefID,
+			Status:    RedemptionReserved,
+		})
+		if err != nil {
+			return err
+		}
+
+		coupons, err := pricingCoupons(reserved)
+		if err != nil {
+			return err
+		}
+
+		lockedRates, err := q.ListCheckoutFxRates(ctx, arg.ProviderTxRefID)
+		if err != nil {
+			return err
+		}
+
+		lines, err := q.withFxRates(ctx, withFlashSales(cartLines(cart), flashSales), checkoutFxRates(lockedRates))
+		if err != nil {
+			return err
+		}
+
+		region, err := q.checkoutRegion(ctx, arg.ProviderTxRefID)
+		if err != nil {
+			return err
+		}
+
+		breakdown, err := q.quoteLines(ctx, lines, region, coupons...)
+		if err != nil {
+			return err
+		}
+
+		breakdown, err = q.withTax(ctx, breakdown, lines, region)
+		if err != nil {
+			return err
+		}
+
+		amount, err := money.Parse(result.Transaction.Amount)
+		if err != nil {
+			return err
+		}
+
+		// Gift cards and store credit paid the rest of the cart.
+		credits, err := q.ListCheckoutCredits(ctx, ListCheckoutCreditsParams{
+			Reference: arg.ProviderTxRefID,
+			Status:    CreditReserved,
+		})
+		if err != nil {
+			return err
+		}
+
+		giftCards, storeCredit, err := creditTotals(credits)
+		if err != nil {
+			return err
+		}
+
+		if paid := amount + giftCards + storeCredit; breakdown.GrandTotal != paid {
+			return fmt.Errorf("%w: paid %s, cart now costs %s", ErrPriceChanged, paid, breakdown.GrandTotal)
+		}
+
+		categories := make(map[int64]string, len(cart))
+		for _, cartItem := range cart {
+			categories[cartItem.ItemID] = cartItem.ItemCategory
+		}
+
+		commissions, err := q.lineCommissions(ctx, breakdown, categories)
+		if err != nil {
+			return err
+		}
+
+		deliveryDays := make(map[int64]int32, len(breakdown.Stores))
+		for _, store := range breakdown.Stores {
+			deliveryDays[store.StoreID] = store.MaxDeliveryDays
+		}
+
+		cartItems := make([]TransactionCartItem, 0, len(breakdown.Lines))
+		for i, line := range breakdown.Lines {
+			cartItem := TransactionCartItem{
+				ItemID:               line.ItemID,
+				Quantity:             line.Quantity,
+				UnitPrice:            line.UnitPrice.String(),
+				Currency:             breakdown.Currency,
+				FxRate:               "1",
+				DeliveryFee:          line.DeliveryFee.String(),
+				Discount:             line.Discount.String(),
+				TaxRate:              "0",
+				TaxInclusive:         line.TaxInclusive,
+				Tax:                  line.Tax.String(),
+				DeliveryDays:         deliveryDays[line.StoreID],
+				CommissionPercentage: commissions[i].Percentage,
+				CommissionFixed:      commissions[i].Fixed.String(),
+				Commission:           commissions[i].Amount.String(),
+			}
+			if line.VariantID != 0 {
+				cartItem.VariantID = &breakdown.Lines[i].VariantID
+			}
+			if line.FxRate != "" {
+				cartItem.FxRate = line.FxRate
+			}
+			if line.CouponID != 0 {
+				cartItem.CouponID = &breakdown.Lines[i].CouponID
+			}
+			if line.TaxRate != "" {
+				cartItem.TaxRate = line.TaxRate
+			}
+			if commissions[i].RuleID.Valid {
+				cartItem.CommissionRuleID = &commissions[i].RuleID.Int64
+			}
+			cartItems = append(cartItems, cartItem)
+		}
+
+		cartItemsJSON, err := json.Marshal(cartItems)
+		if err != nil {
+			return err
+		}
+
+		// the stock ProcessTransaction deducts is sold to the buyer
+		err = q.setStockMovementContext(ctx, stockMovementContext{
+			Reason:    StockSale,
+			UserID:    arg.UserID,
+			Reference: arg.ProviderTxRefID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transaction, err = q.ProcessTransaction(ctx, ProcessTransactionParams{
+			ProviderTxRefID: arg.ProviderTxRefID,
+			Status:          "COMPLETED",
+			ProviderTxFee:   arg.ProviderTxFee,
+			CartItems:       cartItemsJSON,
+		})
+		if err != nil {
+			// Flash sale lines aren't checked above, so their supply may have run out.
+			if isSupplyExhausted(err) {
+				return fmt.Errorf("%w: %v", ErrInsufficientStock, err)
+			}
+			return err
+		}
+
+		err = q.postCapture(ctx, result.Transaction, breakdown, commissions, arg.ProviderTxFee, credits)
+		if err != nil {
+			return err
+		}
+
+		result.OrderGroup, err = q.placeCheckoutOrderGroup(ctx, result.Transaction, breakdown)
+		if err != nil {
+			return err
+		}
+
+		if err := q.createInvoices(ctx, result.Transaction); err != nil {
+			return err
+		}
+
+		_, err = q.UpdateCouponRedemptionsStatus(ctx, UpdateCouponRedemptionsStatusParams{
+			Reference:  arg.ProviderTxRefID,
+			FromStatus: RedemptionReserved,
+			ToStatus:   RedemptionRedeemed,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateCheckoutCreditsStatus(ctx, UpdateCheckoutCreditsStatusParams{
+			Reference:  arg.ProviderTxRefID,
+			FromStatus: CreditReserved,
+			ToStatus:   CreditRedeemed,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.convertFlashSaleClaims(ctx, arg.ProviderTxRefID); err != nil {
+			return err
+		}
+
+		// ProcessTransaction deducted the stock the reservations held.
+		_, err = q.UpdateStockReservationsStatus(ctx, UpdateStockReservationsStatusParams{
+			Reference:  arg.ProviderTxRefID,
+			FromStatus: ReservationReserved,
+			ToStatus:   ReservationConverted,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.ClearCartCoupons(ctx, result.CartID); err != nil {
+			return err
+		}
+
+		return q.RemoveCheckoutLinesFromCart(ctx, RemoveCheckoutLinesFromCartParams{
+			CartID:    result.CartID,
+			Reference: arg.ProviderTxRefID,
+		})
+	})
+
+	return result, err
+}
+
+// AbandonCheckoutTx releases the coupons, stock, gift cards, store credit and
+// flash sale units reserved under reference, and fails the checkout's order group.
+func (dbTx *SQLTx) AbandonCheckoutTx(ctx context.Context, reference string) error {
+	return dbTx.execTx(ctx, func(q *Queries) error {
+		_, err := q.UpdateCouponRedemptionsStatus(ctx, UpdateCouponRedemptionsStatusParams{
+			Reference:  reference,
+			FromStatus: RedemptionReserved,
+			ToStatus:   RedemptionReleased,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.releaseStock(ctx, reference); err != nil {
+			return err
+		}
+
+		if err := q.releaseCredits(ctx, reference); err != nil {
+			return err
+		}
+
+		if err := q.releaseFlashSaleClaims(ctx, reference); err != nil {
+			return err
+		}
+
+		return q.FailOrderGroup(ctx, reference)
+	})
+}
+
+type FailTransactionTxParams struct {
+	ProviderTxRefID string
+	ProviderTxFee   string
+}
+
+// FailTransactionTx marks a transaction FAILED without creating any order,
+// releases the coupons, stock, gift cards, store credit and flash sale units
+// reserved for it and fails its order group. A gift card bought under it is
+// FAILED too.
+func (dbTx *SQLTx) FailTransactionTx(ctx context.Context, arg FailTransactionTxParams) (Transaction, error) {
+	var transaction Transaction
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+		transaction, err = q.failTransaction(ctx, arg)
+		return err
+	})
+
+	return transaction, err
+}
+
+func (q *Queries) failTransaction(ctx context.Context, arg FailTransactionTxParams) (Transaction, error) {
+	transaction, err := q.ProcessTransaction(ctx, ProcessTransactionParams{
+		ProviderTxRefID: arg.ProviderTxRefID,
+		Status:          "FAILED",
+		ProviderTxFee:   arg.ProviderTxFee,
+		CartItems:       json.RawMessage("[]"),
+	})
+	if err != nil {
+		return transaction, err
+	}
+
+	_, err = q.UpdateCouponRedemptionsStatus(ctx, UpdateCouponRedemptionsStatusParams{
+		Reference:  arg.ProviderTxRefID,
+		FromStatus: RedemptionReserved,
+		ToStatus:   RedemptionReleased,
+	})
+	if err != nil {
+		return transaction, err
+	}
+
+	if err := q.releaseStock(ctx, arg.ProviderTxRefID); err != nil {
+		return transaction, err
+	}
+
+	if err := q.releaseCredits(ctx, arg.ProviderTxRefID); err != nil {
+		return transaction, err
+	}
+
+	if err := q.releaseFlashSaleClaims(ctx, arg.ProviderTxRefID); err != nil {
+		return transaction, err
+	}
+
+	_, err = q.UpdateGiftCardPurchaseStatus(ctx, UpdateGiftCardPurchaseStatusParams{
+		Reference: arg.ProviderTxRefID,
+		Status:    GiftCardFailed,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return transaction, err
+	}
+
+	return transaction, q.FailOrderGroup(ctx, arg.ProviderTxRefID)
+}
+
+// isSupplyExhausted reports whether err is a sale refused for taking an
+// item's or a variant's supply below zero.
+func isSupplyExhausted(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code.Name() != "check_violation" {
+		return false
+	}
+	return pqErr.Constraint == "valid_item_supply" || pqErr.Constraint == "valid_item_variant"
+}
+
+// Statuses of a CheckoutRefund.
+const (
+	CheckoutRefundPending   = "PENDING"
+	CheckoutRefundCompleted = "COMPLETED"
+	CheckoutRefundFailed    = "FAILED"
+)
+
+type FailPaidCheckoutTxParams struct {
+	ProviderTxRefID string
+	ProviderTxFee   string
+	Reason          string
+	Amount          string // what was paid, if not the transaction's amount
+	NEARReceiverID  string // the buyer's NEAR account, for a NEAR wallet payment
+	NEARAmount      string // in yoctoNEAR, for a NEAR wallet payment
+}
+
+type FailPaidCheckoutTxResult struct {
+	Transaction  Transaction
+	Refund       CheckoutRefund
+	NEARTransfer NearTransfer // sends Refund, for a NEAR wallet payment
+	Created      bool         // false if the checkout was already refunded
+}
+
+// FailPaidCheckoutTx fails a checkout that was paid but can't be completed,
+// as FailTransactionTx does, and records a PENDING refund of everything its
+// provider charged, the transaction's amount unless told otherwise. For a NEAR wallet payment, the NEAR transfer that sends
+// it is recorded too. A checkout already refunded keeps its refund.
+func (dbTx *SQLTx) FailPaidCheckoutTx(ctx context.Context, arg FailPaidCheckoutTxParams) (FailPaidCheckoutTxResult, error) {
+	var result FailPaidCheckoutTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock the transaction, so a repeated failure waits and then finds its refund.
+		result.Transaction, err = q.GetTransactionByRefIDForUpdate(ctx, arg.ProviderTxRefID)
+		if err != nil {
+			return err
+		}
+
+		result.Refund, err = q.GetCheckoutRefundByTransaction(ctx, result.Transaction.ID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		result.Transaction, err = q.failTransaction(ctx, FailTransactionTxParams{
+			ProviderTxRefID: arg.ProviderTxRefID,
+			ProviderTxFee:   arg.ProviderTxFee,
+		})
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount == "" {
+			amount = result.Transaction.Amount
+		}
+
+		result.Refund, err = q.CreateCheckoutRefund(ctx, CreateCheckoutRefundParams{
+			TransactionID: result.Transaction.ID,
+			Amount:        amount,
+			Reason:        arg.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		result.Created = true
+
+		if result.Transaction.PaymentProvider != "NEAR_WALLET" {
+			return nil
+		}
+
+		result.NEARTransfer, err = q.CreateNEARTransfer(ctx, CreateNEARTransferParams{
+			Reference:        arg.ProviderTxRefID,
+			CheckoutRefundID: sql.NullInt64{Int64: result.Refund.ID, Valid: true},
+			ReceiverID:       arg.NEARReceiverID,
+			Amount:           arg.NEARAmount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// saveCheckoutLines saves the lines of cart for the checkout under reference,
+// so it completes with what was priced, whatever the cart holds by then.
+func (q *Queries) saveCheckoutLines(ctx context.Context, reference string, cart []GetCartByUserIDRow) error {
+	for _, cartItem := range cart {
+		err := q.CreateCheckoutLine(ctx, CreateCheckoutLineParams{
+			Reference:          reference,
+			ItemID:             cartItem.ItemID,
+			VariantID:          cartItem.VariantID,
+			VariantSku:         cartItem.VariantSku,
+			StoreID:            cartItem.StoreID,
+			ItemName:           cartItem.ItemName,
+			Price:              cartItem.Price,
+			DiscountPercentage: cartItem.DiscountPercentage,
+			ItemCategory:       cartItem.ItemCategory,
+			ItemCurrency:       cartItem.ItemCurrency,
+			ItemWeightGrams:    cartItem.ItemWeightGrams,
+			Quantity:           cartItem.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkoutCart returns the cart lines saved for the checkout under reference,
+// as the cart items they were saved from.
+func (q *Queries) checkoutCart(ctx context.Context, reference string) ([]GetCartByUserIDRow, error) {
+	lines, err := q.ListCheckoutLines(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := make([]GetCartByUserIDRow, 0, len(lines))
+	for _, line := range lines {
+		cart = append(cart, GetCartByUserIDRow{
+			ItemID:             line.ItemID,
+			VariantID:          line.VariantID,
+			VariantSku:         line.VariantSku,
+			StoreID:            line.StoreID,
+			ItemName:           line.ItemName,
+			Price:              line.Price,
+			DiscountPercentage: line.DiscountPercentage,
+			ItemCategory:       line.ItemCategory,
+			ItemCurrency:       line.ItemCurrency,
+			ItemWeightGrams:    line.ItemWeightGrams,
+			Quantity:           line.Quantity,
+		})
+	}
+	return cart, nil
+}
+
+// checkoutFxRates converts the rates locked at checkout for the fx component.
+func checkoutFxRates(rates []CheckoutFxRate) fx.Rates {
+	out := make(fx.Rates, len(rates))
+	for _, r := range rates {
+		out[r.Currency] = r.Rate
+	}
+	return out
+}
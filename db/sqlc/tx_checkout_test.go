@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckoutCartTxLatePayment(t *testing.T) {
+	user := createRandomUser(t)
+	reference := util.RandomString(32)
+
+	_, err := testQueries.CreateTransaction(context.Background(), CreateTransactionParams{
+		CustomerID:      user.ID,
+		Amount:          "2500.00",
+		PaymentProvider: "PAYSTACK",
+		ProviderTxRefID: reference,
+	})
+	require.NoError(t, err)
+
+	// reconciliation gave up on the payment before it arrived
+	transaction, err := testQueries.FailTransactionTx(context.Background(), FailTransactionTxParams{
+		ProviderTxRefID: reference,
+		ProviderTxFee:   "0",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "FAILED", transaction.Status)
+
+	// then Paystack's charge.success webhook came
+	_, err = testQueries.CheckoutCartTx(context.Background(), CheckoutCartTxParams{
+		UserID:          user.ID,
+		ProviderTxRefID: reference,
+		ProviderTxFee:   "37.50",
+	})
+	require.ErrorIs(t, err, ErrTransactionFailed)
+	require.True(t, IsUnfulfillableCart(err))
+	reason := err.Error()
+
+	result, err := testQueries.FailPaidCheckoutTx(context.Background(), FailPaidCheckoutTxParams{
+		ProviderTxRefID: reference,
+		ProviderTxFee:   "37.50",
+		Reason:          reason,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Created)
+	require.Equal(t, transaction.ID, result.Refund.TransactionID)
+	require.Equal(t, "2500.00", result.Refund.Amount)
+	require.Equal(t, "PENDING", result.Refund.Status)
+
+	// a redelivered webhook finds the refund already under way
+	again, err := testQueries.FailPaidCheckoutTx(context.Background(), FailPaidCheckoutTxParams{
+		ProviderTxRefID: reference,
+		ProviderTxFee:   "37.50",
+		Reason:          reason,
+	})
+	require.NoError(t, err)
+	require.False(t, again.Created)
+	require.Equal(t, result.Refund.ID, again.Refund.ID)
+
+	transaction, err = testQueries.GetTransactionByRefID(context.Background(), reference)
+	require.NoError(t, err)
+	require.Equal(t, "FAILED", transaction.Status)
+}
+
+func TestFailPaidCheckoutTxAmountMismatch(t *testing.T) {
+	user := createRandomUser(t)
+	reference := util.RandomString(32)
+
+	_, err := testQueries.CreateTransaction(context.Background(), CreateTransactionParams{
+		CustomerID:      user.ID,
+		Amount:          "2500.00",
+		PaymentProvider: "PAYSTACK",
+		ProviderTxRefID: reference,
+	})
+	require.NoError(t, err)
+
+	// the buyer paid less than the checkout's total
+	result, err := testQueries.FailPaidCheckoutTx(context.Background(), FailPaidCheckoutTxParams{
+		ProviderTxRefID: reference,
+		ProviderTxFee:   "15.00",
+		Reason:          "paid 1000.00, expected 2500.00",
+		Amount:          "1000.00",
+	})
+	require.NoError(t, err)
+	require.True(t, result.Created)
+	require.Equal(t, "FAILED", result.Transaction.Status)
+	require.Equal(t, "1000.00", result.Refund.Amount)
+}
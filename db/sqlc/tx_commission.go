@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/OCD-Labs/store-hub/commission"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+// LineCommission is the commission taken from an order line.
+type LineCommission struct {
+	RuleID     sql.NullInt64
+	Percentage string
+	Fixed      money.Amount
+	Amount     money.Amount
+}
+
+// Rule converts r for the commission component.
+func (r CommissionRule) Rule() (commission.Rule, error) {
+	fixed, err := money.Parse(r.FixedAmount)
+	if err != nil {
+		return commission.Rule{}, err
+	}
+
+	return commission.Rule{
+		ID:         r.ID,
+		Percentage: r.Percentage,
+		Fixed:      fixed,
+	}, nil
+}
+
+// lineCommissions works out the commission on each line of breakdown, under
+// the rules for the lines' stores and categories. categories maps item IDs
+// to their category.
+func (q *Queries) lineCommissions(ctx context.Context, breakdown pricing.Breakdown, categories map[int64]string) ([]LineCommission, error) {
+	storeIDs := make([]int64, 0, len(breakdown.Stores))
+	for _, store := range breakdown.Stores {
+		storeIDs = append(storeIDs, store.StoreID)
+	}
+
+	categoryNames := make([]string, 0, len(categories))
+	for _, category := range categories {
+		categoryNames = append(categoryNames, category)
+	}
+
+	rules, err := q.ListApplicableCommissionRules(ctx, ListApplicableCommissionRulesParams{
+		StoreIds:   storeIDs,
+		Categories: categoryNames,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule commission.Schedule
+	for _, r := range rules {
+		rule, err := r.Rule()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := schedule.Add(r.Scope, r.StoreID.Int64, r.Category.String, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	commissions := make([]LineCommission, len(breakdown.Lines))
+	for i, line := range breakdown.Lines {
+		rule, ok := schedule.RuleFor(line.StoreID, categories[line.ItemID])
+		if !ok {
+			commissions[i] = LineCommission{Percentage: "0"}
+			continue
+		}
+
+		amount, err := rule.Fee(line.LineTotal-line.Discount, line.Total())
+		if err != nil {
+			return nil, err
+		}
+
+		commissions[i] = LineCommission{
+			RuleID:     sql.NullInt64{Int64: rule.ID, Valid: true},
+			Percentage: rule.Percentage,
+			Fixed:      rule.Fixed,
+			Amount:     amount,
+		}
+	}
+
+	return commissions, nil
+}
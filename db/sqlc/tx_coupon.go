@@ -0,0 +1,400 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+var (
+	ErrCartNotFound        = errors.New("cart not found")
+	ErrCouponNotFound      = errors.New("coupon not found")
+	ErrCouponInactive      = errors.New("coupon is not active")
+	ErrCouponNotValidNow   = errors.New("coupon is not valid at this time")
+	ErrCouponExhausted     = errors.New("coupon has been used up")
+	ErrCouponUserLimit     = errors.New("coupon has already been used the maximum number of times by this user")
+	ErrCouponNotApplicable = errors.New("coupon does not apply")
+)
+
+// IsCouponRejected reports whether err means a coupon can't be used, so the
+// buyer should be told why.
+func IsCouponRejected(err error) bool {
+	return errors.Is(err, ErrCouponNotFound) ||
+		errors.Is(err, ErrCouponInactive) ||
+		errors.Is(err, ErrCouponNotValidNow) ||
+		errors.Is(err, ErrCouponExhausted) ||
+		errors.Is(err, ErrCouponUserLimit) ||
+		errors.Is(err, ErrCouponNotApplicable)
+}
+
+// Statuses of a CouponRedemption.
+const (
+	RedemptionReserved = "RESERVED"
+	RedemptionRedeemed = "REDEEMED"
+	RedemptionReleased = "RELEASED"
+)
+
+// PricingCoupon converts c for the pricing component.
+func (c Coupon) PricingCoupon() (pricing.Coupon, error) {
+	minSpend, err := money.Parse(c.MinSpend)
+	if err != nil {
+		return pricing.Coupon{}, err
+	}
+
+	return pricing.Coupon{
+		ID:       c.ID,
+		Code:     c.Code,
+		StoreID:  c.StoreID,
+		Kind:     c.DiscountType,
+		Value:    c.DiscountValue,
+		ItemIDs:  c.ItemIds,
+		Category: c.Category,
+		MinSpend: minSpend,
+	}, nil
+}
+
+// usable checks c is active and within its validity window at now.
+func (c Coupon) usable(now time.Time) error {
+	if !c.IsActive {
+		return ErrCouponInactive
+	}
+
+	if now.Before(c.StartsAt) || (c.EndsAt.Valid && !now.Before(c.EndsAt.Time)) {
+		return ErrCouponNotValidNow
+	}
+
+	return nil
+}
+
+// checkCoupon checks userID can redeem c once more. The limits are only
+// enforced when c's row is locked.
+func (q *Queries) checkCoupon(ctx context.Context, c Coupon, userID int64) error {
+	if err := c.usable(time.Now()); err != nil {
+		return err
+	}
+
+	if c.UsageLimit == 0 && c.PerUserLimit == 0 {
+		return nil
+	}
+
+	used, err := q.CountCouponRedemptions(ctx, CountCouponRedemptionsParams{
+		CouponID: c.ID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.UsageLimit > 0 && used.Total >= int64(c.UsageLimit) {
+		return ErrCouponExhausted
+	}
+
+	if c.PerUserLimit > 0 && used.ByUser >= int64(c.PerUserLimit) {
+		return ErrCouponUserLimit
+	}
+
+	return nil
+}
+
+// pricingCoupons converts coupons for the pricing component.
+func pricingCoupons(coupons []Coupon) ([]pricing.Coupon, error) {
+	pcs := make([]pricing.Coupon, 0, len(coupons))
+	for _, c := range coupons {
+		pc, err := c.PricingCoupon()
+		if err != nil {
+			return nil, err
+		}
+		pcs = append(pcs, pc)
+	}
+	return pcs, nil
+}
+
+// appliedCoupon finds how coupon applied in breakdown, failing with
+// ErrCouponNotApplicable if it took nothing off.
+func appliedCoupon(breakdown pricing.Breakdown, couponID int64) (pricing.CouponBreakdown, error) {
+	for _, cb := range breakdown.Coupons {
+		if cb.CouponID != couponID {
+			continue
+		}
+		if !cb.Applied {
+			return cb, fmt.Errorf("%w: %s", ErrCouponNotApplicable, cb.Reason)
+		}
+		return cb, nil
+	}
+	return pricing.CouponBreakdown{}, ErrCouponNotApplicable
+}
+
+type ApplyCartCouponTxParams struct {
+	UserID int64
+	CartID int64
+	Code   string
+}
+
+type ApplyCartCouponTxResult struct {
+	Coupon    Coupon            `json:"coupon"`
+	Breakdown pricing.Breakdown `json:"breakdown"`
+}
+
+// ApplyCartCouponTx applies the coupon with a code to a user's cart, replacing
+// any coupon already applied for the same store. The coupon must be usable by
+// the user and take something off the cart.
+func (dbTx *SQLTx) ApplyCartCouponTx(ctx context.Context, arg ApplyCartCouponTxParams) (ApplyCartCouponTxResult, error) {
+	var result ApplyCartCouponTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		cartID, err := q.GetCartID(ctx, arg.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrCartNotFound
+			}
+			return err
+		}
+
+		if cartID != arg.CartID {
+			return ErrCartNotFound
+		}
+
+		result.Coupon, err = q.GetCouponByCode(ctx, arg.Code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrCouponNotFound
+			}
+			return err
+		}
+
+		if err := q.checkCoupon(ctx, result.Coupon, arg.UserID); err != nil {
+			return err
+		}
+
+		cart, err := q.GetCartByUserID(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		applied, err := q.ListCartCoupons(ctx, cartID)
+		if err != nil {
+			return err
+		}
+
+		coupons := []Coupon{result.Coupon}
+		for _, c := range applied {
+			if c.StoreID != result.Coupon.StoreID {
+				coupons = append(coupons, c)
+			}
+		}
+
+		pcs, err := pricingCoupons(coupons)
+		if err != nil {
+			return err
+		}
+
+		lines, _, err := q.liveFlashSaleLines(ctx, cart)
+		if err != nil {
+			return err
+		}
+
+		result.Breakdown, err = q.quoteLines(ctx, lines, pricing.Region{}, pcs...)
+		if err != nil {
+			return err
+		}
+
+		if _, err := appliedCoupon(result.Breakdown, result.Coupon.ID); err != nil {
+			return err
+		}
+
+		_, err = q.AddCartCoupon(ctx, AddCartCouponParams{
+			CartID:   cartID,
+			CouponID: result.Coupon.ID,
+			StoreID:  result.Coupon.StoreID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CreateOrderTxParams struct {
+	Line            pricing.Line
+	CouponCode      string
+	BuyerID         int64
+	SellerID        int64
+	PaymentChannel  string
+	PaymentMethod   string
+	ShippingAddress json.RawMessage
+}
+
+type CreateOrderTxResult struct {
+	Order      Order             `json:"order"`
+	OrderGroup OrderGroup        `json:"order_group"`
+	Breakdown  pricing.Breakdown `json:"breakdown"`
+}
+
+// CreateOrderTx prices and creates an order for a single line, in an order
+// group of its own, charging delivery and tax for where it ships to. When a coupon code is given,
+// the coupon is redeemed for the order, failing if it can't be. An item with
+// variants is ordered in the line's variant, at its price, and fails without one.
+func (dbTx *SQLTx) CreateOrderTx(ctx context.Context, arg CreateOrderTxParams) (CreateOrderTxResult, error) {
+	var result CreateOrderTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var coupon Coupon
+		var coupons []pricing.Coupon
+
+		itemVariant, err := q.lineVariant(ctx, arg.Line)
+		if err != nil {
+			return err
+		}
+		if itemVariant.Price.Valid {
+			arg.Line.Price = itemVariant.Price.String
+		}
+
+		if arg.CouponCode != "" {
+			found, err := q.GetCouponByCode(ctx, arg.CouponCode)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return ErrCouponNotFound
+				}
+				return err
+			}
+
+			// Lock the coupon, so concurrent orders count each other's redemptions.
+			coupon, err = q.GetCouponForUpdate(ctx, found.ID)
+			if err != nil {
+				return err
+			}
+
+			if err := q.checkCoupon(ctx, coupon, arg.BuyerID); err != nil {
+				return err
+			}
+
+			pc, err := coupon.PricingCoupon()
+			if err != nil {
+				return err
+			}
+			coupons = append(coupons, pc)
+		}
+
+		region := shippingRegion(arg.ShippingAddress)
+		result.Breakdown, err = q.quoteLines(ctx, []pricing.Line{arg.Line}, region, coupons...)
+		if err != nil {
+			return err
+		}
+
+		result.Breakdown, err = q.withTax(ctx, result.Breakdown, []pricing.Line{arg.Line}, region)
+		if err != nil {
+			return err
+		}
+
+		line := result.Breakdown.Lines[0]
+		fxRate := line.FxRate
+		if fxRate == "" {
+			fxRate = "1"
+		}
+
+		result.Order, err = q.CreateOrderFn(ctx, CreateOrderFnParams{
+			ItemID:         arg.Line.ItemID,
+			OrderQuantity:  arg.Line.Quantity,
+			BuyerID:        arg.BuyerID,
+			SellerID:       arg.SellerID,
+			StoreID:        arg.Line.StoreID,
+			ItemPrice:      line.UnitPrice.String(),
+			DeliveryFee:    line.DeliveryFee.String(),
+			PaymentChannel: arg.PaymentChannel,
+			PaymentMethod:  arg.PaymentMethod,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Order, err = q.SetOrderFxRate(ctx, SetOrderFxRateParams{
+			OrderID:  result.Order.ID,
+			Currency: result.Breakdown.Currency,
+			FxRate:   fxRate,
+		})
+		if err != nil {
+			return err
+		}
+
+		if itemVariant.ID != 0 {
+			result.Order, err = q.SetOrderVariant(ctx, SetOrderVariantParams{
+				OrderID:    result.Order.ID,
+				VariantID:  sql.NullInt64{Int64: itemVariant.ID, Valid: true},
+				VariantSku: itemVariant.Sku,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if date, ok := expectedDeliveryDate(result.Breakdown.Stores[0], time.Now()); ok {
+			result.Order, err = q.SetOrderExpectedDeliveryDate(ctx, SetOrderExpectedDeliveryDateParams{
+				OrderID:              result.Order.ID,
+				ExpectedDeliveryDate: date,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if line.TaxRuleID != 0 {
+			result.Order, err = q.SetOrderTax(ctx, SetOrderTaxParams{
+				OrderID:      result.Order.ID,
+				TaxRate:      line.TaxRate,
+				TaxInclusive: line.TaxInclusive,
+				TaxAmount:    line.Tax.String(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		reference := fmt.Sprintf("order:%d", result.Order.ID)
+		orderGroup, err := q.createOrderGroup(ctx, arg.BuyerID, reference, arg.ShippingAddress, result.Breakdown)
+		if err != nil {
+			return err
+		}
+
+		if arg.CouponCode != "" {
+			if err := q.redeemOrderCoupon(ctx, &result, coupon, arg.BuyerID, reference); err != nil {
+				return err
+			}
+		}
+
+		result.OrderGroup, err = q.placeOrderGroup(ctx, orderGroup.ID, []int64{result.Order.ID})
+		return err
+	})
+
+	return result, err
+}
+
+// redeemOrderCoupon redeems coupon for the order in result, under reference.
+func (q *Queries) redeemOrderCoupon(ctx context.Context, result *CreateOrderTxResult, coupon Coupon, buyerID int64, reference string) error {
+	cb, err := appliedCoupon(result.Breakdown, coupon.ID)
+	if err != nil {
+		return err
+	}
+
+	result.Order, err = q.SetOrderCoupon(ctx, SetOrderCouponParams{
+		OrderID:        result.Order.ID,
+		CouponID:       sql.NullInt64{Int64: coupon.ID, Valid: true},
+		DiscountAmount: cb.Discount.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateCouponRedemption(ctx, CreateCouponRedemptionParams{
+		CouponID:       coupon.ID,
+		UserID:         buyerID,
+		Reference:      reference,
+		DiscountAmount: cb.Discount.String(),
+		Status:         RedemptionRedeemed,
+	})
+	return err
+}
@@ -19,7 +19,8 @@ type CreateReviewTxParams struct {
 	Comment string
 }
 
-// CreateReviewTx create a review for an item under a store, updates an order.
+// CreateReviewTx create a review for an item under a store, in the variant the
+// order was for, updates an order.
 func (dbTx SQLTx) CreateReviewTx(ctx context.Context, arg CreateReviewTxParams) error {
 	hasMade, err := dbTx.HasMadePurchase(ctx, HasMadePurchaseParams{
 		CustomerID: arg.UserID,
@@ -52,6 +53,7 @@ func (dbTx SQLTx) CreateReviewTx(ctx context.Context, arg CreateReviewTxParams)
 			StoreID:            arg.StoreID,
 			UserID:             arg.UserID,
 			ItemID:             arg.ItemID,
+			VariantID:          order.VariantID,
 			Rating:             arg.Rating,
 			ReviewType:         "type:item_review",
 			Comment:            arg.Comment,
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+var (
+	ErrFlashSaleOverlaps     = errors.New("item already has a flash sale at that time")
+	ErrFlashSaleExceedsStock = errors.New("flash sale quantity exceeds the item's stock")
+	ErrFlashSaleNotClaimed   = errors.New("flash sale units were not claimed for the cart")
+)
+
+// Statuses of a FlashSaleClaim.
+const (
+	FlashClaimed   = "CLAIMED"
+	FlashConverted = "CONVERTED"
+	FlashReleased  = "RELEASED"
+)
+
+// CreateFlashSaleTx creates a flash sale for an item of a store, once it checks
+// the item has the sale's quantity in stock, net of reservations, and no other
+// flash sale at the same time.
+func (dbTx *SQLTx) CreateFlashSaleTx(ctx context.Context, arg CreateFlashSaleParams) (FlashSale, error) {
+	var flashSale FlashSale
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		// Lock the item, so concurrent flash sales for it see each other.
+		available, err := q.availableStock(ctx, arg.ItemID, arg.StoreID, "")
+		if err != nil {
+			return err
+		}
+
+		if int64(arg.Quantity) > available {
+			return fmt.Errorf("%w: %d left", ErrFlashSaleExceedsStock, available)
+		}
+
+		overlapping, err := q.CountOverlappingFlashSales(ctx, CountOverlappingFlashSalesParams{
+			ItemID:   arg.ItemID,
+			StartsAt: arg.StartsAt,
+			EndsAt:   arg.EndsAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if overlapping > 0 {
+			return ErrFlashSaleOverlaps
+		}
+
+		flashSale, err = q.CreateFlashSale(ctx, arg)
+		return err
+	})
+
+	return flashSale, err
+}
+
+// liveFlashSaleLines converts cart items to pricing lines, at the price of the
+// flash sales running on them, and returns those flash sales.
+func (q *Queries) liveFlashSaleLines(ctx context.Context, cart []GetCartByUserIDRow) ([]pricing.Line, []FlashSale, error) {
+	itemIDs := make([]int64, 0, len(cart))
+	for _, cartItem := range cart {
+		itemIDs = append(itemIDs, cartItem.ItemID)
+	}
+
+	flashSales, err := q.ListLiveFlashSales(ctx, itemIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return withFlashSales(cartLines(cart), flashSales), flashSales, nil
+}
+
+// withFlashSales prices the lines of items on flashSales at their sale price,
+// which no discount is taken off.
+func withFlashSales(lines []pricing.Line, flashSales []FlashSale) []pricing.Line {
+	salePrices := make(map[int64]string, len(flashSales))
+	for _, flashSale := range flashSales {
+		salePrices[flashSale.ItemID] = flashSale.SalePrice
+	}
+
+	for i, line := range lines {
+		if price, ok := salePrices[line.ItemID]; ok {
+			lines[i].Price = price
+			lines[i].DiscountPercentage = "0"
+		}
+	}
+	return lines
+}
+
+// flashSaleItems returns the ids of the items on flashSales. Checkout doesn't
+// lock or reserve stock for them, so a drop's buyers don't queue on the item's
+// row: a flash sale holds its units out of the item's available stock until
+// they're sold or it ends, and its claims hold theirs until they convert.
+func flashSaleItems(flashSales []FlashSale) map[int64]bool {
+	items := make(map[int64]bool, len(flashSales))
+	for _, flashSale := range flashSales {
+		items[flashSale.ItemID] = true
+	}
+	return items
+}
+
+// claimFlashSales records the units of each of flashSales the checkout under
+// reference claimed, failing with ErrFlashSaleNotClaimed unless claimed, by
+// flash sale id, holds the cart's quantity of its item, in all its variants.
+func (q *Queries) claimFlashSales(ctx context.Context, userID int64, reference string, cart []GetCartByUserIDRow, flashSales []FlashSale, claimed map[int64]int32) error {
+	for _, flashSale := range flashSales {
+		var quantity int32
+		var itemName string
+		for _, cartItem := range cart {
+			if cartItem.ItemID == flashSale.ItemID {
+				quantity += cartItem.Quantity
+				itemName = cartItem.ItemName
+			}
+		}
+
+		if quantity == 0 {
+			continue
+		}
+
+		if claimed[flashSale.ID] != quantity {
+			return fmt.Errorf("%w: %s", ErrFlashSaleNotClaimed, itemName)
+		}
+
+		_, err := q.CreateFlashSaleClaim(ctx, CreateFlashSaleClaimParams{
+			FlashSaleID: flashSale.ID,
+			UserID:      userID,
+			Reference:   reference,
+			Quantity:    quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertFlashSaleClaims counts the units claimed for the checkout under
+// reference as sold.
+func (q *Queries) convertFlashSaleClaims(ctx context.Context, reference string) error {
+	claims, err := q.UpdateFlashSaleClaimsStatus(ctx, UpdateFlashSaleClaimsStatusParams{
+		Reference:  reference,
+		FromStatus: FlashClaimed,
+		ToStatus:   FlashConverted,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, claim := range claims {
+		err := q.AddFlashSaleSold(ctx, AddFlashSaleSoldParams{
+			FlashSaleID: claim.FlashSaleID,
+			Quantity:    claim.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseFlashSaleClaims releases the units claimed for the checkout under
+// reference. The flash sale reconciliation gives them back to the sale.
+func (q *Queries) releaseFlashSaleClaims(ctx context.Context, reference string) error {
+	_, err := q.UpdateFlashSaleClaimsStatus(ctx, UpdateFlashSaleClaimsStatusParams{
+		Reference:  reference,
+		FromStatus: FlashClaimed,
+		ToStatus:   FlashReleased,
+	})
+	return err
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlashSaleHoldsStock(t *testing.T) {
+	store, owner := createStoreAndOwners(t)
+
+	item, err := testQueries.CreateStoreItem(context.Background(), CreateStoreItemParams{
+		Name:               util.RandomString(8),
+		Description:        util.RandomString(20),
+		Price:              "1000.00",
+		StoreID:            store.Store.ID,
+		ImageUrls:          []string{},
+		Category:           util.RandomString(5),
+		DiscountPercentage: "0",
+		SupplyQuantity:     10,
+		Extra:              []byte("{}"),
+		Status:             "VISIBLE",
+		Currency:           "NGN",
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateFlashSaleTx(context.Background(), CreateFlashSaleParams{
+		StoreID:      store.Store.ID,
+		ItemID:       item.ID,
+		SalePrice:    "500.00",
+		Quantity:     6,
+		PerUserLimit: 1,
+		StartsAt:     time.Now().Add(time.Hour),
+		EndsAt:       time.Now().Add(2 * time.Hour),
+		CreatedBy:    owner.ID,
+	})
+	require.NoError(t, err)
+
+	// the sale's units aren't available to regular buyers, even before it starts
+	_, err = testQueries.BuyItemTx(context.Background(), BuyItemTxParams{
+		ItemID:   item.ID,
+		StoreID:  store.Store.ID,
+		Quantity: 5,
+		UserID:   owner.ID,
+	})
+	require.ErrorIs(t, err, ErrInsufficientStock)
+
+	item, err = testQueries.BuyItemTx(context.Background(), BuyItemTxParams{
+		ItemID:   item.ID,
+		StoreID:  store.Store.ID,
+		Quantity: 4,
+		UserID:   owner.ID,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 0, item.SupplyQuantity)
+}
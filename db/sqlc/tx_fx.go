@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+// FxRates converts rates for the fx component.
+func FxRates(rates []FxRate) fx.Rates {
+	out := make(fx.Rates, len(rates))
+	for _, r := range rates {
+		out[r.Currency] = r.Rate
+	}
+	return out
+}
+
+// withFxRates sets the rate converting each line's price to fx.Base, for
+// the lines that don't have one. Rates are looked up in locked first, then
+// among the current rates.
+func (q *Queries) withFxRates(ctx context.Context, lines []pricing.Line, locked fx.Rates) ([]pricing.Line, error) {
+	var missing []string
+	for _, line := range lines {
+		if line.Currency == "" || line.Currency == fx.Base || line.FxRate != "" {
+			continue
+		}
+		if _, ok := locked[line.Currency]; !ok {
+			missing = append(missing, line.Currency)
+		}
+	}
+
+	rates := fx.Rates{}
+	if len(missing) > 0 {
+		current, err := q.ListFxRatesFor(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		rates = FxRates(current)
+	}
+	for currency, rate := range locked {
+		rates[currency] = rate
+	}
+
+	out := make([]pricing.Line, len(lines))
+	for i, line := range lines {
+		out[i] = line
+		if line.Currency == "" || line.Currency == fx.Base || line.FxRate != "" {
+			continue
+		}
+
+		rate, err := rates.BaseRate(line.Currency)
+		if err != nil {
+			return nil, err
+		}
+		out[i].FxRate = rate
+	}
+
+	return out, nil
+}
+
+type UpdateFxRatesTxParams struct {
+	Source string
+	Quote  fx.Quote
+}
+
+// UpdateFxRatesTx replaces the rates of the currencies in a quote. Rates of
+// other currencies are left as they are.
+func (dbTx *SQLTx) UpdateFxRatesTx(ctx context.Context, arg UpdateFxRatesTxParams) ([]FxRate, error) {
+	var rates []FxRate
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		for currency, rate := range arg.Quote.Rates {
+			if currency == fx.Base {
+				continue
+			}
+
+			_, err := q.UpsertFxRate(ctx, UpsertFxRateParams{
+				Currency: currency,
+				Rate:     rate,
+				Source:   arg.Source,
+				AsOf:     arg.Quote.AsOf,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		rates, err = q.ListFxRates(ctx)
+		return err
+	})
+
+	return rates, err
+}
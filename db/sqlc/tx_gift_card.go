@@ -0,0 +1,358 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/OCD-Labs/store-hub/credit"
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/ledger"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+var (
+	ErrGiftCardNotFound  = errors.New("gift card not found")
+	ErrTransactionFailed = errors.New("transaction has failed")
+)
+
+// IsGiftCardRejected reports whether err means a gift card can't pay for a checkout.
+func IsGiftCardRejected(err error) bool {
+	return errors.Is(err, ErrGiftCardNotFound) || errors.Is(err, credit.ErrCardNotApplicable)
+}
+
+// Statuses of a GiftCard.
+const (
+	GiftCardPending = "PENDING"
+	GiftCardActive  = "ACTIVE"
+	GiftCardFailed  = "FAILED"
+)
+
+// Statuses of a CheckoutCredit.
+const (
+	CreditReserved = "RESERVED"
+	CreditRedeemed = "REDEEMED"
+	CreditReleased = "RELEASED"
+)
+
+// Kinds of StoreCreditEntry.
+const (
+	StoreCreditRefund          = "REFUND"
+	StoreCreditCheckout        = "CHECKOUT"
+	StoreCreditCheckoutRelease = "CHECKOUT_RELEASE"
+)
+
+type CreateGiftCardPurchaseTxParams struct {
+	StoreID              int64
+	PurchaserID          int64
+	Amount               money.Amount
+	PaymentProvider      string
+	Reference            string
+	ProviderTxAccessCode string
+	NearAmount           sql.NullString // the yoctoNEAR a NEAR wallet payment is quoted at
+}
+
+type CreateGiftCardPurchaseTxResult struct {
+	GiftCard    GiftCard    `json:"gift_card"`
+	Transaction Transaction `json:"transaction"`
+}
+
+// CreateGiftCardPurchaseTx creates a PENDING gift card for a store, with a new
+// code, and the transaction it's paid for under.
+func (dbTx *SQLTx) CreateGiftCardPurchaseTx(ctx context.Context, arg CreateGiftCardPurchaseTxParams) (CreateGiftCardPurchaseTxResult, error) {
+	var result CreateGiftCardPurchaseTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		code, err := credit.NewCode()
+		if err != nil {
+			return err
+		}
+
+		result.GiftCard, err = q.CreateGiftCard(ctx, CreateGiftCardParams{
+			StoreID:     arg.StoreID,
+			Code:        code,
+			Currency:    fx.Base,
+			Amount:      arg.Amount.String(),
+			PurchaserID: arg.PurchaserID,
+			Reference:   arg.Reference,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transaction, err = q.CreateTransaction(ctx, CreateTransactionParams{
+			CustomerID:           arg.PurchaserID,
+			Amount:               arg.Amount.String(),
+			PaymentProvider:      arg.PaymentProvider,
+			ProviderTxRefID:      arg.Reference,
+			ProviderTxAccessCode: arg.ProviderTxAccessCode,
+			NearAmount:           arg.NearAmount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CompleteGiftCardPurchaseTxParams struct {
+	ProviderTxRefID string
+	ProviderTxFee   string
+}
+
+// CompleteGiftCardPurchaseTx completes the transaction a gift card was bought
+// under and activates the card, holding what was paid for it until it's spent.
+func (dbTx *SQLTx) CompleteGiftCardPurchaseTx(ctx context.Context, arg CompleteGiftCardPurchaseTxParams) (GiftCard, error) {
+	var giftCard GiftCard
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		// Lock the transaction, so a repeated completion waits and then sees it COMPLETED.
+		transaction, err := q.GetTransactionByRefIDForUpdate(ctx, arg.ProviderTxRefID)
+		if err != nil {
+			return err
+		}
+
+		switch transaction.Status {
+		case "COMPLETED":
+			return nil
+		case "FAILED":
+			return ErrTransactionFailed
+		}
+
+		transaction, err = q.ProcessTransaction(ctx, ProcessTransactionParams{
+			ProviderTxRefID: arg.ProviderTxRefID,
+			Status:          "COMPLETED",
+			ProviderTxFee:   arg.ProviderTxFee,
+			CartItems:       []byte("[]"),
+		})
+		if err != nil {
+			return err
+		}
+
+		giftCard, err = q.UpdateGiftCardPurchaseStatus(ctx, UpdateGiftCardPurchaseStatusParams{
+			Reference: arg.ProviderTxRefID,
+			Status:    GiftCardActive,
+		})
+		if err != nil {
+			return err
+		}
+
+		amount, err := money.Parse(transaction.Amount)
+		if err != nil {
+			return err
+		}
+
+		fee, err := money.Parse(arg.ProviderTxFee)
+		if err != nil {
+			return err
+		}
+
+		cash := ledger.ProviderCash(transaction.PaymentProvider)
+		for _, entry := range []ledger.Entry{
+			{
+				Kind:        ledger.KindGiftCardSale,
+				Reference:   transaction.ProviderTxRefID,
+				Description: fmt.Sprintf("gift card %d for store %d", giftCard.ID, giftCard.StoreID),
+				Postings: []ledger.Posting{
+					ledger.Debit(cash, amount),
+					ledger.Credit(ledger.GiftCards(), amount),
+				},
+			},
+			{
+				Kind:        ledger.KindProviderFee,
+				Reference:   transaction.ProviderTxRefID,
+				Description: fmt.Sprintf("%s fee", transaction.PaymentProvider),
+				Postings: []ledger.Posting{
+					ledger.Debit(ledger.ProviderFees(), fee),
+					ledger.Credit(cash, fee),
+				},
+			},
+		} {
+			if err := q.postEntry(ctx, entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return giftCard, err
+}
+
+// adjustStoreCredit adds amount, or takes it off if negative, to a user's store
+// credit, and records it in the user's store credit history.
+func (q *Queries) adjustStoreCredit(ctx context.Context, userID int64, amount money.Amount, kind, reference, description string) error {
+	balance, err := q.AdjustStoreCredit(ctx, AdjustStoreCreditParams{
+		UserID: userID,
+		Amount: amount.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateStoreCreditEntry(ctx, CreateStoreCreditEntryParams{
+		UserID:      userID,
+		Kind:        kind,
+		Amount:      amount.String(),
+		Balance:     balance,
+		Reference:   reference,
+		Description: description,
+	})
+	return err
+}
+
+// reserveCredits pays what it can of a checkout under reference, priced at
+// breakdown, with the gift cards with codes, then the user's store credit if
+// useStoreCredit. What each pays is taken off it and held for the checkout.
+// Gift cards are locked in code order, so concurrent checkouts spending the
+// same cards can't deadlock.
+func (q *Queries) reserveCredits(ctx context.Context, userID int64, reference string, breakdown pricing.Breakdown, codes []string, useStoreCredit bool) (credit.Application, error) {
+	seen := make(map[string]bool, len(codes))
+	var sorted []string
+	for _, code := range codes {
+		code = credit.NormalizeCode(code)
+		if !seen[code] {
+			seen[code] = true
+			sorted = append(sorted, code)
+		}
+	}
+	sort.Strings(sorted)
+
+	cards := make([]credit.Card, 0, len(sorted))
+	for _, code := range sorted {
+		giftCard, err := q.GetActiveGiftCardForUpdate(ctx, code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return credit.Application{}, fmt.Errorf("%w: %s", ErrGiftCardNotFound, code)
+			}
+			return credit.Application{}, err
+		}
+
+		balance, err := money.Parse(giftCard.Balance)
+		if err != nil {
+			return credit.Application{}, err
+		}
+
+		cards = append(cards, credit.Card{
+			ID:      giftCard.ID,
+			Code:    giftCard.Code,
+			StoreID: giftCard.StoreID,
+			Balance: balance,
+		})
+	}
+
+	storeCredit := money.Zero
+	if useStoreCredit {
+		balance, err := q.GetStoreCreditForUpdate(ctx, userID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return credit.Application{}, err
+		default:
+			if storeCredit, err = money.Parse(balance); err != nil {
+				return credit.Application{}, err
+			}
+		}
+	}
+
+	storeTotals := make(map[int64]money.Amount, len(breakdown.Stores))
+	for _, store := range breakdown.Stores {
+		storeTotals[store.StoreID] = store.Total
+	}
+
+	application, err := credit.Apply(breakdown.GrandTotal, storeTotals, cards, storeCredit)
+	if err != nil {
+		return credit.Application{}, err
+	}
+
+	for _, ca := range application.Cards {
+		_, err := q.AdjustGiftCardBalance(ctx, AdjustGiftCardBalanceParams{
+			GiftCardID: ca.CardID,
+			Amount:     (-ca.Amount).String(),
+		})
+		if err != nil {
+			return credit.Application{}, err
+		}
+
+		_, err = q.CreateCheckoutCredit(ctx, CreateCheckoutCreditParams{
+			Reference:  reference,
+			UserID:     userID,
+			GiftCardID: sql.NullInt64{Int64: ca.CardID, Valid: true},
+			Amount:     ca.Amount.String(),
+		})
+		if err != nil {
+			return credit.Application{}, err
+		}
+	}
+
+	if application.StoreCredit > money.Zero {
+		err := q.adjustStoreCredit(ctx, userID, -application.StoreCredit, StoreCreditCheckout, reference, "paid for a checkout")
+		if err != nil {
+			return credit.Application{}, err
+		}
+
+		_, err = q.CreateCheckoutCredit(ctx, CreateCheckoutCreditParams{
+			Reference: reference,
+			UserID:    userID,
+			Amount:    application.StoreCredit.String(),
+		})
+		if err != nil {
+			return credit.Application{}, err
+		}
+	}
+
+	return application, nil
+}
+
+// releaseCredits gives what gift cards and store credit held for the checkout
+// under reference back to them.
+func (q *Queries) releaseCredits(ctx context.Context, reference string) error {
+	credits, err := q.UpdateCheckoutCreditsStatus(ctx, UpdateCheckoutCreditsStatusParams{
+		Reference:  reference,
+		FromStatus: CreditReserved,
+		ToStatus:   CreditReleased,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range credits {
+		amount, err := money.Parse(c.Amount)
+		if err != nil {
+			return err
+		}
+
+		if c.GiftCardID.Valid {
+			_, err = q.AdjustGiftCardBalance(ctx, AdjustGiftCardBalanceParams{
+				GiftCardID: c.GiftCardID.Int64,
+				Amount:     c.Amount,
+			})
+		} else {
+			err = q.adjustStoreCredit(ctx, c.UserID, amount, StoreCreditCheckoutRelease, reference, "checkout was not paid for")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// creditTotals sums what gift cards and store credit pay in credits.
+func creditTotals(credits []CheckoutCredit) (giftCards, storeCredit money.Amount, err error) {
+	for _, c := range credits {
+		amount, err := money.Parse(c.Amount)
+		if err != nil {
+			return money.Zero, money.Zero, err
+		}
+
+		if c.GiftCardID.Valid {
+			giftCards += amount
+		} else {
+			storeCredit += amount
+		}
+	}
+	return giftCards, storeCredit, nil
+}
@@ -0,0 +1,180 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/invoice"
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// invoiceLine is the line an order of a transaction takes on its store's invoice.
+func invoiceLine(o GetTransactionOrdersRow) (invoice.Line, error) {
+	line := invoice.Line{
+		OrderID:      o.ID,
+		Description:  o.ItemName,
+		Quantity:     o.OrderQuantity,
+		TaxRate:      o.TaxRate,
+		TaxInclusive: o.TaxInclusive,
+	}
+
+	for _, a := range []struct {
+		dst *money.Amount
+		src string
+	}{
+		{&line.UnitPrice, o.ItemPrice},
+		{&line.Discount, o.DiscountAmount},
+		{&line.DeliveryFee, o.DeliveryFee},
+		{&line.Tax, o.TaxAmount},
+	} {
+		amount, err := money.Parse(a.src)
+		if err != nil {
+			return invoice.Line{}, err
+		}
+		*a.dst = amount
+	}
+
+	return line, nil
+}
+
+// createInvoices issues each store's invoice for its orders in a completed
+// transaction. Stores take their next numbers in id order, so concurrent
+// transactions can't deadlock on their sequences.
+func (q *Queries) createInvoices(ctx context.Context, transaction Transaction) error {
+	orders, err := q.GetTransactionOrders(ctx, transaction.ProviderTxRefID)
+	if err != nil {
+		return err
+	}
+
+	lines := make(map[int64][]invoice.Line)
+	currencies := make(map[int64]string)
+	for _, o := range orders {
+		line, err := invoiceLine(o)
+		if err != nil {
+			return err
+		}
+		lines[o.StoreID] = append(lines[o.StoreID], line)
+		currencies[o.StoreID] = o.Currency
+	}
+
+	storeIDs := make([]int64, 0, len(lines))
+	for storeID := range lines {
+		storeIDs = append(storeIDs, storeID)
+	}
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+
+	for _, storeID := range storeIDs {
+		sequenceNumber, err := q.NextInvoiceNumber(ctx, storeID)
+		if err != nil {
+			return err
+		}
+
+		totals := invoice.Invoice{Lines: lines[storeID]}.Totals()
+		_, err = q.CreateInvoice(ctx, CreateInvoiceParams{
+			StoreID:        storeID,
+			SequenceNumber: sequenceNumber,
+			InvoiceNumber:  invoice.Number(storeID, sequenceNumber),
+			Reference:      transaction.ProviderTxRefID,
+			BuyerID:        transaction.CustomerID,
+			Currency:       currencies[storeID],
+			Subtotal:       totals.Subtotal.String(),
+			Discount:       totals.Discount.String(),
+			DeliveryFee:    totals.DeliveryFee.String(),
+			Tax:            totals.Tax.String(),
+			Total:          totals.Total.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// invoiceAddress lays a shipping address out a line at a time.
+func invoiceAddress(shippingAddress json.RawMessage) []string {
+	var address struct {
+		RecipientName string `json:"recipient_name"`
+		PhoneNumber   string `json:"phone_number"`
+		AddressLine1  string `json:"address_line1"`
+		AddressLine2  string `json:"address_line2"`
+		City          string `json:"city"`
+		State         string `json:"state"`
+		PostalCode    string `json:"postal_code"`
+		Country       string `json:"country"`
+	}
+	if len(shippingAddress) > 0 {
+		// an address that doesn't parse is left off the invoice
+		_ = json.Unmarshal(shippingAddress, &address)
+	}
+
+	var lines []string
+	for _, l := range []string{
+		address.RecipientName,
+		address.AddressLine1,
+		address.AddressLine2,
+		strings.TrimSpace(strings.Trim(address.City+", "+address.State, ", ") + " " + address.PostalCode),
+		address.Country,
+		address.PhoneNumber,
+	} {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// InvoiceDocument lays out an invoice with its store's orders, its buyer and
+// where the orders ship to, ready to render.
+func (dbTx *SQLTx) InvoiceDocument(ctx context.Context, inv Invoice) (invoice.Invoice, error) {
+	doc := invoice.Invoice{
+		Number:    inv.InvoiceNumber,
+		IssuedAt:  inv.CreatedAt,
+		Reference: inv.Reference,
+		Currency:  inv.Currency,
+	}
+
+	orders, err := dbTx.GetTransactionOrders(ctx, inv.Reference)
+	if err != nil {
+		return doc, err
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	for _, o := range orders {
+		if o.StoreID != inv.StoreID {
+			continue
+		}
+
+		line, err := invoiceLine(o)
+		if err != nil {
+			return doc, err
+		}
+		doc.Lines = append(doc.Lines, line)
+		doc.StoreName = o.StoreName
+		doc.PaymentMethod = o.PaymentMethod
+	}
+
+	buyer, err := dbTx.GetUserByID(ctx, inv.BuyerID)
+	if err != nil {
+		return doc, err
+	}
+	doc.BuyerName = strings.TrimSpace(buyer.FirstName + " " + buyer.LastName)
+	doc.BuyerEmail = buyer.Email
+
+	// A transaction completed before order groups existed has no order number.
+	orderGroup, err := dbTx.GetOrderGroupByReference(ctx, inv.Reference)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return doc, nil
+		}
+		return doc, err
+	}
+	doc.OrderNumber = orderGroup.OrderNumber
+	doc.ShippingAddress = invoiceAddress(orderGroup.ShippingAddress)
+
+	return doc, nil
+}
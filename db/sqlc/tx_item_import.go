@@ -0,0 +1,232 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/itemcsv"
+	"github.com/lib/pq"
+)
+
+var ErrImportRowRejected = errors.New("row rejected")
+
+type CreateItemImportTxParams struct {
+	StoreID    int64
+	UploadedBy int64
+	Filename   string
+	TotalRows  int32
+	Content    []byte
+}
+
+// CreateItemImportTx records an upload of a CSV of items to a store, PENDING
+// until ImportItemsTx imports its rows.
+func (dbTx *SQLTx) CreateItemImportTx(ctx context.Context, arg CreateItemImportTxParams) (ItemImport, error) {
+	var itemImport ItemImport
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+		itemImport, err = q.CreateItemImport(ctx, CreateItemImportParams{
+			StoreID:    arg.StoreID,
+			UploadedBy: arg.UploadedBy,
+			Filename:   arg.Filename,
+			TotalRows:  arg.TotalRows,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.CreateItemImportFile(ctx, CreateItemImportFileParams{
+			ImportID: itemImport.ID,
+			Content:  arg.Content,
+		})
+	})
+
+	return itemImport, err
+}
+
+// ImportItemsTx imports the rows of an import's CSV into its store, creating
+// an item for each SKU the store hasn't got and updating the item that has
+// it otherwise. Each row is imported in a transaction of its own, so a row
+// that's rejected is recorded as an error of the import and the rest carry
+// on. Rows are matched by SKU, so importing a file again after a failure
+// only redoes the same changes. Changes of supply are recorded as IMPORT
+// stock movements by the uploader. An import that's already COMPLETED or
+// FAILED is returned as it is.
+func (dbTx *SQLTx) ImportItemsTx(ctx context.Context, importID int64) (ItemImport, error) {
+	itemImport, err := dbTx.StartItemImport(ctx, importID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dbTx.GetItemImportByID(ctx, importID)
+		}
+		return ItemImport{}, err
+	}
+
+	content, err := dbTx.GetItemImportFile(ctx, importID)
+	if err != nil {
+		return ItemImport{}, err
+	}
+
+	rows, rowErrs, err := itemcsv.Read(bytes.NewReader(content))
+	if err != nil {
+		return dbTx.FailItemImport(ctx, FailItemImportParams{
+			ImportID: importID,
+			Error:    err.Error(),
+		})
+	}
+
+	// a retried import reports its errors afresh
+	if err := dbTx.DeleteItemImportErrors(ctx, importID); err != nil {
+		return ItemImport{}, err
+	}
+
+	var created, updated int32
+	for _, row := range rows {
+		var isNew bool
+		err := dbTx.execTx(ctx, func(q *Queries) error {
+			err := q.setStockMovementContext(ctx, stockMovementContext{
+				Reason:    StockImport,
+				UserID:    itemImport.UploadedBy,
+				Reference: fmt.Sprintf("import:%d", importID),
+			})
+			if err != nil {
+				return err
+			}
+
+			isNew, err = q.importItemRow(ctx, itemImport.StoreID, row)
+			return err
+		})
+		if err != nil {
+			if !isRowRejected(err) {
+				return ItemImport{}, fmt.Errorf("failed to import line %d: %w", row.Line, err)
+			}
+			rowErrs = append(rowErrs, itemcsv.RowError{Line: row.Line, SKU: row.SKU, Message: err.Error()})
+			continue
+		}
+
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	for _, rowErr := range rowErrs {
+		err := dbTx.CreateItemImportError(ctx, CreateItemImportErrorParams{
+			ImportID: importID,
+			Line:     int32(rowErr.Line),
+			Sku:      rowErr.SKU,
+			Message:  rowErr.Message,
+		})
+		if err != nil {
+			return ItemImport{}, err
+		}
+	}
+
+	err = dbTx.execTx(ctx, func(q *Queries) error {
+		itemImport, err = q.CompleteItemImport(ctx, CompleteItemImportParams{
+			ImportID:    importID,
+			CreatedRows: created,
+			UpdatedRows: updated,
+			FailedRows:  int32(len(rowErrs)),
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteItemImportFile(ctx, importID)
+	})
+
+	return itemImport, err
+}
+
+// importItemRow creates or updates the item of a store with the row's SKU,
+// and reports whether it created it. An item with variants keeps the sum of
+// their supply, whatever supply_quantity the row has.
+func (q *Queries) importItemRow(ctx context.Context, storeID int64, row itemcsv.Row) (bool, error) {
+	currency := row.Currency
+	if currency == "" {
+		currency = fx.Base
+	}
+
+	// an item can only be priced in a currency with an exchange rate
+	if _, err := q.GetFxRate(ctx, currency); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("%w: currency %s is not supported", ErrImportRowRejected, currency)
+		}
+		return false, err
+	}
+
+	item, err := q.GetStoreItemBySKUForUpdate(ctx, GetStoreItemBySKUForUpdateParams{
+		StoreID: storeID,
+		Sku:     row.SKU,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = q.CreateStoreItem(ctx, CreateStoreItemParams{
+			Name:               row.Name,
+			Description:        row.Description,
+			Price:              row.Price,
+			StoreID:            storeID,
+			ImageUrls:          row.ImageURLs,
+			Category:           row.Category,
+			CoverImgUrl:        row.CoverImgURL,
+			DiscountPercentage: row.DiscountPercentage,
+			SupplyQuantity:     row.SupplyQuantity,
+			Extra:              []byte("{}"),
+			Status:             row.Status,
+			Currency:           currency,
+			WeightGrams:        row.WeightGrams,
+			Sku:                row.SKU,
+		})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = q.UpdateItem(ctx, UpdateItemParams{
+		ItemID:             item.ID,
+		Name:               sql.NullString{String: row.Name, Valid: true},
+		Description:        sql.NullString{String: row.Description, Valid: true},
+		Price:              sql.NullString{String: row.Price, Valid: true},
+		ImageUrls:          row.ImageURLs,
+		CoverImgUrl:        sql.NullString{String: row.CoverImgURL, Valid: true},
+		Category:           sql.NullString{String: row.Category, Valid: true},
+		DiscountPercentage: sql.NullString{String: row.DiscountPercentage, Valid: true},
+		SupplyQuantity:     sql.NullInt64{Int64: row.SupplyQuantity, Valid: true},
+		Status:             sql.NullString{String: row.Status, Valid: true},
+		WeightGrams:        sql.NullInt64{Int64: row.WeightGrams, Valid: true},
+		Currency:           sql.NullString{String: currency, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	variants, err := q.CountItemVariants(ctx, item.ID)
+	if err != nil {
+		return false, err
+	}
+	if variants > 0 {
+		_, err = q.SumItemVariantsSupply(ctx, item.ID)
+	}
+	return false, err
+}
+
+// isRowRejected reports whether err means a row of an import isn't a valid
+// item, rather than the import failing.
+func isRowRejected(err error) bool {
+	if errors.Is(err, ErrImportRowRejected) {
+		return true
+	}
+
+	// data exceptions, e.g. a price too large, and constraint violations
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		class := pqErr.Code.Class()
+		return class == "22" || class == "23"
+	}
+	return false
+}
@@ -0,0 +1,303 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/pricing"
+	"github.com/OCD-Labs/store-hub/variant"
+)
+
+var (
+	ErrVariantNotFound = errors.New("item variant not found")
+	ErrVariantRequired = errors.New("item has variants; choose one")
+	ErrSKUTaken        = errors.New("SKU is already used by another item of the store")
+)
+
+// IsVariantRejected reports whether err means an item's options or variants
+// are invalid.
+func IsVariantRejected(err error) bool {
+	return errors.Is(err, variant.ErrInvalidOption) ||
+		errors.Is(err, variant.ErrInvalidVariant) ||
+		errors.Is(err, ErrSKUTaken)
+}
+
+// An ItemVariantParams is a variant of an item, identified by its SKU. A
+// variant without a price sells at its item's.
+type ItemVariantParams struct {
+	SKU            string
+	Options        variant.Values
+	Price          sql.NullString
+	SupplyQuantity int64
+	ImageUrls      []string
+}
+
+// ItemVariantsParams are the options of an item, and the variants it's sold in.
+type ItemVariantsParams struct {
+	Options  []variant.Option
+	Variants []ItemVariantParams
+}
+
+// ItemWithVariants is an item with its options and variants.
+type ItemWithVariants struct {
+	Item     Item          `json:"item"`
+	Options  []ItemOption  `json:"options"`
+	Variants []ItemVariant `json:"variants"`
+}
+
+type CreateStoreItemTxParams struct {
+	CreateStoreItemParams
+	Variants ItemVariantsParams
+
+	// UserID is who the INITIAL stock movements are recorded for.
+	UserID int64
+}
+
+// CreateStoreItemTx creates an item of a store with its options and variants.
+// An item with variants has the sum of their supply.
+func (dbTx *SQLTx) CreateStoreItemTx(ctx context.Context, arg CreateStoreItemTxParams) (ItemWithVariants, error) {
+	var result ItemWithVariants
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		err := q.setStockMovementContext(ctx, stockMovementContext{UserID: arg.UserID})
+		if err != nil {
+			return err
+		}
+
+		// its stock comes in with the variants', not on top of it
+		if len(arg.Variants.Variants) > 0 {
+			arg.SupplyQuantity = 0
+		}
+
+		item, err := q.CreateStoreItem(ctx, arg.CreateStoreItemParams)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.setItemVariants(ctx, item, arg.Variants)
+		return err
+	})
+
+	return result, err
+}
+
+type UpdateStoreItemTxParams struct {
+	UpdateItemParams
+	StoreID int64
+
+	// Variants replace the item's options and variants; nil leaves them as they are.
+	Variants *ItemVariantsParams
+
+	// UserID is who the stock movements of a change of supply are recorded for.
+	UserID int64
+}
+
+// UpdateStoreItemTx updates an item of a store, and replaces its options and
+// variants when given. Variants left out are deleted. An item with variants
+// keeps the sum of their supply, whatever supply_quantity it's updated with.
+// A change of supply is recorded as an ADJUSTMENT by arg.UserID.
+func (dbTx *SQLTx) UpdateStoreItemTx(ctx context.Context, arg UpdateStoreItemTxParams) (ItemWithVariants, error) {
+	var result ItemWithVariants
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		err := q.setStockMovementContext(ctx, stockMovementContext{UserID: arg.UserID})
+		if err != nil {
+			return err
+		}
+
+		item, err := q.UpdateItem(ctx, arg.UpdateItemParams)
+		if err != nil {
+			return err
+		}
+
+		if item.StoreID != arg.StoreID {
+			return sql.ErrNoRows
+		}
+
+		if arg.Variants != nil {
+			result, err = q.setItemVariants(ctx, item, *arg.Variants)
+			return err
+		}
+
+		result, err = q.itemWithVariants(ctx, item)
+		if err != nil {
+			return err
+		}
+
+		if len(result.Variants) > 0 {
+			result.Item, err = q.SumItemVariantsSupply(ctx, item.ID)
+		}
+		return err
+	})
+
+	return result, err
+}
+
+// setItemVariants replaces the options and variants of item, and sets its
+// supply to the sum of the variants'.
+func (q *Queries) setItemVariants(ctx context.Context, item Item, arg ItemVariantsParams) (ItemWithVariants, error) {
+	values := make([]variant.Values, len(arg.Variants))
+	skus := make([]string, len(arg.Variants))
+	seen := make(map[string]bool, len(arg.Variants))
+	for i, v := range arg.Variants {
+		if seen[v.SKU] {
+			return ItemWithVariants{}, fmt.Errorf("%w: SKU %q is repeated", variant.ErrInvalidVariant, v.SKU)
+		}
+		seen[v.SKU] = true
+
+		values[i] = v.Options
+		skus[i] = v.SKU
+	}
+
+	if err := variant.Validate(arg.Options, values); err != nil {
+		return ItemWithVariants{}, err
+	}
+
+	if err := q.DeleteItemOptions(ctx, item.ID); err != nil {
+		return ItemWithVariants{}, err
+	}
+
+	result := ItemWithVariants{
+		Item:     item,
+		Options:  make([]ItemOption, 0, len(arg.Options)),
+		Variants: make([]ItemVariant, 0, len(arg.Variants)),
+	}
+
+	for i, option := range arg.Options {
+		itemOption, err := q.CreateItemOption(ctx, CreateItemOptionParams{
+			ItemID:       item.ID,
+			Name:         option.Name,
+			OptionValues: option.Values,
+			Position:     int32(i),
+		})
+		if err != nil {
+			return ItemWithVariants{}, err
+		}
+		result.Options = append(result.Options, itemOption)
+	}
+
+	err := q.DeleteItemVariantsExcept(ctx, DeleteItemVariantsExceptParams{
+		ItemID: item.ID,
+		Skus:   skus,
+	})
+	if err != nil {
+		return ItemWithVariants{}, err
+	}
+
+	for _, v := range arg.Variants {
+		options, err := json.Marshal(v.Options)
+		if err != nil {
+			return ItemWithVariants{}, err
+		}
+
+		imageUrls := v.ImageUrls
+		if imageUrls == nil {
+			imageUrls = []string{}
+		}
+
+		itemVariant, err := q.UpsertItemVariant(ctx, UpsertItemVariantParams{
+			ItemID:         item.ID,
+			StoreID:        item.StoreID,
+			Sku:            v.SKU,
+			Options:        options,
+			Price:          v.Price,
+			SupplyQuantity: v.SupplyQuantity,
+			ImageUrls:      imageUrls,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ItemWithVariants{}, fmt.Errorf("%w: %s", ErrSKUTaken, v.SKU)
+			}
+			return ItemWithVariants{}, err
+		}
+		result.Variants = append(result.Variants, itemVariant)
+	}
+
+	if len(result.Variants) > 0 {
+		var err error
+		result.Item, err = q.SumItemVariantsSupply(ctx, item.ID)
+		if err != nil {
+			return ItemWithVariants{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// itemWithVariants fetches the options and variants of item.
+func (q *Queries) itemWithVariants(ctx context.Context, item Item) (ItemWithVariants, error) {
+	var err error
+	result := ItemWithVariants{Item: item}
+
+	result.Options, err = q.ListItemOptions(ctx, item.ID)
+	if err != nil {
+		return ItemWithVariants{}, err
+	}
+
+	result.Variants, err = q.ListItemVariants(ctx, item.ID)
+	if err != nil {
+		return ItemWithVariants{}, err
+	}
+
+	return result, nil
+}
+
+// lineVariant fetches the variant a line buys, failing if its item has
+// variants and the line names none. It's the zero ItemVariant for an item
+// without variants.
+func (q *Queries) lineVariant(ctx context.Context, line pricing.Line) (ItemVariant, error) {
+	if line.VariantID != 0 {
+		itemVariant, err := q.GetItemVariant(ctx, GetItemVariantParams{
+			VariantID: line.VariantID,
+			ItemID:    line.ItemID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ItemVariant{}, fmt.Errorf("%w: %d", ErrVariantNotFound, line.VariantID)
+			}
+			return ItemVariant{}, err
+		}
+		return itemVariant, nil
+	}
+
+	variants, err := q.CountItemVariants(ctx, line.ItemID)
+	if err != nil {
+		return ItemVariant{}, err
+	}
+
+	if variants > 0 {
+		return ItemVariant{}, ErrVariantRequired
+	}
+	return ItemVariant{}, nil
+}
+
+type AddCartItemTxParams struct {
+	CartID    int64
+	ItemID    int64
+	StoreID   int64
+	VariantID sql.NullInt64
+}
+
+// AddCartItemTx adds a unit of an item of a store to a cart. An item with
+// variants is added in one of them, and an item without in none.
+func (dbTx *SQLTx) AddCartItemTx(ctx context.Context, arg AddCartItemTxParams) (CartItem, error) {
+	var cartItem CartItem
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		_, err := q.lineVariant(ctx, pricing.Line{
+			ItemID:    arg.ItemID,
+			VariantID: arg.VariantID.Int64,
+		})
+		if err != nil {
+			return err
+		}
+
+		cartItem, err = q.UpsertCartItem(ctx, UpsertCartItemParams(arg))
+		return err
+	})
+
+	return cartItem, err
+}
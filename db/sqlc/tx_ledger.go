@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/ledger"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+// AccountTypeForProvider returns the account type funds paid through provider are held under.
+func AccountTypeForProvider(provider string) string {
+	if provider == "PAYSTACK" {
+		return AccountTypeFiat
+	}
+	return AccountTypeCrypto
+}
+
+// AccountTypeForChannel returns the account type funds of an order paid through channel are held under.
+func AccountTypeForChannel(paymentChannel string) string {
+	if paymentChannel == AccountTypeFiat {
+		return AccountTypeFiat
+	}
+	return AccountTypeCrypto
+}
+
+// postEntry records a journal entry and its postings. An entry left with
+// no postings once zero amounts are dropped is not recorded.
+func (q *Queries) postEntry(ctx context.Context, entry ledger.Entry) error {
+	if !entry.Compact() {
+		return nil
+	}
+
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	journalEntry, err := q.CreateJournalEntry(ctx, CreateJournalEntryParams{
+		Kind:        entry.Kind,
+		Reference:   entry.Reference,
+		Description: entry.Description,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, posting := range entry.Postings {
+		accountID, err := q.EnsureLedgerAccount(ctx, EnsureLedgerAccountParams{
+			Code: posting.Account.Code,
+			Type: string(posting.Account.Type),
+			StoreID: sql.NullInt64{
+				Int64: posting.Account.StoreID,
+				Valid: posting.Account.StoreID != 0,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateLedgerPosting(ctx, CreateLedgerPostingParams{
+			JournalEntryID:  journalEntry.ID,
+			LedgerAccountID: accountID,
+			Amount:          posting.Amount.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// postCapture records a completed payment: the captured amount, with what
+// gift cards and store credit paid in credits, its allocation to each store's
+// pending funds and the platform's commission, and the provider's fee.
+// commissions holds the commission on each line of breakdown.
+func (q *Queries) postCapture(ctx context.Context, transaction Transaction, breakdown pricing.Breakdown, commissions []LineCommission, providerTxFee string, credits []CheckoutCredit) error {
+	amount, err := money.Parse(transaction.Amount)
+	if err != nil {
+		return err
+	}
+
+	giftCards, storeCredit, err := creditTotals(credits)
+	if err != nil {
+		return err
+	}
+	paid := amount + giftCards + storeCredit
+
+	fee, err := money.Parse(providerTxFee)
+	if err != nil {
+		return err
+	}
+
+	cash := ledger.ProviderCash(transaction.PaymentProvider)
+	accountType := AccountTypeForProvider(transaction.PaymentProvider)
+	reference := transaction.ProviderTxRefID
+
+	entries := []ledger.Entry{
+		{
+			Kind:        ledger.KindCapture,
+			Reference:   reference,
+			Description: fmt.Sprintf("payment captured by %s", transaction.PaymentProvider),
+			Postings: []ledger.Posting{
+				ledger.Debit(cash, amount),
+				ledger.Debit(ledger.GiftCards(), giftCards),
+				ledger.Debit(ledger.StoreCredit(), storeCredit),
+				ledger.Credit(ledger.CustomerPayments(), paid),
+			},
+		},
+		{
+			Kind:        ledger.KindEscrowHold,
+			Reference:   reference,
+			Description: "payment held for stores until delivery",
+		},
+		{
+			Kind:        ledger.KindCommission,
+			Reference:   reference,
+			Description: "platform commission",
+		},
+		{
+			Kind:        ledger.KindProviderFee,
+			Reference:   reference,
+			Description: fmt.Sprintf("%s fee", transaction.PaymentProvider),
+			Postings: []ledger.Posting{
+				ledger.Debit(ledger.ProviderFees(), fee),
+				ledger.Credit(cash, fee),
+			},
+		},
+	}
+
+	storeCommission := make(map[int64]money.Amount)
+	var totalCommission money.Amount
+	for i, line := range breakdown.Lines {
+		storeCommission[line.StoreID] += commissions[i].Amount
+		totalCommission += commissions[i].Amount
+	}
+
+	entries[1].Postings = []ledger.Posting{ledger.Debit(ledger.CustomerPayments(), paid-totalCommission)}
+	for _, store := range breakdown.Stores {
+		share := store.Total - storeCommission[store.StoreID]
+		entries[1].Postings = append(entries[1].Postings, ledger.Credit(ledger.StorePending(store.StoreID, accountType), share))
+	}
+
+	entries[2].Postings = []ledger.Posting{
+		ledger.Debit(ledger.CustomerPayments(), totalCommission),
+		ledger.Credit(ledger.Commission(), totalCommission),
+	}
+
+	for _, entry := range entries {
+		if err := q.postEntry(ctx, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
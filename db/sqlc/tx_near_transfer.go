@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Statuses of a NearTransfer.
+const (
+	NEARTransferPending   = "PENDING"
+	NEARTransferCompleted = "COMPLETED"
+	NEARTransferFailed    = "FAILED"
+)
+
+// CompleteNEARTransferTx completes a PENDING NEAR transfer once its transaction
+// has executed on chain, and the order's or checkout's refund it sends, under
+// the transaction's hash.
+func (dbTx *SQLTx) CompleteNEARTransferTx(ctx context.Context, transferID int64) (NearTransfer, error) {
+	var transfer NearTransfer
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		transfer, err = q.GetNEARTransferForUpdate(ctx, transferID)
+		if err != nil {
+			return err
+		}
+
+		if transfer.Status != NEARTransferPending {
+			return nil
+		}
+
+		transfer, err = q.CompleteNEARTransfer(ctx, transferID)
+		if err != nil {
+			return err
+		}
+
+		if transfer.CheckoutRefundID.Valid {
+			_, err = q.CompleteCheckoutRefund(ctx, CompleteCheckoutRefundParams{
+				CheckoutRefundID: transfer.CheckoutRefundID.Int64,
+				ProviderRefundID: transfer.TxHash,
+			})
+			return err
+		}
+
+		if !transfer.RefundID.Valid {
+			return nil
+		}
+
+		_, err = q.completeRefund(ctx, CompleteRefundTxParams{
+			RefundID:         transfer.RefundID.Int64,
+			ProviderRefundID: transfer.TxHash,
+		})
+		return err
+	})
+
+	return transfer, err
+}
+
+type FailNEARTransferTxParams struct {
+	TransferID    int64
+	FailureReason string
+}
+
+// FailNEARTransferTx fails a PENDING NEAR transfer, and the refund it sends,
+// once its transaction failed on chain or none can be sent.
+func (dbTx *SQLTx) FailNEARTransferTx(ctx context.Context, arg FailNEARTransferTxParams) (NearTransfer, error) {
+	var transfer NearTransfer
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		transfer, err = q.GetNEARTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if transfer.Status != NEARTransferPending {
+			return nil
+		}
+
+		transfer, err = q.FailNEARTransfer(ctx, FailNEARTransferParams{
+			TransferID:    arg.TransferID,
+			FailureReason: arg.FailureReason,
+		})
+		if err != nil {
+			return err
+		}
+
+		// A refund no longer PENDING has been settled some other way.
+		if transfer.CheckoutRefundID.Valid {
+			_, err = q.FailCheckoutRefund(ctx, FailCheckoutRefundParams{
+				CheckoutRefundID: transfer.CheckoutRefundID.Int64,
+				FailureReason:    arg.FailureReason,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		if !transfer.RefundID.Valid {
+			return nil
+		}
+
+		_, err = q.FailRefund(ctx, FailRefundParams{
+			RefundID:      transfer.RefundID.Int64,
+			FailureReason: arg.FailureReason,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	})
+
+	return transfer, err
+}
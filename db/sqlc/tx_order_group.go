@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/OCD-Labs/store-hub/pricing"
+	"github.com/OCD-Labs/store-hub/util"
+)
+
+const (
+	OrderGroupPending = "PENDING"
+	OrderGroupPlaced  = "PLACED"
+	OrderGroupFailed  = "FAILED"
+)
+
+// createOrderGroup records what a buyer is buying under reference, priced at breakdown.
+func (q *Queries) createOrderGroup(ctx context.Context, buyerID int64, reference string, shippingAddress json.RawMessage, breakdown pricing.Breakdown) (OrderGroup, error) {
+	if len(shippingAddress) == 0 {
+		shippingAddress = json.RawMessage("{}")
+	}
+
+	return q.CreateOrderGroup(ctx, CreateOrderGroupParams{
+		BuyerID:         buyerID,
+		Reference:       reference,
+		Status:          OrderGroupPending,
+		ShippingAddress: shippingAddress,
+		Currency:        breakdown.Currency,
+		Subtotal:        breakdown.Subtotal.String(),
+		DiscountTotal:   breakdown.Discount.String(),
+		DeliveryTotal:   breakdown.DeliveryFee.String(),
+		TaxTotal:        breakdown.Tax.String(),
+		GrandTotal:      breakdown.GrandTotal.String(),
+	})
+}
+
+// placeOrderGroup groups orderIDs by the store shipping them under an order
+// group, and marks it PLACED.
+func (q *Queries) placeOrderGroup(ctx context.Context, orderGroupID int64, orderIDs []int64) (OrderGroup, error) {
+	_, err := q.CreateFulfilmentGroups(ctx, CreateFulfilmentGroupsParams{
+		OrderGroupID: orderGroupID,
+		OrderIds:     orderIDs,
+	})
+	if err != nil {
+		return OrderGroup{}, err
+	}
+
+	_, err = q.AssignFulfilmentGroups(ctx, AssignFulfilmentGroupsParams{
+		OrderGroupID: orderGroupID,
+		OrderIds:     orderIDs,
+	})
+	if err != nil {
+		return OrderGroup{}, err
+	}
+
+	return q.PlaceOrderGroup(ctx, orderGroupID)
+}
+
+// placeCheckoutOrderGroup places the order group of the checkout under reference
+// with the orders its transaction created. A checkout started before order
+// groups existed gets one without a shipping address.
+func (q *Queries) placeCheckoutOrderGroup(ctx context.Context, transaction Transaction, breakdown pricing.Breakdown) (OrderGroup, error) {
+	orderGroup, err := q.GetOrderGroupByReferenceForUpdate(ctx, transaction.ProviderTxRefID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return OrderGroup{}, err
+		}
+
+		orderGroup, err = q.createOrderGroup(ctx, transaction.CustomerID, transaction.ProviderTxRefID, nil, breakdown)
+		if err != nil {
+			return OrderGroup{}, err
+		}
+	}
+
+	return q.placeOrderGroup(ctx, orderGroup.ID, transaction.OrderIds)
+}
+
+// updateSellerOrder updates an order, creating a sale row and releasing its
+// pending funds to the store if it's DELIVERED, and brings the status of the
+// fulfilment group it ships in up to date.
+func (q *Queries) updateSellerOrder(ctx context.Context, arg UpdateSellerOrderParams) (Order, error) {
+	o, err := q.UpdateSellerOrder(ctx, arg)
+	if err != nil {
+		return o, err
+	}
+
+	if o.DeliveryStatus == "DELIVERED" {
+		if err := q.deliverOrder(ctx, o); err != nil {
+			return o, err
+		}
+	}
+
+	if o.DeliveryStatus == "RETURNED" {
+		err = q.ReduceSalesOverview(ctx, ReduceSalesOverviewParams{
+			StoreID: o.StoreID,
+			ItemID:  o.ItemID,
+			OrderID: o.ID,
+		})
+		if err != nil {
+			return o, err
+		}
+	}
+
+	if o.FulfilmentGroupID.Valid {
+		if _, err := q.SyncFulfilmentGroup(ctx, o.FulfilmentGroupID.Int64); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
+
+type UpdateShipmentTxParams struct {
+	FulfilmentGroupID    int64
+	StoreID              int64
+	SellerID             int64
+	DeliveryStatus       sql.NullString
+	DeliveredOn          sql.NullTime
+	ExpectedDeliveryDate sql.NullTime
+	Carrier              sql.NullString
+	TrackingNumber       sql.NullString
+}
+
+type UpdateShipmentTxResult struct {
+	Shipment FulfilmentGroup `json:"shipment"`
+	Orders   []Order         `json:"orders"`
+}
+
+// UpdateShipmentTx updates the tracking details of a store's fulfilment group,
+// and moves every line of it that can be to a new delivery status. Lines that
+// can't, such as a CANCELLED one when the rest ship, are left as they are.
+func (dbTx *SQLTx) UpdateShipmentTx(ctx context.Context, arg UpdateShipmentTxParams) (UpdateShipmentTxResult, error) {
+	var result UpdateShipmentTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetSellerFulfilmentGroup(ctx, GetSellerFulfilmentGroupParams{
+			FulfilmentGroupID: arg.FulfilmentGroupID,
+			StoreID:           arg.StoreID,
+			SellerID:          arg.SellerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Shipment, err = q.UpdateFulfilmentGroupShipping(ctx, UpdateFulfilmentGroupShippingParams{
+			FulfilmentGroupID:    arg.FulfilmentGroupID,
+			Carrier:              arg.Carrier,
+			TrackingNumber:       arg.TrackingNumber,
+			ExpectedDeliveryDate: arg.ExpectedDeliveryDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Orders, err = q.ListFulfilmentGroupOrders(ctx, arg.FulfilmentGroupID)
+		if err != nil {
+			return err
+		}
+
+		for i, o := range result.Orders {
+			status := arg.DeliveryStatus.String
+			if !arg.DeliveryStatus.Valid || o.DeliveryStatus == status || !util.CanChangeStatus(o.DeliveryStatus, status) {
+				continue
+			}
+
+			result.Orders[i], err = q.updateSellerOrder(ctx, UpdateSellerOrderParams{
+				OrderID:              o.ID,
+				SellerID:             o.SellerID,
+				StoreID:              o.StoreID,
+				DeliveryStatus:       arg.DeliveryStatus,
+				DeliveredOn:          arg.DeliveredOn,
+				ExpectedDeliveryDate: arg.ExpectedDeliveryDate,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Shipment, err = q.SyncFulfilmentGroup(ctx, arg.FulfilmentGroupID)
+		return err
+	})
+
+	return result, err
+}
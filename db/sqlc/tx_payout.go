@@ -0,0 +1,205 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/ledger"
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+var (
+	ErrInsufficientBalance  = errors.New("insufficient available balance")
+	ErrWithdrawalNotPending = errors.New("withdrawal request is no longer pending")
+)
+
+// Account types funds are held and paid out under.
+const (
+	AccountTypeFiat   = "FIAT"
+	AccountTypeCrypto = "CRYPTO"
+)
+
+// StoreBalance is a store's balance under an account type, derived from
+// the ledger. Pending funds belong to paid orders not yet delivered,
+// available funds can be withdrawn, and withdrawing funds are held by
+// pending withdrawal requests.
+type StoreBalance struct {
+	AccountType   string       `json:"account_type"`
+	Currency      string       `json:"currency,omitempty"`
+	WalletAddress string       `json:"wallet_address,omitempty"`
+	Pending       money.Amount `json:"pending"`
+	Available     money.Amount `json:"available"`
+	Withdrawing   money.Amount `json:"withdrawing"`
+}
+
+// GetStoreBalancesTx retrieves a store's fiat and crypto balances.
+func (dbTx *SQLTx) GetStoreBalancesTx(ctx context.Context, storeID int64) ([]StoreBalance, error) {
+	balances := []StoreBalance{
+		{AccountType: AccountTypeFiat},
+		{AccountType: AccountTypeCrypto},
+	}
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		fiatAccount, err := q.GetStoreFiatAccount(ctx, storeID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		balances[0].Currency = fiatAccount.Currency
+
+		cryptoAccount, err := q.GetStoreCryptoAccount(ctx, storeID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		balances[1].WalletAddress = cryptoAccount.WalletAddress
+
+		totals, err := q.ListStoreLedgerBalances(ctx, storeID)
+		if err != nil {
+			return err
+		}
+
+		byCode := make(map[string]money.Amount, len(totals))
+		for _, t := range totals {
+			sum, err := money.Parse(t.Total)
+			if err != nil {
+				return err
+			}
+			byCode[t.Code] = ledger.AccountType(t.Type).Balance(sum)
+		}
+
+		for i := range balances {
+			b := &balances[i]
+			b.Pending = byCode[ledger.StorePending(storeID, b.AccountType).Code]
+			b.Available = byCode[ledger.StoreAvailable(storeID, b.AccountType).Code]
+			b.Withdrawing = byCode[ledger.StoreWithdrawing(storeID, b.AccountType).Code]
+		}
+
+		return nil
+	})
+
+	return balances, err
+}
+
+type CreateWithdrawalTxParams struct {
+	StoreID     int64
+	AccountType string
+	Amount      money.Amount
+	Destination string
+	RequestedBy int64
+}
+
+// CreateWithdrawalTx records a withdrawal request, holding its amount from the store's account.
+func (dbTx *SQLTx) CreateWithdrawalTx(ctx context.Context, arg CreateWithdrawalTxParams) (WithdrawalRequest, error) {
+	var withdrawal WithdrawalRequest
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		switch arg.AccountType {
+		case AccountTypeFiat:
+			_, err = q.DebitFiatAccount(ctx, DebitFiatAccountParams{
+				StoreID: arg.StoreID,
+				Amount:  arg.Amount.String(),
+			})
+		default:
+			_, err = q.DebitCryptoAccount(ctx, DebitCryptoAccountParams{
+				StoreID: arg.StoreID,
+				Amount:  arg.Amount.String(),
+			})
+		}
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInsufficientBalance
+			}
+			return err
+		}
+
+		withdrawal, err = q.CreateWithdrawalRequest(ctx, CreateWithdrawalRequestParams{
+			StoreID:     arg.StoreID,
+			AccountType: arg.AccountType,
+			Amount:      arg.Amount.String(),
+			Destination: arg.Destination,
+			RequestedBy: arg.RequestedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.postEntry(ctx, ledger.Transfer(
+			ledger.KindPayoutRequest,
+			fmt.Sprintf("withdrawal:%d", withdrawal.ID),
+			ledger.StoreAvailable(arg.StoreID, arg.AccountType),
+			ledger.StoreWithdrawing(arg.StoreID, arg.AccountType),
+			arg.Amount,
+		))
+	})
+
+	return withdrawal, err
+}
+
+type CancelWithdrawalTxParams struct {
+	StoreID      int64
+	WithdrawalID int64
+}
+
+// CancelWithdrawalTx cancels a pending withdrawal request, giving its amount back to the store's account.
+func (dbTx *SQLTx) CancelWithdrawalTx(ctx context.Context, arg CancelWithdrawalTxParams) (WithdrawalRequest, error) {
+	var withdrawal WithdrawalRequest
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		withdrawal, err = q.GetWithdrawalRequestForUpdate(ctx, GetWithdrawalRequestForUpdateParams{
+			WithdrawalID: arg.WithdrawalID,
+			StoreID:      arg.StoreID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if withdrawal.Status != "PENDING" {
+			return ErrWithdrawalNotPending
+		}
+
+		switch withdrawal.AccountType {
+		case AccountTypeFiat:
+			_, err = q.CreditFiatAccount(ctx, CreditFiatAccountParams{
+				StoreID: withdrawal.StoreID,
+				Amount:  withdrawal.Amount,
+			})
+		default:
+			_, err = q.CreditCryptoAccount(ctx, CreditCryptoAccountParams{
+				StoreID: withdrawal.StoreID,
+				Amount:  withdrawal.Amount,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		withdrawal, err = q.UpdateWithdrawalRequestStatus(ctx, UpdateWithdrawalRequestStatusParams{
+			WithdrawalID: withdrawal.ID,
+			Status:       "CANCELLED",
+			Note:         withdrawal.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		amount, err := money.Parse(withdrawal.Amount)
+		if err != nil {
+			return err
+		}
+
+		return q.postEntry(ctx, ledger.Transfer(
+			ledger.KindPayoutCancel,
+			fmt.Sprintf("withdrawal:%d", withdrawal.ID),
+			ledger.StoreWithdrawing(withdrawal.StoreID, withdrawal.AccountType),
+			ledger.StoreAvailable(withdrawal.StoreID, withdrawal.AccountType),
+			amount,
+		))
+	})
+
+	return withdrawal, err
+}
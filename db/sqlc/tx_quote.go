@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+
+	"github.com/OCD-Labs/store-hub/fx"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+type QuoteCartTxResult struct {
+	CartID     int64                `json:"cart_id"`
+	Cart       []GetCartByUserIDRow `json:"cart"`
+	Breakdown  pricing.Breakdown    `json:"breakdown"`
+	FlashSales []FlashSale          `json:"flash_sales"`
+}
+
+type QuoteCartTxParams struct {
+	UserID int64
+	Region pricing.Region // where the cart ships to, if known
+}
+
+// QuoteCartTx prices a user's cart, with the coupons applied to it, and with
+// delivery and tax charged for the region it ships to. Items on a flash sale
+// are priced at its sale price.
+func (dbTx *SQLTx) QuoteCartTx(ctx context.Context, arg QuoteCartTxParams) (QuoteCartTxResult, error) {
+	var result QuoteCartTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.quoteCart(ctx, arg.UserID, arg.Region)
+		return err
+	})
+
+	return result, err
+}
+
+// quoteCart prices a user's cart, with the coupons applied to it and at the
+// price of the flash sales running, and with delivery and tax charged for region.
+func (q *Queries) quoteCart(ctx context.Context, userID int64, region pricing.Region) (QuoteCartTxResult, error) {
+	var result QuoteCartTxResult
+	var err error
+
+	result.CartID, err = q.GetCartID(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Cart, err = q.GetCartByUserID(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+
+	applied, err := q.ListCartCoupons(ctx, result.CartID)
+	if err != nil {
+		return result, err
+	}
+
+	coupons, err := pricingCoupons(applied)
+	if err != nil {
+		return result, err
+	}
+
+	lines, flashSales, err := q.liveFlashSaleLines(ctx, result.Cart)
+	if err != nil {
+		return result, err
+	}
+	result.FlashSales = flashSales
+
+	result.Breakdown, err = q.quoteLines(ctx, lines, region, coupons...)
+	if err != nil {
+		return result, err
+	}
+
+	result.Breakdown, err = q.withTax(ctx, result.Breakdown, lines, region)
+	return result, err
+}
+
+// QuoteLines prices lines with the delivery rules of their stores.
+func (dbTx *SQLTx) QuoteLines(ctx context.Context, lines []pricing.Line) (pricing.Breakdown, error) {
+	return dbTx.quoteLines(ctx, lines, pricing.Region{})
+}
+
+// quoteLines prices lines in fx.Base with what their stores charge to deliver
+// to region, taking coupons off the lines they cover. Lines listed in another
+// currency without a rate are converted at the current rate.
+func (q *Queries) quoteLines(ctx context.Context, lines []pricing.Line, region pricing.Region, coupons ...pricing.Coupon) (pricing.Breakdown, error) {
+	lines, err := q.withFxRates(ctx, lines, nil)
+	if err != nil {
+		return pricing.Breakdown{}, err
+	}
+
+	storeIDs := make([]int64, 0, len(lines))
+	for _, line := range lines {
+		storeIDs = append(storeIDs, line.StoreID)
+	}
+
+	rules, err := q.deliveryRules(ctx, storeIDs, region)
+	if err != nil {
+		return pricing.Breakdown{}, err
+	}
+
+	breakdown, err := pricing.Quote(lines, rules, coupons...)
+	if err != nil {
+		return pricing.Breakdown{}, err
+	}
+
+	breakdown.Currency = fx.Base
+	return breakdown, nil
+}
+
+// DeliveryRule converts r for the pricing component.
+func (r StoreDeliveryRule) DeliveryRule() (pricing.DeliveryRule, error) {
+	var rule pricing.DeliveryRule
+	var err error
+
+	rule.BaseFee, err = money.Parse(r.BaseFee)
+	if err != nil {
+		return rule, err
+	}
+
+	rule.PerItemFee, err = money.Parse(r.PerItemFee)
+	if err != nil {
+		return rule, err
+	}
+
+	rule.FreeDeliveryThreshold, err = money.Parse(r.FreeDeliveryThreshold)
+	return rule, err
+}
+
+// cartLines converts cart items to pricing lines.
+func cartLines(cart []GetCartByUserIDRow) []pricing.Line {
+	lines := make([]pricing.Line, 0, len(cart))
+	for _, cartItem := range cart {
+		lines = append(lines, pricing.Line{
+			ItemID:             cartItem.ItemID,
+			VariantID:          cartItem.VariantID.Int64,
+			StoreID:            cartItem.StoreID,
+			Price:              cartItem.Price,
+			Currency:           cartItem.ItemCurrency,
+			DiscountPercentage: cartItem.DiscountPercentage,
+			Category:           cartItem.ItemCategory,
+			Quantity:           cartItem.Quantity,
+			WeightGrams:        cartItem.ItemWeightGrams,
+		})
+	}
+	return lines
+}
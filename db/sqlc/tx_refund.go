@@ -0,0 +1,355 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/commission"
+	"github.com/OCD-Labs/store-hub/ledger"
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+var (
+	ErrOrderNotRefundable   = errors.New("only CANCELLED or RETURNED orders can be refunded")
+	ErrOrderNotPaid         = errors.New("order was not paid through a transaction")
+	ErrRefundExceedsOrder   = errors.New("refund exceeds what is left of the order's total")
+	ErrRestockExceedsOrder  = errors.New("restock quantity exceeds the units left to restock")
+	ErrRefundExceedsPayment = errors.New("refund exceeds what is left of the provider payment")
+)
+
+// Where a Refund goes.
+const (
+	RefundToOriginal    = "ORIGINAL"
+	RefundToStoreCredit = "STORE_CREDIT"
+)
+
+type CreateRefundTxParams struct {
+	StoreID int64
+	OrderID int64
+	// Amount is refunded; zero refunds all that is left of the order's total.
+	Amount money.Amount
+	// RestockQuantity units go back into supply; nil restocks all units left
+	// on a full refund, and none on a partial one.
+	RestockQuantity *int32
+	Reason          string
+	RequestedBy     int64
+	// Destination is where the refund goes; empty refunds to the original
+	// payment method.
+	Destination string
+}
+
+type CreateRefundTxResult struct {
+	Refund      Refund      `json:"refund"`
+	Order       Order       `json:"order"`
+	Transaction Transaction `json:"-"`
+}
+
+// CreateRefundTx records a PENDING refund for a CANCELLED or RETURNED order,
+// once it checks the order has enough left to refund. A refund to the original
+// payment method can't exceed what's left of what the provider took, as gift
+// cards and store credit may have paid for the rest. Nothing moves until
+// CompleteRefundTx.
+func (dbTx *SQLTx) CreateRefundTx(ctx context.Context, arg CreateRefundTxParams) (CreateRefundTxResult, error) {
+	var result CreateRefundTxResult
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock the order, so concurrent refunds see each other's totals.
+		result.Order, err = q.GetOrderForUpdate(ctx, GetOrderForUpdateParams{
+			OrderID: arg.OrderID,
+			StoreID: arg.StoreID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Order.DeliveryStatus != "CANCELLED" && result.Order.DeliveryStatus != "RETURNED" {
+			return ErrOrderNotRefundable
+		}
+
+		result.Transaction, err = q.GetOrderTransaction(ctx, result.Order.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrOrderNotPaid
+			}
+			return err
+		}
+
+		totals, err := q.GetOrderRefundTotals(ctx, result.Order.ID)
+		if err != nil {
+			return err
+		}
+
+		refunded, err := money.Parse(totals.Amount)
+		if err != nil {
+			return err
+		}
+
+		total, err := orderTotal(result.Order)
+		if err != nil {
+			return err
+		}
+
+		remaining := total - refunded
+		amount := arg.Amount
+		if amount == money.Zero {
+			amount = remaining
+		}
+		if amount <= money.Zero || amount > remaining {
+			return fmt.Errorf("%w: %s left", ErrRefundExceedsOrder, remaining)
+		}
+
+		unitsLeft := result.Order.OrderQuantity - totals.RestockQuantity
+		restock := int32(0)
+		switch {
+		case arg.RestockQuantity != nil:
+			restock = *arg.RestockQuantity
+		case amount == remaining:
+			restock = unitsLeft
+		}
+		if restock < 0 || restock > unitsLeft {
+			return fmt.Errorf("%w: %d left", ErrRestockExceedsOrder, unitsLeft)
+		}
+
+		destination := arg.Destination
+		if destination == "" {
+			destination = RefundToOriginal
+		}
+
+		if destination == RefundToOriginal {
+			// Lock the transaction, so refunds of its other orders see this one.
+			transaction, err := q.GetTransactionByRefIDForUpdate(ctx, result.Transaction.ProviderTxRefID)
+			if err != nil {
+				return err
+			}
+
+			paid, err := money.Parse(transaction.Amount)
+			if err != nil {
+				return err
+			}
+
+			refundedAmount, err := q.GetTransactionRefundedAmount(ctx, GetTransactionRefundedAmountParams{
+				TransactionID: transaction.ID,
+				Destination:   RefundToOriginal,
+			})
+			if err != nil {
+				return err
+			}
+
+			refundedToOriginal, err := money.Parse(refundedAmount)
+			if err != nil {
+				return err
+			}
+
+			if left := paid - refundedToOriginal; amount > left {
+				return fmt.Errorf("%w: %s left", ErrRefundExceedsPayment, left)
+			}
+		}
+
+		// The platform gives back its commission in proportion to the refund.
+		orderCommission, err := money.Parse(result.Order.CommissionAmount)
+		if err != nil {
+			return err
+		}
+		commissionAmount := commission.Share(orderCommission, refunded+amount, total) -
+			commission.Share(orderCommission, refunded, total)
+
+		result.Refund, err = q.CreateRefund(ctx, CreateRefundParams{
+			OrderID:          result.Order.ID,
+			TransactionID:    result.Transaction.ID,
+			StoreID:          result.Order.StoreID,
+			Amount:           amount.String(),
+			CommissionAmount: commissionAmount.String(),
+			RestockQuantity:  restock,
+			Reason:           arg.Reason,
+			RequestedBy:      arg.RequestedBy,
+			Destination:      destination,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+type CompleteRefundTxParams struct {
+	RefundID         int64
+	ProviderRefundID string
+}
+
+// CompleteRefundTx completes a PENDING refund once the provider accepts it, or
+// at once for a refund to store credit, which it adds to the buyer's wallet.
+// The store's share of the amount comes out of its pending funds, or its
+// available balance if the order's funds were already released, and the rest
+// out of the platform's commission; the restocked units go back into the
+// item's supply, as a RETURN by whoever requested the refund.
+func (dbTx *SQLTx) CompleteRefundTx(ctx context.Context, arg CompleteRefundTxParams) (Refund, error) {
+	var refund Refund
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+		refund, err = q.completeRefund(ctx, arg)
+		return err
+	})
+
+	return refund, err
+}
+
+// completeRefund completes a PENDING refund, as CompleteRefundTx does.
+func (q *Queries) completeRefund(ctx context.Context, arg CompleteRefundTxParams) (Refund, error) {
+	refund, err := q.GetRefundForUpdate(ctx, arg.RefundID)
+	if err != nil {
+		return refund, err
+	}
+
+	if refund.Status != "PENDING" {
+		return refund, nil
+	}
+
+	order, err := q.GetOrderForUpdate(ctx, GetOrderForUpdateParams{
+		OrderID: refund.OrderID,
+		StoreID: refund.StoreID,
+	})
+	if err != nil {
+		return refund, err
+	}
+
+	transaction, err := q.GetOrderTransaction(ctx, order.ID)
+	if err != nil {
+		return refund, err
+	}
+
+	amount, err := money.Parse(refund.Amount)
+	if err != nil {
+		return refund, err
+	}
+
+	commissionAmount, err := money.Parse(refund.CommissionAmount)
+	if err != nil {
+		return refund, err
+	}
+
+	// the store only gives back its share; the platform returns its commission
+	storeAmount := amount - commissionAmount
+
+	accountType := AccountTypeForChannel(order.PaymentChannel)
+	from := ledger.StorePending(order.StoreID, accountType)
+
+	switch {
+	case !order.FundsReleasedAt.Valid:
+		_, err = q.ReducePendingFunds(ctx, ReducePendingFundsParams{
+			StoreID:     order.StoreID,
+			AccountType: accountType,
+			Amount:      storeAmount.String(),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("store %d holds less than %s pending", order.StoreID, storeAmount)
+		}
+	case accountType == AccountTypeFiat:
+		from = ledger.StoreAvailable(order.StoreID, accountType)
+		_, err = q.ChargeBackFiatAccount(ctx, ChargeBackFiatAccountParams{
+			StoreID: order.StoreID,
+			Amount:  storeAmount.String(),
+		})
+	default:
+		from = ledger.StoreAvailable(order.StoreID, accountType)
+		_, err = q.ChargeBackCryptoAccount(ctx, ChargeBackCryptoAccountParams{
+			StoreID: order.StoreID,
+			Amount:  storeAmount.String(),
+		})
+	}
+	if err != nil {
+		return refund, err
+	}
+
+	if refund.RestockQuantity > 0 {
+		err = q.setStockMovementContext(ctx, stockMovementContext{
+			Reason:    StockReturn,
+			UserID:    refund.RequestedBy,
+			Reference: fmt.Sprintf("refund:%d", refund.ID),
+			Note:      fmt.Sprintf("refund of order %d", order.ID),
+		})
+		if err != nil {
+			return refund, err
+		}
+
+		err = q.RestockItem(ctx, RestockItemParams{
+			ItemID:   order.ItemID,
+			Quantity: int64(refund.RestockQuantity),
+		})
+		if err != nil {
+			return refund, err
+		}
+
+		if order.VariantID.Valid {
+			err = q.RestockItemVariant(ctx, RestockItemVariantParams{
+				VariantID: order.VariantID.Int64,
+				Quantity:  int64(refund.RestockQuantity),
+			})
+			if err != nil {
+				return refund, err
+			}
+		}
+	}
+
+	to := ledger.ProviderCash(transaction.PaymentProvider)
+	if refund.Destination == RefundToStoreCredit {
+		to = ledger.StoreCredit()
+		err = q.adjustStoreCredit(ctx, order.BuyerID, amount, StoreCreditRefund,
+			fmt.Sprintf("refund:%d", refund.ID), fmt.Sprintf("refund of order %d", order.ID))
+		if err != nil {
+			return refund, err
+		}
+	}
+
+	err = q.postEntry(ctx, ledger.Entry{
+		Kind:        ledger.KindRefund,
+		Reference:   fmt.Sprintf("refund:%d", refund.ID),
+		Description: fmt.Sprintf("refund of order %d, paid by %s", order.ID, transaction.ProviderTxRefID),
+		Postings: []ledger.Posting{
+			ledger.Debit(from, storeAmount),
+			ledger.Debit(ledger.Commission(), commissionAmount),
+			ledger.Credit(to, amount),
+		},
+	})
+	if err != nil {
+		return refund, err
+	}
+
+	return q.CompleteRefund(ctx, CompleteRefundParams{
+		RefundID:         refund.ID,
+		ProviderRefundID: arg.ProviderRefundID,
+	})
+}
+
+// orderTotal is what the buyer paid for an order, after any coupon discount and
+// with delivery and exclusive tax included.
+func orderTotal(order Order) (money.Amount, error) {
+	price, err := money.Parse(order.ItemPrice)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	deliveryFee, err := money.Parse(order.DeliveryFee)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	discount, err := money.Parse(order.DiscountAmount)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	total := price.Mul(int64(order.OrderQuantity)) - discount + deliveryFee
+	if !order.TaxInclusive {
+		tax, err := money.Parse(order.TaxAmount)
+		if err != nil {
+			return money.Zero, err
+		}
+		total += tax
+	}
+
+	return total, nil
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+// ShippingRateParams is a row of a shipping zone's rate table.
+type ShippingRateParams struct {
+	FromQuantity int64
+	Fee          string
+}
+
+type CreateShippingZoneTxParams struct {
+	CreateShippingZoneParams
+	Rates []ShippingRateParams
+}
+
+type UpdateShippingZoneTxParams struct {
+	UpdateShippingZoneParams
+	Rates []ShippingRateParams
+}
+
+// ShippingZoneWithRates is a shipping zone with its rate table.
+type ShippingZoneWithRates struct {
+	ShippingZone
+	Rates []ShippingRate `json:"rates"`
+}
+
+// CreateShippingZoneTx creates a shipping zone with its rate table.
+func (dbTx *SQLTx) CreateShippingZoneTx(ctx context.Context, arg CreateShippingZoneTxParams) (ShippingZoneWithRates, error) {
+	var result ShippingZoneWithRates
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.ShippingZone, err = q.CreateShippingZone(ctx, arg.CreateShippingZoneParams)
+		if err != nil {
+			return err
+		}
+
+		result.Rates, err = q.createShippingRates(ctx, result.ID, arg.Rates)
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateShippingZoneTx updates a shipping zone, replacing its rate table.
+func (dbTx *SQLTx) UpdateShippingZoneTx(ctx context.Context, arg UpdateShippingZoneTxParams) (ShippingZoneWithRates, error) {
+	var result ShippingZoneWithRates
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.ShippingZone, err = q.UpdateShippingZone(ctx, arg.UpdateShippingZoneParams)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteShippingRates(ctx, result.ID); err != nil {
+			return err
+		}
+
+		result.Rates, err = q.createShippingRates(ctx, result.ID, arg.Rates)
+		return err
+	})
+
+	return result, err
+}
+
+// createShippingRates creates a shipping zone's rate table.
+func (q *Queries) createShippingRates(ctx context.Context, shippingZoneID int64, rates []ShippingRateParams) ([]ShippingRate, error) {
+	created := make([]ShippingRate, 0, len(rates))
+	for _, rate := range rates {
+		r, err := q.CreateShippingRate(ctx, CreateShippingRateParams{
+			ShippingZoneID: shippingZoneID,
+			FromQuantity:   rate.FromQuantity,
+			Fee:            rate.Fee,
+		})
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, r)
+	}
+	return created, nil
+}
+
+// PricingZone converts z, with its rate table, for the pricing component.
+func (z ShippingZone) PricingZone(rates []ShippingRate) (pricing.ShippingZone, error) {
+	threshold, err := money.Parse(z.FreeShippingThreshold)
+	if err != nil {
+		return pricing.ShippingZone{}, err
+	}
+
+	rule := pricing.DeliveryRule{
+		ZoneID:                z.ID,
+		FreeDeliveryThreshold: threshold,
+		Basis:                 z.RateBasis,
+		Rates:                 make([]pricing.Rate, 0, len(rates)),
+		MinDays:               z.MinDeliveryDays,
+		MaxDays:               z.MaxDeliveryDays,
+	}
+	for _, r := range rates {
+		fee, err := money.Parse(r.Fee)
+		if err != nil {
+			return pricing.ShippingZone{}, err
+		}
+		rule.Rates = append(rule.Rates, pricing.Rate{From: r.FromQuantity, Fee: fee})
+	}
+
+	return pricing.ShippingZone{
+		ID:      z.ID,
+		StoreID: z.StoreID,
+		Country: z.Country,
+		State:   z.State,
+		City:    z.City,
+		Rule:    rule,
+	}, nil
+}
+
+// deliveryRules returns how each of storeIDs charges for delivery to region:
+// by its most specific shipping zone covering region, or else by its
+// delivery rules. A store with neither delivers for free.
+func (q *Queries) deliveryRules(ctx context.Context, storeIDs []int64, region pricing.Region) (map[int64]pricing.DeliveryRule, error) {
+	storeRules, err := q.ListStoreDeliveryRules(ctx, storeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[int64]pricing.DeliveryRule, len(storeRules))
+	for _, r := range storeRules {
+		rules[r.StoreID], err = r.DeliveryRule()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if region.Country == "" {
+		return rules, nil
+	}
+
+	zones, err := q.ListStoreShippingZones(ctx, ListStoreShippingZonesParams{
+		StoreIds: storeIDs,
+		Country:  region.Country,
+	})
+	if err != nil || len(zones) == 0 {
+		return rules, err
+	}
+
+	zoneIDs := make([]int64, 0, len(zones))
+	for _, z := range zones {
+		zoneIDs = append(zoneIDs, z.ID)
+	}
+
+	rates, err := q.ListShippingRates(ctx, zoneIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneRates := make(map[int64][]ShippingRate, len(zones))
+	for _, r := range rates {
+		zoneRates[r.ShippingZoneID] = append(zoneRates[r.ShippingZoneID], r)
+	}
+
+	storeZones := make(map[int64][]pricing.ShippingZone)
+	for _, z := range zones {
+		zone, err := z.PricingZone(zoneRates[z.ID])
+		if err != nil {
+			return nil, err
+		}
+		storeZones[z.StoreID] = append(storeZones[z.StoreID], zone)
+	}
+
+	for storeID, zones := range storeZones {
+		if zone, ok := pricing.MatchShippingZone(zones, region); ok {
+			rules[storeID] = zone.Rule
+		}
+	}
+
+	return rules, nil
+}
+
+// expectedDeliveryDate is when an order placed at now from store is expected,
+// invalid when its shipping zone, if any, doesn't say.
+func expectedDeliveryDate(store pricing.StoreBreakdown, now time.Time) (time.Time, bool) {
+	if store.MaxDeliveryDays == 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, int(store.MaxDeliveryDays)), true
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Reasons of a StockMovement.
+const (
+	StockInitial    = "INITIAL"
+	StockSale       = "SALE"
+	StockAdjustment = "ADJUSTMENT"
+	StockTake       = "STOCK_TAKE"
+	StockReturn     = "RETURN"
+	StockImport     = "IMPORT"
+
+	// Recorded for stock held for a checkout, and given back when the hold
+	// ends, without changing the supply.
+	StockReserve = "RESERVATION"
+	StockRelease = "RELEASE"
+)
+
+// Kinds of StockAlert.
+const (
+	StockAlertLow        = "LOW_STOCK"
+	StockAlertOutOfStock = "OUT_OF_STOCK"
+)
+
+// A stockMovementContext is why the supply changes within a transaction, as
+// recorded with each stock movement. A zero UserID records no user.
+type stockMovementContext struct {
+	Reason    string
+	UserID    int64
+	Reference string
+	Note      string
+}
+
+// setStockMovementContext tells the triggers recording stock movements why
+// the supply changes for the rest of the transaction. An empty reason
+// leaves them to record INITIAL for a new item or variant and ADJUSTMENT
+// otherwise.
+func (q *Queries) setStockMovementContext(ctx context.Context, arg stockMovementContext) error {
+	var userID string
+	if arg.UserID != 0 {
+		userID = strconv.FormatInt(arg.UserID, 10)
+	}
+
+	return q.SetStockMovementContext(ctx, SetStockMovementContextParams{
+		Reason:    arg.Reason,
+		UserID:    userID,
+		Reference: arg.Reference,
+		Note:      arg.Note,
+	})
+}
+
+// A StockCount is the quantity of an item, or of one of its variants, counted
+// in a stock-take.
+type StockCount struct {
+	ItemID          int64
+	VariantID       int64 // zero for an item without variants
+	CountedQuantity int64
+}
+
+type StockTakeTxParams struct {
+	StoreID   int64
+	UserID    int64
+	Reference string
+	Note      string
+	Counts    []StockCount
+}
+
+// StockTakeTx sets the supply of the counted items and variants of a store to
+// their counts, and returns the STOCK_TAKE movements it recorded under
+// reference. A count matching the supply records none. An item with
+// variants is counted by variant, failing with ErrVariantRequired otherwise,
+// and keeps the sum of their supply.
+func (dbTx *SQLTx) StockTakeTx(ctx context.Context, arg StockTakeTxParams) ([]StockMovement, error) {
+	var movements []StockMovement
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		err := q.setStockMovementContext(ctx, stockMovementContext{
+			Reason:    StockTake,
+			UserID:    arg.UserID,
+			Reference: arg.Reference,
+			Note:      arg.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, count := range arg.Counts {
+			if err := q.takeStockCount(ctx, arg.StoreID, count); err != nil {
+				return err
+			}
+		}
+
+		movements, err = q.ListStockMovementsByReference(ctx, ListStockMovementsByReferenceParams{
+			StoreID:   arg.StoreID,
+			Reference: arg.Reference,
+		})
+		return err
+	})
+
+	return movements, err
+}
+
+func (q *Queries) takeStockCount(ctx context.Context, storeID int64, count StockCount) error {
+	if count.VariantID == 0 {
+		variants, err := q.CountItemVariants(ctx, count.ItemID)
+		if err != nil {
+			return err
+		}
+		if variants > 0 {
+			return fmt.Errorf("%w: %d", ErrVariantRequired, count.ItemID)
+		}
+
+		_, err = q.SetItemSupply(ctx, SetItemSupplyParams{
+			ItemID:         count.ItemID,
+			StoreID:        storeID,
+			SupplyQuantity: count.CountedQuantity,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %d", ErrItemNotFound, count.ItemID)
+		}
+		return err
+	}
+
+	_, err := q.SetItemVariantSupply(ctx, SetItemVariantSupplyParams{
+		VariantID:      count.VariantID,
+		ItemID:         count.ItemID,
+		StoreID:        storeID,
+		SupplyQuantity: count.CountedQuantity,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %d", ErrVariantNotFound, count.VariantID)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = q.SumItemVariantsSupply(ctx, count.ItemID)
+	return err
+}
@@ -0,0 +1,237 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Statuses of a StockReservation.
+const (
+	ReservationReserved  = "RESERVED"
+	ReservationConverted = "CONVERTED"
+	ReservationReleased  = "RELEASED"
+	ReservationExpired   = "EXPIRED"
+)
+
+// availableStock locks an item of a store, and returns its supply less the live
+// reservations held for checkouts other than reference, and the units its flash
+// sales hold for their buyers.
+func (q *Queries) availableStock(ctx context.Context, itemID, storeID int64, reference string) (int64, error) {
+	supplyQuantity, err := q.CheckItemStoreMatch(ctx, CheckItemStoreMatchParams{
+		ItemID:  itemID,
+		StoreID: storeID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %d", ErrItemNotFound, itemID)
+		}
+		return 0, err
+	}
+
+	reserved, err := q.GetReservedStock(ctx, GetReservedStockParams{
+		ItemID:    itemID,
+		Reference: reference,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	held, err := q.GetFlashSaleHeldStock(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+
+	return supplyQuantity - reserved - held, nil
+}
+
+// availableVariantStock locks a variant of an item, and returns its supply
+// less the live reservations held for checkouts other than reference.
+func (q *Queries) availableVariantStock(ctx context.Context, variantID, itemID int64, reference string) (int64, error) {
+	itemVariant, err := q.GetItemVariantForUpdate(ctx, GetItemVariantForUpdateParams{
+		VariantID: variantID,
+		ItemID:    itemID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %d", ErrVariantNotFound, variantID)
+		}
+		return 0, err
+	}
+
+	reserved, err := q.GetReservedVariantStock(ctx, GetReservedVariantStockParams{
+		VariantID: sql.NullInt64{Int64: variantID, Valid: true},
+		Reference: reference,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return itemVariant.SupplyQuantity - reserved, nil
+}
+
+// checkStock locks the items in cart, then the variants they're in, and checks
+// each has its cart quantity available to the checkout under reference. An
+// item's supply counts all its variants, so it needs what the cart holds of it
+// in all of them. Items, then variants, are locked in id order, so concurrent
+// checkouts of the same items can't deadlock. Items in flashSaleItems are
+// skipped; the checkout's flash sale claims hold their stock.
+func (q *Queries) checkStock(ctx context.Context, cart []GetCartByUserIDRow, reference string, flashSaleItems map[int64]bool) error {
+	cart = append([]GetCartByUserIDRow(nil), cart...)
+	sort.Slice(cart, func(i, j int) bool {
+		if cart[i].ItemID != cart[j].ItemID {
+			return cart[i].ItemID < cart[j].ItemID
+		}
+		return cart[i].VariantID.Int64 < cart[j].VariantID.Int64
+	})
+
+	for i, cartItem := range cart {
+		if flashSaleItems[cartItem.ItemID] || i > 0 && cart[i-1].ItemID == cartItem.ItemID {
+			continue
+		}
+
+		var quantity int64
+		for _, other := range cart[i:] {
+			if other.ItemID != cartItem.ItemID {
+				break
+			}
+			quantity += int64(other.Quantity)
+		}
+
+		available, err := q.availableStock(ctx, cartItem.ItemID, cartItem.StoreID, reference)
+		if err != nil {
+			return err
+		}
+
+		if available < quantity {
+			return fmt.Errorf("%w: %s", ErrInsufficientStock, cartItem.ItemName)
+		}
+	}
+
+	sort.Slice(cart, func(i, j int) bool { return cart[i].VariantID.Int64 < cart[j].VariantID.Int64 })
+
+	for _, cartItem := range cart {
+		if !cartItem.VariantID.Valid {
+			// An item that has variants is bought in one of them.
+			variants, err := q.CountItemVariants(ctx, cartItem.ItemID)
+			if err != nil {
+				return err
+			}
+			if variants > 0 {
+				return fmt.Errorf("%w: %s", ErrVariantRequired, cartItem.ItemName)
+			}
+			continue
+		}
+
+		if flashSaleItems[cartItem.ItemID] {
+			continue
+		}
+
+		available, err := q.availableVariantStock(ctx, cartItem.VariantID.Int64, cartItem.ItemID, reference)
+		if err != nil {
+			return err
+		}
+
+		if available < int64(cartItem.Quantity) {
+			return fmt.Errorf("%w: %s (%s)", ErrInsufficientStock, cartItem.ItemName, cartItem.VariantSku)
+		}
+	}
+
+	return nil
+}
+
+// reserveStock holds the quantity of each item in cart for the checkout under
+// reference until expiresAt, failing if any isn't available. Items in
+// flashSaleItems aren't reserved; the checkout's flash sale claims hold them.
+// Each reservation is recorded as a StockReserve movement by a trigger.
+func (q *Queries) reserveStock(ctx context.Context, userID int64, reference string, cart []GetCartByUserIDRow, expiresAt time.Time, flashSaleItems map[int64]bool) error {
+	if err := q.checkStock(ctx, cart, reference, flashSaleItems); err != nil {
+		return err
+	}
+
+	for _, cartItem := range cart {
+		if flashSaleItems[cartItem.ItemID] {
+			continue
+		}
+
+		_, err := q.CreateStockReservation(ctx, CreateStockReservationParams{
+			ItemID:    cartItem.ItemID,
+			VariantID: cartItem.VariantID,
+			UserID:    userID,
+			Reference: reference,
+			Quantity:  cartItem.Quantity,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseStock releases the stock still reserved for the checkout under
+// reference. A trigger records each release as a StockRelease movement, as it
+// does a reservation expiring or converting.
+func (q *Queries) releaseStock(ctx context.Context, reference string) error {
+	_, err := q.UpdateStockReservationsStatus(ctx, UpdateStockReservationsStatusParams{
+		Reference:  reference,
+		FromStatus: ReservationReserved,
+		ToStatus:   ReservationReleased,
+	})
+	return err
+}
+
+type BuyItemTxParams struct {
+	ItemID   int64
+	StoreID  int64
+	Quantity int64
+	UserID   int64 // the buyer
+}
+
+// BuyItemTx deducts quantity from the supply of an item of a store, failing with
+// ErrInsufficientStock unless that much is available net of reservations. An
+// item with variants can't be bought this way, but only in one of them. The
+// deduction is recorded as a SALE to the buyer.
+func (dbTx *SQLTx) BuyItemTx(ctx context.Context, arg BuyItemTxParams) (Item, error) {
+	var item Item
+
+	err := dbTx.execTx(ctx, func(q *Queries) error {
+		err := q.setStockMovementContext(ctx, stockMovementContext{
+			Reason: StockSale,
+			UserID: arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		available, err := q.availableStock(ctx, arg.ItemID, arg.StoreID, "")
+		if err != nil {
+			return err
+		}
+
+		variants, err := q.CountItemVariants(ctx, arg.ItemID)
+		if err != nil {
+			return err
+		}
+
+		if variants > 0 {
+			return fmt.Errorf("%w: %d", ErrVariantRequired, arg.ItemID)
+		}
+
+		if available < arg.Quantity {
+			return fmt.Errorf("%w: %d", ErrInsufficientStock, arg.ItemID)
+		}
+
+		item, err = q.DeductItemSupply(ctx, DeductItemSupplyParams{
+			ItemID:        arg.ItemID,
+			OrderQuantity: arg.Quantity,
+		})
+		return err
+	})
+
+	return item, err
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/OCD-Labs/store-hub/pricing"
+)
+
+// PricingTaxRule converts r for the pricing component.
+func (r TaxRule) PricingTaxRule() pricing.TaxRule {
+	return pricing.TaxRule{
+		ID:        r.ID,
+		StoreID:   r.StoreID,
+		Name:      r.Name,
+		Country:   r.Country,
+		State:     r.State,
+		Category:  r.Category,
+		Rate:      r.Rate,
+		Inclusive: r.Inclusive,
+	}
+}
+
+// shippingRegion is the region a shipping address is in, empty if it has none.
+func shippingRegion(shippingAddress json.RawMessage) pricing.Region {
+	var address struct {
+		Country string `json:"country"`
+		State   string `json:"state"`
+		City    string `json:"city"`
+	}
+	if len(shippingAddress) > 0 {
+		// an address without a region is taxed nowhere
+		_ = json.Unmarshal(shippingAddress, &address)
+	}
+	return pricing.Region{Country: address.Country, State: address.State, City: address.City}
+}
+
+// withTax taxes breakdown, priced from lines, under the rules of its stores for
+// sales shipped to region. Nothing is taxed when region has no country.
+func (q *Queries) withTax(ctx context.Context, breakdown pricing.Breakdown, lines []pricing.Line, region pricing.Region) (pricing.Breakdown, error) {
+	if region.Country == "" || len(breakdown.Stores) == 0 {
+		return breakdown, nil
+	}
+
+	storeIDs := make([]int64, 0, len(breakdown.Stores))
+	for _, store := range breakdown.Stores {
+		storeIDs = append(storeIDs, store.StoreID)
+	}
+
+	storeRules, err := q.ListStoreTaxRules(ctx, ListStoreTaxRulesParams{
+		StoreIds: storeIDs,
+		Country:  region.Country,
+	})
+	if err != nil {
+		return pricing.Breakdown{}, err
+	}
+
+	rules := make(map[int64][]pricing.TaxRule, len(storeIDs))
+	for _, r := range storeRules {
+		rules[r.StoreID] = append(rules[r.StoreID], r.PricingTaxRule())
+	}
+
+	return pricing.ApplyTax(breakdown, lines, rules, region)
+}
+
+// checkoutRegion is the region the checkout under reference ships to, empty
+// for a checkout started before order groups existed.
+func (q *Queries) checkoutRegion(ctx context.Context, reference string) (pricing.Region, error) {
+	orderGroup, err := q.GetOrderGroupByReferenceForUpdate(ctx, reference)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return pricing.Region{}, nil
+		}
+		return pricing.Region{}, err
+	}
+
+	return shippingRegion(orderGroup.ShippingAddress), nil
+}
@@ -2,11 +2,16 @@ package db
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/OCD-Labs/store-hub/ledger"
+	"github.com/OCD-Labs/store-hub/money"
 	"github.com/OCD-Labs/store-hub/util"
 )
 
-// UpdateSellerOrderTx updates a order row, create a sale row if order is DELIVERED.
+// UpdateSellerOrderTx updates a order row, create a sale row and releases the
+// order's pending funds to the store if order is DELIVERED. The status of the
+// fulfilment group it ships in follows.
 func (dbTx SQLTx) UpdateSellerOrderTx(ctx context.Context, arg UpdateSellerOrderParams) (GetOrderForSellerRow, error) {
 	var sellerOrder GetOrderForSellerRow
 	var err error
@@ -23,39 +28,11 @@ func (dbTx SQLTx) UpdateSellerOrderTx(ctx context.Context, arg UpdateSellerOrder
 	if sellerOrder.DeliveryStatus != arg.DeliveryStatus.String && util.CanChangeStatus(sellerOrder.DeliveryStatus, arg.DeliveryStatus.String) {
 
 		err = dbTx.execTx(ctx, func(q *Queries) error {
-			var o Order
-
-			o, err = q.UpdateSellerOrder(ctx, arg)
+			o, err := q.updateSellerOrder(ctx, arg)
 			if err != nil {
 				return err
 			}
 
-			if o.DeliveryStatus == "DELIVERED" {
-				sArg := CreateSaleParams{
-					StoreID:    o.StoreID,
-					ItemID:     o.ItemID,
-					CustomerID: o.BuyerID,
-					SellerID:   o.SellerID,
-					OrderID:    o.ID,
-				}
-
-				_, err = q.CreateSale(ctx, sArg)
-				if err != nil {
-					return err
-				}
-			}
-
-			if o.DeliveryStatus == "RETURNED" {
-				err = q.ReduceSalesOverview(ctx, ReduceSalesOverviewParams{
-					StoreID: o.StoreID,
-					ItemID:  o.ItemID,
-					OrderID: o.ID,
-				})
-				if err != nil {
-					return err
-				}
-			}
-
 			sellerOrder.DeliveredOn = o.DeliveredOn
 			sellerOrder.ExpectedDeliveryDate = o.ExpectedDeliveryDate
 			sellerOrder.DeliveryStatus = o.DeliveryStatus
@@ -68,3 +45,39 @@ func (dbTx SQLTx) UpdateSellerOrderTx(ctx context.Context, arg UpdateSellerOrder
 
 	return sellerOrder, err
 }
+
+// deliverOrder creates a sale row for a DELIVERED order, and releases its
+// pending funds to the store.
+func (q *Queries) deliverOrder(ctx context.Context, o Order) error {
+	sArg := CreateSaleParams{
+		StoreID:    o.StoreID,
+		ItemID:     o.ItemID,
+		CustomerID: o.BuyerID,
+		SellerID:   o.SellerID,
+		OrderID:    o.ID,
+	}
+
+	_, err := q.CreateSale(ctx, sArg)
+	if err != nil {
+		return err
+	}
+
+	released, err := q.ReleaseFunds(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+
+	amount, err := money.Parse(released)
+	if err != nil {
+		return err
+	}
+
+	accountType := AccountTypeForChannel(o.PaymentChannel)
+	return q.postEntry(ctx, ledger.Transfer(
+		ledger.KindRelease,
+		fmt.Sprintf("order:%d", o.ID),
+		ledger.StorePending(o.StoreID, accountType),
+		ledger.StoreAvailable(o.StoreID, accountType),
+		amount,
+	))
+}
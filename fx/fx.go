@@ -0,0 +1,134 @@
+// Package fx converts amounts between currencies. Prices are quoted and
+// orders settle in Base; every other currency has a rate giving the
+// value of one unit of it in Base.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/go-playground/validator/v10"
+)
+
+// Base is the currency prices are quoted and orders settle in.
+const Base = "NGN"
+
+// ErrNoRate is returned when a currency has no rate.
+var ErrNoRate = errors.New("fx: no rate for currency")
+
+var validate = validator.New()
+
+// ValidCode reports whether code is an ISO-4217 currency code.
+func ValidCode(code string) bool {
+	return validate.Var(code, "required,iso4217") == nil
+}
+
+// Rates maps currencies to the value of one unit of each in Base.
+type Rates map[string]string
+
+// Rate returns the rate converting amounts in from to amounts in to, as an
+// exact fraction such as "1/1540".
+func (r Rates) Rate(from, to string) (string, error) {
+	if from == to {
+		return "1", nil
+	}
+
+	fromRate, err := r.rat(from)
+	if err != nil {
+		return "", err
+	}
+
+	toRate, err := r.rat(to)
+	if err != nil {
+		return "", err
+	}
+
+	return new(big.Rat).Quo(fromRate, toRate).RatString(), nil
+}
+
+// BaseRate returns the rate converting amounts in currency to amounts in
+// Base, as a decimal with 8 places.
+func (r Rates) BaseRate(currency string) (string, error) {
+	rate, err := r.rat(currency)
+	if err != nil {
+		return "", err
+	}
+	return rate.FloatString(8), nil
+}
+
+// Convert converts amount in from to an amount in to.
+func (r Rates) Convert(amount money.Amount, from, to string) (money.Amount, error) {
+	rate, err := r.Rate(from, to)
+	if err != nil {
+		return money.Zero, err
+	}
+	return amount.Scale(rate)
+}
+
+// rat parses the rate of currency.
+func (r Rates) rat(currency string) (*big.Rat, error) {
+	if currency == Base {
+		return big.NewRat(1, 1), nil
+	}
+
+	s, ok := r[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoRate, currency)
+	}
+
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("fx: invalid rate %q for %s", s, currency)
+	}
+	return rate, nil
+}
+
+// Rebase converts rates quoted as units of each currency per one unit of
+// base, the way most rate feeds publish them, to Rates. rates must quote
+// Base unless base is Base. Rates are kept to 8 decimal places.
+func Rebase(base string, rates map[string]string) (Rates, error) {
+	if !ValidCode(base) {
+		return nil, fmt.Errorf("fx: invalid currency %q", base)
+	}
+
+	perBase := big.NewRat(1, 1) // units of Base per one unit of base
+	if base != Base {
+		s, ok := rates[Base]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", ErrNoRate, Base)
+		}
+
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+		if !ok || r.Sign() <= 0 {
+			return nil, fmt.Errorf("fx: invalid rate %q for %s", s, Base)
+		}
+		perBase = r
+	}
+
+	out := Rates{}
+	if base != Base {
+		out[base] = perBase.FloatString(8)
+	}
+
+	for currency, s := range rates {
+		if currency == Base || currency == base {
+			continue
+		}
+		if !ValidCode(currency) {
+			return nil, fmt.Errorf("fx: invalid currency %q", currency)
+		}
+
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+		if !ok || r.Sign() <= 0 {
+			return nil, fmt.Errorf("fx: invalid rate %q for %s", s, currency)
+		}
+
+		// one unit of currency is worth 1/r of base, and perBase/r of Base
+		out[currency] = new(big.Rat).Quo(perBase, r).FloatString(8)
+	}
+
+	return out, nil
+}
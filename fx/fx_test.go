@@ -0,0 +1,86 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidCode(t *testing.T) {
+	require.True(t, ValidCode("NGN"))
+	require.True(t, ValidCode("USD"))
+	require.False(t, ValidCode("usd"))
+	require.False(t, ValidCode("XYZ"))
+	require.False(t, ValidCode(""))
+}
+
+func TestRatesConvert(t *testing.T) {
+	rates := Rates{"USD": "1500", "GBP": "1875"}
+
+	got, err := rates.Convert(money.MustParse("10.00"), "USD", Base)
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("15000.00"), got)
+
+	got, err = rates.Convert(money.MustParse("3000.00"), Base, "USD")
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("2.00"), got)
+
+	got, err = rates.Convert(money.MustParse("10.00"), "GBP", "USD")
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("12.50"), got)
+
+	got, err = rates.Convert(money.MustParse("10.00"), "EUR", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("10.00"), got)
+
+	_, err = rates.Convert(money.MustParse("10.00"), "EUR", Base)
+	require.ErrorIs(t, err, ErrNoRate)
+
+	rate, err := rates.BaseRate("USD")
+	require.NoError(t, err)
+	require.Equal(t, "1500.00000000", rate)
+
+	rate, err = rates.Rate(Base, "USD")
+	require.NoError(t, err)
+	require.Equal(t, "1/1500", rate)
+}
+
+func TestRebase(t *testing.T) {
+	rates, err := Rebase("USD", map[string]string{"NGN": "1500", "GBP": "0.8"})
+	require.NoError(t, err)
+	require.Equal(t, Rates{"USD": "1500.00000000", "GBP": "1875.00000000"}, rates)
+
+	rates, err = Rebase(Base, map[string]string{"USD": "0.0005"})
+	require.NoError(t, err)
+	require.Equal(t, Rates{"USD": "2000.00000000"}, rates)
+
+	_, err = Rebase("USD", map[string]string{"GBP": "0.8"})
+	require.ErrorIs(t, err, ErrNoRate)
+
+	_, err = Rebase(Base, map[string]string{"ABC": "1"})
+	require.Error(t, err)
+
+	_, err = Rebase(Base, map[string]string{"USD": "-1"})
+	require.Error(t, err)
+}
+
+func TestStaticFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err := os.WriteFile(path, []byte(`{"base": "USD", "as_of": "2026-10-18T00:00:00Z", "rates": {"NGN": "1500", "GBP": "0.8"}}`), 0o600)
+	require.NoError(t, err)
+
+	provider := NewStaticFileProvider(path)
+	require.Equal(t, "file:"+path, provider.Name())
+
+	quote, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, Rates{"USD": "1500.00000000", "GBP": "1875.00000000"}, quote.Rates)
+	require.Equal(t, 2026, quote.AsOf.Year())
+
+	_, err = NewStaticFileProvider(filepath.Join(t.TempDir(), "missing.json")).FetchRates(context.Background())
+	require.Error(t, err)
+}
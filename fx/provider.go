@@ -0,0 +1,74 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// A Provider supplies current exchange rates.
+type Provider interface {
+	// Name identifies the provider as the source of the rates it supplies.
+	Name() string
+
+	// FetchRates returns the current rates.
+	FetchRates(ctx context.Context) (Quote, error)
+}
+
+// A Quote is a set of rates published by a Provider.
+type Quote struct {
+	Rates Rates
+	AsOf  time.Time
+}
+
+// StaticFileProvider reads rates from a JSON file such as:
+//
+//	{"base": "USD", "as_of": "2026-10-18T00:00:00Z", "rates": {"NGN": "1540.25", "GBP": "0.79"}}
+//
+// where each rate is the units of a currency per one unit of base.
+type StaticFileProvider struct {
+	path string
+}
+
+// NewStaticFileProvider creates a StaticFileProvider reading path.
+func NewStaticFileProvider(path string) Provider {
+	return &StaticFileProvider{path: path}
+}
+
+type staticRatesFile struct {
+	Base  string            `json:"base"`
+	AsOf  time.Time         `json:"as_of"`
+	Rates map[string]string `json:"rates"`
+}
+
+// Name identifies the provider as the source of the rates it supplies.
+func (p *StaticFileProvider) Name() string {
+	return "file:" + p.path
+}
+
+// FetchRates reads the current rates from the file.
+func (p *StaticFileProvider) FetchRates(ctx context.Context) (Quote, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return Quote{}, fmt.Errorf("fx: failed to read rates file: %w", err)
+	}
+
+	var file staticRatesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Quote{}, fmt.Errorf("fx: failed to parse rates file: %w", err)
+	}
+
+	rates, err := Rebase(file.Base, file.Rates)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	asOf := file.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	return Quote{Rates: rates, AsOf: asOf}, nil
+}
@@ -0,0 +1,230 @@
+// Package imaging checks uploaded images and derives their thumbnails, each
+// both in the image's own format and as WebP.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	MaxSize   = 10 << 20   // largest upload, in bytes
+	MaxPixels = 40_000_000 // largest upload, in pixels, so small files can't decode to huge images
+)
+
+var (
+	ErrUnsupportedType = errors.New("imaging: image must be a JPEG, PNG or GIF")
+	ErrTooLarge        = errors.New("imaging: image is too large")
+)
+
+// A Size is a thumbnail size, fitting inside a square of MaxSide.
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// Thumbnails are the sizes every uploaded image is scaled down to.
+var Thumbnails = []Size{
+	{Name: "small", MaxSide: 320},
+	{Name: "medium", MaxSide: 960},
+}
+
+// A File is an encoded image.
+type File struct {
+	ContentType string
+	Ext         string // file extension, without the "."
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// A Thumbnail is an image scaled down to Size, in the image's own format and as WebP.
+type Thumbnail struct {
+	Size string
+	File File
+	WebP File
+}
+
+// An Image is an uploaded image and its thumbnails.
+type Image struct {
+	Original   File
+	Thumbnails []Thumbnail
+}
+
+var extensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// Process checks data is a JPEG, PNG or GIF image no larger than MaxSize and
+// MaxPixels, by its contents rather than what the client claims, and makes
+// its Thumbnails. Thumbnails of JPEGs are JPEGs; of PNGs and GIFs, PNGs.
+// Only the first frame of an animated GIF is kept in its thumbnails. The
+// Original is data stripped of its metadata, such as EXIF and its GPS
+// position, but otherwise as uploaded.
+func Process(data []byte) (Image, error) {
+	if len(data) > MaxSize {
+		return Image{}, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return Image{}, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("imaging: invalid image: %w", err)
+	}
+	if cfg.Width < 1 || cfg.Height < 1 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return Image{}, ErrTooLarge
+	}
+
+	src, err := decode(contentType, data)
+	if err != nil {
+		return Image{}, fmt.Errorf("imaging: invalid image: %w", err)
+	}
+	src = toRGBA(src)
+
+	original, err := stripMetadata(contentType, data)
+	if err != nil {
+		return Image{}, fmt.Errorf("imaging: invalid image: %w", err)
+	}
+
+	img := Image{Original: File{
+		ContentType: contentType,
+		Ext:         ext,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Data:        original,
+	}}
+
+	for _, size := range Thumbnails {
+		scaled := Fit(src, size.MaxSide)
+		bounds := scaled.Bounds()
+
+		var thumb Thumbnail
+		thumb.Size = size.Name
+
+		var buf bytes.Buffer
+		if contentType == "image/jpeg" {
+			err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: 85})
+			thumb.File = File{ContentType: "image/jpeg", Ext: "jpg"}
+		} else {
+			err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, scaled)
+			thumb.File = File{ContentType: "image/png", Ext: "png"}
+		}
+		if err != nil {
+			return Image{}, fmt.Errorf("imaging: failed to encode thumbnail: %w", err)
+		}
+		thumb.File.Width, thumb.File.Height, thumb.File.Data = bounds.Dx(), bounds.Dy(), buf.Bytes()
+
+		var webp bytes.Buffer
+		if err := EncodeWebP(&webp, scaled); err != nil {
+			return Image{}, err
+		}
+		thumb.WebP = File{
+			ContentType: "image/webp",
+			Ext:         "webp",
+			Width:       bounds.Dx(),
+			Height:      bounds.Dy(),
+			Data:        webp.Bytes(),
+		}
+
+		img.Thumbnails = append(img.Thumbnails, thumb)
+	}
+
+	return img, nil
+}
+
+func decode(contentType string, data []byte) (image.Image, error) {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		return png.Decode(bytes.NewReader(data))
+	default:
+		return gif.Decode(bytes.NewReader(data))
+	}
+}
+
+// Fit scales img down to fit inside a square of maxSide, keeping its aspect
+// ratio. An image that already fits isn't scaled up.
+func Fit(img image.Image, maxSide int) *image.NRGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			w, h = maxSide, maxInt(1, (h*maxSide+w/2)/w)
+		} else {
+			w, h = maxInt(1, (w*maxSide+h/2)/h), maxSide
+		}
+	}
+	return resize(img, w, h)
+}
+
+// resize scales img down to w by h with a box filter, averaging each block of
+// source pixels into one. Colours are averaged premultiplied by their alpha,
+// so transparent pixels don't bleed their colour into the edges of opaque ones.
+func resize(img image.Image, w, h int) *image.NRGBA {
+	src := toRGBA(img)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, maxInt((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, maxInt((x+1)*sw/w, x*sw/w+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			c := color.RGBA{
+				R: uint8((r + n/2) / n),
+				G: uint8((g + n/2) / n),
+				B: uint8((b + n/2) / n),
+				A: uint8((a + n/2) / n),
+			}
+			dst.SetNRGBA(x, y, color.NRGBAModel.Convert(c).(color.NRGBA))
+		}
+	}
+	return dst
+}
+
+// toRGBA converts img to an *image.RGBA at the origin, unless it is one already.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,225 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+func TestFit(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		width, height int
+		maxSide       int
+		wantW, wantH  int
+	}{
+		{"landscape", 1000, 500, 320, 320, 160},
+		{"portrait", 500, 1000, 320, 160, 320},
+		{"square", 640, 640, 320, 320, 320},
+		{"already fits", 200, 100, 320, 200, 100},
+		{"sliver", 10000, 3, 320, 320, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			img := image.NewNRGBA(image.Rect(0, 0, tc.width, tc.height))
+			got := Fit(img, tc.maxSide)
+			require.Equal(t, tc.wantW, got.Bounds().Dx())
+			require.Equal(t, tc.wantH, got.Bounds().Dy())
+		})
+	}
+}
+
+func TestResizeAverages(t *testing.T) {
+	// Left half red, right half transparent.
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		img.SetNRGBA(0, y, color.NRGBA{R: 255, A: 255})
+		img.SetNRGBA(1, y, color.NRGBA{R: 255, A: 255})
+		img.SetNRGBA(2, y, color.NRGBA{B: 255})
+		img.SetNRGBA(3, y, color.NRGBA{B: 255})
+	}
+
+	got := resize(img, 2, 1)
+	require.Equal(t, color.NRGBA{R: 255, A: 255}, got.NRGBAAt(0, 0))
+	require.Equal(t, uint8(0), got.NRGBAAt(1, 0).A)
+
+	got = resize(img, 1, 1)
+	// Half covered in red; the transparent blue doesn't tint it.
+	require.Equal(t, color.NRGBA{R: 255, A: 128}, got.NRGBAAt(0, 0))
+}
+
+func TestProcess(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1200, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 1200; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var jpg bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpg, src, nil))
+	img, err := Process(jpg.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, File{ContentType: "image/jpeg", Ext: "jpg", Width: 1200, Height: 600, Data: jpg.Bytes()}, img.Original)
+	require.Len(t, img.Thumbnails, 2)
+
+	small := img.Thumbnails[0]
+	require.Equal(t, "small", small.Size)
+	require.Equal(t, "image/jpeg", small.File.ContentType)
+	require.Equal(t, [2]int{320, 160}, [2]int{small.File.Width, small.File.Height})
+	decoded, err := jpeg.Decode(bytes.NewReader(small.File.Data))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 320, 160), decoded.Bounds())
+	require.Equal(t, "image/webp", small.WebP.ContentType)
+	require.Equal(t, "webp", small.WebP.Ext)
+
+	medium := img.Thumbnails[1]
+	require.Equal(t, "medium", medium.Size)
+	require.Equal(t, [2]int{960, 480}, [2]int{medium.WebP.Width, medium.WebP.Height})
+
+	var gifData bytes.Buffer
+	require.NoError(t, gif.Encode(&gifData, src, nil))
+	img, err = Process(gifData.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "gif", img.Original.Ext)
+	require.Equal(t, "image/png", img.Thumbnails[0].File.ContentType)
+	_, err = png.Decode(bytes.NewReader(img.Thumbnails[0].File.Data))
+	require.NoError(t, err)
+
+	_, err = Process([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	require.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = Process(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 16)...))
+	require.Error(t, err)
+
+	// A tiny PNG claiming to be far too many pixels is refused before it's decoded.
+	var huge bytes.Buffer
+	require.NoError(t, png.Encode(&huge, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := huge.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	_, err = Process(data)
+	require.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestEncodeWebP(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	noisy := image.NewNRGBA(image.Rect(0, 0, 37, 23))
+	rng.Read(noisy.Pix)
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 5), B: uint8(x ^ y), A: 255})
+		}
+	}
+
+	twoColours := image.NewNRGBA(image.Rect(0, 0, 5, 3))
+	for i := range twoColours.Pix {
+		twoColours.Pix[i] = 255
+	}
+	twoColours.SetNRGBA(2, 1, color.NRGBA{R: 1, G: 2, B: 3, A: 255})
+
+	for _, tc := range []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"noisy with alpha", noisy},
+		{"opaque gradient", gradient},
+		{"two colours", twoColours},
+		{"single pixel", image.NewNRGBA(image.Rect(0, 0, 1, 1))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, EncodeWebP(&buf, tc.img))
+
+			decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			got, ok := decoded.(*image.NRGBA)
+			require.True(t, ok)
+			require.Equal(t, tc.img.Bounds(), got.Bounds())
+			require.Equal(t, tc.img.Pix, got.Pix)
+		})
+	}
+
+	require.Error(t, EncodeWebP(&bytes.Buffer{}, image.NewNRGBA(image.Rect(0, 0, 1<<14+1, 1))))
+}
+
+func TestProcessStripsMetadata(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 40, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 6), G: uint8(y * 8), B: 64, A: 255})
+		}
+	}
+
+	insert := func(data []byte, at int, extra ...[]byte) []byte {
+		out := append([]byte{}, data[:at]...)
+		for _, e := range extra {
+			out = append(out, e...)
+		}
+		return append(out, data[at:]...)
+	}
+	pngChunk := func(typ, body string) []byte {
+		chunk := binary.BigEndian.AppendUint32(nil, uint32(len(body)))
+		chunk = append(chunk, typ+body...)
+		return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE([]byte(typ+body)))
+	}
+	exif := "Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08GPSLatitude 6.5244 N"
+
+	var jpg bytes.Buffer
+	require.NoError(t, jpeg.Encode(&jpg, src, nil))
+	app1 := append([]byte{0xff, 0xe1, 0, byte(len(exif) + 2)}, exif...)
+	com := append([]byte{0xff, 0xfe, 0, 9}, "Lagos!!"...)
+	dirtyJPEG := insert(jpg.Bytes(), 2, app1, com)
+
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, src))
+	dirtyPNG := insert(pngData.Bytes(), 33, pngChunk("eXIf", exif[6:]), pngChunk("tEXt", "Comment\x00Lagos"))
+
+	var gifData bytes.Buffer
+	require.NoError(t, gif.Encode(&gifData, src, nil))
+	gct := 13 + 3<<(gifData.Bytes()[10]&7+1)
+	comment := append([]byte{0x21, 0xfe, 5}, "Lagos\x00"...)
+	xmp := append([]byte{0x21, 0xff, 11}, "XMP DataXMP\x07GPS 6.5\x00"...)
+	dirtyGIF := insert(gifData.Bytes(), gct, comment, xmp)
+
+	for _, tc := range []struct {
+		name         string
+		clean, dirty []byte
+	}{
+		{"jpeg", jpg.Bytes(), dirtyJPEG},
+		{"png", pngData.Bytes(), dirtyPNG},
+		{"gif", gifData.Bytes(), dirtyGIF},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := Process(tc.dirty)
+			require.NoError(t, err)
+			require.Equal(t, tc.clean, img.Original.Data)
+			require.NotContains(t, string(img.Original.Data), "Lagos")
+			require.NotContains(t, string(img.Original.Data), "GPS")
+		})
+	}
+
+	// what decoding needs stays, as does an animation's loop count
+	var anim bytes.Buffer
+	frame := image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.Black, color.White})
+	require.NoError(t, gif.EncodeAll(&anim, &gif.GIF{Image: []*image.Paletted{frame, frame}, Delay: []int{10, 10}}))
+	img, err := Process(anim.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, anim.Bytes(), img.Original.Data)
+	decoded, err := gif.DecodeAll(bytes.NewReader(img.Original.Data))
+	require.NoError(t, err)
+	require.Len(t, decoded.Image, 2)
+}
@@ -0,0 +1,187 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformed = errors.New("malformed image")
+
+// stripMetadata returns data without the metadata a camera or editor may have
+// left in it, such as EXIF (with its GPS position), XMP and comments, which an
+// uploaded original shouldn't give away. What decoding the image needs, such as
+// its colour profile, is kept, and so is every frame of an animated GIF.
+func stripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	default:
+		return stripGIF(data)
+	}
+}
+
+// stripJPEG drops a JPEG's APPn segments, but for JFIF's APP0, the ICC
+// profile's APP2 and Adobe's APP14, and its comments.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, errMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	for i := 2; i < len(data); {
+		if data[i] != 0xff {
+			return nil, errMalformed
+		}
+		// markers may be padded with any number of 0xff
+		for i < len(data) && data[i] == 0xff {
+			i++
+		}
+		if i == len(data) {
+			return nil, errMalformed
+		}
+		marker := data[i]
+		i++
+
+		if marker == 0xd9 { // EOI
+			return append(out, 0xff, marker), nil
+		}
+		if i+2 > len(data) {
+			return nil, errMalformed
+		}
+		end := i + int(binary.BigEndian.Uint16(data[i:]))
+		if end > len(data) || end < i+2 {
+			return nil, errMalformed
+		}
+
+		isAPP := marker >= 0xe0 && marker <= 0xef
+		if (!isAPP || marker == 0xe0 || marker == 0xe2 || marker == 0xee) && marker != 0xfe {
+			out = append(out, 0xff, marker)
+			out = append(out, data[i:end]...)
+		}
+		i = end
+
+		if marker == 0xda { // SOS: the scan's entropy-coded data runs up to the next marker
+			start := i
+			for i < len(data) && !(data[i] == 0xff && i+1 < len(data) && data[i+1] != 0 && (data[i+1] < 0xd0 || data[i+1] > 0xd7)) {
+				i++
+			}
+			if i == len(data) {
+				return nil, errMalformed
+			}
+			out = append(out, data[start:i]...)
+		}
+	}
+	return nil, errMalformed
+}
+
+// pngMetadata are the PNG chunks stripPNG drops.
+var pngMetadata = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+// stripPNG drops a PNG's EXIF, text and modification time chunks.
+func stripPNG(data []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(data, []byte(signature)) {
+		return nil, errMalformed
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, signature...)
+	for i := len(signature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, errMalformed
+		}
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:]))
+		if end > len(data) || end < i+12 {
+			return nil, errMalformed
+		}
+
+		chunk := string(data[i+4 : i+8])
+		if !pngMetadata[chunk] {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+
+		if chunk == "IEND" {
+			return out, nil
+		}
+	}
+	return nil, errMalformed
+}
+
+// stripGIF drops a GIF's comments, and its application extensions but for the
+// ones that make an animation loop.
+func stripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 {
+		return nil, errMalformed
+	}
+
+	// the header, the logical screen descriptor and the global colour table
+	i := 13
+	if data[10]&0x80 != 0 {
+		i += 3 << (data[10]&7 + 1)
+	}
+	if i > len(data) {
+		return nil, errMalformed
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:i]...)
+
+	// subBlocks returns where the data sub-blocks starting at j end.
+	subBlocks := func(j int) (int, error) {
+		for j < len(data) {
+			n := int(data[j])
+			j += 1 + n
+			if n == 0 {
+				return j, nil
+			}
+		}
+		return 0, errMalformed
+	}
+
+	for i < len(data) {
+		start := i
+		switch data[i] {
+		case 0x2c: // image descriptor, local colour table and image data
+			if i+11 > len(data) {
+				return nil, errMalformed
+			}
+			j := i + 10
+			if data[i+9]&0x80 != 0 {
+				j += 3 << (data[i+9]&7 + 1)
+			}
+			end, err := subBlocks(j + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data[start:end]...)
+			i = end
+		case 0x21: // extension
+			if i+2 > len(data) {
+				return nil, errMalformed
+			}
+			label := data[i+1]
+			end, err := subBlocks(i + 2)
+			if err != nil {
+				return nil, err
+			}
+			keep := label != 0xfe
+			if label == 0xff {
+				app := data[i+2 : end]
+				keep = len(app) > 11 && (string(app[1:12]) == "NETSCAPE2.0" || string(app[1:12]) == "ANIMEXTS1.0")
+			}
+			if keep {
+				out = append(out, data[start:end]...)
+			}
+			i = end
+		case 0x3b: // trailer
+			return append(out, data[i]), nil
+		default:
+			return nil, errMalformed
+		}
+	}
+	return nil, errMalformed
+}
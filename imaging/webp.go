@@ -0,0 +1,306 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// EncodeWebP writes img to w as a lossless WebP (VP8L) image.
+//
+// The standard library can't encode WebP, so this is a plain VP8L encoder:
+// pixels are written as Huffman-coded literals, without VP8L's transforms,
+// colour cache or backward references. Its files are bigger than an
+// optimising encoder's but decode exactly to img in every browser.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > 1<<14 || height > 1<<14 {
+		return errors.New("imaging: WebP images must be 1 to 16384 pixels wide and high")
+	}
+
+	// Pixels, and how often each value of each channel occurs. The green code
+	// also covers VP8L's length prefixes and colour cache, which aren't used.
+	pixels := make([]color.NRGBA, 0, width*height)
+	var green [256 + 24]int
+	var red, blue, alpha [256]int
+	var distance [40]int
+	hasAlpha := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pixels = append(pixels, c)
+			green[c.G]++
+			red[c.R]++
+			blue[c.B]++
+			alpha[c.A]++
+			hasAlpha = hasAlpha || c.A != 0xff
+		}
+	}
+
+	var bw bitWriter
+	bw.write(0x2f, 8) // signature
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // version
+	bw.write(0, 1) // no transforms
+	bw.write(0, 1) // no colour cache
+	bw.write(0, 1) // a single group of prefix codes for the whole image
+
+	greenCode := bw.writePrefixCode(green[:])
+	redCode := bw.writePrefixCode(red[:])
+	blueCode := bw.writePrefixCode(blue[:])
+	alphaCode := bw.writePrefixCode(alpha[:])
+	bw.writePrefixCode(distance[:])
+
+	for _, c := range pixels {
+		greenCode.write(&bw, int(c.G))
+		redCode.write(&bw, int(c.R))
+		blueCode.write(&bw, int(c.B))
+		alphaCode.write(&bw, int(c.A))
+	}
+	bw.flush()
+
+	chunk := bw.buf
+	if len(chunk)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(chunk)))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(bw.buf)))
+
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("imaging: failed to write WebP image: %w", err)
+	}
+	if _, err := w.Write(chunk); err != nil {
+		return fmt.Errorf("imaging: failed to write WebP image: %w", err)
+	}
+	return nil
+}
+
+// bitWriter packs bits least significant first, as VP8L reads them.
+type bitWriter struct {
+	buf  []byte
+	bits uint64
+	n    uint
+}
+
+func (bw *bitWriter) write(v uint32, n uint) {
+	bw.bits |= uint64(v) << bw.n
+	bw.n += n
+	for bw.n >= 8 {
+		bw.buf = append(bw.buf, byte(bw.bits))
+		bw.bits >>= 8
+		bw.n -= 8
+	}
+}
+
+func (bw *bitWriter) flush() {
+	if bw.n > 0 {
+		bw.buf = append(bw.buf, byte(bw.bits))
+		bw.bits, bw.n = 0, 0
+	}
+}
+
+// A prefixCode is a canonical Huffman code, with each code's bits reversed so
+// they can be written least significant first.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func (c prefixCode) write(bw *bitWriter, symbol int) {
+	bw.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// The order code lengths of the code length code are written in.
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// writePrefixCode writes the prefix code of an alphabet with symbol
+// frequencies freq, and returns it.
+func (bw *bitWriter) writePrefixCode(freq []int) prefixCode {
+	var used []int
+	for symbol, f := range freq {
+		if f > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	// A code of one or two symbols below 256 is written as a "simple" code.
+	// A code of one symbol takes no bits at all.
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < 256) {
+		if len(used) == 0 {
+			used = []int{0}
+		}
+		bw.write(1, 1)
+		bw.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(used[0]), 8)
+		}
+		if len(used) == 2 {
+			bw.write(uint32(used[1]), 8)
+		}
+
+		lengths := make([]uint8, len(freq))
+		if len(used) == 2 {
+			lengths[used[0]], lengths[used[1]] = 1, 1
+		}
+		return prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+	}
+
+	// Otherwise each symbol's code length is written, itself coded with the
+	// code length code. Lengths are written one by one, without the repeat
+	// codes 16 to 18.
+	lengths := codeLengths(freq, 15)
+	var lengthFreq [19]int
+	for _, l := range lengths {
+		lengthFreq[l]++
+	}
+	lengthLengths := codeLengths(lengthFreq[:], 7)
+	lengthCode := prefixCode{lengths: lengthLengths, codes: canonicalCodes(lengthLengths)}
+
+	numLengths := 4
+	for i, symbol := range codeLengthOrder {
+		if lengthLengths[symbol] != 0 && i+1 > numLengths {
+			numLengths = i + 1
+		}
+	}
+
+	bw.write(0, 1)
+	bw.write(uint32(numLengths-4), 4)
+	for _, symbol := range codeLengthOrder[:numLengths] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+	bw.write(0, 1) // lengths of every symbol of the alphabet follow
+	for _, l := range lengths {
+		lengthCode.write(bw, int(l))
+	}
+
+	return prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+}
+
+// codeLengths returns the Huffman code lengths, of at most maxLength, of the
+// symbols with frequencies freq. Unused symbols get no code. If only one
+// symbol is used, a second is given a code too, since a prefix code of
+// VP8L's normal form must have at least two.
+func codeLengths(freq []int, maxLength int) []uint8 {
+	freq = append([]int(nil), freq...)
+	used := 0
+	for _, f := range freq {
+		if f > 0 {
+			used++
+		}
+	}
+	if used < 2 {
+		for symbol := range freq {
+			if freq[symbol] == 0 {
+				freq[symbol] = 1
+				used++
+				if used == 2 {
+					break
+				}
+			}
+		}
+	}
+
+	for {
+		lengths := huffmanLengths(freq)
+		longest := uint8(0)
+		for _, l := range lengths {
+			if l > longest {
+				longest = l
+			}
+		}
+		if int(longest) <= maxLength {
+			return lengths
+		}
+		// Too long: flatten the frequencies and try again. At worst every
+		// used symbol ends up equally frequent, giving a balanced tree.
+		for symbol, f := range freq {
+			if f > 0 {
+				freq[symbol] = f/2 + 1
+			}
+		}
+	}
+}
+
+// huffmanLengths returns the depths of the symbols with frequencies freq in
+// their Huffman tree.
+func huffmanLengths(freq []int) []uint8 {
+	type node struct {
+		weight  int
+		symbols []int
+	}
+
+	var nodes []node
+	for symbol, f := range freq {
+		if f > 0 {
+			nodes = append(nodes, node{weight: f, symbols: []int{symbol}})
+		}
+	}
+
+	lengths := make([]uint8, len(freq))
+	for len(nodes) > 1 {
+		sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].weight < nodes[j].weight })
+		a, b := nodes[0], nodes[1]
+		for _, symbol := range a.symbols {
+			lengths[symbol]++
+		}
+		for _, symbol := range b.symbols {
+			lengths[symbol]++
+		}
+		merged := node{weight: a.weight + b.weight, symbols: append(append([]int(nil), a.symbols...), b.symbols...)}
+		nodes = append([]node{merged}, nodes[2:]...)
+	}
+	return lengths
+}
+
+// canonicalCodes assigns the canonical Huffman codes of lengths, as DEFLATE
+// and VP8L do, and reverses their bits.
+func canonicalCodes(lengths []uint8) []uint32 {
+	var count [16]uint32
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+		}
+	}
+
+	var next [16]uint32
+	code := uint32(0)
+	for l := 1; l < 16; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c := next[l]
+		next[l]++
+
+		var reversed uint32
+		for i := uint8(0); i < l; i++ {
+			reversed = reversed<<1 | c&1
+			c >>= 1
+		}
+		codes[symbol] = reversed
+	}
+	return codes
+}
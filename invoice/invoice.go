@@ -0,0 +1,87 @@
+// Package invoice lays out the invoice a store issues for its part of a
+// completed transaction, and renders it as a PDF. Each store numbers its
+// invoices in a sequence of its own, with no gaps.
+package invoice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// A Line is an order on the invoice, at what the buyer paid for it.
+type Line struct {
+	OrderID      int64
+	Description  string
+	Quantity     int32
+	UnitPrice    money.Amount
+	Discount     money.Amount
+	DeliveryFee  money.Amount
+	TaxRate      string // e.g. "7.5" for 7.5%
+	TaxInclusive bool
+	Tax          money.Amount
+}
+
+// Subtotal is the line's price before discount, delivery and tax.
+func (l Line) Subtotal() money.Amount {
+	return l.UnitPrice.Mul(int64(l.Quantity))
+}
+
+// Total is what the buyer paid for the line. Inclusive tax is already part
+// of its price; exclusive tax is charged on top.
+func (l Line) Total() money.Amount {
+	total := l.Subtotal() - l.Discount + l.DeliveryFee
+	if !l.TaxInclusive {
+		total += l.Tax
+	}
+	return total
+}
+
+// An Invoice is a store's bill to a buyer for the orders of a transaction.
+type Invoice struct {
+	Number          string
+	IssuedAt        time.Time
+	Reference       string // the transaction's reference
+	OrderNumber     string
+	StoreName       string
+	BuyerName       string
+	BuyerEmail      string
+	ShippingAddress []string
+	PaymentMethod   string
+	Currency        string
+	Lines           []Line
+}
+
+// Totals sums an invoice's lines. Tax counts inclusive and exclusive tax,
+// but only exclusive tax adds to Total.
+type Totals struct {
+	Subtotal    money.Amount
+	Discount    money.Amount
+	DeliveryFee money.Amount
+	Tax         money.Amount
+	Total       money.Amount
+}
+
+// Totals sums the invoice's lines.
+func (inv Invoice) Totals() Totals {
+	var t Totals
+	for _, l := range inv.Lines {
+		t.Subtotal += l.Subtotal()
+		t.Discount += l.Discount
+		t.DeliveryFee += l.DeliveryFee
+		t.Tax += l.Tax
+		t.Total += l.Total()
+	}
+	return t
+}
+
+// Number formats the sequenceNumber-th invoice of a store.
+func Number(storeID, sequenceNumber int64) string {
+	return fmt.Sprintf("INV-%d-%06d", storeID, sequenceNumber)
+}
+
+// Filename is the name an invoice's PDF is served and attached under.
+func (inv Invoice) Filename() string {
+	return inv.Number + ".pdf"
+}
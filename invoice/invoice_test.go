@@ -0,0 +1,158 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func testInvoice(lines int) Invoice {
+	inv := Invoice{
+		Number:          Number(7, 42),
+		IssuedAt:        time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		Reference:       "ref-1",
+		OrderNumber:     "SH-00000001",
+		StoreName:       "Ada's (Fine) Goods",
+		BuyerName:       "Ada Obi",
+		BuyerEmail:      "ada@example.com",
+		ShippingAddress: []string{"1 Marina Road", "Lagos, LA", "NG"},
+		PaymentMethod:   "Instant Pay",
+		Currency:        "NGN",
+	}
+	for i := 0; i < lines; i++ {
+		inv.Lines = append(inv.Lines, Line{
+			OrderID:     int64(i + 1),
+			Description: fmt.Sprintf("Item %d", i+1),
+			Quantity:    2,
+			UnitPrice:   money.MustParse("100"),
+			DeliveryFee: money.MustParse("10"),
+		})
+	}
+	return inv
+}
+
+func TestLineTotal(t *testing.T) {
+	line := Line{
+		Quantity:    3,
+		UnitPrice:   money.MustParse("1000"),
+		Discount:    money.MustParse("300"),
+		DeliveryFee: money.MustParse("500"),
+		TaxRate:     "7.5",
+		Tax:         money.MustParse("202.50"),
+	}
+	require.Equal(t, money.MustParse("3000"), line.Subtotal())
+	require.Equal(t, money.MustParse("3402.50"), line.Total())
+
+	line.TaxInclusive = true
+	require.Equal(t, money.MustParse("3200"), line.Total())
+}
+
+func TestInvoiceTotals(t *testing.T) {
+	inv := testInvoice(2)
+	inv.Lines[0].Discount = money.MustParse("20")
+	inv.Lines[1].Tax = money.MustParse("15")
+	inv.Lines[1].TaxRate = "7.5"
+	inv.Lines = append(inv.Lines, Line{
+		Quantity:     1,
+		UnitPrice:    money.MustParse("107.50"),
+		TaxRate:      "7.5",
+		TaxInclusive: true,
+		Tax:          money.MustParse("7.50"),
+	})
+
+	require.Equal(t, Totals{
+		Subtotal:    money.MustParse("507.50"),
+		Discount:    money.MustParse("20"),
+		DeliveryFee: money.MustParse("20"),
+		Tax:         money.MustParse("22.50"),
+		Total:       money.MustParse("522.50"),
+	}, inv.Totals())
+}
+
+func TestNumber(t *testing.T) {
+	require.Equal(t, "INV-7-000042", Number(7, 42))
+	require.Equal(t, "INV-12-1234567", Number(12, 1234567))
+}
+
+// requireValidPDF checks pdf is a PDF whose cross-reference table points at
+// its objects, and returns its page count.
+func requireValidPDF(t *testing.T, pdf []byte) int {
+	t.Helper()
+
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, m)
+	xref, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	for _, stream := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(pdf, -1) {
+		length, err := strconv.Atoi(string(stream[1]))
+		require.NoError(t, err)
+		require.Len(t, stream[2], length)
+	}
+
+	m = regexp.MustCompile(`/Count (\d+)`).FindSubmatch(pdf)
+	require.NotNil(t, m)
+	pages, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	return pages
+}
+
+func TestRender(t *testing.T) {
+	inv := testInvoice(3)
+	inv.Lines[0].Tax = money.MustParse("15")
+	inv.Lines[0].TaxRate = "7.5"
+
+	pdf, err := Render(inv)
+	require.NoError(t, err)
+	require.Equal(t, 1, requireValidPDF(t, pdf))
+
+	require.Contains(t, string(pdf), "(INV-7-000042)")
+	require.Contains(t, string(pdf), `(Ada's \(Fine\) Goods)`)
+	require.Contains(t, string(pdf), "(Tax at 7.5%)")
+	require.Contains(t, string(pdf), "(NGN 645.00)")
+
+	_, err = Render(Invoice{})
+	require.Error(t, err)
+}
+
+func TestRenderPages(t *testing.T) {
+	pdf, err := Render(testInvoice(120))
+	require.NoError(t, err)
+
+	pages := requireValidPDF(t, pdf)
+	require.Greater(t, pages, 1)
+	require.Contains(t, string(pdf), fmt.Sprintf("(INV-7-000042  -  page %d of %d)", pages, pages))
+	require.Contains(t, string(pdf), "(Item 120)")
+}
+
+func TestEscape(t *testing.T) {
+	require.Equal(t, `a\(b\)\\c`, escape(`a(b)\c`))
+	require.Equal(t, `caf\351`, escape("café"))
+	require.Equal(t, "?100", escape("₦100"))
+}
+
+func TestFit(t *testing.T) {
+	require.Equal(t, "short", fit("short", 8, 100))
+
+	long := fit("a very long item description that runs on and on", 8, 60)
+	require.True(t, width(long, 8) <= 60)
+	require.Regexp(t, `\.\.\.$`, long)
+}
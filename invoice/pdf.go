@@ -0,0 +1,297 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// A4, in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+)
+
+// Fonts every page can use, by resource name. Both are standard PDF fonts, so
+// nothing needs embedding.
+const (
+	regular = "F1" // Helvetica
+	bold    = "F2" // Helvetica-Bold
+)
+
+// Right edges of the line item columns.
+const (
+	colQty      = 265.0
+	colUnit     = 325.0
+	colDiscount = 380.0
+	colDelivery = 435.0
+	colTax      = 490.0
+	colTotal    = pageWidth - margin
+)
+
+// Render lays inv out as a PDF, starting a new page whenever the lines run
+// past the bottom margin.
+func Render(inv Invoice) ([]byte, error) {
+	if inv.Number == "" {
+		return nil, fmt.Errorf("invoice: missing number")
+	}
+
+	d := &document{}
+	d.newPage()
+
+	d.text(bold, 20, margin, d.y, "INVOICE")
+	d.textRight(bold, 11, colTotal, d.y, inv.Number)
+	d.y -= 16
+	d.textRight(regular, 9, colTotal, d.y, "Issued "+inv.IssuedAt.UTC().Format("02 Jan 2006"))
+	d.y -= 30
+
+	d.text(bold, 12, margin, d.y, inv.StoreName)
+	d.y -= 24
+
+	d.text(bold, 9, margin, d.y, "Billed to")
+	d.text(bold, 9, 330, d.y, "Payment")
+	d.y -= 13
+
+	billedTo := append([]string{inv.BuyerName, inv.BuyerEmail}, inv.ShippingAddress...)
+	var payment []string
+	if inv.OrderNumber != "" {
+		payment = append(payment, "Order "+inv.OrderNumber)
+	}
+	payment = append(payment,
+		"Reference "+inv.Reference,
+		"Paid with "+inv.PaymentMethod,
+		"Amounts in "+inv.Currency,
+	)
+	top := d.y
+	for _, s := range billedTo {
+		if s == "" {
+			continue
+		}
+		d.text(regular, 9, margin, d.y, fit(s, 9, 260))
+		d.y -= 12
+	}
+	bottom := d.y
+	d.y = top
+	for _, s := range payment {
+		d.text(regular, 9, 330, d.y, fit(s, 9, colTotal-330))
+		d.y -= 12
+	}
+	if bottom < d.y {
+		d.y = bottom
+	}
+	d.y -= 18
+
+	d.tableHeader()
+
+	inclusive := false
+	for _, l := range inv.Lines {
+		rowHeight := 14.0
+		if l.Tax != money.Zero {
+			rowHeight += 10
+		}
+		if d.y-rowHeight < margin+20 {
+			d.newPage()
+			d.tableHeader()
+		}
+
+		d.text(regular, 8, margin, d.y, fit(l.Description, 8, colQty-margin-30))
+		d.textRight(regular, 8, colQty, d.y, fmt.Sprint(l.Quantity))
+		d.textRight(regular, 8, colUnit, d.y, l.UnitPrice.String())
+		d.textRight(regular, 8, colDiscount, d.y, l.Discount.String())
+		d.textRight(regular, 8, colDelivery, d.y, l.DeliveryFee.String())
+		d.textRight(regular, 8, colTax, d.y, l.Tax.String())
+		d.textRight(regular, 8, colTotal, d.y, l.Total().String())
+
+		if l.Tax != money.Zero {
+			d.y -= 10
+			note := fmt.Sprintf("Tax at %s%%", l.TaxRate)
+			if l.TaxInclusive {
+				note += ", included in the price"
+				inclusive = true
+			}
+			d.text(regular, 7, margin+8, d.y, note)
+		}
+		d.y -= 14
+	}
+
+	if d.y < margin+110 {
+		d.newPage()
+	}
+
+	d.line(colDelivery-40, d.y+6, colTotal, d.y+6)
+	d.y -= 8
+
+	totals := inv.Totals()
+	for _, row := range []struct {
+		label  string
+		amount money.Amount
+	}{
+		{"Subtotal", totals.Subtotal},
+		{"Discount", -totals.Discount},
+		{"Delivery", totals.DeliveryFee},
+		{"Tax", totals.Tax},
+	} {
+		d.text(regular, 9, colDelivery-40, d.y, row.label)
+		d.textRight(regular, 9, colTotal, d.y, row.amount.String())
+		d.y -= 13
+	}
+	d.y -= 3
+	d.text(bold, 10, colDelivery-40, d.y, "Total")
+	d.textRight(bold, 10, colTotal, d.y, inv.Currency+" "+totals.Total.String())
+	d.y -= 24
+
+	if inclusive {
+		d.text(regular, 7, margin, d.y, "Inclusive tax is part of the item's price, and isn't added to the total.")
+	}
+
+	for i := range d.pages {
+		d.pageFooter(i, fmt.Sprintf("%s  -  page %d of %d", inv.Number, i+1, len(d.pages)))
+	}
+
+	return d.bytes(), nil
+}
+
+// A document is a PDF being laid out, a page's content stream at a time.
+// Drawing goes on the current page, at y from the bottom for the next row.
+type document struct {
+	pages   []*bytes.Buffer
+	current int
+	y       float64
+}
+
+// newPage starts a page, and moves to its top.
+func (d *document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+	d.y = pageHeight - margin - 10
+}
+
+// tableHeader draws the line item column headings.
+func (d *document) tableHeader() {
+	d.text(bold, 8, margin, d.y, "Item")
+	d.textRight(bold, 8, colQty, d.y, "Qty")
+	d.textRight(bold, 8, colUnit, d.y, "Unit price")
+	d.textRight(bold, 8, colDiscount, d.y, "Discount")
+	d.textRight(bold, 8, colDelivery, d.y, "Delivery")
+	d.textRight(bold, 8, colTax, d.y, "Tax")
+	d.textRight(bold, 8, colTotal, d.y, "Total")
+	d.line(margin, d.y-4, colTotal, d.y-4)
+	d.y -= 16
+}
+
+// pageFooter writes s at the bottom of the i-th page.
+func (d *document) pageFooter(i int, s string) {
+	d.current = i
+	d.text(regular, 7, margin, margin-20, s)
+}
+
+// text writes s on the current page with its baseline starting at x, y.
+func (d *document) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(d.pages[d.current], "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(s))
+}
+
+// textRight writes s on the current page with its baseline ending at right, y.
+func (d *document) textRight(font string, size, right, y float64, s string) {
+	d.text(font, size, right-width(s, size), y, s)
+}
+
+// line strokes a thin line on the current page.
+func (d *document) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.pages[d.current], "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// bytes assembles the pages into a PDF file.
+func (d *document) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1 to 4 are the catalog, page tree and fonts; each page is then
+	// a page object followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, regular, bold, 6+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// escape encodes s for a PDF string in WinAnsiEncoding. Characters it can't
+// encode are replaced with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// width estimates how wide s is set in Helvetica at size. Digits, which
+// amounts are right-aligned by, are exact.
+func width(s string, size float64) float64 {
+	w := 0
+	for _, r := range s {
+		switch r {
+		case '.', ',', ' ', 'I', 'i', 'l', 'j', 't', 'f':
+			w += 278
+		case '-', '(', ')', 'r':
+			w += 333
+		case 'm', 'M', 'W':
+			w += 833
+		default:
+			w += 556
+		}
+	}
+	return float64(w) * size / 1000
+}
+
+// fit truncates s to fit in maxWidth at size.
+func fit(s string, size, maxWidth float64) string {
+	if width(s, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && width(string(runes)+"...", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
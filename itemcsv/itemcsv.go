@@ -0,0 +1,366 @@
+// Package itemcsv reads and writes a store's items as CSV, one item per row,
+// identified by its SKU.
+//
+// The columns are those of Header, in any order. Only sku, name,
+// description, price and category are required; image_urls holds the URLs
+// separated by "|".
+//
+// A spreadsheet runs a cell starting with "=", "+", "-" or "@" as a formula,
+// so such cells are written with a leading "'", which reading strips again.
+package itemcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// Limits on a file, keeping an import to what a worker gets through in one run.
+const (
+	MaxRows      = 10000
+	MaxSKULength = 64
+)
+
+// Header is the columns of an items CSV, in the order they're written.
+var Header = []string{
+	"sku",
+	"name",
+	"description",
+	"price",
+	"currency",
+	"discount_percentage",
+	"category",
+	"supply_quantity",
+	"status",
+	"weight_grams",
+	"cover_img_url",
+	"image_urls",
+}
+
+var required = []string{"sku", "name", "description", "price", "category"}
+
+var (
+	ErrInvalidHeader = errors.New("itemcsv: invalid header")
+	ErrTooManyRows   = fmt.Errorf("itemcsv: more than %d rows", MaxRows)
+)
+
+var currencyRx = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// A Row is an item as read from or written to a CSV.
+type Row struct {
+	// Line is where the row starts in the file, the header being line 1.
+	Line int
+
+	SKU                string
+	Name               string
+	Description        string
+	Price              string
+	Currency           string // empty for the platform's base currency
+	DiscountPercentage string
+	Category           string
+	SupplyQuantity     int64
+	Status             string
+	WeightGrams        int64
+	CoverImgURL        string
+	ImageURLs          []string
+}
+
+// A RowError is why a row of a CSV can't be imported.
+type RowError struct {
+	Line    int    `json:"line"`
+	SKU     string `json:"sku"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Read parses a CSV of items. Rows that aren't valid items are left out and
+// reported as RowErrors, so the rest can still be imported; a row repeating
+// an earlier row's SKU is one of them. It fails when the file isn't a CSV,
+// its header is invalid, or it has more than MaxRows rows.
+func Read(r io.Reader) ([]Row, []RowError, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: the file is empty", ErrInvalidHeader)
+		}
+		return nil, nil, err
+	}
+
+	columns, err := columnsOf(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []Row
+	var rowErrs []RowError
+	seen := make(map[string]int)
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(rows)+len(rowErrs) == MaxRows {
+			return nil, nil, ErrTooManyRows
+		}
+
+		line, _ := cr.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+
+		row, err := parseRow(columns, record)
+		row.Line = line
+		if err == nil {
+			if first, ok := seen[row.SKU]; ok {
+				err = fmt.Errorf("sku %q is repeated from line %d", row.SKU, first)
+			}
+		}
+		if err != nil {
+			rowErrs = append(rowErrs, RowError{Line: line, SKU: row.SKU, Message: err.Error()})
+			continue
+		}
+
+		seen[row.SKU] = line
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrs, nil
+}
+
+// columnsOf maps the columns of header to their index, matching names
+// whatever their case.
+func columnsOf(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(Header))
+	for _, name := range Header {
+		known[name] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// spreadsheets may start the file with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidHeader, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: column %q is repeated", ErrInvalidHeader, name)
+		}
+		columns[name] = i
+	}
+
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidHeader, name)
+		}
+	}
+
+	return columns, nil
+}
+
+// parseRow checks a record is a valid item, filling in the defaults of the
+// columns left blank.
+func parseRow(columns map[string]int, record []string) (Row, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return unescapeCell(strings.TrimSpace(record[i]))
+	}
+
+	row := Row{
+		SKU:                field("sku"),
+		Name:               field("name"),
+		Description:        field("description"),
+		Price:              field("price"),
+		Currency:           strings.ToUpper(field("currency")),
+		DiscountPercentage: field("discount_percentage"),
+		Category:           field("category"),
+		Status:             strings.ToUpper(field("status")),
+		CoverImgURL:        field("cover_img_url"),
+	}
+
+	for _, name := range required {
+		if field(name) == "" {
+			return row, fmt.Errorf("%s is required", name)
+		}
+	}
+
+	if len(row.SKU) > MaxSKULength {
+		return row, fmt.Errorf("sku is longer than %d characters", MaxSKULength)
+	}
+
+	price, err := money.Parse(row.Price)
+	if err != nil || price <= 0 {
+		return row, fmt.Errorf("price %q is not a positive amount", row.Price)
+	}
+
+	if row.Currency != "" && !currencyRx.MatchString(row.Currency) {
+		return row, fmt.Errorf("currency %q is not an ISO-4217 code", row.Currency)
+	}
+
+	if row.DiscountPercentage == "" {
+		row.DiscountPercentage = "0"
+	}
+	pct, ok := new(big.Rat).SetString(row.DiscountPercentage)
+	if !ok || pct.Sign() < 0 || pct.Cmp(big.NewRat(100, 1)) >= 0 {
+		return row, fmt.Errorf("discount_percentage %q is not from 0 up to 100", row.DiscountPercentage)
+	}
+
+	if row.SupplyQuantity, err = parseCount(field("supply_quantity")); err != nil {
+		return row, fmt.Errorf("supply_quantity %s", err)
+	}
+
+	if row.WeightGrams, err = parseCount(field("weight_grams")); err != nil {
+		return row, fmt.Errorf("weight_grams %s", err)
+	}
+
+	switch row.Status {
+	case "":
+		row.Status = "VISIBLE"
+	case "VISIBLE", "HIDDEN":
+	default:
+		return row, fmt.Errorf("status %q is not VISIBLE or HIDDEN", row.Status)
+	}
+
+	row.ImageURLs = []string{}
+	for _, url := range strings.Split(field("image_urls"), "|") {
+		if url = strings.TrimSpace(url); url != "" {
+			row.ImageURLs = append(row.ImageURLs, url)
+		}
+	}
+
+	return row, nil
+}
+
+// parseCount parses a non-negative whole number, zero when s is blank.
+func parseCount(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a whole number of at least 0", s)
+	}
+	return n, nil
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// A Writer writes items as CSV, starting with the header.
+type Writer struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewWriter returns a Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: csv.NewWriter(w)}
+}
+
+// Write writes row, after the header if it's the first.
+func (w *Writer) Write(row Row) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.w.Write(escapeRecord([]string{
+		row.SKU,
+		row.Name,
+		row.Description,
+		row.Price,
+		row.Currency,
+		row.DiscountPercentage,
+		row.Category,
+		strconv.FormatInt(row.SupplyQuantity, 10),
+		row.Status,
+		strconv.FormatInt(row.WeightGrams, 10),
+		row.CoverImgURL,
+		strings.Join(row.ImageURLs, "|"),
+	}))
+}
+
+// Flush writes any buffered rows, and the header if no row was written.
+func (w *Writer) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *Writer) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	return w.w.Write(Header)
+}
+
+// WriteErrors writes a report of the rows that couldn't be imported as CSV.
+func WriteErrors(w io.Writer, rowErrs []RowError) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"line", "sku", "error"}); err != nil {
+		return err
+	}
+
+	for _, rowErr := range rowErrs {
+		if err := cw.Write(escapeRecord([]string{strconv.Itoa(rowErr.Line), rowErr.SKU, rowErr.Message})); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formulaPrefixes are the characters a spreadsheet starts a formula with,
+// and the tab and carriage return that can hide one after them.
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeRecord prefixes each cell of record a spreadsheet would run as a
+// formula with "'", so it's shown as text.
+func escapeRecord(record []string) []string {
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+			record[i] = "'" + cell
+		}
+	}
+	return record
+}
+
+// unescapeCell strips the "'" escapeRecord prefixed cell with.
+func unescapeCell(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
@@ -0,0 +1,206 @@
+package itemcsv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	file := strings.Join([]string{
+		"SKU,Name,Description,Price,Category,image_urls,status",
+		"TEE-1,T-shirt,Cotton tee,5500,Clothing,https://a/1.png | https://a/2.png,",
+		",Mug,Ceramic mug,1200,Kitchen,,",
+		"",
+		"TEE-2,Hoodie,Fleece hoodie,-1,Clothing,,",
+		"TEE-1,Cap,Baseball cap,2000,Clothing,,hidden",
+		"CAP-1,Cap,Baseball cap,2000,Clothing,,hidden",
+	}, "\n")
+
+	rows, rowErrs, err := Read(strings.NewReader(file))
+	require.NoError(t, err)
+
+	require.Len(t, rows, 2)
+	require.Equal(t, Row{
+		Line:               2,
+		SKU:                "TEE-1",
+		Name:               "T-shirt",
+		Description:        "Cotton tee",
+		Price:              "5500",
+		DiscountPercentage: "0",
+		Category:           "Clothing",
+		Status:             "VISIBLE",
+		ImageURLs:          []string{"https://a/1.png", "https://a/2.png"},
+	}, rows[0])
+	require.Equal(t, "CAP-1", rows[1].SKU)
+	require.Equal(t, "HIDDEN", rows[1].Status)
+	require.Equal(t, 7, rows[1].Line)
+
+	require.Len(t, rowErrs, 3)
+	require.Equal(t, 3, rowErrs[0].Line)
+	require.Contains(t, rowErrs[0].Message, "sku is required")
+	require.Equal(t, "TEE-2", rowErrs[1].SKU)
+	require.Contains(t, rowErrs[1].Message, "price")
+	require.Equal(t, 6, rowErrs[2].Line)
+	require.Contains(t, rowErrs[2].Message, "repeated from line 2")
+}
+
+func TestReadRow(t *testing.T) {
+	header := "sku,name,description,price,category,currency,discount_percentage,supply_quantity,weight_grams,status\n"
+
+	testCases := []struct {
+		name string
+		row  string
+		err  string
+	}{
+		{name: "Valid", row: "A,B,C,10.50,D,usd,12.5,3,250,VISIBLE"},
+		{name: "BadCurrency", row: "A,B,C,10,D,dollars,,,,", err: "currency"},
+		{name: "DiscountTooHigh", row: "A,B,C,10,D,,100,,,", err: "discount_percentage"},
+		{name: "NegativeSupply", row: "A,B,C,10,D,,,-1,,", err: "supply_quantity"},
+		{name: "FractionalWeight", row: "A,B,C,10,D,,,,1.5,", err: "weight_grams"},
+		{name: "BadStatus", row: "A,B,C,10,D,,,,,ARCHIVED", err: "status"},
+		{name: "LongSKU", row: strings.Repeat("A", MaxSKULength+1) + ",B,C,10,D,,,,,", err: "sku"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, rowErrs, err := Read(strings.NewReader(header + tc.row))
+			require.NoError(t, err)
+
+			if tc.err == "" {
+				require.Empty(t, rowErrs)
+				require.Len(t, rows, 1)
+				return
+			}
+			require.Empty(t, rows)
+			require.Len(t, rowErrs, 1)
+			require.Contains(t, rowErrs[0].Message, tc.err)
+		})
+	}
+
+	rows, _, err := Read(strings.NewReader(header + "A,B,C,10.50,D,usd,12.5,3,250,VISIBLE"))
+	require.NoError(t, err)
+	require.Equal(t, "USD", rows[0].Currency)
+	require.Equal(t, int64(3), rows[0].SupplyQuantity)
+	require.Equal(t, int64(250), rows[0].WeightGrams)
+}
+
+func TestReadHeader(t *testing.T) {
+	testCases := []struct {
+		name string
+		file string
+	}{
+		{name: "Empty", file: ""},
+		{name: "UnknownColumn", file: "sku,name,description,price,category,colour\n"},
+		{name: "RepeatedColumn", file: "sku,name,description,price,category,SKU\n"},
+		{name: "MissingColumn", file: "sku,name,price,category\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := Read(strings.NewReader(tc.file))
+			require.ErrorIs(t, err, ErrInvalidHeader)
+		})
+	}
+
+	// a byte order mark is ignored
+	_, _, err := Read(strings.NewReader("\ufeffsku,name,description,price,category\n"))
+	require.NoError(t, err)
+}
+
+func TestReadTooManyRows(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("sku,name,description,price,category\n")
+	for i := 0; i <= MaxRows; i++ {
+		b.WriteString("A,B,C,1,D\n")
+	}
+
+	_, _, err := Read(strings.NewReader(b.String()))
+	require.ErrorIs(t, err, ErrTooManyRows)
+}
+
+func TestWriteRead(t *testing.T) {
+	row := Row{
+		Line:               2,
+		SKU:                "TEE-1",
+		Name:               "T-shirt, \"classic\"",
+		Description:        "Cotton tee",
+		Price:              "5500.00",
+		Currency:           "NGN",
+		DiscountPercentage: "10.0000",
+		Category:           "Clothing",
+		SupplyQuantity:     12,
+		Status:             "HIDDEN",
+		WeightGrams:        180,
+		CoverImgURL:        "https://a/cover.png",
+		ImageURLs:          []string{"https://a/1.png", "https://a/2.png"},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.Write(row))
+	require.NoError(t, w.Flush())
+
+	rows, rowErrs, err := Read(&buf)
+	require.NoError(t, err)
+	require.Empty(t, rowErrs)
+	require.Equal(t, []Row{row}, rows)
+
+	// cells a spreadsheet would run as formulas are written as text
+	row.Name = "=HYPERLINK(\"https://evil.example\",\"click\")"
+	row.Description = "@SUM(1+1)"
+	row.Category = "+Clothing"
+	row.CoverImgURL = "-https://a/cover.png"
+
+	buf.Reset()
+	w = NewWriter(&buf)
+	require.NoError(t, w.Write(row))
+	require.NoError(t, w.Flush())
+	require.Contains(t, buf.String(), `,"'=HYPERLINK(""https://evil.example"",""click"")",'@SUM(1+1),`)
+	require.Contains(t, buf.String(), ",'+Clothing,")
+	require.Contains(t, buf.String(), ",'-https://a/cover.png,")
+
+	rows, rowErrs, err = Read(&buf)
+	require.NoError(t, err)
+	require.Empty(t, rowErrs)
+	require.Equal(t, []Row{row}, rows)
+
+	// so are ones a tab or carriage return hides a formula behind
+	row.Name = "\t=1+1"
+	row.Description = "\r=HYPERLINK(\"https://evil.example\")"
+
+	buf.Reset()
+	w = NewWriter(&buf)
+	require.NoError(t, w.Write(row))
+	require.NoError(t, w.Flush())
+	require.Contains(t, buf.String(), ",'\t=1+1,")
+	require.Contains(t, buf.String(), ",\"'\r=HYPERLINK(\"\"https://evil.example\"\")\",")
+
+	rows, rowErrs, err = Read(&buf)
+	require.NoError(t, err)
+	require.Empty(t, rowErrs)
+	require.Equal(t, []Row{row}, rows)
+
+	// an empty export is just the header
+	buf.Reset()
+	require.NoError(t, NewWriter(&buf).Flush())
+	require.Equal(t, strings.Join(Header, ",")+"\n", buf.String())
+}
+
+func TestWriteErrors(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteErrors(&buf, []RowError{
+		{Line: 3, SKU: "TEE-2", Message: `price "-1" is not a positive amount`},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "line,sku,error\n3,TEE-2,\"price \"\"-1\"\" is not a positive amount\"\n", buf.String())
+
+	buf.Reset()
+	err = WriteErrors(&buf, []RowError{
+		{Line: 4, SKU: "=1+1", Message: "sku is repeated"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "line,sku,error\n4,'=1+1,sku is repeated\n", buf.String())
+}
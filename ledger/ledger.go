@@ -0,0 +1,199 @@
+// Package ledger describes the double-entry journal every money movement
+// between buyers, stores and the platform is recorded in.
+//
+// A journal entry is a set of postings that sum to zero. Debits are
+// positive amounts and credits negative, so an account's balance is the
+// sum of its postings, negated for credit-normal accounts.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// ErrUnbalanced is returned for a journal entry whose postings don't sum to zero.
+var ErrUnbalanced = errors.New("ledger: journal entry is unbalanced")
+
+// Kinds of journal entry.
+const (
+	KindOpeningBalance = "OPENING_BALANCE"
+	KindCapture        = "CAPTURE"
+	KindProviderFee    = "PROVIDER_FEE"
+	KindEscrowHold     = "ESCROW_HOLD"
+	KindCommission     = "COMMISSION"
+	KindRelease        = "RELEASE"
+	KindRefund         = "REFUND"
+	KindPayoutRequest  = "PAYOUT_REQUEST"
+	KindPayoutCancel   = "PAYOUT_CANCEL"
+	KindPayout         = "PAYOUT"
+	KindGiftCardSale   = "GIFT_CARD_SALE"
+)
+
+// An AccountType decides which side of the ledger increases an account.
+type AccountType string
+
+// Account types.
+const (
+	Asset     AccountType = "ASSET"
+	Liability AccountType = "LIABILITY"
+	Equity    AccountType = "EQUITY"
+	Revenue   AccountType = "REVENUE"
+	Expense   AccountType = "EXPENSE"
+)
+
+// DebitNormal reports whether debits increase accounts of type t.
+func (t AccountType) DebitNormal() bool {
+	return t == Asset || t == Expense
+}
+
+// Balance converts the sum of an account's postings into its balance.
+func (t AccountType) Balance(sum money.Amount) money.Amount {
+	if t.DebitNormal() {
+		return sum
+	}
+	return -sum
+}
+
+// An Account is a ledger account, identified by its code. StoreID is 0
+// for the platform's own accounts.
+type Account struct {
+	Code    string
+	Type    AccountType
+	StoreID int64
+}
+
+// ProviderCash is the money held with a payment provider (PAYSTACK, NEAR_WALLET).
+func ProviderCash(provider string) Account {
+	return Account{Code: "platform:cash:" + provider, Type: Asset}
+}
+
+// CustomerPayments holds captured payments until they are allocated to stores.
+func CustomerPayments() Account {
+	return Account{Code: "platform:customer_payments", Type: Liability}
+}
+
+// ProviderFees is what payment providers charge the platform.
+func ProviderFees() Account {
+	return Account{Code: "platform:provider_fees", Type: Expense}
+}
+
+// Commission is what the platform earns from sales.
+func Commission() Account {
+	return Account{Code: "platform:commission", Type: Revenue}
+}
+
+// GiftCards holds what buyers paid for gift cards until the cards are spent.
+func GiftCards() Account {
+	return Account{Code: "platform:gift_cards", Type: Liability}
+}
+
+// StoreCredit is what the platform owes buyers as store credit.
+func StoreCredit() Account {
+	return Account{Code: "platform:store_credit", Type: Liability}
+}
+
+// OpeningBalances offsets balances that existed before the ledger.
+func OpeningBalances() Account {
+	return Account{Code: "platform:opening_balances", Type: Equity}
+}
+
+// StorePending holds a store's funds for orders not yet delivered.
+// accountType is FIAT or CRYPTO.
+func StorePending(storeID int64, accountType string) Account {
+	return storeAccount(storeID, "pending", accountType)
+}
+
+// StoreAvailable holds a store's funds it can withdraw.
+func StoreAvailable(storeID int64, accountType string) Account {
+	return storeAccount(storeID, "available", accountType)
+}
+
+// StoreWithdrawing holds a store's funds requested for a payout.
+func StoreWithdrawing(storeID int64, accountType string) Account {
+	return storeAccount(storeID, "withdrawing", accountType)
+}
+
+func storeAccount(storeID int64, bucket, accountType string) Account {
+	return Account{
+		Code:    fmt.Sprintf("store:%d:%s:%s", storeID, bucket, accountType),
+		Type:    Liability,
+		StoreID: storeID,
+	}
+}
+
+// A Posting debits (positive Amount) or credits (negative Amount) an account.
+type Posting struct {
+	Account Account
+	Amount  money.Amount
+}
+
+// Debit creates a posting debiting amount to account.
+func Debit(account Account, amount money.Amount) Posting {
+	return Posting{Account: account, Amount: amount}
+}
+
+// Credit creates a posting crediting amount to account.
+func Credit(account Account, amount money.Amount) Posting {
+	return Posting{Account: account, Amount: -amount}
+}
+
+// An Entry is a journal entry. Reference ties it to what caused it,
+// such as a transaction's provider_tx_ref_id or "order:42".
+type Entry struct {
+	Kind        string
+	Reference   string
+	Description string
+	Postings    []Posting
+}
+
+// Transfer creates an entry moving amount out of from and into to, where
+// both are accounts of the same type.
+func Transfer(kind, reference string, from, to Account, amount money.Amount) Entry {
+	out, in := Credit(from, amount), Debit(to, amount)
+	if !from.Type.DebitNormal() {
+		out, in = Debit(from, amount), Credit(to, amount)
+	}
+	return Entry{
+		Kind:      kind,
+		Reference: reference,
+		Postings:  []Posting{out, in},
+	}
+}
+
+// Validate checks the entry has postings, none of them zero, and that they balance.
+func (e Entry) Validate() error {
+	if e.Kind == "" {
+		return errors.New("ledger: journal entry has no kind")
+	}
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("ledger: %s entry needs at least two postings", e.Kind)
+	}
+
+	var sum money.Amount
+	for _, p := range e.Postings {
+		if p.Amount == money.Zero {
+			return fmt.Errorf("ledger: %s entry posts zero to %s", e.Kind, p.Account.Code)
+		}
+		sum += p.Amount
+	}
+	if sum != money.Zero {
+		return fmt.Errorf("%w: %s entry is off by %s", ErrUnbalanced, e.Kind, sum)
+	}
+
+	return nil
+}
+
+// Compact drops zero postings, so optional legs (a zero fee) can be built
+// unconditionally. It reports whether anything is left to record.
+func (e *Entry) Compact() bool {
+	postings := e.Postings[:0]
+	for _, p := range e.Postings {
+		if p.Amount != money.Zero {
+			postings = append(postings, p)
+		}
+	}
+	e.Postings = postings
+	return len(postings) > 0
+}
@@ -0,0 +1,108 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransfer(t *testing.T) {
+	amount := money.MustParse("150.50")
+
+	release := Transfer(KindRelease, "order:1", StorePending(1, "FIAT"), StoreAvailable(1, "FIAT"), amount)
+	require.NoError(t, release.Validate())
+	require.Equal(t, []Posting{
+		{Account: StorePending(1, "FIAT"), Amount: amount},
+		{Account: StoreAvailable(1, "FIAT"), Amount: -amount},
+	}, release.Postings)
+
+	// the pending balance drops, the available balance grows
+	require.Equal(t, -amount, Liability.Balance(release.Postings[0].Amount))
+	require.Equal(t, amount, Liability.Balance(release.Postings[1].Amount))
+
+	payout := Transfer(KindPayout, "withdrawal:1", ProviderCash("PAYSTACK"), ProviderCash("NEAR_WALLET"), amount)
+	require.NoError(t, payout.Validate())
+	require.Equal(t, -amount, Asset.Balance(payout.Postings[0].Amount))
+	require.Equal(t, amount, Asset.Balance(payout.Postings[1].Amount))
+}
+
+func TestValidate(t *testing.T) {
+	ten := money.MustParse("10")
+
+	testCases := []struct {
+		name  string
+		entry Entry
+		ok    bool
+	}{
+		{
+			name: "balanced",
+			entry: Entry{Kind: KindCapture, Postings: []Posting{
+				Debit(ProviderCash("PAYSTACK"), ten),
+				Credit(StorePending(1, "FIAT"), money.MustParse("7.5")),
+				Credit(StorePending(2, "FIAT"), money.MustParse("2.5")),
+			}},
+			ok: true,
+		},
+		{
+			name: "unbalanced",
+			entry: Entry{Kind: KindCapture, Postings: []Posting{
+				Debit(ProviderCash("PAYSTACK"), ten),
+				Credit(CustomerPayments(), money.MustParse("9.99")),
+			}},
+		},
+		{
+			name:  "single posting",
+			entry: Entry{Kind: KindCapture, Postings: []Posting{Debit(ProviderCash("PAYSTACK"), money.Zero)}},
+		},
+		{
+			name: "zero posting",
+			entry: Entry{Kind: KindCapture, Postings: []Posting{
+				Debit(ProviderCash("PAYSTACK"), ten),
+				Credit(CustomerPayments(), ten),
+				Credit(ProviderFees(), money.Zero),
+			}},
+		},
+		{
+			name: "no kind",
+			entry: Entry{Postings: []Posting{
+				Debit(ProviderCash("PAYSTACK"), ten),
+				Credit(CustomerPayments(), ten),
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.entry.Validate()
+		if tc.ok {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+		}
+	}
+}
+
+func TestCompact(t *testing.T) {
+	fee := Entry{Kind: KindProviderFee, Postings: []Posting{
+		Debit(ProviderFees(), money.Zero),
+		Credit(ProviderCash("PAYSTACK"), money.Zero),
+	}}
+	require.False(t, fee.Compact())
+
+	hold := Entry{Kind: KindEscrowHold, Postings: []Posting{
+		Debit(CustomerPayments(), money.MustParse("5")),
+		Credit(StorePending(1, "FIAT"), money.MustParse("5")),
+		Credit(StorePending(2, "FIAT"), money.Zero),
+	}}
+	require.True(t, hold.Compact())
+	require.Len(t, hold.Postings, 2)
+	require.NoError(t, hold.Validate())
+}
+
+func TestStoreAccountCodes(t *testing.T) {
+	require.Equal(t, "store:7:pending:CRYPTO", StorePending(7, "CRYPTO").Code)
+	require.Equal(t, "store:7:available:FIAT", StoreAvailable(7, "FIAT").Code)
+	require.Equal(t, "store:7:withdrawing:FIAT", StoreWithdrawing(7, "FIAT").Code)
+	require.Equal(t, int64(7), StoreWithdrawing(7, "FIAT").StoreID)
+	require.Equal(t, int64(0), Commission().StoreID)
+}
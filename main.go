@@ -10,10 +10,13 @@ import (
 	"time"
 
 	"github.com/OCD-Labs/store-hub/api"
+	"github.com/OCD-Labs/store-hub/blob"
 	"github.com/OCD-Labs/store-hub/cache"
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/fx"
 	"github.com/OCD-Labs/store-hub/mailer"
 	"github.com/OCD-Labs/store-hub/near"
+	"github.com/OCD-Labs/store-hub/payment"
 	"github.com/OCD-Labs/store-hub/token"
 	"github.com/OCD-Labs/store-hub/util"
 	"github.com/OCD-Labs/store-hub/worker"
@@ -43,13 +46,6 @@ func main() {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}).With().Caller().Logger()
 	}
 
-	go func() {
-		if err := setupNEAR(configs); err != nil {
-			log.Fatal().Err(err).Msg("failed setup NEAR")
-		}
-		log.Info().Msg("NEAR setup completed")
-	}()
-
 	log.Info().Msg("connecting to DB")
 	dbConn, err := sql.Open(configs.DBDriver, configs.DBSource)
 	if err != nil {
@@ -81,22 +77,56 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to get subcontent from swaggerDocs")
 	}
 
-	app, err := api.NewStoreHub(configs, log.Logger, cache, dbStore, taskDistributor, tokenMaker, swaggerFiles)
+	paymentProvider := payment.NewPaystackClient(configs.PaystackBaseURL, configs.PaystackSecretKey)
+
+	nearRPCURL := configs.NEARRPCURL
+	if nearRPCURL == "" {
+		nearRPCURL = near.RPCURLForNetwork(configs.NEARNetwork)
+	}
+	nearRPC := near.NewRPCClient(nearRPCURL)
+
+	fxProvider := fx.NewStaticFileProvider(configs.FXRatesFile)
+
+	var blobStore blob.Storage
+	switch configs.StorageDriver {
+	case "s3":
+		blobStore = blob.NewS3Storage(blob.S3Config{
+			Endpoint:        configs.S3Endpoint,
+			Region:          configs.S3Region,
+			Bucket:          configs.S3Bucket,
+			AccessKeyID:     configs.S3AccessKeyID,
+			SecretAccessKey: configs.S3SecretAccessKey,
+			PublicURL:       configs.S3PublicURL,
+			ForcePathStyle:  configs.S3ForcePathStyle,
+		})
+	case "local", "":
+		blobStore = blob.NewLocalStorage(configs.StorageLocalDir, configs.StoragePublicURL)
+	default:
+		log.Fatal().Str("driver", configs.StorageDriver).Msg("unknown storage driver")
+	}
+
+	app, err := api.NewStoreHub(configs, log.Logger, cache, dbStore, taskDistributor, tokenMaker, paymentProvider, nearRPC, fxProvider, blobStore, swaggerFiles)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to initialise application")
 	}
 
+	nearAccount, err := near.NewAccount(nearRPC, configs.NEARAccountID, configs.NEARPrivKey)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load NEAR master account")
+	}
+
 	log.Info().Msg("starting redis server")
-	go runTaskProcessor(configs, redisOpt, dbStore, tokenMaker)
+	go runTaskProcessor(configs, redisOpt, dbStore, cache, tokenMaker, nearAccount, paymentProvider)
+	go runTaskScheduler(configs, redisOpt)
 
 	if err = app.Start(); err != nil {
 		log.Fatal().Err(err).Msg("failed to start server")
 	}
 }
 
-func runTaskProcessor(config util.Configs, redisOpt asynq.RedisClientOpt, store db.StoreTx, tokenMaker token.Maker) {
+func runTaskProcessor(config util.Configs, redisOpt asynq.RedisClientOpt, store db.StoreTx, cache cache.Cache, tokenMaker token.Maker, nearAccount *near.Account, paymentProvider payment.Provider) {
 	mailer := mailer.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, config, tokenMaker)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, cache, mailer, config, tokenMaker, nearAccount, paymentProvider)
 	log.Info().Msg("starting task processor")
 	err := taskProcessor.Start()
 	if err != nil {
@@ -104,6 +134,18 @@ func runTaskProcessor(config util.Configs, redisOpt asynq.RedisClientOpt, store
 	}
 }
 
+func runTaskScheduler(config util.Configs, redisOpt asynq.RedisClientOpt) {
+	taskScheduler, err := worker.NewRedisTaskScheduler(redisOpt, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to register periodic tasks")
+	}
+	log.Info().Msg("starting task scheduler")
+	err = taskScheduler.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start task scheduler")
+	}
+}
+
 func runDBMigrations(migrationURL string, dbSource string, configs util.Configs) {
 	migration, err := migrate.New(migrationURL, dbSource)
 	if err != nil {
@@ -119,19 +161,3 @@ func runDBMigrations(migrationURL string, dbSource string, configs util.Configs)
 	v, d, err := migration.Version()
 	log.Info().Msg(fmt.Sprintf(`db migrated successfully; version=%d, dirty=%v, err=%v`, v, d, err))
 }
-
-func setupNEAR(configs util.Configs) (err error) {
-	if util.CommandExists("npm") {
-		if err = near.InstallNearCLI(); err != nil {
-			return err
-		}
-
-		if err = near.SetupNearMasterAccount(configs.NEARNetwork, configs.NEARAccountID, configs.NEARPubKey, configs.NEARPrivKey); err != nil {
-			return err
-		}
-	} else {
-		return fmt.Errorf("need npm to setup NEAR")
-	}
-
-	return nil
-}
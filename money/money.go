@@ -0,0 +1,144 @@
+// Package money provides helpers for working with the NUMERIC
+// amounts stored in the database.
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// An Amount is a monetary value in minor units (kobo, cents).
+type Amount int64
+
+// Zero is the zero Amount.
+const Zero Amount = 0
+
+// Parse converts a decimal string (as returned for NUMERIC columns)
+// into an Amount, rounding half away from zero to two decimal places.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Zero, fmt.Errorf("money: empty amount")
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("money: invalid amount %q", s)
+	}
+
+	return fromRat(r.Mul(r, big.NewRat(100, 1))), nil
+}
+
+// MustParse is like Parse but panics if s is not a valid amount.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// FromMinor creates an Amount from minor units.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Minor returns the amount in minor units.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// String formats the amount with two decimal places, the way
+// NUMERIC(_, 2) columns expect it.
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// MarshalJSON encodes the amount as a decimal string, matching
+// how NUMERIC columns are returned to clients.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes a decimal string or number into the amount.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Mul multiplies the amount by a whole quantity.
+func (a Amount) Mul(qty int64) Amount {
+	return a * Amount(qty)
+}
+
+// Percent returns pct percent of the amount, where pct is a
+// decimal string such as "7.5".
+func (a Amount) Percent(pct string) (Amount, error) {
+	p, ok := new(big.Rat).SetString(strings.TrimSpace(pct))
+	if !ok {
+		return Zero, fmt.Errorf("money: invalid percentage %q", pct)
+	}
+
+	r := new(big.Rat).SetInt64(int64(a))
+	r.Mul(r, p)
+	r.Quo(r, big.NewRat(100, 1))
+
+	return fromRat(r), nil
+}
+
+// Scale multiplies the amount by rate, a decimal or fraction string such
+// as "1540.25" or "1/1540", rounding half up to minor units.
+func (a Amount) Scale(rate string) (Amount, error) {
+	q, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok {
+		return Zero, fmt.Errorf("money: invalid rate %q", rate)
+	}
+
+	r := new(big.Rat).SetInt64(int64(a))
+	return fromRat(r.Mul(r, q)), nil
+}
+
+// Sum adds up the given amounts.
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// fromRat rounds r (in minor units) half away from zero.
+func fromRat(r *big.Rat) Amount {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+
+	neg := num.Sign() < 0
+	num.Abs(num)
+
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if m.Mul(m, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if neg {
+		q.Neg(q)
+	}
+
+	return Amount(q.Int64())
+}
@@ -0,0 +1,93 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want Amount
+	}{
+		{"0", 0},
+		{"1500", 150000},
+		{"1500.5", 150050},
+		{"1500.50", 150050},
+		{"0.005", 1},
+		{"0.004", 0},
+		{"-12.345", -1235},
+		{" 99.99 ", 9999},
+	}
+
+	for _, tc := range testCases {
+		got, err := Parse(tc.in)
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.want, got, tc.in)
+	}
+
+	_, err := Parse("")
+	require.Error(t, err)
+
+	_, err = Parse("abc")
+	require.Error(t, err)
+}
+
+func TestAmountString(t *testing.T) {
+	require.Equal(t, "0.00", Zero.String())
+	require.Equal(t, "1500.05", Amount(150005).String())
+	require.Equal(t, "-0.50", Amount(-50).String())
+}
+
+func TestAmountPercent(t *testing.T) {
+	a := MustParse("1000.00")
+
+	p, err := a.Percent("7.5")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("75.00"), p)
+
+	p, err = MustParse("0.15").Percent("50")
+	require.NoError(t, err)
+	require.Equal(t, Amount(8), p)
+
+	_, err = a.Percent("ten")
+	require.Error(t, err)
+}
+
+func TestAmountScale(t *testing.T) {
+	a, err := MustParse("10.00").Scale("1540.25")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("15402.50"), a)
+
+	a, err = MustParse("1000.00").Scale("1/1540")
+	require.NoError(t, err)
+	require.Equal(t, MustParse("0.65"), a)
+
+	_, err = a.Scale("rate")
+	require.Error(t, err)
+}
+
+func TestAmountMulAndSum(t *testing.T) {
+	require.Equal(t, MustParse("45.00"), MustParse("15.00").Mul(3))
+	require.Equal(t, MustParse("10.50"), Sum(MustParse("10.00"), MustParse("0.50")))
+}
+
+func TestAmountJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Total Amount `json:"total"`
+	}{MustParse("1500.5")})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"total":"1500.50"}`, string(data))
+
+	var got struct {
+		A Amount `json:"a"`
+		B Amount `json:"b"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"a":"12.30","b":7.5}`), &got))
+	require.Equal(t, Amount(1230), got.A)
+	require.Equal(t, Amount(750), got.B)
+
+	require.Error(t, json.Unmarshal([]byte(`{"a":"abc"}`), &got))
+}
@@ -0,0 +1,173 @@
+// Package near talks to the NEAR blockchain over JSON-RPC, and signs
+// transactions for the StoreHub master account.
+package near
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// DefaultFunctionCallGas is the gas attached to a function call when none is given (30 TGas).
+const DefaultFunctionCallGas uint64 = 30_000_000_000_000
+
+// An Account signs and sends transactions as a NEAR account.
+type Account struct {
+	rpc       RPCClient
+	accountID string
+	keyPair   KeyPair
+
+	// mu serialises transactions, since each uses the access key's next nonce.
+	mu sync.Mutex
+	// nonce is the last nonce signed with, which may not have reached the
+	// chain yet when a transaction is signed and sent separately.
+	nonce uint64
+}
+
+// NewAccount creates an Account for accountID, signing with privKey in
+// its "ed25519:<base58>" form.
+func NewAccount(rpc RPCClient, accountID, privKey string) (*Account, error) {
+	if !strings.HasSuffix(accountID, ".near") && !strings.HasSuffix(accountID, ".testnet") {
+		return nil, fmt.Errorf("near: account_id must end with '.near' or '.testnet'")
+	}
+
+	keyPair, err := ParseKeyPair(privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Account{
+		rpc:       rpc,
+		accountID: accountID,
+		keyPair:   keyPair,
+	}, nil
+}
+
+// AccountID returns the account's ID.
+func (a *Account) AccountID() string {
+	return a.accountID
+}
+
+// PublicKey returns the public key the account signs with.
+func (a *Account) PublicKey() PublicKey {
+	return a.keyPair.PublicKey()
+}
+
+// Transfer sends amount yoctoNEAR to receiverID.
+func (a *Account) Transfer(ctx context.Context, receiverID string, amount *big.Int) (TxStatusResult, error) {
+	return a.SignAndSendTransaction(ctx, receiverID, TransferAction{Deposit: amount})
+}
+
+// FunctionCall calls methodName on contractID with args (usually JSON),
+// attaching gas and deposit yoctoNEAR.
+func (a *Account) FunctionCall(ctx context.Context, contractID, methodName string, args []byte, gas uint64, deposit *big.Int) (TxStatusResult, error) {
+	if gas == 0 {
+		gas = DefaultFunctionCallGas
+	}
+
+	return a.SignAndSendTransaction(ctx, contractID, FunctionCallAction{
+		MethodName: methodName,
+		Args:       args,
+		Gas:        gas,
+		Deposit:    deposit,
+	})
+}
+
+// CreateSubAccount creates accountID under this account, funds it with
+// initialBalance yoctoNEAR, and gives publicKey full access to it.
+func (a *Account) CreateSubAccount(ctx context.Context, accountID string, publicKey PublicKey, initialBalance *big.Int) (TxStatusResult, error) {
+	if !strings.HasSuffix(accountID, "."+a.accountID) {
+		return TxStatusResult{}, fmt.Errorf("near: %s is not a sub-account of %s", accountID, a.accountID)
+	}
+
+	return a.SignAndSendTransaction(ctx, accountID,
+		CreateAccountAction{},
+		TransferAction{Deposit: initialBalance},
+		AddFullAccessKeyAction{PublicKey: publicKey},
+	)
+}
+
+// SignAndSendTransaction signs a transaction of actions to receiverID,
+// and waits for it to execute. A transaction that executes but fails is
+// returned as an error.
+func (a *Account) SignAndSendTransaction(ctx context.Context, receiverID string, actions ...Action) (TxStatusResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	signedTx, _, err := a.signTransaction(ctx, receiverID, actions...)
+	if err != nil {
+		return TxStatusResult{}, err
+	}
+
+	return a.SendTransaction(ctx, signedTx)
+}
+
+// SignTransaction signs a transaction of actions to receiverID without sending
+// it, and returns it with its hash. Saving it before SendTransaction lets a
+// retry look it up with TransactionStatus, and send the same transaction again
+// rather than sign another.
+func (a *Account) SignTransaction(ctx context.Context, receiverID string, actions ...Action) ([]byte, string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.signTransaction(ctx, receiverID, actions...)
+}
+
+func (a *Account) signTransaction(ctx context.Context, receiverID string, actions ...Action) ([]byte, string, error) {
+	publicKey := a.keyPair.PublicKey()
+
+	accessKey, err := a.rpc.ViewAccessKey(ctx, a.accountID, publicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch access key: %w", err)
+	}
+
+	blockHash, err := Base58Decode(accessKey.BlockHash)
+	if err != nil || len(blockHash) != 32 {
+		return nil, "", fmt.Errorf("near: invalid block hash %q", accessKey.BlockHash)
+	}
+
+	if accessKey.Nonce > a.nonce {
+		a.nonce = accessKey.Nonce
+	}
+	a.nonce++
+
+	tx := Transaction{
+		SignerID:   a.accountID,
+		PublicKey:  publicKey,
+		Nonce:      a.nonce,
+		ReceiverID: receiverID,
+		Actions:    actions,
+	}
+	copy(tx.BlockHash[:], blockHash)
+
+	signedTx, hash, err := tx.Sign(a.keyPair)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return signedTx, Base58Encode(hash[:]), nil
+}
+
+// SendTransaction sends a signed transaction, and waits for it to execute.
+// Sending the same transaction twice executes it once. A transaction that
+// executes but fails is returned as an error.
+func (a *Account) SendTransaction(ctx context.Context, signedTx []byte) (TxStatusResult, error) {
+	result, err := a.rpc.BroadcastTxCommit(ctx, signedTx)
+	if err != nil {
+		return result, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	if !result.IsSuccess() {
+		return result, fmt.Errorf("near: transaction %s failed: %s", result.Transaction.Hash, result.Status["Failure"])
+	}
+
+	return result, nil
+}
+
+// TransactionStatus looks up a transaction the account signed by its hash,
+// failing with ErrUnknownTransaction if the chain hasn't seen it.
+func (a *Account) TransactionStatus(ctx context.Context, txHash string) (TxStatusResult, error) {
+	return a.rpc.TxStatus(ctx, txHash, a.accountID)
+}
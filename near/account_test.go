@@ -0,0 +1,158 @@
+package near
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubRPC is an RPCClient recording what is broadcast.
+type stubRPC struct {
+	nonce     uint64
+	blockHash string
+	broadcast [][]byte
+	result    TxStatusResult
+}
+
+func (s *stubRPC) TxStatus(ctx context.Context, txHash, senderID string) (TxStatusResult, error) {
+	return TxStatusResult{}, ErrUnknownTransaction
+}
+
+func (s *stubRPC) ViewAccessKey(ctx context.Context, accountID string, publicKey PublicKey) (AccessKeyView, error) {
+	return AccessKeyView{Nonce: s.nonce, BlockHash: s.blockHash}, nil
+}
+
+func (s *stubRPC) BroadcastTxCommit(ctx context.Context, signedTx []byte) (TxStatusResult, error) {
+	s.broadcast = append(s.broadcast, signedTx)
+	s.nonce++
+	return s.result, nil
+}
+
+func newTestAccount(t *testing.T, status string) (*Account, *stubRPC) {
+	rpc := &stubRPC{
+		nonce:     7,
+		blockHash: Base58Encode(make([]byte, 32)),
+	}
+	require.NoError(t, json.Unmarshal([]byte(status), &rpc.result.Status))
+
+	account, err := NewAccount(rpc, "storehub-v1.testnet", testPrivKey)
+	require.NoError(t, err)
+	return account, rpc
+}
+
+func TestAccountCreateSubAccount(t *testing.T) {
+	account, rpc := newTestAccount(t, `{"SuccessValue":""}`)
+
+	balance, err := ParseNEAR("1")
+	require.NoError(t, err)
+
+	_, err = account.CreateSubAccount(context.Background(), "shop-1.storehub-v1.testnet", account.PublicKey(), balance)
+	require.NoError(t, err)
+	require.Len(t, rpc.broadcast, 1)
+
+	want := Transaction{
+		SignerID:   "storehub-v1.testnet",
+		PublicKey:  account.PublicKey(),
+		Nonce:      8,
+		ReceiverID: "shop-1.storehub-v1.testnet",
+		Actions: []Action{
+			CreateAccountAction{},
+			TransferAction{Deposit: balance},
+			AddFullAccessKeyAction{PublicKey: account.PublicKey()},
+		},
+	}
+	wantSigned, _, err := want.Sign(account.keyPair)
+	require.NoError(t, err)
+	require.Equal(t, base64.StdEncoding.EncodeToString(wantSigned), base64.StdEncoding.EncodeToString(rpc.broadcast[0]))
+
+	_, err = account.CreateSubAccount(context.Background(), "shop-1.someone-else.testnet", account.PublicKey(), balance)
+	require.Error(t, err)
+}
+
+func TestAccountNoncesIncrease(t *testing.T) {
+	account, rpc := newTestAccount(t, `{"SuccessValue":""}`)
+
+	for i := 0; i < 3; i++ {
+		_, err := account.Transfer(context.Background(), "buyer.testnet", big.NewInt(int64(i+1)))
+		require.NoError(t, err)
+	}
+	require.Len(t, rpc.broadcast, 3)
+	require.Equal(t, uint64(10), rpc.nonce)
+
+	_, err := account.FunctionCall(context.Background(), "market.testnet", "ping", []byte(`{}`), 0, nil)
+	require.NoError(t, err)
+}
+
+func TestAccountFailedTransaction(t *testing.T) {
+	account, _ := newTestAccount(t, `{"Failure":{"ActionError":{"index":0,"kind":{"AccountAlreadyExists":{}}}}}`)
+
+	_, err := account.Transfer(context.Background(), "buyer.testnet", big.NewInt(1))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "AccountAlreadyExists")
+}
+
+func TestAccountSignThenSend(t *testing.T) {
+	account, rpc := newTestAccount(t, `{"SuccessValue":""}`)
+
+	signedTx, hash, err := account.SignTransaction(context.Background(), "buyer.testnet", TransferAction{Deposit: big.NewInt(1)})
+	require.NoError(t, err)
+	require.NotEmpty(t, hash)
+
+	// A transaction signed before the first is sent takes the next nonce.
+	other, _, err := account.SignTransaction(context.Background(), "buyer.testnet", TransferAction{Deposit: big.NewInt(1)})
+	require.NoError(t, err)
+	require.NotEqual(t, signedTx, other)
+
+	want := Transaction{
+		SignerID:   "storehub-v1.testnet",
+		PublicKey:  account.PublicKey(),
+		Nonce:      9,
+		ReceiverID: "buyer.testnet",
+		Actions:    []Action{TransferAction{Deposit: big.NewInt(1)}},
+	}
+	wantSigned, _, err := want.Sign(account.keyPair)
+	require.NoError(t, err)
+	require.Equal(t, wantSigned, other)
+
+	// Sending again sends the same transaction.
+	for i := 0; i < 2; i++ {
+		_, err = account.SendTransaction(context.Background(), signedTx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, [][]byte{signedTx, signedTx}, rpc.broadcast)
+
+	_, err = account.TransactionStatus(context.Background(), hash)
+	require.ErrorIs(t, err, ErrUnknownTransaction)
+}
+
+func TestNewAccount(t *testing.T) {
+	_, err := NewAccount(&stubRPC{}, "storehub", testPrivKey)
+	require.Error(t, err)
+
+	_, err = NewAccount(&stubRPC{}, "storehub.testnet", "ed25519:")
+	require.Error(t, err)
+}
+
+func TestParseNEAR(t *testing.T) {
+	testCases := map[string]string{
+		"1":     "1000000000000000000000000",
+		"0.5":   "500000000000000000000000",
+		"0":     "0",
+		"1e-24": "1",
+	}
+	for in, want := range testCases {
+		got, err := ParseNEAR(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got.String(), in)
+	}
+
+	for _, in := range []string{"-1", "abc", fmt.Sprintf("%se-25", "1")} {
+		_, err := ParseNEAR(in)
+		require.Error(t, err, in)
+	}
+}
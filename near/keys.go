@@ -0,0 +1,79 @@
+package near
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+)
+
+// keyTypeED25519 is the only key type StoreHub signs with.
+const keyTypeED25519 = 0
+
+// A PublicKey is an ed25519 public key of a NEAR access key.
+type PublicKey [ed25519.PublicKeySize]byte
+
+// ParsePublicKey parses a key in its "ed25519:<base58>" form.
+func ParsePublicKey(s string) (PublicKey, error) {
+	var pk PublicKey
+
+	data, err := decodeKey(s)
+	if err != nil {
+		return pk, err
+	}
+	if len(data) != ed25519.PublicKeySize {
+		return pk, fmt.Errorf("near: public key must be %d bytes, got %d", ed25519.PublicKeySize, len(data))
+	}
+
+	copy(pk[:], data)
+	return pk, nil
+}
+
+// String formats the key as "ed25519:<base58>".
+func (pk PublicKey) String() string {
+	return "ed25519:" + Base58Encode(pk[:])
+}
+
+// A KeyPair signs transactions for an account.
+type KeyPair struct {
+	privateKey ed25519.PrivateKey
+}
+
+// ParseKeyPair parses a private key in its "ed25519:<base58>" form, as
+// found in NEAR credential files. Both the 64 byte expanded key and
+// the 32 byte seed are accepted.
+func ParseKeyPair(s string) (KeyPair, error) {
+	data, err := decodeKey(s)
+	if err != nil {
+		return KeyPair{}, err
+	}
+
+	switch len(data) {
+	case ed25519.PrivateKeySize:
+		return KeyPair{privateKey: ed25519.PrivateKey(data)}, nil
+	case ed25519.SeedSize:
+		return KeyPair{privateKey: ed25519.NewKeyFromSeed(data)}, nil
+	default:
+		return KeyPair{}, fmt.Errorf("near: private key must be %d or %d bytes, got %d", ed25519.PrivateKeySize, ed25519.SeedSize, len(data))
+	}
+}
+
+// PublicKey returns the public half of the key pair.
+func (kp KeyPair) PublicKey() PublicKey {
+	var pk PublicKey
+	copy(pk[:], kp.privateKey.Public().(ed25519.PublicKey))
+	return pk
+}
+
+// Sign signs message.
+func (kp KeyPair) Sign(message []byte) []byte {
+	return ed25519.Sign(kp.privateKey, message)
+}
+
+// decodeKey decodes the base58 data of an "ed25519:" prefixed key.
+func decodeKey(s string) ([]byte, error) {
+	data, ok := strings.CutPrefix(s, "ed25519:")
+	if !ok {
+		return nil, fmt.Errorf("near: key must start with 'ed25519:'")
+	}
+	return Base58Decode(data)
+}
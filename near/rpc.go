@@ -0,0 +1,234 @@
+package near
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// TestnetRPCURL is the public NEAR testnet RPC endpoint.
+	TestnetRPCURL = "https://rpc.testnet.near.org"
+	// MainnetRPCURL is the public NEAR mainnet RPC endpoint.
+	MainnetRPCURL = "https://rpc.mainnet.near.org"
+)
+
+var (
+	// ErrUnknownTransaction is returned when the RPC node does not know a transaction hash.
+	ErrUnknownTransaction = errors.New("unknown NEAR transaction")
+	// ErrInvalidTransaction is returned when a transaction is rejected before
+	// it executes, such as for a used nonce or an expired block hash, so it
+	// never will.
+	ErrInvalidTransaction = errors.New("invalid NEAR transaction")
+)
+
+// RPCURLForNetwork returns the public RPC endpoint of network.
+func RPCURLForNetwork(network string) string {
+	if network == "mainnet" {
+		return MainnetRPCURL
+	}
+	return TestnetRPCURL
+}
+
+// RPCClient defines the NEAR JSON-RPC methods StoreHub needs.
+type RPCClient interface {
+	// TxStatus looks up a transaction by its hash and signer, via EXPERIMENTAL_tx_status.
+	TxStatus(ctx context.Context, txHash, senderID string) (TxStatusResult, error)
+
+	// ViewAccessKey fetches an access key's nonce, along with a recent block hash.
+	ViewAccessKey(ctx context.Context, accountID string, publicKey PublicKey) (AccessKeyView, error)
+
+	// BroadcastTxCommit sends a signed transaction and waits for it to execute.
+	BroadcastTxCommit(ctx context.Context, signedTx []byte) (TxStatusResult, error)
+}
+
+// AccessKeyView is an access key as returned by the view_access_key query.
+type AccessKeyView struct {
+	Nonce       uint64 `json:"nonce"`
+	BlockHeight uint64 `json:"block_height"`
+	BlockHash   string `json:"block_hash"`
+}
+
+// An RPCError is an error returned by a NEAR RPC node.
+type RPCError struct {
+	Name    string          `json:"name"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Cause   struct {
+		Name string          `json:"name"`
+		Info json.RawMessage `json:"info"`
+	} `json:"cause"`
+}
+
+func (e *RPCError) Error() string {
+	if e.Cause.Name != "" {
+		return fmt.Sprintf("near rpc: %s: %s", e.Cause.Name, e.Message)
+	}
+	return fmt.Sprintf("near rpc: %s", e.Message)
+}
+
+// Is lets errors.Is match ErrUnknownTransaction and ErrInvalidTransaction.
+func (e *RPCError) Is(target error) bool {
+	switch target {
+	case ErrUnknownTransaction:
+		return e.Cause.Name == "UNKNOWN_TRANSACTION"
+	case ErrInvalidTransaction:
+		return e.Cause.Name == "INVALID_TRANSACTION"
+	}
+	return false
+}
+
+// TxStatusResult is the part of a transaction's final outcome StoreHub reads.
+type TxStatusResult struct {
+	Status      map[string]json.RawMessage `json:"status"`
+	Transaction SignedTransactionView      `json:"transaction"`
+}
+
+// SignedTransactionView is a transaction as returned by the RPC.
+type SignedTransactionView struct {
+	Hash       string            `json:"hash"`
+	SignerID   string            `json:"signer_id"`
+	ReceiverID string            `json:"receiver_id"`
+	Nonce      uint64            `json:"nonce"`
+	Actions    []json.RawMessage `json:"actions"`
+}
+
+// IsSuccess reports whether the transaction executed successfully.
+func (r TxStatusResult) IsSuccess() bool {
+	if _, ok := r.Status["Failure"]; ok {
+		return false
+	}
+	_, ok := r.Status["SuccessValue"]
+	return ok
+}
+
+// IsFailure reports whether the transaction executed and failed.
+func (r TxStatusResult) IsFailure() bool {
+	_, ok := r.Status["Failure"]
+	return ok
+}
+
+// Deposit sums the yoctoNEAR attached by the transaction's Transfer actions.
+func (r TxStatusResult) Deposit() (*big.Int, error) {
+	total := new(big.Int)
+	for _, raw := range r.Transaction.Actions {
+		var action struct {
+			Transfer *struct {
+				Deposit string `json:"deposit"`
+			} `json:"Transfer"`
+		}
+		// actions without a payload, like "CreateAccount", are plain strings
+		if err := json.Unmarshal(raw, &action); err != nil || action.Transfer == nil {
+			continue
+		}
+
+		deposit, err := ParseYocto(action.Transfer.Deposit)
+		if err != nil {
+			return nil, err
+		}
+		total.Add(total, deposit)
+	}
+	return total, nil
+}
+
+// JSONRPCClient is an RPCClient talking to a NEAR RPC node over HTTP.
+type JSONRPCClient struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewRPCClient creates an RPCClient for the node at endpoint.
+func NewRPCClient(endpoint string) RPCClient {
+	return &JSONRPCClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// TxStatus looks up a transaction by its hash and signer, via EXPERIMENTAL_tx_status.
+func (c *JSONRPCClient) TxStatus(ctx context.Context, txHash, senderID string) (TxStatusResult, error) {
+	var result TxStatusResult
+	err := c.call(ctx, "EXPERIMENTAL_tx_status", []string{txHash, senderID}, &result)
+	return result, err
+}
+
+// ViewAccessKey fetches an access key's nonce, along with a recent block hash.
+func (c *JSONRPCClient) ViewAccessKey(ctx context.Context, accountID string, publicKey PublicKey) (AccessKeyView, error) {
+	var result struct {
+		AccessKeyView
+		// older nodes report a missing key here, rather than as an RPC error
+		Error string `json:"error"`
+	}
+	err := c.call(ctx, "query", map[string]string{
+		"request_type": "view_access_key",
+		"finality":     "final",
+		"account_id":   accountID,
+		"public_key":   publicKey.String(),
+	}, &result)
+	if err == nil && result.Error != "" {
+		err = fmt.Errorf("near rpc: %s", result.Error)
+	}
+	return result.AccessKeyView, err
+}
+
+// BroadcastTxCommit sends a signed transaction and waits for it to execute.
+func (c *JSONRPCClient) BroadcastTxCommit(ctx context.Context, signedTx []byte) (TxStatusResult, error) {
+	var result TxStatusResult
+	err := c.call(ctx, "broadcast_tx_commit", []string{base64.StdEncoding.EncodeToString(signedTx)}, &result)
+	return result, err
+}
+
+// call sends a JSON-RPC request and decodes its result into out.
+func (c *JSONRPCClient) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "storehub",
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach near rpc: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode near rpc response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if envelope.Error != nil {
+		return envelope.Error
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(envelope.Result, out); err != nil {
+			return fmt.Errorf("failed to decode near rpc result: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubRPC(t *testing.T, handler func(method string, params []interface{}) string) RPCClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+
+		var req struct {
+			JSONRPC string        `json:"jsonrpc"`
+			Method  string        `json:"method"`
+			Params  []interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "2.0", req.JSONRPC)
+
+		w.Write([]byte(handler(req.Method, req.Params)))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewRPCClient(server.URL)
+}
+
+func TestTxStatus(t *testing.T) {
+	client := newStubRPC(t, func(method string, params []interface{}) string {
+		require.Equal(t, "EXPERIMENTAL_tx_status", method)
+		require.Equal(t, []interface{}{"9FtHUFBQsZ2MG77K3x3MJ9wjX3UT8zE1TczCrhZEcG8U", "buyer.testnet"}, params)
+
+		return `{"jsonrpc":"2.0","id":"storehub","result":{
+			"status":{"SuccessValue":""},
+			"transaction":{
+				"hash":"9FtHUFBQsZ2MG77K3x3MJ9wjX3UT8zE1TczCrhZEcG8U",
+				"signer_id":"buyer.testnet",
+				"receiver_id":"storehub-v1.testnet",
+				"nonce":7,
+				"actions":["CreateAccount",{"Transfer":{"deposit":"1500000000000000000000000"}},{"Transfer":{"deposit":"500000000000000000000000"}}]
+			}}}`
+	})
+
+	res, err := client.TxStatus(context.Background(), "9FtHUFBQsZ2MG77K3x3MJ9wjX3UT8zE1TczCrhZEcG8U", "buyer.testnet")
+	require.NoError(t, err)
+	require.True(t, res.IsSuccess())
+	require.Equal(t, "buyer.testnet", res.Transaction.SignerID)
+	require.Equal(t, "storehub-v1.testnet", res.Transaction.ReceiverID)
+
+	deposit, err := res.Deposit()
+	require.NoError(t, err)
+	require.Equal(t, "2", FormatNEAR(deposit))
+}
+
+func TestTxStatusFailure(t *testing.T) {
+	client := newStubRPC(t, func(method string, params []interface{}) string {
+		return `{"jsonrpc":"2.0","id":"storehub","result":{
+			"status":{"Failure":{"ActionError":{"index":0}}},
+			"transaction":{"hash":"abc","signer_id":"buyer.testnet","receiver_id":"storehub-v1.testnet","actions":[]}}}`
+	})
+
+	res, err := client.TxStatus(context.Background(), "abc", "buyer.testnet")
+	require.NoError(t, err)
+	require.False(t, res.IsSuccess())
+	require.True(t, res.IsFailure())
+}
+
+func TestTxStatusUnknownTransaction(t *testing.T) {
+	client := newStubRPC(t, func(method string, params []interface{}) string {
+		return `{"jsonrpc":"2.0","id":"storehub","error":{
+			"name":"HANDLER_ERROR","code":-32000,"message":"Server error",
+			"cause":{"name":"UNKNOWN_TRANSACTION","info":{}}}}`
+	})
+
+	_, err := client.TxStatus(context.Background(), "abc", "buyer.testnet")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrUnknownTransaction))
+
+	var rpcErr *RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, -32000, rpcErr.Code)
+}
+
+func TestBroadcastInvalidTransaction(t *testing.T) {
+	client := newStubRPC(t, func(method string, params []interface{}) string {
+		require.Equal(t, "broadcast_tx_commit", method)
+		return `{"jsonrpc":"2.0","id":"storehub","error":{
+			"name":"HANDLER_ERROR","code":-32000,"message":"Server error",
+			"cause":{"name":"INVALID_TRANSACTION","info":{"InvalidNonce":{"ak_nonce":9,"tx_nonce":8}}}}}`
+	})
+
+	_, err := client.BroadcastTxCommit(context.Background(), []byte("signed"))
+	require.ErrorIs(t, err, ErrInvalidTransaction)
+	require.False(t, errors.Is(err, ErrUnknownTransaction))
+}
+
+func TestToYocto(t *testing.T) {
+	// 1500.00 at 750.00 a NEAR is exactly 2 NEAR
+	yocto, err := ToYocto(money.MustParse("1500"), money.MustParse("750"))
+	require.NoError(t, err)
+	require.Equal(t, "2", FormatNEAR(yocto))
+
+	// 1.00 at 3.00 a NEAR rounds up
+	yocto, err = ToYocto(money.MustParse("1"), money.MustParse("3"))
+	require.NoError(t, err)
+	require.Equal(t, "333333333333333333333334", yocto.String())
+
+	_, err = ToYocto(money.MustParse("1"), money.Zero)
+	require.Error(t, err)
+
+	_, err = ParseYocto("-1")
+	require.Error(t, err)
+}
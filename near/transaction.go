@@ -0,0 +1,169 @@
+package near
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// Action tags, in the order of the Action enum of nearcore.
+const (
+	actionCreateAccount = 0
+	actionFunctionCall  = 2
+	actionTransfer      = 3
+	actionAddKey        = 5
+)
+
+// accessKeyPermissionFullAccess is the tag of the FullAccess permission.
+const accessKeyPermissionFullAccess = 1
+
+// An Action is one step of a Transaction.
+type Action interface {
+	serialize(w *borshWriter)
+}
+
+// CreateAccountAction creates the transaction's receiver as a new account.
+type CreateAccountAction struct{}
+
+// TransferAction sends Deposit yoctoNEAR to the transaction's receiver.
+type TransferAction struct {
+	Deposit *big.Int
+}
+
+// FunctionCallAction calls MethodName on the receiver's contract.
+type FunctionCallAction struct {
+	MethodName string
+	Args       []byte
+	Gas        uint64
+	Deposit    *big.Int
+}
+
+// AddFullAccessKeyAction adds PublicKey to the receiver as a full access key.
+type AddFullAccessKeyAction struct {
+	PublicKey PublicKey
+}
+
+func (CreateAccountAction) serialize(w *borshWriter) {
+	w.u8(actionCreateAccount)
+}
+
+func (a TransferAction) serialize(w *borshWriter) {
+	w.u8(actionTransfer)
+	w.u128(a.Deposit)
+}
+
+func (a FunctionCallAction) serialize(w *borshWriter) {
+	w.u8(actionFunctionCall)
+	w.string(a.MethodName)
+	w.bytes(a.Args)
+	w.u64(a.Gas)
+	w.u128(a.Deposit)
+}
+
+func (a AddFullAccessKeyAction) serialize(w *borshWriter) {
+	w.u8(actionAddKey)
+	w.publicKey(a.PublicKey)
+	w.u64(0) // access key nonce
+	w.u8(accessKeyPermissionFullAccess)
+}
+
+// A Transaction is an unsigned NEAR transaction.
+type Transaction struct {
+	SignerID   string
+	PublicKey  PublicKey
+	Nonce      uint64
+	ReceiverID string
+	BlockHash  [32]byte
+	Actions    []Action
+}
+
+// Serialize encodes the transaction with borsh.
+func (tx Transaction) Serialize() ([]byte, error) {
+	w := &borshWriter{}
+	w.string(tx.SignerID)
+	w.publicKey(tx.PublicKey)
+	w.u64(tx.Nonce)
+	w.string(tx.ReceiverID)
+	w.buf.Write(tx.BlockHash[:])
+	w.u32(uint32(len(tx.Actions)))
+	for _, action := range tx.Actions {
+		action.serialize(w)
+	}
+	return w.buf.Bytes(), w.err
+}
+
+// Sign signs the transaction with kp, returning the borsh encoded
+// SignedTransaction and the transaction's hash.
+func (tx Transaction) Sign(kp KeyPair) (signedTx []byte, hash [32]byte, err error) {
+	data, err := tx.Serialize()
+	if err != nil {
+		return nil, hash, err
+	}
+
+	hash = sha256.Sum256(data)
+	signature := kp.Sign(hash[:])
+
+	w := &borshWriter{}
+	w.buf.Write(data)
+	w.u8(keyTypeED25519)
+	w.buf.Write(signature)
+
+	return w.buf.Bytes(), hash, w.err
+}
+
+// borshWriter encodes values with borsh (https://borsh.io).
+type borshWriter struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (w *borshWriter) u8(v uint8) {
+	w.buf.WriteByte(v)
+}
+
+func (w *borshWriter) u32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.buf.Write(b[:])
+}
+
+func (w *borshWriter) u64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.buf.Write(b[:])
+}
+
+func (w *borshWriter) u128(v *big.Int) {
+	var b [16]byte
+	if v == nil {
+		v = new(big.Int)
+	}
+	if v.Sign() < 0 || v.BitLen() > 128 {
+		if w.err == nil {
+			w.err = fmt.Errorf("near: %s does not fit in a u128", v)
+		}
+	} else {
+		// big.Int bytes are big endian
+		be := v.Bytes()
+		for i := range be {
+			b[i] = be[len(be)-1-i]
+		}
+	}
+	w.buf.Write(b[:])
+}
+
+func (w *borshWriter) bytes(v []byte) {
+	w.u32(uint32(len(v)))
+	w.buf.Write(v)
+}
+
+func (w *borshWriter) string(v string) {
+	w.bytes([]byte(v))
+}
+
+func (w *borshWriter) publicKey(pk PublicKey) {
+	w.u8(keyTypeED25519)
+	w.buf.Write(pk[:])
+}
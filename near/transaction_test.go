@@ -0,0 +1,119 @@
+package near
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPubKey  = "ed25519:2D1EQbh5mLaFNkYhEVH8jDYwjiazGaNJwx3a1GfyoFat"
+	testPrivKey = "ed25519:4PFLtqFujp64BjiKMvhX8iPUaaRJ5jvFHVz85mrCpgc5xwHRTwGBsgTQnY8VnaD4J2pxBAbkuGG4mjpHnXazsFpx"
+)
+
+func TestBase58(t *testing.T) {
+	for _, b := range [][]byte{
+		{},
+		{0},
+		{0, 0, 1},
+		[]byte("storehub"),
+		sha256.New().Sum(nil),
+	} {
+		decoded, err := Base58Decode(Base58Encode(b))
+		require.NoError(t, err)
+		require.Equal(t, b, decoded)
+	}
+
+	require.Equal(t, "11", Base58Encode([]byte{0, 0}))
+
+	_, err := Base58Decode("0OIl")
+	require.Error(t, err)
+}
+
+func TestParseKeyPair(t *testing.T) {
+	kp, err := ParseKeyPair(testPrivKey)
+	require.NoError(t, err)
+	require.Equal(t, testPubKey, kp.PublicKey().String())
+
+	pk, err := ParsePublicKey(testPubKey)
+	require.NoError(t, err)
+	require.Equal(t, kp.PublicKey(), pk)
+
+	// a bare seed is accepted too
+	seed := kp.privateKey.Seed()
+	fromSeed, err := ParseKeyPair("ed25519:" + Base58Encode(seed))
+	require.NoError(t, err)
+	require.Equal(t, pk, fromSeed.PublicKey())
+
+	_, err = ParseKeyPair("secp256k1:abc")
+	require.Error(t, err)
+
+	_, err = ParsePublicKey("ed25519:abc")
+	require.Error(t, err)
+}
+
+// TestSerializeTransaction checks against the transfer vector of near-api-js.
+func TestSerializeTransaction(t *testing.T) {
+	pk, err := ParsePublicKey("ed25519:Anu7LYDfpLtkP7E16LT9imXF694BdQaa9ufVkQiwTQxC")
+	require.NoError(t, err)
+
+	blockHash, err := Base58Decode("244ZQ9cgj3CQ6bWBdytfrJMuMQ1jdXLFGnr4HhvtCTnM")
+	require.NoError(t, err)
+
+	tx := Transaction{
+		SignerID:   "test.near",
+		PublicKey:  pk,
+		Nonce:      1,
+		ReceiverID: "whatever.near",
+		Actions:    []Action{TransferAction{Deposit: big.NewInt(1)}},
+	}
+	copy(tx.BlockHash[:], blockHash)
+
+	data, err := tx.Serialize()
+	require.NoError(t, err)
+	require.Equal(t,
+		"09000000746573742e6e65617200917b3d268d4b58f7fec1b150bd68d69be3ee5d4cc39855e341538465bb77860d01000000000000000d00000077686174657665722e6e6561720fa473fd26901df296be6adc4cc4df34d040efa2435224b6986910e630c2fef6010000000301000000000000000000000000000000",
+		hex.EncodeToString(data),
+	)
+}
+
+func TestSignTransaction(t *testing.T) {
+	kp, err := ParseKeyPair(testPrivKey)
+	require.NoError(t, err)
+
+	tx := Transaction{
+		SignerID:   "storehub-v1.testnet",
+		PublicKey:  kp.PublicKey(),
+		Nonce:      42,
+		ReceiverID: "shop-1.storehub-v1.testnet",
+		Actions: []Action{
+			CreateAccountAction{},
+			TransferAction{Deposit: YoctoPerNEAR},
+			AddFullAccessKeyAction{PublicKey: kp.PublicKey()},
+			FunctionCallAction{MethodName: "init", Args: []byte(`{}`), Gas: DefaultFunctionCallGas},
+		},
+	}
+
+	signedTx, hash, err := tx.Sign(kp)
+	require.NoError(t, err)
+
+	data, err := tx.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, sha256.Sum256(data), hash)
+
+	// SignedTransaction is the transaction followed by the key type and signature
+	require.Len(t, signedTx, len(data)+1+ed25519.SignatureSize)
+	require.Equal(t, data, signedTx[:len(data)])
+	require.Equal(t, byte(keyTypeED25519), signedTx[len(data)])
+
+	pub := kp.PublicKey()
+	require.True(t, ed25519.Verify(pub[:], hash[:], signedTx[len(data)+1:]))
+
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 128)
+	_, _, err = Transaction{Actions: []Action{TransferAction{Deposit: tooBig}}}.Sign(kp)
+	require.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package near
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// YoctoPerNEAR is the number of yoctoNEAR in one NEAR.
+var YoctoPerNEAR = new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil)
+
+// ParseYocto parses a yoctoNEAR amount, as found in RPC responses.
+func ParseYocto(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("near: invalid yoctoNEAR amount %q", s)
+	}
+	return v, nil
+}
+
+// ParseNEAR parses an amount of NEAR, e.g. "1.5", into yoctoNEAR.
+func ParseNEAR(s string) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() < 0 {
+		return nil, fmt.Errorf("near: invalid NEAR amount %q", s)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(YoctoPerNEAR))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("near: %q is more precise than a yoctoNEAR", s)
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// ToYocto converts amount into yoctoNEAR, given the price of one NEAR in
+// the amount's currency. It rounds up, so a buyer never pays short.
+func ToYocto(amount, pricePerNEAR money.Amount) (*big.Int, error) {
+	if pricePerNEAR <= money.Zero {
+		return nil, fmt.Errorf("near: invalid NEAR price %s", pricePerNEAR)
+	}
+
+	num := new(big.Int).Mul(big.NewInt(amount.Minor()), YoctoPerNEAR)
+	den := big.NewInt(pricePerNEAR.Minor())
+
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q, nil
+}
+
+// FormatNEAR formats a yoctoNEAR amount in NEAR, e.g. "1.5".
+func FormatNEAR(yocto *big.Int) string {
+	s := new(big.Rat).SetFrac(yocto, YoctoPerNEAR).FloatString(24)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
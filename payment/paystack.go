@@ -0,0 +1,198 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPaystackBaseURL is Paystack's API host.
+	DefaultPaystackBaseURL = "https://api.paystack.co"
+
+	// PaystackSignatureHeader carries the HMAC of a webhook's body.
+	PaystackSignatureHeader = "x-paystack-signature"
+
+	// PaystackEventChargeSuccess is the webhook event sent for a successful charge.
+	PaystackEventChargeSuccess = "charge.success"
+
+	// paystackCodeTransactionNotFound is the error code of a lookup of a
+	// reference Paystack has no transaction for.
+	paystackCodeTransactionNotFound = "transaction_not_found"
+)
+
+// PaystackClient talks to the Paystack API.
+type PaystackClient struct {
+	baseURL    string
+	secretKey  string
+	httpClient *http.Client
+}
+
+// NewPaystackClient creates a Paystack Provider. An empty
+// baseURL falls back to DefaultPaystackBaseURL.
+func NewPaystackClient(baseURL, secretKey string) Provider {
+	if baseURL == "" {
+		baseURL = DefaultPaystackBaseURL
+	}
+
+	return &PaystackClient{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		secretKey: secretKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// paystackResponse is the envelope every Paystack response is wrapped in.
+type paystackResponse struct {
+	Status  bool            `json:"status"`
+	Message string          `json:"message"`
+	Code    string          `json:"code"` // set on errors
+	Data    json.RawMessage `json:"data"`
+}
+
+// PaystackWebhookEvent is the body of a Paystack webhook.
+type PaystackWebhookEvent struct {
+	Event string             `json:"event"`
+	Data  TransactionDetails `json:"data"`
+}
+
+// ParsePaystackWebhookEvent decodes a Paystack webhook body.
+func ParsePaystackWebhookEvent(body []byte) (PaystackWebhookEvent, error) {
+	var event PaystackWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return event, fmt.Errorf("failed to decode paystack event: %w", err)
+	}
+	return event, nil
+}
+
+// InitializeTransaction starts a transaction the buyer pays for on Paystack's checkout page.
+func (c *PaystackClient) InitializeTransaction(ctx context.Context, arg InitializeTransactionParams) (InitializeTransactionResult, error) {
+	var result InitializeTransactionResult
+
+	reqBody := map[string]interface{}{
+		"email":     arg.Email,
+		"amount":    arg.Amount,
+		"reference": arg.Reference,
+	}
+	if arg.Currency != "" {
+		reqBody["currency"] = arg.Currency
+	}
+	if arg.CallbackURL != "" {
+		reqBody["callback_url"] = arg.CallbackURL
+	}
+	if arg.Metadata != nil {
+		reqBody["metadata"] = arg.Metadata
+	}
+
+	err := c.do(ctx, http.MethodPost, "/transaction/initialize", reqBody, &result)
+	return result, err
+}
+
+// VerifyTransaction fetches the current state of a transaction from Paystack.
+// It returns ErrTransactionNotFound if Paystack has none with the reference.
+func (c *PaystackClient) VerifyTransaction(ctx context.Context, reference string) (TransactionDetails, error) {
+	var details TransactionDetails
+	err := c.do(ctx, http.MethodGet, "/transaction/verify/"+url.PathEscape(reference), nil, &details)
+	return details, err
+}
+
+// Refund asks Paystack to refund amount of the transaction with the given
+// reference. Paystack processes refunds asynchronously, so the result is
+// usually "pending".
+func (c *PaystackClient) Refund(ctx context.Context, arg RefundParams) (RefundResult, error) {
+	var result RefundResult
+
+	reqBody := map[string]interface{}{
+		"transaction": arg.Reference,
+		"amount":      arg.Amount,
+	}
+	if arg.Reason != "" {
+		reqBody["merchant_note"] = arg.Reason
+	}
+
+	err := c.do(ctx, http.MethodPost, "/refund", reqBody, &result)
+	return result, err
+}
+
+// ListRefunds fetches the refunds of the transaction with the given reference
+// from Paystack.
+func (c *PaystackClient) ListRefunds(ctx context.Context, reference string) ([]RefundResult, error) {
+	var refunds []RefundResult
+	err := c.do(ctx, http.MethodGet, "/refund?reference="+url.QueryEscape(reference), nil, &refunds)
+	return refunds, err
+}
+
+// ValidateWebhookSignature checks the x-paystack-signature header, a
+// hex encoded HMAC-SHA512 of the body keyed with the secret key.
+func (c *PaystackClient) ValidateWebhookSignature(body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha512.New, []byte(c.secretKey))
+	mac.Write(body)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// do sends a request to Paystack and decodes the response's data into out.
+func (c *PaystackClient) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reqBody *bytes.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal paystack request: %w", err)
+		}
+		reqBody = bytes.NewReader(buf)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach paystack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope paystackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode paystack response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("paystack failed (status %d): %s", resp.StatusCode, envelope.Message)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || !envelope.Status {
+		if envelope.Code == paystackCodeTransactionNotFound {
+			return fmt.Errorf("%w: %s", ErrTransactionNotFound, envelope.Message)
+		}
+		return fmt.Errorf("%w: %s", ErrProvider, envelope.Message)
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("failed to decode paystack data: %w", err)
+		}
+	}
+
+	return nil
+}
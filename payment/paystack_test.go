@@ -0,0 +1,171 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSecretKey = "sk_test_storehub"
+
+func newFakePaystack(t *testing.T, handler http.HandlerFunc) (Provider, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer "+testSecretKey, r.Header.Get("Authorization"))
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewPaystackClient(server.URL, testSecretKey), server
+}
+
+func TestPaystackInitializeTransaction(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/transaction/initialize", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "buyer@storehub.dev", body["email"])
+		require.EqualValues(t, 250000, body["amount"])
+		require.Equal(t, "ref-123", body["reference"])
+
+		w.Write([]byte(`{"status":true,"message":"Authorization URL created","data":{
+			"authorization_url":"https://checkout.paystack.com/abc","access_code":"abc","reference":"ref-123"}}`))
+	})
+
+	res, err := client.InitializeTransaction(context.Background(), InitializeTransactionParams{
+		Email:     "buyer@storehub.dev",
+		Amount:    250000,
+		Reference: "ref-123",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://checkout.paystack.com/abc", res.AuthorizationURL)
+	require.Equal(t, "abc", res.AccessCode)
+	require.Equal(t, "ref-123", res.Reference)
+}
+
+func TestPaystackVerifyTransaction(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/transaction/verify/ref-123", r.URL.Path)
+
+		w.Write([]byte(`{"status":true,"message":"Verification successful","data":{
+			"id":1,"status":"success","reference":"ref-123","amount":250000,"fees":3750,
+			"currency":"NGN","paid_at":"2023-09-01T10:00:00.000Z"}}`))
+	})
+
+	details, err := client.VerifyTransaction(context.Background(), "ref-123")
+	require.NoError(t, err)
+	require.True(t, details.IsSuccessful())
+	require.EqualValues(t, 250000, details.Amount)
+	require.EqualValues(t, 3750, details.Fees)
+	require.False(t, details.PaidAt.IsZero())
+}
+
+func TestPaystackRefund(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/refund", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "ref-123", body["transaction"])
+		require.EqualValues(t, 100000, body["amount"])
+		require.Equal(t, "returned damaged", body["merchant_note"])
+
+		w.Write([]byte(`{"status":true,"message":"Refund has been queued for processing","data":{
+			"transaction":{"id":1,"reference":"ref-123","amount":250000},
+			"id":7,"status":"pending","amount":100000,"currency":"NGN"}}`))
+	})
+
+	res, err := client.Refund(context.Background(), RefundParams{
+		Reference: "ref-123",
+		Amount:    100000,
+		Reason:    "returned damaged",
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 7, res.ID)
+	require.Equal(t, "pending", res.Status)
+	require.EqualValues(t, 100000, res.Amount)
+}
+
+func TestPaystackListRefunds(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/refund", r.URL.Path)
+		require.Equal(t, "ref-123", r.URL.Query().Get("reference"))
+
+		w.Write([]byte(`{"status":true,"message":"Refunds retrieved","data":[
+			{"id":7,"status":"processed","amount":100000,"currency":"NGN","merchant_note":"returned damaged"},
+			{"id":8,"status":"failed","amount":50000,"currency":"NGN","merchant_note":"wrong size"}]}`))
+	})
+
+	refunds, err := client.ListRefunds(context.Background(), "ref-123")
+	require.NoError(t, err)
+	require.Len(t, refunds, 2)
+	require.EqualValues(t, 7, refunds[0].ID)
+	require.Equal(t, "returned damaged", refunds[0].MerchantNote)
+	require.False(t, refunds[0].IsFailed())
+	require.True(t, refunds[1].IsFailed())
+}
+
+func TestPaystackRejectedRequest(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":false,"message":"Invalid key"}`))
+	})
+
+	_, err := client.VerifyTransaction(context.Background(), "ref-123")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrProvider))
+	require.False(t, errors.Is(err, ErrTransactionNotFound))
+}
+
+func TestPaystackServerError(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":false,"message":"Service unavailable"}`))
+	})
+
+	_, err := client.Refund(context.Background(), RefundParams{Reference: "ref-123", Amount: 100000})
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrProvider))
+}
+
+func TestPaystackTransactionNotFound(t *testing.T) {
+	client, _ := newFakePaystack(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":false,"message":"Transaction reference not found","type":"validation_error","code":"transaction_not_found"}`))
+	})
+
+	_, err := client.VerifyTransaction(context.Background(), "ref-123")
+	require.True(t, errors.Is(err, ErrTransactionNotFound))
+	require.True(t, errors.Is(err, ErrProvider))
+}
+
+func TestPaystackValidateWebhookSignature(t *testing.T) {
+	client := NewPaystackClient("", testSecretKey)
+	body := []byte(`{"event":"charge.success","data":{"reference":"ref-123","amount":250000}}`)
+
+	mac := hmac.New(sha512.New, []byte(testSecretKey))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	require.True(t, client.ValidateWebhookSignature(body, signature))
+	require.False(t, client.ValidateWebhookSignature(body, ""))
+	require.False(t, client.ValidateWebhookSignature(body, "not-hex"))
+	require.False(t, client.ValidateWebhookSignature(append(body, ' '), signature))
+
+	event, err := ParsePaystackWebhookEvent(body)
+	require.NoError(t, err)
+	require.Equal(t, PaystackEventChargeSuccess, event.Event)
+	require.Equal(t, "ref-123", event.Data.Reference)
+}
@@ -0,0 +1,109 @@
+// Package payment defines the interface StoreHub needs from a
+// payment provider, and its Paystack implementation.
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// ProviderPaystack is the payment_provider value stored for Paystack transactions.
+	ProviderPaystack = "PAYSTACK"
+	// ProviderNEARWallet is the payment_provider value stored for NEAR wallet transactions.
+	ProviderNEARWallet = "NEAR_WALLET"
+)
+
+const (
+	// StatusSuccess is reported by the provider for a paid transaction.
+	StatusSuccess = "success"
+	// StatusFailed is reported by the provider for a failed transaction.
+	StatusFailed = "failed"
+	// StatusAbandoned is reported by the provider when the buyer never completed payment.
+	StatusAbandoned = "abandoned"
+)
+
+// ErrProvider is returned when the provider rejects a request. Any other
+// error, such as a timeout or a server error, leaves it unknown whether the
+// request took effect.
+var ErrProvider = errors.New("payment provider rejected the request")
+
+// ErrTransactionNotFound is returned when the provider has no transaction
+// with the given reference. It wraps ErrProvider.
+var ErrTransactionNotFound = fmt.Errorf("%w: transaction not found", ErrProvider)
+
+// Provider defines the operations required from a payment provider.
+type Provider interface {
+	// InitializeTransaction starts a transaction the buyer pays for on the provider's checkout page.
+	InitializeTransaction(ctx context.Context, arg InitializeTransactionParams) (InitializeTransactionResult, error)
+
+	// VerifyTransaction fetches the current state of a transaction from the provider.
+	VerifyTransaction(ctx context.Context, reference string) (TransactionDetails, error)
+
+	// ValidateWebhookSignature reports whether signature was produced by the provider for body.
+	ValidateWebhookSignature(body []byte, signature string) bool
+
+	// Refund returns all or part of a transaction's amount to the buyer.
+	Refund(ctx context.Context, arg RefundParams) (RefundResult, error)
+
+	// ListRefunds fetches the refunds of the transaction with the given reference.
+	ListRefunds(ctx context.Context, reference string) ([]RefundResult, error)
+}
+
+// InitializeTransactionParams contains the input parameters
+// needed to initialize a transaction.
+type InitializeTransactionParams struct {
+	Email       string
+	Amount      int64 // in minor units (kobo)
+	Currency    string
+	Reference   string
+	CallbackURL string
+	Metadata    map[string]interface{}
+}
+
+// InitializeTransactionResult contains what the buyer needs to pay.
+type InitializeTransactionResult struct {
+	AuthorizationURL string `json:"authorization_url"`
+	AccessCode       string `json:"access_code"`
+	Reference        string `json:"reference"`
+}
+
+// TransactionDetails describes a transaction as seen by the provider.
+type TransactionDetails struct {
+	ID        int64     `json:"id"`
+	Status    string    `json:"status"`
+	Reference string    `json:"reference"`
+	Amount    int64     `json:"amount"`
+	Fees      int64     `json:"fees"`
+	Currency  string    `json:"currency"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// IsSuccessful reports whether the buyer paid.
+func (d TransactionDetails) IsSuccessful() bool {
+	return d.Status == StatusSuccess
+}
+
+// RefundParams describes a refund of a paid transaction.
+type RefundParams struct {
+	Reference string // the transaction's reference
+	Amount    int64  // in minor units (kobo)
+	Reason    string
+}
+
+// RefundResult describes a refund as seen by the provider.
+type RefundResult struct {
+	ID           int64  `json:"id"`
+	Status       string `json:"status"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	MerchantNote string `json:"merchant_note"`
+}
+
+// IsFailed reports whether the provider gave up on the refund, so the buyer
+// wasn't refunded by it.
+func (r RefundResult) IsFailed() bool {
+	return r.Status == StatusFailed
+}
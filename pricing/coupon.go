@@ -0,0 +1,168 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// Kinds of Coupon.
+const (
+	CouponPercent = "PERCENT"
+	CouponFixed   = "FIXED"
+)
+
+// A Coupon takes money off the lines it covers: lines from its store,
+// narrowed to ItemIDs or Category when set.
+type Coupon struct {
+	ID       int64
+	Code     string
+	StoreID  int64
+	Kind     string       // CouponPercent or CouponFixed
+	Value    string       // percentage off for CouponPercent, amount off for CouponFixed
+	ItemIDs  []int64      // only these items, when set
+	Category string       // only items in this category, when set
+	MinSpend money.Amount // least that must be spent on covered lines
+}
+
+// CouponBreakdown is how a Coupon applied to an order. A coupon that
+// doesn't apply is reported with the Reason, and takes nothing off.
+type CouponBreakdown struct {
+	CouponID int64        `json:"coupon_id"`
+	Code     string       `json:"code"`
+	StoreID  int64        `json:"store_id"`
+	Discount money.Amount `json:"discount"`
+	Applied  bool         `json:"applied"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+// covers reports whether the coupon takes money off line.
+func (c Coupon) covers(line Line) bool {
+	if line.StoreID != c.StoreID {
+		return false
+	}
+
+	if len(c.ItemIDs) > 0 {
+		for _, id := range c.ItemIDs {
+			if id == line.ItemID {
+				return true
+			}
+		}
+		return false
+	}
+
+	return c.Category == "" || c.Category == line.Category
+}
+
+// applyCoupons sets the discount of every line in b covered by a coupon.
+// A store's lines take at most one coupon, the first that applies.
+func applyCoupons(b *Breakdown, lines []Line, coupons []Coupon) error {
+	couponed := make(map[int64]bool)
+
+	for _, coupon := range coupons {
+		cb := CouponBreakdown{
+			CouponID: coupon.ID,
+			Code:     coupon.Code,
+			StoreID:  coupon.StoreID,
+		}
+
+		var covered []int
+		var coveredTotal money.Amount
+		for i, line := range lines {
+			if coupon.covers(line) {
+				covered = append(covered, i)
+				coveredTotal += b.Lines[i].LineTotal
+			}
+		}
+
+		switch {
+		case couponed[coupon.StoreID]:
+			cb.Reason = "another coupon already applies to this store"
+		case len(covered) == 0:
+			cb.Reason = "no items in the order are covered by this coupon"
+		case coveredTotal < coupon.MinSpend:
+			cb.Reason = fmt.Sprintf("spend at least %s on covered items", coupon.MinSpend)
+		}
+
+		if cb.Reason != "" {
+			b.Coupons = append(b.Coupons, cb)
+			continue
+		}
+
+		discounts := make([]money.Amount, len(covered))
+		switch coupon.Kind {
+		case CouponPercent:
+			for j, i := range covered {
+				discount, err := b.Lines[i].LineTotal.Percent(coupon.Value)
+				if err != nil {
+					return err
+				}
+				if discount < money.Zero || discount > b.Lines[i].LineTotal {
+					return fmt.Errorf("pricing: invalid percentage %q on coupon %d", coupon.Value, coupon.ID)
+				}
+				discounts[j] = discount
+			}
+		case CouponFixed:
+			amount, err := money.Parse(coupon.Value)
+			if err != nil {
+				return err
+			}
+			if amount < money.Zero {
+				return fmt.Errorf("pricing: invalid amount %q on coupon %d", coupon.Value, coupon.ID)
+			}
+			if amount > coveredTotal {
+				amount = coveredTotal
+			}
+
+			weights := make([]money.Amount, len(covered))
+			for j, i := range covered {
+				weights[j] = b.Lines[i].LineTotal
+			}
+			discounts = split(amount, weights)
+		default:
+			return fmt.Errorf("pricing: unknown kind %q on coupon %d", coupon.Kind, coupon.ID)
+		}
+
+		for j, i := range covered {
+			b.Lines[i].Discount = discounts[j]
+			b.Lines[i].CouponID = coupon.ID
+			cb.Discount += discounts[j]
+		}
+
+		cb.Applied = true
+		couponed[coupon.StoreID] = true
+		b.Coupons = append(b.Coupons, cb)
+	}
+
+	return nil
+}
+
+// split divides amount in proportion to weights, which sum to at least
+// amount. The last share takes what rounding leaves over.
+func split(amount money.Amount, weights []money.Amount) []money.Amount {
+	shares := make([]money.Amount, len(weights))
+
+	var total money.Amount
+	for _, w := range weights {
+		total += w
+	}
+	if total == money.Zero {
+		return shares
+	}
+
+	var given money.Amount
+	for i, w := range weights {
+		if i == len(weights)-1 {
+			shares[i] = amount - given
+			break
+		}
+
+		share := new(big.Int).Mul(big.NewInt(amount.Minor()), big.NewInt(w.Minor()))
+		share.Quo(share, big.NewInt(total.Minor()))
+		shares[i] = money.FromMinor(share.Int64())
+		given += shares[i]
+	}
+
+	return shares
+}
@@ -0,0 +1,81 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteCoupons(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "100.00", Category: "shoes", Quantity: 2},
+		{ItemID: 2, StoreID: 10, Price: "50.00", Category: "hats", Quantity: 1},
+		{ItemID: 3, StoreID: 20, Price: "300.00", Category: "shoes", Quantity: 1},
+	}
+	rules := map[int64]DeliveryRule{
+		10: {BaseFee: money.MustParse("20"), FreeDeliveryThreshold: money.MustParse("250")},
+	}
+
+	b, err := Quote(lines, rules,
+		Coupon{ID: 1, Code: "SHOES10", StoreID: 10, Kind: CouponPercent, Value: "10", Category: "shoes"},
+		Coupon{ID: 2, Code: "FIVEOFF", StoreID: 20, Kind: CouponFixed, Value: "5", MinSpend: money.MustParse("500")},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, money.MustParse("20"), b.Lines[0].Discount)
+	require.Equal(t, int64(1), b.Lines[0].CouponID)
+	require.Equal(t, money.Zero, b.Lines[1].Discount)
+	require.Equal(t, money.Zero, b.Lines[2].Discount)
+
+	require.Len(t, b.Coupons, 2)
+	require.True(t, b.Coupons[0].Applied)
+	require.Equal(t, money.MustParse("20"), b.Coupons[0].Discount)
+	require.False(t, b.Coupons[1].Applied)
+	require.NotEmpty(t, b.Coupons[1].Reason)
+
+	// 250 less the 20 discount misses the free delivery threshold
+	require.Equal(t, money.MustParse("20"), b.Stores[0].Discount)
+	require.Equal(t, money.MustParse("20"), b.Stores[0].DeliveryFee)
+	require.Equal(t, money.MustParse("250"), b.Stores[0].Total)
+
+	require.Equal(t, money.MustParse("20"), b.Discount)
+	require.Equal(t, money.MustParse("550"), b.GrandTotal)
+
+	var lineTotals money.Amount
+	for _, l := range b.Lines {
+		lineTotals += l.Total()
+	}
+	require.Equal(t, b.GrandTotal, lineTotals)
+}
+
+func TestQuoteFixedCoupon(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "10.00", Quantity: 1},
+		{ItemID: 2, StoreID: 10, Price: "20.00", Quantity: 1},
+		{ItemID: 3, StoreID: 10, Price: "5.00", Quantity: 1},
+	}
+
+	b, err := Quote(lines, nil,
+		Coupon{ID: 1, StoreID: 10, Kind: CouponFixed, Value: "10", ItemIDs: []int64{1, 2}},
+		Coupon{ID: 2, StoreID: 10, Kind: CouponPercent, Value: "50"},
+	)
+	require.NoError(t, err)
+
+	// split by line total, the last covered line takes the rounding
+	require.Equal(t, money.MustParse("3.33"), b.Lines[0].Discount)
+	require.Equal(t, money.MustParse("6.67"), b.Lines[1].Discount)
+	require.Equal(t, money.Zero, b.Lines[2].Discount)
+
+	// one coupon per store
+	require.False(t, b.Coupons[1].Applied)
+	require.Equal(t, money.MustParse("25"), b.GrandTotal)
+
+	// a fixed coupon never takes more than the covered lines cost
+	b, err = Quote(lines[2:], nil, Coupon{ID: 1, StoreID: 10, Kind: CouponFixed, Value: "10"})
+	require.NoError(t, err)
+	require.Equal(t, money.Zero, b.GrandTotal)
+
+	_, err = Quote(lines, nil, Coupon{ID: 1, StoreID: 10, Kind: "BOGO"})
+	require.Error(t, err)
+}
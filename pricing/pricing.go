@@ -0,0 +1,286 @@
+// Package pricing computes what a buyer pays for an order, in one
+// currency: each line's price, coupon discounts, every store's delivery
+// fee and tax, and the grand total. Every order and checkout prices
+// through it, so the buyer sees the figures stored on the orders.
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// A Line is an item being bought.
+type Line struct {
+	ItemID             int64
+	VariantID          int64 // the variant of the item bought, if it has variants
+	StoreID            int64
+	Price              string // the item's listed price
+	Currency           string // the currency Price is listed in
+	FxRate             string // converts Price to the quote's currency, empty if it's listed in it
+	DiscountPercentage string // e.g. "12.5" for 12.5% off
+	Category           string
+	Quantity           int32
+	WeightGrams        int64 // of a unit
+}
+
+// A DeliveryRule is how a store charges for delivery. An order costs
+// BaseFee plus PerItemFee for every unit, plus the fee its Rates table
+// charges when it has one, and is free once the store's subtotal reaches
+// FreeDeliveryThreshold (when not zero). It arrives within MinDays to
+// MaxDays, when they're known.
+type DeliveryRule struct {
+	ZoneID                int64 // the shipping zone the rule is for, if any
+	BaseFee               money.Amount
+	PerItemFee            money.Amount
+	FreeDeliveryThreshold money.Amount
+	Basis                 string // what Rates are looked up by, e.g. RateWeight
+	Rates                 []Rate
+	MinDays               int32
+	MaxDays               int32
+}
+
+// LineBreakdown is a priced Line.
+type LineBreakdown struct {
+	ItemID             int64        `json:"item_id"`
+	VariantID          int64        `json:"variant_id,omitempty"`
+	StoreID            int64        `json:"store_id"`
+	Quantity           int32        `json:"quantity"`
+	Currency           string       `json:"currency,omitempty"` // the currency the item is listed in
+	ListedPrice        money.Amount `json:"listed_price"`
+	FxRate             string       `json:"fx_rate,omitempty"`
+	Price              money.Amount `json:"price"` // ListedPrice in the quote's currency
+	DiscountPercentage string       `json:"discount_percentage"`
+	UnitPrice          money.Amount `json:"unit_price"`
+	LineTotal          money.Amount `json:"line_total"`
+	Discount           money.Amount `json:"discount"` // taken off LineTotal by a coupon
+	CouponID           int64        `json:"coupon_id,omitempty"`
+	DeliveryFee        money.Amount `json:"delivery_fee"`
+	TaxRuleID          int64        `json:"tax_rule_id,omitempty"`
+	TaxRate            string       `json:"tax_rate,omitempty"`
+	TaxInclusive       bool         `json:"tax_inclusive"` // Tax is part of LineTotal
+	Tax                money.Amount `json:"tax"`
+}
+
+// Total is what the buyer pays for the line, delivery and tax included.
+func (lb LineBreakdown) Total() money.Amount {
+	total := lb.LineTotal - lb.Discount + lb.DeliveryFee
+	if !lb.TaxInclusive {
+		total += lb.Tax
+	}
+	return total
+}
+
+// StoreBreakdown sums up the lines bought from a store.
+type StoreBreakdown struct {
+	StoreID         int64        `json:"store_id"`
+	ShippingZoneID  int64        `json:"shipping_zone_id,omitempty"`
+	MinDeliveryDays int32        `json:"min_delivery_days,omitempty"`
+	MaxDeliveryDays int32        `json:"max_delivery_days,omitempty"`
+	Subtotal        money.Amount `json:"subtotal"`
+	Discount        money.Amount `json:"discount"`
+	DeliveryFee     money.Amount `json:"delivery_fee"`
+	Tax             money.Amount `json:"tax"`
+	Total           money.Amount `json:"total"`
+}
+
+// A Breakdown is the buyer-visible price of an order.
+type Breakdown struct {
+	Currency    string            `json:"currency"`
+	Lines       []LineBreakdown   `json:"lines"`
+	Stores      []StoreBreakdown  `json:"stores"`
+	Coupons     []CouponBreakdown `json:"coupons"`
+	Subtotal    money.Amount      `json:"subtotal"`
+	Discount    money.Amount      `json:"discount"`
+	DeliveryFee money.Amount      `json:"delivery_fee"`
+	Tax         money.Amount      `json:"tax"`
+	GrandTotal  money.Amount      `json:"grand_total"`
+}
+
+// UnitPrice returns price less discountPercentage percent.
+func UnitPrice(price, discountPercentage string) (money.Amount, error) {
+	p, err := money.Parse(price)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	if discountPercentage == "" {
+		return p, nil
+	}
+
+	discount, err := p.Percent(discountPercentage)
+	if err != nil {
+		return money.Zero, err
+	}
+
+	if discount < money.Zero || discount > p {
+		return money.Zero, fmt.Errorf("pricing: invalid discount percentage %q", discountPercentage)
+	}
+
+	return p - discount, nil
+}
+
+// Fee returns the delivery fee for qty units worth subtotal, weighing
+// weight grams.
+func (r DeliveryRule) Fee(subtotal money.Amount, qty, weight int64) money.Amount {
+	if r.FreeDeliveryThreshold > money.Zero && subtotal >= r.FreeDeliveryThreshold {
+		return money.Zero
+	}
+	return r.BaseFee + r.PerItemFee.Mul(qty) + r.rateFee(qty, weight)
+}
+
+// Quote prices lines. rules holds each store's DeliveryRule, a store
+// without one delivers for free. coupons take money off the lines they
+// cover before delivery is charged.
+//
+// Orders are created per line, so a store's delivery fee is also split
+// across its lines: each line carries its per-item fee, and the first
+// line from the store carries the base fee and the rate table's fee.
+func Quote(lines []Line, rules map[int64]DeliveryRule, coupons ...Coupon) (Breakdown, error) {
+	breakdown := Breakdown{
+		Lines:   make([]LineBreakdown, 0, len(lines)),
+		Stores:  []StoreBreakdown{},
+		Coupons: []CouponBreakdown{},
+	}
+
+	storeIdx := make(map[int64]int)
+	storeQty := make(map[int64]int64)
+	storeWeight := make(map[int64]int64)
+
+	for _, line := range lines {
+		if line.Quantity < 1 {
+			return Breakdown{}, fmt.Errorf("pricing: invalid quantity %d for item %d", line.Quantity, line.ItemID)
+		}
+
+		listedPrice, err := money.Parse(line.Price)
+		if err != nil {
+			return Breakdown{}, err
+		}
+
+		price := listedPrice
+		if line.FxRate != "" {
+			price, err = listedPrice.Scale(line.FxRate)
+			if err != nil {
+				return Breakdown{}, err
+			}
+		}
+
+		unitPrice, err := UnitPrice(price.String(), line.DiscountPercentage)
+		if err != nil {
+			return Breakdown{}, err
+		}
+
+		lb := LineBreakdown{
+			ItemID:             line.ItemID,
+			VariantID:          line.VariantID,
+			StoreID:            line.StoreID,
+			Quantity:           line.Quantity,
+			Currency:           line.Currency,
+			ListedPrice:        listedPrice,
+			FxRate:             line.FxRate,
+			Price:              price,
+			DiscountPercentage: line.DiscountPercentage,
+			UnitPrice:          unitPrice,
+			LineTotal:          unitPrice.Mul(int64(line.Quantity)),
+		}
+		breakdown.Lines = append(breakdown.Lines, lb)
+
+		idx, ok := storeIdx[line.StoreID]
+		if !ok {
+			idx = len(breakdown.Stores)
+			storeIdx[line.StoreID] = idx
+			breakdown.Stores = append(breakdown.Stores, StoreBreakdown{StoreID: line.StoreID})
+		}
+		breakdown.Stores[idx].Subtotal += lb.LineTotal
+		storeQty[line.StoreID] += int64(line.Quantity)
+		storeWeight[line.StoreID] += line.WeightGrams * int64(line.Quantity)
+	}
+
+	if err := applyCoupons(&breakdown, lines, coupons); err != nil {
+		return Breakdown{}, err
+	}
+
+	for _, lb := range breakdown.Lines {
+		breakdown.Stores[storeIdx[lb.StoreID]].Discount += lb.Discount
+	}
+
+	for i := range breakdown.Stores {
+		sb := &breakdown.Stores[i]
+
+		rule := rules[sb.StoreID]
+		sb.ShippingZoneID = rule.ZoneID
+		sb.MinDeliveryDays = rule.MinDays
+		sb.MaxDeliveryDays = rule.MaxDays
+		sb.DeliveryFee = rule.Fee(sb.Subtotal-sb.Discount, storeQty[sb.StoreID], storeWeight[sb.StoreID])
+		sb.Total = sb.Subtotal - sb.Discount + sb.DeliveryFee
+
+		breakdown.Subtotal += sb.Subtotal
+		breakdown.Discount += sb.Discount
+		breakdown.DeliveryFee += sb.DeliveryFee
+	}
+	breakdown.GrandTotal = breakdown.Subtotal - breakdown.Discount + breakdown.DeliveryFee
+
+	// Split each store's delivery fee across its lines.
+	baseCharged := make(map[int64]bool)
+	for i := range breakdown.Lines {
+		lb := &breakdown.Lines[i]
+		if breakdown.Stores[storeIdx[lb.StoreID]].DeliveryFee == money.Zero {
+			continue
+		}
+
+		rule := rules[lb.StoreID]
+		lb.DeliveryFee = rule.PerItemFee.Mul(int64(lb.Quantity))
+		if !baseCharged[lb.StoreID] {
+			lb.DeliveryFee += rule.BaseFee + rule.rateFee(storeQty[lb.StoreID], storeWeight[lb.StoreID])
+			baseCharged[lb.StoreID] = true
+		}
+	}
+
+	return breakdown, nil
+}
+
+// Convert returns b with every amount multiplied by rate, to show b in
+// currency. Each amount is converted on its own, so a converted total can
+// be a minor unit off the sum of its converted parts.
+func (b Breakdown) Convert(currency, rate string) (Breakdown, error) {
+	var err error
+	scale := func(a *money.Amount) {
+		if err == nil {
+			*a, err = a.Scale(rate)
+		}
+	}
+
+	out := b
+	out.Currency = currency
+	out.Lines = append([]LineBreakdown(nil), b.Lines...)
+	out.Stores = append([]StoreBreakdown(nil), b.Stores...)
+	out.Coupons = append([]CouponBreakdown(nil), b.Coupons...)
+
+	for i := range out.Lines {
+		lb := &out.Lines[i]
+		scale(&lb.Price)
+		scale(&lb.UnitPrice)
+		scale(&lb.LineTotal)
+		scale(&lb.Discount)
+		scale(&lb.DeliveryFee)
+		scale(&lb.Tax)
+	}
+	for i := range out.Stores {
+		sb := &out.Stores[i]
+		scale(&sb.Subtotal)
+		scale(&sb.Discount)
+		scale(&sb.DeliveryFee)
+		scale(&sb.Tax)
+		scale(&sb.Total)
+	}
+	for i := range out.Coupons {
+		scale(&out.Coupons[i].Discount)
+	}
+	scale(&out.Subtotal)
+	scale(&out.Discount)
+	scale(&out.DeliveryFee)
+	scale(&out.Tax)
+	scale(&out.GrandTotal)
+
+	return out, err
+}
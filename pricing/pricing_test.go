@@ -0,0 +1,139 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitPrice(t *testing.T) {
+	testCases := []struct {
+		price    string
+		discount string
+		want     money.Amount
+	}{
+		{"1000.00", "0.0000", money.MustParse("1000")},
+		{"1000.00", "", money.MustParse("1000")},
+		{"1000.00", "12.5000", money.MustParse("875")},
+		{"99.99", "33.3333", money.MustParse("66.66")},
+		{"50.00", "100", money.Zero},
+	}
+
+	for _, tc := range testCases {
+		got, err := UnitPrice(tc.price, tc.discount)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got, "%s less %s%%", tc.price, tc.discount)
+	}
+
+	_, err := UnitPrice("50.00", "120")
+	require.Error(t, err)
+
+	_, err = UnitPrice("50.00", "-5")
+	require.Error(t, err)
+}
+
+func TestQuote(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "1000.00", DiscountPercentage: "10.0000", Quantity: 2},
+		{ItemID: 2, StoreID: 20, Price: "250.00", DiscountPercentage: "0.0000", Quantity: 1},
+		{ItemID: 3, StoreID: 10, Price: "300.00", DiscountPercentage: "0.0000", Quantity: 3},
+	}
+	rules := map[int64]DeliveryRule{
+		10: {BaseFee: money.MustParse("500"), PerItemFee: money.MustParse("50")},
+	}
+
+	b, err := Quote(lines, rules)
+	require.NoError(t, err)
+
+	require.Len(t, b.Lines, 3)
+	require.Equal(t, money.MustParse("900"), b.Lines[0].UnitPrice)
+	require.Equal(t, money.MustParse("1800"), b.Lines[0].LineTotal)
+	require.Equal(t, money.MustParse("600"), b.Lines[0].DeliveryFee)
+	require.Equal(t, money.Zero, b.Lines[1].DeliveryFee)
+	require.Equal(t, money.MustParse("150"), b.Lines[2].DeliveryFee)
+
+	require.Len(t, b.Stores, 2)
+	require.Equal(t, int64(10), b.Stores[0].StoreID)
+	require.Equal(t, money.MustParse("2700"), b.Stores[0].Subtotal)
+	require.Equal(t, money.MustParse("750"), b.Stores[0].DeliveryFee)
+	require.Equal(t, money.MustParse("3450"), b.Stores[0].Total)
+	require.Equal(t, money.MustParse("250"), b.Stores[1].Total)
+
+	require.Equal(t, money.MustParse("2950"), b.Subtotal)
+	require.Equal(t, money.MustParse("750"), b.DeliveryFee)
+	require.Equal(t, money.MustParse("3700"), b.GrandTotal)
+
+	var lineFees money.Amount
+	for _, l := range b.Lines {
+		lineFees += l.DeliveryFee
+	}
+	require.Equal(t, b.DeliveryFee, lineFees)
+}
+
+func TestQuoteFreeDelivery(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "5000.00", DiscountPercentage: "0", Quantity: 1},
+	}
+	rules := map[int64]DeliveryRule{
+		10: {BaseFee: money.MustParse("500"), FreeDeliveryThreshold: money.MustParse("5000")},
+	}
+
+	b, err := Quote(lines, rules)
+	require.NoError(t, err)
+	require.Equal(t, money.Zero, b.DeliveryFee)
+	require.Equal(t, money.Zero, b.Lines[0].DeliveryFee)
+	require.Equal(t, money.MustParse("5000"), b.GrandTotal)
+}
+
+func TestQuoteInvalidLine(t *testing.T) {
+	_, err := Quote([]Line{{ItemID: 1, StoreID: 1, Price: "10", Quantity: 0}}, nil)
+	require.Error(t, err)
+
+	_, err = Quote([]Line{{ItemID: 1, StoreID: 1, Price: "ten", Quantity: 1}}, nil)
+	require.Error(t, err)
+}
+
+func TestQuoteFxRate(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "20.00", Currency: "USD", FxRate: "1500", DiscountPercentage: "10", Quantity: 2},
+		{ItemID: 2, StoreID: 10, Price: "1000.00", Currency: "NGN", Quantity: 1},
+	}
+
+	b, err := Quote(lines, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "USD", b.Lines[0].Currency)
+	require.Equal(t, money.MustParse("20"), b.Lines[0].ListedPrice)
+	require.Equal(t, money.MustParse("30000"), b.Lines[0].Price)
+	require.Equal(t, money.MustParse("27000"), b.Lines[0].UnitPrice)
+	require.Equal(t, money.MustParse("54000"), b.Lines[0].LineTotal)
+	require.Equal(t, money.MustParse("1000"), b.Lines[1].Price)
+	require.Equal(t, money.MustParse("55000"), b.GrandTotal)
+}
+
+func TestBreakdownConvert(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "1500.00", Quantity: 2},
+	}
+	rules := map[int64]DeliveryRule{
+		10: {BaseFee: money.MustParse("300")},
+	}
+
+	b, err := Quote(lines, rules)
+	require.NoError(t, err)
+	b.Currency = "NGN"
+
+	usd, err := b.Convert("USD", "1/1500")
+	require.NoError(t, err)
+	require.Equal(t, "USD", usd.Currency)
+	require.Equal(t, money.MustParse("2.00"), usd.Lines[0].LineTotal)
+	require.Equal(t, money.MustParse("0.20"), usd.DeliveryFee)
+	require.Equal(t, money.MustParse("2.20"), usd.GrandTotal)
+
+	// b is left as it was
+	require.Equal(t, money.MustParse("3000"), b.Lines[0].LineTotal)
+
+	_, err = b.Convert("USD", "rate")
+	require.Error(t, err)
+}
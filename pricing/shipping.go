@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// Rate bases say what a shipping zone's rate table is looked up by.
+const (
+	RateFlat      = "FLAT"       // one fee, whatever is shipped
+	RateWeight    = "WEIGHT"     // by the total weight in grams
+	RateItemCount = "ITEM_COUNT" // by the number of units
+)
+
+// A Rate is a row of a rate table: the Fee for shipping From grams or units up.
+type Rate struct {
+	From int64
+	Fee  money.Amount
+}
+
+// A ShippingZone is where a store ships to, a country or a state or city of
+// it, and how it charges for delivery there.
+type ShippingZone struct {
+	ID      int64
+	StoreID int64
+	Country string
+	State   string
+	City    string
+	Rule    DeliveryRule
+}
+
+// matches reports whether the zone covers region.
+func (z ShippingZone) matches(region Region) bool {
+	if !strings.EqualFold(z.Country, region.Country) {
+		return false
+	}
+	if z.State != "" && !strings.EqualFold(z.State, region.State) {
+		return false
+	}
+	return z.City == "" || strings.EqualFold(z.City, region.City)
+}
+
+// specificity ranks zones covering the same region: a city beats a state,
+// which beats a country.
+func (z ShippingZone) specificity() int {
+	n := 0
+	if z.State != "" {
+		n++
+	}
+	if z.City != "" {
+		n += 2
+	}
+	return n
+}
+
+// MatchShippingZone returns the most specific of zones covering region.
+func MatchShippingZone(zones []ShippingZone, region Region) (ShippingZone, bool) {
+	var match ShippingZone
+	found := false
+	for _, z := range zones {
+		if !z.matches(region) {
+			continue
+		}
+		if !found || z.specificity() > match.specificity() {
+			match = z
+			found = true
+		}
+	}
+	return match, found
+}
+
+// rateFee looks up the fee for qty units weighing weight grams in the rule's
+// rate table, charging the row with the highest From reached. Anything below
+// the first row is charged at it.
+func (r DeliveryRule) rateFee(qty, weight int64) money.Amount {
+	if len(r.Rates) == 0 {
+		return money.Zero
+	}
+
+	var measure int64
+	switch r.Basis {
+	case RateWeight:
+		measure = weight
+	case RateItemCount:
+		measure = qty
+	}
+
+	rates := append([]Rate(nil), r.Rates...)
+	sort.Slice(rates, func(i, j int) bool { return rates[i].From < rates[j].From })
+
+	fee := rates[0].Fee
+	for _, rate := range rates[1:] {
+		if measure < rate.From {
+			break
+		}
+		fee = rate.Fee
+	}
+	return fee
+}
@@ -0,0 +1,96 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchShippingZone(t *testing.T) {
+	zones := []ShippingZone{
+		{ID: 1, Country: "NG"},
+		{ID: 2, Country: "NG", State: "Lagos"},
+		{ID: 3, Country: "NG", State: "Lagos", City: "Ikeja"},
+		{ID: 4, Country: "NG", City: "Abuja"},
+	}
+
+	testCases := []struct {
+		region Region
+		wantID int64
+	}{
+		{Region{Country: "NG", State: "Kano", City: "Kano"}, 1},
+		{Region{Country: "NG", State: "Lagos", City: "Lekki"}, 2},
+		{Region{Country: "ng", State: "lagos", City: "ikeja"}, 3},
+		{Region{Country: "NG", State: "FCT", City: "Abuja"}, 4},
+	}
+
+	for _, tc := range testCases {
+		zone, ok := MatchShippingZone(zones, tc.region)
+		require.True(t, ok)
+		require.Equal(t, tc.wantID, zone.ID, "%+v", tc.region)
+	}
+
+	_, ok := MatchShippingZone(zones, Region{Country: "GH"})
+	require.False(t, ok)
+}
+
+func TestDeliveryRuleFee(t *testing.T) {
+	byWeight := DeliveryRule{
+		Basis: RateWeight,
+		Rates: []Rate{
+			{From: 5000, Fee: money.MustParse("2500")},
+			{From: 0, Fee: money.MustParse("1000")},
+			{From: 1000, Fee: money.MustParse("1500")},
+		},
+		FreeDeliveryThreshold: money.MustParse("50000"),
+	}
+
+	testCases := []struct {
+		rule     DeliveryRule
+		subtotal string
+		qty      int64
+		weight   int64
+		want     money.Amount
+	}{
+		{byWeight, "1000", 1, 500, money.MustParse("1000")},
+		{byWeight, "1000", 1, 1000, money.MustParse("1500")},
+		{byWeight, "1000", 3, 12000, money.MustParse("2500")},
+		{byWeight, "50000", 3, 12000, money.Zero},
+		{DeliveryRule{Basis: RateItemCount, Rates: []Rate{{From: 1, Fee: money.MustParse("800")}, {From: 4, Fee: money.MustParse("1200")}}}, "1000", 5, 0, money.MustParse("1200")},
+		{DeliveryRule{Basis: RateFlat, Rates: []Rate{{Fee: money.MustParse("700")}}}, "1000", 9, 9000, money.MustParse("700")},
+		{DeliveryRule{BaseFee: money.MustParse("500"), PerItemFee: money.MustParse("50")}, "1000", 2, 0, money.MustParse("600")},
+	}
+
+	for i, tc := range testCases {
+		got := tc.rule.Fee(money.MustParse(tc.subtotal), tc.qty, tc.weight)
+		require.Equal(t, tc.want, got, "case %d", i)
+	}
+}
+
+func TestQuoteShippingZone(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "1000.00", Quantity: 2, WeightGrams: 400},
+		{ItemID: 2, StoreID: 10, Price: "500.00", Quantity: 1, WeightGrams: 300},
+	}
+	rules := map[int64]DeliveryRule{
+		10: {
+			ZoneID:  7,
+			Basis:   RateWeight,
+			Rates:   []Rate{{From: 0, Fee: money.MustParse("1000")}, {From: 1000, Fee: money.MustParse("1500")}},
+			MinDays: 1,
+			MaxDays: 2,
+		},
+	}
+
+	b, err := Quote(lines, rules)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(7), b.Stores[0].ShippingZoneID)
+	require.Equal(t, int32(1), b.Stores[0].MinDeliveryDays)
+	require.Equal(t, int32(2), b.Stores[0].MaxDeliveryDays)
+	require.Equal(t, money.MustParse("1500"), b.Stores[0].DeliveryFee)
+	require.Equal(t, money.MustParse("1500"), b.Lines[0].DeliveryFee)
+	require.Equal(t, money.Zero, b.Lines[1].DeliveryFee)
+	require.Equal(t, money.MustParse("4000"), b.GrandTotal)
+}
@@ -0,0 +1,139 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/OCD-Labs/store-hub/money"
+)
+
+// A Region is where an order ships to.
+type Region struct {
+	Country string // ISO-3166 alpha-2 code, e.g. "NG"
+	State   string
+	City    string
+}
+
+// A TaxRule is the tax a store charges on sales shipped to a region. A
+// rule without State covers the whole country, and one without Category
+// covers every item.
+type TaxRule struct {
+	ID        int64
+	StoreID   int64
+	Name      string // e.g. "VAT"
+	Country   string
+	State     string
+	Category  string
+	Rate      string // percentage, e.g. "7.5"
+	Inclusive bool   // the price already includes the tax
+}
+
+// matches reports whether the rule taxes an item in category shipped to region.
+func (r TaxRule) matches(region Region, category string) bool {
+	if !strings.EqualFold(r.Country, region.Country) {
+		return false
+	}
+	if r.State != "" && !strings.EqualFold(r.State, region.State) {
+		return false
+	}
+	return r.Category == "" || r.Category == category
+}
+
+// specificity ranks rules that match the same line: a state beats a
+// country, then a category beats every item.
+func (r TaxRule) specificity() int {
+	n := 0
+	if r.State != "" {
+		n += 2
+	}
+	if r.Category != "" {
+		n++
+	}
+	return n
+}
+
+// MatchTaxRule returns the most specific of rules taxing an item in
+// category shipped to region.
+func MatchTaxRule(rules []TaxRule, region Region, category string) (TaxRule, bool) {
+	var match TaxRule
+	found := false
+	for _, r := range rules {
+		if !r.matches(region, category) {
+			continue
+		}
+		if !found || r.specificity() > match.specificity() {
+			match = r
+			found = true
+		}
+	}
+	return match, found
+}
+
+// Tax returns the tax on amount. An inclusive rule takes the tax out of
+// amount, an exclusive one charges it on top.
+func (r TaxRule) Tax(amount money.Amount) (money.Amount, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(r.Rate))
+	if !ok || rate.Sign() < 0 {
+		return money.Zero, fmt.Errorf("pricing: invalid tax rate %q", r.Rate)
+	}
+
+	if !r.Inclusive {
+		return amount.Percent(r.Rate)
+	}
+
+	// amount is net * (100 + rate) / 100
+	net := new(big.Rat).Quo(big.NewRat(100, 1), new(big.Rat).Add(big.NewRat(100, 1), rate))
+	netAmount, err := amount.Scale(net.RatString())
+	if err != nil {
+		return money.Zero, err
+	}
+	return amount - netAmount, nil
+}
+
+// ApplyTax taxes each line of b, priced from lines, at the most specific
+// of its store's rules for region. A line is taxed on what it costs after
+// coupons; delivery is not taxed. Exclusive taxes are added to the totals.
+func ApplyTax(b Breakdown, lines []Line, rules map[int64][]TaxRule, region Region) (Breakdown, error) {
+	if len(lines) != len(b.Lines) {
+		return Breakdown{}, fmt.Errorf("pricing: %d lines priced, %d given", len(b.Lines), len(lines))
+	}
+
+	out := b
+	out.Lines = append([]LineBreakdown(nil), b.Lines...)
+	out.Stores = append([]StoreBreakdown(nil), b.Stores...)
+
+	storeIdx := make(map[int64]int, len(out.Stores))
+	for i, sb := range out.Stores {
+		storeIdx[sb.StoreID] = i
+	}
+
+	for i := range out.Lines {
+		lb := &out.Lines[i]
+
+		rule, ok := MatchTaxRule(rules[lb.StoreID], region, lines[i].Category)
+		if !ok {
+			continue
+		}
+
+		tax, err := rule.Tax(lb.LineTotal - lb.Discount)
+		if err != nil {
+			return Breakdown{}, err
+		}
+
+		lb.TaxRuleID = rule.ID
+		lb.TaxRate = rule.Rate
+		lb.TaxInclusive = rule.Inclusive
+		lb.Tax = tax
+
+		sb := &out.Stores[storeIdx[lb.StoreID]]
+		sb.Tax += tax
+		out.Tax += tax
+		if !rule.Inclusive {
+			sb.Total += tax
+			out.GrandTotal += tax
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTaxRule(t *testing.T) {
+	rules := []TaxRule{
+		{ID: 1, Country: "NG", Rate: "7.5"},
+		{ID: 2, Country: "NG", Category: "books", Rate: "0"},
+		{ID: 3, Country: "NG", State: "Lagos", Rate: "10"},
+		{ID: 4, Country: "GH", Rate: "15"},
+	}
+
+	testCases := []struct {
+		region   Region
+		category string
+		wantID   int64
+	}{
+		{Region{Country: "NG", State: "Abuja"}, "shoes", 1},
+		{Region{Country: "NG", State: "Abuja"}, "books", 2},
+		{Region{Country: "ng", State: "lagos"}, "books", 3},
+		{Region{Country: "GH"}, "shoes", 4},
+	}
+
+	for _, tc := range testCases {
+		rule, ok := MatchTaxRule(rules, tc.region, tc.category)
+		require.True(t, ok)
+		require.Equal(t, tc.wantID, rule.ID, "%+v %s", tc.region, tc.category)
+	}
+
+	_, ok := MatchTaxRule(rules, Region{Country: "US"}, "shoes")
+	require.False(t, ok)
+}
+
+func TestTaxRuleTax(t *testing.T) {
+	tax, err := TaxRule{Rate: "7.5"}.Tax(money.MustParse("1000"))
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("75"), tax)
+
+	tax, err = TaxRule{Rate: "7.5", Inclusive: true}.Tax(money.MustParse("1075"))
+	require.NoError(t, err)
+	require.Equal(t, money.MustParse("75"), tax)
+
+	tax, err = TaxRule{Rate: "0", Inclusive: true}.Tax(money.MustParse("1000"))
+	require.NoError(t, err)
+	require.Equal(t, money.Zero, tax)
+
+	_, err = TaxRule{Rate: "-5"}.Tax(money.MustParse("1000"))
+	require.Error(t, err)
+
+	_, err = TaxRule{Rate: "abc"}.Tax(money.MustParse("1000"))
+	require.Error(t, err)
+}
+
+func TestApplyTax(t *testing.T) {
+	lines := []Line{
+		{ItemID: 1, StoreID: 10, Price: "1000.00", Category: "shoes", Quantity: 2},
+		{ItemID: 2, StoreID: 20, Price: "1075.00", Category: "shoes", Quantity: 1},
+		{ItemID: 3, StoreID: 10, Price: "300.00", Category: "books", Quantity: 1},
+	}
+	deliveryRules := map[int64]DeliveryRule{
+		10: {BaseFee: money.MustParse("500")},
+	}
+	taxRules := map[int64][]TaxRule{
+		10: {
+			{ID: 1, Country: "NG", Rate: "10"},
+			{ID: 2, Country: "NG", Category: "books", Rate: "0"},
+		},
+		20: {{ID: 3, Country: "NG", Rate: "7.5", Inclusive: true}},
+	}
+
+	b, err := Quote(lines, deliveryRules)
+	require.NoError(t, err)
+
+	taxed, err := ApplyTax(b, lines, taxRules, Region{Country: "NG", State: "Lagos"})
+	require.NoError(t, err)
+
+	require.Equal(t, money.MustParse("200"), taxed.Lines[0].Tax)
+	require.False(t, taxed.Lines[0].TaxInclusive)
+	require.Equal(t, money.MustParse("2700"), taxed.Lines[0].Total())
+	require.Equal(t, money.MustParse("75"), taxed.Lines[1].Tax)
+	require.True(t, taxed.Lines[1].TaxInclusive)
+	require.Equal(t, money.MustParse("1075"), taxed.Lines[1].Total())
+	require.Equal(t, int64(2), taxed.Lines[2].TaxRuleID)
+	require.Equal(t, money.Zero, taxed.Lines[2].Tax)
+
+	require.Equal(t, money.MustParse("200"), taxed.Stores[0].Tax)
+	require.Equal(t, money.MustParse("3000"), taxed.Stores[0].Total)
+	require.Equal(t, money.MustParse("75"), taxed.Stores[1].Tax)
+	require.Equal(t, money.MustParse("1075"), taxed.Stores[1].Total)
+	require.Equal(t, money.MustParse("275"), taxed.Tax)
+	require.Equal(t, b.GrandTotal+money.MustParse("200"), taxed.GrandTotal)
+
+	// b is left as it was.
+	require.Equal(t, money.Zero, b.Tax)
+	require.Equal(t, money.Zero, b.Lines[0].Tax)
+
+	untaxed, err := ApplyTax(b, lines, taxRules, Region{Country: "US"})
+	require.NoError(t, err)
+	require.Equal(t, b.GrandTotal, untaxed.GrandTotal)
+	require.Equal(t, money.Zero, untaxed.Tax)
+
+	_, err = ApplyTax(b, lines[:1], taxRules, Region{Country: "NG"})
+	require.Error(t, err)
+}
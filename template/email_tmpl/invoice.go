@@ -0,0 +1,61 @@
+package email_tmpl
+
+import "fmt"
+
+func InvoiceTmpl(buyerName, storeName, invoiceNumber, orderNumber, total string) string {
+	cnt := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html lang="en">
+		<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>Your Invoice</title>
+		<style>
+				body {
+						font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+						margin: 0;
+						padding: 0;
+						background-color: #f7f7f7;
+						color: #333;
+				}
+				.container {
+						max-width: 560px;
+						margin: 40px auto;
+						padding: 20px;
+						background-color: #ffffff;
+						border: 1px solid #dedede;
+						border-radius: 5px;
+						box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+				}
+				.content {
+						text-align: center;
+						line-height: 1.5;
+				}
+				.total {
+						font-size: 20px;
+						font-weight: bold;
+				}
+				.footer {
+						margin-top: 20px;
+						font-size: 12px;
+						text-align: center;
+						color: #999;
+				}
+		</style>
+		</head>
+		<body>
+		<div class="container">
+				<div class="content">
+						<h2>Hello %s,</h2>
+						<p>Thank you for shopping with %s on StoreHub. Your invoice %s for order %s is attached.</p>
+						<p class="total">%s</p>
+				</div>
+				<div class="footer">
+					Keep this invoice for your records. If you have questions about your order, please contact the store or our support.
+				</div>
+		</div>
+		</body>
+		</html>
+	`, buyerName, storeName, invoiceNumber, orderNumber, total)
+	return cnt
+}
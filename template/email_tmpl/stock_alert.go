@@ -0,0 +1,123 @@
+package email_tmpl
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// A StockLine is an item listed in a stock alert or digest.
+type StockLine struct {
+	Name           string
+	SKU            string
+	SupplyQuantity int64
+	Threshold      int32
+	Status         string // e.g. "Out of stock"
+}
+
+func StockAlertTmpl(storeName string, lines []StockLine) string {
+	return stockTmpl(
+		"Stock alert",
+		fmt.Sprintf("These items of %s just ran low or out of stock. Items out of stock are hidden from the storefront until they're restocked.", html.EscapeString(storeName)),
+		lines,
+	)
+}
+
+func StockDigestTmpl(storeName string, lines []StockLine) string {
+	return stockTmpl(
+		"Items at risk",
+		fmt.Sprintf("These items of %s are at or below their reorder threshold, or sold more in the last week than they have left.", html.EscapeString(storeName)),
+		lines,
+	)
+}
+
+func stockTmpl(title, intro string, lines []StockLine) string {
+	var rows strings.Builder
+	for _, line := range lines {
+		fmt.Fprintf(&rows, `
+						<tr>
+								<td>%s</td>
+								<td>%s</td>
+								<td class="number">%d</td>
+								<td class="number">%d</td>
+								<td>%s</td>
+						</tr>`,
+			html.EscapeString(line.Name),
+			html.EscapeString(line.SKU),
+			line.SupplyQuantity,
+			line.Threshold,
+			html.EscapeString(line.Status),
+		)
+	}
+
+	cnt := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html lang="en">
+		<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=1.0">
+		<title>%s</title>
+		<style>
+				body {
+						font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif;
+						margin: 0;
+						padding: 0;
+						background-color: #f7f7f7;
+						color: #333;
+				}
+				.container {
+						max-width: 640px;
+						margin: 40px auto;
+						padding: 20px;
+						background-color: #ffffff;
+						border: 1px solid #dedede;
+						border-radius: 5px;
+						box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+				}
+				.content {
+						line-height: 1.5;
+				}
+				table {
+						width: 100%%;
+						border-collapse: collapse;
+				}
+				th, td {
+						padding: 6px;
+						border-bottom: 1px solid #dedede;
+						text-align: left;
+				}
+				.number {
+						text-align: right;
+				}
+				.footer {
+						margin-top: 20px;
+						font-size: 12px;
+						text-align: center;
+						color: #999;
+				}
+		</style>
+		</head>
+		<body>
+		<div class="container">
+				<div class="content">
+						<h2>%s</h2>
+						<p>%s</p>
+						<table>
+						<tr>
+								<th>Item</th>
+								<th>SKU</th>
+								<th class="number">In stock</th>
+								<th class="number">Threshold</th>
+								<th></th>
+						</tr>%s
+						</table>
+				</div>
+				<div class="footer">
+					You're receiving this as staff of the store with product inventory access. Set the thresholds in the store's inventory settings.
+				</div>
+		</div>
+		</body>
+		</html>
+	`, title, title, intro, rows.String())
+	return cnt
+}
@@ -1,6 +1,11 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 // A Configs defines the expected config values.
 type Configs struct {
@@ -25,8 +30,50 @@ type Configs struct {
 	NEARNetwork   string `mapstructure:"NEAR_NETWORK"`
 	NEARPubKey    string `mapstructure:"NEAR_ACCOUNT_PUB_KEY"`
 	NEARPrivKey   string `mapstructure:"NEAR_ACCOUNT_PRIV_KEY"`
+	NEARRPCURL    string `mapstructure:"NEAR_RPC_URL"`
+	NEARPrice     string `mapstructure:"NEAR_PRICE"` // price of 1 NEAR in the store currency
+
+	PaystackSecretKey   string `mapstructure:"PAYSTACK_SECRET_KEY"`
+	PaystackBaseURL     string `mapstructure:"PAYSTACK_BASE_URL"`
+	PaystackCallbackURL string `mapstructure:"PAYSTACK_CALLBACK_URL"`
+
+	PlatformAdmins []string `mapstructure:"PLATFORM_ADMINS"` // account IDs allowed to manage platform settings
+
+	FXRatesFile string `mapstructure:"FX_RATES_FILE"` // JSON file exchange rates are refreshed from
+
+	ReconcileTransactionsSchedule string        `mapstructure:"RECONCILE_TRANSACTIONS_SCHEDULE"` // cron spec, e.g. "@every 15m"
+	StuckTransactionAge           time.Duration `mapstructure:"STUCK_TRANSACTION_AGE"`           // PROCESSING for longer is reconciled
+	AbandonedTransactionAge       time.Duration `mapstructure:"ABANDONED_TRANSACTION_AGE"`       // unpaid for longer is failed by reconciliation
+
+	StockReservationTTL time.Duration `mapstructure:"STOCK_RESERVATION_TTL"` // how long checkout holds a cart's stock
+
+	ReconcileFlashSalesSchedule string `mapstructure:"RECONCILE_FLASH_SALES_SCHEDULE"` // cron spec, e.g. "@every 1m"
+
+	StockAlertsSchedule string `mapstructure:"STOCK_ALERTS_SCHEDULE"` // cron spec, e.g. "@every 5m"
+	StockDigestSchedule string `mapstructure:"STOCK_DIGEST_SCHEDULE"` // cron spec, e.g. "0 7 * * *"
+
+	StorageDriver    string `mapstructure:"STORAGE_DRIVER"`     // where uploaded images are kept: "local" or "s3"
+	StorageLocalDir  string `mapstructure:"STORAGE_LOCAL_DIR"`  // directory uploads are kept in by the "local" driver
+	StoragePublicURL string `mapstructure:"STORAGE_PUBLIC_URL"` // base URL the "local" driver's uploads are served at
+
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"` // e.g. "https://s3.eu-west-1.amazonaws.com"
+	S3Region          string `mapstructure:"S3_REGION"`
+	S3Bucket          string `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID     string `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	S3PublicURL       string `mapstructure:"S3_PUBLIC_URL"`       // base URL the bucket is served at, e.g. a CDN; optional
+	S3ForcePathStyle  bool   `mapstructure:"S3_FORCE_PATH_STYLE"` // true for MinIO and other services without bucket subdomains
 }
 
+// defaultStuckTransactionAge is how long a transaction stays PROCESSING before
+// it's reconciled, when STUCK_TRANSACTION_AGE isn't set.
+const defaultStuckTransactionAge = 30 * time.Minute
+
+// defaultAbandonedTransactionAge is how long a transaction the buyer hasn't
+// paid yet is left to them before reconciliation fails it, when
+// ABANDONED_TRANSACTION_AGE isn't set.
+const defaultAbandonedTransactionAge = 24 * time.Hour
+
 // ParseConfigs parses the configuration files.
 func ParseConfigs(path string) (config Configs, err error) {
 	viper.AddConfigPath(path)
@@ -34,6 +81,8 @@ func ParseConfigs(path string) (config Configs, err error) {
 	viper.SetConfigType("env")
 
 	viper.AutomaticEnv()
+	viper.SetDefault("STUCK_TRANSACTION_AGE", defaultStuckTransactionAge)
+	viper.SetDefault("ABANDONED_TRANSACTION_AGE", defaultAbandonedTransactionAge)
 
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -41,5 +90,19 @@ func ParseConfigs(path string) (config Configs, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	// Reconciling transactions no older than now would fail ones the buyer is still paying.
+	if config.StuckTransactionAge <= 0 {
+		err = fmt.Errorf("STUCK_TRANSACTION_AGE must be positive, got %s", config.StuckTransactionAge)
+		return
+	}
+
+	// A buyer can leave the payment page open for a while before paying.
+	if config.AbandonedTransactionAge <= config.StuckTransactionAge {
+		err = fmt.Errorf("ABANDONED_TRANSACTION_AGE must be longer than STUCK_TRANSACTION_AGE, got %s", config.AbandonedTransactionAge)
+	}
 	return
 }
@@ -0,0 +1,147 @@
+// Package variant checks the versions an item is sold in, such as a
+// T-shirt's sizes and colours.
+//
+// An item's options are its axes, e.g. Size with S, M and L, and each of
+// its variants picks one value of every option. No two variants of an item
+// pick the same values.
+package variant
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Limits on an item's options, keeping its variant matrix small enough to list.
+const (
+	MaxOptions = 3
+	MaxValues  = 50
+)
+
+var (
+	ErrInvalidOption  = errors.New("variant: invalid option")
+	ErrInvalidVariant = errors.New("variant: invalid variant")
+)
+
+// An Option is an axis an item's variants differ along.
+type Option struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Values are the option values a variant picks, by option name.
+type Values map[string]string
+
+// Key identifies the combination of values, whatever order they're given in.
+func (v Values) Key() string {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(v[name])
+	}
+	return b.String()
+}
+
+// Label describes the values in the order of options, e.g. "M / Red".
+func (v Values) Label(options []Option) string {
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		if value, ok := v[option.Name]; ok {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// ValidateOptions checks options have distinct, non-empty names, each with
+// distinct, non-empty values.
+func ValidateOptions(options []Option) error {
+	if len(options) > MaxOptions {
+		return fmt.Errorf("%w: at most %d options", ErrInvalidOption, MaxOptions)
+	}
+
+	names := make(map[string]bool, len(options))
+	for _, option := range options {
+		if strings.TrimSpace(option.Name) == "" {
+			return fmt.Errorf("%w: missing name", ErrInvalidOption)
+		}
+		if names[option.Name] {
+			return fmt.Errorf("%w: %q is repeated", ErrInvalidOption, option.Name)
+		}
+		names[option.Name] = true
+
+		if len(option.Values) == 0 || len(option.Values) > MaxValues {
+			return fmt.Errorf("%w: %q needs 1 to %d values", ErrInvalidOption, option.Name, MaxValues)
+		}
+
+		values := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%w: %q has an empty value", ErrInvalidOption, option.Name)
+			}
+			if values[value] {
+				return fmt.Errorf("%w: %q repeats %q", ErrInvalidOption, option.Name, value)
+			}
+			values[value] = true
+		}
+	}
+
+	return nil
+}
+
+// Validate checks options, and that each of variants picks one of the values
+// of every option and no other, and no two pick the same values. An item
+// with variants must have options.
+func Validate(options []Option, variants []Values) error {
+	if err := ValidateOptions(options); err != nil {
+		return err
+	}
+
+	if len(variants) > 0 && len(options) == 0 {
+		return fmt.Errorf("%w: variants need options", ErrInvalidVariant)
+	}
+
+	allowed := make(map[string]map[string]bool, len(options))
+	for _, option := range options {
+		allowed[option.Name] = make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			allowed[option.Name][value] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(variants))
+	for _, values := range variants {
+		if len(values) != len(options) {
+			return fmt.Errorf("%w: %q must pick a value of every option", ErrInvalidVariant, values.Key())
+		}
+
+		for name, value := range values {
+			option, ok := allowed[name]
+			if !ok {
+				return fmt.Errorf("%w: no option %q", ErrInvalidVariant, name)
+			}
+			if !option[value] {
+				return fmt.Errorf("%w: %q is not a value of %q", ErrInvalidVariant, value, name)
+			}
+		}
+
+		key := values.Key()
+		if seen[key] {
+			return fmt.Errorf("%w: %q is repeated", ErrInvalidVariant, key)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
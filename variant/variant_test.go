@@ -0,0 +1,126 @@
+package variant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuesKey(t *testing.T) {
+	a := Values{"Size": "M", "Colour": "Red"}
+	b := Values{"Colour": "Red", "Size": "M"}
+
+	require.Equal(t, "Colour=Red/Size=M", a.Key())
+	require.Equal(t, a.Key(), b.Key())
+	require.NotEqual(t, a.Key(), Values{"Size": "L", "Colour": "Red"}.Key())
+}
+
+func TestValuesLabel(t *testing.T) {
+	options := []Option{
+		{Name: "Size", Values: []string{"S", "M"}},
+		{Name: "Colour", Values: []string{"Red", "Blue"}},
+	}
+
+	require.Equal(t, "M / Red", Values{"Colour": "Red", "Size": "M"}.Label(options))
+	require.Equal(t, "", Values{}.Label(options))
+}
+
+func TestValidate(t *testing.T) {
+	options := []Option{
+		{Name: "Size", Values: []string{"S", "M", "L"}},
+		{Name: "Colour", Values: []string{"Red", "Blue"}},
+	}
+
+	testCases := []struct {
+		name     string
+		options  []Option
+		variants []Values
+		err      error
+	}{
+		{
+			name:    "Valid",
+			options: options,
+			variants: []Values{
+				{"Size": "S", "Colour": "Red"},
+				{"Size": "M", "Colour": "Red"},
+				{"Size": "M", "Colour": "Blue"},
+			},
+		},
+		{
+			name: "NoOptionsNoVariants",
+		},
+		{
+			name:     "VariantsWithoutOptions",
+			variants: []Values{{}},
+			err:      ErrInvalidVariant,
+		},
+		{
+			name:    "RepeatedOption",
+			options: []Option{{Name: "Size", Values: []string{"S"}}, {Name: "Size", Values: []string{"M"}}},
+			err:     ErrInvalidOption,
+		},
+		{
+			name:    "OptionWithoutValues",
+			options: []Option{{Name: "Size"}},
+			err:     ErrInvalidOption,
+		},
+		{
+			name:    "RepeatedValue",
+			options: []Option{{Name: "Size", Values: []string{"S", "S"}}},
+			err:     ErrInvalidOption,
+		},
+		{
+			name:    "EmptyName",
+			options: []Option{{Name: " ", Values: []string{"S"}}},
+			err:     ErrInvalidOption,
+		},
+		{
+			name: "TooManyOptions",
+			options: []Option{
+				{Name: "A", Values: []string{"1"}},
+				{Name: "B", Values: []string{"1"}},
+				{Name: "C", Values: []string{"1"}},
+				{Name: "D", Values: []string{"1"}},
+			},
+			err: ErrInvalidOption,
+		},
+		{
+			name:     "MissingOption",
+			options:  options,
+			variants: []Values{{"Size": "S"}},
+			err:      ErrInvalidVariant,
+		},
+		{
+			name:     "UnknownOption",
+			options:  options,
+			variants: []Values{{"Size": "S", "Fit": "Slim"}},
+			err:      ErrInvalidVariant,
+		},
+		{
+			name:     "UnknownValue",
+			options:  options,
+			variants: []Values{{"Size": "XL", "Colour": "Red"}},
+			err:      ErrInvalidVariant,
+		},
+		{
+			name:    "RepeatedVariant",
+			options: options,
+			variants: []Values{
+				{"Size": "S", "Colour": "Red"},
+				{"Colour": "Red", "Size": "S"},
+			},
+			err: ErrInvalidVariant,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.options, tc.variants)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
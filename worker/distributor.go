@@ -26,6 +26,42 @@ type TaskDistributor interface {
 		args *PayloadNEARTx,
 		opts ...asynq.Option,
 	) error
+
+	DistributeTaskExpireStockReservations(
+		ctx context.Context,
+		payload *PayloadExpireStockReservations,
+		opts ...asynq.Option,
+	) error
+
+	DistributeTaskSendInvoices(
+		ctx context.Context,
+		payload *PayloadSendInvoices,
+		opts ...asynq.Option,
+	) error
+
+	DistributeTaskImportItems(
+		ctx context.Context,
+		payload *PayloadImportItems,
+		opts ...asynq.Option,
+	) error
+
+	DistributeTaskSendNEARTransfer(
+		ctx context.Context,
+		payload *PayloadSendNEARTransfer,
+		opts ...asynq.Option,
+	) error
+
+	DistributeTaskSendCheckoutRefund(
+		ctx context.Context,
+		payload *PayloadSendCheckoutRefund,
+		opts ...asynq.Option,
+	) error
+
+	DistributeTaskSendRefund(
+		ctx context.Context,
+		payload *PayloadSendRefund,
+		opts ...asynq.Option,
+	) error
 }
 
 // RedisTaskDistributor defines and wrap a asynq client
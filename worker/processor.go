@@ -3,9 +3,12 @@ package worker
 import (
 	"context"
 
+	"github.com/OCD-Labs/store-hub/cache"
 	db "github.com/OCD-Labs/store-hub/db/sqlc"
 	"github.com/OCD-Labs/store-hub/logger"
 	"github.com/OCD-Labs/store-hub/mailer"
+	"github.com/OCD-Labs/store-hub/near"
+	"github.com/OCD-Labs/store-hub/payment"
 	"github.com/OCD-Labs/store-hub/token"
 	"github.com/OCD-Labs/store-hub/util"
 	"github.com/go-redis/redis/v8"
@@ -36,23 +39,60 @@ type TaskProcessor interface {
 		ctx context.Context,
 		task *asynq.Task,
 	) error
+
+	// ProcessTaskReconcileTransactions processes a 'TaskReconcileTransactions' task.
+	ProcessTaskReconcileTransactions(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskExpireStockReservations processes a 'TaskExpireStockReservations' task.
+	ProcessTaskExpireStockReservations(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskSendInvoices processes a 'TaskSendInvoices' task.
+	ProcessTaskSendInvoices(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskReconcileFlashSales processes a 'TaskReconcileFlashSales' task.
+	ProcessTaskReconcileFlashSales(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskImportItems processes a 'TaskImportItems' task.
+	ProcessTaskImportItems(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskSendStockAlerts processes a 'TaskSendStockAlerts' task.
+	ProcessTaskSendStockAlerts(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskSendStockDigest processes a 'TaskSendStockDigest' task.
+	ProcessTaskSendStockDigest(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskSendNEARTransfer processes a 'TaskSendNEARTransfer' task.
+	ProcessTaskSendNEARTransfer(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskSendCheckoutRefund processes a 'TaskSendCheckoutRefund' task.
+	ProcessTaskSendCheckoutRefund(ctx context.Context, task *asynq.Task) error
+
+	// ProcessTaskSendRefund processes a 'TaskSendRefund' task.
+	ProcessTaskSendRefund(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
-	server     *asynq.Server
-	dbStore    db.StoreTx
-	configs    util.Configs
-	mailer     mailer.EmailSender
-	tokenMaker token.Maker
+	server          *asynq.Server
+	dbStore         db.StoreTx
+	cache           cache.Cache
+	configs         util.Configs
+	mailer          mailer.EmailSender
+	tokenMaker      token.Maker
+	nearAccount     *near.Account
+	paymentProvider payment.Provider
+	distributor     TaskDistributor
 }
 
 // NewRedisTaskProcessor creates a new RedisTaskProcessor.
 func NewRedisTaskProcessor(
 	redisOpt asynq.RedisClientOpt,
 	dbStore db.StoreTx,
+	cache cache.Cache,
 	mailer mailer.EmailSender,
 	configs util.Configs,
 	tokenMaker token.Maker,
+	nearAccount *near.Account,
+	paymentProvider payment.Provider,
 ) TaskProcessor {
 	logger := logger.New()
 	redis.SetLogger(logger)
@@ -72,11 +112,15 @@ func NewRedisTaskProcessor(
 	})
 
 	return &RedisTaskProcessor{
-		server:     server,
-		dbStore:    dbStore,
-		configs:    configs,
-		mailer:     mailer,
-		tokenMaker: tokenMaker,
+		server:          server,
+		dbStore:         dbStore,
+		cache:           cache,
+		configs:         configs,
+		mailer:          mailer,
+		tokenMaker:      tokenMaker,
+		nearAccount:     nearAccount,
+		paymentProvider: paymentProvider,
+		distributor:     NewRedisTaskDistributor(redisOpt),
 	}
 }
 
@@ -86,6 +130,16 @@ func (processor *RedisTaskProcessor) Start() error {
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
 	mux.HandleFunc(TaskSendAccessInvitationEmail, processor.ProcessTaskSendAccessInvitation)
 	mux.HandleFunc(TaskNEARTx, processor.ProcessTaskNEARTx)
+	mux.HandleFunc(TaskReconcileTransactions, processor.ProcessTaskReconcileTransactions)
+	mux.HandleFunc(TaskExpireStockReservations, processor.ProcessTaskExpireStockReservations)
+	mux.HandleFunc(TaskSendInvoices, processor.ProcessTaskSendInvoices)
+	mux.HandleFunc(TaskReconcileFlashSales, processor.ProcessTaskReconcileFlashSales)
+	mux.HandleFunc(TaskImportItems, processor.ProcessTaskImportItems)
+	mux.HandleFunc(TaskSendStockAlerts, processor.ProcessTaskSendStockAlerts)
+	mux.HandleFunc(TaskSendStockDigest, processor.ProcessTaskSendStockDigest)
+	mux.HandleFunc(TaskSendNEARTransfer, processor.ProcessTaskSendNEARTransfer)
+	mux.HandleFunc(TaskSendCheckoutRefund, processor.ProcessTaskSendCheckoutRefund)
+	mux.HandleFunc(TaskSendRefund, processor.ProcessTaskSendRefund)
 	// mux.HandleFunc(TaskSendResetPasswordEmail, processor.ProcessTaskSendResetPasswordEmail)
 	return processor.server.Start(mux)
 }
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/OCD-Labs/store-hub/logger"
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// TaskScheduler is an interface for a worker that enqueues periodic tasks.
+type TaskScheduler interface {
+	// Start starts the RedisTaskScheduler.
+	Start() error
+}
+
+// RedisTaskScheduler wraps an asynq scheduler that enqueues
+// periodic tasks for a RedisTaskProcessor to process.
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+}
+
+// NewRedisTaskScheduler creates a new RedisTaskScheduler, with the
+// periodic tasks in configs registered.
+func NewRedisTaskScheduler(redisOpt asynq.RedisClientOpt, configs util.Configs) (TaskScheduler, error) {
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
+		Logger: logger.New(),
+		PostEnqueueFunc: func(info *asynq.TaskInfo, err error) {
+			if err != nil {
+				log.Error().Err(err).Msg("failed to enqueue periodic task")
+				return
+			}
+			log.Info().Str("type", info.Type).Str("queue", info.Queue).Msg("enqueued periodic task")
+		},
+	})
+
+	if configs.ReconcileTransactionsSchedule != "" {
+		jsonPayload, err := json.Marshal(&PayloadReconcileTransactions{
+			OlderThan:      configs.StuckTransactionAge,
+			AbandonedAfter: configs.AbandonedTransactionAge,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+		}
+
+		// Unique keeps a slow run from overlapping the next one.
+		task := asynq.NewTask(TaskReconcileTransactions, jsonPayload,
+			asynq.Queue(QueueDefault), asynq.MaxRetry(0), asynq.Unique(time.Hour))
+		if _, err := scheduler.Register(configs.ReconcileTransactionsSchedule, task); err != nil {
+			return nil, fmt.Errorf("failed to register %s: %w", TaskReconcileTransactions, err)
+		}
+	}
+
+	if configs.ReconcileFlashSalesSchedule != "" {
+		jsonPayload, err := json.Marshal(&PayloadReconcileFlashSales{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+		}
+
+		task := asynq.NewTask(TaskReconcileFlashSales, jsonPayload,
+			asynq.Queue(QueueDefault), asynq.MaxRetry(0), asynq.Unique(time.Minute))
+		if _, err := scheduler.Register(configs.ReconcileFlashSalesSchedule, task); err != nil {
+			return nil, fmt.Errorf("failed to register %s: %w", TaskReconcileFlashSales, err)
+		}
+	}
+
+	if configs.StockAlertsSchedule != "" {
+		jsonPayload, err := json.Marshal(&PayloadSendStockAlerts{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+		}
+
+		task := asynq.NewTask(TaskSendStockAlerts, jsonPayload,
+			asynq.Queue(QueueDefault), asynq.MaxRetry(0), asynq.Unique(time.Minute))
+		if _, err := scheduler.Register(configs.StockAlertsSchedule, task); err != nil {
+			return nil, fmt.Errorf("failed to register %s: %w", TaskSendStockAlerts, err)
+		}
+	}
+
+	if configs.StockDigestSchedule != "" {
+		jsonPayload, err := json.Marshal(&PayloadSendStockDigest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal task payload: %w", err)
+		}
+
+		// Unique keeps a digest from going out twice.
+		task := asynq.NewTask(TaskSendStockDigest, jsonPayload,
+			asynq.Queue(QueueDefault), asynq.MaxRetry(0), asynq.Unique(time.Hour))
+		if _, err := scheduler.Register(configs.StockDigestSchedule, task); err != nil {
+			return nil, fmt.Errorf("failed to register %s: %w", TaskSendStockDigest, err)
+		}
+	}
+
+	return &RedisTaskScheduler{scheduler: scheduler}, nil
+}
+
+// Start starts the RedisTaskScheduler.
+func (scheduler *RedisTaskScheduler) Start() error {
+	return scheduler.scheduler.Start()
+}
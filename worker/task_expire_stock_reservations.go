@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskExpireStockReservations represents the name of the task that releases a checkout's stock once its reservations lapse.
+	TaskExpireStockReservations = "task:expire_stock_reservations"
+)
+
+// PayloadExpireStockReservations holds the reference of the checkout whose reservations lapse.
+type PayloadExpireStockReservations struct {
+	Reference string `json:"reference"`
+}
+
+// DistributeTaskExpireStockReservations enqueues the given task to be processed by a worker.
+// It's meant to be enqueued with asynq.ProcessAt, for when the reservations lapse.
+func (distributor *RedisTaskDistributor) DistributeTaskExpireStockReservations(
+	ctx context.Context,
+	payload *PayloadExpireStockReservations,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskExpireStockReservations, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskExpireStockReservations processes a TaskExpireStockReservations task.
+// The checkout's reservations still RESERVED are marked EXPIRED; those its payment
+// already converted or released are left alone. Lapsed reservations stop holding
+// stock whether or not this runs, so it only settles their status.
+func (processor *RedisTaskProcessor) ProcessTaskExpireStockReservations(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExpireStockReservations
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	expired, err := processor.dbStore.ExpireStockReservations(ctx, payload.Reference)
+	if err != nil {
+		return fmt.Errorf("failed to expire stock reservations: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Str("reference", payload.Reference).
+		Int64("expired", expired).
+		Msg("processed task")
+
+	return nil
+}
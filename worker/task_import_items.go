@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskImportItems represents the name of the task that imports a CSV of items into a store.
+	TaskImportItems = "task:import_items"
+)
+
+// PayloadImportItems holds the ID of the import whose rows are imported.
+type PayloadImportItems struct {
+	ImportID int64 `json:"import_id"`
+}
+
+// DistributeTaskImportItems enqueues the given task to be processed by a worker.
+// It returns an error if the task could not be enqueued.
+func (distributor *RedisTaskDistributor) DistributeTaskImportItems(
+	ctx context.Context,
+	payload *PayloadImportItems,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskImportItems, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskImportItems processes a TaskImportItems task.
+// Each row of the import's CSV creates or updates the store's item with its
+// SKU, and the rows that can't be imported are recorded for the seller's
+// error report. A retry imports the file again, which only redoes the same
+// changes; an import that's already COMPLETED or FAILED is left alone.
+func (processor *RedisTaskProcessor) ProcessTaskImportItems(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadImportItems
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	itemImport, err := processor.dbStore.ImportItemsTx(ctx, payload.ImportID)
+	if err != nil {
+		return fmt.Errorf("failed to import items: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int64("import_id", itemImport.ID).
+		Str("status", itemImport.Status).
+		Int32("created_rows", itemImport.CreatedRows).
+		Int32("updated_rows", itemImport.UpdatedRows).
+		Int32("failed_rows", itemImport.FailedRows).
+		Msg("processed task")
+
+	return nil
+}
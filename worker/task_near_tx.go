@@ -2,9 +2,13 @@ package worker
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
 	"github.com/OCD-Labs/store-hub/near"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
@@ -15,9 +19,35 @@ const (
 	TaskNEARTx = "task:near_tx"
 )
 
-// PayloadNEARTx provides the NEAR transaction args.
+// NEAR operations a TaskNEARTx can run.
+const (
+	NEAROpTransfer         = "transfer"
+	NEAROpFunctionCall     = "function_call"
+	NEAROpCreateSubAccount = "create_sub_account"
+)
+
+// PayloadNEARTx describes a NEAR transaction, signed by the master account.
+// Op picks the operation, and the fields it reads.
 type PayloadNEARTx struct {
-	Args []string `json:"args"`
+	Op string `json:"op"`
+
+	// NEAROpTransfer: send Amount NEAR to ReceiverID.
+	ReceiverID string `json:"receiver_id,omitempty"`
+	Amount     string `json:"amount,omitempty"`
+
+	// NEAROpFunctionCall: call MethodName on ContractID, attaching Gas and Deposit NEAR.
+	ContractID string          `json:"contract_id,omitempty"`
+	MethodName string          `json:"method_name,omitempty"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	Gas        uint64          `json:"gas,omitempty"`
+	Deposit    string          `json:"deposit,omitempty"`
+
+	// NEAROpCreateSubAccount: create AccountID, funded with InitialBalance NEAR,
+	// with full access for PublicKey, the "ed25519:<base58>" key of whoever the
+	// new account belongs to.
+	AccountID      string `json:"account_id,omitempty"`
+	InitialBalance string `json:"initial_balance,omitempty"`
+	PublicKey      string `json:"public_key,omitempty"`
 }
 
 // DistributeTaskNEARTx enqueues the given near task to be processed by a worker.
@@ -46,6 +76,10 @@ func (distributor *RedisTaskDistributor) DistributeTaskNEARTx(
 }
 
 // ProcessTaskNEARTx processes a 'TaskNEARTx' task.
+// The task's transaction is signed once and saved under the task's id before
+// it's sent, so a retry looks it up on chain first, and sends the same
+// transaction again if the chain hasn't seen it; it can only execute once. A
+// transaction that executes but fails isn't retried.
 func (processor *RedisTaskProcessor) ProcessTaskNEARTx(
 	ctx context.Context,
 	task *asynq.Task,
@@ -55,13 +89,116 @@ func (processor *RedisTaskProcessor) ProcessTaskNEARTx(
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
 	}
 
-	if err := near.RunNearCLICommand(payload.Args...); err != nil {
+	taskID, ok := asynq.GetTaskID(ctx)
+	if !ok {
+		return fmt.Errorf("task has no id: %w", asynq.SkipRetry)
+	}
+
+	receiverID, actions, err := processor.nearTxActions(payload)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	var saved db.NearTx
+	saved, err = processor.dbStore.GetNEARTx(ctx, taskID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get NEAR transaction: %w", err)
+	}
+
+	save := func(signedTx []byte, txHash string) error {
+		var err error
+		if signedTx == nil {
+			err = processor.dbStore.DeleteNEARTx(ctx, taskID)
+		} else {
+			_, err = processor.dbStore.SaveNEARTx(ctx, db.SaveNEARTxParams{
+				TaskID:   taskID,
+				SignedTx: signedTx,
+				TxHash:   txHash,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save NEAR transaction: %w", err)
+		}
+		return nil
+	}
+
+	result, err := processor.sendNEARTxOnce(ctx, saved.SignedTx, saved.TxHash, save, receiverID, actions...)
+	if err != nil {
 		return err
 	}
 
+	if result.IsFailure() {
+		return fmt.Errorf("NEAR transaction %s failed: %v: %w", result.Transaction.Hash, result.Status["Failure"], asynq.SkipRetry)
+	}
+
+	if !result.IsSuccess() {
+		return fmt.Errorf("NEAR transaction %s hasn't finished executing", result.Transaction.Hash)
+	}
+
 	log.Info().Str("type", task.Type()).
 		Bytes("payload", task.Payload()).
+		Str("tx_hash", result.Transaction.Hash).
 		Msg("processed task")
 
 	return nil
 }
+
+// nearTxActions returns the receiver and the actions of the transaction a
+// TaskNEARTx sends.
+func (processor *RedisTaskProcessor) nearTxActions(payload PayloadNEARTx) (string, []near.Action, error) {
+	switch payload.Op {
+	case NEAROpTransfer:
+		amount, err := near.ParseNEAR(payload.Amount)
+		if err != nil {
+			return "", nil, err
+		}
+		return payload.ReceiverID, []near.Action{near.TransferAction{Deposit: amount}}, nil
+	case NEAROpFunctionCall:
+		deposit, err := near.ParseNEAR(defaultNEARAmount(payload.Deposit))
+		if err != nil {
+			return "", nil, err
+		}
+
+		gas := payload.Gas
+		if gas == 0 {
+			gas = near.DefaultFunctionCallGas
+		}
+
+		return payload.ContractID, []near.Action{near.FunctionCallAction{
+			MethodName: payload.MethodName,
+			Args:       payload.Args,
+			Gas:        gas,
+			Deposit:    deposit,
+		}}, nil
+	case NEAROpCreateSubAccount:
+		if !strings.HasSuffix(payload.AccountID, "."+processor.nearAccount.AccountID()) {
+			return "", nil, fmt.Errorf("%s is not a sub-account of %s", payload.AccountID, processor.nearAccount.AccountID())
+		}
+
+		initialBalance, err := near.ParseNEAR(payload.InitialBalance)
+		if err != nil {
+			return "", nil, err
+		}
+
+		publicKey, err := near.ParsePublicKey(payload.PublicKey)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return payload.AccountID, []near.Action{
+			near.CreateAccountAction{},
+			near.TransferAction{Deposit: initialBalance},
+			near.AddFullAccessKeyAction{PublicKey: publicKey},
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown NEAR operation %q", payload.Op)
+	}
+}
+
+// defaultNEARAmount treats an empty amount as zero NEAR.
+func defaultNEARAmount(amount string) string {
+	if amount == "" {
+		return "0"
+	}
+	return amount
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OCD-Labs/store-hub/cache"
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskReconcileFlashSales represents the name of the task that reconciles flash sale counters with Postgres.
+	TaskReconcileFlashSales = "task:reconcile_flash_sales"
+
+	// flashSaleBatchSize caps the claims and flash sales reconciled per run.
+	flashSaleBatchSize = 500
+)
+
+// PayloadReconcileFlashSales is the payload of a TaskReconcileFlashSales task, which needs nothing.
+type PayloadReconcileFlashSales struct{}
+
+// ProcessTaskReconcileFlashSales processes a TaskReconcileFlashSales task.
+// The units of claims released by failed or abandoned checkouts are given back
+// to their flash sales in Redis, then the units claimed of each flash sale that
+// has started are copied from Redis to Postgres, until once after it ends.
+func (processor *RedisTaskProcessor) ProcessTaskReconcileFlashSales(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadReconcileFlashSales
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	claims, err := processor.dbStore.ListUnreturnedFlashSaleClaims(ctx, flashSaleBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list released flash sale claims: %w", err)
+	}
+
+	returned := 0
+	for _, claim := range claims {
+		// Releasing a claim twice does nothing, so a failure after this is safe to retry.
+		err := processor.cache.ReleaseFlashSaleClaim(ctx, cache.FlashSaleClaim{
+			FlashSaleID: claim.FlashSaleID,
+			UserID:      claim.UserID,
+			Reference:   claim.Reference,
+			Units:       int64(claim.Quantity),
+			EndsAt:      claim.EndsAt,
+		})
+		if err == nil {
+			err = processor.dbStore.SetFlashSaleClaimReturned(ctx, claim.ID)
+		}
+		if err != nil {
+			log.Error().Err(err).Int64("claim_id", claim.ID).Msg("failed to return flash sale claim")
+			continue
+		}
+		returned++
+	}
+
+	flashSales, err := processor.dbStore.ListFlashSalesToReconcile(ctx, flashSaleBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list flash sales: %w", err)
+	}
+
+	ids := make([]int64, len(flashSales))
+	for i, flashSale := range flashSales {
+		ids[i] = flashSale.ID
+	}
+
+	claimed, err := processor.cache.FlashSalesClaimed(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to read flash sale counters: %w", err)
+	}
+
+	reconciled := 0
+	for _, flashSale := range flashSales {
+		err := processor.dbStore.SetFlashSaleClaimedQuantity(ctx, db.SetFlashSaleClaimedQuantityParams{
+			FlashSaleID:     flashSale.ID,
+			ClaimedQuantity: int32(claimed[flashSale.ID]),
+		})
+		if err != nil {
+			log.Error().Err(err).Int64("flash_sale_id", flashSale.ID).Msg("failed to reconcile flash sale")
+			continue
+		}
+		reconciled++
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int("returned", returned).
+		Int("reconciled", reconciled).
+		Msg("reconciled flash sales")
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/payment"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskReconcileTransactions represents the name of the task that settles stuck transactions.
+	TaskReconcileTransactions = "task:reconcile_transactions"
+
+	// reconcileBatchSize caps the transactions settled per run.
+	reconcileBatchSize = 100
+)
+
+// PayloadReconcileTransactions holds the age past which a PROCESSING transaction
+// is stuck, and the longer one past which a stuck transaction the buyer hasn't
+// paid is abandoned.
+type PayloadReconcileTransactions struct {
+	OlderThan      time.Duration `json:"older_than"`
+	AbandonedAfter time.Duration `json:"abandoned_after"`
+}
+
+// reconcileReport counts how a reconciliation run settled the transactions it found.
+type reconcileReport struct {
+	checked    int
+	completed  int
+	failed     int
+	mismatched int
+	unsettled  int
+}
+
+// ProcessTaskReconcileTransactions processes a TaskReconcileTransactions task.
+// Each PROCESSING transaction older than the payload's age is looked up with its
+// provider, then completed or failed the way the provider's webhook would have.
+// Transactions whose provider disagrees with what StoreHub recorded are reported
+// as mismatched, and failed. One the buyer hasn't paid yet is only failed once
+// it's older than the payload's abandoned age, as they may still be paying.
+func (processor *RedisTaskProcessor) ProcessTaskReconcileTransactions(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadReconcileTransactions
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	transactions, err := processor.dbStore.ListStuckTransactions(ctx, db.ListStuckTransactionsParams{
+		CreatedBefore: time.Now().Add(-payload.OlderThan),
+		RwLimit:       reconcileBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list stuck transactions: %w", err)
+	}
+
+	abandonedBefore := time.Now().Add(-payload.AbandonedAfter)
+
+	var report reconcileReport
+	for _, transaction := range transactions {
+		report.checked++
+
+		outcome, err := processor.reconcileTransaction(ctx, transaction, abandonedBefore)
+		if err != nil {
+			report.unsettled++
+			log.Error().Err(err).
+				Str("reference", transaction.ProviderTxRefID).
+				Msg("failed to reconcile transaction")
+			continue
+		}
+
+		switch outcome {
+		case reconcileCompleted:
+			report.completed++
+		case reconcileFailed:
+			report.failed++
+		case reconcileMismatched:
+			report.mismatched++
+		default:
+			report.unsettled++
+		}
+	}
+
+	event := log.Info()
+	if report.mismatched > 0 {
+		event = log.Warn()
+	}
+	event.Str("type", task.Type()).
+		Int("checked", report.checked).
+		Int("completed", report.completed).
+		Int("failed", report.failed).
+		Int("mismatched", report.mismatched).
+		Int("unsettled", report.unsettled).
+		Msg("reconciled stuck transactions")
+
+	return nil
+}
+
+type reconcileOutcome int
+
+const (
+	reconcileUnsettled reconcileOutcome = iota
+	reconcileCompleted
+	reconcileFailed
+	reconcileMismatched
+)
+
+// reconcileTransaction settles a single stuck transaction. An unpaid one
+// created before abandonedBefore is failed.
+func (processor *RedisTaskProcessor) reconcileTransaction(ctx context.Context, transaction db.Transaction, abandonedBefore time.Time) (reconcileOutcome, error) {
+	switch transaction.PaymentProvider {
+	case payment.ProviderPaystack:
+		return processor.reconcilePaystackTransaction(ctx, transaction, abandonedBefore)
+	case payment.ProviderNEARWallet:
+		// A NEAR payment is only known once the buyer submits its hash; one that
+		// got a hash but is still PROCESSING failed verification and is retried
+		// by the buyer. One without a hash may still be paid, and if it's paid
+		// after it's abandoned, verifying it refunds the buyer.
+		if transaction.ProviderTxHash.Valid || !transaction.CreatedAt.Before(abandonedBefore) {
+			return reconcileUnsettled, nil
+		}
+		return reconcileFailed, processor.failTransaction(ctx, transaction.ProviderTxRefID, money.Zero)
+	default:
+		return reconcileUnsettled, fmt.Errorf("unknown payment provider %q", transaction.PaymentProvider)
+	}
+}
+
+// reconcilePaystackTransaction settles a transaction from Paystack's view of it.
+func (processor *RedisTaskProcessor) reconcilePaystackTransaction(ctx context.Context, transaction db.Transaction, abandonedBefore time.Time) (reconcileOutcome, error) {
+	details, err := processor.paymentProvider.VerifyTransaction(ctx, transaction.ProviderTxRefID)
+	if err != nil {
+		if errors.Is(err, payment.ErrTransactionNotFound) {
+			// Paystack never saw the reference, so the buyer never paid.
+			// Any other error, such as a rejected key or an outage, is
+			// retried by the next run.
+			return reconcileFailed, processor.failTransaction(ctx, transaction.ProviderTxRefID, money.Zero)
+		}
+		return reconcileUnsettled, err
+	}
+
+	fee := money.FromMinor(details.Fees)
+
+	switch details.Status {
+	case payment.StatusSuccess:
+	case payment.StatusFailed:
+		return reconcileFailed, processor.failTransaction(ctx, transaction.ProviderTxRefID, fee)
+	case payment.StatusAbandoned:
+		// Paystack reports the payment abandoned until it's made, so the buyer may still be on its page.
+		if !transaction.CreatedAt.Before(abandonedBefore) {
+			return reconcileUnsettled, nil
+		}
+		return reconcileFailed, processor.failTransaction(ctx, transaction.ProviderTxRefID, fee)
+	default:
+		// still in progress on Paystack's side
+		return reconcileUnsettled, nil
+	}
+
+	amount, err := money.Parse(transaction.Amount)
+	if err != nil {
+		return reconcileUnsettled, err
+	}
+
+	if details.Amount != amount.Minor() {
+		log.Warn().
+			Str("reference", transaction.ProviderTxRefID).
+			Int64("paid", details.Amount).
+			Int64("expected", amount.Minor()).
+			Msg("reconcile: paid amount does not match transaction amount")
+
+		// The checkout can't complete for a different amount, so whatever was paid is refunded.
+		return reconcileMismatched, RefundFailedCheckout(ctx, processor.dbStore, processor.distributor, db.FailPaidCheckoutTxParams{
+			ProviderTxRefID: transaction.ProviderTxRefID,
+			ProviderTxFee:   fee.String(),
+			Reason:          fmt.Sprintf("paid %s, expected %s", money.FromMinor(details.Amount), amount),
+			Amount:          money.FromMinor(details.Amount).String(),
+		})
+	}
+
+	isGiftCard, err := processor.dbStore.IsGiftCardPurchase(ctx, transaction.ProviderTxRefID)
+	if err != nil {
+		return reconcileUnsettled, err
+	}
+
+	if isGiftCard {
+		_, err = processor.dbStore.CompleteGiftCardPurchaseTx(ctx, db.CompleteGiftCardPurchaseTxParams{
+			ProviderTxRefID: transaction.ProviderTxRefID,
+			ProviderTxFee:   fee.String(),
+		})
+		if err != nil {
+			if !errors.Is(err, db.ErrTransactionFailed) {
+				return reconcileUnsettled, err
+			}
+
+			// Paid after the purchase was failed, so the buyer is refunded.
+			return reconcileMismatched, RefundFailedCheckout(ctx, processor.dbStore, processor.distributor, db.FailPaidCheckoutTxParams{
+				ProviderTxRefID: transaction.ProviderTxRefID,
+				ProviderTxFee:   fee.String(),
+				Reason:          err.Error(),
+			})
+		}
+		return reconcileCompleted, nil
+	}
+
+	_, err = processor.dbStore.CheckoutCartTx(ctx, db.CheckoutCartTxParams{
+		UserID:          transaction.CustomerID,
+		ProviderTxRefID: transaction.ProviderTxRefID,
+		ProviderTxFee:   fee.String(),
+	})
+	if err != nil {
+		if !db.IsUnfulfillableCart(err) {
+			return reconcileUnsettled, err
+		}
+
+		// Paid, but the cart can no longer be fulfilled; the buyer is refunded.
+		log.Warn().Err(err).
+			Str("reference", transaction.ProviderTxRefID).
+			Msg("reconcile: paid transaction's cart can no longer be fulfilled")
+		return reconcileMismatched, RefundFailedCheckout(ctx, processor.dbStore, processor.distributor, db.FailPaidCheckoutTxParams{
+			ProviderTxRefID: transaction.ProviderTxRefID,
+			ProviderTxFee:   fee.String(),
+			Reason:          err.Error(),
+		})
+	}
+
+	// The webhook that would have had the invoices emailed never came.
+	if _, err := processor.sendInvoices(ctx, transaction.ProviderTxRefID); err != nil {
+		log.Error().Err(err).
+			Str("reference", transaction.ProviderTxRefID).
+			Msg("reconcile: failed to send invoices")
+	}
+
+	return reconcileCompleted, nil
+}
+
+// failTransaction marks a transaction FAILED without creating any order,
+// releases the coupons, stock, gift cards and store credit reserved for it and
+// fails its order group.
+func (processor *RedisTaskProcessor) failTransaction(ctx context.Context, reference string, fee money.Amount) error {
+	_, err := processor.dbStore.FailTransactionTx(ctx, db.FailTransactionTxParams{
+		ProviderTxRefID: reference,
+		ProviderTxFee:   fee.String(),
+	})
+	return err
+}
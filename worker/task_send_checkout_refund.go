@@ -0,0 +1,180 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/payment"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskSendCheckoutRefund represents the name of the task that asks the payment provider to refund a failed checkout.
+	TaskSendCheckoutRefund = "task:send_checkout_refund"
+)
+
+// PayloadSendCheckoutRefund holds the id of the checkout refund to send.
+type PayloadSendCheckoutRefund struct {
+	CheckoutRefundID int64 `json:"checkout_refund_id"`
+}
+
+// DistributeTaskSendCheckoutRefund enqueues the given task to be processed by a worker.
+func (distributor *RedisTaskDistributor) DistributeTaskSendCheckoutRefund(
+	ctx context.Context,
+	payload *PayloadSendCheckoutRefund,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendCheckoutRefund, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+
+	return nil
+}
+
+// RefundFailedCheckout fails a checkout that was paid but can't be completed,
+// as db.FailPaidCheckoutTx does, and queues the refund it records to be sent:
+// a NEAR wallet one as a NEAR transfer, at the rate the buyer paid at, and any
+// other through the provider that took the payment. A checkout already
+// refunded isn't refunded again. A refund that can't be queued was never sent,
+// so it's left FAILED for finance to settle.
+func RefundFailedCheckout(ctx context.Context, dbStore db.StoreTx, distributor TaskDistributor, arg db.FailPaidCheckoutTxParams) error {
+	result, err := dbStore.FailPaidCheckoutTx(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("failed to fail paid checkout: %w", err)
+	}
+
+	if !result.Created {
+		return nil
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Timeout(time.Minute),
+		asynq.Queue(QueueCritical),
+	}
+
+	if result.Transaction.PaymentProvider == payment.ProviderNEARWallet {
+		err = distributor.DistributeTaskSendNEARTransfer(ctx, &PayloadSendNEARTransfer{
+			TransferID: result.NEARTransfer.ID,
+		}, opts...)
+		if err != nil {
+			// nothing was signed, so the transfer and its refund can't have been sent
+			if _, fErr := dbStore.FailNEARTransferTx(ctx, db.FailNEARTransferTxParams{
+				TransferID:    result.NEARTransfer.ID,
+				FailureReason: err.Error(),
+			}); fErr != nil {
+				log.Error().Err(fErr).Int64("checkout_refund_id", result.Refund.ID).Msg("failed to mark checkout refund FAILED")
+			}
+			return fmt.Errorf("failed to send checkout refund: %w", err)
+		}
+		return nil
+	}
+
+	err = distributor.DistributeTaskSendCheckoutRefund(ctx, &PayloadSendCheckoutRefund{
+		CheckoutRefundID: result.Refund.ID,
+	}, opts...)
+	if err != nil {
+		if _, fErr := dbStore.FailCheckoutRefund(ctx, db.FailCheckoutRefundParams{
+			CheckoutRefundID: result.Refund.ID,
+			FailureReason:    err.Error(),
+		}); fErr != nil {
+			log.Error().Err(fErr).Int64("checkout_refund_id", result.Refund.ID).Msg("failed to mark checkout refund FAILED")
+		}
+		return fmt.Errorf("failed to send checkout refund: %w", err)
+	}
+	return nil
+}
+
+// ProcessTaskSendCheckoutRefund processes a TaskSendCheckoutRefund task.
+// The refund completes once the provider accepts it, and fails only if the
+// provider rejects it. Any other error, such as a timeout, leaves it unknown
+// whether the provider got it, so the refund stays PENDING and is retried; an
+// earlier attempt the provider did get is found rather than sent again. The
+// last attempt leaves it PENDING for finance to settle.
+func (processor *RedisTaskProcessor) ProcessTaskSendCheckoutRefund(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendCheckoutRefund
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	refund, err := processor.dbStore.GetCheckoutRefund(ctx, payload.CheckoutRefundID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("checkout refund %d not found: %w", payload.CheckoutRefundID, asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get checkout refund: %w", err)
+	}
+
+	if refund.Status != db.CheckoutRefundPending {
+		return nil
+	}
+
+	if refund.PaymentProvider == payment.ProviderNEARWallet {
+		return fmt.Errorf("checkout refund %d is sent as a NEAR transfer: %w", refund.ID, asynq.SkipRetry)
+	}
+
+	providerRefund, err := processor.sendCheckoutRefund(ctx, refund)
+	if err != nil {
+		if errors.Is(err, payment.ErrProvider) || errors.Is(err, asynq.SkipRetry) {
+			// rejected, or never sent, so the buyer wasn't refunded
+			if _, fErr := processor.dbStore.FailCheckoutRefund(ctx, db.FailCheckoutRefundParams{
+				CheckoutRefundID: refund.ID,
+				FailureReason:    err.Error(),
+			}); fErr != nil {
+				return fmt.Errorf("failed to fail checkout refund: %w", fErr)
+			}
+			return fmt.Errorf("failed to send checkout refund: %v: %w", err, asynq.SkipRetry)
+		}
+
+		if isLastAttempt(ctx) {
+			log.Error().Err(err).
+				Int64("checkout_refund_id", refund.ID).
+				Str("reference", refund.ProviderTxRefID).
+				Msg("checkout refund left PENDING with an unconfirmed provider refund")
+		}
+		return fmt.Errorf("failed to send checkout refund: %w", err)
+	}
+
+	_, err = processor.dbStore.CompleteCheckoutRefund(ctx, db.CompleteCheckoutRefundParams{
+		CheckoutRefundID: refund.ID,
+		ProviderRefundID: strconv.FormatInt(providerRefund.ID, 10),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete checkout refund: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int64("checkout_refund_id", refund.ID).
+		Int64("provider_refund_id", providerRefund.ID).
+		Msg("processed task")
+
+	return nil
+}
+
+// sendCheckoutRefund asks the provider to refund a checkout, unless an earlier
+// attempt already has, as sendProviderRefund does.
+func (processor *RedisTaskProcessor) sendCheckoutRefund(ctx context.Context, refund db.GetCheckoutRefundRow) (payment.RefundResult, error) {
+	note := fmt.Sprintf("checkout refund %d: %s", refund.ID, refund.Reason)
+	return sendProviderRefund(ctx, processor.paymentProvider, refund.ProviderTxRefID, refund.Amount, note)
+}
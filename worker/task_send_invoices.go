@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/invoice"
+	"github.com/OCD-Labs/store-hub/template/email_tmpl"
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskSendInvoices represents the name of the task that emails a completed transaction's invoices.
+	TaskSendInvoices = "task:send_invoices"
+)
+
+// PayloadSendInvoices holds the reference of the transaction whose invoices are emailed.
+type PayloadSendInvoices struct {
+	Reference string `json:"reference"`
+}
+
+// DistributeTaskSendInvoices enqueues the given task to be processed by a worker.
+// It returns an error if the task could not be enqueued.
+func (distributor *RedisTaskDistributor) DistributeTaskSendInvoices(
+	ctx context.Context,
+	payload *PayloadSendInvoices,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendInvoices, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskSendInvoices processes a TaskSendInvoices task.
+// Each invoice of the transaction not yet emailed is rendered and sent to the
+// buyer, with the store's full access and financial access staff in bcc. A retry
+// only sends the invoices that failed.
+func (processor *RedisTaskProcessor) ProcessTaskSendInvoices(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendInvoices
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	sent, err := processor.sendInvoices(ctx, payload.Reference)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("type", task.Type()).
+		Str("reference", payload.Reference).
+		Int("sent", sent).
+		Msg("processed task")
+
+	return nil
+}
+
+// sendInvoices emails the invoices of the transaction under reference that
+// haven't been, and returns how many it sent.
+func (processor *RedisTaskProcessor) sendInvoices(ctx context.Context, reference string) (int, error) {
+	invoices, err := processor.dbStore.ListUnsentInvoices(ctx, reference)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	for i, inv := range invoices {
+		if err := processor.sendInvoice(ctx, inv); err != nil {
+			return i, fmt.Errorf("failed to send invoice %s: %w", inv.InvoiceNumber, err)
+		}
+	}
+
+	return len(invoices), nil
+}
+
+// sendInvoice renders an invoice and emails it, marking it emailed.
+func (processor *RedisTaskProcessor) sendInvoice(ctx context.Context, inv db.Invoice) error {
+	doc, err := processor.dbStore.InvoiceDocument(ctx, inv)
+	if err != nil {
+		return err
+	}
+
+	pdf, err := invoice.Render(doc)
+	if err != nil {
+		return err
+	}
+
+	// The attachment is named after the file it's read from.
+	dir, err := os.MkdirTemp("", "invoice")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, doc.Filename())
+	if err := os.WriteFile(file, pdf, 0600); err != nil {
+		return err
+	}
+
+	staff, err := processor.dbStore.ListStoreStaffEmails(ctx, db.ListStoreStaffEmailsParams{
+		StoreID:      inv.StoreID,
+		AccessLevels: []int32{util.FULLACCESS, util.FINANCIALACCESS},
+	})
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Your %s invoice %s", doc.StoreName, doc.Number)
+	content := email_tmpl.InvoiceTmpl(
+		doc.BuyerName,
+		doc.StoreName,
+		doc.Number,
+		doc.OrderNumber,
+		fmt.Sprintf("%s %s", inv.Currency, inv.Total),
+	)
+	to := []string{doc.BuyerEmail}
+	if err := processor.mailer.SendEmail(subject, content, to, nil, staff, []string{file}); err != nil {
+		return err
+	}
+
+	return processor.dbStore.SetInvoiceEmailed(ctx, inv.ID)
+}
@@ -0,0 +1,227 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/near"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskSendNEARTransfer represents the name of the task that sends a NEAR transfer from the master account.
+	TaskSendNEARTransfer = "task:send_near_transfer"
+)
+
+// PayloadSendNEARTransfer holds the id of the NEAR transfer to send.
+type PayloadSendNEARTransfer struct {
+	TransferID int64 `json:"transfer_id"`
+}
+
+// DistributeTaskSendNEARTransfer enqueues the given task to be processed by a worker.
+func (distributor *RedisTaskDistributor) DistributeTaskSendNEARTransfer(
+	ctx context.Context,
+	payload *PayloadSendNEARTransfer,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendNEARTransfer, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskSendNEARTransfer processes a TaskSendNEARTransfer task.
+// The transfer's transaction is signed once and saved before it's sent, so a
+// retry looks it up on chain first, and sends the same transaction again if
+// the chain hasn't seen it; it can only execute once. Only a transaction the
+// chain rejected unexecuted is replaced. The transfer, and the refund it
+// sends, complete once its transaction succeeds, and fail if it fails, or if
+// the last attempt leaves no transaction that could still execute.
+func (processor *RedisTaskProcessor) ProcessTaskSendNEARTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendNEARTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	transfer, err := processor.dbStore.GetNEARTransfer(ctx, payload.TransferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("NEAR transfer %d not found: %w", payload.TransferID, asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get NEAR transfer: %w", err)
+	}
+
+	if transfer.Status != db.NEARTransferPending {
+		return nil
+	}
+
+	result, err := processor.sendNEARTransfer(ctx, transfer)
+	if err == nil && !result.IsSuccess() && !result.IsFailure() {
+		err = fmt.Errorf("NEAR transfer %s hasn't finished executing", result.Transaction.Hash)
+	}
+	if err != nil {
+		if errors.Is(err, asynq.SkipRetry) || isLastAttempt(ctx) {
+			processor.giveUpNEARTransfer(ctx, transfer.ID, err)
+		}
+		return err
+	}
+
+	if result.IsFailure() {
+		_, err := processor.dbStore.FailNEARTransferTx(ctx, db.FailNEARTransferTxParams{
+			TransferID:    transfer.ID,
+			FailureReason: fmt.Sprintf("transaction %s failed: %s", result.Transaction.Hash, result.Status["Failure"]),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fail NEAR transfer: %w", err)
+		}
+
+		log.Error().Str("type", task.Type()).
+			Int64("transfer_id", transfer.ID).
+			Str("tx_hash", result.Transaction.Hash).
+			Msg("NEAR transfer failed on chain")
+		return nil
+	}
+
+	if _, err := processor.dbStore.CompleteNEARTransferTx(ctx, transfer.ID); err != nil {
+		return fmt.Errorf("failed to complete NEAR transfer: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int64("transfer_id", transfer.ID).
+		Str("tx_hash", result.Transaction.Hash).
+		Msg("processed task")
+
+	return nil
+}
+
+// sendNEARTransfer returns the outcome of a transfer's transaction, as
+// sendNEARTxOnce does, saving it with the transfer.
+func (processor *RedisTaskProcessor) sendNEARTransfer(ctx context.Context, transfer db.NearTransfer) (near.TxStatusResult, error) {
+	amount, err := near.ParseYocto(transfer.Amount)
+	if err != nil {
+		return near.TxStatusResult{}, fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	save := func(signedTx []byte, txHash string) error {
+		_, err := processor.dbStore.SetNEARTransferSignedTx(ctx, db.SetNEARTransferSignedTxParams{
+			SignedTx:   signedTx,
+			TxHash:     txHash,
+			TransferID: transfer.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save NEAR transfer: %w", err)
+		}
+		return nil
+	}
+
+	return processor.sendNEARTxOnce(ctx, transfer.SignedTx, transfer.TxHash, save,
+		transfer.ReceiverID, near.TransferAction{Deposit: amount})
+}
+
+// sendNEARTxOnce returns the outcome of a transaction of actions to
+// receiverID, signed once and saved before it's sent. A transaction an earlier
+// attempt saved, signedTx with hash txHash, is looked up if the chain has seen
+// it, and sent again if it hasn't; it can only execute once. Otherwise a new
+// one is signed and saved with save first. One the chain rejected unexecuted
+// is cleared with a nil signedTx, so the next attempt signs another. An error
+// means the outcome isn't known yet.
+func (processor *RedisTaskProcessor) sendNEARTxOnce(ctx context.Context, signedTx []byte, txHash string, save func(signedTx []byte, txHash string) error, receiverID string, actions ...near.Action) (near.TxStatusResult, error) {
+	if signedTx != nil {
+		result, err := processor.nearAccount.TransactionStatus(ctx, txHash)
+		if !errors.Is(err, near.ErrUnknownTransaction) {
+			return result, err
+		}
+	} else {
+		var err error
+		signedTx, txHash, err = processor.nearAccount.SignTransaction(ctx, receiverID, actions...)
+		if err != nil {
+			return near.TxStatusResult{}, err
+		}
+
+		if err := save(signedTx, txHash); err != nil {
+			return near.TxStatusResult{}, err
+		}
+	}
+
+	result, err := processor.nearAccount.SendTransaction(ctx, signedTx)
+	if err == nil || result.IsFailure() {
+		return result, nil
+	}
+
+	if !errors.Is(err, near.ErrInvalidTransaction) {
+		return result, err
+	}
+
+	// Rejected without executing, such as for a nonce another transaction
+	// took, so it never will. Unless it executed since it was looked up, the
+	// next attempt signs another.
+	result, sErr := processor.nearAccount.TransactionStatus(ctx, txHash)
+	if sErr == nil {
+		return result, nil
+	}
+	if !errors.Is(sErr, near.ErrUnknownTransaction) {
+		return result, sErr
+	}
+
+	if cErr := save(nil, ""); cErr != nil {
+		return result, cErr
+	}
+	return result, err
+}
+
+// giveUpNEARTransfer fails a transfer whose last attempt failed with err,
+// unless a transaction it signed might still execute; that one is left
+// PENDING for finance to settle.
+func (processor *RedisTaskProcessor) giveUpNEARTransfer(ctx context.Context, transferID int64, err error) {
+	transfer, gErr := processor.dbStore.GetNEARTransfer(ctx, transferID)
+	if gErr != nil {
+		log.Error().Err(gErr).Int64("transfer_id", transferID).Msg("failed to get NEAR transfer")
+		return
+	}
+
+	if transfer.SignedTx != nil {
+		log.Error().Err(err).
+			Int64("transfer_id", transferID).
+			Str("tx_hash", transfer.TxHash).
+			Msg("NEAR transfer left PENDING with an unconfirmed transaction")
+		return
+	}
+
+	_, fErr := processor.dbStore.FailNEARTransferTx(ctx, db.FailNEARTransferTxParams{
+		TransferID:    transferID,
+		FailureReason: err.Error(),
+	})
+	if fErr != nil {
+		log.Error().Err(fErr).Int64("transfer_id", transferID).Msg("failed to fail NEAR transfer")
+	}
+}
+
+// isLastAttempt reports whether the task being processed won't be retried if it fails.
+func isLastAttempt(ctx context.Context) bool {
+	retried, ok := asynq.GetRetryCount(ctx)
+	if !ok {
+		return false
+	}
+	maxRetry, ok := asynq.GetMaxRetry(ctx)
+	return ok && retried >= maxRetry
+}
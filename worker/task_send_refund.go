@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/money"
+	"github.com/OCD-Labs/store-hub/payment"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskSendRefund represents the name of the task that asks the payment provider to send an order's refund.
+	TaskSendRefund = "task:send_refund"
+)
+
+// PayloadSendRefund holds the id of the order refund to send.
+type PayloadSendRefund struct {
+	RefundID int64 `json:"refund_id"`
+}
+
+// DistributeTaskSendRefund enqueues the given task to be processed by a worker.
+func (distributor *RedisTaskDistributor) DistributeTaskSendRefund(
+	ctx context.Context,
+	payload *PayloadSendRefund,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendRefund, jsonPayload, opts...)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+
+	return nil
+}
+
+// ProcessTaskSendRefund processes a TaskSendRefund task, for an order refund
+// whose first attempt left it unknown whether the provider got it. It's
+// confirmed with the provider, and sent again only if the provider doesn't
+// have it. The refund completes once the provider accepts it, and fails only if
+// the provider rejects it; the last attempt leaves it PENDING for finance to
+// settle.
+func (processor *RedisTaskProcessor) ProcessTaskSendRefund(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendRefund
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	refund, err := processor.dbStore.GetRefund(ctx, payload.RefundID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("refund %d not found: %w", payload.RefundID, asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	if refund.Status != "PENDING" {
+		return nil
+	}
+
+	var providerRefund payment.RefundResult
+	if refund.PaymentProvider == payment.ProviderPaystack {
+		providerRefund, err = SendRefund(ctx, processor.paymentProvider, refund.ID, refund.ProviderTxRefID, refund.Amount, refund.Reason)
+	} else {
+		err = fmt.Errorf("can't send refunds of %s payments: %w", refund.PaymentProvider, asynq.SkipRetry)
+	}
+	if err != nil {
+		if errors.Is(err, payment.ErrProvider) || errors.Is(err, asynq.SkipRetry) {
+			// rejected, or never sent, so the buyer wasn't refunded
+			if _, fErr := processor.dbStore.FailRefund(ctx, db.FailRefundParams{
+				RefundID:      refund.ID,
+				FailureReason: err.Error(),
+			}); fErr != nil {
+				return fmt.Errorf("failed to fail refund: %w", fErr)
+			}
+			return fmt.Errorf("failed to send refund: %v: %w", err, asynq.SkipRetry)
+		}
+
+		if isLastAttempt(ctx) {
+			log.Error().Err(err).
+				Int64("refund_id", refund.ID).
+				Str("reference", refund.ProviderTxRefID).
+				Msg("refund left PENDING with an unconfirmed provider refund")
+		}
+		return fmt.Errorf("failed to send refund: %w", err)
+	}
+
+	_, err = processor.dbStore.CompleteRefundTx(ctx, db.CompleteRefundTxParams{
+		RefundID:         refund.ID,
+		ProviderRefundID: strconv.FormatInt(providerRefund.ID, 10),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete refund: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int64("refund_id", refund.ID).
+		Int64("provider_refund_id", providerRefund.ID).
+		Msg("processed task")
+
+	return nil
+}
+
+// SendRefund asks provider to send the order refund with the given id, of
+// amount of the transaction with the given reference, unless an earlier
+// attempt already has, as sendProviderRefund does.
+func SendRefund(ctx context.Context, provider payment.Provider, refundID int64, reference, amount, reason string) (payment.RefundResult, error) {
+	note := fmt.Sprintf("refund %d: %s", refundID, reason)
+	return sendProviderRefund(ctx, provider, reference, amount, note)
+}
+
+// sendProviderRefund asks provider to refund amount of the transaction with
+// the given reference, unless an earlier attempt already has. Each refund is
+// sent with a note naming the refund it's for, so one the provider has with
+// that note, and hasn't failed, is returned instead. An error that isn't a
+// payment.ErrProvider rejection or an asynq.SkipRetry leaves it unknown
+// whether the provider got the refund.
+func sendProviderRefund(ctx context.Context, provider payment.Provider, reference, amount, note string) (payment.RefundResult, error) {
+	sent, err := provider.ListRefunds(ctx, reference)
+	if err != nil {
+		// not a rejection of the refund itself, so it's retried rather than failed
+		return payment.RefundResult{}, fmt.Errorf("failed to list refunds: %v", err)
+	}
+
+	for _, providerRefund := range sent {
+		if providerRefund.MerchantNote == note && !providerRefund.IsFailed() {
+			return providerRefund, nil
+		}
+	}
+
+	minor, err := money.Parse(amount)
+	if err != nil {
+		return payment.RefundResult{}, fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	return provider.Refund(ctx, payment.RefundParams{
+		Reference: reference,
+		Amount:    minor.Minor(),
+		Reason:    note,
+	})
+}
@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/template/email_tmpl"
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskSendStockAlerts represents the name of the task that emails stores' low-stock and out-of-stock alerts.
+	TaskSendStockAlerts = "task:send_stock_alerts"
+
+	// stockAlertBatchSize caps the alerts sent per run.
+	stockAlertBatchSize = 500
+)
+
+// PayloadSendStockAlerts is the payload of a TaskSendStockAlerts task, which needs nothing.
+type PayloadSendStockAlerts struct{}
+
+// ProcessTaskSendStockAlerts processes a TaskSendStockAlerts task.
+// The alerts recorded since the last run, as items fell to their threshold or
+// ran out, are emailed to the staff of their store with full access or
+// product inventory access, one email per store. A store whose email fails
+// keeps its alerts for the next run.
+func (processor *RedisTaskProcessor) ProcessTaskSendStockAlerts(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendStockAlerts
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	alerts, err := processor.dbStore.ListUnsentStockAlerts(ctx, stockAlertBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list stock alerts: %w", err)
+	}
+
+	sent := 0
+	// the alerts come ordered by store
+	for start := 0; start < len(alerts); {
+		end := start + 1
+		for end < len(alerts) && alerts[end].StoreID == alerts[start].StoreID {
+			end++
+		}
+
+		storeAlerts := alerts[start:end]
+		start = end
+
+		if err := processor.sendStockAlerts(ctx, storeAlerts); err != nil {
+			log.Error().Err(err).Int64("store_id", storeAlerts[0].StoreID).Msg("failed to send stock alerts")
+			continue
+		}
+		sent += len(storeAlerts)
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int("sent", sent).
+		Msg("sent stock alerts")
+
+	return nil
+}
+
+// sendStockAlerts emails the alerts of a store to its staff, and marks them sent.
+func (processor *RedisTaskProcessor) sendStockAlerts(ctx context.Context, alerts []db.ListUnsentStockAlertsRow) error {
+	store := alerts[0]
+
+	staff, err := processor.dbStore.ListStoreStaffEmails(ctx, db.ListStoreStaffEmailsParams{
+		StoreID:      store.StoreID,
+		AccessLevels: []int32{util.FULLACCESS, util.PRODUCTINVENTORYACCESS},
+	})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, len(alerts))
+	lines := make([]email_tmpl.StockLine, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+		lines[i] = email_tmpl.StockLine{
+			Name:           alert.ItemName,
+			SKU:            alert.ItemSku,
+			SupplyQuantity: alert.SupplyQuantity,
+			Threshold:      alert.Threshold,
+			Status:         stockStatus(alert.Kind),
+		}
+	}
+
+	// a store without staff to tell has nobody to email
+	if len(staff) > 0 {
+		subject := fmt.Sprintf("Stock alert: %d item(s) of %s running out", len(alerts), store.StoreName)
+		content := email_tmpl.StockAlertTmpl(store.StoreName, lines)
+		if err := processor.mailer.SendEmail(subject, content, staff, nil, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return processor.dbStore.SetStockAlertsSent(ctx, ids)
+}
+
+func stockStatus(kind string) string {
+	switch kind {
+	case db.StockAlertOutOfStock:
+		return "Out of stock"
+	case db.StockAlertLow:
+		return "Low stock"
+	default:
+		return kind
+	}
+}
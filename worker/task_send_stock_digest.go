@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/OCD-Labs/store-hub/db/sqlc"
+	"github.com/OCD-Labs/store-hub/template/email_tmpl"
+	"github.com/OCD-Labs/store-hub/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// TaskSendStockDigest represents the name of the task that emails stores the items at risk of running out.
+	TaskSendStockDigest = "task:send_stock_digest"
+)
+
+// PayloadSendStockDigest is the payload of a TaskSendStockDigest task, which needs nothing.
+type PayloadSendStockDigest struct{}
+
+// ProcessTaskSendStockDigest processes a TaskSendStockDigest task.
+// Every store with items at or below their threshold, or that sold more in
+// the last week than they have left, is emailed the list of them, to its
+// staff with full access or product inventory access. A store whose email
+// fails is skipped until the next digest.
+func (processor *RedisTaskProcessor) ProcessTaskSendStockDigest(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendStockDigest
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	items, err := processor.dbStore.ListItemsAtRisk(ctx, sql.NullInt64{})
+	if err != nil {
+		return fmt.Errorf("failed to list items at risk: %w", err)
+	}
+
+	sent := 0
+	// the items come ordered by store
+	for start := 0; start < len(items); {
+		end := start + 1
+		for end < len(items) && items[end].StoreID == items[start].StoreID {
+			end++
+		}
+
+		storeItems := items[start:end]
+		start = end
+
+		if err := processor.sendStockDigest(ctx, storeItems); err != nil {
+			log.Error().Err(err).Int64("store_id", storeItems[0].StoreID).Msg("failed to send stock digest")
+			continue
+		}
+		sent++
+	}
+
+	log.Info().Str("type", task.Type()).
+		Int("stores", sent).
+		Msg("sent stock digests")
+
+	return nil
+}
+
+// sendStockDigest emails the items at risk of a store to its staff.
+func (processor *RedisTaskProcessor) sendStockDigest(ctx context.Context, items []db.ListItemsAtRiskRow) error {
+	store := items[0]
+
+	staff, err := processor.dbStore.ListStoreStaffEmails(ctx, db.ListStoreStaffEmailsParams{
+		StoreID:      store.StoreID,
+		AccessLevels: []int32{util.FULLACCESS, util.PRODUCTINVENTORYACCESS},
+	})
+	if err != nil {
+		return err
+	}
+	if len(staff) == 0 {
+		return nil
+	}
+
+	lines := make([]email_tmpl.StockLine, len(items))
+	for i, item := range items {
+		lines[i] = email_tmpl.StockLine{
+			Name:           item.ItemName,
+			SKU:            item.ItemSku,
+			SupplyQuantity: item.SupplyQuantity,
+			Threshold:      item.Threshold,
+			Status:         riskStatus(item),
+		}
+	}
+
+	subject := fmt.Sprintf("%d item(s) of %s at risk of running out", len(items), store.StoreName)
+	content := email_tmpl.StockDigestTmpl(store.StoreName, lines)
+	return processor.mailer.SendEmail(subject, content, staff, nil, nil, nil)
+}
+
+func riskStatus(item db.ListItemsAtRiskRow) string {
+	switch {
+	case item.SupplyQuantity <= 0:
+		return "Out of stock"
+	case item.SupplyQuantity <= int64(item.Threshold):
+		return "Low stock"
+	default:
+		return fmt.Sprintf("%d sold last week", item.SoldLastWeek)
+	}
+}